@@ -0,0 +1,105 @@
+// Package applehealthxml reads records from an Apple Health export.xml.
+package applehealthxml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MoodRecordType is the <Record> type of the "Mood Changes" symptom.
+//
+// It is a presence symptom, not a mood score: its value is
+// HKCategoryValuePresencePresent or HKCategoryValuePresenceNotPresent, so it
+// says that the user's mood changed but not in which direction or by how much.
+const MoodRecordType = "HKCategoryTypeIdentifierMoodChanges"
+
+const (
+	presencePresent    = "HKCategoryValuePresencePresent"
+	presenceNotPresent = "HKCategoryValuePresenceNotPresent"
+)
+
+// dateLayout is the timestamp format of export.xml, e.g. "2024-05-01 08:30:00 +0900".
+const dateLayout = "2006-01-02 15:04:05 -0700"
+
+type record struct {
+	Type       string `xml:"type,attr"`
+	SourceName string `xml:"sourceName,attr"`
+	StartDate  string `xml:"startDate,attr"`
+	Value      string `xml:"value,attr"`
+}
+
+// MoodChange is a "Mood Changes: Present" symptom entry.
+type MoodChange struct {
+	StartDate  time.Time
+	SourceName string
+}
+
+// ConditionMapper reads mood change symptoms for condition logs.
+type ConditionMapper struct{}
+
+func NewConditionMapper() *ConditionMapper {
+	return &ConditionMapper{}
+}
+
+// Map streams export.xml from r and returns the mood change records marked
+// present, plus how many mood change records could not be read. Records
+// marked not present and other record types are ignored. The error is
+// non-nil only when the XML itself cannot be read.
+func (m *ConditionMapper) Map(r io.Reader) ([]MoodChange, int, error) {
+	dec := xml.NewDecoder(r)
+	var changes []MoodChange
+	var failed int
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return changes, failed, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("read export.xml: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "Record" || attr(start, "type") != MoodRecordType {
+			continue
+		}
+		var rec record
+		if err := dec.DecodeElement(&rec, &start); err != nil {
+			return nil, 0, fmt.Errorf("read export.xml: %w", err)
+		}
+		change, present, err := mapMoodRecord(rec)
+		if err != nil {
+			failed++
+			continue
+		}
+		if present {
+			changes = append(changes, change)
+		}
+	}
+}
+
+// mapMoodRecord reports whether rec marks mood changes as present.
+func mapMoodRecord(rec record) (MoodChange, bool, error) {
+	startDate, err := time.Parse(dateLayout, rec.StartDate)
+	if err != nil {
+		return MoodChange{}, false, fmt.Errorf("parse startDate: %w", err)
+	}
+	switch rec.Value {
+	case presencePresent:
+		return MoodChange{StartDate: startDate, SourceName: rec.SourceName}, true, nil
+	case presenceNotPresent:
+		return MoodChange{}, false, nil
+	default:
+		return MoodChange{}, false, fmt.Errorf("unknown presence value %q", rec.Value)
+	}
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
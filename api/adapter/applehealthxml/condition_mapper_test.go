@@ -0,0 +1,47 @@
+package applehealthxml
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditionMapper_Map(t *testing.T) {
+	f, err := os.Open("testdata/export.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	changes, failed, err := NewConditionMapper().Map(f)
+	if err != nil {
+		t.Fatalf("Map() error = %v", err)
+	}
+	// A severity value, which MoodChanges never carries, and an unparseable date.
+	if failed != 2 {
+		t.Errorf("failed = %d, want 2", failed)
+	}
+
+	// The NotPresent record on 2024-05-02 is not a mood change.
+	jst := time.FixedZone("", 9*3600)
+	want := []time.Time{
+		time.Date(2024, 5, 1, 8, 30, 0, 0, jst),
+		time.Date(2024, 5, 3, 12, 0, 0, 0, jst),
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("len(changes) = %d, want %d", len(changes), len(want))
+	}
+	for i, w := range want {
+		if !changes[i].StartDate.Equal(w) || changes[i].SourceName != "Health" {
+			t.Errorf("changes[%d] = %+v, want start %v from Health", i, changes[i], w)
+		}
+	}
+}
+
+func TestConditionMapper_Map_MalformedXML(t *testing.T) {
+	_, _, err := NewConditionMapper().Map(strings.NewReader(`<HealthData><Record type="x"`))
+	if err == nil {
+		t.Error("Map() error = nil, want error for truncated XML")
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
 
 	"vitametron/api/domain/entity"
+	"vitametron/api/infrastructure/config"
 )
 
 const baseURL = "https://api.fitbit.com"
@@ -19,11 +21,13 @@ type FitbitClient struct {
 	oauth      *FitbitOAuth
 	httpClient *http.Client
 	baseURL    string
+	profile    config.ProfileConfig
 }
 
-func NewFitbitClient(oauth *FitbitOAuth) *FitbitClient {
+func NewFitbitClient(oauth *FitbitOAuth, profile config.ProfileConfig) *FitbitClient {
 	return &FitbitClient{
-		oauth: oauth,
+		oauth:   oauth,
+		profile: profile,
 		httpClient: &http.Client{
 			Timeout: 20 * time.Second,
 			Transport: &http.Transport{
@@ -43,6 +47,12 @@ func (c *FitbitClient) ProviderName() string {
 }
 
 func (c *FitbitClient) doGet(ctx context.Context, path string, out any) error {
+	return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// do sends an authorized request and decodes a 2xx body into out. A nil out
+// or a 204 response skips decoding.
+func (c *FitbitClient) do(ctx context.Context, method, path string, header http.Header, out any) error {
 	if err := c.oauth.RefreshTokenIfNeeded(ctx); err != nil {
 		return fmt.Errorf("fitbit: refresh token: %w", err)
 	}
@@ -52,7 +62,7 @@ func (c *FitbitClient) doGet(ctx context.Context, path string, out any) error {
 		return fmt.Errorf("fitbit: get access token: %w", err)
 	}
 
-	resp, err := c.executeRequest(ctx, path, accessToken)
+	resp, err := c.executeRequest(ctx, method, path, header, accessToken)
 	if err != nil {
 		return err
 	}
@@ -68,7 +78,7 @@ func (c *FitbitClient) doGet(ctx context.Context, path string, out any) error {
 		if err != nil {
 			return fmt.Errorf("fitbit: get token after 401: %w", err)
 		}
-		resp, err = c.executeRequest(ctx, path, accessToken)
+		resp, err = c.executeRequest(ctx, method, path, header, accessToken)
 		if err != nil {
 			return err
 		}
@@ -98,14 +108,14 @@ func (c *FitbitClient) doGet(ctx context.Context, path string, out any) error {
 		case <-ctx.Done():
 			return ctx.Err()
 		}
-		resp, err = c.executeRequest(ctx, path, accessToken)
+		resp, err = c.executeRequest(ctx, method, path, header, accessToken)
 		if err != nil {
 			return err
 		}
 		defer resp.Body.Close()
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("fitbit: %s returned %d: %s", path, resp.StatusCode, string(body))
 	}
@@ -115,14 +125,20 @@ func (c *FitbitClient) doGet(ctx context.Context, path string, out any) error {
 		log.Printf("fitbit: rate limit remaining: %s", remaining)
 	}
 
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
 	return json.NewDecoder(resp.Body).Decode(out)
 }
 
-func (c *FitbitClient) executeRequest(ctx context.Context, path, accessToken string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+func (c *FitbitClient) executeRequest(ctx context.Context, method, path string, header http.Header, accessToken string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("fitbit: create request: %w", err)
 	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("User-Agent", "VitaMetron/0.1")
 
@@ -194,6 +210,44 @@ func (c *FitbitClient) FetchHRV(ctx context.Context, date time.Time) (float32, f
 	return hrvResp.HRV[0].HRV.DailyRMSSD, hrvResp.HRV[0].HRV.DeepRMSSD, nil
 }
 
+func (c *FitbitClient) FetchHRVIntraday(ctx context.Context, date time.Time) ([]entity.HRVSample, error) {
+	dateStr := date.Format("2006-01-02")
+
+	var hrvResp HRVIntradayResponse
+	if err := c.doGet(ctx, fmt.Sprintf("/1/user/-/hrv/date/%s/all.json", dateStr), &hrvResp); err != nil {
+		return nil, fmt.Errorf("fitbit: fetch hrv intraday: %w", err)
+	}
+
+	return mapHRVIntraday(&hrvResp), nil
+}
+
+func (c *FitbitClient) FetchActiveZoneMinutesIntraday(ctx context.Context, date time.Time) ([]entity.ActiveZoneSample, error) {
+	dateStr := date.Format("2006-01-02")
+
+	var azmResp AZMIntradayResponse
+	if err := c.doGet(ctx, fmt.Sprintf("/1/user/-/activities/active-zone-minutes/date/%s/1d/1min.json", dateStr), &azmResp); err != nil {
+		return nil, fmt.Errorf("fitbit: fetch active zone minutes intraday: %w", err)
+	}
+
+	return mapAZMIntraday(&azmResp), nil
+}
+
+func (c *FitbitClient) FetchLifetimeStats(ctx context.Context) (*entity.FitbitLifetimeStats, error) {
+	var resp LifetimeResponse
+	if err := c.doGet(ctx, "/1/user/-/activities.json", &resp); err != nil {
+		return nil, fmt.Errorf("fitbit: fetch lifetime stats: %w", err)
+	}
+	return mapLifetimeStats(&resp), nil
+}
+
+func (c *FitbitClient) FetchBadges(ctx context.Context) ([]entity.FitbitBadge, error) {
+	var resp BadgesResponse
+	if err := c.doGet(ctx, "/1/user/-/badges.json", &resp); err != nil {
+		return nil, fmt.Errorf("fitbit: fetch badges: %w", err)
+	}
+	return mapBadges(&resp), nil
+}
+
 func (c *FitbitClient) FetchSpO2(ctx context.Context, date time.Time) (avg, min, max float32, err error) {
 	dateStr := date.Format("2006-01-02")
 
@@ -240,6 +294,18 @@ func (c *FitbitClient) FetchSkinTemperature(ctx context.Context, date time.Time)
 	return tempResp.TempSkin[0].Value.NightlyRelative, nil
 }
 
+// FetchWaterLog returns the total water logged on date in mL.
+func (c *FitbitClient) FetchWaterLog(ctx context.Context, date time.Time) (int, error) {
+	dateStr := date.Format("2006-01-02")
+
+	var waterResp WaterLogResponse
+	if err := c.doGet(ctx, fmt.Sprintf("/1/user/-/foods/log/water/date/%s.json", dateStr), &waterResp); err != nil {
+		return 0, fmt.Errorf("fitbit: fetch water log: %w", err)
+	}
+
+	return int(math.Round(waterResp.Summary.Water)), nil
+}
+
 func (c *FitbitClient) FetchExerciseLogs(ctx context.Context, date time.Time) ([]entity.ExerciseLog, error) {
 	dateStr := date.Format("2006-01-02")
 
@@ -248,5 +314,5 @@ func (c *FitbitClient) FetchExerciseLogs(ctx context.Context, date time.Time) ([
 		return nil, fmt.Errorf("fitbit: fetch activities: %w", err)
 	}
 
-	return mapExerciseLogs(&actResp, date), nil
+	return mapExerciseLogs(&actResp, date, c.profile), nil
 }
@@ -0,0 +1,419 @@
+package fitbit
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+// newFakeServer serves responses keyed by request path as a stand-in for the
+// Fitbit Web API. A string value is written as-is; anything else is encoded
+// as JSON. Unknown paths return 404, and requests without the test bearer
+// token return 401.
+func newFakeServer(t *testing.T, responses map[string]any) (*FitbitClient, *httptest.Server) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if s, ok := body.(string); ok {
+			w.Write([]byte(s))
+			return
+		}
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			t.Errorf("encode response for %s: %v", r.URL.Path, err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return newTestClient(t, srv), srv
+}
+
+var fakeDate = time.Date(2026, 2, 17, 0, 0, 0, 0, jst)
+
+func TestIntegration_FetchDailySummary(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]any{
+		"/1/user/-/activities/date/2026-02-17.json": `{
+			"activities": [],
+			"goals": {"caloriesOut": 2500, "distance": 8.05, "steps": 10000},
+			"summary": {
+				"activeScore": -1,
+				"activeZoneMinutes": 34,
+				"caloriesBMR": 1520,
+				"caloriesOut": 2301,
+				"distances": [
+					{"activity": "total", "distance": 6.42},
+					{"activity": "tracker", "distance": 6.42},
+					{"activity": "veryActive", "distance": 1.9}
+				],
+				"fairlyActiveMinutes": 12,
+				"floors": 9,
+				"heartRateZones": [
+					{"caloriesOut": 1700.5, "max": 113, "min": 30, "minutes": 1290, "name": "Out of Range"},
+					{"caloriesOut": 420.1, "max": 137, "min": 113, "minutes": 95, "name": "Fat Burn"},
+					{"caloriesOut": 120.7, "max": 169, "min": 137, "minutes": 14, "name": "Cardio"},
+					{"caloriesOut": 10.2, "max": 220, "min": 169, "minutes": 2, "name": "Peak"}
+				],
+				"lightlyActiveMinutes": 210,
+				"restingHeartRate": 58,
+				"sedentaryMinutes": 640,
+				"steps": 8412,
+				"veryActiveMinutes": 22
+			}
+		}`,
+		"/1/user/-/cardioscore/date/2026-02-17.json": `{"cardioScore": [{"dateTime": "2026-02-17", "value": {"vo2Max": "44-48"}}]}`,
+	})
+
+	got, err := c.FetchDailySummary(context.Background(), fakeDate)
+	if err != nil {
+		t.Fatalf("FetchDailySummary() error = %v", err)
+	}
+	want := entity.DailySummary{
+		Steps: 8412, CaloriesTotal: 2301, CaloriesBMR: 1520, CaloriesActive: 781,
+		Floors: 9, RestingHR: 58, ActiveZoneMin: 34, DistanceKM: 6.42,
+		MinutesSedentary: 640, MinutesLightly: 210, MinutesFairly: 12, MinutesVery: 22,
+		HRZoneOutMin: 1290, HRZoneFatMin: 95, HRZoneCardioMin: 14, HRZonePeakMin: 2,
+	}
+	if got.Steps != want.Steps || got.CaloriesTotal != want.CaloriesTotal || got.CaloriesBMR != want.CaloriesBMR ||
+		got.CaloriesActive != want.CaloriesActive || got.Floors != want.Floors || got.RestingHR != want.RestingHR ||
+		got.ActiveZoneMin != want.ActiveZoneMin || got.DistanceKM != want.DistanceKM {
+		t.Errorf("activity fields = %+v, want %+v", got, want)
+	}
+	if got.MinutesSedentary != want.MinutesSedentary || got.MinutesLightly != want.MinutesLightly ||
+		got.MinutesFairly != want.MinutesFairly || got.MinutesVery != want.MinutesVery {
+		t.Errorf("activity minutes = %d/%d/%d/%d, want 640/210/12/22",
+			got.MinutesSedentary, got.MinutesLightly, got.MinutesFairly, got.MinutesVery)
+	}
+	if got.HRZoneOutMin != want.HRZoneOutMin || got.HRZoneFatMin != want.HRZoneFatMin ||
+		got.HRZoneCardioMin != want.HRZoneCardioMin || got.HRZonePeakMin != want.HRZonePeakMin {
+		t.Errorf("HR zones = %d/%d/%d/%d, want 1290/95/14/2",
+			got.HRZoneOutMin, got.HRZoneFatMin, got.HRZoneCardioMin, got.HRZonePeakMin)
+	}
+	if got.VO2Max == nil || *got.VO2Max != 46 {
+		t.Errorf("VO2Max = %v, want 46", got.VO2Max)
+	}
+	if !got.Date.Equal(fakeDate) || len(got.Providers) != 1 || got.Providers[0] != "fitbit" {
+		t.Errorf("Date = %v, Providers = %v", got.Date, got.Providers)
+	}
+}
+
+func TestIntegration_FetchDailySummary_CardioScoreMissing(t *testing.T) {
+	// Devices without VO2 Max support 404 the cardioscore endpoint; the
+	// summary must still be returned.
+	c, _ := newFakeServer(t, map[string]any{
+		"/1/user/-/activities/date/2026-02-17.json": `{"activities": [], "summary": {"steps": 120, "restingHeartRate": 61}}`,
+	})
+
+	got, err := c.FetchDailySummary(context.Background(), fakeDate)
+	if err != nil {
+		t.Fatalf("FetchDailySummary() error = %v", err)
+	}
+	if got.Steps != 120 || got.RestingHR != 61 || got.VO2Max != nil {
+		t.Errorf("summary = %+v, want steps 120, resting HR 61, no VO2Max", got)
+	}
+}
+
+func TestIntegration_FetchHRV(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]any{
+		"/1/user/-/hrv/date/2026-02-17.json": `{"hrv": [{"value": {"dailyRmssd": 34.938, "deepRmssd": 31.567}, "dateTime": "2026-02-17"}]}`,
+	})
+
+	daily, deep, err := c.FetchHRV(context.Background(), fakeDate)
+	if err != nil {
+		t.Fatalf("FetchHRV() error = %v", err)
+	}
+	if daily != 34.938 || deep != 31.567 {
+		t.Errorf("FetchHRV() = %v, %v, want 34.938, 31.567", daily, deep)
+	}
+}
+
+func TestIntegration_FetchHRV_NoData(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]any{
+		"/1/user/-/hrv/date/2026-02-17.json": `{"hrv": []}`,
+	})
+
+	if _, _, err := c.FetchHRV(context.Background(), fakeDate); err == nil {
+		t.Error("FetchHRV() error = nil, want error for empty hrv list")
+	}
+}
+
+func TestIntegration_FetchSpO2(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]any{
+		"/1/user/-/spo2/date/2026-02-17.json": `{"dateTime": "2026-02-17", "value": {"avg": 96.4, "min": 92.1, "max": 99.2}}`,
+	})
+
+	avg, lo, hi, err := c.FetchSpO2(context.Background(), fakeDate)
+	if err != nil {
+		t.Fatalf("FetchSpO2() error = %v", err)
+	}
+	if avg != 96.4 || lo != 92.1 || hi != 99.2 {
+		t.Errorf("FetchSpO2() = %v, %v, %v, want 96.4, 92.1, 99.2", avg, lo, hi)
+	}
+}
+
+func TestIntegration_FetchBreathingRate(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]any{
+		"/1/user/-/br/date/2026-02-17/all.json": `{"br": [{
+			"dateTime": "2026-02-17",
+			"value": {
+				"deepSleepSummary": {"breathingRate": 13.8},
+				"remSleepSummary": {"breathingRate": 15.2},
+				"fullSleepSummary": {"breathingRate": 14.6},
+				"lightSleepSummary": {"breathingRate": 14.4}
+			}
+		}]}`,
+	})
+
+	full, deep, light, rem, err := c.FetchBreathingRate(context.Background(), fakeDate)
+	if err != nil {
+		t.Fatalf("FetchBreathingRate() error = %v", err)
+	}
+	if full != 14.6 || deep != 13.8 || light != 14.4 || rem != 15.2 {
+		t.Errorf("FetchBreathingRate() = %v, %v, %v, %v, want 14.6, 13.8, 14.4, 15.2", full, deep, light, rem)
+	}
+}
+
+func TestIntegration_FetchSkinTemperature(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]any{
+		"/1/user/-/temp/skin/date/2026-02-17.json": `{"tempSkin": [{"dateTime": "2026-02-17", "logType": "dedicated_temp_sensor", "value": {"nightlyRelative": -0.42}}]}`,
+	})
+
+	got, err := c.FetchSkinTemperature(context.Background(), fakeDate)
+	if err != nil {
+		t.Fatalf("FetchSkinTemperature() error = %v", err)
+	}
+	if got != -0.42 {
+		t.Errorf("FetchSkinTemperature() = %v, want -0.42", got)
+	}
+}
+
+func TestIntegration_FetchSleepStages(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]any{
+		"/1.2/user/-/sleep/date/2026-02-17.json": `{
+			"sleep": [
+				{
+					"dateOfSleep": "2026-02-17",
+					"duration": 3600000,
+					"endTime": "2026-02-17T15:00:00.000",
+					"isMainSleep": false,
+					"levels": {"data": [{"dateTime": "2026-02-17T14:00:00.000", "level": "light", "seconds": 3600}], "summary": {}},
+					"logId": 111,
+					"minutesAsleep": 55,
+					"minutesAwake": 5,
+					"startTime": "2026-02-17T14:00:00.000",
+					"type": "stages"
+				},
+				{
+					"dateOfSleep": "2026-02-17",
+					"duration": 27000000,
+					"endTime": "2026-02-17T06:30:00.000",
+					"isMainSleep": true,
+					"levels": {
+						"data": [
+							{"dateTime": "2026-02-16T23:00:00.000", "level": "wake", "seconds": 600},
+							{"dateTime": "2026-02-16T23:10:00.000", "level": "light", "seconds": 1800},
+							{"dateTime": "2026-02-16T23:40:00.000", "level": "deep", "seconds": 3600},
+							{"dateTime": "2026-02-17T00:40:00.000", "level": "rem", "seconds": 1200}
+						],
+						"summary": {
+							"deep": {"count": 3, "minutes": 72, "thirtyDayAvgMinutes": 68},
+							"light": {"count": 25, "minutes": 240, "thirtyDayAvgMinutes": 230},
+							"rem": {"count": 6, "minutes": 85, "thirtyDayAvgMinutes": 90},
+							"wake": {"count": 20, "minutes": 53, "thirtyDayAvgMinutes": 50}
+						}
+					},
+					"logId": 222,
+					"minutesAfterWakeup": 0,
+					"minutesAsleep": 397,
+					"minutesAwake": 53,
+					"startTime": "2026-02-16T23:00:00.000",
+					"timeInBed": 450,
+					"type": "stages"
+				}
+			],
+			"summary": {"totalMinutesAsleep": 452, "totalTimeInBed": 510}
+		}`,
+	})
+
+	stages, record, err := c.FetchSleepStages(context.Background(), fakeDate)
+	if err != nil {
+		t.Fatalf("FetchSleepStages() error = %v", err)
+	}
+
+	wantStages := []entity.SleepStage{
+		{Time: time.Date(2026, 2, 16, 23, 0, 0, 0, jst), Stage: "wake", Seconds: 600},
+		{Time: time.Date(2026, 2, 16, 23, 10, 0, 0, jst), Stage: "light", Seconds: 1800},
+		{Time: time.Date(2026, 2, 16, 23, 40, 0, 0, jst), Stage: "deep", Seconds: 3600},
+		{Time: time.Date(2026, 2, 17, 0, 40, 0, 0, jst), Stage: "rem", Seconds: 1200},
+	}
+	if len(stages) != len(wantStages) {
+		t.Fatalf("len(stages) = %d, want %d (main sleep only)", len(stages), len(wantStages))
+	}
+	for i, w := range wantStages {
+		s := stages[i]
+		if !s.Time.Equal(w.Time) || s.Stage != w.Stage || s.Seconds != w.Seconds ||
+			s.LogID != 222 || s.Source != entity.SleepStageSourceFitbit {
+			t.Errorf("stages[%d] = %+v, want %+v from log 222", i, s, w)
+		}
+	}
+
+	if record == nil {
+		t.Fatal("record = nil, want main sleep record")
+	}
+	if record.LogID != 222 || !record.StartTime.Equal(time.Date(2026, 2, 16, 23, 0, 0, 0, jst)) ||
+		!record.EndTime.Equal(time.Date(2026, 2, 17, 6, 30, 0, 0, jst)) {
+		t.Errorf("record = %+v, want log 222 from 23:00 to 06:30", record)
+	}
+	if record.DurationMin != 450 || record.MinutesAsleep != 397 || record.MinutesAwake != 53 || record.Type != "stages" {
+		t.Errorf("record totals = %+v", record)
+	}
+	if record.DeepMin != 72 || record.LightMin != 240 || record.REMMin != 85 || record.WakeMin != 53 {
+		t.Errorf("record stage minutes = %d/%d/%d/%d, want 72/240/85/53",
+			record.DeepMin, record.LightMin, record.REMMin, record.WakeMin)
+	}
+}
+
+func TestIntegration_FetchHeartRateIntraday(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]any{
+		"/1/user/-/activities/heart/date/2026-02-17/1d/1min.json": `{
+			"activities-heart": [{"dateTime": "2026-02-17", "value": {"customHeartRateZones": [], "restingHeartRate": 58}}],
+			"activities-heart-intraday": {
+				"dataset": [
+					{"time": "00:00:00", "value": 61},
+					{"time": "00:01:00", "value": 59},
+					{"time": "23:59:00", "value": 64}
+				],
+				"datasetInterval": 1,
+				"datasetType": "minute"
+			}
+		}`,
+	})
+
+	got, err := c.FetchHeartRateIntraday(context.Background(), fakeDate)
+	if err != nil {
+		t.Fatalf("FetchHeartRateIntraday() error = %v", err)
+	}
+	want := []entity.HeartRateSample{
+		{Time: time.Date(2026, 2, 17, 0, 0, 0, 0, jst), BPM: 61},
+		{Time: time.Date(2026, 2, 17, 0, 1, 0, 0, jst), BPM: 59},
+		{Time: time.Date(2026, 2, 17, 23, 59, 0, 0, jst), BPM: 64},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("len(samples) = %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Time.Equal(want[i].Time) || got[i].BPM != want[i].BPM {
+			t.Errorf("samples[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIntegration_FetchActiveZoneMinutesIntraday(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]any{
+		"/1/user/-/activities/active-zone-minutes/date/2026-02-17/1d/1min.json": `{
+			"activities-active-zone-minutes-intraday": [{
+				"dateTime": "2026-02-17",
+				"minutes": [
+					{"minute": "2026-02-17T07:30:00", "value": {"activeZoneMinutes": 1, "fatBurnActiveZoneMinutes": 1}},
+					{"minute": "2026-02-17T07:31:00", "value": {"activeZoneMinutes": 2, "cardioActiveZoneMinutes": 2}},
+					{"minute": "2026-02-17T07:32:00", "value": {"activeZoneMinutes": 2, "peakActiveZoneMinutes": 2}},
+					{"minute": "not-a-time", "value": {"activeZoneMinutes": 1, "fatBurnActiveZoneMinutes": 1}}
+				]
+			}]
+		}`,
+	})
+
+	got, err := c.FetchActiveZoneMinutesIntraday(context.Background(), fakeDate)
+	if err != nil {
+		t.Fatalf("FetchActiveZoneMinutesIntraday() error = %v", err)
+	}
+	at := func(min int) time.Time { return time.Date(2026, 2, 17, 7, min, 0, 0, jst) }
+	want := []entity.ActiveZoneSample{
+		{Time: at(30), FatBurnMin: 1},
+		{Time: at(31), CardioMin: 1},
+		{Time: at(32), PeakMin: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("len(samples) = %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Time.Equal(want[i].Time) || got[i].FatBurnMin != want[i].FatBurnMin ||
+			got[i].CardioMin != want[i].CardioMin || got[i].PeakMin != want[i].PeakMin {
+			t.Errorf("samples[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIntegration_FetchExerciseLogs(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]any{
+		"/1/user/-/activities/date/2026-02-17.json": `{
+			"activities": [
+				{
+					"activeZoneMinutes": {
+						"minutesInHeartRateZones": [],
+						"totalMinutes": 18,
+						"activeZoneMinutes": [{"minuteInZone": 12, "type": "FAT_BURN"}, {"minuteInZone": 3, "type": "CARDIO"}]
+					},
+					"activityId": 90009,
+					"activityName": "Run",
+					"averageHeartRate": 148,
+					"calories": 312,
+					"distance": 5.21,
+					"distanceUnit": "Kilometer",
+					"duration": 1800000,
+					"logId": 61234567890,
+					"startTime": "07:15",
+					"title": "Morning run"
+				}
+			],
+			"summary": {"steps": 9800}
+		}`,
+	})
+
+	got, err := c.FetchExerciseLogs(context.Background(), fakeDate)
+	if err != nil {
+		t.Fatalf("FetchExerciseLogs() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("len(logs) = %d, want 1", len(got))
+	}
+	l := got[0]
+	if l.ExternalID != "61234567890" || l.ActivityName != "Run" || l.Notes != "Morning run" {
+		t.Errorf("log identity = %q %q %q", l.ExternalID, l.ActivityName, l.Notes)
+	}
+	if !l.StartedAt.Equal(time.Date(2026, 2, 17, 7, 15, 0, 0, jst)) {
+		t.Errorf("StartedAt = %v, want 07:15 JST", l.StartedAt)
+	}
+	if l.DurationMS != 1800000 || l.Calories != 312 || l.AvgHR != 148 || l.DistanceKM != 5.21 {
+		t.Errorf("log metrics = %+v", l)
+	}
+	if math.Abs(float64(l.CaloriesPerMinute)-10.4) > 1e-4 {
+		t.Errorf("CaloriesPerMinute = %v, want 10.4", l.CaloriesPerMinute)
+	}
+	var zones struct {
+		TotalMinutes int `json:"totalMinutes"`
+	}
+	if err := json.Unmarshal(l.ZoneMinutes, &zones); err != nil || zones.TotalMinutes != 18 {
+		t.Errorf("ZoneMinutes = %s, want totalMinutes 18", l.ZoneMinutes)
+	}
+}
+
+func TestIntegration_ErrorStatus(t *testing.T) {
+	c, _ := newFakeServer(t, nil)
+
+	if _, err := c.FetchExerciseLogs(context.Background(), fakeDate); err == nil {
+		t.Error("FetchExerciseLogs() error = nil, want error for 404")
+	}
+}
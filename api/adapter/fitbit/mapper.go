@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"vitametron/api/domain/entity"
+	"vitametron/api/infrastructure/config"
 )
 
 // jst is the Asia/Tokyo timezone (UTC+9) used for parsing Fitbit timestamps.
@@ -75,7 +76,7 @@ func MapSleepStage(stage string) string {
 func mapActivityToSummary(resp *ActivityResponse, date time.Time) *entity.DailySummary {
 	s := &entity.DailySummary{
 		Date:             date,
-		Provider:         "fitbit",
+		Providers:        []string{"fitbit"},
 		Steps:            resp.Summary.Steps,
 		CaloriesTotal:    resp.Summary.CaloriesOut,
 		CaloriesBMR:      resp.Summary.CaloriesBMR,
@@ -138,6 +139,7 @@ func mapSleepStages(resp *SleepResponse, date time.Time) []entity.SleepStage {
 				Stage:   MapSleepStage(d.Level),
 				Seconds: d.Seconds,
 				LogID:   sleep.LogID,
+				Source:  entity.SleepStageSourceFitbit,
 			})
 		}
 		break // only main sleep
@@ -204,8 +206,55 @@ func mapHRIntraday(resp *HRIntradayResponse, date time.Time) []entity.HeartRateS
 	return samples
 }
 
+// mapHRVIntraday converts 5-minute HRV segments to HRVSample entities.
+func mapHRVIntraday(resp *HRVIntradayResponse) []entity.HRVSample {
+	var samples []entity.HRVSample
+	for _, day := range resp.HRV {
+		for _, m := range day.Minutes {
+			t, err := time.ParseInLocation("2006-01-02T15:04:05.000", m.Minute, jst)
+			if err != nil {
+				continue
+			}
+			samples = append(samples, entity.HRVSample{
+				Time:  t,
+				RMSSD: m.Value.RMSSD,
+			})
+		}
+	}
+	return samples
+}
+
+// mapAZMIntraday converts 1-minute active zone minutes to ActiveZoneSample
+// entities. Fitbit credits cardio and peak minutes double, so zones are
+// recorded as in (1) or out (0) rather than by their credited value.
+func mapAZMIntraday(resp *AZMIntradayResponse) []entity.ActiveZoneSample {
+	var samples []entity.ActiveZoneSample
+	for _, day := range resp.Intraday {
+		for _, m := range day.Minutes {
+			t, err := time.ParseInLocation("2006-01-02T15:04:05", m.Minute, jst)
+			if err != nil {
+				continue
+			}
+			samples = append(samples, entity.ActiveZoneSample{
+				Time:       t,
+				FatBurnMin: inZone(m.Value.FatBurn),
+				CardioMin:  inZone(m.Value.Cardio),
+				PeakMin:    inZone(m.Value.Peak),
+			})
+		}
+	}
+	return samples
+}
+
+func inZone(credited int) int {
+	if credited > 0 {
+		return 1
+	}
+	return 0
+}
+
 // mapExerciseLogs converts activity entries to ExerciseLog entities.
-func mapExerciseLogs(resp *ActivityResponse, date time.Time) []entity.ExerciseLog {
+func mapExerciseLogs(resp *ActivityResponse, date time.Time, profile config.ProfileConfig) []entity.ExerciseLog {
 	dateStr := date.Format("2006-01-02")
 	logs := make([]entity.ExerciseLog, 0, len(resp.Activities))
 
@@ -226,9 +275,17 @@ func mapExerciseLogs(resp *ActivityResponse, date time.Time) []entity.ExerciseLo
 			Calories:     a.Calories,
 			AvgHR:        a.AverageHeartRate,
 			DistanceKM:   float32(a.Distance),
+			MET:          computeMET(a.ActivityName, a.AverageHeartRate, profile),
+			Pace:         entity.ComputePace(a.Duration, float32(a.Distance)),
+			Tags:         []string{},
+			Notes:        a.Title,
 			SyncedAt:     time.Now(),
 		}
 
+		if a.Duration > 0 {
+			log.CaloriesPerMinute = float32(a.Calories) / (float32(a.Duration) / 60000)
+		}
+
 		if a.ActiveZoneMinutes != nil {
 			if zoneJSON, err := json.Marshal(a.ActiveZoneMinutes); err == nil {
 				log.ZoneMinutes = zoneJSON
@@ -240,3 +297,32 @@ func mapExerciseLogs(resp *ActivityResponse, date time.Time) []entity.ExerciseLo
 
 	return logs
 }
+
+func mapLifetimeStats(resp *LifetimeResponse) *entity.FitbitLifetimeStats {
+	t := resp.Lifetime.Total
+	return &entity.FitbitLifetimeStats{
+		Steps:       t.Steps,
+		DistanceKM:  t.Distance,
+		Floors:      t.Floors,
+		ActiveScore: t.ActiveScore,
+		CaloriesOut: t.CaloriesOut,
+	}
+}
+
+func mapBadges(resp *BadgesResponse) []entity.FitbitBadge {
+	badges := make([]entity.FitbitBadge, 0, len(resp.Badges))
+	for _, b := range resp.Badges {
+		badges = append(badges, entity.FitbitBadge{Name: b.Name, Date: b.DateTime, Value: b.Value})
+	}
+	return badges
+}
+
+func mapSubscription(resp *SubscriptionResponse) entity.FitbitSubscription {
+	return entity.FitbitSubscription{
+		SubscriptionID: resp.SubscriptionID,
+		SubscriberID:   resp.SubscriberID,
+		CollectionType: resp.CollectionType,
+		OwnerID:        resp.OwnerID,
+		OwnerType:      resp.OwnerType,
+	}
+}
@@ -1,9 +1,13 @@
 package fitbit
 
 import (
+	"encoding/json"
 	"math"
 	"testing"
 	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/infrastructure/config"
 )
 
 func TestParseVO2MaxRange(t *testing.T) {
@@ -120,8 +124,8 @@ func TestMapActivityToSummary(t *testing.T) {
 	if s.HRZonePeakMin != 10 {
 		t.Errorf("HRZonePeakMin = %d, want 10", s.HRZonePeakMin)
 	}
-	if s.Provider != "fitbit" {
-		t.Errorf("Provider = %q, want fitbit", s.Provider)
+	if len(s.Providers) != 1 || s.Providers[0] != "fitbit" {
+		t.Errorf("Providers = %v, want [fitbit]", s.Providers)
 	}
 }
 
@@ -196,6 +200,9 @@ func TestMapSleepStages(t *testing.T) {
 	if stages[0].LogID != 12345 {
 		t.Errorf("stages[0].LogID = %d, want 12345", stages[0].LogID)
 	}
+	if stages[0].Source != entity.SleepStageSourceFitbit {
+		t.Errorf("stages[0].Source = %q, want %q", stages[0].Source, entity.SleepStageSourceFitbit)
+	}
 }
 
 func TestMapHRIntraday(t *testing.T) {
@@ -222,11 +229,62 @@ func TestMapHRIntraday(t *testing.T) {
 	}
 }
 
+func TestMapHRVIntraday(t *testing.T) {
+	resp := &HRVIntradayResponse{}
+	var valid, invalid HRVMinute
+	valid.Minute = "2025-06-15T03:10:00.000"
+	valid.Value.RMSSD = 41.5
+	invalid.Minute = "not-a-time"
+	resp.HRV = append(resp.HRV, struct {
+		Minutes []HRVMinute `json:"minutes"`
+	}{Minutes: []HRVMinute{valid, invalid}})
+
+	samples := mapHRVIntraday(resp)
+
+	if len(samples) != 1 {
+		t.Fatalf("len(samples) = %d, want 1", len(samples))
+	}
+	if samples[0].RMSSD != 41.5 {
+		t.Errorf("RMSSD = %v, want 41.5", samples[0].RMSSD)
+	}
+	want := time.Date(2025, 6, 15, 3, 10, 0, 0, jst)
+	if !samples[0].Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", samples[0].Time, want)
+	}
+}
+
+func TestMapLifetimeStatsAndBadges(t *testing.T) {
+	var lifetime LifetimeResponse
+	if err := json.Unmarshal([]byte(`{"lifetime":{"total":{"activeScore":-1,"caloriesOut":-1,
+		"distance":4321.5,"floors":1200,"steps":5600000},"tracker":{"steps":5500000}}}`), &lifetime); err != nil {
+		t.Fatal(err)
+	}
+	stats := mapLifetimeStats(&lifetime)
+	want := entity.FitbitLifetimeStats{Steps: 5600000, DistanceKM: 4321.5, Floors: 1200, ActiveScore: -1, CaloriesOut: -1}
+	if *stats != want {
+		t.Errorf("stats = %+v, want %+v", *stats, want)
+	}
+
+	var badges BadgesResponse
+	if err := json.Unmarshal([]byte(`{"badges":[{"badgeType":"DAILY_STEPS","dateTime":"2025-03-02",
+		"name":"Trail Shoe (10,000 steps in a day)","shortName":"Trail Shoe","value":10000}]}`), &badges); err != nil {
+		t.Fatal(err)
+	}
+	got := mapBadges(&badges)
+	if len(got) != 1 || got[0] != (entity.FitbitBadge{Name: "Trail Shoe (10,000 steps in a day)", Date: "2025-03-02", Value: 10000}) {
+		t.Errorf("badges = %+v", got)
+	}
+	if empty := mapBadges(&BadgesResponse{}); empty == nil || len(empty) != 0 {
+		t.Errorf("mapBadges(empty) = %#v, want empty slice", empty)
+	}
+}
+
 func TestMapExerciseLogs(t *testing.T) {
 	resp := &ActivityResponse{}
 	resp.Activities = []struct {
 		LogID              int64   `json:"logId"`
 		ActivityName       string  `json:"activityName"`
+		Title              string  `json:"title"`
 		StartTime          string  `json:"startTime"`
 		Duration           int64   `json:"duration"`
 		Calories           int     `json:"calories"`
@@ -244,6 +302,7 @@ func TestMapExerciseLogs(t *testing.T) {
 		{
 			LogID:            99999,
 			ActivityName:     "Run",
+			Title:            "Morning loop",
 			StartTime:        "07:30",
 			Duration:         1800000,
 			Calories:         350,
@@ -253,7 +312,7 @@ func TestMapExerciseLogs(t *testing.T) {
 	}
 
 	date := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
-	logs := mapExerciseLogs(resp, date)
+	logs := mapExerciseLogs(resp, date, config.ProfileConfig{})
 
 	if len(logs) != 1 {
 		t.Fatalf("len(logs) = %d, want 1", len(logs))
@@ -264,9 +323,21 @@ func TestMapExerciseLogs(t *testing.T) {
 	if logs[0].ActivityName != "Run" {
 		t.Errorf("ActivityName = %q, want Run", logs[0].ActivityName)
 	}
+	if logs[0].Notes != "Morning loop" {
+		t.Errorf("Notes = %q, want Morning loop", logs[0].Notes)
+	}
 	if logs[0].Calories != 350 {
 		t.Errorf("Calories = %d, want 350", logs[0].Calories)
 	}
+	if logs[0].MET != 9.8 {
+		t.Errorf("MET = %f, want 9.8", logs[0].MET)
+	}
+	if math.Abs(float64(logs[0].CaloriesPerMinute)-350.0/30) > 0.01 {
+		t.Errorf("CaloriesPerMinute = %f, want %f", logs[0].CaloriesPerMinute, 350.0/30)
+	}
+	if math.Abs(float64(logs[0].Pace)-1800/5.2) > 0.01 {
+		t.Errorf("Pace = %f, want %f", logs[0].Pace, 1800/5.2)
+	}
 }
 
 func float64Ptr(v float64) *float64 { return &v }
@@ -0,0 +1,58 @@
+package fitbit
+
+import (
+	"strings"
+
+	"vitametron/api/infrastructure/config"
+)
+
+// metTable maps activity names to standard MET values from the
+// Compendium of Physical Activities. Keys are lower-cased; both Fitbit's
+// short names ("Run", "Bike") and generic names ("Running") are included.
+var metTable = map[string]float32{
+	"running":         9.8,
+	"run":             9.8,
+	"treadmill":       9.0,
+	"walking":         3.5,
+	"walk":            3.5,
+	"hiking":          6.0,
+	"hike":            6.0,
+	"cycling":         7.5,
+	"bike":            7.5,
+	"outdoor bike":    7.5,
+	"spinning":        8.5,
+	"swimming":        5.8,
+	"swim":            5.8,
+	"elliptical":      5.0,
+	"aerobic workout": 7.3,
+	"weights":         5.0,
+	"yoga":            2.5,
+	"pilates":         3.0,
+	"tennis":          7.3,
+	"sport":           6.0,
+}
+
+// LookupMET returns the standard MET value for an activity name, or 0 if unknown.
+func LookupMET(activityName string) float32 {
+	return metTable[strings.ToLower(strings.TrimSpace(activityName))]
+}
+
+// EstimateMETFromHR estimates MET from average heart rate using the Keytel
+// energy expenditure equation, divided by 4.184 to convert kJ to kcal/min.
+// Results below resting (1 MET) are clamped.
+func EstimateMETFromHR(avgHR int, weightKG float64, age int) float32 {
+	met := (-55.0969 + 0.6309*float64(avgHR) + 0.1988*weightKG + 0.2017*float64(age)) / 4.184
+	if met < 1 {
+		met = 1
+	}
+	return float32(met)
+}
+
+// computeMET prefers the HR-based formula when heart rate and user profile are
+// known, otherwise falls back to the lookup table.
+func computeMET(activityName string, avgHR int, profile config.ProfileConfig) float32 {
+	if avgHR > 0 && profile.WeightKG > 0 && profile.Age > 0 {
+		return EstimateMETFromHR(avgHR, profile.WeightKG, profile.Age)
+	}
+	return LookupMET(activityName)
+}
@@ -0,0 +1,40 @@
+package fitbit
+
+import (
+	"math"
+	"testing"
+
+	"vitametron/api/infrastructure/config"
+)
+
+func TestComputeMET(t *testing.T) {
+	profile := config.ProfileConfig{WeightKG: 70, Age: 30}
+
+	tests := []struct {
+		name     string
+		activity string
+		avgHR    int
+		profile  config.ProfileConfig
+		want     float32
+	}{
+		{"lookup running", "Running", 0, profile, 9.8},
+		{"lookup fitbit walk", "Walk", 0, profile, 3.5},
+		{"lookup case insensitive", "CYCLING", 0, profile, 7.5},
+		{"lookup unknown", "Underwater Basket Weaving", 0, profile, 0},
+		{"lookup when profile unknown", "Run", 145, config.ProfileConfig{}, 9.8},
+		// (-55.0969 + 0.6309*145 + 0.1988*70 + 0.2017*30) / 4.184
+		{"formula", "Run", 145, profile, 13.4686},
+		// (-55.0969 + 0.6309*100 + 0.1988*70 + 0.2017*30) / 4.184
+		{"formula overrides lookup", "Yoga", 100, profile, 6.6826},
+		{"formula clamped to resting", "Walk", 40, profile, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeMET(tt.activity, tt.avgHR, tt.profile)
+			if math.Abs(float64(got-tt.want)) > 0.01 {
+				t.Errorf("computeMET(%q, %d) = %f, want %f", tt.activity, tt.avgHR, got, tt.want)
+			}
+		})
+	}
+}
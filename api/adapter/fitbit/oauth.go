@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -20,22 +21,38 @@ import (
 )
 
 const (
-	providerName = "fitbit"
-	pkceKeyPrefix = "oauth:pkce:"
-	pkceTTL       = 10 * time.Minute
+	pkceKeyPrefix       = "oauth:pkce:"
+	pkceTTL             = 10 * time.Minute
 	tokenBufferDuration = 5 * time.Minute
+
+	// tokenSaveFailedKey is set when a refreshed token could not be persisted
+	// and cleared by the next successful save. While it is set the stored
+	// refresh token may already be invalidated on the Fitbit side.
+	tokenSaveFailedKey = "auth:fitbit:token_save_failed"
+
+	revokeURL     = "https://api.fitbit.com/oauth2/revoke"
+	introspectURL = "https://api.fitbit.com/1.1/oauth2/introspect"
 )
 
+var _ port.OAuthProvider = (*FitbitOAuth)(nil)
+
 type FitbitOAuth struct {
-	config     *oauth2.Config
-	httpClient *http.Client
-	tokenRepo  port.TokenRepository
-	redis      *redis.Client
-	encryptor  *crypto.Encryptor
+	// providerName keys the stored token in port.TokenRepository.
+	providerName string
+	config       *oauth2.Config
+	httpClient   *http.Client
+	tokenRepo    port.TokenRepository
+	redis        *redis.Client
+	encryptor    *crypto.Encryptor
+	pkceStates   port.PKCEStateRepository
+
+	revokeURL     string
+	introspectURL string
 }
 
 func NewFitbitOAuth(cfg config.FitbitConfig, rdb *redis.Client, tokenRepo port.TokenRepository, enc *crypto.Encryptor) *FitbitOAuth {
 	return &FitbitOAuth{
+		providerName: "fitbit",
 		config: &oauth2.Config{
 			ClientID:     cfg.ClientID,
 			ClientSecret: cfg.ClientSecret,
@@ -48,6 +65,7 @@ func NewFitbitOAuth(cfg config.FitbitConfig, rdb *redis.Client, tokenRepo port.T
 				"sleep",
 				"temperature",
 				"cardio_fitness",
+				"nutrition",
 				"profile",
 			},
 			Endpoint: oauth2.Endpoint{
@@ -60,9 +78,19 @@ func NewFitbitOAuth(cfg config.FitbitConfig, rdb *redis.Client, tokenRepo port.T
 		tokenRepo:  tokenRepo,
 		redis:      rdb,
 		encryptor:  enc,
+
+		revokeURL:     revokeURL,
+		introspectURL: introspectURL,
 	}
 }
 
+// WithPKCEStateStore keeps a durable copy of each PKCE state so an OAuth
+// flow survives a Redis restart between authorization and callback.
+func (f *FitbitOAuth) WithPKCEStateStore(repo port.PKCEStateRepository) *FitbitOAuth {
+	f.pkceStates = repo
+	return f
+}
+
 func (f *FitbitOAuth) AuthorizationURL(ctx context.Context) (string, string, error) {
 	verifier := oauth2.GenerateVerifier()
 
@@ -73,23 +101,33 @@ func (f *FitbitOAuth) AuthorizationURL(ctx context.Context) (string, string, err
 
 	ok, err := f.redis.SetNX(ctx, pkceKeyPrefix+state, verifier, pkceTTL).Result()
 	if err != nil {
-		return "", "", fmt.Errorf("fitbit oauth: redis set: %w", err)
-	}
-	if !ok {
+		if f.pkceStates == nil {
+			return "", "", fmt.Errorf("fitbit oauth: redis set: %w", err)
+		}
+		log.Printf("warn: fitbit oauth: redis set state: %v", err)
+	} else if !ok {
 		return "", "", fmt.Errorf("fitbit oauth: state collision")
 	}
 
+	// With Redis down the Postgres copy is the only one, so its failure is
+	// fatal; otherwise it is best effort.
+	if f.pkceStates != nil {
+		if serr := f.pkceStates.Save(ctx, state, verifier, time.Now().Add(pkceTTL)); serr != nil {
+			if err != nil {
+				return "", "", fmt.Errorf("fitbit oauth: save state: %w", serr)
+			}
+			log.Printf("warn: fitbit oauth: save state to postgres: %v", serr)
+		}
+	}
+
 	authURL := f.config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
 	return authURL, state, nil
 }
 
 func (f *FitbitOAuth) ExchangeCode(ctx context.Context, code, state string) error {
-	verifier, err := f.redis.GetDel(ctx, pkceKeyPrefix+state).Result()
-	if err == redis.Nil {
-		return fmt.Errorf("fitbit oauth: invalid or expired state")
-	}
+	verifier, err := f.takeVerifier(ctx, state)
 	if err != nil {
-		return fmt.Errorf("fitbit oauth: redis get: %w", err)
+		return err
 	}
 
 	token, err := f.config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
@@ -100,8 +138,42 @@ func (f *FitbitOAuth) ExchangeCode(ctx context.Context, code, state string) erro
 	return f.saveToken(ctx, token)
 }
 
+// takeVerifier consumes the PKCE state, preferring Redis and falling back to
+// the Postgres copy when Redis has lost it or is unreachable. The Postgres
+// copy is removed on a Redis hit too, so a state is never usable twice.
+func (f *FitbitOAuth) takeVerifier(ctx context.Context, state string) (string, error) {
+	verifier, err := f.redis.GetDel(ctx, pkceKeyPrefix+state).Result()
+	if f.pkceStates == nil {
+		if err == redis.Nil {
+			return "", fmt.Errorf("fitbit oauth: invalid or expired state")
+		}
+		if err != nil {
+			return "", fmt.Errorf("fitbit oauth: redis get: %w", err)
+		}
+		return verifier, nil
+	}
+	if err != nil && err != redis.Nil {
+		log.Printf("warn: fitbit oauth: redis get state: %v", err)
+	}
+
+	stored, ok, perr := f.pkceStates.Take(ctx, state)
+	if err == nil {
+		if perr != nil {
+			log.Printf("warn: fitbit oauth: delete postgres state: %v", perr)
+		}
+		return verifier, nil
+	}
+	if perr != nil {
+		return "", fmt.Errorf("fitbit oauth: postgres state lookup: %w", perr)
+	}
+	if !ok {
+		return "", fmt.Errorf("fitbit oauth: invalid or expired state")
+	}
+	return stored, nil
+}
+
 func (f *FitbitOAuth) RefreshTokenIfNeeded(ctx context.Context) error {
-	_, encRefresh, expiresAt, err := f.tokenRepo.Get(ctx, providerName)
+	_, encRefresh, expiresAt, err := f.tokenRepo.Get(ctx, f.providerName)
 	if err != nil {
 		return fmt.Errorf("fitbit oauth: get token: %w", err)
 	}
@@ -125,25 +197,49 @@ func (f *FitbitOAuth) RefreshTokenIfNeeded(ctx context.Context) error {
 
 	if err := f.saveToken(ctx, newToken); err != nil {
 		log.Printf("CRITICAL: failed to save refreshed token: %v", err)
+		if f.redis != nil {
+			if serr := f.redis.Set(ctx, tokenSaveFailedKey, "true", 0).Err(); serr != nil {
+				log.Printf("ERROR: failed to flag unsaved token: %v", serr)
+			}
+		}
 		return fmt.Errorf("fitbit oauth: save refreshed token: %w", err)
 	}
 
 	return nil
 }
 
+// IsAuthorized reports whether a usable Fitbit token exists. A token that is
+// near or past expiry is refreshed and then checked with Fitbit's
+// introspection endpoint, so a grant revoked on the Fitbit side is detected
+// rather than trusted because it is still present in the DB.
 func (f *FitbitOAuth) IsAuthorized(ctx context.Context) (bool, error) {
-	_, _, _, err := f.tokenRepo.Get(ctx, providerName)
+	_, _, expiresAt, err := f.tokenRepo.Get(ctx, f.providerName)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			return false, nil
 		}
 		return false, err
 	}
-	return true, nil
+
+	if time.Now().Before(expiresAt.Add(-tokenBufferDuration)) {
+		return true, nil
+	}
+
+	if err := f.RefreshTokenIfNeeded(ctx); err != nil {
+		log.Printf("ERROR: fitbit token refresh during authorization check failed: %v", err)
+		return false, nil
+	}
+
+	accessToken, err := f.GetAccessToken(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return f.introspectToken(ctx, accessToken)
 }
 
 func (f *FitbitOAuth) Disconnect(ctx context.Context) error {
-	_, encRefresh, _, err := f.tokenRepo.Get(ctx, providerName)
+	_, encRefresh, _, err := f.tokenRepo.Get(ctx, f.providerName)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			return nil
@@ -151,19 +247,20 @@ func (f *FitbitOAuth) Disconnect(ctx context.Context) error {
 		return fmt.Errorf("fitbit oauth: get token for revoke: %w", err)
 	}
 
+	// Revocation failures are logged but never block removing the local token.
 	refreshToken, err := f.encryptor.Decrypt(encRefresh)
 	if err != nil {
-		log.Printf("warn: failed to decrypt refresh token for revoke: %v", err)
-	} else {
-		f.revokeToken(ctx, string(refreshToken))
+		log.Printf("ERROR: failed to decrypt refresh token for revoke: %v", err)
+	} else if err := f.revokeToken(ctx, string(refreshToken)); err != nil {
+		log.Printf("ERROR: %v", err)
 	}
 
-	return f.tokenRepo.Delete(ctx, providerName)
+	return f.tokenRepo.Delete(ctx, f.providerName)
 }
 
 // GetAccessToken returns the decrypted access token. Used by the API client.
 func (f *FitbitOAuth) GetAccessToken(ctx context.Context) (string, error) {
-	encAccess, _, _, err := f.tokenRepo.Get(ctx, providerName)
+	encAccess, _, _, err := f.tokenRepo.Get(ctx, f.providerName)
 	if err != nil {
 		return "", fmt.Errorf("fitbit oauth: get token: %w", err)
 	}
@@ -187,31 +284,89 @@ func (f *FitbitOAuth) saveToken(ctx context.Context, token *oauth2.Token) error
 		return fmt.Errorf("encrypt refresh token: %w", err)
 	}
 
-	return f.tokenRepo.Save(ctx, providerName, encAccess, encRefresh, token.Expiry)
+	if err := f.tokenRepo.Save(ctx, f.providerName, encAccess, encRefresh, token.Expiry); err != nil {
+		return err
+	}
+
+	if f.redis != nil {
+		if err := f.redis.Del(ctx, tokenSaveFailedKey).Err(); err != nil {
+			log.Printf("warn: failed to clear token save failure flag: %v", err)
+		}
+	}
+	return nil
+}
+
+// TokenSaveFailed reports whether the last token refresh could not be saved
+// and no successful save has happened since.
+func (f *FitbitOAuth) TokenSaveFailed(ctx context.Context) (bool, error) {
+	if f.redis == nil {
+		return false, nil
+	}
+	v, err := f.redis.Get(ctx, tokenSaveFailedKey).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("fitbit oauth: get token save flag: %w", err)
+	}
+	return v == "true", nil
 }
 
-func (f *FitbitOAuth) revokeToken(ctx context.Context, refreshToken string) {
+func (f *FitbitOAuth) revokeToken(ctx context.Context, refreshToken string) error {
 	data := url.Values{"token": {refreshToken}}
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		"https://api.fitbit.com/oauth2/revoke",
+		f.revokeURL,
 		strings.NewReader(data.Encode()))
 	if err != nil {
-		log.Printf("warn: failed to create revoke request: %v", err)
-		return
+		return fmt.Errorf("fitbit oauth: create revoke request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.SetBasicAuth(f.config.ClientID, f.config.ClientSecret)
 
 	resp, err := f.httpClient.Do(req)
 	if err != nil {
-		log.Printf("warn: fitbit revoke request failed: %v", err)
-		return
+		return fmt.Errorf("fitbit oauth: revoke request: %w", err)
 	}
 	resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("warn: fitbit revoke returned status %d", resp.StatusCode)
+		return fmt.Errorf("fitbit oauth: revoke returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// introspectToken asks Fitbit whether accessToken is still active.
+func (f *FitbitOAuth) introspectToken(ctx context.Context, accessToken string) (bool, error) {
+	data := url.Values{"token": {accessToken}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		f.introspectURL,
+		strings.NewReader(data.Encode()))
+	if err != nil {
+		return false, fmt.Errorf("fitbit oauth: create introspect request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("fitbit oauth: introspect request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("fitbit oauth: introspect returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Active bool `json:"active"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, fmt.Errorf("fitbit oauth: decode introspect response: %w", err)
 	}
+	return body.Active, nil
 }
 
 func generateState() (string, error) {
@@ -0,0 +1,275 @@
+package fitbit
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"vitametron/api/infrastructure/config"
+	"vitametron/api/infrastructure/crypto"
+	"vitametron/api/mocks"
+)
+
+func newTestOAuth(t *testing.T, srv *httptest.Server, tokenRepo *mocks.MockTokenRepository) (*FitbitOAuth, *crypto.Encryptor) {
+	t.Helper()
+	enc, err := crypto.NewEncryptor(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := NewFitbitOAuth(config.FitbitConfig{ClientID: "id", ClientSecret: "secret"}, nil, tokenRepo, enc)
+	f.revokeURL = srv.URL + "/revoke"
+	f.introspectURL = srv.URL + "/introspect"
+	f.config.Endpoint.TokenURL = srv.URL + "/token"
+	return f, enc
+}
+
+func TestFitbitOAuth_Disconnect_DeletesWhenRevokeFails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	var deleted bool
+	repo := &mocks.MockTokenRepository{
+		DeleteFunc: func(_ context.Context, _ string) error { deleted = true; return nil },
+	}
+	f, enc := newTestOAuth(t, srv, repo)
+	encRefresh, _ := enc.Encrypt([]byte("refresh"))
+	repo.GetFunc = func(_ context.Context, _ string) ([]byte, []byte, time.Time, error) {
+		return nil, encRefresh, time.Now(), nil
+	}
+
+	if err := f.Disconnect(context.Background()); err != nil {
+		t.Fatalf("Disconnect() error = %v", err)
+	}
+	if !deleted {
+		t.Error("token should be deleted even when revoke fails")
+	}
+	if err := f.revokeToken(context.Background(), "refresh"); err == nil {
+		t.Error("revokeToken() should return an error on non-200 status")
+	}
+}
+
+func TestFitbitOAuth_IsAuthorized(t *testing.T) {
+	tests := []struct {
+		name           string
+		expiresIn      time.Duration
+		introspect     string
+		wantIntrospect bool
+		want           bool
+	}{
+		{"fresh token skips introspection", time.Hour, `{"active":true}`, false, true},
+		{"expired token still active", -time.Hour, `{"active":true}`, true, true},
+		{"expired token revoked server-side", -time.Hour, `{"active":false}`, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var introspected bool
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/token":
+					w.Write([]byte(`{"access_token":"new-access","refresh_token":"new-refresh","token_type":"Bearer","expires_in":28800}`))
+				case "/introspect":
+					introspected = true
+					if got := r.Header.Get("Authorization"); got != "Bearer new-access" {
+						t.Errorf("Authorization = %q, want refreshed bearer token", got)
+					}
+					w.Write([]byte(tt.introspect))
+				}
+			}))
+			defer srv.Close()
+
+			repo := &mocks.MockTokenRepository{}
+			f, enc := newTestOAuth(t, srv, repo)
+			encAccess, _ := enc.Encrypt([]byte("old-access"))
+			encRefresh, _ := enc.Encrypt([]byte("old-refresh"))
+			expiresAt := time.Now().Add(tt.expiresIn)
+			repo.GetFunc = func(_ context.Context, _ string) ([]byte, []byte, time.Time, error) {
+				return encAccess, encRefresh, expiresAt, nil
+			}
+			repo.SaveFunc = func(_ context.Context, _ string, a, r []byte, exp time.Time) error {
+				encAccess, encRefresh, expiresAt = a, r, exp
+				return nil
+			}
+
+			got, err := f.IsAuthorized(context.Background())
+			if err != nil {
+				t.Fatalf("IsAuthorized() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsAuthorized() = %v, want %v", got, tt.want)
+			}
+			if introspected != tt.wantIntrospect {
+				t.Errorf("introspected = %v, want %v", introspected, tt.wantIntrospect)
+			}
+		})
+	}
+}
+
+func TestFitbitOAuth_TokenSaveFailedFlag(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"new-access","refresh_token":"new-refresh","token_type":"Bearer","expires_in":28800}`))
+	}))
+	defer srv.Close()
+
+	mr := miniredis.RunT(t)
+	repo := &mocks.MockTokenRepository{}
+	f, enc := newTestOAuth(t, srv, repo)
+	f.redis = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	encRefresh, _ := enc.Encrypt([]byte("old-refresh"))
+	repo.GetFunc = func(_ context.Context, _ string) ([]byte, []byte, time.Time, error) {
+		return nil, encRefresh, time.Now().Add(-time.Hour), nil
+	}
+	saveErr := errors.New("db down")
+	repo.SaveFunc = func(_ context.Context, _ string, _, _ []byte, _ time.Time) error {
+		return saveErr
+	}
+
+	ctx := context.Background()
+	if err := f.RefreshTokenIfNeeded(ctx); err == nil {
+		t.Fatal("RefreshTokenIfNeeded() should fail when save fails")
+	}
+	if v, _ := mr.Get(tokenSaveFailedKey); v != "true" {
+		t.Errorf("%s = %q, want \"true\"", tokenSaveFailedKey, v)
+	}
+	if failed, err := f.TokenSaveFailed(ctx); err != nil || !failed {
+		t.Errorf("TokenSaveFailed() = %v, %v; want true, nil", failed, err)
+	}
+
+	saveErr = nil
+	if err := f.RefreshTokenIfNeeded(ctx); err != nil {
+		t.Fatalf("RefreshTokenIfNeeded() error = %v", err)
+	}
+	if mr.Exists(tokenSaveFailedKey) {
+		t.Error("flag should be cleared after a successful save")
+	}
+	if failed, _ := f.TokenSaveFailed(ctx); failed {
+		t.Error("TokenSaveFailed() = true after successful save")
+	}
+}
+
+func newMemPKCEStates() (*mocks.MockPKCEStateRepository, map[string]string) {
+	states := map[string]string{}
+	return &mocks.MockPKCEStateRepository{
+		SaveFunc: func(_ context.Context, state, verifier string, _ time.Time) error {
+			states[state] = verifier
+			return nil
+		},
+		TakeFunc: func(_ context.Context, state string) (string, bool, error) {
+			v, ok := states[state]
+			delete(states, state)
+			return v, ok, nil
+		},
+	}, states
+}
+
+func TestFitbitOAuth_ExchangeCode_PostgresFallback(t *testing.T) {
+	var gotVerifier string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		gotVerifier = r.PostForm.Get("code_verifier")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"a","refresh_token":"r","token_type":"Bearer","expires_in":28800}`))
+	}))
+	defer srv.Close()
+
+	mr := miniredis.RunT(t)
+	repo := &mocks.MockTokenRepository{
+		SaveFunc: func(_ context.Context, _ string, _, _ []byte, _ time.Time) error { return nil },
+	}
+	f, _ := newTestOAuth(t, srv, repo)
+	f.redis = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	pkce, states := newMemPKCEStates()
+	f.WithPKCEStateStore(pkce)
+
+	ctx := context.Background()
+	_, state, err := f.AuthorizationURL(ctx)
+	if err != nil {
+		t.Fatalf("AuthorizationURL() error = %v", err)
+	}
+	verifier, _ := mr.Get(pkceKeyPrefix + state)
+	if states[state] != verifier {
+		t.Fatalf("postgres verifier = %q, want %q", states[state], verifier)
+	}
+
+	// Simulate a Redis restart between authorization and callback.
+	mr.FlushAll()
+	if err := f.ExchangeCode(ctx, "code", state); err != nil {
+		t.Fatalf("ExchangeCode() error = %v", err)
+	}
+	if gotVerifier != verifier {
+		t.Errorf("code_verifier = %q, want %q", gotVerifier, verifier)
+	}
+	if err := f.ExchangeCode(ctx, "code", state); err == nil {
+		t.Error("ExchangeCode() should reject a state that was already used")
+	}
+}
+
+func TestFitbitOAuth_ExchangeCode_RedisHitConsumesPostgresCopy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"a","refresh_token":"r","token_type":"Bearer","expires_in":28800}`))
+	}))
+	defer srv.Close()
+
+	mr := miniredis.RunT(t)
+	repo := &mocks.MockTokenRepository{
+		SaveFunc: func(_ context.Context, _ string, _, _ []byte, _ time.Time) error { return nil },
+	}
+	f, _ := newTestOAuth(t, srv, repo)
+	f.redis = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	pkce, states := newMemPKCEStates()
+	f.WithPKCEStateStore(pkce)
+
+	ctx := context.Background()
+	_, state, err := f.AuthorizationURL(ctx)
+	if err != nil {
+		t.Fatalf("AuthorizationURL() error = %v", err)
+	}
+	if err := f.ExchangeCode(ctx, "code", state); err != nil {
+		t.Fatalf("ExchangeCode() error = %v", err)
+	}
+	if _, ok := states[state]; ok {
+		t.Error("postgres state should be removed after a Redis hit")
+	}
+}
+
+func TestFitbitOAuth_AuthorizationURL_RedisDown(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	mr := miniredis.RunT(t)
+	f, _ := newTestOAuth(t, srv, &mocks.MockTokenRepository{})
+	f.redis = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	mr.Close()
+
+	ctx := context.Background()
+	if _, _, err := f.AuthorizationURL(ctx); err == nil {
+		t.Fatal("AuthorizationURL() should fail with Redis down and no fallback")
+	}
+
+	pkce, states := newMemPKCEStates()
+	f.WithPKCEStateStore(pkce)
+	_, state, err := f.AuthorizationURL(ctx)
+	if err != nil {
+		t.Fatalf("AuthorizationURL() error = %v", err)
+	}
+	if _, ok := states[state]; !ok {
+		t.Error("state should be saved to postgres when Redis is down")
+	}
+
+	pkce.SaveFunc = func(context.Context, string, string, time.Time) error { return errors.New("db down") }
+	if _, _, err := f.AuthorizationURL(ctx); err == nil {
+		t.Error("AuthorizationURL() should fail when both stores fail")
+	}
+}
@@ -26,6 +26,7 @@ type ActivityResponse struct {
 	Activities []struct {
 		LogID              int64   `json:"logId"`
 		ActivityName       string  `json:"activityName"`
+		Title              string  `json:"title"`
 		StartTime          string  `json:"startTime"`
 		Duration           int64   `json:"duration"`
 		Calories           int     `json:"calories"`
@@ -106,6 +107,42 @@ type HRVResponse struct {
 	} `json:"hrv"`
 }
 
+// HRVIntradayResponse represents /1/user/-/hrv/date/{date}/all.json
+type HRVIntradayResponse struct {
+	HRV []struct {
+		Minutes []HRVMinute `json:"minutes"`
+	} `json:"hrv"`
+}
+
+// HRVMinute is one 5-minute HRV segment. Minute is local time without offset.
+type HRVMinute struct {
+	Minute string `json:"minute"`
+	Value  struct {
+		RMSSD    float32 `json:"rmssd"`
+		Coverage float32 `json:"coverage"`
+	} `json:"value"`
+}
+
+// AZMIntradayResponse represents
+// /1/user/-/activities/active-zone-minutes/date/{date}/1d/1min.json
+type AZMIntradayResponse struct {
+	Intraday []struct {
+		Minutes []AZMMinute `json:"minutes"`
+	} `json:"activities-active-zone-minutes-intraday"`
+}
+
+// AZMMinute is one minute of active zone minutes. Minute is local time
+// without offset; a zone's field is non-zero when the minute was in it.
+type AZMMinute struct {
+	Minute string `json:"minute"`
+	Value  struct {
+		ActiveZoneMinutes int `json:"activeZoneMinutes"`
+		FatBurn           int `json:"fatBurnActiveZoneMinutes"`
+		Cardio            int `json:"cardioActiveZoneMinutes"`
+		Peak              int `json:"peakActiveZoneMinutes"`
+	} `json:"value"`
+}
+
 // SpO2Response represents /1/user/-/spo2/date/{date}.json
 type SpO2Response struct {
 	Value struct {
@@ -115,7 +152,10 @@ type SpO2Response struct {
 	} `json:"value"`
 }
 
-// BreathingRateResponse represents /1/user/-/br/date/{date}/all.json
+// BreathingRateResponse represents /1/user/-/br/date/{date}/all.json. Despite
+// being Fitbit's "intraday" breathing rate endpoint, it returns one average
+// per sleep stage for the main sleep, not a time series; FetchBreathingRate
+// already stores each of them on the daily summary.
 type BreathingRateResponse struct {
 	BR []struct {
 		Value struct {
@@ -145,6 +185,14 @@ type SkinTempResponse struct {
 	} `json:"tempSkin"`
 }
 
+// WaterLogResponse represents /1/user/-/foods/log/water/date/{date}.json.
+// Amounts are in mL when no Accept-Language header is sent.
+type WaterLogResponse struct {
+	Summary struct {
+		Water float64 `json:"water"`
+	} `json:"summary"`
+}
+
 // CardioScoreResponse represents /1/user/-/cardioscore/date/{date}.json
 type CardioScoreResponse struct {
 	CardioScore []struct {
@@ -153,3 +201,39 @@ type CardioScoreResponse struct {
 		} `json:"value"`
 	} `json:"cardioScore"`
 }
+
+// LifetimeResponse represents the lifetime section of /1/user/-/activities.json
+type LifetimeResponse struct {
+	Lifetime struct {
+		Total struct {
+			ActiveScore int     `json:"activeScore"`
+			CaloriesOut int     `json:"caloriesOut"`
+			Distance    float64 `json:"distance"`
+			Floors      int     `json:"floors"`
+			Steps       int     `json:"steps"`
+		} `json:"total"`
+	} `json:"lifetime"`
+}
+
+// BadgesResponse represents /1/user/-/badges.json
+type BadgesResponse struct {
+	Badges []struct {
+		Name     string `json:"name"`
+		DateTime string `json:"dateTime"`
+		Value    int    `json:"value"`
+	} `json:"badges"`
+}
+
+// SubscriptionResponse is a single apiSubscriptions entry.
+type SubscriptionResponse struct {
+	CollectionType string `json:"collectionType"`
+	OwnerID        string `json:"ownerId"`
+	OwnerType      string `json:"ownerType"`
+	SubscriberID   string `json:"subscriberId"`
+	SubscriptionID string `json:"subscriptionId"`
+}
+
+// SubscriptionsResponse represents /1/user/-/apiSubscriptions.json
+type SubscriptionsResponse struct {
+	APISubscriptions []SubscriptionResponse `json:"apiSubscriptions"`
+}
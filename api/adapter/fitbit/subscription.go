@@ -0,0 +1,64 @@
+package fitbit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"vitametron/api/domain/entity"
+)
+
+func (c *FitbitClient) FetchSubscriptions(ctx context.Context) ([]entity.FitbitSubscription, error) {
+	var resp SubscriptionsResponse
+	if err := c.doGet(ctx, "/1/user/-/apiSubscriptions.json", &resp); err != nil {
+		return nil, fmt.Errorf("fitbit: fetch subscriptions: %w", err)
+	}
+	subs := make([]entity.FitbitSubscription, 0, len(resp.APISubscriptions))
+	for i := range resp.APISubscriptions {
+		subs = append(subs, mapSubscription(&resp.APISubscriptions[i]))
+	}
+	return subs, nil
+}
+
+// CreateSubscription subscribes to every collection of the user's data.
+// endpoint is the subscriber ID configured in the Fitbit developer console;
+// an empty endpoint uses the default subscriber.
+func (c *FitbitClient) CreateSubscription(ctx context.Context, endpoint, subscriptionID string) (*entity.FitbitSubscription, error) {
+	var header http.Header
+	if endpoint != "" {
+		header = http.Header{"X-Fitbit-Subscriber-Id": {endpoint}}
+	}
+	var resp SubscriptionResponse
+	if err := c.do(ctx, http.MethodPost, subscriptionPath(subscriptionID), header, &resp); err != nil {
+		return nil, fmt.Errorf("fitbit: create subscription: %w", err)
+	}
+	sub := mapSubscription(&resp)
+	return &sub, nil
+}
+
+func (c *FitbitClient) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	if err := c.do(ctx, http.MethodDelete, subscriptionPath(subscriptionID), nil, nil); err != nil {
+		return fmt.Errorf("fitbit: delete subscription: %w", err)
+	}
+	return nil
+}
+
+// VerifyNotification checks the X-Fitbit-Signature header of a push: the
+// base64 HMAC-SHA1 of the raw body keyed with the client secret plus "&".
+func (c *FitbitClient) VerifyNotification(body []byte, signature string) bool {
+	want, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, []byte(c.oauth.config.ClientSecret+"&"))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), want)
+}
+
+func subscriptionPath(subscriptionID string) string {
+	return "/1/user/-/apiSubscriptions/" + url.PathEscape(subscriptionID) + ".json"
+}
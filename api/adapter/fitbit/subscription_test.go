@@ -0,0 +1,115 @@
+package fitbit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"vitametron/api/infrastructure/config"
+	"vitametron/api/mocks"
+)
+
+func newTestClient(t *testing.T, srv *httptest.Server) *FitbitClient {
+	t.Helper()
+	repo := &mocks.MockTokenRepository{}
+	oauth, enc := newTestOAuth(t, srv, repo)
+	encAccess, _ := enc.Encrypt([]byte("access"))
+	encRefresh, _ := enc.Encrypt([]byte("refresh"))
+	repo.GetFunc = func(_ context.Context, _ string) ([]byte, []byte, time.Time, error) {
+		return encAccess, encRefresh, time.Now().Add(time.Hour), nil
+	}
+	c := NewFitbitClient(oauth, config.ProfileConfig{})
+	c.baseURL = srv.URL
+	return c
+}
+
+func TestFitbitClient_Subscriptions(t *testing.T) {
+	var gotMethod, gotPath, gotSubscriber string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		gotSubscriber = r.Header.Get("X-Fitbit-Subscriber-Id")
+		if r.Header.Get("Authorization") != "Bearer access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"apiSubscriptions":[{"collectionType":"user","ownerId":"ABC","ownerType":"user","subscriberId":"1","subscriptionId":"vm-1"}]}`))
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"collectionType":"user","ownerId":"ABC","ownerType":"user","subscriberId":"2","subscriptionId":"vm-1"}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	subs, err := c.FetchSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("FetchSubscriptions() error = %v", err)
+	}
+	if len(subs) != 1 || subs[0].SubscriptionID != "vm-1" || subs[0].OwnerID != "ABC" {
+		t.Errorf("subscriptions = %+v", subs)
+	}
+	if gotPath != "/1/user/-/apiSubscriptions.json" {
+		t.Errorf("list path = %s", gotPath)
+	}
+
+	sub, err := c.CreateSubscription(ctx, "2", "vm-1")
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+	if gotMethod != http.MethodPost || gotPath != "/1/user/-/apiSubscriptions/vm-1.json" {
+		t.Errorf("create request = %s %s", gotMethod, gotPath)
+	}
+	if gotSubscriber != "2" || sub.SubscriberID != "2" {
+		t.Errorf("subscriber header = %q, response = %q", gotSubscriber, sub.SubscriberID)
+	}
+
+	if err := c.DeleteSubscription(ctx, "vm-1"); err != nil {
+		t.Fatalf("DeleteSubscription() error = %v", err)
+	}
+	if gotMethod != http.MethodDelete || gotPath != "/1/user/-/apiSubscriptions/vm-1.json" {
+		t.Errorf("delete request = %s %s", gotMethod, gotPath)
+	}
+}
+
+func TestFitbitClient_DeleteSubscription_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	if err := newTestClient(t, srv).DeleteSubscription(context.Background(), "missing"); err == nil {
+		t.Error("DeleteSubscription() should fail on 404")
+	}
+}
+
+func TestFitbitClient_VerifyNotification(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	c := newTestClient(t, srv)
+
+	body := []byte(`[{"collectionType":"activities","date":"2026-04-19"}]`)
+	mac := hmac.New(sha1.New, []byte("secret&"))
+	mac.Write(body)
+	valid := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if !c.VerifyNotification(body, valid) {
+		t.Error("valid signature rejected")
+	}
+	if c.VerifyNotification(append(body, ' '), valid) {
+		t.Error("signature accepted for a modified body")
+	}
+	if c.VerifyNotification(body, "not base64!") {
+		t.Error("malformed signature accepted")
+	}
+}
@@ -0,0 +1,91 @@
+// Package gpx serialises exercise routes as GPX 1.1 documents.
+package gpx
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+const (
+	namespace = "http://www.topografix.com/GPX/1/1"
+	creator   = "VitaMetron"
+	// ContentType is the media type of a GPX document.
+	ContentType = "application/gpx+xml"
+)
+
+type document struct {
+	XMLName  xml.Name `xml:"gpx"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Version  string   `xml:"version,attr"`
+	Creator  string   `xml:"creator,attr"`
+	Metadata metadata `xml:"metadata"`
+	Track    track    `xml:"trk"`
+}
+
+type metadata struct {
+	Name string `xml:"name,omitempty"`
+	Time string `xml:"time"`
+}
+
+type track struct {
+	Name    string  `xml:"name,omitempty"`
+	Type    string  `xml:"type,omitempty"`
+	Segment segment `xml:"trkseg"`
+}
+
+type segment struct {
+	Points []trackPoint `xml:"trkpt"`
+}
+
+type trackPoint struct {
+	Lat       float64  `xml:"lat,attr"`
+	Lon       float64  `xml:"lon,attr"`
+	Elevation *float64 `xml:"ele,omitempty"`
+	Time      string   `xml:"time"`
+}
+
+// Encode writes route as a GPX document with one track segment, named and
+// typed after the exercise it belongs to. Times are written in UTC as GPX
+// requires.
+func Encode(w io.Writer, exercise *entity.ExerciseLog, route *entity.ExerciseRoute) error {
+	doc := document{
+		Xmlns:   namespace,
+		Version: "1.1",
+		Creator: creator,
+		Metadata: metadata{
+			Name: exercise.ActivityName,
+			Time: formatTime(exercise.StartedAt),
+		},
+		Track: track{
+			Name: exercise.ActivityName,
+			Type: exercise.ActivityName,
+		},
+	}
+	doc.Track.Segment.Points = make([]trackPoint, len(route.Points))
+	for i, p := range route.Points {
+		doc.Track.Segment.Points[i] = trackPoint{
+			Lat:       p.Lat,
+			Lon:       p.Lng,
+			Elevation: p.Elevation,
+			Time:      formatTime(p.Time),
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
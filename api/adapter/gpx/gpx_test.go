@@ -0,0 +1,71 @@
+package gpx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+func TestEncode(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	start := time.Date(2025, 5, 3, 7, 0, 0, 0, jst)
+	ele := 12.5
+	exercise := &entity.ExerciseLog{ActivityName: "Running", StartedAt: start}
+	route := &entity.ExerciseRoute{
+		ExerciseExternalID: "hc-01",
+		Points: []entity.GPXPoint{
+			{Lat: 35.6812, Lng: 139.7671, Elevation: &ele, Time: start},
+			{Lat: 35.6815, Lng: 139.7675, Time: start.Add(5 * time.Second)},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, exercise, route); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Errorf("output does not start with the XML header: %q", out[:40])
+	}
+
+	var got struct {
+		XMLName xml.Name `xml:"gpx"`
+		Version string   `xml:"version,attr"`
+		Track   struct {
+			Name   string `xml:"name"`
+			Points []struct {
+				Lat  float64  `xml:"lat,attr"`
+				Lon  float64  `xml:"lon,attr"`
+				Ele  *float64 `xml:"ele"`
+				Time string   `xml:"time"`
+			} `xml:"trkseg>trkpt"`
+		} `xml:"trk"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	if got.XMLName.Space != namespace || got.Version != "1.1" {
+		t.Errorf("gpx namespace = %q, version = %q", got.XMLName.Space, got.Version)
+	}
+	if got.Track.Name != "Running" {
+		t.Errorf("track name = %q, want Running", got.Track.Name)
+	}
+	if len(got.Track.Points) != 2 {
+		t.Fatalf("len(points) = %d, want 2", len(got.Track.Points))
+	}
+	p0, p1 := got.Track.Points[0], got.Track.Points[1]
+	if p0.Lat != 35.6812 || p0.Lon != 139.7671 || p0.Ele == nil || *p0.Ele != 12.5 {
+		t.Errorf("points[0] = %+v", p0)
+	}
+	// Times are written in UTC.
+	if p0.Time != "2025-05-02T22:00:00Z" || p1.Time != "2025-05-02T22:00:05Z" {
+		t.Errorf("times = %q, %q", p0.Time, p1.Time)
+	}
+	if p1.Ele != nil {
+		t.Errorf("points[1].Ele = %v, want omitted", *p1.Ele)
+	}
+}
@@ -1,6 +1,7 @@
 package healthconnect
 
 import (
+	"context"
 	"database/sql"
 	"encoding/hex"
 	"fmt"
@@ -18,12 +19,35 @@ const (
 	appNothingX = 5
 )
 
+// DefaultQueryTimeout bounds opening the export and each query against it
+// unless SetQueryTimeout changes it.
+const DefaultQueryTimeout = 30 * time.Second
+
+var queryTimeout = DefaultQueryTimeout
+
+// SetQueryTimeout sets the timeout applied to opening an export and to each
+// query run against it.
+func SetQueryTimeout(d time.Duration) {
+	queryTimeout = d
+}
+
+const (
+	// mmapSize lets SQLite memory-map up to 256 MB of the export, which
+	// speeds up the full-table scans on large files.
+	mmapSize = 256 << 20
+	// exerciseDedupWindow is how far apart two apps' sessions of the same
+	// exercise type may start and still count as one workout.
+	exerciseDedupWindow = 5 * time.Minute
+)
+
 // ImportData holds all extracted and merged data from a Health Connect DB.
 type ImportData struct {
 	Summaries   []entity.DailySummary
 	HRSamples   []entity.HeartRateSample
 	SleepStages []entity.SleepStage
 	Exercises   []entity.ExerciseLog
+	Routes      []entity.ExerciseRoute
+	Devices     []entity.DeviceInfo
 }
 
 // Importer reads a Health Connect SQLite export and extracts biometric data.
@@ -31,7 +55,8 @@ type Importer struct{}
 
 // Extract opens the SQLite DB at dbPath and returns merged ImportData.
 func (imp *Importer) Extract(dbPath string) (*ImportData, error) {
-	db, err := sql.Open("sqlite", dbPath+"?mode=ro")
+	ctx := context.Background()
+	db, err := openExport(ctx, dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("open sqlite: %w", err)
 	}
@@ -39,33 +64,114 @@ func (imp *Importer) Extract(dbPath string) (*ImportData, error) {
 
 	data := &ImportData{}
 
-	summaries, err := imp.extractSummaries(db)
+	// Device info is only for debugging; an export without it still imports.
+	devices, err := imp.extractDeviceInfo(ctx, db)
+	if err != nil {
+		log.Printf("warn: device info query: %v", err)
+	}
+	for _, d := range devices {
+		log.Printf("info: HC device app_info_id=%d package=%s device=%s model=%s",
+			d.AppInfoID, d.PackageName, d.DeviceName, d.DeviceModel)
+	}
+	data.Devices = devices
+
+	summaries, err := imp.extractSummaries(ctx, db)
 	if err != nil {
 		return nil, fmt.Errorf("extract summaries: %w", err)
 	}
 	data.Summaries = summaries
 
-	hrSamples, err := imp.extractHR(db)
+	hrSamples, err := imp.extractHR(ctx, db)
 	if err != nil {
 		return nil, fmt.Errorf("extract HR: %w", err)
 	}
 	data.HRSamples = hrSamples
 
-	sleepStages, err := imp.extractSleep(db)
+	sleepStages, err := imp.extractSleep(ctx, db)
 	if err != nil {
 		return nil, fmt.Errorf("extract sleep: %w", err)
 	}
 	data.SleepStages = sleepStages
 
-	exercises, err := imp.extractExercises(db)
+	exercises, sessions, err := imp.extractExercises(ctx, db)
 	if err != nil {
 		return nil, fmt.Errorf("extract exercises: %w", err)
 	}
 	data.Exercises = exercises
 
+	// Routes are optional extras on an exercise, so a failed read only logs.
+	routes, err := imp.extractRoutes(ctx, db, sessions)
+	if err != nil {
+		log.Printf("warn: exercise route query: %v", err)
+	}
+	data.Routes = routes
+
 	return data, nil
 }
 
+// openExport opens the export read-only with mmap enabled on every pooled
+// connection, then runs a passive WAL checkpoint. The file: prefix is what
+// makes SQLite honour mode=ro. A failed checkpoint only logs, since a
+// read-only connection cannot fold a pending WAL back but still reads it.
+func openExport(ctx context.Context, dbPath string) (*sql.DB, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	dsn := fmt.Sprintf("file:%s?mode=ro&_pragma=mmap_size(%d)", dbPath, mmapSize)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := db.ExecContext(ctx, `PRAGMA wal_checkpoint(PASSIVE)`); err != nil {
+		log.Printf("warn: sqlite wal checkpoint: %v", err)
+	}
+	return db, nil
+}
+
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+// extractDeviceInfo lists the apps in the export together with the device
+// each one recorded from. Exports without device_info_table yield nothing.
+func (imp *Importer) extractDeviceInfo(ctx context.Context, db *sql.DB) ([]entity.DeviceInfo, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var name string
+	err := db.QueryRowContext(ctx, `SELECT name FROM sqlite_master WHERE type='table' AND name='device_info_table'`).Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT a.row_id, COALESCE(a.package_name, ''), COALESCE(d.manufacturer, ''), COALESCE(d.model, '')
+		FROM app_info_table a
+		JOIN device_info_table d ON d.app_info_id = a.row_id
+		ORDER BY a.row_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices []entity.DeviceInfo
+	for rows.Next() {
+		var d entity.DeviceInfo
+		if err := rows.Scan(&d.AppInfoID, &d.PackageName, &d.DeviceName, &d.DeviceModel); err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
 // priorityPick returns the Fitbit value if present, otherwise Nothing X.
 func priorityPick[T any](m map[int]T) (T, bool) {
 	if v, ok := m[appFitbit]; ok {
@@ -106,12 +212,14 @@ func plausiblePick[T any](m map[int]T, isPlausible func(T) bool) (T, bool) {
 
 // extractSummaries builds per-day DailySummary by querying each metric table
 // with app_info_id filtering and applying Fitbit > Nothing X priority.
-func (imp *Importer) extractSummaries(db *sql.DB) ([]entity.DailySummary, error) {
+// Each metric query gets its own timeout, since together they scan most of
+// the export.
+func (imp *Importer) extractSummaries(ctx context.Context, db *sql.DB) ([]entity.DailySummary, error) {
 	dates := make(map[string]*entity.DailySummary)
 	now := time.Now()
 
 	// Steps (Fitbit priority, plausibility check)
-	if err := imp.queryDailyInt(db, `
+	if err := imp.queryDailyInt(ctx, db, `
 		SELECT date(start_time/1000,'unixepoch','+9 hours') AS day, app_info_id, SUM(count)
 		FROM steps_record_table WHERE app_info_id IN (3,5)
 		GROUP BY day, app_info_id`, dates, func(s *entity.DailySummary, v int) { s.Steps = v },
@@ -121,7 +229,7 @@ func (imp *Importer) extractSummaries(db *sql.DB) ([]entity.DailySummary, error)
 	}
 
 	// Distance (Fitbit priority, meters → km, plausibility check on raw meters)
-	if err := imp.queryDailyFloat(db, `
+	if err := imp.queryDailyFloat(ctx, db, `
 		SELECT date(start_time/1000,'unixepoch','+9 hours') AS day, app_info_id, SUM(distance)
 		FROM distance_record_table WHERE app_info_id IN (3,5)
 		GROUP BY day, app_info_id`, dates, func(s *entity.DailySummary, v float64) { s.DistanceKM = float32(v / 1000) },
@@ -131,7 +239,7 @@ func (imp *Importer) extractSummaries(db *sql.DB) ([]entity.DailySummary, error)
 	}
 
 	// Calories (Fitbit priority, small cal → kcal, plausibility check on raw cal)
-	if err := imp.queryDailyFloat(db, `
+	if err := imp.queryDailyFloat(ctx, db, `
 		SELECT date(start_time/1000,'unixepoch','+9 hours') AS day, app_info_id, SUM(energy)
 		FROM total_calories_burned_record_table WHERE app_info_id IN (3,5)
 		GROUP BY day, app_info_id`, dates, func(s *entity.DailySummary, v float64) { s.CaloriesTotal = int(v / 1000) },
@@ -141,12 +249,12 @@ func (imp *Importer) extractSummaries(db *sql.DB) ([]entity.DailySummary, error)
 	}
 
 	// AvgHR / MaxHR from heart_rate_record series (Fitbit priority)
-	if err := imp.queryDailyHR(db, dates); err != nil {
+	if err := imp.queryDailyHR(ctx, db, dates); err != nil {
 		log.Printf("warn: avg/max HR query: %v", err)
 	}
 
 	// RestingHR (plausibility check)
-	if err := imp.queryDailyFloat(db, `
+	if err := imp.queryDailyFloat(ctx, db, `
 		SELECT date(time/1000,'unixepoch','+9 hours') AS day, app_info_id, AVG(beats_per_minute)
 		FROM resting_heart_rate_record_table WHERE app_info_id IN (3,5)
 		GROUP BY day, app_info_id`, dates, func(s *entity.DailySummary, v float64) { s.RestingHR = int(v) },
@@ -156,12 +264,12 @@ func (imp *Importer) extractSummaries(db *sql.DB) ([]entity.DailySummary, error)
 	}
 
 	// SpO2 (Nothing X only)
-	if err := imp.queryDailySpO2(db, dates); err != nil {
+	if err := imp.queryDailySpO2(ctx, db, dates); err != nil {
 		log.Printf("warn: SpO2 query: %v", err)
 	}
 
 	// HRV (plausibility check)
-	if err := imp.queryDailyFloat(db, `
+	if err := imp.queryDailyFloat(ctx, db, `
 		SELECT date(time/1000,'unixepoch','+9 hours') AS day, app_info_id, AVG(heart_rate_variability_millis)
 		FROM heart_rate_variability_rmssd_record_table WHERE app_info_id IN (3,5)
 		GROUP BY day, app_info_id`, dates, func(s *entity.DailySummary, v float64) { f := float32(v); s.HRVDailyRMSSD = &f },
@@ -171,7 +279,7 @@ func (imp *Importer) extractSummaries(db *sql.DB) ([]entity.DailySummary, error)
 	}
 
 	// SkinTemp (plausibility check) — join delta child table with parent record table
-	if err := imp.queryDailyFloat(db, `
+	if err := imp.queryDailyFloat(ctx, db, `
 		SELECT date(d.epoch_millis/1000,'unixepoch','+9 hours') AS day, s.app_info_id, AVG(d.delta)
 		FROM skin_temperature_delta_table d
 		JOIN skin_temperature_record_table s ON d.parent_key = s.row_id
@@ -182,15 +290,20 @@ func (imp *Importer) extractSummaries(db *sql.DB) ([]entity.DailySummary, error)
 		log.Printf("warn: skin temp query: %v", err)
 	}
 
+	// Respiratory rate (plausibility check)
+	if err := imp.extractRespiratoryRate(ctx, db, dates); err != nil {
+		log.Printf("warn: respiratory rate query: %v", err)
+	}
+
 	// Sleep summary (Fitbit priority) — uses sleep session records
-	if err := imp.queryDailySleep(db, dates); err != nil {
+	if err := imp.queryDailySleep(ctx, db, dates); err != nil {
 		log.Printf("warn: sleep summary query: %v", err)
 	}
 
 	// Build result slice
 	result := make([]entity.DailySummary, 0, len(dates))
 	for _, s := range dates {
-		s.Provider = "health_connect"
+		s.Providers = []string{"health_connect"}
 		s.SyncedAt = now
 		result = append(result, *s)
 	}
@@ -213,9 +326,12 @@ func (imp *Importer) ensureDate(dates map[string]*entity.DailySummary, day strin
 // queryDailyInt queries for day, app_info_id, int_value and applies priority merge.
 // If check is non-nil, plausiblePick is used instead of priorityPick so that an
 // implausible Fitbit value can be replaced by a plausible Nothing X value.
-func (imp *Importer) queryDailyInt(db *sql.DB, query string, dates map[string]*entity.DailySummary,
+func (imp *Importer) queryDailyInt(ctx context.Context, db *sql.DB, query string, dates map[string]*entity.DailySummary,
 	setter func(*entity.DailySummary, int), check func(int) bool) error {
-	rows, err := db.Query(query)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -255,9 +371,12 @@ func (imp *Importer) queryDailyInt(db *sql.DB, query string, dates map[string]*e
 // queryDailyFloat queries for day, app_info_id, float_value and applies priority merge.
 // If check is non-nil, plausiblePick is used instead of priorityPick so that an
 // implausible Fitbit value can be replaced by a plausible Nothing X value.
-func (imp *Importer) queryDailyFloat(db *sql.DB, query string, dates map[string]*entity.DailySummary,
+func (imp *Importer) queryDailyFloat(ctx context.Context, db *sql.DB, query string, dates map[string]*entity.DailySummary,
 	setter func(*entity.DailySummary, float64), check func(float64) bool) error {
-	rows, err := db.Query(query)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -295,12 +414,39 @@ func (imp *Importer) queryDailyFloat(db *sql.DB, query string, dates map[string]
 	return nil
 }
 
+// extractRespiratoryRate sets BRFullSleep to the per-day average breathing
+// rate with priority merge. Older exports lack respiratory_rate_record_table,
+// in which case nothing is extracted.
+func (imp *Importer) extractRespiratoryRate(ctx context.Context, db *sql.DB, dates map[string]*entity.DailySummary) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var name string
+	err := db.QueryRowContext(ctx, `SELECT name FROM sqlite_master WHERE type='table' AND name='respiratory_rate_record_table'`).Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return imp.queryDailyFloat(ctx, db, `
+		SELECT date(time/1000,'unixepoch','+9 hours') AS day, app_info_id, AVG(rate)
+		FROM respiratory_rate_record_table WHERE app_info_id IN (3,5)
+		GROUP BY day, app_info_id`, dates, func(s *entity.DailySummary, v float64) { f := float32(v); s.BRFullSleep = &f },
+		func(v float64) bool { return v >= float64(entity.BRMin) && v <= float64(entity.BRMax) },
+	)
+}
+
 // queryDailyHR extracts AVG and MAX heart rate per day with priority merge.
 // Schema: heart_rate_record_table (parent, has app_info_id) →
 //
 //	heart_rate_record_series_table (child, parent_key → row_id, has beats_per_minute + epoch_millis)
-func (imp *Importer) queryDailyHR(db *sql.DB, dates map[string]*entity.DailySummary) error {
-	rows, err := db.Query(`
+func (imp *Importer) queryDailyHR(ctx context.Context, db *sql.DB, dates map[string]*entity.DailySummary) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
 		SELECT date(h.start_time/1000,'unixepoch','+9 hours') AS day,
 		       h.app_info_id,
 		       AVG(s.beats_per_minute) AS avg_bpm,
@@ -349,8 +495,11 @@ func (imp *Importer) queryDailyHR(db *sql.DB, dates map[string]*entity.DailySumm
 }
 
 // queryDailySpO2 extracts AVG/MIN/MAX SpO2 per day with priority merge.
-func (imp *Importer) queryDailySpO2(db *sql.DB, dates map[string]*entity.DailySummary) error {
-	rows, err := db.Query(`
+func (imp *Importer) queryDailySpO2(ctx context.Context, db *sql.DB, dates map[string]*entity.DailySummary) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
 		SELECT date(time/1000,'unixepoch','+9 hours') AS day,
 		       app_info_id,
 		       AVG(percentage), MIN(percentage), MAX(percentage)
@@ -401,8 +550,11 @@ func (imp *Importer) queryDailySpO2(db *sql.DB, dates map[string]*entity.DailySu
 // queryDailySleep extracts sleep session summary per day with priority merge.
 // Picks the longest session per app per day, then Fitbit > Nothing X.
 // Schema: sleep_session_record_table has row_id (PK), sleep_stages_table uses parent_key → row_id.
-func (imp *Importer) queryDailySleep(db *sql.DB, dates map[string]*entity.DailySummary) error {
-	rows, err := db.Query(`
+func (imp *Importer) queryDailySleep(ctx context.Context, db *sql.DB, dates map[string]*entity.DailySummary) error {
+	sessionCtx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(sessionCtx, `
 		SELECT date(start_time/1000,'unixepoch','+9 hours') AS day,
 		       app_info_id, row_id,
 		       start_time, end_time,
@@ -459,11 +611,13 @@ func (imp *Importer) queryDailySleep(db *sql.DB, dates map[string]*entity.DailyS
 		s.SleepIsMain = true
 
 		// Query sleep stages for this session to compute stage totals
-		stageRows, err := db.Query(`
+		stageCtx, cancelStage := withQueryTimeout(ctx)
+		stageRows, err := db.QueryContext(stageCtx, `
 			SELECT stage_type, SUM(stage_end_time - stage_start_time) AS total_ms
 			FROM sleep_stages_table WHERE parent_key = ?
 			GROUP BY stage_type`, session.rowID)
 		if err != nil {
+			cancelStage()
 			log.Printf("warn: sleep stages for session %d: %v", session.rowID, err)
 			continue
 		}
@@ -493,6 +647,7 @@ func (imp *Importer) queryDailySleep(db *sql.DB, dates map[string]*entity.DailyS
 			}
 		}
 		stageRows.Close()
+		cancelStage()
 
 		s.SleepMinutesAsleep = int(totalAsleep / 60000)
 		s.SleepMinutesAwake = int(totalAwake / 60000)
@@ -505,8 +660,11 @@ func (imp *Importer) queryDailySleep(db *sql.DB, dates map[string]*entity.DailyS
 // Schema: heart_rate_record_table (parent, row_id, app_info_id) →
 //
 //	heart_rate_record_series_table (child, parent_key, beats_per_minute, epoch_millis)
-func (imp *Importer) extractHR(db *sql.DB) ([]entity.HeartRateSample, error) {
-	rows, err := db.Query(`
+func (imp *Importer) extractHR(ctx context.Context, db *sql.DB) ([]entity.HeartRateSample, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
 		SELECT h.app_info_id, s.epoch_millis, s.beats_per_minute
 		FROM heart_rate_record_series_table s
 		JOIN heart_rate_record_table h ON s.parent_key = h.row_id
@@ -570,9 +728,12 @@ func (imp *Importer) extractHR(db *sql.DB) ([]entity.HeartRateSample, error) {
 // selected session (matching the sessions chosen in extractSummaries).
 // Schema: sleep_stages_table uses parent_key → sleep_session_record_table.row_id,
 // columns: stage_start_time, stage_end_time, stage_type.
-func (imp *Importer) extractSleep(db *sql.DB) ([]entity.SleepStage, error) {
+func (imp *Importer) extractSleep(ctx context.Context, db *sql.DB) ([]entity.SleepStage, error) {
+	sessionCtx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	// Identify the best session row_id per day (Fitbit priority, longest session)
-	rows, err := db.Query(`
+	rows, err := db.QueryContext(sessionCtx, `
 		SELECT date(start_time/1000,'unixepoch','+9 hours') AS day,
 		       app_info_id, row_id,
 		       (end_time - start_time) AS duration_ms
@@ -609,11 +770,13 @@ func (imp *Importer) extractSleep(db *sql.DB) ([]entity.SleepStage, error) {
 
 	var stages []entity.SleepStage
 	for _, session := range bestSession {
-		stageRows, err := db.Query(`
+		stageCtx, cancelStage := withQueryTimeout(ctx)
+		stageRows, err := db.QueryContext(stageCtx, `
 			SELECT stage_start_time, stage_end_time, stage_type
 			FROM sleep_stages_table WHERE parent_key = ?
 			ORDER BY stage_start_time`, session.rowID)
 		if err != nil {
+			cancelStage()
 			log.Printf("warn: sleep stages query for row_id %d: %v", session.rowID, err)
 			continue
 		}
@@ -633,9 +796,11 @@ func (imp *Importer) extractSleep(db *sql.DB) ([]entity.SleepStage, error) {
 				Time:    EpochMillisToJST(startMS),
 				Stage:   stageName,
 				Seconds: int((endMS - startMS) / 1000),
+				Source:  entity.SleepStageSourceHealthConnect,
 			})
 		}
 		stageRows.Close()
+		cancelStage()
 	}
 
 	sort.Slice(stages, func(i, j int) bool {
@@ -646,39 +811,146 @@ func (imp *Importer) extractSleep(db *sql.DB) ([]entity.SleepStage, error) {
 
 // extractExercises reads exercise sessions from both Fitbit and Nothing X.
 // Uses hex-encoded uuid as ExternalID for deduplication via ON CONFLICT.
-func (imp *Importer) extractExercises(db *sql.DB) ([]entity.ExerciseLog, error) {
-	rows, err := db.Query(`
-		SELECT uuid, exercise_type, start_time, end_time, start_zone_offset
+// A workout both apps recorded is kept once; see deduplicateExercises.
+// The returned map links each kept session's row_id to its ExternalID.
+func (imp *Importer) extractExercises(ctx context.Context, db *sql.DB) ([]entity.ExerciseLog, map[int64]string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT row_id, uuid, app_info_id, exercise_type, start_time, end_time, start_zone_offset
 		FROM exercise_session_record_table
 		WHERE app_info_id IN (3,5)
 		ORDER BY start_time`)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	now := time.Now()
 	var exercises []entity.ExerciseLog
+	sessions := make(map[int64]string)
+	apps := make(map[string]int)
 
 	for rows.Next() {
+		var rowID int64
 		var uuidBytes []byte
-		var exerciseType, zoneOffset int
+		var appID, exerciseType, zoneOffset int
 		var startMS, endMS int64
-		if err := rows.Scan(&uuidBytes, &exerciseType, &startMS, &endMS, &zoneOffset); err != nil {
-			return nil, err
+		if err := rows.Scan(&rowID, &uuidBytes, &appID, &exerciseType, &startMS, &endMS, &zoneOffset); err != nil {
+			return nil, nil, err
 		}
 
-		externalID := hex.EncodeToString(uuidBytes)
+		externalID := fmt.Sprintf("hc-%s", hex.EncodeToString(uuidBytes))
 		startTime := EpochMillisToJST(startMS)
 		durationMS := endMS - startMS
 
 		exercises = append(exercises, entity.ExerciseLog{
-			ExternalID:   fmt.Sprintf("hc-%s", externalID),
+			ExternalID:   externalID,
 			ActivityName: MapExerciseType(exerciseType),
 			StartedAt:    startTime,
 			DurationMS:   durationMS,
 			SyncedAt:     now,
 		})
+		sessions[rowID] = externalID
+		apps[externalID] = appID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	exercises = deduplicateExercises(exercises, apps)
+	kept := make(map[string]bool, len(exercises))
+	for _, e := range exercises {
+		kept[e.ExternalID] = true
+	}
+	for rowID, externalID := range sessions {
+		if !kept[externalID] {
+			delete(sessions, rowID)
+		}
+	}
+	return exercises, sessions, nil
+}
+
+// deduplicateExercises drops the second app's copy of a workout recorded by
+// both Fitbit and Nothing X: sessions of the same activity from different
+// apps (apps maps ExternalID to app_info_id) starting within
+// exerciseDedupWindow of each other. The Fitbit session is kept; its
+// uuid-based ExternalID stays the key that makes re-imports idempotent.
+// Sessions from the same app are never merged. exercises must be ordered by
+// StartedAt.
+func deduplicateExercises(exercises []entity.ExerciseLog, apps map[string]int) []entity.ExerciseLog {
+	kept := make([]entity.ExerciseLog, 0, len(exercises))
+	for _, e := range exercises {
+		dup := -1
+		for i := len(kept) - 1; i >= 0; i-- {
+			if e.StartedAt.Sub(kept[i].StartedAt) > exerciseDedupWindow {
+				break
+			}
+			if kept[i].ActivityName == e.ActivityName && apps[kept[i].ExternalID] != apps[e.ExternalID] {
+				dup = i
+				break
+			}
+		}
+		switch {
+		case dup < 0:
+			kept = append(kept, e)
+		case apps[e.ExternalID] == appFitbit:
+			kept[dup] = e
+		}
+	}
+	return kept
+}
+
+// extractRoutes reads the GPS points of the sessions in exerciseIDs, keyed by
+// session row_id, into one route per session. Exports without
+// exercise_route_table yield nothing.
+func (imp *Importer) extractRoutes(ctx context.Context, db *sql.DB, exerciseIDs map[int64]string) ([]entity.ExerciseRoute, error) {
+	if len(exerciseIDs) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var name string
+	err := db.QueryRowContext(ctx, `SELECT name FROM sqlite_master WHERE type='table' AND name='exercise_route_table'`).Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT parent_key, time, latitude, longitude, altitude
+		FROM exercise_route_table
+		ORDER BY parent_key, time`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var routes []entity.ExerciseRoute
+	for rows.Next() {
+		var parentKey, timeMS int64
+		var lat, lng float64
+		var altitude sql.NullFloat64
+		if err := rows.Scan(&parentKey, &timeMS, &lat, &lng, &altitude); err != nil {
+			return nil, err
+		}
+		externalID, ok := exerciseIDs[parentKey]
+		if !ok {
+			continue
+		}
+		if len(routes) == 0 || routes[len(routes)-1].ExerciseExternalID != externalID {
+			routes = append(routes, entity.ExerciseRoute{ExerciseExternalID: externalID})
+		}
+		p := entity.GPXPoint{Lat: lat, Lng: lng, Time: EpochMillisToJST(timeMS)}
+		if altitude.Valid {
+			p.Elevation = &altitude.Float64
+		}
+		route := &routes[len(routes)-1]
+		route.Points = append(route.Points, p)
 	}
-	return exercises, rows.Err()
+	return routes, rows.Err()
 }
@@ -1,7 +1,15 @@
 package healthconnect
 
 import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
 )
 
 func TestPlausiblePick(t *testing.T) {
@@ -181,3 +189,289 @@ func TestPlausiblePickStruct(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractRespiratoryRate(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	imp := &Importer{}
+
+	t.Run("missing table", func(t *testing.T) {
+		dates := make(map[string]*entity.DailySummary)
+		if err := imp.extractRespiratoryRate(context.Background(), db, dates); err != nil {
+			t.Fatalf("extractRespiratoryRate() error = %v, want nil", err)
+		}
+		if len(dates) != 0 {
+			t.Errorf("len(dates) = %d, want 0", len(dates))
+		}
+	})
+
+	if _, err := db.Exec(`CREATE TABLE respiratory_rate_record_table (
+		row_id INTEGER PRIMARY KEY, app_info_id INTEGER, time INTEGER, rate REAL)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	// 2025-01-10 02:00 and 04:00 JST = 2025-01-09 17:00 and 19:00 UTC
+	day1a := time.Date(2025, 1, 9, 17, 0, 0, 0, time.UTC).UnixMilli()
+	day1b := time.Date(2025, 1, 9, 19, 0, 0, 0, time.UTC).UnixMilli()
+	// 2025-01-11 03:00 JST
+	day2 := time.Date(2025, 1, 10, 18, 0, 0, 0, time.UTC).UnixMilli()
+	if _, err := db.Exec(`INSERT INTO respiratory_rate_record_table (app_info_id, time, rate) VALUES
+		(3, ?, 14), (3, ?, 16), (5, ?, 20),
+		(3, ?, 99), (5, ?, 13), (1, ?, 15)`,
+		day1a, day1b, day1a, day2, day2, day2); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	t.Run("averages per day with priority", func(t *testing.T) {
+		dates := make(map[string]*entity.DailySummary)
+		if err := imp.extractRespiratoryRate(context.Background(), db, dates); err != nil {
+			t.Fatalf("extractRespiratoryRate() error = %v", err)
+		}
+
+		tests := []struct {
+			day  string
+			want float32
+		}{
+			{"2025-01-10", 15}, // Fitbit average preferred over Nothing X
+			{"2025-01-11", 13}, // implausible Fitbit value replaced by Nothing X
+		}
+		for _, tt := range tests {
+			s, ok := dates[tt.day]
+			if !ok || s.BRFullSleep == nil {
+				t.Fatalf("%s: BRFullSleep not set", tt.day)
+			}
+			if *s.BRFullSleep != tt.want {
+				t.Errorf("%s: BRFullSleep = %v, want %v", tt.day, *s.BRFullSleep, tt.want)
+			}
+		}
+	})
+}
+
+func TestExtractDeviceInfo(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	imp := &Importer{}
+
+	t.Run("missing table", func(t *testing.T) {
+		devices, err := imp.extractDeviceInfo(context.Background(), db)
+		if err != nil {
+			t.Fatalf("extractDeviceInfo() error = %v, want nil", err)
+		}
+		if len(devices) != 0 {
+			t.Errorf("len(devices) = %d, want 0", len(devices))
+		}
+	})
+
+	if _, err := db.Exec(`CREATE TABLE app_info_table (row_id INTEGER PRIMARY KEY, package_name TEXT)`); err != nil {
+		t.Fatalf("create app table: %v", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE device_info_table (
+		row_id INTEGER PRIMARY KEY, app_info_id INTEGER, manufacturer TEXT, model TEXT)`); err != nil {
+		t.Fatalf("create device table: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO app_info_table (row_id, package_name) VALUES
+		(3, 'com.fitbit.FitbitMobile'), (5, 'com.nothing.smartcenter'), (7, 'com.example.nodevice')`); err != nil {
+		t.Fatalf("insert apps: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO device_info_table (app_info_id, manufacturer, model) VALUES
+		(5, 'Nothing', 'CMF Watch Pro'), (3, 'Google', NULL)`); err != nil {
+		t.Fatalf("insert devices: %v", err)
+	}
+
+	devices, err := imp.extractDeviceInfo(context.Background(), db)
+	if err != nil {
+		t.Fatalf("extractDeviceInfo() error = %v", err)
+	}
+	want := []entity.DeviceInfo{
+		{AppInfoID: 3, PackageName: "com.fitbit.FitbitMobile", DeviceName: "Google"},
+		{AppInfoID: 5, PackageName: "com.nothing.smartcenter", DeviceName: "Nothing", DeviceModel: "CMF Watch Pro"},
+	}
+	if len(devices) != len(want) {
+		t.Fatalf("devices = %+v, want %+v", devices, want)
+	}
+	for i := range want {
+		if devices[i] != want[i] {
+			t.Errorf("devices[%d] = %+v, want %+v", i, devices[i], want[i])
+		}
+	}
+}
+
+func TestOpenExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "health_connect_export.db")
+	src, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, stmt := range []string{
+		`PRAGMA journal_mode=WAL`,
+		`CREATE TABLE steps_record_table (row_id INTEGER PRIMARY KEY, count INTEGER)`,
+		`INSERT INTO steps_record_table (count) VALUES (1200)`,
+	} {
+		if _, err := src.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	defer src.Close()
+
+	db, err := openExport(context.Background(), path)
+	if err != nil {
+		t.Fatalf("openExport() error = %v", err)
+	}
+	defer db.Close()
+
+	var mmap int64
+	if err := db.QueryRow(`PRAGMA mmap_size`).Scan(&mmap); err != nil {
+		t.Fatalf("read mmap_size: %v", err)
+	}
+	if mmap != mmapSize {
+		t.Errorf("mmap_size = %d, want %d", mmap, mmapSize)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT count FROM steps_record_table`).Scan(&count); err != nil {
+		t.Fatalf("read WAL row: %v", err)
+	}
+	if count != 1200 {
+		t.Errorf("count = %d, want 1200", count)
+	}
+	if _, err := db.Exec(`INSERT INTO steps_record_table (count) VALUES (1)`); err == nil {
+		t.Error("insert succeeded, want read-only connection")
+	}
+}
+
+// BenchmarkExtract runs a full extraction against the export named by
+// HC_BENCH_DB, e.g. HC_BENCH_DB=/tmp/health_connect_export.db. The query
+// timeout is lifted so slow machines measure speed rather than time out.
+func BenchmarkExtract(b *testing.B) {
+	path := os.Getenv("HC_BENCH_DB")
+	if path == "" {
+		b.Skip("HC_BENCH_DB not set")
+	}
+	SetQueryTimeout(time.Hour)
+	defer SetQueryTimeout(DefaultQueryTimeout)
+	imp := &Importer{}
+	for i := 0; i < b.N; i++ {
+		if _, err := imp.Extract(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestExtractRoutes(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	imp := &Importer{}
+	sessions := map[int64]string{1: "hc-aa", 2: "hc-bb"}
+
+	t.Run("missing table", func(t *testing.T) {
+		routes, err := imp.extractRoutes(context.Background(), db, sessions)
+		if err != nil {
+			t.Fatalf("extractRoutes() error = %v, want nil", err)
+		}
+		if len(routes) != 0 {
+			t.Errorf("len(routes) = %d, want 0", len(routes))
+		}
+	})
+
+	if _, err := db.Exec(`CREATE TABLE exercise_route_table (
+		row_id INTEGER PRIMARY KEY, parent_key INTEGER, time INTEGER,
+		latitude REAL, longitude REAL, altitude REAL, horizontal_accuracy REAL, vertical_accuracy REAL)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	// 2025-05-03 07:00 JST
+	start := time.Date(2025, 5, 2, 22, 0, 0, 0, time.UTC).UnixMilli()
+	if _, err := db.Exec(`INSERT INTO exercise_route_table (parent_key, time, latitude, longitude, altitude) VALUES
+		(1, ?, 35.6815, 139.7675, NULL), (1, ?, 35.6812, 139.7671, 12.5),
+		(2, ?, 34.7025, 135.4959, 3.0),
+		(9, ?, 0, 0, NULL)`,
+		start+5000, start, start, start); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	routes, err := imp.extractRoutes(context.Background(), db, sessions)
+	if err != nil {
+		t.Fatalf("extractRoutes() error = %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("len(routes) = %d, want 2 (unknown session skipped)", len(routes))
+	}
+	first := routes[0]
+	if first.ExerciseExternalID != "hc-aa" || len(first.Points) != 2 {
+		t.Fatalf("routes[0] = %+v", first)
+	}
+	// Points are ordered by time within a route.
+	if first.Points[0].Lat != 35.6812 || first.Points[0].Elevation == nil || *first.Points[0].Elevation != 12.5 {
+		t.Errorf("routes[0].Points[0] = %+v", first.Points[0])
+	}
+	if first.Points[1].Elevation != nil {
+		t.Errorf("routes[0].Points[1].Elevation = %v, want nil", *first.Points[1].Elevation)
+	}
+	if want := time.Date(2025, 5, 3, 7, 0, 0, 0, jst); !first.Points[0].Time.Equal(want) {
+		t.Errorf("routes[0].Points[0].Time = %v, want %v", first.Points[0].Time, want)
+	}
+	if routes[1].ExerciseExternalID != "hc-bb" || len(routes[1].Points) != 1 {
+		t.Errorf("routes[1] = %+v", routes[1])
+	}
+}
+
+func TestExtractExercises_DeduplicatesAcrossApps(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`CREATE TABLE exercise_session_record_table (
+		row_id INTEGER PRIMARY KEY, uuid BLOB, app_info_id INTEGER, exercise_type INTEGER,
+		start_time INTEGER, end_time INTEGER, start_zone_offset INTEGER)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	// 2025-05-03 07:00 JST
+	start := time.Date(2025, 5, 2, 22, 0, 0, 0, time.UTC).UnixMilli()
+	minute := time.Minute.Milliseconds()
+	const walking = 79
+	if _, err := db.Exec(`INSERT INTO exercise_session_record_table
+		(row_id, uuid, app_info_id, exercise_type, start_time, end_time, start_zone_offset) VALUES
+		(1, x'aa01', 5, ?, ?, ?, 32400),
+		(2, x'bb01', 3, ?, ?, ?, 32400),
+		(3, x'aa02', 5, ?, ?, ?, 32400),
+		(4, x'aa03', 5, ?, ?, ?, 32400)`,
+		walking, start, start+30*minute, // Nothing X copy of the morning walk
+		walking, start+2*minute, start+31*minute, // Fitbit copy, 2 minutes later
+		walking, start+20*minute, start+25*minute, // a separate Nothing X session
+		walking, start+120*minute, start+150*minute, // a later walk only Nothing X saw
+	); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	exercises, sessions, err := (&Importer{}).extractExercises(context.Background(), db)
+	if err != nil {
+		t.Fatalf("extractExercises() error = %v", err)
+	}
+	var ids []string
+	for _, e := range exercises {
+		ids = append(ids, e.ExternalID)
+	}
+	want := []string{"hc-bb01", "hc-aa02", "hc-aa03"}
+	if !slices.Equal(ids, want) {
+		t.Fatalf("ExternalIDs = %v, want %v (Fitbit copy kept, Nothing X copy dropped)", ids, want)
+	}
+	if _, ok := sessions[1]; ok || len(sessions) != 3 || sessions[2] != "hc-bb01" {
+		t.Errorf("sessions = %v, want the dropped session removed", sessions)
+	}
+}
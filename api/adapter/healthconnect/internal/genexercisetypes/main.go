@@ -0,0 +1,132 @@
+// Command genexercisetypes writes exercise_types_gen.go from the
+// EXERCISE_SESSION_TYPE_* constants in Android's
+// android.health.connect.datatypes.ExerciseSessionType.
+//
+// Usage (via go generate in adapter/healthconnect):
+//
+//	go run ./internal/genexercisetypes -o exercise_types_gen.go
+//	go run ./internal/genexercisetypes -in ExerciseSessionType.java -o exercise_types_gen.go
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sourceURL serves the enum base64-encoded (?format=TEXT).
+const sourceURL = "https://android.googlesource.com/platform/packages/modules/HealthFitness/+/refs/heads/main/framework/java/android/health/connect/datatypes/ExerciseSessionType.java?format=TEXT"
+
+var constRe = regexp.MustCompile(`public static final int EXERCISE_SESSION_TYPE_([A-Z0-9_]+)\s*=\s*(\d+);`)
+
+// displayNames overrides the title-cased constant name where the plain
+// conversion reads poorly.
+var displayNames = map[string]string{
+	"BIKING_STATIONARY":                "Biking (Stationary)",
+	"FOOTBALL_AMERICAN":                "Football (American)",
+	"FOOTBALL_AUSTRALIAN":              "Football (Australian)",
+	"FRISBEE_DISC":                     "Frisbee",
+	"HIGH_INTENSITY_INTERVAL_TRAINING": "HIIT",
+	"ROWING_MACHINE":                   "Rowing (Machine)",
+	"RUNNING_TREADMILL":                "Running (Treadmill)",
+	"STAIR_CLIMBING_MACHINE":           "Stair Climbing (Machine)",
+	"SWIMMING_OPEN_WATER":              "Swimming (Open Water)",
+	"SWIMMING_POOL":                    "Swimming (Pool)",
+}
+
+// skipped constants fall through to MapExerciseType's "Other".
+var skipped = map[string]bool{
+	"UNKNOWN":       true,
+	"OTHER_WORKOUT": true,
+}
+
+func main() {
+	in := flag.String("in", "", "read the Java source from this file instead of downloading it")
+	out := flag.String("o", "exercise_types_gen.go", "output file")
+	flag.Parse()
+
+	src, err := readSource(*in)
+	if err != nil {
+		log.Fatalf("read source: %v", err)
+	}
+
+	types := map[int]string{}
+	for _, m := range constRe.FindAllStringSubmatch(string(src), -1) {
+		if skipped[m[1]] {
+			continue
+		}
+		code, err := strconv.Atoi(m[2])
+		if err != nil {
+			log.Fatalf("parse code for %s: %v", m[1], err)
+		}
+		types[code] = displayName(m[1])
+	}
+	if len(types) == 0 {
+		log.Fatal("no EXERCISE_SESSION_TYPE_* constants found")
+	}
+
+	code, err := render(types)
+	if err != nil {
+		log.Fatalf("render: %v", err)
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		log.Fatalf("write %s: %v", *out, err)
+	}
+}
+
+func readSource(path string) ([]byte, error) {
+	if path != "" {
+		return os.ReadFile(path)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: status %d", sourceURL, resp.StatusCode)
+	}
+	return io.ReadAll(base64.NewDecoder(base64.StdEncoding, resp.Body))
+}
+
+func displayName(constant string) string {
+	if name, ok := displayNames[constant]; ok {
+		return name
+	}
+	words := strings.Split(strings.ToLower(constant), "_")
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func render(types map[int]string) ([]byte, error) {
+	codes := make([]int, 0, len(types))
+	for c := range types {
+		codes = append(codes, c)
+	}
+	sort.Ints(codes)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by genexercisetypes from android.health.connect.datatypes.ExerciseSessionType. DO NOT EDIT.\n\n")
+	b.WriteString("package healthconnect\n\n")
+	b.WriteString("// exerciseTypeNames maps Health Connect exercise session type codes to activity names.\n")
+	b.WriteString("var exerciseTypeNames = map[int]string{\n")
+	for _, c := range codes {
+		fmt.Fprintf(&b, "\t%d: %q,\n", c, types[c])
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
@@ -20,104 +20,15 @@ func MapSleepStage(stageType int) string {
 	}
 }
 
-// MapExerciseType converts Health Connect exercise type int to activity name.
+//go:generate go run ./internal/genexercisetypes -o exercise_types_gen.go
+
+// MapExerciseType converts a Health Connect exercise session type code to an
+// activity name. Unknown codes, including OTHER_WORKOUT, map to "Other".
 func MapExerciseType(exerciseType int) string {
-	switch exerciseType {
-	case 2:
-		return "Badminton"
-	case 4:
-		return "Basketball"
-	case 5:
-		return "Biking"
-	case 8:
-		return "Boot Camp"
-	case 10:
-		return "Boxing"
-	case 14:
-		return "Calisthenics"
-	case 16:
-		return "Cricket"
-	case 24:
-		return "Elliptical"
-	case 26:
-		return "Fencing"
-	case 29:
-		return "Football (American)"
-	case 31:
-		return "Golf"
-	case 32:
-		return "Guided Breathing"
-	case 33:
-		return "Gymnastics"
-	case 34:
-		return "Handball"
-	case 35:
-		return "HIIT"
-	case 36:
-		return "Hiking"
-	case 37:
-		return "Ice Hockey"
-	case 38:
-		return "Ice Skating"
-	case 43:
-		return "Martial Arts"
-	case 46:
-		return "Pilates"
-	case 48:
-		return "Racquetball"
-	case 49:
-		return "Running"
-	case 50:
-		return "Running (Treadmill)"
-	case 51:
-		return "Rowing"
-	case 52:
-		return "Rugby"
-	case 53:
-		return "Walking"
-	case 54:
-		return "Sailing"
-	case 56:
-		return "Skating"
-	case 57:
-		return "Skiing"
-	case 58:
-		return "Snowboarding"
-	case 59:
-		return "Snowshoeing"
-	case 60:
-		return "Soccer"
-	case 61:
-		return "Softball"
-	case 62:
-		return "Squash"
-	case 63:
-		return "Stair Climbing"
-	case 64:
-		return "Stair Climbing (Machine)"
-	case 65:
-		return "Strength Training"
-	case 67:
-		return "Surfing"
-	case 68:
-		return "Swimming (Open Water)"
-	case 69:
-		return "Swimming (Pool)"
-	case 70:
-		return "Table Tennis"
-	case 71:
-		return "Tennis"
-	case 73:
-		return "Volleyball"
-	case 75:
-		return "Weightlifting"
-	case 76:
-		return "Wheelchair"
-	case 78:
-		return "Yoga"
-	default:
-		return "Other"
+	if name, ok := exerciseTypeNames[exerciseType]; ok {
+		return name
 	}
+	return "Other"
 }
 
 var jst = time.FixedZone("JST", 9*3600)
@@ -39,3 +39,38 @@ func TestLocalDate(t *testing.T) {
 		t.Errorf("expected midnight, got %02d:%02d", got.Hour(), got.Minute())
 	}
 }
+
+func TestMapExerciseType(t *testing.T) {
+	tests := []struct {
+		code int
+		want string
+	}{
+		{8, "Biking"},
+		{9, "Biking (Stationary)"},
+		{36, "HIIT"},
+		{56, "Running"},
+		{73, "Swimming (Open Water)"},
+		{74, "Swimming (Pool)"},
+		{79, "Walking"},
+		{83, "Yoga"},
+		{0, "Other"},
+		{1, "Other"},
+	}
+	for _, tt := range tests {
+		if got := MapExerciseType(tt.code); got != tt.want {
+			t.Errorf("MapExerciseType(%d) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+
+	// Every generated code must resolve to its own name, not the fallback.
+	seen := map[string]int{}
+	for code, name := range exerciseTypeNames {
+		if got := MapExerciseType(code); got != name || got == "" || got == "Other" {
+			t.Errorf("MapExerciseType(%d) = %q, want generated name %q", code, got, name)
+		}
+		if prev, ok := seen[name]; ok {
+			t.Errorf("codes %d and %d both map to %q", prev, code, name)
+		}
+		seen[name] = code
+	}
+}
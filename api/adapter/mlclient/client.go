@@ -1,23 +1,39 @@
 package mlclient
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"time"
 
 	"vitametron/api/domain/entity"
 )
 
+// pingTimeout bounds the health check so an unreachable ML service does not
+// hold up startup.
+const pingTimeout = 5 * time.Second
+
 type Client struct {
-	baseURL       string
-	httpClient    *http.Client
-	trainClient   *http.Client
+	baseURL             string
+	httpClient          *http.Client
+	trainClient         *http.Client
+	anomalyModelVersion string
 }
 
-func New(baseURL string) *Client {
+// New returns a client for the ML service at baseURL, which must be an
+// absolute http or https URL. Reachability is not checked; see Ping.
+func New(baseURL string) (*Client, error) {
+	u, err := neturl.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("mlclient: invalid base URL %q: %w", baseURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("mlclient: base URL %q must be an absolute http or https URL", baseURL)
+	}
 	return &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
@@ -26,7 +42,42 @@ func New(baseURL string) *Client {
 		trainClient: &http.Client{
 			Timeout: 30 * time.Minute,
 		},
+	}, nil
+}
+
+// BaseURL returns the ML service URL the client was created with.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Ping calls GET /health on the ML service and fails unless it answers 200
+// within pingTimeout.
+func (c *Client) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/health", nil)
+	if err != nil {
+		return err
 	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ml service returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// WithAnomalyModelVersion pins anomaly detection requests to the given model
+// version. An empty version leaves the choice to the ML service.
+func (c *Client) WithAnomalyModelVersion(version string) *Client {
+	c.anomalyModelVersion = version
+	return c
 }
 
 type predictionResponse struct {
@@ -130,14 +181,14 @@ func (c *Client) GetVRIRange(ctx context.Context, from, to time.Time) ([]entity.
 
 	scores := make([]entity.VRIScore, len(vrs))
 	for i, vr := range vrs {
-		scores[i] = *vriResponseToEntity(vr, from)
+		scores[i] = *vriResponseToEntity(vr, from.AddDate(0, 0, i))
 	}
 	return scores, nil
 }
 
 func vriResponseToEntity(vr vriResponse, fallbackDate time.Time) *entity.VRIScore {
 	s := &entity.VRIScore{
-		Date:                fallbackDate,
+		Date:                responseDate(vr.Date, fallbackDate),
 		VRIScore:            float32(vr.VRIScore),
 		VRIConfidence:       float32(vr.VRIConfidence),
 		SRIDaysUsed:         vr.SRIDaysUsed,
@@ -384,10 +435,19 @@ type anomalyResponse struct {
 	ModelVersion         string                `json:"model_version"`
 }
 
+// responseDate parses a "YYYY-MM-DD" date from an ML response in the same
+// location as fallback, returning fallback if the field is empty or malformed.
+func responseDate(s string, fallback time.Time) time.Time {
+	if d, err := time.ParseInLocation("2006-01-02", s, fallback.Location()); err == nil {
+		return d
+	}
+	return fallback
+}
+
 func anomalyResponseToEntity(ar anomalyResponse, fallbackDate time.Time) *entity.AnomalyDetection {
 	driversJSON, _ := json.Marshal(ar.TopDrivers)
 	return &entity.AnomalyDetection{
-		Date:                 fallbackDate,
+		Date:                 responseDate(ar.Date, fallbackDate),
 		AnomalyScore:         float32(ar.AnomalyScore),
 		NormalizedScore:      float32(ar.NormalizedScore),
 		IsAnomaly:            ar.IsAnomaly,
@@ -401,8 +461,17 @@ func anomalyResponseToEntity(ar anomalyResponse, fallbackDate time.Time) *entity
 	}
 }
 
+// anomalyModelVersionParam returns the model_version query suffix when a
+// version is pinned, or an empty string otherwise.
+func (c *Client) anomalyModelVersionParam() string {
+	if c.anomalyModelVersion == "" {
+		return ""
+	}
+	return "&model_version=" + neturl.QueryEscape(c.anomalyModelVersion)
+}
+
 func (c *Client) DetectAnomaly(ctx context.Context, date time.Time) (*entity.AnomalyDetection, error) {
-	url := fmt.Sprintf("%s/anomaly/detect?date=%s", c.baseURL, date.Format("2006-01-02"))
+	url := fmt.Sprintf("%s/anomaly/detect?date=%s%s", c.baseURL, date.Format("2006-01-02"), c.anomalyModelVersionParam())
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -427,7 +496,7 @@ func (c *Client) DetectAnomaly(ctx context.Context, date time.Time) (*entity.Ano
 }
 
 func (c *Client) DetectAnomalyRange(ctx context.Context, from, to time.Time) ([]entity.AnomalyDetection, error) {
-	url := fmt.Sprintf("%s/anomaly/range?start=%s&end=%s", c.baseURL, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	url := fmt.Sprintf("%s/anomaly/range?start=%s&end=%s%s", c.baseURL, from.Format("2006-01-02"), to.Format("2006-01-02"), c.anomalyModelVersionParam())
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -452,7 +521,7 @@ func (c *Client) DetectAnomalyRange(ctx context.Context, from, to time.Time) ([]
 
 	results := make([]entity.AnomalyDetection, len(rangeResp.Detections))
 	for i, ar := range rangeResp.Detections {
-		results[i] = *anomalyResponseToEntity(ar, from)
+		results[i] = *anomalyResponseToEntity(ar, from.AddDate(0, 0, i))
 	}
 	return results, nil
 }
@@ -466,12 +535,25 @@ type anomalyTrainResponseML struct {
 	Message          string   `json:"message"`
 }
 
-func (c *Client) TrainAnomalyModel(ctx context.Context) (*entity.AnomalyTrainResult, error) {
+// TrainAnomalyModel trains a new anomaly model. A zero contamination leaves
+// the ML service's default in place.
+func (c *Client) TrainAnomalyModel(ctx context.Context, contamination float64) (*entity.AnomalyTrainResult, error) {
 	url := fmt.Sprintf("%s/anomaly/train", c.baseURL)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	var body io.Reader
+	if contamination > 0 {
+		b, err := json.Marshal(map[string]float64{"contamination": contamination})
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
 	if err != nil {
 		return nil, err
 	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	resp, err := c.trainClient.Do(req)
 	if err != nil {
@@ -594,6 +676,103 @@ func (c *Client) PredictHRV(ctx context.Context, date time.Time) (*entity.HRVPre
 	}, nil
 }
 
+type sleepPredictionResponse struct {
+	Date                 string  `json:"date"`
+	TargetDate           string  `json:"target_date"`
+	PredictedEfficiency  float32 `json:"predicted_efficiency"`
+	PredictedDurationMin float32 `json:"predicted_duration_min"`
+	Confidence           float32 `json:"confidence"`
+	ModelVersion         string  `json:"model_version"`
+}
+
+// PredictSleepQuality forecasts the coming night's sleep from the
+// biometrics of date.
+func (c *Client) PredictSleepQuality(ctx context.Context, date time.Time) (*entity.SleepQualityPrediction, error) {
+	url := fmt.Sprintf("%s/sleep/predict?date=%s", c.baseURL, date.Format("2006-01-02"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ml service returned %d", resp.StatusCode)
+	}
+
+	var sr sleepPredictionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil {
+		return nil, err
+	}
+
+	targetDate, err := time.Parse("2006-01-02", sr.TargetDate)
+	if err != nil {
+		targetDate = date.AddDate(0, 0, 1)
+	}
+
+	return &entity.SleepQualityPrediction{
+		Date:                 date,
+		TargetDate:           targetDate,
+		PredictedEfficiency:  sr.PredictedEfficiency,
+		PredictedDurationMin: sr.PredictedDurationMin,
+		Confidence:           sr.Confidence,
+		ModelVersion:         sr.ModelVersion,
+	}, nil
+}
+
+// predictHRVRangeResponse is the batch shape of /hrv/predict/range, one
+// prediction per forecast day in target date order.
+type predictHRVRangeResponse struct {
+	Predictions []hrvPredictionResponse `json:"predictions"`
+}
+
+// PredictHRVRange forecasts HRV for the days following startDate, one
+// prediction per day. Missing dates in the response fall back to startDate
+// and the i-th day after it.
+func (c *Client) PredictHRVRange(ctx context.Context, startDate time.Time, days int) ([]entity.HRVPrediction, error) {
+	url := fmt.Sprintf("%s/hrv/predict/range?date=%s&days=%d", c.baseURL, startDate.Format("2006-01-02"), days)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ml service returned %d", resp.StatusCode)
+	}
+
+	var rangeResp predictHRVRangeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rangeResp); err != nil {
+		return nil, err
+	}
+
+	computedAt := time.Now()
+	predictions := make([]entity.HRVPrediction, len(rangeResp.Predictions))
+	for i, hr := range rangeResp.Predictions {
+		driversJSON, _ := json.Marshal(hr.TopDrivers)
+		predictions[i] = entity.HRVPrediction{
+			Date:               responseDate(hr.Date, startDate),
+			TargetDate:         responseDate(hr.TargetDate, startDate.AddDate(0, 0, i+1)),
+			PredictedZScore:    hr.PredictedHRVZScore,
+			PredictedDirection: hr.PredictedDirection,
+			Confidence:         hr.Confidence,
+			TopDrivers:         driversJSON,
+			ModelVersion:       hr.ModelVersion,
+			ComputedAt:         computedAt,
+		}
+	}
+	return predictions, nil
+}
+
 type hrvTrainResponse struct {
 	ModelVersion          string            `json:"model_version"`
 	TrainingDaysUsed      int               `json:"training_days_used"`
@@ -755,7 +934,7 @@ type divergenceResponse struct {
 func divergenceResponseToEntity(dr divergenceResponse, fallbackDate time.Time) *entity.DivergenceDetection {
 	driversJSON, _ := json.Marshal(dr.TopDrivers)
 	return &entity.DivergenceDetection{
-		Date:           fallbackDate,
+		Date:           responseDate(dr.Date, fallbackDate),
 		ActualScore:    float32(dr.ActualScore),
 		PredictedScore: float32(dr.PredictedScore),
 		Residual:       float32(dr.Residual),
@@ -822,7 +1001,7 @@ func (c *Client) GetDivergenceRange(ctx context.Context, from, to time.Time) ([]
 
 	results := make([]entity.DivergenceDetection, len(rangeResp.Detections))
 	for i, dr := range rangeResp.Detections {
-		results[i] = *divergenceResponseToEntity(dr, from)
+		results[i] = *divergenceResponseToEntity(dr, from.AddDate(0, 0, i))
 	}
 	return results, nil
 }
@@ -3,6 +3,7 @@ package mlclient
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -24,7 +25,7 @@ func TestClient_PredictCondition(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := New(ts.URL)
+	client := newTestClient(t, ts.URL)
 	date := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
 	pred, err := client.PredictCondition(context.Background(), date)
 	if err != nil {
@@ -48,7 +49,7 @@ func TestClient_DetectRisk(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := New(ts.URL)
+	client := newTestClient(t, ts.URL)
 	date := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
 	risks, err := client.DetectRisk(context.Background(), date)
 	if err != nil {
@@ -65,9 +66,286 @@ func TestClient_PredictCondition_ServerError(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := New(ts.URL)
+	client := newTestClient(t, ts.URL)
 	_, err := client.PredictCondition(context.Background(), time.Now())
 	if err == nil {
 		t.Fatal("expected error for 500 response")
 	}
 }
+
+func TestClient_DetectAnomaly_ModelVersionPinned(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{"unpinned", "", ""},
+		{"pinned", "if-2026.01", "if-2026.01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("model_version"); got != tt.want {
+					t.Errorf("model_version = %q, want %q", got, tt.want)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]any{"model_version": "if-2026.01"})
+			}))
+			defer ts.Close()
+
+			client := newTestClient(t, ts.URL).WithAnomalyModelVersion(tt.version)
+			date := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+			d, err := client.DetectAnomaly(context.Background(), date)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d.ModelVersion != "if-2026.01" {
+				t.Errorf("ModelVersion = %q, want if-2026.01", d.ModelVersion)
+			}
+		})
+	}
+}
+
+func TestClient_DetectAnomalyRange_UsesResponseDates(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"detections":[
+			{"date":"2025-06-10","anomaly_score":0.1},
+			{"date":"2025-06-12","anomaly_score":0.2},
+			{"date":"2025-06-15","anomaly_score":0.3}
+		]}`))
+	}))
+	defer ts.Close()
+
+	client := newTestClient(t, ts.URL)
+	from := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	got, err := client.DetectAnomalyRange(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"2025-06-10", "2025-06-12", "2025-06-15"}
+	if len(got) != len(want) {
+		t.Fatalf("len = %d, want %d", len(got), len(want))
+	}
+	for i, d := range got {
+		if d.Date.Format("2006-01-02") != want[i] {
+			t.Errorf("detections[%d].Date = %s, want %s", i, d.Date.Format("2006-01-02"), want[i])
+		}
+	}
+}
+
+func TestClient_DetectAnomalyRange_FallbackDates(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"detections":[{"date":""},{"date":"not-a-date"}]}`))
+	}))
+	defer ts.Close()
+
+	client := newTestClient(t, ts.URL)
+	from := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	got, err := client.DetectAnomalyRange(context.Background(), from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got[0].Date.Format("2006-01-02") != "2025-06-10" || got[1].Date.Format("2006-01-02") != "2025-06-11" {
+		t.Errorf("dates = %s, %s, want 2025-06-10, 2025-06-11",
+			got[0].Date.Format("2006-01-02"), got[1].Date.Format("2006-01-02"))
+	}
+}
+
+func TestClient_RangeEndpoints_UseResponseDates(t *testing.T) {
+	from := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	want := []string{"2025-06-10", "2025-06-13", "2025-06-15"}
+
+	tests := []struct {
+		name  string
+		body  string
+		fetch func(c *Client) ([]time.Time, error)
+	}{
+		{
+			name: "anomaly",
+			body: `{"detections":[{"date":"2025-06-10"},{"date":"2025-06-13"},{"date":"2025-06-15"}]}`,
+			fetch: func(c *Client) ([]time.Time, error) {
+				ds, err := c.DetectAnomalyRange(context.Background(), from, to)
+				dates := make([]time.Time, len(ds))
+				for i, d := range ds {
+					dates[i] = d.Date
+				}
+				return dates, err
+			},
+		},
+		{
+			name: "vri",
+			body: `[{"date":"2025-06-10"},{"date":"2025-06-13"},{"date":"2025-06-15"}]`,
+			fetch: func(c *Client) ([]time.Time, error) {
+				ss, err := c.GetVRIRange(context.Background(), from, to)
+				dates := make([]time.Time, len(ss))
+				for i, s := range ss {
+					dates[i] = s.Date
+				}
+				return dates, err
+			},
+		},
+		{
+			name: "divergence",
+			body: `{"detections":[{"date":"2025-06-10"},{"date":"2025-06-13"},{"date":"2025-06-15"}]}`,
+			fetch: func(c *Client) ([]time.Time, error) {
+				ds, err := c.GetDivergenceRange(context.Background(), from, to)
+				dates := make([]time.Time, len(ds))
+				for i, d := range ds {
+					dates[i] = d.Date
+				}
+				return dates, err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			got, err := tt.fetch(newTestClient(t, ts.URL))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("len = %d, want %d", len(got), len(want))
+			}
+			for i, d := range got {
+				if d.Format("2006-01-02") != want[i] {
+					t.Errorf("[%d].Date = %s, want %s", i, d.Format("2006-01-02"), want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestClient_TrainAnomalyModel_Contamination(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model_version":"v1","contamination":0.02}`))
+	}))
+	defer ts.Close()
+
+	client := newTestClient(t, ts.URL)
+	if _, err := client.TrainAnomalyModel(context.Background(), 0); err != nil {
+		t.Fatalf("TrainAnomalyModel(0) error = %v", err)
+	}
+	if _, err := client.TrainAnomalyModel(context.Background(), 0.05); err != nil {
+		t.Fatalf("TrainAnomalyModel(0.05) error = %v", err)
+	}
+	if bodies[0] != "" {
+		t.Errorf("default body = %q, want empty", bodies[0])
+	}
+	if bodies[1] != `{"contamination":0.05}` {
+		t.Errorf("body = %q, want contamination 0.05", bodies[1])
+	}
+}
+
+func newTestClient(t *testing.T, url string) *Client {
+	t.Helper()
+	c, err := New(url)
+	if err != nil {
+		t.Fatalf("New(%q) error = %v", url, err)
+	}
+	return c
+}
+
+func TestNew_ValidatesBaseURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"http://ml:8000", false},
+		{"https://ml.example.com", false},
+		{"", true},
+		{"ml:8000", true},
+		{"/relative/path", true},
+		{"ftp://ml:8000", true},
+		{"http://%zz", true},
+	}
+	for _, tt := range tests {
+		c, err := New(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("New(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if err == nil && c.BaseURL() != tt.url {
+			t.Errorf("BaseURL() = %q, want %q", c.BaseURL(), tt.url)
+		}
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("path = %q, want /health", r.URL.Path)
+		}
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	client := newTestClient(t, ts.URL)
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v, want nil", err)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := client.Ping(context.Background()); err == nil {
+		t.Error("Ping() error = nil, want error on 503")
+	}
+
+	ts.Close()
+	if err := client.Ping(context.Background()); err == nil {
+		t.Error("Ping() error = nil, want error when unreachable")
+	}
+}
+
+func TestClient_PredictSleepQuality(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sleep/predict" {
+			t.Errorf("path = %q, want /sleep/predict", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("date"); got != "2025-06-15" {
+			t.Errorf("date = %q, want 2025-06-15", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"date":                   "2025-06-15",
+			"target_date":            "2025-06-16",
+			"predicted_efficiency":   88.5,
+			"predicted_duration_min": 412,
+			"confidence":             0.7,
+			"model_version":          "sleep-v1",
+		})
+	}))
+	defer ts.Close()
+
+	client := newTestClient(t, ts.URL)
+	date := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	pred, err := client.PredictSleepQuality(context.Background(), date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !pred.TargetDate.Equal(time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("TargetDate = %v, want 2025-06-16", pred.TargetDate)
+	}
+	if pred.PredictedEfficiency != 88.5 || pred.PredictedDurationMin != 412 {
+		t.Errorf("prediction = %+v, want efficiency 88.5 and duration 412", pred)
+	}
+	if pred.ModelVersion != "sleep-v1" {
+		t.Errorf("ModelVersion = %q, want sleep-v1", pred.ModelVersion)
+	}
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"vitametron/api/domain/entity"
+)
+
+type ActiveZoneSampleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewActiveZoneSampleRepo(pool *pgxpool.Pool) *ActiveZoneSampleRepo {
+	return &ActiveZoneSampleRepo{pool: pool}
+}
+
+func (r *ActiveZoneSampleRepo) BulkUpsert(ctx context.Context, samples []entity.ActiveZoneSample) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, s := range samples {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO active_zone_intraday (time, fat_burn_min, cardio_min, peak_min)
+			 VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (time) DO UPDATE SET fat_burn_min=$2, cardio_min=$3, peak_min=$4`,
+			s.Time, s.FatBurnMin, s.CardioMin, s.PeakMin)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *ActiveZoneSampleRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.ActiveZoneSample, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT time, fat_burn_min, cardio_min, peak_min FROM active_zone_intraday
+		 WHERE time BETWEEN $1 AND $2 ORDER BY time`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []entity.ActiveZoneSample
+	for rows.Next() {
+		var s entity.ActiveZoneSample
+		if err := rows.Scan(&s.Time, &s.FatBurnMin, &s.CardioMin, &s.PeakMin); err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
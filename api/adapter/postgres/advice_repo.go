@@ -19,6 +19,9 @@ func NewAdviceRepo(pool *pgxpool.Pool) *AdviceRepo {
 }
 
 func (r *AdviceRepo) GetByDate(ctx context.Context, date time.Time) (*entity.DailyAdvice, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	row := r.pool.QueryRow(ctx,
 		`SELECT date, advice_text, model_name, generation_ms, generated_at
 		 FROM daily_advice WHERE date = $1`, date)
@@ -19,6 +19,9 @@ func NewAnomalyRepo(pool *pgxpool.Pool) *AnomalyRepo {
 }
 
 func (r *AnomalyRepo) GetByDate(ctx context.Context, date time.Time) (*entity.AnomalyDetection, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	row := r.pool.QueryRow(ctx,
 		`SELECT date, anomaly_score, normalized_score, is_anomaly,
 			quality_gate, quality_confidence, quality_adjusted_score,
@@ -40,6 +43,9 @@ func (r *AnomalyRepo) GetByDate(ctx context.Context, date time.Time) (*entity.An
 }
 
 func (r *AnomalyRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.AnomalyDetection, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
 		`SELECT date, anomaly_score, normalized_score, is_anomaly,
 			quality_gate, quality_confidence, quality_adjusted_score,
@@ -63,3 +69,15 @@ func (r *AnomalyRepo) ListRange(ctx context.Context, from, to time.Time) ([]enti
 	}
 	return detections, rows.Err()
 }
+
+func (r *AnomalyRepo) DeleteOtherModelVersions(ctx context.Context, modelVersion string) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM anomaly_detections WHERE model_version IS DISTINCT FROM $1`, modelVersion)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
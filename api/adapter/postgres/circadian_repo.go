@@ -19,6 +19,9 @@ func NewCircadianRepo(pool *pgxpool.Pool) *CircadianRepo {
 }
 
 func (r *CircadianRepo) GetByDate(ctx context.Context, date time.Time) (*entity.CircadianScore, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	row := r.pool.QueryRow(ctx,
 		`SELECT date, chs_score, chs_confidence,
 			cosinor_mesor, cosinor_amplitude, cosinor_acrophase_hour,
@@ -50,6 +53,9 @@ func (r *CircadianRepo) GetByDate(ctx context.Context, date time.Time) (*entity.
 }
 
 func (r *CircadianRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.CircadianScore, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
 		`SELECT date, chs_score, chs_confidence,
 			cosinor_mesor, cosinor_amplitude, cosinor_acrophase_hour,
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"vitametron/api/domain/entity"
+)
+
+type ConditionAnnotationRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewConditionAnnotationRepo(pool *pgxpool.Pool) *ConditionAnnotationRepo {
+	return &ConditionAnnotationRepo{pool: pool}
+}
+
+func (r *ConditionAnnotationRepo) Create(ctx context.Context, a *entity.ConditionAnnotation) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	return r.pool.QueryRow(ctx,
+		`INSERT INTO condition_annotations (condition_log_id, label, note)
+		 VALUES ($1, $2, $3)
+		 RETURNING id, created_at`,
+		a.ConditionLogID, a.Label, a.Note,
+	).Scan(&a.ID, &a.CreatedAt)
+}
+
+func (r *ConditionAnnotationRepo) ListByLogID(ctx context.Context, logID int64) ([]entity.ConditionAnnotation, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, condition_log_id, label, note, created_at
+		 FROM condition_annotations WHERE condition_log_id = $1
+		 ORDER BY created_at, id`, logID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var annotations []entity.ConditionAnnotation
+	for rows.Next() {
+		var a entity.ConditionAnnotation
+		if err := rows.Scan(&a.ID, &a.ConditionLogID, &a.Label, &a.Note, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations, rows.Err()
+}
+
+func (r *ConditionAnnotationRepo) Delete(ctx context.Context, logID, id int64) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM condition_annotations WHERE id = $1 AND condition_log_id = $2`, id, logID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"vitametron/api/domain/entity"
+)
+
+func TestConditionAnnotationRepo_CreateListDelete(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewConditionAnnotationRepo(pool)
+	ctx := context.Background()
+
+	// Log IDs far above any real identity value keep the test rows apart.
+	const logID, otherLogID = 9_000_000_001, 9_000_000_002
+	t.Cleanup(func() {
+		pool.Exec(ctx, `DELETE FROM condition_annotations WHERE condition_log_id IN ($1, $2)`, logID, otherLogID)
+	})
+
+	first := &entity.ConditionAnnotation{ConditionLogID: logID, Label: "pre-intervention"}
+	second := &entity.ConditionAnnotation{ConditionLogID: logID, Label: "on-medication", Note: "10mg"}
+	other := &entity.ConditionAnnotation{ConditionLogID: otherLogID, Label: "washout"}
+	for _, a := range []*entity.ConditionAnnotation{first, second, other} {
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if a.ID == 0 || a.CreatedAt.IsZero() {
+			t.Fatalf("Create() did not populate ID/CreatedAt: %+v", a)
+		}
+	}
+
+	got, err := repo.ListByLogID(ctx, logID)
+	if err != nil {
+		t.Fatalf("ListByLogID() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != first.ID || got[1].Note != "10mg" {
+		t.Fatalf("ListByLogID() = %+v", got)
+	}
+
+	if ok, err := repo.Delete(ctx, otherLogID, first.ID); err != nil || ok {
+		t.Errorf("Delete(other log) = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := repo.Delete(ctx, logID, first.ID); err != nil || !ok {
+		t.Errorf("Delete() = %v, %v; want true, nil", ok, err)
+	}
+	if got, _ := repo.ListByLogID(ctx, logID); len(got) != 1 {
+		t.Errorf("after Delete() len = %d, want 1", len(got))
+	}
+}
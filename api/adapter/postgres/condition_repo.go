@@ -3,8 +3,10 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"vitametron/api/domain/entity"
@@ -19,23 +21,29 @@ func NewConditionRepo(pool *pgxpool.Pool) *ConditionRepo {
 }
 
 func (r *ConditionRepo) Create(ctx context.Context, log *entity.ConditionLog) error {
-	_, err := r.pool.Exec(ctx,
-		`INSERT INTO condition_logs (logged_at, overall, mental, physical, energy, overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas, note, tags)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	return r.pool.QueryRow(ctx,
+		`INSERT INTO condition_logs (logged_at, overall, mental, physical, energy, overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas, note, tags, source, is_outlier)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		 RETURNING id, created_at`,
 		log.LoggedAt, log.Overall, log.Mental, log.Physical, log.Energy,
 		log.OverallVAS, log.MoodVAS, log.EnergyVAS, log.SleepQualityVAS, log.StressVAS,
-		log.Note, log.Tags)
-	return err
+		log.Note, log.Tags, log.Source, log.IsOutlier).Scan(&log.ID, &log.CreatedAt)
 }
 
 func (r *ConditionRepo) GetByID(ctx context.Context, id int64) (*entity.ConditionLog, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	var l entity.ConditionLog
 	err := r.pool.QueryRow(ctx,
-		`SELECT id, logged_at, overall, mental, physical, energy, overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas, note, tags, created_at
-		 FROM condition_logs WHERE id = $1`, id).
+		`SELECT id, logged_at, overall, mental, physical, energy, overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas, note, tags, source, is_outlier, created_at
+		 FROM condition_logs WHERE id = $1 AND deleted_at IS NULL`, id).
 		Scan(&l.ID, &l.LoggedAt, &l.Overall, &l.Mental, &l.Physical,
 			&l.Energy, &l.OverallVAS, &l.MoodVAS, &l.EnergyVAS, &l.SleepQualityVAS, &l.StressVAS,
-			&l.Note, &l.Tags, &l.CreatedAt)
+			&l.Note, &l.Tags, &l.Source, &l.IsOutlier, &l.CreatedAt)
 	if err != nil {
 		if err.Error() == "no rows in result set" {
 			return nil, nil
@@ -49,28 +57,25 @@ func (r *ConditionRepo) GetByID(ctx context.Context, id int64) (*entity.Conditio
 }
 
 func (r *ConditionRepo) List(ctx context.Context, filter entity.ConditionFilter) (*entity.ConditionListResult, error) {
-	query := `SELECT id, logged_at, overall, mental, physical, energy, overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas, note, tags, created_at, COUNT(*) OVER() AS total FROM condition_logs`
-	var args []interface{}
-	argIdx := 1
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	where := ""
-	if !filter.From.IsZero() && !filter.To.IsZero() {
-		where += fmt.Sprintf(" logged_at BETWEEN $%d AND $%d", argIdx, argIdx+1)
-		args = append(args, filter.From, filter.To)
-		argIdx += 2
+	table := "condition_logs"
+	if filter.Archived {
+		table = "condition_logs_archive"
 	}
-	if filter.Tag != "" {
-		if where != "" {
-			where += " AND"
+	where, args := conditionListWhere(filter)
+
+	if filter.CountOnly {
+		total, err := r.count(ctx, table, where, args)
+		if err != nil {
+			return nil, err
 		}
-		where += fmt.Sprintf(" tags @> ARRAY[$%d]::text[]", argIdx)
-		args = append(args, filter.Tag)
-		argIdx++
-	}
-	if where != "" {
-		query += " WHERE" + where
+		return &entity.ConditionListResult{Items: []entity.ConditionLog{}, Total: total}, nil
 	}
 
+	query := `SELECT id, logged_at, overall, mental, physical, energy, overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas, note, tags, source, is_outlier, created_at, deleted_at, COUNT(*) OVER() AS total FROM ` + table + where
+
 	sortField := "logged_at"
 	if filter.SortField == "overall" || filter.SortField == "overall_vas" || filter.SortField == "created_at" {
 		sortField = filter.SortField
@@ -81,10 +86,9 @@ func (r *ConditionRepo) List(ctx context.Context, filter entity.ConditionFilter)
 	}
 	query += fmt.Sprintf(" ORDER BY %s %s", sortField, sortDir)
 
+	argIdx := len(args) + 1
 	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
-	args = append(args, filter.Limit, filter.Offset)
-
-	rows, err := r.pool.Query(ctx, query, args...)
+	rows, err := r.pool.Query(ctx, query, append(args, filter.Limit, filter.Offset)...)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +100,7 @@ func (r *ConditionRepo) List(ctx context.Context, filter entity.ConditionFilter)
 		var l entity.ConditionLog
 		if err := rows.Scan(&l.ID, &l.LoggedAt, &l.Overall, &l.Mental, &l.Physical,
 			&l.Energy, &l.OverallVAS, &l.MoodVAS, &l.EnergyVAS, &l.SleepQualityVAS, &l.StressVAS,
-			&l.Note, &l.Tags, &l.CreatedAt, &total); err != nil {
+			&l.Note, &l.Tags, &l.Source, &l.IsOutlier, &l.CreatedAt, &l.DeletedAt, &total); err != nil {
 			return nil, err
 		}
 		if l.Tags == nil {
@@ -108,27 +112,164 @@ func (r *ConditionRepo) List(ctx context.Context, filter entity.ConditionFilter)
 		return nil, err
 	}
 
+	// The window count rides on the returned rows, so a page past the end
+	// has no row to read it from.
+	if len(logs) == 0 && filter.Offset > 0 {
+		if total, err = r.count(ctx, table, where, args); err != nil {
+			return nil, err
+		}
+	}
+
 	return &entity.ConditionListResult{Items: logs, Total: total}, nil
 }
 
+// conditionListWhere builds the WHERE clause (with a leading space, or empty)
+// and its arguments, numbered from $1, for List.
+func conditionListWhere(filter entity.ConditionFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if !filter.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if filter.ExcludeOutliers {
+		conds = append(conds, "NOT is_outlier")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() {
+		conds = append(conds, fmt.Sprintf("logged_at BETWEEN $%d AND $%d", len(args)+1, len(args)+2))
+		args = append(args, filter.From, filter.To)
+	}
+	if filter.Tag != "" {
+		conds = append(conds, fmt.Sprintf("tags @> ARRAY[$%d]::text[]", len(args)+1))
+		args = append(args, filter.Tag)
+	}
+	if len(filter.Tags) > 0 {
+		op := "@>"
+		if filter.TagOperator == entity.TagOperatorOr {
+			op = "&&"
+		}
+		conds = append(conds, fmt.Sprintf("tags %s $%d::text[]", op, len(args)+1))
+		args = append(args, filter.Tags)
+	}
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func (r *ConditionRepo) count(ctx context.Context, table, where string, args []interface{}) (int, error) {
+	var total int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM `+table+where, args...).Scan(&total)
+	return total, err
+}
+
 func (r *ConditionRepo) Update(ctx context.Context, log *entity.ConditionLog) error {
-	_, err := r.pool.Exec(ctx,
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var old entity.ConditionLog
+	err = tx.QueryRow(ctx,
+		`SELECT id, logged_at, overall, mental, physical, energy, overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas, note, tags, source, is_outlier, created_at
+		 FROM condition_logs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, log.ID).
+		Scan(&old.ID, &old.LoggedAt, &old.Overall, &old.Mental, &old.Physical,
+			&old.Energy, &old.OverallVAS, &old.MoodVAS, &old.EnergyVAS, &old.SleepQualityVAS, &old.StressVAS,
+			&old.Note, &old.Tags, &old.Source, &old.IsOutlier, &old.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load condition log for history: %w", err)
+	}
+
+	// Update does not touch source, is_outlier or created_at, so the new
+	// snapshot keeps them.
+	updated := *log
+	updated.Source = old.Source
+	updated.IsOutlier = old.IsOutlier
+	updated.CreatedAt = old.CreatedAt
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO condition_log_history (condition_log_id, old_values, new_values)
+		 VALUES ($1, $2, $3)`, log.ID, old, updated); err != nil {
+		return fmt.Errorf("insert condition log history: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
 		`UPDATE condition_logs SET overall=$2, mental=$3, physical=$4, energy=$5, overall_vas=$6, mood_vas=$7, energy_vas=$8, sleep_quality_vas=$9, stress_vas=$10, note=$11, tags=$12, logged_at=$13
 		 WHERE id=$1`,
 		log.ID, log.Overall, log.Mental, log.Physical, log.Energy,
 		log.OverallVAS, log.MoodVAS, log.EnergyVAS, log.SleepQualityVAS, log.StressVAS,
-		log.Note, log.Tags, log.LoggedAt)
-	return err
+		log.Note, log.Tags, log.LoggedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ConditionRepo) GetHistory(ctx context.Context, id int64) ([]entity.ConditionLogHistory, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, condition_log_id, changed_at, old_values, new_values
+		 FROM condition_log_history WHERE condition_log_id = $1
+		 ORDER BY changed_at, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []entity.ConditionLogHistory
+	for rows.Next() {
+		var h entity.ConditionLogHistory
+		if err := rows.Scan(&h.ID, &h.ConditionLogID, &h.ChangedAt, &h.OldValues, &h.NewValues); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
 }
 
 func (r *ConditionRepo) Delete(ctx context.Context, id int64) error {
-	_, err := r.pool.Exec(ctx, `DELETE FROM condition_logs WHERE id = $1`, id)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx, `UPDATE condition_logs SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
 	return err
 }
 
+func (r *ConditionRepo) Restore(ctx context.Context, id int64) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx, `UPDATE condition_logs SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *ConditionRepo) DeletePermanent(ctx context.Context, id int64) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM condition_logs WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
 func (r *ConditionRepo) GetTags(ctx context.Context) ([]entity.TagCount, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
-		`SELECT unnest(tags) AS tag, COUNT(*) AS count FROM condition_logs GROUP BY tag ORDER BY count DESC`)
+		`SELECT unnest(tags) AS tag, COUNT(*) AS count FROM condition_logs WHERE deleted_at IS NULL GROUP BY tag ORDER BY count DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -145,20 +286,77 @@ func (r *ConditionRepo) GetTags(ctx context.Context) ([]entity.TagCount, error)
 	return tags, rows.Err()
 }
 
-func (r *ConditionRepo) GetSummary(ctx context.Context, from, to time.Time) (*entity.ConditionSummary, error) {
+func (r *ConditionRepo) CountBySource(ctx context.Context, from, to time.Time) ([]entity.ConditionSourceCount, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT source, COUNT(*) FROM condition_logs
+		 WHERE logged_at BETWEEN $1 AND $2 AND deleted_at IS NULL
+		 GROUP BY source ORDER BY source`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []entity.ConditionSourceCount
+	for rows.Next() {
+		var sc entity.ConditionSourceCount
+		if err := rows.Scan(&sc.Source, &sc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, sc)
+	}
+	return counts, rows.Err()
+}
+
+func (r *ConditionRepo) RenameTag(ctx context.Context, oldTag, newTag string) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	// If a log already carries newTag, drop oldTag instead of duplicating newTag.
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE condition_logs
+		 SET tags = CASE WHEN $2 = ANY(tags) THEN array_remove(tags, $1) ELSE array_replace(tags, $1, $2) END
+		 WHERE $1 = ANY(tags)`, oldTag, newTag)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// summaryColumns are the condition_logs columns GetSummary reports
+// avg/min/max for, in ConditionSummary field order.
+var summaryColumns = []string{
+	"overall", "mental", "physical", "energy",
+	"overall_vas", "mood_vas", "energy_vas", "sleep_quality_vas", "stress_vas",
+}
+
+func (r *ConditionRepo) GetSummary(ctx context.Context, filter entity.ConditionFilter) (*entity.ConditionSummary, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	// Time weighting ranks logs within each JST day; a day's weights sum to
+	// its log count, so only the balance inside multi-log days changes.
+	source := "condition_logs WHERE logged_at BETWEEN $1 AND $2 AND deleted_at IS NULL"
+	avg := func(col string) string { return "AVG(" + col + ")" }
+	if filter.WeightingMode == entity.WeightingTimeWeighted {
+		source = `(SELECT *, 2.0 * ROW_NUMBER() OVER (PARTITION BY day ORDER BY logged_at, id) / (COUNT(*) OVER (PARTITION BY day) + 1) AS weight
+		   FROM (SELECT *, (logged_at AT TIME ZONE 'Asia/Tokyo')::date AS day
+		         FROM condition_logs WHERE logged_at BETWEEN $1 AND $2 AND deleted_at IS NULL) d) w`
+		avg = func(col string) string {
+			return fmt.Sprintf("SUM(weight * %[1]s) / NULLIF(SUM(weight) FILTER (WHERE %[1]s IS NOT NULL), 0)", col)
+		}
+	}
+
+	cols := make([]string, 0, len(summaryColumns))
+	for _, col := range summaryColumns {
+		cols = append(cols, fmt.Sprintf("COALESCE(%s, 0), COALESCE(MIN(%[2]s), 0), COALESCE(MAX(%[2]s), 0)", avg(col), col))
+	}
+	query := "SELECT COUNT(*),\n\t\t" + strings.Join(cols, ",\n\t\t") + "\n FROM " + source
+
 	var s entity.ConditionSummary
-	err := r.pool.QueryRow(ctx,
-		`SELECT COUNT(*),
-		        COALESCE(AVG(overall), 0), COALESCE(MIN(overall), 0), COALESCE(MAX(overall), 0),
-		        COALESCE(AVG(mental), 0), COALESCE(MIN(mental), 0), COALESCE(MAX(mental), 0),
-		        COALESCE(AVG(physical), 0), COALESCE(MIN(physical), 0), COALESCE(MAX(physical), 0),
-		        COALESCE(AVG(energy), 0), COALESCE(MIN(energy), 0), COALESCE(MAX(energy), 0),
-		        COALESCE(AVG(overall_vas), 0), COALESCE(MIN(overall_vas), 0), COALESCE(MAX(overall_vas), 0),
-		        COALESCE(AVG(mood_vas), 0), COALESCE(MIN(mood_vas), 0), COALESCE(MAX(mood_vas), 0),
-		        COALESCE(AVG(energy_vas), 0), COALESCE(MIN(energy_vas), 0), COALESCE(MAX(energy_vas), 0),
-		        COALESCE(AVG(sleep_quality_vas), 0), COALESCE(MIN(sleep_quality_vas), 0), COALESCE(MAX(sleep_quality_vas), 0),
-		        COALESCE(AVG(stress_vas), 0), COALESCE(MIN(stress_vas), 0), COALESCE(MAX(stress_vas), 0)
-		 FROM condition_logs WHERE logged_at BETWEEN $1 AND $2`, from, to).
+	err := r.pool.QueryRow(ctx, query, filter.From, filter.To).
 		Scan(&s.TotalCount,
 			&s.OverallAvg, &s.OverallMin, &s.OverallMax,
 			&s.MentalAvg, &s.MentalMin, &s.MentalMax,
@@ -174,3 +372,83 @@ func (r *ConditionRepo) GetSummary(ctx context.Context, from, to time.Time) (*en
 	}
 	return &s, nil
 }
+
+func (r *ConditionRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.ConditionLog, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, logged_at, overall, mental, physical, energy, overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas, note, tags, source, is_outlier, created_at
+		 FROM condition_logs WHERE logged_at BETWEEN $1 AND $2 AND deleted_at IS NULL ORDER BY logged_at`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []entity.ConditionLog
+	for rows.Next() {
+		var l entity.ConditionLog
+		if err := rows.Scan(&l.ID, &l.LoggedAt, &l.Overall, &l.Mental, &l.Physical,
+			&l.Energy, &l.OverallVAS, &l.MoodVAS, &l.EnergyVAS, &l.SleepQualityVAS, &l.StressVAS,
+			&l.Note, &l.Tags, &l.Source, &l.IsOutlier, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		if l.Tags == nil {
+			l.Tags = []string{}
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
+
+func (r *ConditionRepo) GetVASSeries(ctx context.Context, from, to time.Time) ([]entity.VASPoint, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT logged_at, overall_vas FROM condition_logs
+		 WHERE logged_at BETWEEN $1 AND $2 AND deleted_at IS NULL ORDER BY logged_at`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []entity.VASPoint
+	for rows.Next() {
+		var p entity.VASPoint
+		if err := rows.Scan(&p.LoggedAt, &p.OverallVAS); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+func (r *ConditionRepo) Archive(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`WITH moved AS (
+		     DELETE FROM condition_logs WHERE logged_at < $1
+		     RETURNING id, logged_at, overall, mental, physical, energy, note, tags, created_at,
+		               overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas, source, deleted_at, is_outlier
+		 )
+		 INSERT INTO condition_logs_archive (id, logged_at, overall, mental, physical, energy, note, tags, created_at,
+		                                     overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas, source, deleted_at, is_outlier)
+		 SELECT * FROM moved`, before)
+	if err != nil {
+		return 0, fmt.Errorf("archive condition logs: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
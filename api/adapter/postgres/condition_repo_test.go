@@ -0,0 +1,409 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/infrastructure/database"
+)
+
+// newTestPool connects to TEST_DATABASE_URL (a TimescaleDB instance) and
+// applies migrations. Tests using it are skipped when the variable is unset.
+func newTestPool(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	if err := database.RunMigrations(dsn); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	pool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestConditionRepo_CreatedAtRoundTrip(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewConditionRepo(pool)
+	ctx := context.Background()
+
+	log := &entity.ConditionLog{Overall: 3, OverallVAS: 50, LoggedAt: time.Now(), Tags: []string{}}
+	if err := repo.Create(ctx, log); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	t.Cleanup(func() { repo.DeletePermanent(ctx, log.ID) })
+
+	if log.ID == 0 {
+		t.Fatal("Create() did not populate ID")
+	}
+	if d := time.Since(log.CreatedAt); d < -time.Minute || d > time.Minute {
+		t.Errorf("Create() CreatedAt = %v, want close to now", log.CreatedAt)
+	}
+
+	got, err := repo.GetByID(ctx, log.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetByID() = %v, %v", got, err)
+	}
+	if !got.CreatedAt.Equal(log.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, log.CreatedAt)
+	}
+	// A DATE column would truncate to midnight; the time of day must survive.
+	now := time.Now().In(got.CreatedAt.Location())
+	if got.CreatedAt.Hour() != now.Hour() && got.CreatedAt.Hour() != now.Add(-time.Minute).Hour() {
+		t.Errorf("CreatedAt.Hour() = %d, want %d", got.CreatedAt.Hour(), now.Hour())
+	}
+}
+
+func TestConditionRepo_ListByTags(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewConditionRepo(pool)
+	ctx := context.Background()
+
+	base := time.Date(2001, 2, 3, 12, 0, 0, 0, time.UTC)
+	logs := map[string]*entity.ConditionLog{
+		"both":     {Overall: 3, OverallVAS: 50, LoggedAt: base, Tags: []string{"headache", "tired"}},
+		"headache": {Overall: 3, OverallVAS: 50, LoggedAt: base.Add(time.Hour), Tags: []string{"headache"}},
+		"other":    {Overall: 3, OverallVAS: 50, LoggedAt: base.Add(2 * time.Hour), Tags: []string{"caffeine"}},
+	}
+	byID := map[int64]string{}
+	for name, l := range logs {
+		if err := repo.Create(ctx, l); err != nil {
+			t.Fatalf("Create(%s) error = %v", name, err)
+		}
+		byID[l.ID] = name
+		id := l.ID
+		t.Cleanup(func() { repo.DeletePermanent(ctx, id) })
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		op   string
+		want []string
+	}{
+		{"and overlapping", []string{"headache", "tired"}, entity.TagOperatorAnd, []string{"both"}},
+		{"and default", []string{"headache"}, "", []string{"both", "headache"}},
+		{"and non-overlapping", []string{"headache", "caffeine"}, entity.TagOperatorAnd, nil},
+		{"or overlapping", []string{"headache", "tired"}, entity.TagOperatorOr, []string{"both", "headache"}},
+		{"or non-overlapping", []string{"tired", "caffeine"}, entity.TagOperatorOr, []string{"both", "other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := repo.List(ctx, entity.ConditionFilter{
+				From: base.Add(-time.Minute), To: base.Add(3 * time.Hour),
+				Tags: tt.tags, TagOperator: tt.op,
+				Limit: 10, SortDir: "asc",
+			})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			var got []string
+			for _, l := range res.Items {
+				got = append(got, byID[l.ID])
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestConditionRepo_ListTotal(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewConditionRepo(pool)
+	ctx := context.Background()
+
+	base := time.Date(2001, 3, 4, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		l := &entity.ConditionLog{Overall: 3, OverallVAS: 50, LoggedAt: base.Add(time.Duration(i) * time.Hour)}
+		if err := repo.Create(ctx, l); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		id := l.ID
+		t.Cleanup(func() { repo.DeletePermanent(ctx, id) })
+	}
+	window := entity.ConditionFilter{From: base.Add(-time.Minute), To: base.Add(3 * time.Hour), Limit: 10}
+
+	tests := []struct {
+		name      string
+		countOnly bool
+		offset    int
+		wantItems int
+	}{
+		{"page", false, 0, 3},
+		{"page past the end", false, 10, 0},
+		{"count only", true, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := window
+			filter.CountOnly = tt.countOnly
+			filter.Offset = tt.offset
+			res, err := repo.List(ctx, filter)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if res.Total != 3 {
+				t.Errorf("Total = %d, want 3", res.Total)
+			}
+			if len(res.Items) != tt.wantItems {
+				t.Errorf("len(Items) = %d, want %d", len(res.Items), tt.wantItems)
+			}
+			if tt.countOnly && res.Items == nil {
+				t.Error("Items = nil, want empty slice")
+			}
+		})
+	}
+}
+
+// BenchmarkConditionRepo_List compares a count-only request with fetching
+// the first page, whose total comes from COUNT(*) OVER().
+func BenchmarkConditionRepo_List(b *testing.B) {
+	pool := newTestPool(b)
+	repo := NewConditionRepo(pool)
+	ctx := context.Background()
+
+	base := time.Date(2001, 4, 5, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 500; i++ {
+		l := &entity.ConditionLog{Overall: 3, OverallVAS: 50, LoggedAt: base.Add(time.Duration(i) * time.Minute)}
+		if err := repo.Create(ctx, l); err != nil {
+			b.Fatalf("Create() error = %v", err)
+		}
+		id := l.ID
+		b.Cleanup(func() { repo.DeletePermanent(ctx, id) })
+	}
+	filter := entity.ConditionFilter{From: base, To: base.Add(500 * time.Minute), Limit: 100}
+
+	for _, countOnly := range []bool{false, true} {
+		b.Run(fmt.Sprintf("count_only=%v", countOnly), func(b *testing.B) {
+			f := filter
+			f.CountOnly = countOnly
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.List(ctx, f); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestConditionRepo_UpdateWritesHistory(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewConditionRepo(pool)
+	ctx := context.Background()
+
+	log := &entity.ConditionLog{Overall: 3, OverallVAS: 40, Note: "before", LoggedAt: time.Now(), Tags: []string{}}
+	if err := repo.Create(ctx, log); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	t.Cleanup(func() {
+		repo.DeletePermanent(ctx, log.ID)
+		pool.Exec(ctx, `DELETE FROM condition_log_history WHERE condition_log_id = $1`, log.ID)
+	})
+
+	updated := *log
+	updated.OverallVAS = 70
+	updated.Note = "after"
+	if err := repo.Update(ctx, &updated); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	history, err := repo.GetHistory(ctx, log.ID)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("len(history) = %d, want 1", len(history))
+	}
+	h := history[0]
+	if h.ConditionLogID != log.ID {
+		t.Errorf("ConditionLogID = %d, want %d", h.ConditionLogID, log.ID)
+	}
+	if h.OldValues.OverallVAS != 40 || h.OldValues.Note != "before" {
+		t.Errorf("OldValues = %+v, want VAS 40 note before", h.OldValues)
+	}
+	if h.NewValues.OverallVAS != 70 || h.NewValues.Note != "after" {
+		t.Errorf("NewValues = %+v, want VAS 70 note after", h.NewValues)
+	}
+}
+
+func TestConditionRepo_SoftDeleteAndRestore(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewConditionRepo(pool)
+	ctx := context.Background()
+
+	loggedAt := time.Date(2001, 3, 4, 12, 0, 0, 0, time.UTC)
+	log := &entity.ConditionLog{Overall: 3, OverallVAS: 50, LoggedAt: loggedAt, Tags: []string{}}
+	if err := repo.Create(ctx, log); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	t.Cleanup(func() { repo.DeletePermanent(ctx, log.ID) })
+
+	if err := repo.Delete(ctx, log.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got, err := repo.GetByID(ctx, log.ID); err != nil || got != nil {
+		t.Fatalf("GetByID() after delete = %v, %v, want nil", got, err)
+	}
+	filter := entity.ConditionFilter{From: loggedAt.Add(-time.Hour), To: loggedAt.Add(time.Hour), Limit: 10}
+	if res, err := repo.List(ctx, filter); err != nil || len(res.Items) != 0 {
+		t.Fatalf("List() after delete = %+v, %v, want empty", res, err)
+	}
+	filter.IncludeDeleted = true
+	res, err := repo.List(ctx, filter)
+	if err != nil || len(res.Items) != 1 || res.Items[0].DeletedAt == nil {
+		t.Fatalf("List(IncludeDeleted) = %+v, %v, want one deleted log", res, err)
+	}
+
+	if ok, err := repo.Restore(ctx, log.ID); err != nil || !ok {
+		t.Fatalf("Restore() = %v, %v, want true", ok, err)
+	}
+	if ok, _ := repo.Restore(ctx, log.ID); ok {
+		t.Error("Restore() of a live log = true, want false")
+	}
+	if got, err := repo.GetByID(ctx, log.ID); err != nil || got == nil {
+		t.Fatalf("GetByID() after restore = %v, %v", got, err)
+	}
+
+	if ok, err := repo.DeletePermanent(ctx, log.ID); err != nil || !ok {
+		t.Fatalf("DeletePermanent() = %v, %v, want true", ok, err)
+	}
+	if ok, _ := repo.Restore(ctx, log.ID); ok {
+		t.Error("Restore() after permanent delete = true, want false")
+	}
+}
+
+func TestConditionRepo_ExcludeOutliers(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewConditionRepo(pool)
+	ctx := context.Background()
+
+	loggedAt := time.Date(2001, 5, 6, 12, 0, 0, 0, time.UTC)
+	normal := &entity.ConditionLog{Overall: 4, OverallVAS: 70, LoggedAt: loggedAt, Tags: []string{}}
+	outlier := &entity.ConditionLog{Overall: 1, OverallVAS: 1, LoggedAt: loggedAt.Add(time.Minute), Tags: []string{}, IsOutlier: true}
+	for _, l := range []*entity.ConditionLog{normal, outlier} {
+		if err := repo.Create(ctx, l); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		t.Cleanup(func() { repo.DeletePermanent(ctx, l.ID) })
+	}
+
+	if got, err := repo.GetByID(ctx, outlier.ID); err != nil || got == nil || !got.IsOutlier {
+		t.Fatalf("GetByID() = %+v, %v, want IsOutlier", got, err)
+	}
+
+	filter := entity.ConditionFilter{From: loggedAt.Add(-time.Hour), To: loggedAt.Add(time.Hour), Limit: 10}
+	if res, err := repo.List(ctx, filter); err != nil || res.Total != 2 {
+		t.Fatalf("List() = %+v, %v, want both logs", res, err)
+	}
+	filter.ExcludeOutliers = true
+	res, err := repo.List(ctx, filter)
+	if err != nil || len(res.Items) != 1 || res.Items[0].ID != normal.ID {
+		t.Fatalf("List(ExcludeOutliers) = %+v, %v, want only log %d", res, err, normal.ID)
+	}
+}
+
+func TestConditionRepo_GetSummary_TimeWeighted(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewConditionRepo(pool)
+	ctx := context.Background()
+
+	// One JST day with a morning 30 and an evening 90: weights 2/3 and 4/3.
+	jst := time.FixedZone("JST", 9*60*60)
+	day := time.Date(2001, 3, 4, 0, 0, 0, 0, jst)
+	for _, l := range []*entity.ConditionLog{
+		{Overall: 2, OverallVAS: 30, LoggedAt: day.Add(8 * time.Hour), Tags: []string{}},
+		{Overall: 5, OverallVAS: 90, LoggedAt: day.Add(21 * time.Hour), Tags: []string{}},
+	} {
+		if err := repo.Create(ctx, l); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		id := l.ID
+		t.Cleanup(func() { repo.DeletePermanent(ctx, id) })
+	}
+
+	filter := entity.ConditionFilter{From: day, To: day.AddDate(0, 0, 1).Add(-time.Nanosecond)}
+	uniform, err := repo.GetSummary(ctx, filter)
+	if err != nil {
+		t.Fatalf("GetSummary(uniform) error = %v", err)
+	}
+	if uniform.OverallVASAvg != 60 {
+		t.Errorf("uniform OverallVASAvg = %v, want 60", uniform.OverallVASAvg)
+	}
+
+	filter.WeightingMode = entity.WeightingTimeWeighted
+	weighted, err := repo.GetSummary(ctx, filter)
+	if err != nil {
+		t.Fatalf("GetSummary(time_weighted) error = %v", err)
+	}
+	if d := weighted.OverallVASAvg - 70; d > 1e-9 || d < -1e-9 {
+		t.Errorf("time_weighted OverallVASAvg = %v, want 70", weighted.OverallVASAvg)
+	}
+	if weighted.TotalCount != 2 || weighted.OverallVASMin != 30 || weighted.OverallVASMax != 90 {
+		t.Errorf("time_weighted count/min/max = %d/%d/%d, want 2/30/90",
+			weighted.TotalCount, weighted.OverallVASMin, weighted.OverallVASMax)
+	}
+}
+
+func TestConditionRepo_GetSummary_VASAggregates(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewConditionRepo(pool)
+	ctx := context.Background()
+
+	vas := func(v int) *int { return &v }
+	day := time.Date(2001, 4, 5, 0, 0, 0, 0, time.UTC)
+	for _, l := range []*entity.ConditionLog{
+		{Overall: 3, OverallVAS: 40, MoodVAS: vas(20), EnergyVAS: vas(50), SleepQualityVAS: vas(60), StressVAS: vas(70),
+			LoggedAt: day.Add(9 * time.Hour), Tags: []string{}},
+		{Overall: 4, OverallVAS: 80, MoodVAS: vas(60), StressVAS: vas(30),
+			LoggedAt: day.Add(18 * time.Hour), Tags: []string{}},
+	} {
+		if err := repo.Create(ctx, l); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		id := l.ID
+		t.Cleanup(func() { repo.DeletePermanent(ctx, id) })
+	}
+
+	s, err := repo.GetSummary(ctx, entity.ConditionFilter{From: day, To: day.AddDate(0, 0, 1).Add(-time.Nanosecond)})
+	if err != nil {
+		t.Fatalf("GetSummary() error = %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		avg              float64
+		min, max         int
+		wantAvg          float64
+		wantMin, wantMax int
+	}{
+		{"overall_vas", s.OverallVASAvg, s.OverallVASMin, s.OverallVASMax, 60, 40, 80},
+		{"mood_vas", s.MoodVASAvg, s.MoodVASMin, s.MoodVASMax, 40, 20, 60},
+		// NULLs are skipped, so a single value sets avg, min and max.
+		{"energy_vas", s.EnergyVASAvg, s.EnergyVASMin, s.EnergyVASMax, 50, 50, 50},
+		{"sleep_quality_vas", s.SleepQualityVASAvg, s.SleepQualityVASMin, s.SleepQualityVASMax, 60, 60, 60},
+		{"stress_vas", s.StressVASAvg, s.StressVASMin, s.StressVASMax, 50, 30, 70},
+	}
+	for _, tt := range tests {
+		if tt.avg != tt.wantAvg || tt.min != tt.wantMin || tt.max != tt.wantMax {
+			t.Errorf("%s avg/min/max = %v/%d/%d, want %v/%d/%d",
+				tt.name, tt.avg, tt.min, tt.max, tt.wantAvg, tt.wantMin, tt.wantMax)
+		}
+	}
+}
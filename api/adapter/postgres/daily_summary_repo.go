@@ -19,6 +19,18 @@ func NewDailySummaryRepo(pool *pgxpool.Pool) *DailySummaryRepo {
 }
 
 func (r *DailySummaryRepo) Upsert(ctx context.Context, s *entity.DailySummary) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	providers := s.Providers
+	if providers == nil {
+		providers = []string{}
+	}
+	filled := s.FilledForwardFields
+	if filled == nil {
+		filled = []string{}
+	}
+
 	_, err := r.pool.Exec(ctx,
 		`INSERT INTO daily_summaries (
 			date, provider,
@@ -33,12 +45,15 @@ func (r *DailySummaryRepo) Upsert(ctx context.Context, s *entity.DailySummary) e
 			active_zone_min, minutes_sedentary, minutes_lightly, minutes_fairly, minutes_very,
 			vo2_max,
 			hr_zone_out_min, hr_zone_fat_min, hr_zone_cardio_min, hr_zone_peak_min,
-			synced_at
+			synced_at, fever_candidate, water_intake_ml, providers, filled_forward_fields,
+			activity_intensity_score
 		) VALUES (
 			$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,
-			$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38,$39,$40,$41,$42,$43,$44
+			$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38,$39,$40,$41,$42,$43,$44,$45,$46,$47,$48,$49
 		) ON CONFLICT (date) DO UPDATE SET
 			provider=$2,
+			providers=array_cat(daily_summaries.providers,
+				ARRAY(SELECT p FROM unnest($47::text[]) AS p WHERE p <> ALL(daily_summaries.providers))),
 			resting_hr=$3, avg_hr=$4, max_hr=$5,
 			hrv_daily_rmssd=COALESCE(NULLIF($6::real,0),daily_summaries.hrv_daily_rmssd),
 			hrv_deep_rmssd=COALESCE(NULLIF($7::real,0),daily_summaries.hrv_deep_rmssd),
@@ -56,8 +71,10 @@ func (r *DailySummaryRepo) Upsert(ctx context.Context, s *entity.DailySummary) e
 			active_zone_min=$34, minutes_sedentary=$35, minutes_lightly=$36, minutes_fairly=$37, minutes_very=$38,
 			vo2_max=$39,
 			hr_zone_out_min=$40, hr_zone_fat_min=$41, hr_zone_cardio_min=$42, hr_zone_peak_min=$43,
-			synced_at=$44`,
-		s.Date, s.Provider,
+			synced_at=$44, fever_candidate=$45,
+			water_intake_ml=COALESCE(NULLIF($46::int,0),daily_summaries.water_intake_ml),
+			filled_forward_fields=$48, activity_intensity_score=$49`,
+		s.Date, s.PrimaryProvider(),
 		s.RestingHR, s.AvgHR, s.MaxHR,
 		s.HRVDailyRMSSD, s.HRVDeepRMSSD,
 		s.SpO2Avg, s.SpO2Min, s.SpO2Max,
@@ -69,13 +86,17 @@ func (r *DailySummaryRepo) Upsert(ctx context.Context, s *entity.DailySummary) e
 		s.ActiveZoneMin, s.MinutesSedentary, s.MinutesLightly, s.MinutesFairly, s.MinutesVery,
 		s.VO2Max,
 		s.HRZoneOutMin, s.HRZoneFatMin, s.HRZoneCardioMin, s.HRZonePeakMin,
-		s.SyncedAt)
+		s.SyncedAt, s.FeverCandidate, s.WaterIntakeMl, providers, filled,
+		s.ActivityIntensityScore)
 	return err
 }
 
 func (r *DailySummaryRepo) GetByDate(ctx context.Context, date time.Time) (*entity.DailySummary, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	row := r.pool.QueryRow(ctx,
-		`SELECT date, provider,
+		`SELECT date, COALESCE(NULLIF(providers, '{}'), ARRAY[provider]),
 			resting_hr, avg_hr, max_hr,
 			hrv_daily_rmssd, hrv_deep_rmssd,
 			spo2_avg, spo2_min, spo2_max,
@@ -87,12 +108,12 @@ func (r *DailySummaryRepo) GetByDate(ctx context.Context, date time.Time) (*enti
 			active_zone_min, minutes_sedentary, minutes_lightly, minutes_fairly, minutes_very,
 			vo2_max,
 			hr_zone_out_min, hr_zone_fat_min, hr_zone_cardio_min, hr_zone_peak_min,
-			synced_at
+			synced_at, fever_candidate, water_intake_ml, filled_forward_fields, activity_intensity_score
 		 FROM daily_summaries WHERE date = $1`, date)
 
 	var s entity.DailySummary
 	err := row.Scan(
-		&s.Date, &s.Provider,
+		&s.Date, &s.Providers,
 		&s.RestingHR, &s.AvgHR, &s.MaxHR,
 		&s.HRVDailyRMSSD, &s.HRVDeepRMSSD,
 		&s.SpO2Avg, &s.SpO2Min, &s.SpO2Max,
@@ -104,7 +125,7 @@ func (r *DailySummaryRepo) GetByDate(ctx context.Context, date time.Time) (*enti
 		&s.ActiveZoneMin, &s.MinutesSedentary, &s.MinutesLightly, &s.MinutesFairly, &s.MinutesVery,
 		&s.VO2Max,
 		&s.HRZoneOutMin, &s.HRZoneFatMin, &s.HRZoneCardioMin, &s.HRZonePeakMin,
-		&s.SyncedAt)
+		&s.SyncedAt, &s.FeverCandidate, &s.WaterIntakeMl, &s.FilledForwardFields, &s.ActivityIntensityScore)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
@@ -115,8 +136,11 @@ func (r *DailySummaryRepo) GetByDate(ctx context.Context, date time.Time) (*enti
 }
 
 func (r *DailySummaryRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.DailySummary, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
-		`SELECT date, provider,
+		`SELECT date, COALESCE(NULLIF(providers, '{}'), ARRAY[provider]),
 			resting_hr, avg_hr, max_hr,
 			hrv_daily_rmssd, hrv_deep_rmssd,
 			spo2_avg, spo2_min, spo2_max,
@@ -128,7 +152,7 @@ func (r *DailySummaryRepo) ListRange(ctx context.Context, from, to time.Time) ([
 			active_zone_min, minutes_sedentary, minutes_lightly, minutes_fairly, minutes_very,
 			vo2_max,
 			hr_zone_out_min, hr_zone_fat_min, hr_zone_cardio_min, hr_zone_peak_min,
-			synced_at
+			synced_at, fever_candidate, water_intake_ml, filled_forward_fields, activity_intensity_score
 		 FROM daily_summaries WHERE date BETWEEN $1 AND $2 ORDER BY date ASC`, from, to)
 	if err != nil {
 		return nil, err
@@ -139,7 +163,7 @@ func (r *DailySummaryRepo) ListRange(ctx context.Context, from, to time.Time) ([
 	for rows.Next() {
 		var s entity.DailySummary
 		if err := rows.Scan(
-			&s.Date, &s.Provider,
+			&s.Date, &s.Providers,
 			&s.RestingHR, &s.AvgHR, &s.MaxHR,
 			&s.HRVDailyRMSSD, &s.HRVDeepRMSSD,
 			&s.SpO2Avg, &s.SpO2Min, &s.SpO2Max,
@@ -151,10 +175,20 @@ func (r *DailySummaryRepo) ListRange(ctx context.Context, from, to time.Time) ([
 			&s.ActiveZoneMin, &s.MinutesSedentary, &s.MinutesLightly, &s.MinutesFairly, &s.MinutesVery,
 			&s.VO2Max,
 			&s.HRZoneOutMin, &s.HRZoneFatMin, &s.HRZoneCardioMin, &s.HRZonePeakMin,
-			&s.SyncedAt); err != nil {
+			&s.SyncedAt, &s.FeverCandidate, &s.WaterIntakeMl, &s.FilledForwardFields, &s.ActivityIntensityScore); err != nil {
 			return nil, err
 		}
 		summaries = append(summaries, s)
 	}
 	return summaries, rows.Err()
 }
+
+func (r *DailySummaryRepo) CountRange(ctx context.Context, from, to time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var n int
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM daily_summaries WHERE date BETWEEN $1 AND $2`, from, to).Scan(&n)
+	return n, err
+}
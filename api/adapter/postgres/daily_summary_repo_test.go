@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+func TestDailySummaryRepo_MergesProviders(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDailySummaryRepo(pool)
+	ctx := context.Background()
+
+	date := time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)
+	t.Cleanup(func() { pool.Exec(ctx, `DELETE FROM daily_summaries WHERE date = $1`, date) })
+
+	for _, provider := range []string{"fitbit", "health_connect", "fitbit"} {
+		s := &entity.DailySummary{Date: date, Providers: []string{provider}, SyncedAt: time.Now()}
+		if err := repo.Upsert(ctx, s); err != nil {
+			t.Fatalf("Upsert(%s) error = %v", provider, err)
+		}
+	}
+
+	got, err := repo.GetByDate(ctx, date)
+	if err != nil || got == nil {
+		t.Fatalf("GetByDate() = %v, %v", got, err)
+	}
+	if want := []string{"fitbit", "health_connect"}; !slices.Equal(got.Providers, want) {
+		t.Errorf("Providers = %v, want %v", got.Providers, want)
+	}
+}
+
+func TestDailySummaryRepo_FilledForwardFields(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDailySummaryRepo(pool)
+	ctx := context.Background()
+
+	date := time.Date(1999, 1, 2, 0, 0, 0, 0, time.UTC)
+	t.Cleanup(func() { pool.Exec(ctx, `DELETE FROM daily_summaries WHERE date = $1`, date) })
+
+	s := &entity.DailySummary{
+		Date:                date,
+		Providers:           []string{"fitbit"},
+		HRVDailyRMSSD:       entity.Float32Ptr(42),
+		FilledForwardFields: []string{"HRVDailyRMSSD"},
+		SyncedAt:            time.Now(),
+	}
+	if err := repo.Upsert(ctx, s); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	got, err := repo.GetByDate(ctx, date)
+	if err != nil || got == nil {
+		t.Fatalf("GetByDate() = %v, %v", got, err)
+	}
+	if !got.IsFilledForward("HRVDailyRMSSD") {
+		t.Errorf("FilledForwardFields = %v, want HRVDailyRMSSD", got.FilledForwardFields)
+	}
+
+	// A later sync that measured the value clears the marker.
+	s.FilledForwardFields = nil
+	if err := repo.Upsert(ctx, s); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if got, _ := repo.GetByDate(ctx, date); len(got.FilledForwardFields) != 0 {
+		t.Errorf("FilledForwardFields = %v, want none", got.FilledForwardFields)
+	}
+}
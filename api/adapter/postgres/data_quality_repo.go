@@ -21,6 +21,9 @@ func NewDataQualityRepo(pool *pgxpool.Pool) *DataQualityRepo {
 }
 
 func (r *DataQualityRepo) Upsert(ctx context.Context, q *entity.DataQuality) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	flagsJSON, err := json.Marshal(q.PlausibilityFlags)
 	if err != nil {
 		return fmt.Errorf("marshal plausibility_flags: %w", err)
@@ -34,9 +37,11 @@ func (r *DataQualityRepo) Upsert(ctx context.Context, q *entity.DataQuality) err
 			is_valid_day,
 			baseline_days, baseline_maturity,
 			confidence_score, confidence_level,
-			computed_at
+			computed_at,
+			lowest_spo2, spo2_min_alert,
+			sleep_stage_confidence, sleep_stage_quality_pct
 		) VALUES (
-			$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14
+			$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18
 		) ON CONFLICT (date) DO UPDATE SET
 			wear_time_hours=$2, hr_sample_count=$3,
 			completeness_pct=$4, metrics_present=$5, metrics_missing=$6,
@@ -44,18 +49,25 @@ func (r *DataQualityRepo) Upsert(ctx context.Context, q *entity.DataQuality) err
 			is_valid_day=$9,
 			baseline_days=$10, baseline_maturity=$11,
 			confidence_score=$12, confidence_level=$13,
-			computed_at=$14`,
+			computed_at=$14,
+			lowest_spo2=$15, spo2_min_alert=$16,
+			sleep_stage_confidence=$17, sleep_stage_quality_pct=$18`,
 		q.Date, q.WearTimeHours, q.HRSampleCount,
 		q.CompletenessPct, q.MetricsPresent, q.MetricsMissing,
 		flagsJSON, q.PlausibilityPass,
 		q.IsValidDay,
 		q.BaselineDays, q.BaselineMaturity,
 		q.ConfidenceScore, q.ConfidenceLevel,
-		q.ComputedAt)
+		q.ComputedAt,
+		q.LowestSpO2, q.SpO2MinAlert,
+		q.SleepStageConfidence, q.SleepStageQualityPct)
 	return err
 }
 
 func (r *DataQualityRepo) GetByDate(ctx context.Context, date time.Time) (*entity.DataQuality, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	row := r.pool.QueryRow(ctx,
 		`SELECT date, wear_time_hours, hr_sample_count,
 			completeness_pct, metrics_present, metrics_missing,
@@ -63,13 +75,18 @@ func (r *DataQualityRepo) GetByDate(ctx context.Context, date time.Time) (*entit
 			is_valid_day,
 			baseline_days, baseline_maturity,
 			confidence_score, confidence_level,
-			computed_at
+			computed_at,
+			lowest_spo2, spo2_min_alert,
+			sleep_stage_confidence, sleep_stage_quality_pct
 		FROM daily_data_quality WHERE date = $1`, date)
 
 	return scanDataQuality(row)
 }
 
 func (r *DataQualityRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.DataQuality, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
 		`SELECT date, wear_time_hours, hr_sample_count,
 			completeness_pct, metrics_present, metrics_missing,
@@ -77,7 +94,9 @@ func (r *DataQualityRepo) ListRange(ctx context.Context, from, to time.Time) ([]
 			is_valid_day,
 			baseline_days, baseline_maturity,
 			confidence_score, confidence_level,
-			computed_at
+			computed_at,
+			lowest_spo2, spo2_min_alert,
+			sleep_stage_confidence, sleep_stage_quality_pct
 		FROM daily_data_quality WHERE date BETWEEN $1 AND $2 ORDER BY date ASC`, from, to)
 	if err != nil {
 		return nil, err
@@ -95,7 +114,46 @@ func (r *DataQualityRepo) ListRange(ctx context.Context, from, to time.Time) ([]
 	return result, rows.Err()
 }
 
+// ListAlerts returns days in [from, to] with a low SpO2 alert or a failed
+// plausibility check, oldest first.
+func (r *DataQualityRepo) ListAlerts(ctx context.Context, from, to time.Time) ([]entity.DataQuality, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT date, wear_time_hours, hr_sample_count,
+			completeness_pct, metrics_present, metrics_missing,
+			plausibility_flags, plausibility_pass,
+			is_valid_day,
+			baseline_days, baseline_maturity,
+			confidence_score, confidence_level,
+			computed_at,
+			lowest_spo2, spo2_min_alert,
+			sleep_stage_confidence, sleep_stage_quality_pct
+		FROM daily_data_quality
+		WHERE date BETWEEN $1 AND $2
+		  AND (spo2_min_alert OR plausibility_pass = FALSE)
+		ORDER BY date ASC`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.DataQuality
+	for rows.Next() {
+		q, err := scanDataQualityRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *q)
+	}
+	return result, rows.Err()
+}
+
 func (r *DataQualityRepo) CountValidDays(ctx context.Context, before time.Time, windowDays int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	var count int
 	err := r.pool.QueryRow(ctx,
 		`SELECT COUNT(*) FROM daily_data_quality
@@ -115,7 +173,9 @@ func scanDataQuality(row pgx.Row) (*entity.DataQuality, error) {
 		&q.IsValidDay,
 		&q.BaselineDays, &q.BaselineMaturity,
 		&q.ConfidenceScore, &q.ConfidenceLevel,
-		&q.ComputedAt)
+		&q.ComputedAt,
+		&q.LowestSpO2, &q.SpO2MinAlert,
+		&q.SleepStageConfidence, &q.SleepStageQualityPct)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
@@ -140,7 +200,9 @@ func scanDataQualityRows(rows pgx.Rows) (*entity.DataQuality, error) {
 		&q.IsValidDay,
 		&q.BaselineDays, &q.BaselineMaturity,
 		&q.ConfidenceScore, &q.ConfidenceLevel,
-		&q.ComputedAt)
+		&q.ComputedAt,
+		&q.LowestSpO2, &q.SpO2MinAlert,
+		&q.SleepStageConfidence, &q.SleepStageQualityPct)
 	if err != nil {
 		return nil, err
 	}
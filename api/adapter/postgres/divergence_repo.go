@@ -19,6 +19,9 @@ func NewDivergenceRepo(pool *pgxpool.Pool) *DivergenceRepo {
 }
 
 func (r *DivergenceRepo) GetByDate(ctx context.Context, date time.Time) (*entity.DivergenceDetection, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	row := r.pool.QueryRow(ctx,
 		`SELECT date, condition_log_id, actual_score, predicted_score, residual,
 			cusum_positive, cusum_negative, cusum_alert,
@@ -42,6 +45,9 @@ func (r *DivergenceRepo) GetByDate(ctx context.Context, date time.Time) (*entity
 }
 
 func (r *DivergenceRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.DivergenceDetection, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
 		`SELECT date, condition_log_id, actual_score, predicted_score, residual,
 			cusum_positive, cusum_negative, cusum_alert,
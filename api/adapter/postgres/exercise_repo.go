@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"vitametron/api/domain/entity"
@@ -18,33 +19,121 @@ func NewExerciseRepo(pool *pgxpool.Pool) *ExerciseRepo {
 }
 
 func (r *ExerciseRepo) Upsert(ctx context.Context, log *entity.ExerciseLog) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tags := log.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	// A re-sync keeps tags and notes edited after import; imported notes
+	// only fill a log whose notes are still empty. A log without a VO2max
+	// estimate keeps the stored one.
 	_, err := r.pool.Exec(ctx,
-		`INSERT INTO exercise_logs (external_id, activity_name, started_at, duration_ms, calories, avg_hr, distance_km, zone_minutes)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`INSERT INTO exercise_logs (external_id, activity_name, started_at, duration_ms, calories, avg_hr, distance_km, zone_minutes, met, calories_per_minute, pace, tags, notes, estimated_vo2max)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		 ON CONFLICT (external_id) DO UPDATE SET
-			activity_name=$2, started_at=$3, duration_ms=$4, calories=$5, avg_hr=$6, distance_km=$7, zone_minutes=$8, synced_at=NOW()`,
+			activity_name=$2, started_at=$3, duration_ms=$4, calories=$5, avg_hr=$6, distance_km=$7, zone_minutes=$8,
+			met=$9, calories_per_minute=$10, pace=$11,
+			notes=CASE WHEN exercise_logs.notes = '' THEN $13 ELSE exercise_logs.notes END,
+			estimated_vo2max=COALESCE($14, exercise_logs.estimated_vo2max), synced_at=NOW()`,
 		log.ExternalID, log.ActivityName, log.StartedAt, log.DurationMS,
-		log.Calories, log.AvgHR, log.DistanceKM, log.ZoneMinutes)
+		log.Calories, log.AvgHR, log.DistanceKM, log.ZoneMinutes,
+		log.MET, log.CaloriesPerMinute, log.Pace, tags, log.Notes, log.EstimatedVO2Max)
 	return err
 }
 
+// exerciseColumns is the column list scanned by scanExerciseLog.
+const exerciseColumns = `id, external_id, activity_name, started_at, duration_ms, calories, avg_hr, distance_km,
+			COALESCE(met, 0), COALESCE(calories_per_minute, 0), COALESCE(pace, 0), estimated_vo2max, tags, notes, synced_at`
+
+func scanExerciseLog(row pgx.Row) (*entity.ExerciseLog, error) {
+	var l entity.ExerciseLog
+	if err := row.Scan(&l.ID, &l.ExternalID, &l.ActivityName, &l.StartedAt,
+		&l.DurationMS, &l.Calories, &l.AvgHR, &l.DistanceKM,
+		&l.MET, &l.CaloriesPerMinute, &l.Pace, &l.EstimatedVO2Max, &l.Tags, &l.Notes, &l.SyncedAt); err != nil {
+		return nil, err
+	}
+	if l.Tags == nil {
+		l.Tags = []string{}
+	}
+	return &l, nil
+}
+
 func (r *ExerciseRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.ExerciseLog, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
-		`SELECT id, external_id, activity_name, started_at, duration_ms, calories, avg_hr, distance_km, synced_at
+		`SELECT `+exerciseColumns+`
 		 FROM exercise_logs WHERE started_at BETWEEN $1 AND $2 ORDER BY started_at DESC`, from, to)
 	if err != nil {
 		return nil, err
 	}
+	return collectExerciseLogs(rows)
+}
+
+func (r *ExerciseRepo) ListByTag(ctx context.Context, tag string, from, to time.Time) ([]entity.ExerciseLog, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+exerciseColumns+`
+		 FROM exercise_logs WHERE tags @> ARRAY[$1]::text[] AND started_at BETWEEN $2 AND $3
+		 ORDER BY started_at DESC`, tag, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return collectExerciseLogs(rows)
+}
+
+func collectExerciseLogs(rows pgx.Rows) ([]entity.ExerciseLog, error) {
 	defer rows.Close()
 
 	var logs []entity.ExerciseLog
 	for rows.Next() {
-		var l entity.ExerciseLog
-		if err := rows.Scan(&l.ID, &l.ExternalID, &l.ActivityName, &l.StartedAt,
-			&l.DurationMS, &l.Calories, &l.AvgHR, &l.DistanceKM, &l.SyncedAt); err != nil {
+		l, err := scanExerciseLog(rows)
+		if err != nil {
 			return nil, err
 		}
-		logs = append(logs, l)
+		logs = append(logs, *l)
 	}
 	return logs, rows.Err()
 }
+
+func (r *ExerciseRepo) GetByID(ctx context.Context, id int64) (*entity.ExerciseLog, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	l, err := scanExerciseLog(r.pool.QueryRow(ctx,
+		`SELECT `+exerciseColumns+`
+		 FROM exercise_logs WHERE id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (r *ExerciseRepo) UpdateEstimatedVO2Max(ctx context.Context, id int64, vo2max float32) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx,
+		`UPDATE exercise_logs SET estimated_vo2max = $2 WHERE id = $1`, id, vo2max)
+	return err
+}
+
+func (r *ExerciseRepo) UpdateNotes(ctx context.Context, id int64, notes string, tags []string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if tags == nil {
+		tags = []string{}
+	}
+	_, err := r.pool.Exec(ctx,
+		`UPDATE exercise_logs SET notes = $2, tags = $3 WHERE id = $1`, id, notes, tags)
+	return err
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+func TestExerciseRepo_ListRange(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewExerciseRepo(pool)
+	ctx := context.Background()
+
+	t.Cleanup(func() { pool.Exec(ctx, `DELETE FROM exercise_logs WHERE external_id LIKE 'test-range-%'`) })
+
+	day := time.Date(2001, 5, 6, 0, 0, 0, 0, time.UTC)
+	for _, l := range []*entity.ExerciseLog{
+		{ExternalID: "test-range-before", ActivityName: "Walk", StartedAt: day.Add(-time.Hour)},
+		{ExternalID: "test-range-morning", ActivityName: "Run", StartedAt: day.Add(7 * time.Hour)},
+		{ExternalID: "test-range-evening", ActivityName: "Bike", StartedAt: day.Add(19 * time.Hour)},
+		{ExternalID: "test-range-after", ActivityName: "Swim", StartedAt: day.AddDate(0, 0, 1)},
+	} {
+		if err := repo.Upsert(ctx, l); err != nil {
+			t.Fatalf("Upsert(%s) error = %v", l.ExternalID, err)
+		}
+	}
+
+	got, err := repo.ListRange(ctx, day, day.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if err != nil {
+		t.Fatalf("ListRange() error = %v", err)
+	}
+	// Newest first, as ExerciseHandler.List documents.
+	want := []string{"test-range-evening", "test-range-morning"}
+	if len(got) != len(want) {
+		t.Fatalf("ListRange() returned %d logs, want %d: %+v", len(got), len(want), got)
+	}
+	for i, id := range want {
+		if got[i].ExternalID != id {
+			t.Errorf("got[%d].ExternalID = %q, want %q", i, got[i].ExternalID, id)
+		}
+		if got[i].Tags == nil {
+			t.Errorf("got[%d].Tags = nil, want empty slice", i)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"vitametron/api/domain/entity"
+)
+
+type ExerciseRouteRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewExerciseRouteRepo(pool *pgxpool.Pool) *ExerciseRouteRepo {
+	return &ExerciseRouteRepo{pool: pool}
+}
+
+func (r *ExerciseRouteRepo) Save(ctx context.Context, route *entity.ExerciseRoute) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	points, err := json.Marshal(route.Points)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx,
+		`INSERT INTO exercise_routes (exercise_external_id, points)
+		 VALUES ($1, $2)
+		 ON CONFLICT (exercise_external_id) DO UPDATE SET points = $2`,
+		route.ExerciseExternalID, points)
+	return err
+}
+
+func (r *ExerciseRouteRepo) GetByExercise(ctx context.Context, externalID string) (*entity.ExerciseRoute, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var points []byte
+	err := r.pool.QueryRow(ctx,
+		`SELECT points FROM exercise_routes WHERE exercise_external_id = $1`, externalID).Scan(&points)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	route := &entity.ExerciseRoute{ExerciseExternalID: externalID}
+	if err := json.Unmarshal(points, &route.Points); err != nil {
+		return nil, err
+	}
+	return route, nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+func TestExerciseRouteRepo_SaveAndGet(t *testing.T) {
+	pool := newTestPool(t)
+	exercises := NewExerciseRepo(pool)
+	repo := NewExerciseRouteRepo(pool)
+	ctx := context.Background()
+
+	t.Cleanup(func() { pool.Exec(ctx, `DELETE FROM exercise_logs WHERE external_id = 'test-route'`) })
+
+	start := time.Date(2001, 5, 6, 7, 0, 0, 0, time.UTC)
+	if err := exercises.Upsert(ctx, &entity.ExerciseLog{ExternalID: "test-route", ActivityName: "Run", StartedAt: start}); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	got, err := repo.GetByExercise(ctx, "test-route")
+	if err != nil || got != nil {
+		t.Fatalf("GetByExercise() before save = %+v, %v, want nil, nil", got, err)
+	}
+
+	ele := 12.5
+	for _, points := range [][]entity.GPXPoint{
+		{{Lat: 1, Lng: 2, Time: start}},
+		{{Lat: 35.6812, Lng: 139.7671, Elevation: &ele, Time: start}, {Lat: 35.6815, Lng: 139.7675, Time: start.Add(5 * time.Second)}},
+	} {
+		if err := repo.Save(ctx, &entity.ExerciseRoute{ExerciseExternalID: "test-route", Points: points}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	got, err = repo.GetByExercise(ctx, "test-route")
+	if err != nil {
+		t.Fatalf("GetByExercise() error = %v", err)
+	}
+	// The second save replaces the first.
+	if got == nil || len(got.Points) != 2 {
+		t.Fatalf("GetByExercise() = %+v, want 2 points", got)
+	}
+	if p := got.Points[0]; p.Lat != 35.6812 || p.Elevation == nil || *p.Elevation != 12.5 || !p.Time.Equal(start) {
+		t.Errorf("Points[0] = %+v", p)
+	}
+	if got.Points[1].Elevation != nil {
+		t.Errorf("Points[1].Elevation = %v, want nil", *got.Points[1].Elevation)
+	}
+}
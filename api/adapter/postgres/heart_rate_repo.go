@@ -18,6 +18,9 @@ func NewHeartRateRepo(pool *pgxpool.Pool) *HeartRateRepo {
 }
 
 func (r *HeartRateRepo) BulkUpsert(ctx context.Context, samples []entity.HeartRateSample) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -38,6 +41,9 @@ func (r *HeartRateRepo) BulkUpsert(ctx context.Context, samples []entity.HeartRa
 }
 
 func (r *HeartRateRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.HeartRateSample, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
 		`SELECT time, bpm, confidence FROM heart_rate_intraday
 		 WHERE time BETWEEN $1 AND $2 ORDER BY time`, from, to)
@@ -56,3 +62,29 @@ func (r *HeartRateRepo) ListRange(ctx context.Context, from, to time.Time) ([]en
 	}
 	return samples, rows.Err()
 }
+
+func (r *HeartRateRepo) ListRangeAggregated(ctx context.Context, from, to time.Time, bucketMin int) ([]entity.HeartRateBucket, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT time_bucket(make_interval(mins => $3), time) AS bucket,
+		        AVG(bpm)::real, MIN(bpm)::real, MAX(bpm)::real, COUNT(*)
+		 FROM heart_rate_intraday
+		 WHERE time >= $1 AND time < $2
+		 GROUP BY bucket ORDER BY bucket`, from, to, bucketMin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []entity.HeartRateBucket
+	for rows.Next() {
+		var b entity.HeartRateBucket
+		if err := rows.Scan(&b.BucketTime, &b.AvgBPM, &b.MinBPM, &b.MaxBPM, &b.SampleCount); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"vitametron/api/domain/entity"
+)
+
+type HRVSampleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewHRVSampleRepo(pool *pgxpool.Pool) *HRVSampleRepo {
+	return &HRVSampleRepo{pool: pool}
+}
+
+func (r *HRVSampleRepo) BulkUpsert(ctx context.Context, samples []entity.HRVSample) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, s := range samples {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO hrv_intraday (time, rmssd)
+			 VALUES ($1, $2)
+			 ON CONFLICT (time) DO UPDATE SET rmssd=$2`,
+			s.Time, s.RMSSD)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *HRVSampleRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.HRVSample, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT time, rmssd FROM hrv_intraday
+		 WHERE time BETWEEN $1 AND $2 ORDER BY time`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []entity.HRVSample
+	for rows.Next() {
+		var s entity.HRVSample
+		if err := rows.Scan(&s.Time, &s.RMSSD); err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
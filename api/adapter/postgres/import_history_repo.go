@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"vitametron/api/domain/entity"
+)
+
+type ImportHistoryRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewImportHistoryRepo(pool *pgxpool.Pool) *ImportHistoryRepo {
+	return &ImportHistoryRepo{pool: pool}
+}
+
+func (r *ImportHistoryRepo) SaveDevices(ctx context.Context, jobID, source string, devices []entity.DeviceInfo) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if devices == nil {
+		devices = []entity.DeviceInfo{}
+	}
+	devicesJSON, err := json.Marshal(devices)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx,
+		`INSERT INTO import_history (job_id, source, devices_json)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (job_id) DO UPDATE SET source=$2, devices_json=$3`,
+		jobID, source, devicesJSON)
+	return err
+}
+
+func (r *ImportHistoryRepo) GetDevices(ctx context.Context, jobID string) ([]entity.DeviceInfo, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var devicesJSON []byte
+	err := r.pool.QueryRow(ctx,
+		`SELECT devices_json FROM import_history WHERE job_id = $1`, jobID).Scan(&devicesJSON)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	devices := []entity.DeviceInfo{}
+	if err := json.Unmarshal(devicesJSON, &devices); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
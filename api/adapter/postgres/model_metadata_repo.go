@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"vitametron/api/domain/entity"
+)
+
+// modelAnomaly is the ml_model_metadata.model value of anomaly model runs.
+const modelAnomaly = "anomaly"
+
+type ModelMetadataRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewModelMetadataRepo(pool *pgxpool.Pool) *ModelMetadataRepo {
+	return &ModelMetadataRepo{pool: pool}
+}
+
+func (r *ModelMetadataRepo) SaveAnomalyTrain(ctx context.Context, result *entity.AnomalyTrainResult) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	features := result.FeatureNames
+	if features == nil {
+		features = []string{}
+	}
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO ml_model_metadata (
+			model, model_version, training_days_used, contamination, pot_threshold, feature_names, message
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		modelAnomaly, result.ModelVersion, result.TrainingDaysUsed, result.Contamination,
+		result.PotThreshold, features, result.Message)
+	return err
+}
+
+const anomalyTrainColumns = `id, trained_at, model_version, training_days_used, contamination,
+	pot_threshold, feature_names, message`
+
+func scanAnomalyTrainRun(row pgx.Row) (*entity.AnomalyTrainRun, error) {
+	var run entity.AnomalyTrainRun
+	err := row.Scan(&run.ID, &run.TrainedAt, &run.ModelVersion, &run.TrainingDaysUsed, &run.Contamination,
+		&run.PotThreshold, &run.FeatureNames, &run.Message)
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *ModelMetadataRepo) LatestAnomalyTrain(ctx context.Context) (*entity.AnomalyTrainRun, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	run, err := scanAnomalyTrainRun(r.pool.QueryRow(ctx,
+		`SELECT `+anomalyTrainColumns+` FROM ml_model_metadata
+		 WHERE model = $1 ORDER BY trained_at DESC, id DESC LIMIT 1`, modelAnomaly))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return run, err
+}
+
+func (r *ModelMetadataRepo) ListAnomalyTrains(ctx context.Context) ([]entity.AnomalyTrainRun, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+anomalyTrainColumns+` FROM ml_model_metadata
+		 WHERE model = $1 ORDER BY trained_at DESC, id DESC`, modelAnomaly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []entity.AnomalyTrainRun{}
+	for rows.Next() {
+		run, err := scanAnomalyTrainRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	return runs, rows.Err()
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PKCEStateRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPKCEStateRepo(pool *pgxpool.Pool) *PKCEStateRepo {
+	return &PKCEStateRepo{pool: pool}
+}
+
+func (r *PKCEStateRepo) Save(ctx context.Context, state, verifier string, expiresAt time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO oauth_pkce_states (state, verifier, expires_at) VALUES ($1, $2, $3)`,
+		state, verifier, expiresAt)
+	return err
+}
+
+func (r *PKCEStateRepo) Take(ctx context.Context, state string) (string, bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var verifier string
+	err := r.pool.QueryRow(ctx,
+		`DELETE FROM oauth_pkce_states WHERE state = $1 AND expires_at > NOW() RETURNING verifier`,
+		state).Scan(&verifier)
+	if err == pgx.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return verifier, true, nil
+}
+
+func (r *PKCEStateRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM oauth_pkce_states WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPKCEStateRepo_TakeIsSingleUseAndHonoursExpiry(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewPKCEStateRepo(pool)
+	ctx := context.Background()
+
+	t.Cleanup(func() { pool.Exec(ctx, `DELETE FROM oauth_pkce_states WHERE state LIKE 'test-%'`) })
+
+	if err := repo.Save(ctx, "test-live", "verifier", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := repo.Save(ctx, "test-expired", "verifier", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if v, ok, err := repo.Take(ctx, "test-live"); err != nil || !ok || v != "verifier" {
+		t.Fatalf("Take(live) = %q, %v, %v", v, ok, err)
+	}
+	if _, ok, err := repo.Take(ctx, "test-live"); err != nil || ok {
+		t.Errorf("second Take(live) = %v, %v; want false, nil", ok, err)
+	}
+	if _, ok, err := repo.Take(ctx, "test-expired"); err != nil || ok {
+		t.Errorf("Take(expired) = %v, %v; want false, nil", ok, err)
+	}
+
+	n, err := repo.DeleteExpired(ctx)
+	if err != nil {
+		t.Fatalf("DeleteExpired() error = %v", err)
+	}
+	if n < 1 {
+		t.Errorf("DeleteExpired() = %d, want at least 1", n)
+	}
+}
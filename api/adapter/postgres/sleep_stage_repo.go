@@ -18,6 +18,9 @@ func NewSleepStageRepo(pool *pgxpool.Pool) *SleepStageRepo {
 }
 
 func (r *SleepStageRepo) BulkUpsert(ctx context.Context, stages []entity.SleepStage) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -26,10 +29,10 @@ func (r *SleepStageRepo) BulkUpsert(ctx context.Context, stages []entity.SleepSt
 
 	for _, s := range stages {
 		_, err := tx.Exec(ctx,
-			`INSERT INTO sleep_stages (time, stage, seconds, log_id)
-			 VALUES ($1, $2, $3, $4)
-			 ON CONFLICT (time) DO UPDATE SET stage=$2, seconds=$3, log_id=$4`,
-			s.Time, s.Stage, s.Seconds, s.LogID)
+			`INSERT INTO sleep_stages (time, stage, seconds, log_id, source)
+			 VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (time) DO UPDATE SET stage=$2, seconds=$3, log_id=$4, source=$5`,
+			s.Time, s.Stage, s.Seconds, s.LogID, sleepStageSource(s))
 		if err != nil {
 			return err
 		}
@@ -38,10 +41,13 @@ func (r *SleepStageRepo) BulkUpsert(ctx context.Context, stages []entity.SleepSt
 }
 
 func (r *SleepStageRepo) ListByDate(ctx context.Context, date time.Time) ([]entity.SleepStage, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	end := start.Add(24 * time.Hour)
 	rows, err := r.pool.Query(ctx,
-		`SELECT time, stage, seconds, log_id FROM sleep_stages
+		`SELECT time, stage, seconds, log_id, source FROM sleep_stages
 		 WHERE time >= $1 AND time < $2 ORDER BY time`, start, end)
 	if err != nil {
 		return nil, err
@@ -51,7 +57,7 @@ func (r *SleepStageRepo) ListByDate(ctx context.Context, date time.Time) ([]enti
 	var stages []entity.SleepStage
 	for rows.Next() {
 		var s entity.SleepStage
-		if err := rows.Scan(&s.Time, &s.Stage, &s.Seconds, &s.LogID); err != nil {
+		if err := rows.Scan(&s.Time, &s.Stage, &s.Seconds, &s.LogID, &s.Source); err != nil {
 			return nil, err
 		}
 		stages = append(stages, s)
@@ -60,8 +66,11 @@ func (r *SleepStageRepo) ListByDate(ctx context.Context, date time.Time) ([]enti
 }
 
 func (r *SleepStageRepo) ListByTimeRange(ctx context.Context, from, to time.Time) ([]entity.SleepStage, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
-		`SELECT time, stage, seconds, log_id FROM sleep_stages
+		`SELECT time, stage, seconds, log_id, source FROM sleep_stages
 		 WHERE time >= $1 AND time < $2 ORDER BY time`, from, to)
 	if err != nil {
 		return nil, err
@@ -71,10 +80,19 @@ func (r *SleepStageRepo) ListByTimeRange(ctx context.Context, from, to time.Time
 	var stages []entity.SleepStage
 	for rows.Next() {
 		var s entity.SleepStage
-		if err := rows.Scan(&s.Time, &s.Stage, &s.Seconds, &s.LogID); err != nil {
+		if err := rows.Scan(&s.Time, &s.Stage, &s.Seconds, &s.LogID, &s.Source); err != nil {
 			return nil, err
 		}
 		stages = append(stages, s)
 	}
 	return stages, rows.Err()
 }
+
+// sleepStageSource defaults untagged stages to Fitbit, matching the column
+// default for rows written before sources were recorded.
+func sleepStageSource(s entity.SleepStage) string {
+	if s.Source == "" {
+		return entity.SleepStageSourceFitbit
+	}
+	return s.Source
+}
@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"vitametron/api/infrastructure/database"
+)
+
+var queryTimeout = database.DefaultQueryTimeout
+
+// SetQueryTimeout sets the per-operation timeout applied by every repository.
+func SetQueryTimeout(d time.Duration) {
+	queryTimeout = d
+}
+
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return database.WithQueryTimeout(ctx, queryTimeout)
+}
@@ -18,6 +18,9 @@ func NewTokenRepo(pool *pgxpool.Pool) *TokenRepo {
 }
 
 func (r *TokenRepo) Get(ctx context.Context, provider string) (accessToken, refreshToken []byte, expiresAt time.Time, err error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	err = r.pool.QueryRow(ctx,
 		`SELECT access_token, refresh_token, expires_at
 		 FROM oauth_tokens WHERE provider = $1`, provider).
@@ -29,6 +32,9 @@ func (r *TokenRepo) Get(ctx context.Context, provider string) (accessToken, refr
 }
 
 func (r *TokenRepo) Save(ctx context.Context, provider string, accessToken, refreshToken []byte, expiresAt time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	_, err := r.pool.Exec(ctx,
 		`INSERT INTO oauth_tokens (provider, access_token, refresh_token, expires_at, updated_at)
 		 VALUES ($1, $2, $3, $4, NOW())
@@ -42,6 +48,9 @@ func (r *TokenRepo) Save(ctx context.Context, provider string, accessToken, refr
 }
 
 func (r *TokenRepo) Delete(ctx context.Context, provider string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	_, err := r.pool.Exec(ctx,
 		`DELETE FROM oauth_tokens WHERE provider = $1`, provider)
 	return err
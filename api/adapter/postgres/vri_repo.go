@@ -19,6 +19,9 @@ func NewVRIRepo(pool *pgxpool.Pool) *VRIRepo {
 }
 
 func (r *VRIRepo) GetByDate(ctx context.Context, date time.Time) (*entity.VRIScore, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	row := r.pool.QueryRow(ctx,
 		`SELECT date, vri_score, vri_confidence,
 			z_ln_rmssd, z_resting_hr, z_sleep_duration, z_sri, z_spo2, z_deep_sleep, z_br,
@@ -40,6 +43,9 @@ func (r *VRIRepo) GetByDate(ctx context.Context, date time.Time) (*entity.VRISco
 }
 
 func (r *VRIRepo) ListRange(ctx context.Context, from, to time.Time) ([]entity.VRIScore, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
 		`SELECT date, vri_score, vri_confidence,
 			z_ln_rmssd, z_resting_hr, z_sleep_duration, z_sri, z_spo2, z_deep_sleep, z_br,
@@ -17,24 +17,30 @@ func NewWHO5Repo(pool *pgxpool.Pool) *WHO5Repo {
 }
 
 func (r *WHO5Repo) Create(ctx context.Context, a *entity.WHO5Assessment) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	return r.pool.QueryRow(ctx,
 		`INSERT INTO who5_assessments (assessed_at, period_start, period_end, item1, item2, item3, item4, item5, note)
 		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		 RETURNING id, raw_score, percentage, created_at`,
+		 RETURNING id, raw_score, percentage, depression_screening_flag, score_interpretation, created_at`,
 		a.AssessedAt, a.PeriodStart, a.PeriodEnd,
 		a.Items[0], a.Items[1], a.Items[2], a.Items[3], a.Items[4],
 		a.Note).
-		Scan(&a.ID, &a.RawScore, &a.Percentage, &a.CreatedAt)
+		Scan(&a.ID, &a.RawScore, &a.Percentage, &a.DepressionScreeningFlag, &a.ScoreInterpretation, &a.CreatedAt)
 }
 
 func (r *WHO5Repo) GetByID(ctx context.Context, id int64) (*entity.WHO5Assessment, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	var a entity.WHO5Assessment
 	err := r.pool.QueryRow(ctx,
-		`SELECT id, assessed_at, period_start, period_end, item1, item2, item3, item4, item5, raw_score, percentage, note, created_at
+		`SELECT id, assessed_at, period_start, period_end, item1, item2, item3, item4, item5, raw_score, percentage, depression_screening_flag, score_interpretation, note, created_at
 		 FROM who5_assessments WHERE id = $1`, id).
 		Scan(&a.ID, &a.AssessedAt, &a.PeriodStart, &a.PeriodEnd,
 			&a.Items[0], &a.Items[1], &a.Items[2], &a.Items[3], &a.Items[4],
-			&a.RawScore, &a.Percentage, &a.Note, &a.CreatedAt)
+			&a.RawScore, &a.Percentage, &a.DepressionScreeningFlag, &a.ScoreInterpretation, &a.Note, &a.CreatedAt)
 	if err != nil {
 		if err.Error() == "no rows in result set" {
 			return nil, nil
@@ -45,13 +51,16 @@ func (r *WHO5Repo) GetByID(ctx context.Context, id int64) (*entity.WHO5Assessmen
 }
 
 func (r *WHO5Repo) GetLatest(ctx context.Context) (*entity.WHO5Assessment, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	var a entity.WHO5Assessment
 	err := r.pool.QueryRow(ctx,
-		`SELECT id, assessed_at, period_start, period_end, item1, item2, item3, item4, item5, raw_score, percentage, note, created_at
+		`SELECT id, assessed_at, period_start, period_end, item1, item2, item3, item4, item5, raw_score, percentage, depression_screening_flag, score_interpretation, note, created_at
 		 FROM who5_assessments ORDER BY assessed_at DESC LIMIT 1`).
 		Scan(&a.ID, &a.AssessedAt, &a.PeriodStart, &a.PeriodEnd,
 			&a.Items[0], &a.Items[1], &a.Items[2], &a.Items[3], &a.Items[4],
-			&a.RawScore, &a.Percentage, &a.Note, &a.CreatedAt)
+			&a.RawScore, &a.Percentage, &a.DepressionScreeningFlag, &a.ScoreInterpretation, &a.Note, &a.CreatedAt)
 	if err != nil {
 		if err.Error() == "no rows in result set" {
 			return nil, nil
@@ -62,8 +71,11 @@ func (r *WHO5Repo) GetLatest(ctx context.Context) (*entity.WHO5Assessment, error
 }
 
 func (r *WHO5Repo) List(ctx context.Context, limit, offset int) ([]entity.WHO5Assessment, int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx,
-		`SELECT id, assessed_at, period_start, period_end, item1, item2, item3, item4, item5, raw_score, percentage, note, created_at, COUNT(*) OVER() AS total
+		`SELECT id, assessed_at, period_start, period_end, item1, item2, item3, item4, item5, raw_score, percentage, depression_screening_flag, score_interpretation, note, created_at, COUNT(*) OVER() AS total
 		 FROM who5_assessments ORDER BY assessed_at DESC LIMIT $1 OFFSET $2`, limit, offset)
 	if err != nil {
 		return nil, 0, err
@@ -76,7 +88,7 @@ func (r *WHO5Repo) List(ctx context.Context, limit, offset int) ([]entity.WHO5As
 		var a entity.WHO5Assessment
 		if err := rows.Scan(&a.ID, &a.AssessedAt, &a.PeriodStart, &a.PeriodEnd,
 			&a.Items[0], &a.Items[1], &a.Items[2], &a.Items[3], &a.Items[4],
-			&a.RawScore, &a.Percentage, &a.Note, &a.CreatedAt, &total); err != nil {
+			&a.RawScore, &a.Percentage, &a.DepressionScreeningFlag, &a.ScoreInterpretation, &a.Note, &a.CreatedAt, &total); err != nil {
 			return nil, 0, err
 		}
 		assessments = append(assessments, a)
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-VitaMetron-Signature"
+	JobIDHeader     = "X-VitaMetron-Job-ID"
+)
+
+// Client POSTs JSON payloads to a webhook URL, signing each body with
+// HMAC-SHA256 so the receiver can verify its origin.
+type Client struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+func New(url, secret string) *Client {
+	return &Client{
+		url:        url,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Sign returns the signature header value for body: "sha256=<hex digest>".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Client) Send(ctx context.Context, jobID string, payload any) error {
+	if c.url == "" {
+		return errors.New("webhook: URL not configured")
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("webhook: marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(JobIDHeader, jobID)
+	req.Header.Set(SignatureHeader, Sign(c.secret, body))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: post: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook: returned %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Send_SignsBody(t *testing.T) {
+	var gotSig, gotJobID string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get(SignatureHeader)
+		gotJobID = r.Header.Get(JobIDHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "s3cret")
+	if err := c.Send(context.Background(), "job-1", map[string]int{"anomaly_count": 2}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if gotJobID != "job-1" {
+		t.Errorf("job ID header = %q, want %q", gotJobID, "job-1")
+	}
+	if want := Sign("s3cret", gotBody); gotSig != want {
+		t.Errorf("signature = %q, want %q", gotSig, want)
+	}
+	if string(gotBody) != `{"anomaly_count":2}` {
+		t.Errorf("body = %s", gotBody)
+	}
+}
+
+func TestClient_Send_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	if err := New(srv.URL, "k").Send(context.Background(), "job", struct{}{}); err == nil {
+		t.Error("Send() to failing endpoint: expected error")
+	}
+	if err := New("", "k").Send(context.Background(), "job", struct{}{}); err == nil {
+		t.Error("Send() without URL: expected error")
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n 'hello' | openssl dgst -sha256 -hmac key
+	want := "sha256=9307b3b915efb5171ff14d8cb55fbcc798c6c0ef1456d66ded1a6aa723a58b7b"
+	if got := Sign("key", []byte("hello")); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
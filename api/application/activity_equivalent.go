@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+const (
+	// defaultStrideM is Fitbit's default walking stride when height is unknown.
+	defaultStrideM = 0.762
+	// walkingCadence is the steps/min threshold for moderate-intensity walking.
+	walkingCadence = 100.0
+	// walkingMET is the compendium MET for walking at ~3 mph.
+	walkingMET = 3.5
+	// defaultWeightKG is used for calorie estimates when the profile weight is unknown.
+	defaultWeightKG = 70.0
+)
+
+// ActivityEquivalentCalculator converts step counts into estimated active
+// minutes, calories and distance, assuming brisk walking at Fitbit defaults.
+type ActivityEquivalentCalculator struct {
+	summaryRepo port.DailySummaryRepository
+	weightKG    float64
+}
+
+// NewActivityEquivalentCalculator creates a calculator. A weightKG of zero
+// falls back to defaultWeightKG.
+func NewActivityEquivalentCalculator(summaryRepo port.DailySummaryRepository, weightKG float64) *ActivityEquivalentCalculator {
+	if weightKG <= 0 {
+		weightKG = defaultWeightKG
+	}
+	return &ActivityEquivalentCalculator{summaryRepo: summaryRepo, weightKG: weightKG}
+}
+
+// Convert estimates the activity equivalent of steps.
+func (c *ActivityEquivalentCalculator) Convert(steps int) entity.ActivityEquivalent {
+	minutes := float64(steps) / walkingCadence
+	calories := walkingMET * c.weightKG * minutes / 60
+	distanceKM := float64(steps) * defaultStrideM / 1000
+
+	return entity.ActivityEquivalent{
+		Steps:                  steps,
+		ActiveMinutesEstimated: int(math.Round(minutes)),
+		CaloriesEstimated:      int(math.Round(calories)),
+		DistanceKMEstimated:    float32(math.Round(distanceKM*100) / 100),
+	}
+}
+
+// ConvertRange applies Convert to each stored day's actual step count.
+func (c *ActivityEquivalentCalculator) ConvertRange(ctx context.Context, from, to time.Time) ([]entity.ActivityEquivalent, error) {
+	summaries, err := c.summaryRepo.ListRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]entity.ActivityEquivalent, 0, len(summaries))
+	for _, s := range summaries {
+		eq := c.Convert(s.Steps)
+		eq.Date = s.Date
+		result = append(result, eq)
+	}
+	return result, nil
+}
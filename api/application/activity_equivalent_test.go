@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestActivityEquivalentCalculator_Convert(t *testing.T) {
+	tests := []struct {
+		name         string
+		weightKG     float64
+		steps        int
+		wantMinutes  int
+		wantCalories int
+		wantKM       float32
+	}{
+		{"10k steps default weight", 0, 10000, 100, 408, 7.62},
+		{"10k steps 60kg", 60, 10000, 100, 350, 7.62},
+		{"zero steps", 70, 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewActivityEquivalentCalculator(nil, tt.weightKG).Convert(tt.steps)
+			if got.Steps != tt.steps {
+				t.Errorf("Steps = %d, want %d", got.Steps, tt.steps)
+			}
+			if got.ActiveMinutesEstimated != tt.wantMinutes {
+				t.Errorf("ActiveMinutesEstimated = %d, want %d", got.ActiveMinutesEstimated, tt.wantMinutes)
+			}
+			if got.CaloriesEstimated != tt.wantCalories {
+				t.Errorf("CaloriesEstimated = %d, want %d", got.CaloriesEstimated, tt.wantCalories)
+			}
+			if got.DistanceKMEstimated != tt.wantKM {
+				t.Errorf("DistanceKMEstimated = %v, want %v", got.DistanceKMEstimated, tt.wantKM)
+			}
+		})
+	}
+}
+
+func TestActivityEquivalentCalculator_ConvertRange(t *testing.T) {
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	repo := &mocks.MockDailySummaryRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+			return []entity.DailySummary{{Date: day1, Steps: 5000}, {Date: day2, Steps: 12000}}, nil
+		},
+	}
+
+	got, err := NewActivityEquivalentCalculator(repo, 70).ConvertRange(context.Background(), day1, day2)
+	if err != nil {
+		t.Fatalf("ConvertRange() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+	if !got[0].Date.Equal(day1) || got[0].Steps != 5000 || got[0].ActiveMinutesEstimated != 50 {
+		t.Errorf("got[0] = %+v", got[0])
+	}
+	if !got[1].Date.Equal(day2) || got[1].Steps != 12000 || got[1].ActiveMinutesEstimated != 120 {
+		t.Errorf("got[1] = %+v", got[1])
+	}
+}
@@ -0,0 +1,101 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// vasMax is the top of the 0-100 VAS scale.
+const vasMax = 100
+
+// ErrUnknownVASMetric is returned for a metric name not in VASMetrics.
+var ErrUnknownVASMetric = errors.New("metric must be one of overall_vas, mood_vas, energy_vas, sleep_quality_vas, stress_vas")
+
+// vasFields reads each VAS metric from a log; optional metrics report false
+// when the log left them blank.
+var vasFields = map[string]func(l *entity.ConditionLog) (int, bool){
+	"overall_vas":       func(l *entity.ConditionLog) (int, bool) { return l.OverallVAS, true },
+	"mood_vas":          func(l *entity.ConditionLog) (int, bool) { return derefInt(l.MoodVAS) },
+	"energy_vas":        func(l *entity.ConditionLog) (int, bool) { return derefInt(l.EnergyVAS) },
+	"sleep_quality_vas": func(l *entity.ConditionLog) (int, bool) { return derefInt(l.SleepQualityVAS) },
+	"stress_vas":        func(l *entity.ConditionLog) (int, bool) { return derefInt(l.StressVAS) },
+}
+
+func derefInt(p *int) (int, bool) {
+	if p == nil {
+		return 0, false
+	}
+	return *p, true
+}
+
+// VASHistogramAnalyzer summarises how condition VAS scores are distributed.
+type VASHistogramAnalyzer struct {
+	repo port.ConditionRepository
+}
+
+func NewVASHistogramAnalyzer(repo port.ConditionRepository) *VASHistogramAnalyzer {
+	return &VASHistogramAnalyzer{repo: repo}
+}
+
+// Histogram buckets metric over the logs in [from, to] into bins equal-width
+// buckets spanning 0-100.
+func (a *VASHistogramAnalyzer) Histogram(ctx context.Context, metric string, bins int, from, to time.Time) ([]entity.HistogramBucket, error) {
+	field, ok := vasFields[metric]
+	if !ok {
+		return nil, ErrUnknownVASMetric
+	}
+
+	logs, err := a.repo.ListRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]int, 0, len(logs))
+	for i := range logs {
+		if v, ok := field(&logs[i]); ok {
+			values = append(values, v)
+		}
+	}
+	return computeHistogram(values, bins), nil
+}
+
+// computeHistogram splits 0-100 into bins equal-width buckets with integer
+// inclusive edges; bucket i starts at ceil(i*100/bins) and the last bucket
+// includes 100. Out-of-range values are clamped into the end buckets.
+func computeHistogram(values []int, bins int) []entity.HistogramBucket {
+	if bins < 1 {
+		return nil
+	}
+
+	buckets := make([]entity.HistogramBucket, bins)
+	for i := range buckets {
+		buckets[i].BucketStart = (i*vasMax + bins - 1) / bins
+	}
+	for i := range buckets {
+		if i == bins-1 {
+			buckets[i].BucketEnd = vasMax
+		} else {
+			buckets[i].BucketEnd = buckets[i+1].BucketStart - 1
+		}
+	}
+
+	for _, v := range values {
+		i := v * bins / vasMax
+		if i < 0 {
+			i = 0
+		}
+		if i >= bins {
+			i = bins - 1
+		}
+		buckets[i].Count++
+	}
+	if len(values) > 0 {
+		for i := range buckets {
+			buckets[i].Frequency = float32(buckets[i].Count) / float32(len(values))
+		}
+	}
+	return buckets
+}
@@ -0,0 +1,119 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestComputeHistogram(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		buckets := computeHistogram(nil, 10)
+		if len(buckets) != 10 {
+			t.Fatalf("len = %d, want 10", len(buckets))
+		}
+		for i, b := range buckets {
+			if b.Count != 0 || b.Frequency != 0 {
+				t.Errorf("[%d] = %+v, want zero count and frequency", i, b)
+			}
+		}
+		if buckets[0].BucketStart != 0 || buckets[0].BucketEnd != 9 || buckets[9].BucketStart != 90 || buckets[9].BucketEnd != 100 {
+			t.Errorf("edges = %+v .. %+v", buckets[0], buckets[9])
+		}
+	})
+
+	t.Run("single value", func(t *testing.T) {
+		buckets := computeHistogram([]int{42}, 10)
+		for i, b := range buckets {
+			want := 0
+			if i == 4 {
+				want = 1
+			}
+			if b.Count != want {
+				t.Errorf("[%d].Count = %d, want %d", i, b.Count, want)
+			}
+		}
+		if buckets[4].Frequency != 1 {
+			t.Errorf("Frequency = %v, want 1", buckets[4].Frequency)
+		}
+	})
+
+	t.Run("uniform", func(t *testing.T) {
+		values := make([]int, 0, 101)
+		for v := 0; v <= 100; v++ {
+			values = append(values, v)
+		}
+		buckets := computeHistogram(values, 4)
+		wantEdges := [][2]int{{0, 24}, {25, 49}, {50, 74}, {75, 100}}
+		wantCounts := []int{25, 25, 25, 26} // 100 lands in the last bucket
+		total := 0
+		for i, b := range buckets {
+			if b.BucketStart != wantEdges[i][0] || b.BucketEnd != wantEdges[i][1] {
+				t.Errorf("[%d] edges = %d-%d, want %d-%d", i, b.BucketStart, b.BucketEnd, wantEdges[i][0], wantEdges[i][1])
+			}
+			if b.Count != wantCounts[i] {
+				t.Errorf("[%d].Count = %d, want %d", i, b.Count, wantCounts[i])
+			}
+			if want := float32(wantCounts[i]) / 101; b.Frequency != want {
+				t.Errorf("[%d].Frequency = %v, want %v", i, b.Frequency, want)
+			}
+			total += b.Count
+		}
+		if total != 101 {
+			t.Errorf("total = %d, want 101", total)
+		}
+	})
+
+	t.Run("uneven width", func(t *testing.T) {
+		// Every value must fall inside its bucket's edges.
+		buckets := computeHistogram([]int{33, 34, 66, 67}, 3)
+		wantEdges := [][2]int{{0, 33}, {34, 66}, {67, 100}}
+		for i, b := range buckets {
+			if b.BucketStart != wantEdges[i][0] || b.BucketEnd != wantEdges[i][1] {
+				t.Errorf("[%d] edges = %d-%d, want %d-%d", i, b.BucketStart, b.BucketEnd, wantEdges[i][0], wantEdges[i][1])
+			}
+		}
+		if buckets[0].Count != 1 || buckets[1].Count != 2 || buckets[2].Count != 1 {
+			t.Errorf("counts = %d %d %d, want 1 2 1", buckets[0].Count, buckets[1].Count, buckets[2].Count)
+		}
+	})
+}
+
+func TestVASHistogramAnalyzer_Histogram(t *testing.T) {
+	mood := 80
+	repo := &mocks.MockConditionRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.ConditionLog, error) {
+			return []entity.ConditionLog{
+				{OverallVAS: 10, MoodVAS: &mood},
+				{OverallVAS: 95},
+			}, nil
+		},
+	}
+	a := NewVASHistogramAnalyzer(repo)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	overall, err := a.Histogram(context.Background(), "overall_vas", 2, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("Histogram() error = %v", err)
+	}
+	if overall[0].Count != 1 || overall[1].Count != 1 {
+		t.Errorf("overall counts = %d %d, want 1 1", overall[0].Count, overall[1].Count)
+	}
+
+	// Logs without an optional metric are skipped rather than counted as 0.
+	moods, err := a.Histogram(context.Background(), "mood_vas", 2, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("Histogram() error = %v", err)
+	}
+	if moods[0].Count != 0 || moods[1].Count != 1 || moods[1].Frequency != 1 {
+		t.Errorf("mood buckets = %+v", moods)
+	}
+
+	if _, err := a.Histogram(context.Background(), "steps", 2, from, from); !errors.Is(err, ErrUnknownVASMetric) {
+		t.Errorf("Histogram(steps) error = %v, want ErrUnknownVASMetric", err)
+	}
+}
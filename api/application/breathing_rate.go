@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+const (
+	// minBreathingRatePairs is the fewest nights for a correlation or a
+	// disturbance threshold.
+	minBreathingRatePairs = 7
+	// disturbancePercentile is the BRFullSleep percentile above which a
+	// night is flagged as a possible respiratory disturbance.
+	disturbancePercentile = 90
+)
+
+// brStages pairs each stage breathing rate with that stage's duration.
+var brStages = []struct {
+	name    string
+	rate    func(s *entity.DailySummary) *float32
+	minutes func(s *entity.DailySummary) int
+}{
+	{"deep", func(s *entity.DailySummary) *float32 { return s.BRDeepSleep }, func(s *entity.DailySummary) int { return s.SleepDeepMin }},
+	{"light", func(s *entity.DailySummary) *float32 { return s.BRLightSleep }, func(s *entity.DailySummary) int { return s.SleepLightMin }},
+	{"rem", func(s *entity.DailySummary) *float32 { return s.BRREMSleep }, func(s *entity.DailySummary) int { return s.SleepREMMin }},
+}
+
+// BreathingRateAnalyzer looks at nightly breathing rate across sleep stages.
+type BreathingRateAnalyzer struct {
+	summaryRepo port.DailySummaryRepository
+}
+
+func NewBreathingRateAnalyzer(summaryRepo port.DailySummaryRepository) *BreathingRateAnalyzer {
+	return &BreathingRateAnalyzer{summaryRepo: summaryRepo}
+}
+
+// Analyze returns the breathing rate series for [from, to], the correlation
+// of each stage's rate with its duration, and nights whose full-sleep rate
+// exceeds the range's 90th percentile.
+func (a *BreathingRateAnalyzer) Analyze(ctx context.Context, from, to time.Time) (*entity.BreathingRateAnalysis, error) {
+	summaries, err := a.summaryRepo.ListRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.BreathingRateAnalysis{
+		From:             from,
+		To:               to,
+		Days:             make([]entity.BreathingRateDay, 0, len(summaries)),
+		DisturbanceDates: []string{},
+		MinPairs:         minBreathingRatePairs,
+	}
+
+	var full []float64
+	for i := range summaries {
+		s := &summaries[i]
+		if s.BRFullSleep == nil && s.BRDeepSleep == nil && s.BRLightSleep == nil && s.BRREMSleep == nil {
+			continue
+		}
+		result.Days = append(result.Days, entity.BreathingRateDay{
+			Date:       s.Date.Format("2006-01-02"),
+			FullSleep:  s.BRFullSleep,
+			DeepSleep:  s.BRDeepSleep,
+			LightSleep: s.BRLightSleep,
+			REMSleep:   s.BRREMSleep,
+		})
+		if s.BRFullSleep != nil {
+			full = append(full, float64(*s.BRFullSleep))
+		}
+	}
+
+	for _, stage := range brStages {
+		var rates, minutes []float64
+		for i := range summaries {
+			r := stage.rate(&summaries[i])
+			m := stage.minutes(&summaries[i])
+			if r == nil || m <= 0 {
+				continue
+			}
+			rates = append(rates, float64(*r))
+			minutes = append(minutes, float64(m))
+		}
+		corr := entity.BreathingRateStageCorrelation{Stage: stage.name, Pairs: len(rates)}
+		if len(rates) >= minBreathingRatePairs {
+			if r, ok := pearson(rates, minutes); ok {
+				r = math.Round(r*1000) / 1000
+				corr.Correlation = &r
+			}
+		}
+		result.Correlations = append(result.Correlations, corr)
+	}
+
+	if len(full) >= minBreathingRatePairs {
+		sort.Float64s(full)
+		threshold := percentile(full, disturbancePercentile)
+		rounded := math.Round(threshold*100) / 100
+		result.DisturbanceThreshold = &rounded
+		for i := range result.Days {
+			d := &result.Days[i]
+			if d.FullSleep != nil && float64(*d.FullSleep) > threshold {
+				d.Disturbance = true
+				result.DisturbanceDates = append(result.DisturbanceDates, d.Date)
+			}
+		}
+	}
+	return result, nil
+}
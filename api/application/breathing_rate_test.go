@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestBreathingRateAnalyzer_Analyze(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Ten nights: deep BR rises with deep minutes, REM BR falls with REM
+	// minutes, light minutes are missing on night 0. Night 9 breathes fastest.
+	var summaries []entity.DailySummary
+	for i := 0; i < 10; i++ {
+		s := entity.DailySummary{
+			Date:          from.AddDate(0, 0, i),
+			BRFullSleep:   f32(14 + float32(i)*0.1),
+			BRDeepSleep:   f32(13 + float32(i)*0.2),
+			BRLightSleep:  f32(14),
+			BRREMSleep:    f32(16 - float32(i)*0.2),
+			SleepDeepMin:  60 + i*5,
+			SleepLightMin: 200 + i,
+			SleepREMMin:   80 + i*3,
+		}
+		if i == 0 {
+			s.SleepLightMin = 0
+		}
+		if i == 9 {
+			s.BRFullSleep = f32(18)
+		}
+		summaries = append(summaries, s)
+	}
+	// A night without breathing rate data is left out of the series.
+	summaries = append(summaries, entity.DailySummary{Date: from.AddDate(0, 0, 10), SleepDeepMin: 50})
+
+	repo := &mocks.MockDailySummaryRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+			return summaries, nil
+		},
+	}
+
+	result, err := NewBreathingRateAnalyzer(repo).Analyze(context.Background(), from, from.AddDate(0, 0, 10))
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if len(result.Days) != 10 {
+		t.Fatalf("len(Days) = %d, want 10", len(result.Days))
+	}
+
+	pos, neg := 1.0, -1.0
+	want := map[string]struct {
+		pairs int
+		corr  *float64
+	}{
+		"deep":  {10, &pos},
+		"light": {9, nil}, // constant light BR has no variance
+		"rem":   {10, &neg},
+	}
+	for _, c := range result.Correlations {
+		w := want[c.Stage]
+		if c.Pairs != w.pairs {
+			t.Errorf("%s Pairs = %d, want %d", c.Stage, c.Pairs, w.pairs)
+		}
+		switch {
+		case w.corr == nil && c.Correlation != nil:
+			t.Errorf("%s Correlation = %v, want nil", c.Stage, *c.Correlation)
+		case w.corr != nil && (c.Correlation == nil || *c.Correlation != *w.corr):
+			t.Errorf("%s Correlation = %v, want %v", c.Stage, c.Correlation, *w.corr)
+		}
+	}
+
+	if result.DisturbanceThreshold == nil {
+		t.Fatal("DisturbanceThreshold = nil, want a value")
+	}
+	if len(result.DisturbanceDates) != 1 || result.DisturbanceDates[0] != "2025-01-10" {
+		t.Errorf("DisturbanceDates = %v, want [2025-01-10]", result.DisturbanceDates)
+	}
+	if !result.Days[9].Disturbance || result.Days[8].Disturbance {
+		t.Error("only night 9 should be flagged as a disturbance")
+	}
+}
+
+func TestBreathingRateAnalyzer_TooFewNights(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mocks.MockDailySummaryRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+			return []entity.DailySummary{
+				{Date: day, BRFullSleep: f32(14), BRDeepSleep: f32(13), SleepDeepMin: 60},
+				{Date: day.AddDate(0, 0, 1), BRFullSleep: f32(20), BRDeepSleep: f32(15), SleepDeepMin: 80},
+			}, nil
+		},
+	}
+
+	result, err := NewBreathingRateAnalyzer(repo).Analyze(context.Background(), day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if result.DisturbanceThreshold != nil || len(result.DisturbanceDates) != 0 {
+		t.Errorf("threshold = %v, dates = %v; want none below %d nights", result.DisturbanceThreshold, result.DisturbanceDates, minBreathingRatePairs)
+	}
+	for _, c := range result.Correlations {
+		if c.Correlation != nil {
+			t.Errorf("%s Correlation = %v, want nil", c.Stage, *c.Correlation)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// ConditionSourceAnalyzer reports how condition logs were prompted, to gauge
+// reporting bias in self-reported data.
+type ConditionSourceAnalyzer struct {
+	repo port.ConditionRepository
+}
+
+func NewConditionSourceAnalyzer(repo port.ConditionRepository) *ConditionSourceAnalyzer {
+	return &ConditionSourceAnalyzer{repo: repo}
+}
+
+// Breakdown counts logs in [from, to] per source. Every known source is
+// listed, with zero counts where nothing was logged.
+func (a *ConditionSourceAnalyzer) Breakdown(ctx context.Context, from, to time.Time) (*entity.ConditionSourceBreakdown, error) {
+	counts, err := a.repo.CountBySource(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	bySource := make(map[string]int, len(counts))
+	for _, c := range counts {
+		bySource[c.Source] = c.Count
+	}
+
+	result := &entity.ConditionSourceBreakdown{From: from, To: to}
+	for _, source := range entity.ConditionSources {
+		result.Sources = append(result.Sources, entity.ConditionSourceCount{Source: source, Count: bySource[source]})
+		result.Total += bySource[source]
+	}
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestConditionSourceAnalyzer_Breakdown(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 29)
+
+	repo := &mocks.MockConditionRepository{
+		CountBySourceFunc: func(_ context.Context, gotFrom, gotTo time.Time) ([]entity.ConditionSourceCount, error) {
+			if !gotFrom.Equal(from) || !gotTo.Equal(to) {
+				t.Errorf("CountBySource(%v, %v), want (%v, %v)", gotFrom, gotTo, from, to)
+			}
+			return []entity.ConditionSourceCount{
+				{Source: entity.ConditionSourceReminder, Count: 4},
+				{Source: entity.ConditionSourceSpontaneous, Count: 10},
+			}, nil
+		},
+	}
+
+	got, err := NewConditionSourceAnalyzer(repo).Breakdown(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Breakdown() error = %v", err)
+	}
+	if got.Total != 14 {
+		t.Errorf("Total = %d, want 14", got.Total)
+	}
+	want := []entity.ConditionSourceCount{
+		{Source: entity.ConditionSourceSpontaneous, Count: 10},
+		{Source: entity.ConditionSourceReminder, Count: 4},
+		{Source: entity.ConditionSourceScheduled, Count: 0},
+	}
+	if len(got.Sources) != len(want) {
+		t.Fatalf("Sources = %v, want %v", got.Sources, want)
+	}
+	for i := range want {
+		if got.Sources[i] != want[i] {
+			t.Errorf("Sources[%d] = %v, want %v", i, got.Sources[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+// GetWeekly returns the condition logs in [from, to] rolled up by ISO week.
+func (uc *RecordConditionUseCase) GetWeekly(ctx context.Context, from, to time.Time) ([]entity.ConditionWeekly, error) {
+	logs, err := uc.repo.ListRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return groupConditionsByWeek(logs), nil
+}
+
+// vasStats accumulates one VAS field over a week.
+type vasStats struct {
+	sum      float64
+	n        int
+	min, max int
+}
+
+func (v *vasStats) add(x *int) {
+	if x == nil {
+		return
+	}
+	if v.n == 0 || *x < v.min {
+		v.min = *x
+	}
+	if v.n == 0 || *x > v.max {
+		v.max = *x
+	}
+	v.sum += float64(*x)
+	v.n++
+}
+
+// ptrs returns mean, min and max, all nil when nothing was recorded.
+func (v *vasStats) ptrs() (*float64, *float64, *float64) {
+	if v.n == 0 {
+		return nil, nil, nil
+	}
+	mean, lo, hi := v.sum/float64(v.n), float64(v.min), float64(v.max)
+	return &mean, &lo, &hi
+}
+
+type weekStats struct {
+	count                                    int
+	overall, mood, energy, sleepQual, stress vasStats
+}
+
+// isoWeekStart returns midnight JST of the Monday starting t's week.
+func isoWeekStart(t time.Time) time.Time {
+	t = t.In(jst)
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, jst)
+}
+
+// groupConditionsByWeek rolls logs up into ISO weeks in JST, oldest first.
+// Every week from the first log's to the last log's is returned, including
+// weeks without logs, so charts get an unbroken series.
+func groupConditionsByWeek(logs []entity.ConditionLog) []entity.ConditionWeekly {
+	if len(logs) == 0 {
+		return []entity.ConditionWeekly{}
+	}
+
+	byWeek := map[time.Time]*weekStats{}
+	first, last := isoWeekStart(logs[0].LoggedAt), isoWeekStart(logs[0].LoggedAt)
+	for i := range logs {
+		l := &logs[i]
+		week := isoWeekStart(l.LoggedAt)
+		if week.Before(first) {
+			first = week
+		}
+		if week.After(last) {
+			last = week
+		}
+		ws := byWeek[week]
+		if ws == nil {
+			ws = &weekStats{}
+			byWeek[week] = ws
+		}
+		ws.count++
+		ws.overall.add(&l.OverallVAS)
+		ws.mood.add(l.MoodVAS)
+		ws.energy.add(l.EnergyVAS)
+		ws.sleepQual.add(l.SleepQualityVAS)
+		ws.stress.add(l.StressVAS)
+	}
+
+	var weeks []entity.ConditionWeekly
+	for week := first; !week.After(last); week = week.AddDate(0, 0, 7) {
+		w := entity.ConditionWeekly{
+			WeekStart: week.Format("2006-01-02"),
+			WeekEnd:   week.AddDate(0, 0, 6).Format("2006-01-02"),
+		}
+		if ws := byWeek[week]; ws != nil {
+			w.LogCount = ws.count
+			w.OverallVASMean = ws.overall.sum / float64(ws.overall.n)
+			w.OverallVASMin = float64(ws.overall.min)
+			w.OverallVASMax = float64(ws.overall.max)
+			w.MoodVASMean, w.MoodVASMin, w.MoodVASMax = ws.mood.ptrs()
+			w.EnergyVASMean, w.EnergyVASMin, w.EnergyVASMax = ws.energy.ptrs()
+			w.SleepQualityVASMean, w.SleepQualityVASMin, w.SleepQualityVASMax = ws.sleepQual.ptrs()
+			w.StressVASMean, w.StressVASMin, w.StressVASMax = ws.stress.ptrs()
+		}
+		weeks = append(weeks, w)
+	}
+	return weeks
+}
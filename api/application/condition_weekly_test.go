@@ -0,0 +1,96 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestGroupConditionsByWeek(t *testing.T) {
+	vas := func(v int) *int { return &v }
+	logs := []entity.ConditionLog{
+		// Week of Mon 2024-01-29 spans January and February.
+		{LoggedAt: time.Date(2024, 1, 31, 9, 0, 0, 0, jst), OverallVAS: 40, MoodVAS: vas(30)},
+		{LoggedAt: time.Date(2024, 2, 4, 21, 0, 0, 0, jst), OverallVAS: 80, MoodVAS: vas(70), StressVAS: vas(20)},
+		// Sunday 2024-01-21 15:30 UTC is Monday 00:30 JST, so it opens the
+		// week of 2024-01-22 rather than closing the one before.
+		{LoggedAt: time.Date(2024, 1, 21, 15, 30, 0, 0, time.UTC), OverallVAS: 55},
+		// Week of 2024-02-05 has no logs; 2024-02-12 has one.
+		{LoggedAt: time.Date(2024, 2, 12, 0, 0, 0, 0, jst), OverallVAS: 60},
+	}
+
+	weeks := groupConditionsByWeek(logs)
+
+	wantStarts := []string{"2024-01-22", "2024-01-29", "2024-02-05", "2024-02-12"}
+	if len(weeks) != len(wantStarts) {
+		t.Fatalf("len = %d, want %d: %+v", len(weeks), len(wantStarts), weeks)
+	}
+	for i, want := range wantStarts {
+		if weeks[i].WeekStart != want {
+			t.Errorf("[%d].WeekStart = %s, want %s", i, weeks[i].WeekStart, want)
+		}
+	}
+
+	spanning := weeks[1]
+	if spanning.WeekEnd != "2024-02-04" || spanning.LogCount != 2 {
+		t.Errorf("spanning week = %s..%s with %d logs, want 2024-01-29..2024-02-04 with 2",
+			spanning.WeekStart, spanning.WeekEnd, spanning.LogCount)
+	}
+	if spanning.OverallVASMean != 60 || spanning.OverallVASMin != 40 || spanning.OverallVASMax != 80 {
+		t.Errorf("overall = %v/%v/%v, want 60/40/80", spanning.OverallVASMean, spanning.OverallVASMin, spanning.OverallVASMax)
+	}
+	if spanning.MoodVASMean == nil || *spanning.MoodVASMean != 50 {
+		t.Errorf("MoodVASMean = %v, want 50", spanning.MoodVASMean)
+	}
+	if spanning.StressVASMin == nil || *spanning.StressVASMin != 20 || *spanning.StressVASMax != 20 {
+		t.Errorf("StressVAS min/max = %v/%v, want 20/20", spanning.StressVASMin, spanning.StressVASMax)
+	}
+	if spanning.EnergyVASMean != nil {
+		t.Errorf("EnergyVASMean = %v, want nil when unrecorded", *spanning.EnergyVASMean)
+	}
+
+	if weeks[0].LogCount != 1 || weeks[0].OverallVASMean != 55 {
+		t.Errorf("first week = %+v, want the JST Sunday log", weeks[0])
+	}
+
+	empty := weeks[2]
+	if empty.LogCount != 0 || empty.OverallVASMean != 0 || empty.MoodVASMean != nil {
+		t.Errorf("empty week = %+v, want no data", empty)
+	}
+	if empty.WeekEnd != "2024-02-11" {
+		t.Errorf("empty WeekEnd = %s, want 2024-02-11", empty.WeekEnd)
+	}
+}
+
+func TestGroupConditionsByWeek_NoLogs(t *testing.T) {
+	weeks := groupConditionsByWeek(nil)
+	if weeks == nil || len(weeks) != 0 {
+		t.Errorf("groupConditionsByWeek(nil) = %#v, want empty non-nil slice", weeks)
+	}
+}
+
+func TestRecordCondition_GetWeekly(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, jst)
+	to := time.Date(2024, 1, 31, 23, 59, 59, 0, jst)
+	var gotFrom, gotTo time.Time
+	repo := &mocks.MockConditionRepository{
+		ListRangeFunc: func(_ context.Context, f, t time.Time) ([]entity.ConditionLog, error) {
+			gotFrom, gotTo = f, t
+			return []entity.ConditionLog{{LoggedAt: time.Date(2024, 1, 3, 12, 0, 0, 0, jst), OverallVAS: 70}}, nil
+		},
+	}
+
+	weeks, err := NewRecordConditionUseCase(repo).GetWeekly(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("GetWeekly() error = %v", err)
+	}
+	if !gotFrom.Equal(from) || !gotTo.Equal(to) {
+		t.Errorf("ListRange(%v, %v), want (%v, %v)", gotFrom, gotTo, from, to)
+	}
+	if len(weeks) != 1 || weeks[0].WeekStart != "2024-01-01" || weeks[0].OverallVASMean != 70 {
+		t.Errorf("weeks = %+v", weeks)
+	}
+}
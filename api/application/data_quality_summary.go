@@ -0,0 +1,65 @@
+package application
+
+import (
+	"sort"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+// SummarizeDataQuality aggregates per-day quality records for the inclusive
+// date range [from, to]. Days without a record count towards TotalDays but
+// towards no metric's coverage. Ties for the most common missing metric are
+// broken alphabetically.
+func SummarizeDataQuality(qualities []entity.DataQuality, from, to time.Time) entity.DataQualitySummary {
+	summary := entity.DataQualitySummary{
+		From:           from,
+		To:             to,
+		TotalDays:      int(to.Sub(from).Hours()/24) + 1,
+		DaysWithData:   len(qualities),
+		MetricCoverage: map[string]float32{},
+	}
+	if len(qualities) == 0 {
+		return summary
+	}
+
+	sorted := make([]entity.DataQuality, len(qualities))
+	copy(sorted, qualities)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	present := map[string]int{}
+	missing := map[string]int{}
+	var confidenceSum float32
+	for _, q := range sorted {
+		if q.IsValidDay {
+			summary.ValidDays++
+		}
+		confidenceSum += q.ConfidenceScore
+		for _, m := range q.MetricsPresent {
+			present[m]++
+		}
+		for _, m := range q.MetricsMissing {
+			missing[m]++
+			if _, ok := present[m]; !ok {
+				present[m] = 0
+			}
+		}
+	}
+	summary.AvgConfidence = confidenceSum / float32(len(sorted))
+	for m, n := range present {
+		summary.MetricCoverage[m] = float32(n) / float32(summary.TotalDays)
+	}
+
+	bestCount := 0
+	for m, n := range missing {
+		if n > bestCount || (n == bestCount && m < summary.MostCommonMissing) {
+			summary.MostCommonMissing, bestCount = m, n
+		}
+	}
+
+	first, last := sorted[0], sorted[len(sorted)-1]
+	summary.BaselineMaturityStart = first.BaselineMaturity
+	summary.BaselineMaturityEnd = last.BaselineMaturity
+	summary.BaselineDaysChange = last.BaselineDays - first.BaselineDays
+	return summary
+}
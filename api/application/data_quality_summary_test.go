@@ -0,0 +1,59 @@
+package application
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+func TestSummarizeDataQuality(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 3) // 4 days, one of them without a record
+	day := func(i int) time.Time { return from.AddDate(0, 0, i) }
+
+	// Deliberately out of order; the repo sorts but the summary must not rely on it.
+	qualities := []entity.DataQuality{
+		{Date: day(2), IsValidDay: true, ConfidenceScore: 0.9, BaselineDays: 15, BaselineMaturity: "warming",
+			MetricsPresent: []string{"resting_hr", "hrv", "spo2"}, MetricsMissing: []string{"br"}},
+		{Date: day(0), IsValidDay: true, ConfidenceScore: 0.6, BaselineDays: 13, BaselineMaturity: "cold",
+			MetricsPresent: []string{"resting_hr", "hrv"}, MetricsMissing: []string{"spo2", "br"}},
+		{Date: day(1), IsValidDay: false, ConfidenceScore: 0.3, BaselineDays: 14, BaselineMaturity: "warming",
+			MetricsPresent: []string{"resting_hr"}, MetricsMissing: []string{"hrv", "spo2", "br"}},
+	}
+
+	got := SummarizeDataQuality(qualities, from, to)
+
+	if got.TotalDays != 4 || got.DaysWithData != 3 || got.ValidDays != 2 {
+		t.Errorf("days = %d total, %d with data, %d valid; want 4, 3, 2", got.TotalDays, got.DaysWithData, got.ValidDays)
+	}
+	if math.Abs(float64(got.AvgConfidence)-0.6) > 1e-6 {
+		t.Errorf("AvgConfidence = %v, want 0.6", got.AvgConfidence)
+	}
+	wantCoverage := map[string]float32{"resting_hr": 0.75, "hrv": 0.5, "spo2": 0.25, "br": 0}
+	for m, want := range wantCoverage {
+		if c, ok := got.MetricCoverage[m]; !ok || c != want {
+			t.Errorf("MetricCoverage[%s] = %v (present %v), want %v", m, c, ok, want)
+		}
+	}
+	if got.MostCommonMissing != "br" {
+		t.Errorf("MostCommonMissing = %q, want br", got.MostCommonMissing)
+	}
+	if got.BaselineMaturityStart != "cold" || got.BaselineMaturityEnd != "warming" || got.BaselineDaysChange != 2 {
+		t.Errorf("baseline = %s -> %s (%+d), want cold -> warming (+2)",
+			got.BaselineMaturityStart, got.BaselineMaturityEnd, got.BaselineDaysChange)
+	}
+}
+
+func TestSummarizeDataQuality_Empty(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	got := SummarizeDataQuality(nil, from, from)
+
+	if got.TotalDays != 1 || got.DaysWithData != 0 || got.AvgConfidence != 0 {
+		t.Errorf("summary = %+v", got)
+	}
+	if got.MetricCoverage == nil || got.MostCommonMissing != "" {
+		t.Errorf("MetricCoverage = %v, MostCommonMissing = %q; want empty map and \"\"", got.MetricCoverage, got.MostCommonMissing)
+	}
+}
@@ -0,0 +1,76 @@
+package application
+
+import (
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+// qualityTrendWindow is the length in days of the trend's rolling averages.
+const qualityTrendWindow = 7
+
+// BuildDataQualityTrend lays the quality records of the inclusive date range
+// [from, to] out as daily series with 7-day rolling averages. ValidRatePct is
+// the share of all calendar days in the range, not only days with a record,
+// that were valid.
+func BuildDataQualityTrend(qualities []entity.DataQuality, from, to time.Time) entity.DataQualityTrend {
+	days := int(to.Sub(from).Hours()/24) + 1
+	byDay := make(map[string]*entity.DataQuality, len(qualities))
+	for i := range qualities {
+		byDay[qualities[i].Date.Format("2006-01-02")] = &qualities[i]
+	}
+
+	trend := entity.DataQualityTrend{
+		From:            from,
+		To:              to,
+		Dates:           make([]string, days),
+		WearTimeHours:   make([]*float32, days),
+		CompletenessPct: make([]*float32, days),
+		ConfidenceScore: make([]*float32, days),
+		TotalDays:       days,
+	}
+	for i := range trend.Dates {
+		day := from.AddDate(0, 0, i).Format("2006-01-02")
+		trend.Dates[i] = day
+		q, ok := byDay[day]
+		if !ok {
+			continue
+		}
+		// A recorded zero (e.g. the device charged all day) is kept, unlike
+		// entity.Float32Ptr's missing-data sentinel.
+		trend.WearTimeHours[i] = &q.WearTimeHours
+		trend.CompletenessPct[i] = &q.CompletenessPct
+		trend.ConfidenceScore[i] = &q.ConfidenceScore
+		if q.IsValidDay {
+			trend.ValidDayCount++
+		}
+	}
+	trend.WearTimeHours7d = rollingMean(trend.WearTimeHours, qualityTrendWindow)
+	trend.CompletenessPct7d = rollingMean(trend.CompletenessPct, qualityTrendWindow)
+	trend.ConfidenceScore7d = rollingMean(trend.ConfidenceScore, qualityTrendWindow)
+	if days > 0 {
+		trend.ValidRatePct = float32(trend.ValidDayCount) / float32(days) * 100
+	}
+	return trend
+}
+
+// rollingMean returns, for each index, the mean of the non-nil values among
+// it and the window-1 before it, or nil when all of them are nil.
+func rollingMean(series []*float32, window int) []*float32 {
+	out := make([]*float32, len(series))
+	for i := range series {
+		var sum float32
+		var n int
+		for j := max(0, i-window+1); j <= i; j++ {
+			if series[j] != nil {
+				sum += *series[j]
+				n++
+			}
+		}
+		if n > 0 {
+			mean := sum / float32(n)
+			out[i] = &mean
+		}
+	}
+	return out
+}
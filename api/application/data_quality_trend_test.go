@@ -0,0 +1,74 @@
+package application
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+func TestBuildDataQualityTrend(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 8) // 9 days
+	day := func(i int) time.Time { return from.AddDate(0, 0, i) }
+
+	// Day 3 has no record; day 4 was spent on the charger.
+	var qualities []entity.DataQuality
+	for i := 0; i < 9; i++ {
+		if i == 3 {
+			continue
+		}
+		q := entity.DataQuality{Date: day(i), WearTimeHours: 20, CompletenessPct: 0.9, ConfidenceScore: 0.8, IsValidDay: true}
+		if i == 4 {
+			q = entity.DataQuality{Date: day(i)}
+		}
+		qualities = append(qualities, q)
+	}
+
+	got := BuildDataQualityTrend(qualities, from, to)
+
+	if got.TotalDays != 9 || got.ValidDayCount != 7 {
+		t.Errorf("TotalDays = %d, ValidDayCount = %d, want 9, 7", got.TotalDays, got.ValidDayCount)
+	}
+	if math.Abs(float64(got.ValidRatePct)-700.0/9) > 1e-4 {
+		t.Errorf("ValidRatePct = %v, want %v", got.ValidRatePct, 700.0/9)
+	}
+	if len(got.Dates) != 9 || got.Dates[0] != "2025-06-01" || got.Dates[8] != "2025-06-09" {
+		t.Fatalf("Dates = %v", got.Dates)
+	}
+	for _, s := range [][]*float32{got.WearTimeHours, got.CompletenessPct, got.ConfidenceScore,
+		got.WearTimeHours7d, got.CompletenessPct7d, got.ConfidenceScore7d} {
+		if len(s) != 9 {
+			t.Fatalf("series length = %d, want 9", len(s))
+		}
+	}
+	if got.WearTimeHours[3] != nil {
+		t.Errorf("WearTimeHours[3] = %v, want nil for a day without a record", *got.WearTimeHours[3])
+	}
+	if got.WearTimeHours[4] == nil || *got.WearTimeHours[4] != 0 {
+		t.Errorf("WearTimeHours[4] = %v, want a recorded 0", got.WearTimeHours[4])
+	}
+
+	// Day 6 averages days 0-6 minus the missing day 3: (5*20 + 0) / 6.
+	wantAvg := []float64{20, 20, 20, 20, 15, 16, 100.0 / 6, 100.0 / 6, 100.0 / 6}
+	for i, want := range wantAvg {
+		if g := got.WearTimeHours7d[i]; g == nil || math.Abs(float64(*g)-want) > 1e-4 {
+			t.Errorf("WearTimeHours7d[%d] = %v, want %v", i, g, want)
+		}
+	}
+}
+
+func TestBuildDataQualityTrend_NoRecords(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	got := BuildDataQualityTrend(nil, from, from.AddDate(0, 0, 2))
+
+	if got.TotalDays != 3 || got.ValidDayCount != 0 || got.ValidRatePct != 0 {
+		t.Errorf("got %d total, %d valid, %v%%; want 3, 0, 0", got.TotalDays, got.ValidDayCount, got.ValidRatePct)
+	}
+	for i, v := range got.ConfidenceScore7d {
+		if v != nil {
+			t.Errorf("ConfidenceScore7d[%d] = %v, want nil", i, *v)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package application
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+// BuildPaceTrend collects the exercises named activity (case-insensitive)
+// that have a pace, oldest first, with their best, worst and overall pace.
+func BuildPaceTrend(activity string, from, to time.Time, logs []entity.ExerciseLog) *entity.PaceTrend {
+	trend := &entity.PaceTrend{
+		Activity: activity,
+		From:     from,
+		To:       to,
+		Points:   []entity.PacePoint{},
+	}
+
+	var totalMS int64
+	var totalKM float64
+	for _, l := range logs {
+		if !strings.EqualFold(l.ActivityName, activity) || l.Pace <= 0 {
+			continue
+		}
+		trend.Points = append(trend.Points, entity.PacePoint{
+			StartedAt:  l.StartedAt,
+			DistanceKM: l.DistanceKM,
+			DurationMS: l.DurationMS,
+			Pace:       l.Pace,
+		})
+		totalMS += l.DurationMS
+		totalKM += float64(l.DistanceKM)
+	}
+	if len(trend.Points) == 0 {
+		return trend
+	}
+
+	sort.Slice(trend.Points, func(i, j int) bool {
+		return trend.Points[i].StartedAt.Before(trend.Points[j].StartedAt)
+	})
+	best, worst := trend.Points[0].Pace, trend.Points[0].Pace
+	for _, p := range trend.Points[1:] {
+		best = min(best, p.Pace)
+		worst = max(worst, p.Pace)
+	}
+	avg := entity.ComputePace(totalMS, float32(totalKM))
+	trend.Best, trend.Worst, trend.Average = &best, &worst, &avg
+	return trend
+}
@@ -0,0 +1,45 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+func TestBuildPaceTrend(t *testing.T) {
+	day := time.Date(2025, 1, 1, 7, 0, 0, 0, jst)
+	logs := []entity.ExerciseLog{
+		// ListRange returns newest first.
+		{ActivityName: "Running", StartedAt: day.AddDate(0, 0, 2), DurationMS: 20 * 60000, DistanceKM: 5, Pace: 240},
+		{ActivityName: "Cycling", StartedAt: day.AddDate(0, 0, 1), DurationMS: 60 * 60000, DistanceKM: 30, Pace: 120},
+		{ActivityName: "running", StartedAt: day.AddDate(0, 0, 1), DurationMS: 40 * 60000, DistanceKM: 5, Pace: 480},
+		{ActivityName: "Running", StartedAt: day, DurationMS: 30 * 60000, DistanceKM: 5, Pace: 360},
+		{ActivityName: "Running", StartedAt: day, DurationMS: 30 * 60000}, // treadmill without distance
+	}
+
+	trend := BuildPaceTrend("Running", day, day.AddDate(0, 0, 2), logs)
+	if len(trend.Points) != 3 {
+		t.Fatalf("len(Points) = %d, want 3", len(trend.Points))
+	}
+	for i := 1; i < len(trend.Points); i++ {
+		if trend.Points[i].StartedAt.Before(trend.Points[i-1].StartedAt) {
+			t.Fatalf("Points not oldest first: %v", trend.Points)
+		}
+	}
+	if trend.Best == nil || *trend.Best != 240 {
+		t.Errorf("Best = %v, want 240", trend.Best)
+	}
+	if trend.Worst == nil || *trend.Worst != 480 {
+		t.Errorf("Worst = %v, want 480", trend.Worst)
+	}
+	// 90 minutes over 15 km
+	if trend.Average == nil || *trend.Average != 360 {
+		t.Errorf("Average = %v, want 360", trend.Average)
+	}
+
+	empty := BuildPaceTrend("Swimming", day, day, logs)
+	if len(empty.Points) != 0 || empty.Best != nil || empty.Average != nil {
+		t.Errorf("BuildPaceTrend(Swimming) = %+v, want no points", empty)
+	}
+}
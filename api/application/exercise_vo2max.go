@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// ErrVO2MaxInputsMissing is returned when an exercise lacks the heart rate
+// data needed for a VO2max estimate.
+var ErrVO2MaxInputsMissing = errors.New("exercise needs avg HR, and the day needs resting HR and a max HR (profile age or daily max)")
+
+// jst is the timezone daily summaries and condition days are keyed by.
+var jst = time.FixedZone("JST", 9*60*60)
+
+// EstimateVO2MaxFromExercise applies the Uth-Sørensen formula,
+// VO2max = 15 * (maxHR / restingHR), in ml/kg/min. It returns nil when the
+// log has no average heart rate or the inputs cannot describe a real effort.
+func EstimateVO2MaxFromExercise(log entity.ExerciseLog, restingHR, maxHR int) *float32 {
+	if log.AvgHR <= 0 || restingHR <= 0 || maxHR <= restingHR {
+		return nil
+	}
+	v := float32(15 * float64(maxHR) / float64(restingHR))
+	return &v
+}
+
+// ExerciseVO2MaxUseCase estimates VO2max for stored exercise logs using the
+// resting heart rate of the day the exercise started.
+type ExerciseVO2MaxUseCase struct {
+	exercises port.ExerciseRepository
+	summaries port.DailySummaryRepository
+	age       int
+}
+
+// NewExerciseVO2MaxUseCase creates the use case. With a known age the
+// age-predicted maximum (220 - age) is used; otherwise the day's observed
+// max HR stands in.
+func NewExerciseVO2MaxUseCase(exercises port.ExerciseRepository, summaries port.DailySummaryRepository, age int) *ExerciseVO2MaxUseCase {
+	return &ExerciseVO2MaxUseCase{exercises: exercises, summaries: summaries, age: age}
+}
+
+// Estimate computes and stores the VO2max estimate for the exercise with id.
+func (uc *ExerciseVO2MaxUseCase) Estimate(ctx context.Context, id int64) (*entity.ExerciseLog, error) {
+	log, err := uc.exercises.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if log == nil {
+		return nil, entity.ErrNotFound
+	}
+
+	vo2max, err := uc.estimate(ctx, *log)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.exercises.UpdateEstimatedVO2Max(ctx, id, *vo2max); err != nil {
+		return nil, err
+	}
+	log.EstimatedVO2Max = vo2max
+	return log, nil
+}
+
+// Fill sets log.EstimatedVO2Max before the log is stored, so synced and
+// imported exercises get an estimate without the manual POST. The log is
+// left unchanged when it has no average HR or its day no resting HR yet.
+func (uc *ExerciseVO2MaxUseCase) Fill(ctx context.Context, log *entity.ExerciseLog) error {
+	if log.AvgHR <= 0 {
+		return nil
+	}
+	vo2max, err := uc.estimate(ctx, *log)
+	if errors.Is(err, ErrVO2MaxInputsMissing) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	log.EstimatedVO2Max = vo2max
+	return nil
+}
+
+// estimate looks up the resting and max HR of the JST day log started on.
+func (uc *ExerciseVO2MaxUseCase) estimate(ctx context.Context, log entity.ExerciseLog) (*float32, error) {
+	local := log.StartedAt.In(jst)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	summary, err := uc.summaries.GetByDate(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		return nil, ErrVO2MaxInputsMissing
+	}
+
+	maxHR := summary.MaxHR
+	if uc.age > 0 {
+		maxHR = 220 - uc.age
+	}
+	vo2max := EstimateVO2MaxFromExercise(log, summary.RestingHR, maxHR)
+	if vo2max == nil {
+		return nil, ErrVO2MaxInputsMissing
+	}
+	return vo2max, nil
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func f32(v float32) *float32 { return &v }
+
+func TestEstimateVO2MaxFromExercise(t *testing.T) {
+	tests := []struct {
+		name      string
+		avgHR     int
+		restingHR int
+		maxHR     int
+		want      *float32
+	}{
+		{"typical", 150, 60, 190, f32(47.5)},
+		{"no avg HR", 0, 60, 190, nil},
+		{"no resting HR", 150, 0, 190, nil},
+		{"max not above resting", 150, 60, 60, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateVO2MaxFromExercise(entity.ExerciseLog{AvgHR: tt.avgHR}, tt.restingHR, tt.maxHR)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("EstimateVO2MaxFromExercise() = %v, want %v", got, tt.want)
+			}
+			if got != nil && *got != *tt.want {
+				t.Errorf("EstimateVO2MaxFromExercise() = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestExerciseVO2MaxUseCase_Estimate(t *testing.T) {
+	// 2025-01-10 23:30 UTC is 2025-01-11 in JST.
+	started := time.Date(2025, 1, 10, 23, 30, 0, 0, time.UTC)
+	exercises := &mocks.MockExerciseRepository{
+		GetByIDFunc: func(_ context.Context, id int64) (*entity.ExerciseLog, error) {
+			if id != 7 {
+				return nil, nil
+			}
+			return &entity.ExerciseLog{ID: 7, StartedAt: started, AvgHR: 145}, nil
+		},
+	}
+	var gotDay time.Time
+	summaries := &mocks.MockDailySummaryRepository{
+		GetByDateFunc: func(_ context.Context, date time.Time) (*entity.DailySummary, error) {
+			gotDay = date
+			return &entity.DailySummary{RestingHR: 60, MaxHR: 165}, nil
+		},
+	}
+
+	t.Run("age-predicted max HR", func(t *testing.T) {
+		var stored float32
+		exercises.UpdateEstimatedVO2MaxFunc = func(_ context.Context, _ int64, v float32) error {
+			stored = v
+			return nil
+		}
+		log, err := NewExerciseVO2MaxUseCase(exercises, summaries, 30).Estimate(context.Background(), 7)
+		if err != nil {
+			t.Fatalf("Estimate() error = %v", err)
+		}
+		// 15 * (190 / 60)
+		if stored != 47.5 || log.EstimatedVO2Max == nil || *log.EstimatedVO2Max != 47.5 {
+			t.Errorf("stored = %v, log = %v, want 47.5", stored, log.EstimatedVO2Max)
+		}
+		if want := time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC); !gotDay.Equal(want) {
+			t.Errorf("summary day = %v, want %v", gotDay, want)
+		}
+	})
+
+	t.Run("daily max HR without age", func(t *testing.T) {
+		var stored float32
+		exercises.UpdateEstimatedVO2MaxFunc = func(_ context.Context, _ int64, v float32) error {
+			stored = v
+			return nil
+		}
+		if _, err := NewExerciseVO2MaxUseCase(exercises, summaries, 0).Estimate(context.Background(), 7); err != nil {
+			t.Fatalf("Estimate() error = %v", err)
+		}
+		// 15 * (165 / 60)
+		if stored != 41.25 {
+			t.Errorf("stored = %v, want 41.25", stored)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := NewExerciseVO2MaxUseCase(exercises, summaries, 30).Estimate(context.Background(), 8)
+		if !errors.Is(err, entity.ErrNotFound) {
+			t.Errorf("Estimate() error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("no summary", func(t *testing.T) {
+		empty := &mocks.MockDailySummaryRepository{
+			GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+				return nil, nil
+			},
+		}
+		_, err := NewExerciseVO2MaxUseCase(exercises, empty, 30).Estimate(context.Background(), 7)
+		if !errors.Is(err, ErrVO2MaxInputsMissing) {
+			t.Errorf("Estimate() error = %v, want ErrVO2MaxInputsMissing", err)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+const (
+	feverBaselineDays = 30
+	// feverMinBaseline is the fewest skin temperature readings needed for a
+	// meaningful baseline SD.
+	feverMinBaseline = 7
+)
+
+// DetectFeverCandidate compares date's stored skin temperature variation with
+// the preceding 30-day mean + 2 SD. It returns whether the value exceeds that
+// threshold along with the threshold itself, and writes nothing; sync stores
+// the flag in DailySummary.FeverCandidate. A missing reading or too little
+// history yields false with a zero threshold.
+func DetectFeverCandidate(ctx context.Context, date time.Time, summaryRepo port.DailySummaryRepository) (bool, float32, error) {
+	today, err := summaryRepo.GetByDate(ctx, date)
+	if err != nil || today == nil {
+		return false, 0, err
+	}
+	return evaluateFever(ctx, date, today, summaryRepo)
+}
+
+// evaluateFever checks summary's skin temperature against the 30 days before
+// date. A filled-forward reading never counts.
+func evaluateFever(ctx context.Context, date time.Time, summary *entity.DailySummary, summaryRepo port.DailySummaryRepository) (bool, float32, error) {
+	if summary.SkinTempVariation == nil || summary.IsFilledForward(skinTempField) {
+		return false, 0, nil
+	}
+
+	history, err := summaryRepo.ListRange(ctx, date.AddDate(0, 0, -feverBaselineDays), date.AddDate(0, 0, -1))
+	if err != nil {
+		return false, 0, err
+	}
+	values := make([]float64, 0, len(history))
+	for _, s := range history {
+		if s.SkinTempVariation != nil && !s.IsFilledForward(skinTempField) {
+			values = append(values, float64(*s.SkinTempVariation))
+		}
+	}
+	if len(values) < feverMinBaseline {
+		return false, 0, nil
+	}
+
+	mean, sd := meanStdDev(values)
+	threshold := float32(mean + 2*sd)
+	return *summary.SkinTempVariation > threshold, threshold, nil
+}
@@ -0,0 +1,137 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestDetectFeverCandidate(t *testing.T) {
+	date := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	// Baseline alternates -0.2 / 0.2 → mean 0, sample SD ≈ 0.2034, threshold ≈ 0.41.
+	baseline := make([]entity.DailySummary, 0, 30)
+	for i := 0; i < 30; i++ {
+		v := float32(0.2)
+		if i%2 == 0 {
+			v = -0.2
+		}
+		baseline = append(baseline, entity.DailySummary{Date: date.AddDate(0, 0, -30+i), SkinTempVariation: &v})
+	}
+
+	tests := []struct {
+		name    string
+		today   *entity.DailySummary
+		history []entity.DailySummary
+		want    bool
+	}{
+		{"elevated", &entity.DailySummary{SkinTempVariation: entity.Float32Ptr(1.2)}, baseline, true},
+		{"within baseline", &entity.DailySummary{SkinTempVariation: entity.Float32Ptr(0.3)}, baseline, false},
+		{"ignores stale flag", &entity.DailySummary{SkinTempVariation: entity.Float32Ptr(0.3), FeverCandidate: true}, baseline, false},
+		{"no summary", nil, baseline, false},
+		{"no reading today", &entity.DailySummary{}, baseline, false},
+		{"filled forward reading", &entity.DailySummary{SkinTempVariation: entity.Float32Ptr(1.2), FilledForwardFields: []string{"SkinTempVariation"}}, baseline, false},
+		{"insufficient history", &entity.DailySummary{SkinTempVariation: entity.Float32Ptr(1.2)}, baseline[:3], false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var upserted *entity.DailySummary
+			repo := &mocks.MockDailySummaryRepository{
+				GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) { return tt.today, nil },
+				ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) { return tt.history, nil },
+				UpsertFunc: func(_ context.Context, s *entity.DailySummary) error {
+					upserted = s
+					return nil
+				},
+			}
+
+			got, threshold, err := DetectFeverCandidate(context.Background(), date, repo)
+			if err != nil {
+				t.Fatalf("DetectFeverCandidate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("candidate = %v, want %v (threshold %.3f)", got, tt.want, threshold)
+			}
+			if upserted != nil {
+				t.Error("DetectFeverCandidate() wrote the summary, want read-only")
+			}
+		})
+	}
+}
+
+func TestSyncBiometrics_FeverCandidate(t *testing.T) {
+	date := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	baseline := make([]entity.DailySummary, 0, 30)
+	for i := 0; i < 30; i++ {
+		v := float32(0.2)
+		if i%2 == 0 {
+			v = -0.2
+		}
+		baseline = append(baseline, entity.DailySummary{Date: date.AddDate(0, 0, -30+i), SkinTempVariation: &v})
+	}
+
+	tests := []struct {
+		name      string
+		skinTemp  float32
+		wantAlert bool
+	}{
+		{"elevated", 1.2, true},
+		{"within baseline", 0.3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := summaryOnlyProvider(entity.DailySummary{Date: date})
+			provider.FetchSkinTemperatureFunc = func(_ context.Context, _ time.Time) (float32, error) {
+				return tt.skinTemp, nil
+			}
+			var upserts []entity.DailySummary
+			summaryRepo := &mocks.MockDailySummaryRepository{
+				UpsertFunc: func(_ context.Context, s *entity.DailySummary) error {
+					upserts = append(upserts, *s)
+					return nil
+				},
+				ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+					return baseline, nil
+				},
+			}
+			var sent []*entity.FeverAlert
+			sender := &mocks.MockWebhookSender{
+				SendFunc: func(_ context.Context, _ string, payload any) error {
+					sent = append(sent, payload.(*entity.FeverAlert))
+					return nil
+				},
+			}
+
+			uc := NewSyncBiometricsUseCase(provider, summaryRepo, &mocks.MockHeartRateRepository{},
+				&mocks.MockSleepStageRepository{}, &mocks.MockExerciseRepository{}, nil).
+				WithFeverAlert(sender).
+				WithAlertSentStore(memAlertSentStore())
+			for i := 0; i < 2; i++ {
+				if err := uc.SyncDate(context.Background(), date); err != nil {
+					t.Fatalf("SyncDate() error = %v", err)
+				}
+			}
+
+			// The flag goes out with the synced summary, not a second write.
+			if len(upserts) != 2 {
+				t.Fatalf("upserts = %d, want one per sync", len(upserts))
+			}
+			if upserts[0].FeverCandidate != tt.wantAlert {
+				t.Errorf("FeverCandidate = %v, want %v", upserts[0].FeverCandidate, tt.wantAlert)
+			}
+			if tt.wantAlert {
+				if len(sent) != 1 {
+					t.Fatalf("alerts sent = %d, want 1 for two syncs", len(sent))
+				}
+				if sent[0].Date != "2026-03-31" || sent[0].SkinTempVariation != 1.2 || sent[0].Threshold <= 0 {
+					t.Errorf("alert = %+v", sent[0])
+				}
+			} else if len(sent) != 0 {
+				t.Errorf("alerts sent = %d, want 0", len(sent))
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package application
+
+import "vitametron/api/domain/entity"
+
+// skinTempField is the FilledForwardFields name of SkinTempVariation.
+const skinTempField = "SkinTempVariation"
+
+// fillForwardFields are the nightly readings that change slowly enough for
+// the previous day's value to stand in for a missing one. Activity and sleep
+// totals describe the day itself and are never copied.
+var fillForwardFields = []struct {
+	name  string
+	field func(s *entity.DailySummary) **float32
+}{
+	{"HRVDailyRMSSD", func(s *entity.DailySummary) **float32 { return &s.HRVDailyRMSSD }},
+	{"HRVDeepRMSSD", func(s *entity.DailySummary) **float32 { return &s.HRVDeepRMSSD }},
+	{"SpO2Avg", func(s *entity.DailySummary) **float32 { return &s.SpO2Avg }},
+	{"SpO2Min", func(s *entity.DailySummary) **float32 { return &s.SpO2Min }},
+	{"SpO2Max", func(s *entity.DailySummary) **float32 { return &s.SpO2Max }},
+	{"BRFullSleep", func(s *entity.DailySummary) **float32 { return &s.BRFullSleep }},
+	{"BRDeepSleep", func(s *entity.DailySummary) **float32 { return &s.BRDeepSleep }},
+	{"BRLightSleep", func(s *entity.DailySummary) **float32 { return &s.BRLightSleep }},
+	{"BRREMSleep", func(s *entity.DailySummary) **float32 { return &s.BRREMSleep }},
+	{skinTempField, func(s *entity.DailySummary) **float32 { return &s.SkinTempVariation }},
+	{"VO2Max", func(s *entity.DailySummary) **float32 { return &s.VO2Max }},
+}
+
+// FillForwardSummary copies each reading missing (nil or zero) in current
+// from previous and records its name in current.FilledForwardFields. Values
+// previous itself filled forward are not copied, so a gap is bridged for one
+// day only.
+func FillForwardSummary(current, previous *entity.DailySummary) {
+	if previous == nil {
+		return
+	}
+	for _, f := range fillForwardFields {
+		cur, prev := f.field(current), *f.field(previous)
+		if (*cur != nil && **cur != 0) || prev == nil || *prev == 0 || previous.IsFilledForward(f.name) {
+			continue
+		}
+		v := *prev
+		*cur = &v
+		current.FilledForwardFields = append(current.FilledForwardFields, f.name)
+	}
+}
+
+// keepStoredReadings copies readings missing in current from the already
+// stored row for the same day, so a reading that failed to fetch on re-sync
+// is not replaced by the previous day's value. Upsert keeps such readings
+// anyway; this only runs ahead of FillForwardSummary.
+func keepStoredReadings(current, stored *entity.DailySummary) {
+	if stored == nil {
+		return
+	}
+	for _, f := range fillForwardFields {
+		cur, kept := f.field(current), *f.field(stored)
+		if (*cur != nil && **cur != 0) || kept == nil || stored.IsFilledForward(f.name) {
+			continue
+		}
+		v := *kept
+		*cur = &v
+	}
+}
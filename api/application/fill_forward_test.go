@@ -0,0 +1,160 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestFillForwardSummary(t *testing.T) {
+	t.Run("fills missing readings", func(t *testing.T) {
+		current := &entity.DailySummary{SpO2Avg: entity.Float32Ptr(96), HRVDeepRMSSD: new(float32)}
+		previous := &entity.DailySummary{
+			HRVDailyRMSSD: entity.Float32Ptr(42),
+			HRVDeepRMSSD:  entity.Float32Ptr(50),
+			SpO2Avg:       entity.Float32Ptr(94),
+			DistanceKM:    5,
+		}
+
+		FillForwardSummary(current, previous)
+
+		if current.HRVDailyRMSSD == nil || *current.HRVDailyRMSSD != 42 {
+			t.Errorf("HRVDailyRMSSD = %v, want 42", current.HRVDailyRMSSD)
+		}
+		if current.HRVDeepRMSSD == nil || *current.HRVDeepRMSSD != 50 {
+			t.Errorf("HRVDeepRMSSD = %v, want 50 (zero counts as missing)", current.HRVDeepRMSSD)
+		}
+		if *current.SpO2Avg != 96 {
+			t.Errorf("SpO2Avg = %v, want the measured 96", *current.SpO2Avg)
+		}
+		if current.DistanceKM != 0 {
+			t.Errorf("DistanceKM = %v, activity should not be filled", current.DistanceKM)
+		}
+		if want := []string{"HRVDailyRMSSD", "HRVDeepRMSSD"}; !slices.Equal(current.FilledForwardFields, want) {
+			t.Errorf("FilledForwardFields = %v, want %v", current.FilledForwardFields, want)
+		}
+
+		*previous.HRVDailyRMSSD = 1
+		if *current.HRVDailyRMSSD != 42 {
+			t.Error("filled value should not alias previous")
+		}
+	})
+
+	t.Run("does not chain fills", func(t *testing.T) {
+		current := &entity.DailySummary{}
+		previous := &entity.DailySummary{
+			HRVDailyRMSSD:       entity.Float32Ptr(42),
+			SpO2Avg:             entity.Float32Ptr(94),
+			FilledForwardFields: []string{"HRVDailyRMSSD"},
+		}
+
+		FillForwardSummary(current, previous)
+
+		if current.HRVDailyRMSSD != nil {
+			t.Errorf("HRVDailyRMSSD = %v, want nil", *current.HRVDailyRMSSD)
+		}
+		if want := []string{"SpO2Avg"}; !slices.Equal(current.FilledForwardFields, want) {
+			t.Errorf("FilledForwardFields = %v, want %v", current.FilledForwardFields, want)
+		}
+	})
+
+	t.Run("no previous", func(t *testing.T) {
+		current := &entity.DailySummary{}
+		FillForwardSummary(current, nil)
+		if len(current.FilledForwardFields) != 0 {
+			t.Errorf("FilledForwardFields = %v, want none", current.FilledForwardFields)
+		}
+	})
+}
+
+func TestSyncBiometrics_FillForward(t *testing.T) {
+	date := time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC)
+	unavailable := errors.New("unavailable")
+
+	provider := &mocks.MockBiometricsProvider{
+		FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+			return &entity.DailySummary{Date: date, RestingHR: 58}, nil
+		},
+		FetchHRVFunc: func(_ context.Context, _ time.Time) (float32, float32, error) {
+			return 0, 0, unavailable
+		},
+		FetchSpO2Func: func(_ context.Context, _ time.Time) (float32, float32, float32, error) {
+			return 0, 0, 0, unavailable
+		},
+		FetchBreathingRateFunc: func(_ context.Context, _ time.Time) (float32, float32, float32, float32, error) {
+			return 15, 14, 16, 15, nil
+		},
+		FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) {
+			return 0, unavailable
+		},
+		FetchWaterLogFunc: func(_ context.Context, _ time.Time) (int, error) {
+			return 0, unavailable
+		},
+		FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
+			return nil, unavailable
+		},
+		FetchSleepStagesFunc: func(_ context.Context, _ time.Time) ([]entity.SleepStage, *entity.SleepRecord, error) {
+			return nil, nil, unavailable
+		},
+		FetchExerciseLogsFunc: func(_ context.Context, _ time.Time) ([]entity.ExerciseLog, error) {
+			return nil, unavailable
+		},
+	}
+
+	var upserted *entity.DailySummary
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		GetByDateFunc: func(_ context.Context, d time.Time) (*entity.DailySummary, error) {
+			switch {
+			case d.Equal(date):
+				// An earlier sync of the same day already measured SpO2.
+				return &entity.DailySummary{Date: date, SpO2Avg: entity.Float32Ptr(97)}, nil
+			case d.Equal(date.AddDate(0, 0, -1)):
+				return &entity.DailySummary{
+					Date:          d,
+					HRVDailyRMSSD: entity.Float32Ptr(42),
+					SpO2Avg:       entity.Float32Ptr(94),
+					BRFullSleep:   entity.Float32Ptr(18),
+				}, nil
+			}
+			return nil, nil
+		},
+		UpsertFunc: func(_ context.Context, s *entity.DailySummary) error {
+			upserted = s
+			return nil
+		},
+	}
+	var quality *entity.DataQuality
+	qualityRepo := newQualityRepo()
+	qualityRepo.UpsertFunc = func(_ context.Context, q *entity.DataQuality) error {
+		quality = q
+		return nil
+	}
+
+	uc := NewSyncBiometricsUseCase(provider, summaryRepo, &mocks.MockHeartRateRepository{},
+		&mocks.MockSleepStageRepository{}, &mocks.MockExerciseRepository{}, qualityRepo).
+		WithFillForward()
+	if err := uc.SyncDate(context.Background(), date); err != nil {
+		t.Fatalf("SyncDate() error = %v", err)
+	}
+
+	if upserted.HRVDailyRMSSD == nil || *upserted.HRVDailyRMSSD != 42 {
+		t.Errorf("HRVDailyRMSSD = %v, want 42 from the previous day", upserted.HRVDailyRMSSD)
+	}
+	if upserted.SpO2Avg == nil || *upserted.SpO2Avg != 97 {
+		t.Errorf("SpO2Avg = %v, want the stored 97", upserted.SpO2Avg)
+	}
+	if *upserted.BRFullSleep != 15 {
+		t.Errorf("BRFullSleep = %v, want the measured 15", *upserted.BRFullSleep)
+	}
+	if want := []string{"HRVDailyRMSSD"}; !slices.Equal(upserted.FilledForwardFields, want) {
+		t.Errorf("FilledForwardFields = %v, want %v", upserted.FilledForwardFields, want)
+	}
+	if !slices.Contains(quality.MetricsMissing, "hrv") {
+		t.Errorf("MetricsMissing = %v, filled HRV should still count as missing", quality.MetricsMissing)
+	}
+}
@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// minHydrationPairs is the fewest water/next-day-HRV pairs for a correlation.
+const minHydrationPairs = 7
+
+// HydrationAnalyzer relates water intake to next-day recovery.
+type HydrationAnalyzer struct {
+	summaryRepo port.DailySummaryRepository
+}
+
+func NewHydrationAnalyzer(summaryRepo port.DailySummaryRepository) *HydrationAnalyzer {
+	return &HydrationAnalyzer{summaryRepo: summaryRepo}
+}
+
+// CorrelateNextDayHRV pairs each day in [from, to] that has logged water with
+// the following day's HRVDailyRMSSD and returns their Pearson correlation.
+func (a *HydrationAnalyzer) CorrelateNextDayHRV(ctx context.Context, from, to time.Time) (*entity.HydrationHRVCorrelation, error) {
+	summaries, err := a.summaryRepo.ListRange(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	hrvByDate := make(map[string]float64, len(summaries))
+	for _, s := range summaries {
+		if s.HRVDailyRMSSD != nil {
+			hrvByDate[s.Date.Format("2006-01-02")] = float64(*s.HRVDailyRMSSD)
+		}
+	}
+
+	var water, hrv []float64
+	for _, s := range summaries {
+		if s.WaterIntakeMl <= 0 || s.Date.After(to) {
+			continue
+		}
+		next, ok := hrvByDate[s.Date.AddDate(0, 0, 1).Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		water = append(water, float64(s.WaterIntakeMl))
+		hrv = append(hrv, next)
+	}
+
+	result := &entity.HydrationHRVCorrelation{
+		From:     from,
+		To:       to,
+		Pairs:    len(water),
+		MinPairs: minHydrationPairs,
+	}
+	if len(water) > 0 {
+		mean, _ := meanStdDev(water)
+		avg := math.Round(mean)
+		result.AvgWaterMl = &avg
+	}
+	if len(water) >= minHydrationPairs {
+		if r, ok := pearson(water, hrv); ok {
+			r = math.Round(r*1000) / 1000
+			result.Correlation = &r
+		}
+	}
+	return result, nil
+}
+
+// pearson returns the Pearson correlation of xs and ys. It reports false when
+// either series has no variance.
+func pearson(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	if n == 0 || len(xs) != len(ys) {
+		return 0, false
+	}
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
@@ -0,0 +1,100 @@
+package application
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestPearson(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		want   float64
+		wantOK bool
+	}{
+		{"perfect positive", []float64{1, 2, 3, 4}, []float64{2, 4, 6, 8}, 1, true},
+		{"perfect negative", []float64{1, 2, 3, 4}, []float64{8, 6, 4, 2}, -1, true},
+		{"no variance", []float64{1, 1, 1}, []float64{1, 2, 3}, 0, false},
+		{"empty", nil, nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pearson(tt.xs, tt.ys)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("pearson() = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHydrationAnalyzer_CorrelateNextDayHRV(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 9)
+
+	// Day i logs 1000+100i mL; day i+1 has HRV 40+i, so pairs correlate perfectly.
+	// Day 5 has no water logged and must be skipped.
+	var summaries []entity.DailySummary
+	for i := 0; i <= 10; i++ {
+		s := entity.DailySummary{Date: from.AddDate(0, 0, i)}
+		if i != 5 {
+			s.WaterIntakeMl = 1000 + 100*i
+		}
+		if i > 0 {
+			hrv := float32(40 + i - 1)
+			s.HRVDailyRMSSD = &hrv
+		}
+		summaries = append(summaries, s)
+	}
+
+	var gotTo time.Time
+	repo := &mocks.MockDailySummaryRepository{
+		ListRangeFunc: func(_ context.Context, _, to time.Time) ([]entity.DailySummary, error) {
+			gotTo = to
+			return summaries, nil
+		},
+	}
+
+	result, err := NewHydrationAnalyzer(repo).CorrelateNextDayHRV(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("CorrelateNextDayHRV() error = %v", err)
+	}
+	if !gotTo.Equal(to.AddDate(0, 0, 1)) {
+		t.Errorf("ListRange to = %v, want day after range end", gotTo)
+	}
+	// Days 0–9 minus day 5; day 10 is outside the range.
+	if result.Pairs != 9 {
+		t.Errorf("Pairs = %d, want 9", result.Pairs)
+	}
+	if result.Correlation == nil || *result.Correlation != 1 {
+		t.Errorf("Correlation = %v, want 1", result.Correlation)
+	}
+}
+
+func TestHydrationAnalyzer_TooFewPairs(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	hrv := float32(40)
+	repo := &mocks.MockDailySummaryRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+			return []entity.DailySummary{
+				{Date: day, WaterIntakeMl: 1500},
+				{Date: day.AddDate(0, 0, 1), HRVDailyRMSSD: &hrv},
+			}, nil
+		},
+	}
+
+	result, err := NewHydrationAnalyzer(repo).CorrelateNextDayHRV(context.Background(), day, day)
+	if err != nil {
+		t.Fatalf("CorrelateNextDayHRV() error = %v", err)
+	}
+	if result.Pairs != 1 || result.Correlation != nil {
+		t.Errorf("Pairs = %d, Correlation = %v; want 1, nil", result.Pairs, result.Correlation)
+	}
+	if result.AvgWaterMl == nil || *result.AvgWaterMl != 1500 {
+		t.Errorf("AvgWaterMl = %v, want 1500", result.AvgWaterMl)
+	}
+}
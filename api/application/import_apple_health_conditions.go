@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"io"
+	"log"
+	"slices"
+	"time"
+
+	"vitametron/api/adapter/applehealthxml"
+	"vitametron/api/domain/entity"
+)
+
+// MoodChangesTag marks condition logs on days Apple Health has a
+// "Mood Changes" symptom for.
+const MoodChangesTag = "mood-changes"
+
+// AppleHealthTag marks condition logs created by an Apple Health import, so
+// they can be told apart from logs the user scored.
+const AppleHealthTag = "apple-health"
+
+// importedMoodChangeVAS is the OverallVAS of a log created for a mood change.
+// The symptom carries no score, so the log takes the neutral midpoint.
+const importedMoodChangeVAS = 50
+
+// ConditionImportResult counts the outcome of a mood change import, per
+// symptom record. Imported records had no live log that day and created
+// one; Tagged records added MoodChangesTag to at least one existing log of
+// their day; Skipped records found every log already tagged; Errors could
+// not be read or stored.
+type ConditionImportResult struct {
+	Imported int `json:"imported"`
+	Tagged   int `json:"tagged"`
+	Skipped  int `json:"skipped"`
+	Errors   int `json:"errors"`
+}
+
+// ImportAppleHealthConditionsUseCase imports "Mood Changes" symptoms from an
+// Apple Health export.xml. The symptom only records that mood changed, not a
+// score, so it tags the condition logs the user recorded that JST day. A day
+// without any gets a log of its own at the neutral OverallVAS, tagged
+// MoodChangesTag and AppleHealthTag. Writes go through uc so the logs are
+// validated like any other.
+type ImportAppleHealthConditionsUseCase struct {
+	uc     ConditionUseCase
+	mapper *applehealthxml.ConditionMapper
+}
+
+func NewImportAppleHealthConditionsUseCase(uc ConditionUseCase) *ImportAppleHealthConditionsUseCase {
+	return &ImportAppleHealthConditionsUseCase{uc: uc, mapper: applehealthxml.NewConditionMapper()}
+}
+
+// maxLogsPerDay bounds the logs fetched for one day.
+const maxLogsPerDay = 100
+
+// Execute reads export.xml from r. Re-importing the same export is a no-op,
+// and soft-deleted logs are left untouched.
+func (uc *ImportAppleHealthConditionsUseCase) Execute(ctx context.Context, r io.Reader) (*ConditionImportResult, error) {
+	changes, failed, err := uc.mapper.Map(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ConditionImportResult{Errors: failed}
+	for _, change := range changes {
+		y, m, d := change.StartDate.In(jst).Date()
+		from := time.Date(y, m, d, 0, 0, 0, 0, jst)
+		to := from.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		logs, err := uc.uc.List(ctx, entity.ConditionFilter{From: from, To: to, Limit: maxLogsPerDay})
+		if err != nil {
+			log.Printf("warn: list condition logs for %s: %v", from.Format("2006-01-02"), err)
+			result.Errors++
+			continue
+		}
+		if len(logs.Items) == 0 {
+			cl := &entity.ConditionLog{
+				LoggedAt:   change.StartDate,
+				OverallVAS: importedMoodChangeVAS,
+				Note:       "Mood Changes recorded in Apple Health",
+				Tags:       []string{MoodChangesTag, AppleHealthTag},
+			}
+			if err := uc.uc.Create(ctx, cl); err != nil {
+				log.Printf("warn: create condition log for %s: %v", from.Format("2006-01-02"), err)
+				result.Errors++
+				continue
+			}
+			result.Imported++
+			continue
+		}
+
+		var tagged, updateFailed bool
+		for i := range logs.Items {
+			cl := &logs.Items[i]
+			if slices.Contains(cl.Tags, MoodChangesTag) {
+				continue
+			}
+			cl.Tags = append(cl.Tags, MoodChangesTag)
+			if err := uc.uc.Update(ctx, cl.ID, cl); err != nil {
+				log.Printf("warn: tag condition log %d with %s: %v", cl.ID, MoodChangesTag, err)
+				updateFailed = true
+				continue
+			}
+			tagged = true
+		}
+		switch {
+		case updateFailed:
+			result.Errors++
+		case tagged:
+			result.Tagged++
+		default:
+			result.Skipped++
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+// conditionDayRepo serves the live logs of each JST day, keyed "2006-01-02",
+// applies and records updates, and adds created logs to their day.
+func conditionDayRepo(days map[string][]entity.ConditionLog, updateErr error) (*mocks.MockConditionRepository, *[]entity.ConditionLog) {
+	var updated []entity.ConditionLog
+	return &mocks.MockConditionRepository{
+		CreateFunc: func(_ context.Context, cl *entity.ConditionLog) error {
+			if updateErr != nil {
+				return updateErr
+			}
+			day := cl.LoggedAt.In(jst).Format("2006-01-02")
+			days[day] = append(days[day], *cl)
+			return nil
+		},
+		ListFunc: func(_ context.Context, filter entity.ConditionFilter) (*entity.ConditionListResult, error) {
+			if filter.IncludeDeleted {
+				return nil, errors.New("soft-deleted logs must not be listed")
+			}
+			items := slices.Clone(days[filter.From.Format("2006-01-02")])
+			for i := range items {
+				items[i].Tags = slices.Clone(items[i].Tags)
+			}
+			return &entity.ConditionListResult{Items: items, Total: len(items)}, nil
+		},
+		GetByIDFunc: func(_ context.Context, id int64) (*entity.ConditionLog, error) {
+			return &entity.ConditionLog{ID: id}, nil
+		},
+		UpdateFunc: func(_ context.Context, cl *entity.ConditionLog) error {
+			if updateErr != nil {
+				return updateErr
+			}
+			updated = append(updated, *cl)
+			for _, logs := range days {
+				for i := range logs {
+					if logs[i].ID == cl.ID {
+						logs[i].Tags = slices.Clone(cl.Tags)
+					}
+				}
+			}
+			return nil
+		},
+	}, &updated
+}
+
+func TestImportAppleHealthConditions_Execute(t *testing.T) {
+	f, err := os.Open("../adapter/applehealthxml/testdata/export.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// The fixture has mood changes on 05-01 and 05-03. 05-01 has two logs,
+	// one already tagged; 05-03 has none (a soft-deleted one is not listed).
+	days := map[string][]entity.ConditionLog{
+		"2024-05-01": {
+			{ID: 1, OverallVAS: 40, Tags: []string{"work"}},
+			{ID: 2, OverallVAS: 55, Tags: []string{MoodChangesTag}},
+		},
+	}
+	repo, updated := conditionDayRepo(days, nil)
+	uc := NewImportAppleHealthConditionsUseCase(NewRecordConditionUseCase(repo))
+
+	got, err := uc.Execute(context.Background(), f)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	want := ConditionImportResult{Imported: 1, Tagged: 1, Errors: 2}
+	if *got != want {
+		t.Errorf("result = %+v, want %+v", *got, want)
+	}
+	if len(*updated) != 1 || (*updated)[0].ID != 1 || !slices.Equal((*updated)[0].Tags, []string{"work", MoodChangesTag}) {
+		t.Errorf("updated = %+v, want log 1 tagged %s", *updated, MoodChangesTag)
+	}
+	created := days["2024-05-03"]
+	if len(created) != 1 {
+		t.Fatalf("logs on 2024-05-03 = %+v, want one created", created)
+	}
+	if cl := created[0]; cl.OverallVAS != importedMoodChangeVAS ||
+		!slices.Equal(cl.Tags, []string{MoodChangesTag, AppleHealthTag}) ||
+		cl.Source != entity.ConditionSourceSpontaneous {
+		t.Errorf("created log = %+v, want neutral VAS tagged %s and %s", cl, MoodChangesTag, AppleHealthTag)
+	}
+
+	// Re-importing finds every day tagged and writes nothing.
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	got, err = uc.Execute(context.Background(), f)
+	if err != nil {
+		t.Fatalf("second Execute() error = %v", err)
+	}
+	if *got != (ConditionImportResult{Skipped: 2, Errors: 2}) || len(*updated) != 1 || len(days["2024-05-03"]) != 1 {
+		t.Errorf("re-import = %+v with %d updates; want 2 skipped and no writes", *got, len(*updated))
+	}
+}
+
+func TestImportAppleHealthConditions_Execute_AlreadyTagged(t *testing.T) {
+	f, err := os.Open("../adapter/applehealthxml/testdata/export.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	repo, updated := conditionDayRepo(map[string][]entity.ConditionLog{
+		"2024-05-01": {{ID: 1, OverallVAS: 40, Tags: []string{MoodChangesTag}}},
+		"2024-05-03": {{ID: 3, OverallVAS: 70, Tags: []string{MoodChangesTag}}},
+	}, nil)
+
+	got, err := NewImportAppleHealthConditionsUseCase(NewRecordConditionUseCase(repo)).Execute(context.Background(), f)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got.Skipped != 2 || got.Tagged != 0 || len(*updated) != 0 {
+		t.Errorf("result = %+v, updated = %d; want 2 skipped and no updates", *got, len(*updated))
+	}
+}
+
+func TestImportAppleHealthConditions_Execute_UpdateError(t *testing.T) {
+	f, err := os.Open("../adapter/applehealthxml/testdata/export.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	repo, _ := conditionDayRepo(map[string][]entity.ConditionLog{
+		"2024-05-01": {{ID: 1, OverallVAS: 40}},
+		"2024-05-03": {{ID: 3, OverallVAS: 70}},
+	}, errors.New("db down"))
+
+	got, err := NewImportAppleHealthConditionsUseCase(NewRecordConditionUseCase(repo)).Execute(context.Background(), f)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got.Tagged != 0 || got.Errors != 4 {
+		t.Errorf("result = %+v, want 0 tagged, 4 errors", *got)
+	}
+}
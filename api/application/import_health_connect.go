@@ -2,7 +2,9 @@ package application
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"vitametron/api/adapter/healthconnect"
@@ -12,10 +14,25 @@ import (
 
 // ImportResult contains counts of imported records.
 type ImportResult struct {
-	DatesImported int `json:"dates_imported"`
-	HRSamples     int `json:"hr_samples"`
-	SleepStages   int `json:"sleep_stages"`
-	ExerciseLogs  int `json:"exercise_logs"`
+	DatesImported  int `json:"dates_imported"`
+	HRSamples      int `json:"hr_samples"`
+	SleepStages    int `json:"sleep_stages"`
+	ExerciseLogs   int `json:"exercise_logs"`
+	ExerciseRoutes int `json:"exercise_routes"`
+
+	Devices []entity.DeviceInfo `json:"devices,omitempty"`
+}
+
+// ImportPreview describes what an import would write without writing it.
+// DateRange spans the extracted summary dates; ConflictingDates lists those
+// that already have a daily summary in the DB.
+type ImportPreview struct {
+	DatesFound        int              `json:"dates_found"`
+	HRSamplesFound    int              `json:"hr_samples_found"`
+	SleepStagesFound  int              `json:"sleep_stages_found"`
+	ExerciseLogsFound int              `json:"exercise_logs_found"`
+	DateRange         entity.DateRange `json:"date_range"`
+	ConflictingDates  []string         `json:"conflicting_dates"`
 }
 
 // ImportHealthConnectUseCase orchestrates Health Connect DB import.
@@ -24,6 +41,11 @@ type ImportHealthConnectUseCase struct {
 	hrRepo       port.HeartRateRepository
 	sleepRepo    port.SleepStageRepository
 	exerciseRepo port.ExerciseRepository
+	historyRepo  port.ImportHistoryRepository
+	routeRepo    port.ExerciseRouteRepository
+	vo2max       *ExerciseVO2MaxUseCase
+
+	skipIfFitbit bool
 }
 
 func NewImportHealthConnectUseCase(
@@ -40,23 +62,74 @@ func NewImportHealthConnectUseCase(
 	}
 }
 
+// WithSkipIfFitbit keeps existing Fitbit daily summaries instead of
+// overwriting them with Health Connect values.
+func (uc *ImportHealthConnectUseCase) WithSkipIfFitbit(skip bool) *ImportHealthConnectUseCase {
+	uc.skipIfFitbit = skip
+	return uc
+}
+
+// WithHistory enables recording per-job import metadata such as the devices
+// found in the export.
+func (uc *ImportHealthConnectUseCase) WithHistory(repo port.ImportHistoryRepository) *ImportHealthConnectUseCase {
+	uc.historyRepo = repo
+	return uc
+}
+
+// WithRoutes stores the GPS tracks of imported exercises in repo.
+func (uc *ImportHealthConnectUseCase) WithRoutes(repo port.ExerciseRouteRepository) *ImportHealthConnectUseCase {
+	uc.routeRepo = repo
+	return uc
+}
+
+// WithVO2MaxEstimate estimates VO2max for each imported exercise with an
+// average HR, using est, before it is stored.
+func (uc *ImportHealthConnectUseCase) WithVO2MaxEstimate(est *ExerciseVO2MaxUseCase) *ImportHealthConnectUseCase {
+	uc.vo2max = est
+	return uc
+}
+
+// RecordDevices stores the devices detected by an async import job. It is a
+// no-op when no history repository is configured.
+func (uc *ImportHealthConnectUseCase) RecordDevices(ctx context.Context, jobID string, devices []entity.DeviceInfo) error {
+	if uc.historyRepo == nil {
+		return nil
+	}
+	return uc.historyRepo.SaveDevices(ctx, jobID, "health_connect", devices)
+}
+
+// GetDevices returns the devices recorded for a completed import job, or nil
+// when the job is unknown or history is not configured.
+func (uc *ImportHealthConnectUseCase) GetDevices(ctx context.Context, jobID string) ([]entity.DeviceInfo, error) {
+	if uc.historyRepo == nil {
+		return nil, nil
+	}
+	return uc.historyRepo.GetDevices(ctx, jobID)
+}
+
+// ImportProgressFunc receives the number of daily summaries processed so
+// far out of the total found in the export.
+type ImportProgressFunc func(processed, total int)
+
+// summaryProgressBatch is how many summaries are processed between progress
+// reports.
+const summaryProgressBatch = 50
+
 func (uc *ImportHealthConnectUseCase) Execute(ctx context.Context, dbPath string) (*ImportResult, error) {
+	return uc.ExecuteWithProgress(ctx, dbPath, nil)
+}
+
+// ExecuteWithProgress is Execute with onProgress called after every batch of
+// daily summaries. onProgress may be nil.
+func (uc *ImportHealthConnectUseCase) ExecuteWithProgress(ctx context.Context, dbPath string, onProgress ImportProgressFunc) (*ImportResult, error) {
 	imp := &healthconnect.Importer{}
 	data, err := imp.Extract(dbPath)
 	if err != nil {
 		return nil, err
 	}
 
-	result := &ImportResult{}
-
-	// Upsert daily summaries one at a time
-	for i := range data.Summaries {
-		if err := uc.summaryRepo.Upsert(ctx, &data.Summaries[i]); err != nil {
-			log.Printf("warn: upsert summary for %s: %v", data.Summaries[i].Date.Format("2006-01-02"), err)
-			continue
-		}
-		result.DatesImported++
-	}
+	result := &ImportResult{Devices: data.Devices}
+	result.DatesImported = uc.importSummaries(ctx, data.Summaries, onProgress)
 
 	// Batch HR samples by day
 	hrByDay := groupHRByDay(data.HRSamples)
@@ -87,16 +160,123 @@ func (uc *ImportHealthConnectUseCase) Execute(ctx context.Context, dbPath string
 		result.SleepStages += len(stages)
 	}
 
-	// Upsert exercises
-	for i := range data.Exercises {
-		if err := uc.exerciseRepo.Upsert(ctx, &data.Exercises[i]); err != nil {
-			log.Printf("warn: upsert exercise %s: %v", data.Exercises[i].ExternalID, err)
+	stored := uc.importExercises(ctx, data.Exercises)
+	result.ExerciseLogs = len(stored)
+
+	if uc.routeRepo != nil {
+		result.ExerciseRoutes = uc.importRoutes(ctx, data.Routes, stored)
+	}
+
+	return result, nil
+}
+
+// importExercises upserts exercises and returns the external IDs stored.
+// Summaries are imported first, so their resting HR can feed the VO2max
+// estimate.
+func (uc *ImportHealthConnectUseCase) importExercises(ctx context.Context, exercises []entity.ExerciseLog) map[string]bool {
+	stored := make(map[string]bool, len(exercises))
+	for i := range exercises {
+		if uc.vo2max != nil {
+			if err := uc.vo2max.Fill(ctx, &exercises[i]); err != nil {
+				log.Printf("warn: estimate VO2max for exercise %s: %v", exercises[i].ExternalID, err)
+			}
+		}
+		if err := uc.exerciseRepo.Upsert(ctx, &exercises[i]); err != nil {
+			log.Printf("warn: upsert exercise %s: %v", exercises[i].ExternalID, err)
 			continue
 		}
-		result.ExerciseLogs++
+		stored[exercises[i].ExternalID] = true
 	}
+	return stored
+}
 
-	return result, nil
+// importRoutes saves the routes whose exercise was stored and returns how
+// many were written.
+func (uc *ImportHealthConnectUseCase) importRoutes(ctx context.Context, routes []entity.ExerciseRoute, stored map[string]bool) int {
+	saved := 0
+	for i := range routes {
+		if !stored[routes[i].ExerciseExternalID] {
+			continue
+		}
+		if err := uc.routeRepo.Save(ctx, &routes[i]); err != nil {
+			log.Printf("warn: save route for exercise %s: %v", routes[i].ExerciseExternalID, err)
+			continue
+		}
+		saved++
+	}
+	return saved
+}
+
+// Preview extracts the Health Connect DB at dbPath and reports what Execute
+// would import, without writing anything.
+func (uc *ImportHealthConnectUseCase) Preview(ctx context.Context, dbPath string) (*ImportPreview, error) {
+	imp := &healthconnect.Importer{}
+	data, err := imp.Extract(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	return uc.preview(ctx, data)
+}
+
+func (uc *ImportHealthConnectUseCase) preview(ctx context.Context, data *healthconnect.ImportData) (*ImportPreview, error) {
+	p := &ImportPreview{
+		DatesFound:        len(data.Summaries),
+		HRSamplesFound:    len(data.HRSamples),
+		SleepStagesFound:  len(data.SleepStages),
+		ExerciseLogsFound: len(data.Exercises),
+		ConflictingDates:  []string{},
+	}
+
+	for i := range data.Summaries {
+		date := data.Summaries[i].Date
+		if p.DateRange.From.IsZero() || date.Before(p.DateRange.From) {
+			p.DateRange.From = date
+		}
+		if date.After(p.DateRange.To) {
+			p.DateRange.To = date
+		}
+		existing, err := uc.summaryRepo.GetByDate(ctx, date)
+		if err != nil {
+			return nil, fmt.Errorf("check existing summary for %s: %w", date.Format("2006-01-02"), err)
+		}
+		if existing != nil {
+			p.ConflictingDates = append(p.ConflictingDates, date.Format("2006-01-02"))
+		}
+	}
+	sort.Strings(p.ConflictingDates)
+	return p, nil
+}
+
+// importSummaries upserts daily summaries one at a time and returns how many
+// were written. onProgress, when set, is called every summaryProgressBatch
+// summaries and once at the end.
+func (uc *ImportHealthConnectUseCase) importSummaries(ctx context.Context, summaries []entity.DailySummary, onProgress ImportProgressFunc) int {
+	imported := 0
+	for i := range summaries {
+		if onProgress != nil && i > 0 && i%summaryProgressBatch == 0 {
+			onProgress(i, len(summaries))
+		}
+		day := summaries[i].Date.Format("2006-01-02")
+		if uc.skipIfFitbit {
+			existing, err := uc.summaryRepo.GetByDate(ctx, summaries[i].Date)
+			if err != nil {
+				log.Printf("warn: get summary for %s: %v", day, err)
+			} else if existing != nil && existing.HasProvider("fitbit") {
+				log.Printf("info: skipping HC summary for %s — Fitbit data exists", day)
+				continue
+			}
+		}
+		summaries[i].ComputeActivityIntensityScore()
+		if err := uc.summaryRepo.Upsert(ctx, &summaries[i]); err != nil {
+			log.Printf("warn: upsert summary for %s: %v", day, err)
+			continue
+		}
+		imported++
+	}
+	if onProgress != nil {
+		onProgress(len(summaries), len(summaries))
+	}
+	return imported
 }
 
 func groupHRByDay(samples []entity.HeartRateSample) map[string][]entity.HeartRateSample {
@@ -0,0 +1,179 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vitametron/api/adapter/healthconnect"
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestImportSummaries_SkipIfFitbit(t *testing.T) {
+	fitbitDay := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	newDay := time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)
+	fitbitHRV := float32(42)
+
+	tests := []struct {
+		name         string
+		skipIfFitbit bool
+		wantUpserts  int
+		wantHRV      float32
+	}{
+		{"skip enabled preserves Fitbit", true, 1, 42},
+		{"skip disabled overwrites", false, 2, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := map[string]*entity.DailySummary{
+				"2025-01-10": {Date: fitbitDay, Providers: []string{"fitbit"}, HRVDailyRMSSD: &fitbitHRV},
+			}
+			upserts := 0
+			summaryRepo := &mocks.MockDailySummaryRepository{
+				GetByDateFunc: func(_ context.Context, date time.Time) (*entity.DailySummary, error) {
+					return stored[date.Format("2006-01-02")], nil
+				},
+				UpsertFunc: func(_ context.Context, s *entity.DailySummary) error {
+					upserts++
+					stored[s.Date.Format("2006-01-02")] = s
+					return nil
+				},
+			}
+
+			hcHRV := float32(30)
+			summaries := []entity.DailySummary{
+				{Date: fitbitDay, Providers: []string{"health_connect"}, HRVDailyRMSSD: &hcHRV},
+				{Date: newDay, Providers: []string{"health_connect"}, HRVDailyRMSSD: &hcHRV},
+			}
+
+			uc := NewImportHealthConnectUseCase(summaryRepo, nil, nil, nil).WithSkipIfFitbit(tt.skipIfFitbit)
+			got := uc.importSummaries(context.Background(), summaries, nil)
+
+			if got != tt.wantUpserts || upserts != tt.wantUpserts {
+				t.Errorf("imported = %d, upserts = %d, want %d", got, upserts, tt.wantUpserts)
+			}
+			if hrv := *stored["2025-01-10"].HRVDailyRMSSD; hrv != tt.wantHRV {
+				t.Errorf("HRVDailyRMSSD = %v, want %v", hrv, tt.wantHRV)
+			}
+			if _, ok := stored["2025-01-11"]; !ok {
+				t.Error("summary for 2025-01-11 not imported")
+			}
+		})
+	}
+}
+
+func TestImportPreview_NoWrites(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		GetByDateFunc: func(_ context.Context, date time.Time) (*entity.DailySummary, error) {
+			if date.Day() == 12 || date.Day() == 10 {
+				return &entity.DailySummary{Date: date}, nil
+			}
+			return nil, nil
+		},
+		// UpsertFunc left nil: any write would panic.
+	}
+	data := &healthconnect.ImportData{
+		Summaries:   []entity.DailySummary{{Date: day(12)}, {Date: day(10)}, {Date: day(11)}},
+		HRSamples:   make([]entity.HeartRateSample, 5),
+		SleepStages: make([]entity.SleepStage, 3),
+		Exercises:   make([]entity.ExerciseLog, 1),
+	}
+
+	uc := NewImportHealthConnectUseCase(summaryRepo, nil, nil, nil)
+	p, err := uc.preview(context.Background(), data)
+	if err != nil {
+		t.Fatalf("preview() error = %v", err)
+	}
+	if p.DatesFound != 3 || p.HRSamplesFound != 5 || p.SleepStagesFound != 3 || p.ExerciseLogsFound != 1 {
+		t.Errorf("counts = %+v", p)
+	}
+	if !p.DateRange.From.Equal(day(10)) || !p.DateRange.To.Equal(day(12)) {
+		t.Errorf("DateRange = %v..%v, want 2025-01-10..2025-01-12", p.DateRange.From, p.DateRange.To)
+	}
+	if len(p.ConflictingDates) != 2 || p.ConflictingDates[0] != "2025-01-10" || p.ConflictingDates[1] != "2025-01-12" {
+		t.Errorf("ConflictingDates = %v, want [2025-01-10 2025-01-12]", p.ConflictingDates)
+	}
+}
+
+func TestImportSummaries_ReportsProgress(t *testing.T) {
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		UpsertFunc: func(context.Context, *entity.DailySummary) error { return nil },
+	}
+	summaries := make([]entity.DailySummary, 2*summaryProgressBatch+1)
+	for i := range summaries {
+		summaries[i].Date = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+	}
+
+	var got [][2]int
+	uc := NewImportHealthConnectUseCase(summaryRepo, nil, nil, nil)
+	uc.importSummaries(context.Background(), summaries, func(processed, total int) {
+		got = append(got, [2]int{processed, total})
+	})
+
+	n := len(summaries)
+	want := [][2]int{{summaryProgressBatch, n}, {2 * summaryProgressBatch, n}, {n, n}}
+	if len(got) != len(want) {
+		t.Fatalf("progress calls = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("progress[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestImportRoutes_OnlyStoredExercises(t *testing.T) {
+	var saved []string
+	uc := NewImportHealthConnectUseCase(nil, nil, nil, nil).WithRoutes(&mocks.MockExerciseRouteRepository{
+		SaveFunc: func(_ context.Context, route *entity.ExerciseRoute) error {
+			saved = append(saved, route.ExerciseExternalID)
+			return nil
+		},
+	})
+	routes := []entity.ExerciseRoute{
+		{ExerciseExternalID: "hc-stored", Points: make([]entity.GPXPoint, 3)},
+		{ExerciseExternalID: "hc-failed", Points: make([]entity.GPXPoint, 2)},
+	}
+
+	n := uc.importRoutes(context.Background(), routes, map[string]bool{"hc-stored": true})
+	if n != 1 || len(saved) != 1 || saved[0] != "hc-stored" {
+		t.Errorf("importRoutes() = %d, saved %v, want only hc-stored", n, saved)
+	}
+}
+
+func TestImportExercises_EstimatesVO2Max(t *testing.T) {
+	summaries := &mocks.MockDailySummaryRepository{
+		GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+			return &entity.DailySummary{RestingHR: 60, MaxHR: 180}, nil
+		},
+	}
+	upserted := make(map[string]*float32)
+	exercises := &mocks.MockExerciseRepository{
+		UpsertFunc: func(_ context.Context, l *entity.ExerciseLog) error {
+			upserted[l.ExternalID] = l.EstimatedVO2Max
+			return nil
+		},
+	}
+	uc := NewImportHealthConnectUseCase(summaries, nil, nil, exercises).
+		WithVO2MaxEstimate(NewExerciseVO2MaxUseCase(exercises, summaries, 0))
+
+	started := time.Date(2025, 1, 11, 7, 0, 0, 0, jst)
+	stored := uc.importExercises(context.Background(), []entity.ExerciseLog{
+		{ExternalID: "hc-run", StartedAt: started, AvgHR: 150},
+		{ExternalID: "hc-walk", StartedAt: started},
+	})
+
+	if len(stored) != 2 {
+		t.Fatalf("stored = %v, want both exercises", stored)
+	}
+	// 15 * (180 / 60)
+	if v := upserted["hc-run"]; v == nil || *v != 45 {
+		t.Errorf("hc-run VO2max = %v, want 45", v)
+	}
+	if v := upserted["hc-walk"]; v != nil {
+		t.Errorf("hc-walk VO2max = %v, want nil without avg HR", *v)
+	}
+}
@@ -12,15 +12,41 @@ type ConditionUseCase interface {
 	GetByID(ctx context.Context, id int64) (*entity.ConditionLog, error)
 	List(ctx context.Context, filter entity.ConditionFilter) (*entity.ConditionListResult, error)
 	Update(ctx context.Context, id int64, log *entity.ConditionLog) error
+	GetHistory(ctx context.Context, id int64) ([]entity.ConditionLogHistory, error)
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (*entity.ConditionLog, error)
+	DeletePermanent(ctx context.Context, id int64) error
 	GetTags(ctx context.Context) ([]entity.TagCount, error)
-	GetSummary(ctx context.Context, from, to time.Time) (*entity.ConditionSummary, error)
+	GetSummary(ctx context.Context, filter entity.ConditionFilter) (*entity.ConditionSummary, error)
+	GetHeatmap(ctx context.Context, year int) ([]entity.HeatmapDay, error)
+	GetWeekly(ctx context.Context, from, to time.Time) ([]entity.ConditionWeekly, error)
+	Archive(ctx context.Context, before time.Time) (int64, error)
+	RenameTag(ctx context.Context, oldTag, newTag string) (int64, error)
 }
 
 type SyncUseCase interface {
 	SyncDate(ctx context.Context, date time.Time) error
 }
 
+// SyncReportUseCase syncs a date and reports what was stored.
+type SyncReportUseCase interface {
+	SyncDateReport(ctx context.Context, date time.Time) (*entity.SyncReport, error)
+}
+
+// SyncScheduler exposes the periodic sync interval for runtime changes.
+type SyncScheduler interface {
+	Interval() time.Duration
+	SetInterval(d time.Duration)
+}
+
+type DigestUseCase interface {
+	Send(ctx context.Context, weekStart time.Time) (*entity.WeeklyDigest, error)
+}
+
+type VRIAlertUseCase interface {
+	Send(ctx context.Context, weekStart time.Time) (*entity.VRIAlertDigest, error)
+}
+
 type InsightsUseCase interface {
 	GetWeeklyInsights(ctx context.Context, date time.Time) (*InsightsResult, error)
 }
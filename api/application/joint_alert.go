@@ -0,0 +1,85 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// jointAlertVRIThreshold is the VRI score below which an anomalous day
+// becomes a joint alert.
+const jointAlertVRIThreshold = 40
+
+// JointAlertAnalyzer finds days where a low VRI and an anomaly coincide.
+type JointAlertAnalyzer struct {
+	vriRepo     port.VRIRepository
+	anomalyRepo port.AnomalyRepository
+}
+
+func NewJointAlertAnalyzer(vriRepo port.VRIRepository, anomalyRepo port.AnomalyRepository) *JointAlertAnalyzer {
+	return &JointAlertAnalyzer{vriRepo: vriRepo, anomalyRepo: anomalyRepo}
+}
+
+// Alerts returns the joint alerts in [from, to], oldest first.
+func (a *JointAlertAnalyzer) Alerts(ctx context.Context, from, to time.Time) ([]entity.JointAlert, error) {
+	scores, err := a.vriRepo.ListRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	detections, err := a.anomalyRepo.ListRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return findJointAlerts(scores, detections), nil
+}
+
+// findJointAlerts pairs VRI scores and anomaly detections by JST day and
+// keeps the days with VRIScore below 40 and IsAnomaly set. A day is critical
+// when its VRI score is at or below the 25th percentile of all scores given
+// and its normalized anomaly score at or above the 75th percentile of all
+// detections given; otherwise it is high.
+func findJointAlerts(scores []entity.VRIScore, detections []entity.AnomalyDetection) []entity.JointAlert {
+	vriValues := make([]float64, len(scores))
+	vriByDay := make(map[string]*entity.VRIScore, len(scores))
+	for i := range scores {
+		vriValues[i] = float64(scores[i].VRIScore)
+		vriByDay[scores[i].Date.In(jst).Format("2006-01-02")] = &scores[i]
+	}
+	anomalyValues := make([]float64, len(detections))
+	for i := range detections {
+		anomalyValues[i] = float64(detections[i].NormalizedScore)
+	}
+	sort.Float64s(vriValues)
+	sort.Float64s(anomalyValues)
+	vriWorst := percentile(vriValues, 25)
+	anomalyWorst := percentile(anomalyValues, 75)
+
+	alerts := []entity.JointAlert{}
+	for i := range detections {
+		d := &detections[i]
+		if !d.IsAnomaly {
+			continue
+		}
+		day := d.Date.In(jst).Format("2006-01-02")
+		v, ok := vriByDay[day]
+		if !ok || v.VRIScore >= jointAlertVRIThreshold {
+			continue
+		}
+		severity := entity.JointSeverityHigh
+		if float64(v.VRIScore) <= vriWorst && float64(d.NormalizedScore) >= anomalyWorst {
+			severity = entity.JointSeverityCritical
+		}
+		alerts = append(alerts, entity.JointAlert{
+			Date:                   day,
+			VRIScore:               v.VRIScore,
+			AnomalyScore:           d.AnomalyScore,
+			NormalizedAnomalyScore: d.NormalizedScore,
+			JointSeverity:          severity,
+		})
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Date < alerts[j].Date })
+	return alerts
+}
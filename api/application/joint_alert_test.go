@@ -0,0 +1,86 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestFindJointAlerts(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	vri := []float32{70, 65, 60, 55, 50, 38, 30, 20}
+	scores := make([]entity.VRIScore, len(vri))
+	for i, v := range vri {
+		scores[i] = entity.VRIScore{Date: day(i + 1), VRIScore: v}
+	}
+	// Newest first, as the repository may return them.
+	detections := []entity.AnomalyDetection{
+		{Date: day(9), NormalizedScore: 0.99, IsAnomaly: true}, // no VRI that day
+		{Date: day(8), AnomalyScore: -0.4, NormalizedScore: 0.95, IsAnomaly: true},
+		{Date: day(7), AnomalyScore: -0.2, NormalizedScore: 0.6, IsAnomaly: true},
+		{Date: day(6), AnomalyScore: -0.3, NormalizedScore: 0.9, IsAnomaly: true},
+		{Date: day(5), NormalizedScore: 0.1, IsAnomaly: true}, // VRI 50 is not low
+		{Date: day(4), NormalizedScore: 0.2},
+		{Date: day(3), NormalizedScore: 0.1},
+		{Date: day(2), NormalizedScore: 0.2},
+		{Date: day(1), NormalizedScore: 0.1},
+	}
+
+	// Worst quartiles: VRI <= 36, normalized anomaly >= 0.9.
+	want := []entity.JointAlert{
+		{Date: "2025-03-06", VRIScore: 38, AnomalyScore: -0.3, NormalizedAnomalyScore: 0.9, JointSeverity: entity.JointSeverityHigh},
+		{Date: "2025-03-07", VRIScore: 30, AnomalyScore: -0.2, NormalizedAnomalyScore: 0.6, JointSeverity: entity.JointSeverityHigh},
+		{Date: "2025-03-08", VRIScore: 20, AnomalyScore: -0.4, NormalizedAnomalyScore: 0.95, JointSeverity: entity.JointSeverityCritical},
+	}
+	got := findJointAlerts(scores, detections)
+	if len(got) != len(want) {
+		t.Fatalf("findJointAlerts() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("alerts[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFindJointAlerts_Empty(t *testing.T) {
+	got := findJointAlerts(nil, nil)
+	if got == nil || len(got) != 0 {
+		t.Errorf("findJointAlerts(nil, nil) = %#v, want empty slice", got)
+	}
+}
+
+func TestJointAlertAnalyzer_Alerts(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	var vriFrom, vriTo, anomalyFrom, anomalyTo time.Time
+	a := NewJointAlertAnalyzer(
+		&mocks.MockVRIRepository{
+			ListRangeFunc: func(_ context.Context, f, t time.Time) ([]entity.VRIScore, error) {
+				vriFrom, vriTo = f, t
+				return []entity.VRIScore{{Date: from, VRIScore: 25}}, nil
+			},
+		},
+		&mocks.MockAnomalyRepository{
+			ListRangeFunc: func(_ context.Context, f, t time.Time) ([]entity.AnomalyDetection, error) {
+				anomalyFrom, anomalyTo = f, t
+				return []entity.AnomalyDetection{{Date: from, NormalizedScore: 0.8, IsAnomaly: true}}, nil
+			},
+		},
+	)
+
+	alerts, err := a.Alerts(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Alerts() error = %v", err)
+	}
+	if !vriFrom.Equal(from) || !vriTo.Equal(to) || !anomalyFrom.Equal(from) || !anomalyTo.Equal(to) {
+		t.Errorf("ListRange called with VRI %v..%v, anomaly %v..%v", vriFrom, vriTo, anomalyFrom, anomalyTo)
+	}
+	// A single day is both quartiles' worst value.
+	if len(alerts) != 1 || alerts[0].JointSeverity != entity.JointSeverityCritical {
+		t.Errorf("Alerts() = %+v, want one critical alert", alerts)
+	}
+}
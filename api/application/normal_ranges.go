@@ -0,0 +1,183 @@
+package application
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// NormalRangesUseCase computes personal reference ranges from recent valid days.
+type NormalRangesUseCase struct {
+	summaryRepo port.DailySummaryRepository
+	qualityRepo port.DataQualityRepository
+}
+
+func NewNormalRangesUseCase(
+	summaryRepo port.DailySummaryRepository,
+	qualityRepo port.DataQualityRepository,
+) *NormalRangesUseCase {
+	return &NormalRangesUseCase{summaryRepo: summaryRepo, qualityRepo: qualityRepo}
+}
+
+// normalRangesLookbackFactor bounds how far back Ranges looks for valid
+// days: windowDays valid days are sought within windowDays times this many
+// calendar days.
+const normalRangesLookbackFactor = 3
+
+// Execute builds ranges from the last windowDays valid days before date and
+// flags whether date's own values fall inside them.
+func (uc *NormalRangesUseCase) Execute(ctx context.Context, date time.Time, windowDays int) (*entity.NormalRanges, error) {
+	result, err := uc.Ranges(ctx, date, windowDays)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.FlagToday(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Ranges builds ranges from the last windowDays valid days before date,
+// without today's values, so the result can be cached for the day.
+func (uc *NormalRangesUseCase) Ranges(ctx context.Context, date time.Time, windowDays int) (*entity.NormalRanges, error) {
+	from := date.AddDate(0, 0, -windowDays*normalRangesLookbackFactor)
+	to := date.AddDate(0, 0, -1)
+
+	summaries, err := uc.summaryRepo.ListRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	qualities, err := uc.qualityRepo.ListRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	invalid := make(map[string]bool, len(qualities))
+	for _, q := range qualities {
+		if !q.IsValidDay {
+			invalid[q.Date.Format("2006-01-02")] = true
+		}
+	}
+
+	// Days without a quality record (e.g. imported history) are kept; only
+	// days explicitly marked invalid are dropped. The newest come first so
+	// the window holds the most recent valid days.
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Date.After(summaries[j].Date) })
+	valid := make([]entity.DailySummary, 0, windowDays)
+	for _, s := range summaries {
+		if len(valid) == windowDays {
+			break
+		}
+		if !invalid[s.Date.Format("2006-01-02")] {
+			valid = append(valid, s)
+		}
+	}
+
+	return &entity.NormalRanges{
+		Date:       date,
+		WindowDays: windowDays,
+		DaysUsed:   len(valid),
+		Ranges:     computeNormalRanges(valid),
+	}, nil
+}
+
+// FlagToday sets each range's TodayValue and TodayInRange from the summary
+// of result.Date, if there is one yet.
+func (uc *NormalRangesUseCase) FlagToday(ctx context.Context, result *entity.NormalRanges) error {
+	today, err := uc.summaryRepo.GetByDate(ctx, result.Date)
+	if err != nil {
+		return err
+	}
+	if today != nil {
+		flagTodayInRange(result.Ranges, today)
+	}
+	return nil
+}
+
+// computeNormalRanges returns mean ± 1 SD and the 5th/95th percentiles for
+// every metric with at least two observations.
+func computeNormalRanges(summaries []entity.DailySummary) []entity.MetricNormalRange {
+	ranges := make([]entity.MetricNormalRange, 0, len(entity.SummaryMetrics))
+	for _, m := range entity.SummaryMetrics {
+		values := make([]float64, 0, len(summaries))
+		for i := range summaries {
+			if v, ok := m.Value(&summaries[i]); ok {
+				values = append(values, v)
+			}
+		}
+		if len(values) < 2 {
+			continue
+		}
+
+		mean, sd := meanStdDev(values)
+		sort.Float64s(values)
+		ranges = append(ranges, entity.MetricNormalRange{
+			Metric:      m.Name,
+			Mean:        float32(mean),
+			StdDev:      float32(sd),
+			Lower:       float32(mean - sd),
+			Upper:       float32(mean + sd),
+			P5:          float32(percentile(values, 5)),
+			P95:         float32(percentile(values, 95)),
+			SampleCount: len(values),
+		})
+	}
+	return ranges
+}
+
+// flagTodayInRange records today's value for each metric and whether it lies
+// within mean ± 1 SD.
+func flagTodayInRange(ranges []entity.MetricNormalRange, today *entity.DailySummary) {
+	values := make(map[string]float64, len(entity.SummaryMetrics))
+	for _, m := range entity.SummaryMetrics {
+		if v, ok := m.Value(today); ok {
+			values[m.Name] = v
+		}
+	}
+	for i := range ranges {
+		v, ok := values[ranges[i].Metric]
+		if !ok {
+			continue
+		}
+		tv := float32(v)
+		in := tv >= ranges[i].Lower && tv <= ranges[i].Upper
+		ranges[i].TodayValue = &tv
+		ranges[i].TodayInRange = &in
+	}
+}
+
+// meanStdDev returns the mean and sample standard deviation.
+func meanStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(values)-1))
+}
+
+// percentile returns the p-th percentile of sorted values using linear interpolation.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (rank-float64(lo))*(sorted[hi]-sorted[lo])
+}
@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestComputeNormalRanges(t *testing.T) {
+	summaries := []entity.DailySummary{
+		{RestingHR: 58, Steps: 8000, HRVDailyRMSSD: entity.Float32Ptr(40)},
+		{RestingHR: 60, Steps: 10000},
+		{RestingHR: 62, Steps: 12000},
+		{RestingHR: 0, Steps: 6000}, // missing resting HR is skipped
+	}
+
+	ranges := computeNormalRanges(summaries)
+	byMetric := make(map[string]entity.MetricNormalRange)
+	for _, r := range ranges {
+		byMetric[r.Metric] = r
+	}
+
+	tests := []struct {
+		metric    string
+		wantMean  float32
+		wantSD    float32
+		wantP5    float32
+		wantP95   float32
+		wantCount int
+	}{
+		{"resting_hr", 60, 2, 58.2, 61.8, 3},
+		{"steps", 9000, 2581.99, 6300, 11700, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			r, ok := byMetric[tt.metric]
+			if !ok {
+				t.Fatalf("metric %q missing", tt.metric)
+			}
+			if r.SampleCount != tt.wantCount {
+				t.Errorf("SampleCount = %d, want %d", r.SampleCount, tt.wantCount)
+			}
+			for _, c := range []struct {
+				name      string
+				got, want float32
+			}{
+				{"Mean", r.Mean, tt.wantMean},
+				{"StdDev", r.StdDev, tt.wantSD},
+				{"Lower", r.Lower, tt.wantMean - tt.wantSD},
+				{"Upper", r.Upper, tt.wantMean + tt.wantSD},
+				{"P5", r.P5, tt.wantP5},
+				{"P95", r.P95, tt.wantP95},
+			} {
+				if math.Abs(float64(c.got-c.want)) > 0.01 {
+					t.Errorf("%s = %f, want %f", c.name, c.got, c.want)
+				}
+			}
+		})
+	}
+
+	if _, ok := byMetric["hrv_daily_rmssd"]; ok {
+		t.Error("metric with a single observation should be omitted")
+	}
+}
+
+func TestNormalRangesUseCase_Execute(t *testing.T) {
+	date := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		ListRangeFunc: func(_ context.Context, from, to time.Time) ([]entity.DailySummary, error) {
+			if !from.Equal(date.AddDate(0, 0, -90)) || !to.Equal(date.AddDate(0, 0, -1)) {
+				t.Errorf("ListRange(%v, %v), want the 90-day lookback before date", from, to)
+			}
+			return []entity.DailySummary{
+				{Date: day(1), RestingHR: 58},
+				{Date: day(2), RestingHR: 62},
+				{Date: day(3), RestingHR: 90}, // invalid day, excluded
+			}, nil
+		},
+		GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+			return &entity.DailySummary{Date: date, RestingHR: 70}, nil
+		},
+	}
+	qualityRepo := &mocks.MockDataQualityRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DataQuality, error) {
+			return []entity.DataQuality{
+				{Date: day(1), IsValidDay: true},
+				{Date: day(3), IsValidDay: false},
+			}, nil
+		},
+	}
+
+	uc := NewNormalRangesUseCase(summaryRepo, qualityRepo)
+	result, err := uc.Execute(context.Background(), date, 30)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.DaysUsed != 2 {
+		t.Errorf("DaysUsed = %d, want 2", result.DaysUsed)
+	}
+	if len(result.Ranges) != 1 || result.Ranges[0].Metric != "resting_hr" {
+		t.Fatalf("Ranges = %+v, want only resting_hr", result.Ranges)
+	}
+	r := result.Ranges[0]
+	if r.Mean != 60 {
+		t.Errorf("Mean = %f, want 60", r.Mean)
+	}
+	if r.TodayValue == nil || *r.TodayValue != 70 {
+		t.Errorf("TodayValue = %v, want 70", r.TodayValue)
+	}
+	if r.TodayInRange == nil || *r.TodayInRange {
+		t.Errorf("TodayInRange = %v, want false", r.TodayInRange)
+	}
+}
+
+func TestNormalRangesUseCase_Ranges_LastValidDays(t *testing.T) {
+	date := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	// Oldest first, as ListRange returns them. With a 3-day window the
+	// newest three valid days are 30, 28 and 27; day 29 is invalid and the
+	// older days fall outside the window.
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+			return []entity.DailySummary{
+				{Date: day(10), RestingHR: 90},
+				{Date: day(26), RestingHR: 90},
+				{Date: day(27), RestingHR: 58},
+				{Date: day(28), RestingHR: 60},
+				{Date: day(29), RestingHR: 90},
+				{Date: day(30), RestingHR: 62},
+			}, nil
+		},
+	}
+	qualityRepo := &mocks.MockDataQualityRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DataQuality, error) {
+			return []entity.DataQuality{{Date: day(29), IsValidDay: false}}, nil
+		},
+	}
+
+	result, err := NewNormalRangesUseCase(summaryRepo, qualityRepo).Ranges(context.Background(), date, 3)
+	if err != nil {
+		t.Fatalf("Ranges() error = %v", err)
+	}
+	if result.DaysUsed != 3 {
+		t.Errorf("DaysUsed = %d, want 3", result.DaysUsed)
+	}
+	if len(result.Ranges) != 1 || result.Ranges[0].Mean != 60 {
+		t.Fatalf("Ranges = %+v, want resting_hr with mean 60", result.Ranges)
+	}
+	if result.Ranges[0].TodayValue != nil {
+		t.Errorf("TodayValue = %v, want nil before FlagToday", *result.Ranges[0].TodayValue)
+	}
+}
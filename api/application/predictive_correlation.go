@@ -0,0 +1,133 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// minPredictivePairs is the fewest condition/lagged-metric day pairs for a
+// correlation. The Fisher interval needs more than three.
+const minPredictivePairs = 10
+
+// fisherZ95 is the two-sided 95% normal quantile used for the interval.
+const fisherZ95 = 1.959964
+
+// ErrUnknownSummaryMetric is returned for a metric name not in entity.SummaryMetrics.
+var ErrUnknownSummaryMetric = errors.New("unknown daily summary metric")
+
+// PredictiveCorrelationAnalyzer tests whether wellbeing on one day predicts a
+// biometric some days later.
+type PredictiveCorrelationAnalyzer struct {
+	conditionRepo port.ConditionRepository
+	summaryRepo   port.DailySummaryRepository
+}
+
+func NewPredictiveCorrelationAnalyzer(conditionRepo port.ConditionRepository, summaryRepo port.DailySummaryRepository) *PredictiveCorrelationAnalyzer {
+	return &PredictiveCorrelationAnalyzer{conditionRepo: conditionRepo, summaryRepo: summaryRepo}
+}
+
+// Correlate pairs the condition logs of each JST day in [from, to] with the
+// metric's value lagDays later.
+func (a *PredictiveCorrelationAnalyzer) Correlate(ctx context.Context, metric string, lagDays int, from, to time.Time) (*entity.PredictiveCorrelation, error) {
+	if _, ok := LookupSummaryMetric(metric); !ok {
+		return nil, ErrUnknownSummaryMetric
+	}
+	logs, err := a.conditionRepo.ListRange(ctx, from, to.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := a.summaryRepo.ListRange(ctx, from.AddDate(0, 0, lagDays), to.AddDate(0, 0, lagDays))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := computeLaggedCorrelation(logs, summaries, metric, lagDays)
+	if err != nil {
+		return nil, err
+	}
+	result.From = from
+	result.To = to
+	return result, nil
+}
+
+// computeLaggedCorrelation averages the overall VAS of logs per JST day D and
+// pairs it with the summary of day D+lagDays that has metric. It returns the
+// Pearson r with a Fisher z 95% confidence interval once minPredictivePairs
+// days pair up.
+func computeLaggedCorrelation(logs []entity.ConditionLog, summaries []entity.DailySummary, metric string, lagDays int) (*entity.PredictiveCorrelation, error) {
+	m, ok := LookupSummaryMetric(metric)
+	if !ok {
+		return nil, ErrUnknownSummaryMetric
+	}
+
+	metricByDate := make(map[string]float64, len(summaries))
+	for i := range summaries {
+		if v, ok := m.Value(&summaries[i]); ok {
+			metricByDate[summaries[i].Date.Format("2006-01-02")] = v
+		}
+	}
+
+	type dayVAS struct {
+		sum float64
+		n   int
+	}
+	var days []string
+	vasByDate := make(map[string]*dayVAS)
+	for _, l := range logs {
+		local := l.LoggedAt.In(jst)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+		key := day.Format("2006-01-02")
+		d, ok := vasByDate[key]
+		if !ok {
+			d = &dayVAS{}
+			vasByDate[key] = d
+			days = append(days, key)
+		}
+		d.sum += float64(l.OverallVAS)
+		d.n++
+	}
+
+	var vas, values []float64
+	for _, key := range days {
+		day, _ := time.Parse("2006-01-02", key)
+		v, ok := metricByDate[day.AddDate(0, 0, lagDays).Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		d := vasByDate[key]
+		vas = append(vas, d.sum/float64(d.n))
+		values = append(values, v)
+	}
+
+	result := &entity.PredictiveCorrelation{
+		Metric:   metric,
+		LagDays:  lagDays,
+		Pairs:    len(vas),
+		MinPairs: minPredictivePairs,
+	}
+	if len(vas) < minPredictivePairs {
+		return result, nil
+	}
+	r, ok := pearson(vas, values)
+	if !ok {
+		return result, nil
+	}
+	z := math.Atanh(math.Max(-0.999999, math.Min(0.999999, r)))
+	se := 1 / math.Sqrt(float64(len(vas)-3))
+	lower := round3(math.Tanh(z - fisherZ95*se))
+	upper := round3(math.Tanh(z + fisherZ95*se))
+	r = round3(r)
+	result.Correlation = &r
+	result.CILower = &lower
+	result.CIUpper = &upper
+	return result, nil
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestComputeLaggedCorrelation(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Day i has two logs averaging 80-2i, logged late in the JST evening
+	// (still day i in JST, already day i in UTC). Resting HR two days later
+	// is 50+i, so wellbeing and lagged HR are perfectly anti-correlated.
+	var logs []entity.ConditionLog
+	var summaries []entity.DailySummary
+	for i := 0; i < 12; i++ {
+		day := start.AddDate(0, 0, i)
+		at := time.Date(day.Year(), day.Month(), day.Day(), 23, 0, 0, 0, jst)
+		logs = append(logs,
+			entity.ConditionLog{LoggedAt: at, OverallVAS: 80 - 2*i - 5},
+			entity.ConditionLog{LoggedAt: at.Add(30 * time.Minute), OverallVAS: 80 - 2*i + 5},
+		)
+		summaries = append(summaries, entity.DailySummary{Date: day.AddDate(0, 0, 2), RestingHR: 50 + i})
+	}
+	// A summary without resting HR leaves its day unpaired.
+	summaries[3].RestingHR = 0
+
+	got, err := computeLaggedCorrelation(logs, summaries, "resting_hr", 2)
+	if err != nil {
+		t.Fatalf("computeLaggedCorrelation() error = %v", err)
+	}
+	if got.Pairs != 11 || got.LagDays != 2 || got.Metric != "resting_hr" {
+		t.Errorf("result = %+v, want 11 pairs at lag 2", got)
+	}
+	if got.Correlation == nil || *got.Correlation != -1 {
+		t.Fatalf("Correlation = %v, want -1", got.Correlation)
+	}
+	if *got.CILower != -1 || *got.CIUpper > -0.99 {
+		t.Errorf("CI = [%v, %v], want tight around -1", *got.CILower, *got.CIUpper)
+	}
+
+	// At lag 1, day 0 has no next-day summary and day 4 pairs with the gap.
+	got, err = computeLaggedCorrelation(logs, summaries, "resting_hr", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Pairs != 10 {
+		t.Errorf("lag 1 Pairs = %d, want 10", got.Pairs)
+	}
+}
+
+func TestComputeLaggedCorrelation_ConfidenceInterval(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	noise := []int{3, -4, 1, 6, -2, -5, 2, 4, -1, -3, 5, 0, -6, 2, 1, -2, 4, -4, 3, -1}
+
+	var logs []entity.ConditionLog
+	var summaries []entity.DailySummary
+	for i, n := range noise {
+		day := start.AddDate(0, 0, i)
+		logs = append(logs, entity.ConditionLog{LoggedAt: day.Add(3 * time.Hour), OverallVAS: 40 + 2*i})
+		summaries = append(summaries, entity.DailySummary{Date: day.AddDate(0, 0, 1), RestingHR: 60 + i + n})
+	}
+
+	got, err := computeLaggedCorrelation(logs, summaries, "resting_hr", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Correlation == nil || got.CILower == nil || got.CIUpper == nil {
+		t.Fatalf("result = %+v, want correlation with interval", got)
+	}
+	r, lo, hi := *got.Correlation, *got.CILower, *got.CIUpper
+	if r <= 0.5 || r >= 1 {
+		t.Errorf("Correlation = %v, want strong positive", r)
+	}
+	if !(lo < r && r < hi && hi <= 1) {
+		t.Errorf("CI = [%v, %v] does not bracket r = %v", lo, hi, r)
+	}
+}
+
+func TestComputeLaggedCorrelation_TooFewPairs(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	logs := []entity.ConditionLog{{LoggedAt: day.Add(3 * time.Hour), OverallVAS: 50}}
+	summaries := []entity.DailySummary{{Date: day.AddDate(0, 0, 1), RestingHR: 60}}
+
+	got, err := computeLaggedCorrelation(logs, summaries, "resting_hr", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Pairs != 1 || got.Correlation != nil || got.CILower != nil {
+		t.Errorf("result = %+v, want 1 pair and no correlation", got)
+	}
+}
+
+func TestComputeLaggedCorrelation_UnknownMetric(t *testing.T) {
+	if _, err := computeLaggedCorrelation(nil, nil, "mood", 1); !errors.Is(err, ErrUnknownSummaryMetric) {
+		t.Errorf("error = %v, want ErrUnknownSummaryMetric", err)
+	}
+}
+
+func TestPredictiveCorrelationAnalyzer_ShiftsSummaryRange(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, jst)
+	to := from.AddDate(0, 0, 59)
+
+	var gotFrom, gotTo time.Time
+	summaries := &mocks.MockDailySummaryRepository{
+		ListRangeFunc: func(_ context.Context, from, to time.Time) ([]entity.DailySummary, error) {
+			gotFrom, gotTo = from, to
+			return nil, nil
+		},
+	}
+	conditions := &mocks.MockConditionRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.ConditionLog, error) {
+			return nil, nil
+		},
+	}
+
+	got, err := NewPredictiveCorrelationAnalyzer(conditions, summaries).Correlate(context.Background(), "resting_hr", 3, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gotFrom.Equal(from.AddDate(0, 0, 3)) || !gotTo.Equal(to.AddDate(0, 0, 3)) {
+		t.Errorf("summary range = %v..%v, want shifted by 3 days", gotFrom, gotTo)
+	}
+	if !got.From.Equal(from) || !got.To.Equal(to) {
+		t.Errorf("result range = %v..%v, want %v..%v", got.From, got.To, from, to)
+	}
+}
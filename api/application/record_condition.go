@@ -2,12 +2,23 @@ package application
 
 import (
 	"context"
+	"log"
+	"math"
 	"time"
 
 	"vitametron/api/domain/entity"
 	"vitametron/api/domain/port"
 )
 
+const (
+	// outlierWindow is how many recent logs form the OverallVAS baseline.
+	outlierWindow = 30
+	// outlierMinLogs is the smallest baseline checkOutlier will judge against.
+	outlierMinLogs = 5
+	// outlierSD is how many standard deviations from the mean count as an outlier.
+	outlierSD = 3.0
+)
+
 type RecordConditionUseCase struct {
 	repo port.ConditionRepository
 }
@@ -16,15 +27,50 @@ func NewRecordConditionUseCase(repo port.ConditionRepository) *RecordConditionUs
 	return &RecordConditionUseCase{repo: repo}
 }
 
-func (uc *RecordConditionUseCase) Create(ctx context.Context, log *entity.ConditionLog) error {
+func (uc *RecordConditionUseCase) Create(ctx context.Context, cl *entity.ConditionLog) error {
 	// Auto-compute legacy Overall from OverallVAS if not set
-	if log.Overall == 0 {
-		log.Overall = entity.VASToLegacyOverall(log.OverallVAS)
+	if cl.Overall == 0 {
+		cl.Overall = entity.VASToLegacyOverall(cl.OverallVAS)
 	}
-	if err := log.Validate(); err != nil {
+	if cl.Source == "" {
+		cl.Source = entity.ConditionSourceSpontaneous
+	}
+	if err := cl.Validate(); err != nil {
 		return err
 	}
-	return uc.repo.Create(ctx, log)
+
+	// The flag is advisory, so a failed baseline lookup does not block the log.
+	recent, err := uc.repo.List(ctx, entity.ConditionFilter{Limit: outlierWindow, ExcludeOutliers: true})
+	if err != nil {
+		log.Printf("warn: load recent condition logs for outlier check: %v", err)
+	} else {
+		cl.IsOutlier = checkOutlier(cl.OverallVAS, recent.Items)
+	}
+	return uc.repo.Create(ctx, cl)
+}
+
+// checkOutlier reports whether vas lies more than outlierSD population
+// standard deviations from the mean OverallVAS of recent. Baselines smaller
+// than outlierMinLogs or without any spread never flag.
+func checkOutlier(vas int, recent []entity.ConditionLog) bool {
+	if len(recent) < outlierMinLogs {
+		return false
+	}
+	var sum float64
+	for _, l := range recent {
+		sum += float64(l.OverallVAS)
+	}
+	mean := sum / float64(len(recent))
+	var sq float64
+	for _, l := range recent {
+		d := float64(l.OverallVAS) - mean
+		sq += d * d
+	}
+	sd := math.Sqrt(sq / float64(len(recent)))
+	if sd == 0 {
+		return false
+	}
+	return math.Abs(float64(vas)-mean) > outlierSD*sd
 }
 
 func (uc *RecordConditionUseCase) GetByID(ctx context.Context, id int64) (*entity.ConditionLog, error) {
@@ -67,14 +113,143 @@ func (uc *RecordConditionUseCase) Update(ctx context.Context, id int64, log *ent
 	return uc.repo.Update(ctx, log)
 }
 
+// GetHistory returns the edits of a live condition log, oldest first.
+func (uc *RecordConditionUseCase) GetHistory(ctx context.Context, id int64) ([]entity.ConditionLogHistory, error) {
+	existing, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, entity.ErrNotFound
+	}
+	history, err := uc.repo.GetHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		history = []entity.ConditionLogHistory{}
+	}
+	return history, nil
+}
+
 func (uc *RecordConditionUseCase) Delete(ctx context.Context, id int64) error {
 	return uc.repo.Delete(ctx, id)
 }
 
+// Restore undoes a soft delete and returns the restored log.
+func (uc *RecordConditionUseCase) Restore(ctx context.Context, id int64) (*entity.ConditionLog, error) {
+	ok, err := uc.repo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, entity.ErrNotFound
+	}
+	return uc.GetByID(ctx, id)
+}
+
+func (uc *RecordConditionUseCase) DeletePermanent(ctx context.Context, id int64) error {
+	ok, err := uc.repo.DeletePermanent(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return entity.ErrNotFound
+	}
+	return nil
+}
+
 func (uc *RecordConditionUseCase) GetTags(ctx context.Context) ([]entity.TagCount, error) {
 	return uc.repo.GetTags(ctx)
 }
 
-func (uc *RecordConditionUseCase) GetSummary(ctx context.Context, from, to time.Time) (*entity.ConditionSummary, error) {
-	return uc.repo.GetSummary(ctx, from, to)
+func (uc *RecordConditionUseCase) GetSummary(ctx context.Context, filter entity.ConditionFilter) (*entity.ConditionSummary, error) {
+	summary, err := uc.repo.GetSummary(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	series, err := uc.repo.GetVASSeries(ctx, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	summary.TrendDirection, summary.TrendSlope = vasTrend(series, filter.From, filter.To)
+	return summary, nil
+}
+
+// GetHeatmap returns one entry per JST calendar day of year, Jan 1 first,
+// holding the mean overall VAS of that day's logs.
+func (uc *RecordConditionUseCase) GetHeatmap(ctx context.Context, year int) ([]entity.HeatmapDay, error) {
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, jst)
+	to := from.AddDate(1, 0, 0)
+	series, err := uc.repo.GetVASSeries(ctx, from, to.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+
+	days := int(to.Sub(from).Hours() / 24)
+	sums := make([]float64, days)
+	counts := make([]int, days)
+	for _, p := range series {
+		local := p.LoggedAt.In(jst)
+		if local.Year() != year {
+			continue
+		}
+		i := local.YearDay() - 1
+		sums[i] += float64(p.OverallVAS)
+		counts[i]++
+	}
+
+	heatmap := make([]entity.HeatmapDay, days)
+	for i := range heatmap {
+		heatmap[i].Date = from.AddDate(0, 0, i).Format("2006-01-02")
+		if counts[i] > 0 {
+			v := float32(sums[i] / float64(counts[i]))
+			heatmap[i].Value = &v
+			heatmap[i].HasData = true
+		}
+	}
+	return heatmap, nil
+}
+
+// vasTrend splits [from, to] at its midpoint and compares the mean overall
+// VAS of each half. The slope is the change per day between the half
+// midpoints; the direction uses the same threshold as the weekly digest.
+func vasTrend(series []entity.VASPoint, from, to time.Time) (string, float64) {
+	mid := from.Add(to.Sub(from) / 2)
+	var firstSum, secondSum float64
+	var firstN, secondN int
+	for _, p := range series {
+		if p.LoggedAt.Before(mid) {
+			firstSum += float64(p.OverallVAS)
+			firstN++
+		} else {
+			secondSum += float64(p.OverallVAS)
+			secondN++
+		}
+	}
+	if firstN == 0 || secondN == 0 {
+		return entity.ConditionTrendInsufficient, 0
+	}
+
+	diff := secondSum/float64(secondN) - firstSum/float64(firstN)
+	var slope float64
+	if halfDays := to.Sub(from).Hours() / 24 / 2; halfDays > 0 {
+		slope = diff / halfDays
+	}
+	switch {
+	case diff >= conditionTrendThreshold:
+		return entity.ConditionTrendImproving, slope
+	case diff <= -conditionTrendThreshold:
+		return entity.ConditionTrendDeclining, slope
+	default:
+		return entity.ConditionTrendStable, slope
+	}
+}
+
+func (uc *RecordConditionUseCase) Archive(ctx context.Context, before time.Time) (int64, error) {
+	return uc.repo.Archive(ctx, before)
+}
+
+func (uc *RecordConditionUseCase) RenameTag(ctx context.Context, oldTag, newTag string) (int64, error) {
+	return uc.repo.RenameTag(ctx, oldTag, newTag)
 }
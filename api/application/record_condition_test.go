@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"errors"
+	"math"
 	"testing"
 	"time"
 
@@ -13,6 +14,9 @@ import (
 func TestRecordCondition_Create_Success(t *testing.T) {
 	var created bool
 	repo := &mocks.MockConditionRepository{
+		ListFunc: func(_ context.Context, _ entity.ConditionFilter) (*entity.ConditionListResult, error) {
+			return &entity.ConditionListResult{}, nil
+		},
 		CreateFunc: func(_ context.Context, _ *entity.ConditionLog) error {
 			created = true
 			return nil
@@ -30,6 +34,93 @@ func TestRecordCondition_Create_Success(t *testing.T) {
 	if log.Overall == 0 {
 		t.Error("Overall should be auto-computed from OverallVAS")
 	}
+	if log.Source != entity.ConditionSourceSpontaneous {
+		t.Errorf("Source = %q, want %q by default", log.Source, entity.ConditionSourceSpontaneous)
+	}
+}
+
+func TestRecordCondition_Create_FlagsOutlier(t *testing.T) {
+	var gotFilter entity.ConditionFilter
+	var recent []entity.ConditionLog
+	for _, v := range []int{68, 70, 72, 69, 71, 70, 73, 67} {
+		recent = append(recent, entity.ConditionLog{OverallVAS: v})
+	}
+	repo := &mocks.MockConditionRepository{
+		ListFunc: func(_ context.Context, filter entity.ConditionFilter) (*entity.ConditionListResult, error) {
+			gotFilter = filter
+			return &entity.ConditionListResult{Items: recent}, nil
+		},
+		CreateFunc: func(_ context.Context, _ *entity.ConditionLog) error { return nil },
+	}
+	uc := NewRecordConditionUseCase(repo)
+
+	log := &entity.ConditionLog{OverallVAS: 1, LoggedAt: time.Now()}
+	if err := uc.Create(context.Background(), log); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !log.IsOutlier {
+		t.Error("IsOutlier = false, want true")
+	}
+	if gotFilter.Limit != outlierWindow || !gotFilter.ExcludeOutliers {
+		t.Errorf("baseline filter = %+v, want last %d non-outlier logs", gotFilter, outlierWindow)
+	}
+}
+
+func TestRecordCondition_Create_OutlierLookupErrorIgnored(t *testing.T) {
+	var created bool
+	repo := &mocks.MockConditionRepository{
+		ListFunc: func(_ context.Context, _ entity.ConditionFilter) (*entity.ConditionListResult, error) {
+			return nil, errors.New("db error")
+		},
+		CreateFunc: func(_ context.Context, _ *entity.ConditionLog) error {
+			created = true
+			return nil
+		},
+	}
+	uc := NewRecordConditionUseCase(repo)
+
+	log := &entity.ConditionLog{OverallVAS: 50, LoggedAt: time.Now()}
+	if err := uc.Create(context.Background(), log); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !created || log.IsOutlier {
+		t.Errorf("created = %v, IsOutlier = %v, want created and not flagged", created, log.IsOutlier)
+	}
+}
+
+func TestCheckOutlier(t *testing.T) {
+	logs := func(vals ...int) []entity.ConditionLog {
+		out := make([]entity.ConditionLog, len(vals))
+		for i, v := range vals {
+			out[i].OverallVAS = v
+		}
+		return out
+	}
+	// mean 70, population SD 2
+	baseline := logs(68, 72, 68, 72, 68, 72, 68, 72)
+
+	tests := []struct {
+		name   string
+		vas    int
+		recent []entity.ConditionLog
+		want   bool
+	}{
+		{"far below", 1, baseline, true},
+		{"far above", 100, baseline, true},
+		{"within 3 SD", 75, baseline, false},
+		{"exactly 3 SD", 76, baseline, false},
+		{"just over 3 SD", 77, baseline, true},
+		{"too few logs", 1, logs(68, 72, 70, 71), false},
+		{"no spread", 1, logs(70, 70, 70, 70, 70), false},
+		{"no history", 1, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkOutlier(tt.vas, tt.recent); got != tt.want {
+				t.Errorf("checkOutlier(%d) = %v, want %v", tt.vas, got, tt.want)
+			}
+		})
+	}
 }
 
 func TestRecordCondition_Create_ValidationError(t *testing.T) {
@@ -53,6 +144,9 @@ func TestRecordCondition_Create_ValidationError(t *testing.T) {
 
 func TestRecordCondition_Create_RepoError(t *testing.T) {
 	repo := &mocks.MockConditionRepository{
+		ListFunc: func(_ context.Context, _ entity.ConditionFilter) (*entity.ConditionListResult, error) {
+			return &entity.ConditionListResult{}, nil
+		},
 		CreateFunc: func(_ context.Context, _ *entity.ConditionLog) error {
 			return errors.New("db error")
 		},
@@ -199,6 +293,33 @@ func TestRecordCondition_Update_NotFound(t *testing.T) {
 	}
 }
 
+func TestRecordCondition_GetHistory(t *testing.T) {
+	repo := &mocks.MockConditionRepository{
+		GetByIDFunc: func(_ context.Context, id int64) (*entity.ConditionLog, error) {
+			if id == 999 {
+				return nil, nil
+			}
+			return &entity.ConditionLog{ID: id}, nil
+		},
+		GetHistoryFunc: func(_ context.Context, _ int64) ([]entity.ConditionLogHistory, error) {
+			return nil, nil
+		},
+	}
+	uc := NewRecordConditionUseCase(repo)
+
+	history, err := uc.GetHistory(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if history == nil || len(history) != 0 {
+		t.Errorf("GetHistory() = %v, want empty non-nil slice", history)
+	}
+
+	if _, err := uc.GetHistory(context.Background(), 999); !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("GetHistory() error = %v, want ErrNotFound", err)
+	}
+}
+
 func TestRecordCondition_Delete(t *testing.T) {
 	repo := &mocks.MockConditionRepository{
 		DeleteFunc: func(_ context.Context, id int64) error {
@@ -215,6 +336,46 @@ func TestRecordCondition_Delete(t *testing.T) {
 	}
 }
 
+func TestRecordCondition_Restore(t *testing.T) {
+	repo := &mocks.MockConditionRepository{
+		RestoreFunc: func(_ context.Context, id int64) (bool, error) {
+			return id == 42, nil
+		},
+		GetByIDFunc: func(_ context.Context, id int64) (*entity.ConditionLog, error) {
+			return &entity.ConditionLog{ID: id}, nil
+		},
+	}
+	uc := NewRecordConditionUseCase(repo)
+
+	log, err := uc.Restore(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if log.ID != 42 {
+		t.Errorf("Restore() ID = %d, want 42", log.ID)
+	}
+
+	if _, err := uc.Restore(context.Background(), 7); !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("Restore() of a live log error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRecordCondition_DeletePermanent(t *testing.T) {
+	repo := &mocks.MockConditionRepository{
+		DeletePermanentFunc: func(_ context.Context, id int64) (bool, error) {
+			return id == 42, nil
+		},
+	}
+	uc := NewRecordConditionUseCase(repo)
+
+	if err := uc.DeletePermanent(context.Background(), 42); err != nil {
+		t.Fatalf("DeletePermanent() error = %v", err)
+	}
+	if err := uc.DeletePermanent(context.Background(), 7); !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("DeletePermanent() of a missing log error = %v, want ErrNotFound", err)
+	}
+}
+
 func TestRecordCondition_GetTags(t *testing.T) {
 	repo := &mocks.MockConditionRepository{
 		GetTagsFunc: func(_ context.Context) ([]entity.TagCount, error) {
@@ -246,20 +407,72 @@ func TestRecordCondition_GetSummary(t *testing.T) {
 		OverallVASMin: 20,
 		OverallVASMax: 95,
 	}
+	var gotMode string
 	repo := &mocks.MockConditionRepository{
-		GetSummaryFunc: func(_ context.Context, _, _ time.Time) (*entity.ConditionSummary, error) {
+		GetSummaryFunc: func(_ context.Context, filter entity.ConditionFilter) (*entity.ConditionSummary, error) {
+			gotMode = filter.WeightingMode
 			return expected, nil
 		},
+		GetVASSeriesFunc: func(_ context.Context, _, _ time.Time) ([]entity.VASPoint, error) {
+			return nil, nil
+		},
 	}
 	uc := NewRecordConditionUseCase(repo)
 
-	result, err := uc.GetSummary(context.Background(), now.Add(-7*24*time.Hour), now)
+	result, err := uc.GetSummary(context.Background(), entity.ConditionFilter{
+		From: now.Add(-7 * 24 * time.Hour), To: now, WeightingMode: entity.WeightingTimeWeighted,
+	})
 	if err != nil {
 		t.Fatalf("GetSummary() error = %v", err)
 	}
+	if gotMode != entity.WeightingTimeWeighted {
+		t.Errorf("repo WeightingMode = %q, want %q", gotMode, entity.WeightingTimeWeighted)
+	}
 	if result.TotalCount != 10 {
 		t.Errorf("GetSummary() TotalCount = %d, want 10", result.TotalCount)
 	}
+	if result.TrendDirection != entity.ConditionTrendInsufficient {
+		t.Errorf("GetSummary() TrendDirection = %q, want %q", result.TrendDirection, entity.ConditionTrendInsufficient)
+	}
+}
+
+func TestVASTrend(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 10)
+
+	// series builds one log per day with the given VAS values.
+	series := func(values ...int) []entity.VASPoint {
+		points := make([]entity.VASPoint, len(values))
+		for i, v := range values {
+			points[i] = entity.VASPoint{LoggedAt: from.AddDate(0, 0, i), OverallVAS: v}
+		}
+		return points
+	}
+
+	tests := []struct {
+		name      string
+		series    []entity.VASPoint
+		want      string
+		wantSlope float64
+	}{
+		// First half mean 30, second half mean 80: +50 over 5 days.
+		{"monotonically increasing", series(10, 20, 30, 40, 50, 60, 70, 80, 90, 100), entity.ConditionTrendImproving, 10},
+		{"monotonically decreasing", series(100, 90, 80, 70, 60, 50, 40, 30, 20, 10), entity.ConditionTrendDeclining, -10},
+		{"flat", series(60, 62, 58, 61, 59, 60, 61, 59, 60, 62), entity.ConditionTrendStable, 0.08},
+		{"second half empty", series(50, 60, 70), entity.ConditionTrendInsufficient, 0},
+		{"no logs", nil, entity.ConditionTrendInsufficient, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, slope := vasTrend(tt.series, from, to)
+			if got != tt.want {
+				t.Errorf("direction = %q, want %q", got, tt.want)
+			}
+			if math.Abs(slope-tt.wantSlope) > 1e-9 {
+				t.Errorf("slope = %v, want %v", slope, tt.wantSlope)
+			}
+		})
+	}
 }
 
 func TestRecordCondition_VASToLegacyConversion(t *testing.T) {
@@ -277,3 +490,87 @@ func TestRecordCondition_VASToLegacyConversion(t *testing.T) {
 		}
 	}
 }
+
+func TestRecordCondition_RenameTag(t *testing.T) {
+	var gotOld, gotNew string
+	repo := &mocks.MockConditionRepository{
+		RenameTagFunc: func(_ context.Context, oldTag, newTag string) (int64, error) {
+			gotOld, gotNew = oldTag, newTag
+			return 3, nil
+		},
+	}
+	uc := NewRecordConditionUseCase(repo)
+
+	n, err := uc.RenameTag(context.Background(), "headache", "migraine")
+	if err != nil {
+		t.Fatalf("RenameTag() error = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("RenameTag() = %d, want 3", n)
+	}
+	if gotOld != "headache" || gotNew != "migraine" {
+		t.Errorf("repo.RenameTag(%q, %q), want (headache, migraine)", gotOld, gotNew)
+	}
+}
+
+func TestRecordCondition_GetHeatmap(t *testing.T) {
+	// 2024-03-01 23:30 UTC is 2024-03-02 in JST.
+	series := []entity.VASPoint{
+		{LoggedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, jst), OverallVAS: 40},
+		{LoggedAt: time.Date(2024, 1, 1, 21, 0, 0, 0, jst), OverallVAS: 61},
+		{LoggedAt: time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC), OverallVAS: 70},
+		{LoggedAt: time.Date(2024, 12, 31, 23, 0, 0, 0, jst), OverallVAS: 55},
+	}
+	var gotFrom, gotTo time.Time
+	repo := &mocks.MockConditionRepository{
+		GetVASSeriesFunc: func(_ context.Context, from, to time.Time) ([]entity.VASPoint, error) {
+			gotFrom, gotTo = from, to
+			return series, nil
+		},
+	}
+
+	heatmap, err := NewRecordConditionUseCase(repo).GetHeatmap(context.Background(), 2024)
+	if err != nil {
+		t.Fatalf("GetHeatmap() error = %v", err)
+	}
+	if len(heatmap) != 366 {
+		t.Fatalf("len = %d, want 366 for a leap year", len(heatmap))
+	}
+	if !gotFrom.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, jst)) || !gotTo.Before(time.Date(2025, 1, 1, 0, 0, 0, 0, jst)) {
+		t.Errorf("range = %v..%v", gotFrom, gotTo)
+	}
+
+	tests := []struct {
+		index int
+		date  string
+		want  *float32
+	}{
+		{0, "2024-01-01", f32(50.5)},
+		{1, "2024-01-02", nil},
+		{61, "2024-03-02", f32(70)},
+		{365, "2024-12-31", f32(55)},
+	}
+	for _, tt := range tests {
+		d := heatmap[tt.index]
+		if d.Date != tt.date {
+			t.Errorf("[%d].Date = %s, want %s", tt.index, d.Date, tt.date)
+		}
+		if d.HasData != (tt.want != nil) {
+			t.Errorf("[%d].HasData = %v, want %v", tt.index, d.HasData, tt.want != nil)
+		}
+		if tt.want != nil && (d.Value == nil || *d.Value != *tt.want) {
+			t.Errorf("[%d].Value = %v, want %v", tt.index, d.Value, *tt.want)
+		}
+	}
+
+	heatmap, err = NewRecordConditionUseCase(repo).GetHeatmap(context.Background(), 2025)
+	if err != nil {
+		t.Fatalf("GetHeatmap() error = %v", err)
+	}
+	if len(heatmap) != 365 {
+		t.Errorf("len = %d, want 365", len(heatmap))
+	}
+	if heatmap[0].HasData {
+		t.Error("2025-01-01 has data from another year")
+	}
+}
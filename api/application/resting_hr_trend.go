@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+const (
+	restingHRShortWindowDays = 3
+	restingHRLongWindowDays  = 30
+	// restingHRAlertDelta is how many BPM the short-term mean must exceed
+	// the long-term mean by to raise an alert.
+	restingHRAlertDelta = 5
+	// restingHRMinBaseline is the fewest resting HR readings in the long
+	// window needed for a meaningful mean.
+	restingHRMinBaseline = 14
+)
+
+// DetectRestingHRTrend compares the mean resting HR of the 3 days ending on
+// the latest summary with the mean of the 30 days ending on it. It returns an
+// alert when the short-term mean is more than 5 BPM higher, and nil when it
+// is not or when a reading in the short window or too much history is missing.
+func DetectRestingHRTrend(_ context.Context, summaries []entity.DailySummary) *entity.HRAlert {
+	readings := make([]entity.DailySummary, 0, len(summaries))
+	for _, s := range summaries {
+		if s.RestingHR > 0 {
+			readings = append(readings, s)
+		}
+	}
+	if len(readings) == 0 {
+		return nil
+	}
+	sort.Slice(readings, func(i, j int) bool { return readings[i].Date.Before(readings[j].Date) })
+
+	latest := readings[len(readings)-1].Date
+	shortStart := latest.AddDate(0, 0, -(restingHRShortWindowDays - 1))
+	longStart := latest.AddDate(0, 0, -(restingHRLongWindowDays - 1))
+
+	var shortSum, longSum float64
+	var shortN, longN int
+	for _, s := range readings {
+		if s.Date.Before(longStart) {
+			continue
+		}
+		longSum += float64(s.RestingHR)
+		longN++
+		if !s.Date.Before(shortStart) {
+			shortSum += float64(s.RestingHR)
+			shortN++
+		}
+	}
+	if shortN < restingHRShortWindowDays || longN < restingHRMinBaseline {
+		return nil
+	}
+
+	shortMean := shortSum / float64(shortN)
+	longMean := longSum / float64(longN)
+	delta := shortMean - longMean
+	if delta <= restingHRAlertDelta {
+		return nil
+	}
+	return &entity.HRAlert{
+		Date:          latest.Format("2006-01-02"),
+		ShortTermMean: float32(shortMean),
+		LongTermMean:  float32(longMean),
+		Delta:         float32(delta),
+	}
+}
+
+// restingHRTrendWindow returns the date range DetectRestingHRTrend needs for
+// an alert on date.
+func restingHRTrendWindow(date time.Time) (time.Time, time.Time) {
+	return date.AddDate(0, 0, -(restingHRLongWindowDays - 1)), date
+}
@@ -0,0 +1,248 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+// restingHRSeries returns one summary per day ending on end, oldest first.
+func restingHRSeries(end time.Time, hrs []int) []entity.DailySummary {
+	out := make([]entity.DailySummary, len(hrs))
+	for i, hr := range hrs {
+		out[i] = entity.DailySummary{Date: end.AddDate(0, 0, i-len(hrs)+1), RestingHR: hr}
+	}
+	return out
+}
+
+func repeatHR(hr, n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = hr
+	}
+	return out
+}
+
+func TestDetectRestingHRTrend(t *testing.T) {
+	end := time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC)
+
+	t.Run("rising", func(t *testing.T) {
+		hrs := append(repeatHR(58, 27), 66, 67, 68)
+		alert := DetectRestingHRTrend(context.Background(), restingHRSeries(end, hrs))
+		if alert == nil {
+			t.Fatal("DetectRestingHRTrend() = nil, want alert")
+		}
+		if alert.Date != "2026-04-18" {
+			t.Errorf("Date = %q, want 2026-04-18", alert.Date)
+		}
+		if alert.ShortTermMean != 67 {
+			t.Errorf("ShortTermMean = %v, want 67", alert.ShortTermMean)
+		}
+		// (27×58 + 66 + 67 + 68) / 30
+		if math.Abs(float64(alert.LongTermMean)-58.9) > 0.001 {
+			t.Errorf("LongTermMean = %v, want 58.9", alert.LongTermMean)
+		}
+		if math.Abs(float64(alert.Delta)-8.1) > 0.001 {
+			t.Errorf("Delta = %v, want 8.1", alert.Delta)
+		}
+	})
+
+	tests := []struct {
+		name string
+		hrs  []int
+	}{
+		{"stable", append(repeatHR(58, 27), 59, 57, 60)},
+		{"rise within threshold", append(repeatHR(58, 27), 63, 63, 63)},
+		{"missing short-term day", append(repeatHR(58, 27), 70, 0, 70)},
+		{"too little history", append(repeatHR(58, 10), 70, 70, 70)},
+		{"empty", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if alert := DetectRestingHRTrend(context.Background(), restingHRSeries(end, tt.hrs)); alert != nil {
+				t.Errorf("DetectRestingHRTrend() = %+v, want nil", alert)
+			}
+		})
+	}
+
+	t.Run("ignores days before the long window", func(t *testing.T) {
+		// 10 high days from before the window would pull the mean above
+		// the short-term mean if they were counted.
+		hrs := append(append(repeatHR(90, 10), repeatHR(58, 27)...), 66, 67, 68)
+		if alert := DetectRestingHRTrend(context.Background(), restingHRSeries(end, hrs)); alert == nil {
+			t.Error("DetectRestingHRTrend() = nil, want alert")
+		}
+	})
+}
+
+func TestSyncBiometrics_RestingHRAlert(t *testing.T) {
+	date := time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC)
+	unavailable := errors.New("unavailable")
+
+	tests := []struct {
+		name      string
+		history   []int
+		wantAlert bool
+	}{
+		{"rising", append(repeatHR(58, 27), 66, 67, 68), true},
+		{"stable", repeatHR(58, 30), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &mocks.MockBiometricsProvider{
+				FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+					return &entity.DailySummary{Date: date, RestingHR: tt.history[len(tt.history)-1]}, nil
+				},
+				FetchHRVFunc: func(_ context.Context, _ time.Time) (float32, float32, error) {
+					return 0, 0, unavailable
+				},
+				FetchSpO2Func: func(_ context.Context, _ time.Time) (float32, float32, float32, error) {
+					return 0, 0, 0, unavailable
+				},
+				FetchBreathingRateFunc: func(_ context.Context, _ time.Time) (float32, float32, float32, float32, error) {
+					return 0, 0, 0, 0, unavailable
+				},
+				FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) {
+					return 0, unavailable
+				},
+				FetchWaterLogFunc: func(_ context.Context, _ time.Time) (int, error) {
+					return 0, unavailable
+				},
+				FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
+					return nil, unavailable
+				},
+				FetchSleepStagesFunc: func(_ context.Context, _ time.Time) ([]entity.SleepStage, *entity.SleepRecord, error) {
+					return nil, nil, unavailable
+				},
+				FetchExerciseLogsFunc: func(_ context.Context, _ time.Time) ([]entity.ExerciseLog, error) {
+					return nil, unavailable
+				},
+			}
+			var gotFrom, gotTo time.Time
+			summaryRepo := &mocks.MockDailySummaryRepository{
+				UpsertFunc: func(_ context.Context, _ *entity.DailySummary) error { return nil },
+				ListRangeFunc: func(_ context.Context, from, to time.Time) ([]entity.DailySummary, error) {
+					gotFrom, gotTo = from, to
+					return restingHRSeries(date, tt.history), nil
+				},
+			}
+			var sent *entity.HRAlert
+			sender := &mocks.MockWebhookSender{
+				SendFunc: func(_ context.Context, _ string, payload any) error {
+					sent = payload.(*entity.HRAlert)
+					return nil
+				},
+			}
+
+			uc := NewSyncBiometricsUseCase(provider, summaryRepo, &mocks.MockHeartRateRepository{},
+				&mocks.MockSleepStageRepository{}, &mocks.MockExerciseRepository{}, nil).
+				WithRestingHRAlert(sender)
+			if err := uc.SyncDate(context.Background(), date); err != nil {
+				t.Fatalf("SyncDate() error = %v", err)
+			}
+			if want := date.AddDate(0, 0, -29); !gotFrom.Equal(want) || !gotTo.Equal(date) {
+				t.Errorf("ListRange(%v, %v), want (%v, %v)", gotFrom, gotTo, want, date)
+			}
+			if (sent != nil) != tt.wantAlert {
+				t.Fatalf("alert sent = %v, want %v", sent != nil, tt.wantAlert)
+			}
+			if sent != nil && sent.Date != "2026-04-18" {
+				t.Errorf("alert Date = %q, want 2026-04-18", sent.Date)
+			}
+		})
+	}
+}
+
+// memAlertSentStore is an AlertSentStore backed by a map.
+func memAlertSentStore() *mocks.MockAlertSentStore {
+	marks := map[string]bool{}
+	return &mocks.MockAlertSentStore{
+		MarkSentFunc: func(_ context.Context, kind, date string) (bool, error) {
+			if marks[kind+":"+date] {
+				return false, nil
+			}
+			marks[kind+":"+date] = true
+			return true, nil
+		},
+		ClearSentFunc: func(_ context.Context, kind, date string) error {
+			delete(marks, kind+":"+date)
+			return nil
+		},
+	}
+}
+
+// summaryOnlyProvider returns summary and fails every other fetch.
+func summaryOnlyProvider(summary entity.DailySummary) *mocks.MockBiometricsProvider {
+	unavailable := errors.New("unavailable")
+	return &mocks.MockBiometricsProvider{
+		FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+			s := summary
+			return &s, nil
+		},
+		FetchHRVFunc: func(_ context.Context, _ time.Time) (float32, float32, error) {
+			return 0, 0, unavailable
+		},
+		FetchSpO2Func: func(_ context.Context, _ time.Time) (float32, float32, float32, error) {
+			return 0, 0, 0, unavailable
+		},
+		FetchBreathingRateFunc: func(_ context.Context, _ time.Time) (float32, float32, float32, float32, error) {
+			return 0, 0, 0, 0, unavailable
+		},
+		FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) {
+			return 0, unavailable
+		},
+		FetchWaterLogFunc: func(_ context.Context, _ time.Time) (int, error) {
+			return 0, unavailable
+		},
+		FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
+			return nil, unavailable
+		},
+		FetchSleepStagesFunc: func(_ context.Context, _ time.Time) ([]entity.SleepStage, *entity.SleepRecord, error) {
+			return nil, nil, unavailable
+		},
+		FetchExerciseLogsFunc: func(_ context.Context, _ time.Time) ([]entity.ExerciseLog, error) {
+			return nil, unavailable
+		},
+	}
+}
+
+func TestSyncBiometrics_RestingHRAlert_SentOncePerDate(t *testing.T) {
+	date := time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC)
+	history := append(repeatHR(58, 27), 66, 67, 68)
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		UpsertFunc: func(_ context.Context, _ *entity.DailySummary) error { return nil },
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+			return restingHRSeries(date, history), nil
+		},
+	}
+	attempts := 0
+	sender := &mocks.MockWebhookSender{
+		SendFunc: func(_ context.Context, _ string, _ any) error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("webhook down")
+			}
+			return nil
+		},
+	}
+
+	uc := NewSyncBiometricsUseCase(summaryOnlyProvider(entity.DailySummary{Date: date, RestingHR: 68}), summaryRepo,
+		&mocks.MockHeartRateRepository{}, &mocks.MockSleepStageRepository{}, &mocks.MockExerciseRepository{}, nil).
+		WithRestingHRAlert(sender).
+		WithAlertSentStore(memAlertSentStore())
+	// The first send fails and is retried on the next sync; later syncs of
+	// the same date stay quiet.
+	for i := 0; i < 3; i++ {
+		if err := uc.SyncDate(context.Background(), date); err != nil {
+			t.Fatalf("SyncDate() error = %v", err)
+		}
+	}
+	if attempts != 2 {
+		t.Errorf("send attempts = %d, want 2", attempts)
+	}
+}
@@ -0,0 +1,150 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// ErrQueryDayMissingMetric means the query day lacks a requested metric, so
+// it has no position in the metric space.
+var ErrQueryDayMissingMetric = errors.New("query day is missing a requested metric")
+
+// SimilarDaysUseCase finds the days whose biometric profile is closest to a
+// given day.
+type SimilarDaysUseCase struct {
+	summaryRepo port.DailySummaryRepository
+}
+
+func NewSimilarDaysUseCase(summaryRepo port.DailySummaryRepository) *SimilarDaysUseCase {
+	return &SimilarDaysUseCase{summaryRepo: summaryRepo}
+}
+
+// LookupSummaryMetric returns the SummaryMetric with the given column name.
+func LookupSummaryMetric(name string) (entity.SummaryMetric, bool) {
+	for _, m := range entity.SummaryMetrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return entity.SummaryMetric{}, false
+}
+
+// Execute compares date with every other day of the year before it. Each
+// metric is divided by its standard deviation over that year first, so the
+// distance is in SD units and no metric dominates by its scale. Days missing
+// any metric are skipped.
+func (uc *SimilarDaysUseCase) Execute(ctx context.Context, date time.Time, metrics []entity.SummaryMetric, top int) (*entity.SimilarDays, error) {
+	summaries, err := uc.summaryRepo.ListRange(ctx, date.AddDate(-1, 0, 0), date)
+	if err != nil {
+		return nil, err
+	}
+
+	dateKey := date.Format("2006-01-02")
+	var query []float64
+	type candidate struct {
+		summary *entity.DailySummary
+		vector  []float64
+	}
+	candidates := make([]candidate, 0, len(summaries))
+	for i := range summaries {
+		s := &summaries[i]
+		vec, ok := metricVector(s, metrics)
+		if s.Date.Format("2006-01-02") == dateKey {
+			if !ok {
+				return nil, ErrQueryDayMissingMetric
+			}
+			query = vec
+			continue
+		}
+		if ok {
+			candidates = append(candidates, candidate{summary: s, vector: vec})
+		}
+	}
+	if query == nil {
+		return nil, entity.ErrNotFound
+	}
+
+	vectors := make([][]float64, 0, len(candidates)+1)
+	vectors = append(vectors, query)
+	for _, c := range candidates {
+		vectors = append(vectors, c.vector)
+	}
+	scale := metricScales(vectors, len(metrics))
+
+	days := make([]entity.SimilarDay, 0, len(candidates))
+	for _, c := range candidates {
+		days = append(days, entity.SimilarDay{
+			Date:     c.summary.Date,
+			Distance: computeEuclideanDistance(scaled(query, scale), scaled(c.vector, scale)),
+			Summary:  c.summary,
+		})
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].Distance < days[j].Distance })
+	if len(days) > top {
+		days = days[:top]
+	}
+
+	names := make([]string, len(metrics))
+	for i, m := range metrics {
+		names[i] = m.Name
+	}
+	return &entity.SimilarDays{Date: date, Metrics: names, Days: days}, nil
+}
+
+// computeEuclideanDistance returns the Euclidean distance between two
+// vectors of equal length.
+func computeEuclideanDistance(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+func metricVector(s *entity.DailySummary, metrics []entity.SummaryMetric) ([]float64, bool) {
+	vec := make([]float64, len(metrics))
+	for i, m := range metrics {
+		v, ok := m.Value(s)
+		if !ok {
+			return nil, false
+		}
+		vec[i] = v
+	}
+	return vec, true
+}
+
+// metricScales returns each dimension's population standard deviation, or 1
+// where the values do not vary.
+func metricScales(vectors [][]float64, dims int) []float64 {
+	scale := make([]float64, dims)
+	n := float64(len(vectors))
+	for d := 0; d < dims; d++ {
+		var sum, sumSq float64
+		for _, v := range vectors {
+			sum += v[d]
+			sumSq += v[d] * v[d]
+		}
+		mean := sum / n
+		sd := math.Sqrt(math.Max(sumSq/n-mean*mean, 0))
+		if sd == 0 {
+			sd = 1
+		}
+		scale[d] = sd
+	}
+	return scale
+}
+
+func scaled(v, scale []float64) []float64 {
+	out := make([]float64, len(v))
+	for i := range v {
+		out[i] = v[i] / scale[i]
+	}
+	return out
+}
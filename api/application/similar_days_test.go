@@ -0,0 +1,107 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestComputeEuclideanDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		{"identical", []float64{1, 2, 3}, []float64{1, 2, 3}, 0},
+		{"3-4-5", []float64{0, 0}, []float64{3, 4}, 5},
+		{"negative", []float64{-1, -1}, []float64{2, 3}, 5},
+		{"one dimension", []float64{10}, []float64{7}, 3},
+		{"empty", nil, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeEuclideanDistance(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("computeEuclideanDistance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarDaysUseCase_Execute(t *testing.T) {
+	date := time.Date(2025, 6, 15, 0, 0, 0, 0, jst)
+	day := func(offset, rhr, steps int) entity.DailySummary {
+		return entity.DailySummary{Date: date.AddDate(0, 0, offset), RestingHR: rhr, Steps: steps}
+	}
+	summaries := []entity.DailySummary{
+		day(-4, 70, 9000),
+		day(-3, 60, 4000),
+		day(-2, 61, 9100),
+		day(-1, 0, 9000), // missing resting HR is skipped
+		day(0, 60, 9000),
+	}
+	var gotFrom, gotTo time.Time
+	uc := NewSimilarDaysUseCase(&mocks.MockDailySummaryRepository{
+		ListRangeFunc: func(_ context.Context, from, to time.Time) ([]entity.DailySummary, error) {
+			gotFrom, gotTo = from, to
+			return summaries, nil
+		},
+	})
+	rhr, _ := LookupSummaryMetric("resting_hr")
+	steps, _ := LookupSummaryMetric("steps")
+
+	got, err := uc.Execute(context.Background(), date, []entity.SummaryMetric{rhr, steps}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gotFrom.Equal(date.AddDate(-1, 0, 0)) || !gotTo.Equal(date) {
+		t.Errorf("ListRange(%v, %v), want the year up to %v", gotFrom, gotTo, date)
+	}
+	if len(got.Days) != 2 {
+		t.Fatalf("len(Days) = %d, want 2", len(got.Days))
+	}
+	// Day -2 is 1 bpm and 100 steps away; the others 10 bpm or 5000 steps.
+	if want := date.AddDate(0, 0, -2); !got.Days[0].Date.Equal(want) {
+		t.Errorf("nearest = %v, want %v", got.Days[0].Date, want)
+	}
+	if got.Days[0].Distance > got.Days[1].Distance {
+		t.Errorf("days not sorted by distance: %v", got.Days)
+	}
+	if got.Days[0].Summary == nil || got.Days[0].Summary.RestingHR != 61 {
+		t.Errorf("nearest summary = %+v", got.Days[0].Summary)
+	}
+	if len(got.Metrics) != 2 || got.Metrics[0] != "resting_hr" || got.Metrics[1] != "steps" {
+		t.Errorf("Metrics = %v", got.Metrics)
+	}
+}
+
+func TestSimilarDaysUseCase_Execute_QueryDay(t *testing.T) {
+	date := time.Date(2025, 6, 15, 0, 0, 0, 0, jst)
+	rhr, _ := LookupSummaryMetric("resting_hr")
+
+	tests := []struct {
+		name      string
+		summaries []entity.DailySummary
+		wantErr   error
+	}{
+		{"no summary", []entity.DailySummary{{Date: date.AddDate(0, 0, -1), RestingHR: 60}}, entity.ErrNotFound},
+		{"missing metric", []entity.DailySummary{{Date: date, Steps: 9000}}, ErrQueryDayMissingMetric},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewSimilarDaysUseCase(&mocks.MockDailySummaryRepository{
+				ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+					return tt.summaries, nil
+				},
+			})
+			_, err := uc.Execute(context.Background(), date, []entity.SummaryMetric{rhr}, 5)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
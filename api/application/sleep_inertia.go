@@ -0,0 +1,54 @@
+package application
+
+import (
+	"sort"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+// n2MinDuration is how long a light-sleep bout must run before waking to be
+// treated as N2. Wearables report N1 and N2 together as "light"; short bouts
+// are mostly N1 transitions, sustained ones mostly N2.
+const n2MinDuration = 20 * time.Minute
+
+// EstimateSleepInertia classifies grogginess by the sleep stage in progress at
+// wakeTime. Trailing wake segments (lying awake before getting up) are skipped
+// so the stage reflects what the sleeper actually woke from.
+func EstimateSleepInertia(stages []entity.SleepStage, wakeTime time.Time) entity.SleepInertia {
+	sorted := make([]entity.SleepStage, 0, len(stages))
+	for _, s := range stages {
+		if !s.Time.After(wakeTime) {
+			sorted = append(sorted, s)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	last := -1
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if sorted[i].Stage != "wake" {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		return entity.SleepInertia{Level: entity.SleepInertiaUnknown}
+	}
+
+	stage := sorted[last].Stage
+	switch stage {
+	case "deep":
+		return entity.SleepInertia{Level: entity.SleepInertiaSevere, WakeStage: stage, EstimatedDurationMin: 30}
+	case "light":
+		// Merge consecutive light segments into one bout.
+		start := last
+		for start > 0 && sorted[start-1].Stage == "light" {
+			start--
+		}
+		boutEnd := sorted[last].Time.Add(time.Duration(sorted[last].Seconds) * time.Second)
+		if boutEnd.Sub(sorted[start].Time) >= n2MinDuration {
+			return entity.SleepInertia{Level: entity.SleepInertiaModerate, WakeStage: stage, EstimatedDurationMin: 15}
+		}
+	}
+	return entity.SleepInertia{Level: entity.SleepInertiaMinimal, WakeStage: stage, EstimatedDurationMin: 5}
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+func TestEstimateSleepInertia(t *testing.T) {
+	base := time.Date(2025, 6, 15, 6, 0, 0, 0, time.UTC)
+	stage := func(offsetMin int, name string, durMin int) entity.SleepStage {
+		return entity.SleepStage{Time: base.Add(time.Duration(offsetMin) * time.Minute), Stage: name, Seconds: durMin * 60}
+	}
+	wake := base.Add(60 * time.Minute)
+
+	tests := []struct {
+		name      string
+		stages    []entity.SleepStage
+		wantLevel string
+		wantStage string
+		wantMin   int
+	}{
+		{
+			name:      "woke from deep",
+			stages:    []entity.SleepStage{stage(0, "light", 30), stage(30, "deep", 30)},
+			wantLevel: entity.SleepInertiaSevere, wantStage: "deep", wantMin: 30,
+		},
+		{
+			name:      "woke from REM",
+			stages:    []entity.SleepStage{stage(0, "deep", 30), stage(30, "rem", 30)},
+			wantLevel: entity.SleepInertiaMinimal, wantStage: "rem", wantMin: 5,
+		},
+		{
+			name:      "woke from sustained light (N2)",
+			stages:    []entity.SleepStage{stage(0, "rem", 30), stage(30, "light", 15), stage(45, "light", 15)},
+			wantLevel: entity.SleepInertiaModerate, wantStage: "light", wantMin: 15,
+		},
+		{
+			name:      "woke from brief light",
+			stages:    []entity.SleepStage{stage(0, "rem", 55), stage(55, "light", 5)},
+			wantLevel: entity.SleepInertiaMinimal, wantStage: "light", wantMin: 5,
+		},
+		{
+			name:      "trailing wake skipped",
+			stages:    []entity.SleepStage{stage(0, "light", 20), stage(20, "deep", 30), stage(50, "wake", 10)},
+			wantLevel: entity.SleepInertiaSevere, wantStage: "deep", wantMin: 30,
+		},
+		{
+			name:      "stages after wake ignored",
+			stages:    []entity.SleepStage{stage(30, "rem", 30), stage(70, "deep", 30)},
+			wantLevel: entity.SleepInertiaMinimal, wantStage: "rem", wantMin: 5,
+		},
+		{
+			name:      "no stages",
+			stages:    nil,
+			wantLevel: entity.SleepInertiaUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateSleepInertia(tt.stages, wake)
+			if got.Level != tt.wantLevel {
+				t.Errorf("Level = %q, want %q", got.Level, tt.wantLevel)
+			}
+			if got.WakeStage != tt.wantStage {
+				t.Errorf("WakeStage = %q, want %q", got.WakeStage, tt.wantStage)
+			}
+			if got.EstimatedDurationMin != tt.wantMin {
+				t.Errorf("EstimatedDurationMin = %d, want %d", got.EstimatedDurationMin, tt.wantMin)
+			}
+		})
+	}
+}
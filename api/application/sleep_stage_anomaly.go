@@ -0,0 +1,124 @@
+package application
+
+import (
+	"math"
+	"time"
+
+	"vitametron/api/domain/entity"
+)
+
+const (
+	sleepStageWindowDays = 30
+	// sleepStageMinBaseline is the fewest staged nights a baseline needs.
+	sleepStageMinBaseline = 14
+	// sleepStageAlertSD is how many standard deviations a stage fraction must
+	// move from its baseline to raise an alert.
+	sleepStageAlertSD = 3.0
+)
+
+var sleepStageNames = []string{"deep", "light", "rem", "wake"}
+
+// DetectSleepStageAnomaly compares the share of each stage in tonight's
+// stages with its mean share over the staged nights in historical. It
+// returns an alert for the stage furthest beyond 3 population SD, or nil
+// when none is, when fewer than 14 staged nights are available, or when
+// stages is empty. Classic-scored nights carry no stage breakdown and are
+// ignored. The alert's Date is left to the caller.
+func DetectSleepStageAnomaly(stages []entity.SleepStage, historical []entity.DailySummary) *entity.SleepStageAlert {
+	tonight := stageFractionsFromStages(stages)
+	if tonight == nil {
+		return nil
+	}
+
+	var nights []map[string]float64
+	for i := range historical {
+		if f := stageFractionsFromSummary(&historical[i]); f != nil {
+			nights = append(nights, f)
+		}
+	}
+	if len(nights) < sleepStageMinBaseline {
+		return nil
+	}
+
+	var alert *entity.SleepStageAlert
+	for _, stage := range sleepStageNames {
+		var sum float64
+		for _, n := range nights {
+			sum += n[stage]
+		}
+		mean := sum / float64(len(nights))
+		var sq float64
+		for _, n := range nights {
+			d := n[stage] - mean
+			sq += d * d
+		}
+		sd := math.Sqrt(sq / float64(len(nights)))
+		// A constant share leaves only rounding error in sd.
+		if sd < 1e-9 {
+			continue
+		}
+		delta := tonight[stage] - mean
+		z := delta / sd
+		if math.Abs(z) <= sleepStageAlertSD {
+			continue
+		}
+		if alert != nil && math.Abs(z) <= math.Abs(float64(alert.ZScore)) {
+			continue
+		}
+		alert = &entity.SleepStageAlert{
+			Stage:            stage,
+			Fraction:         float32(tonight[stage]),
+			BaselineFraction: float32(mean),
+			BaselineSD:       float32(sd),
+			Delta:            float32(delta),
+			ZScore:           float32(z),
+			BaselineNights:   len(nights),
+		}
+	}
+	return alert
+}
+
+// stageFractionsFromStages returns each stage's share of the staged seconds,
+// or nil when there are none.
+func stageFractionsFromStages(stages []entity.SleepStage) map[string]float64 {
+	seconds := make(map[string]float64, len(sleepStageNames))
+	var total float64
+	for _, s := range stages {
+		switch s.Stage {
+		case "deep", "light", "rem", "wake":
+			seconds[s.Stage] += float64(s.Seconds)
+			total += float64(s.Seconds)
+		}
+	}
+	if total <= 0 {
+		return nil
+	}
+	for stage := range seconds {
+		seconds[stage] /= total
+	}
+	return seconds
+}
+
+// stageFractionsFromSummary returns each stage's share of a staged night's
+// minutes, or nil for classic or missing sleep.
+func stageFractionsFromSummary(s *entity.DailySummary) map[string]float64 {
+	if s.SleepType != "stages" {
+		return nil
+	}
+	total := float64(s.SleepDeepMin + s.SleepLightMin + s.SleepREMMin + s.SleepWakeMin)
+	if total <= 0 {
+		return nil
+	}
+	return map[string]float64{
+		"deep":  float64(s.SleepDeepMin) / total,
+		"light": float64(s.SleepLightMin) / total,
+		"rem":   float64(s.SleepREMMin) / total,
+		"wake":  float64(s.SleepWakeMin) / total,
+	}
+}
+
+// sleepStageBaselineWindow returns the nights DetectSleepStageAnomaly
+// compares date against: the 30 before it.
+func sleepStageBaselineWindow(date time.Time) (time.Time, time.Time) {
+	return date.AddDate(0, 0, -sleepStageWindowDays), date.AddDate(0, 0, -1)
+}
@@ -0,0 +1,216 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+// stagedNights returns n staged nights ending the day before end. Deep sleep
+// cycles through 80, 90 and 100 minutes with wake making up the difference,
+// so every night totals 450 minutes.
+func stagedNights(end time.Time, n int) []entity.DailySummary {
+	out := make([]entity.DailySummary, n)
+	for i := range out {
+		deep := 80 + 10*(i%3)
+		out[i] = entity.DailySummary{
+			Date:          end.AddDate(0, 0, i-n),
+			SleepType:     "stages",
+			SleepDeepMin:  deep,
+			SleepLightMin: 240,
+			SleepREMMin:   90,
+			SleepWakeMin:  120 - deep,
+		}
+	}
+	return out
+}
+
+// nightStages returns one entry per stage with the given minutes.
+func nightStages(deep, light, rem, wake int) []entity.SleepStage {
+	start := time.Date(2026, 4, 17, 23, 0, 0, 0, jst)
+	var out []entity.SleepStage
+	for _, s := range []struct {
+		stage string
+		min   int
+	}{{"light", light}, {"deep", deep}, {"rem", rem}, {"wake", wake}} {
+		if s.min == 0 {
+			continue
+		}
+		out = append(out, entity.SleepStage{Time: start, Stage: s.stage, Seconds: s.min * 60})
+		start = start.Add(time.Duration(s.min) * time.Minute)
+	}
+	return out
+}
+
+func TestDetectSleepStageAnomaly(t *testing.T) {
+	end := time.Date(2026, 4, 18, 0, 0, 0, 0, jst)
+
+	t.Run("deep sleep lost", func(t *testing.T) {
+		alert := DetectSleepStageAnomaly(nightStages(0, 240, 90, 120), stagedNights(end, 30))
+		if alert == nil {
+			t.Fatal("DetectSleepStageAnomaly() = nil, want alert")
+		}
+		if alert.Stage != "deep" {
+			t.Errorf("Stage = %q, want deep", alert.Stage)
+		}
+		if alert.Fraction != 0 {
+			t.Errorf("Fraction = %v, want 0", alert.Fraction)
+		}
+		// Mean deep share is 90/450.
+		if math.Abs(float64(alert.BaselineFraction)-0.2) > 1e-6 || math.Abs(float64(alert.Delta)+0.2) > 1e-6 {
+			t.Errorf("BaselineFraction = %v, Delta = %v, want 0.2, -0.2", alert.BaselineFraction, alert.Delta)
+		}
+		if alert.ZScore >= -sleepStageAlertSD {
+			t.Errorf("ZScore = %v, want below -%v", alert.ZScore, sleepStageAlertSD)
+		}
+		if alert.BaselineNights != 30 {
+			t.Errorf("BaselineNights = %d, want 30", alert.BaselineNights)
+		}
+	})
+
+	classic := stagedNights(end, 30)
+	for i := range classic {
+		classic[i].SleepType = "classic"
+	}
+	tests := []struct {
+		name    string
+		stages  []entity.SleepStage
+		history []entity.DailySummary
+	}{
+		{"normal night", nightStages(100, 240, 90, 30), stagedNights(end, 30)},
+		{"too little history", nightStages(0, 240, 90, 120), stagedNights(end, 10)},
+		{"classic history ignored", nightStages(0, 240, 90, 120), classic},
+		{"no stages", nil, stagedNights(end, 30)},
+		{"classic stages", []entity.SleepStage{{Stage: "asleep", Seconds: 27000}}, stagedNights(end, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if alert := DetectSleepStageAnomaly(tt.stages, tt.history); alert != nil {
+				t.Errorf("DetectSleepStageAnomaly() = %+v, want nil", alert)
+			}
+		})
+	}
+}
+
+func TestSyncBiometrics_SleepStageAlert(t *testing.T) {
+	date := time.Date(2026, 4, 18, 0, 0, 0, 0, jst)
+	unavailable := errors.New("unavailable")
+
+	tests := []struct {
+		name      string
+		stages    []entity.SleepStage
+		wantAlert bool
+	}{
+		{"anomalous", nightStages(0, 240, 90, 120), true},
+		{"normal", nightStages(90, 240, 90, 40), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &mocks.MockBiometricsProvider{
+				FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+					return &entity.DailySummary{Date: date}, nil
+				},
+				FetchHRVFunc: func(_ context.Context, _ time.Time) (float32, float32, error) {
+					return 0, 0, unavailable
+				},
+				FetchSpO2Func: func(_ context.Context, _ time.Time) (float32, float32, float32, error) {
+					return 0, 0, 0, unavailable
+				},
+				FetchBreathingRateFunc: func(_ context.Context, _ time.Time) (float32, float32, float32, float32, error) {
+					return 0, 0, 0, 0, unavailable
+				},
+				FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) {
+					return 0, unavailable
+				},
+				FetchWaterLogFunc: func(_ context.Context, _ time.Time) (int, error) {
+					return 0, unavailable
+				},
+				FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
+					return nil, unavailable
+				},
+				FetchSleepStagesFunc: func(_ context.Context, _ time.Time) ([]entity.SleepStage, *entity.SleepRecord, error) {
+					return tt.stages, nil, nil
+				},
+				FetchExerciseLogsFunc: func(_ context.Context, _ time.Time) ([]entity.ExerciseLog, error) {
+					return nil, unavailable
+				},
+			}
+			var gotFrom, gotTo time.Time
+			summaryRepo := &mocks.MockDailySummaryRepository{
+				UpsertFunc: func(_ context.Context, _ *entity.DailySummary) error { return nil },
+				ListRangeFunc: func(_ context.Context, from, to time.Time) ([]entity.DailySummary, error) {
+					gotFrom, gotTo = from, to
+					return stagedNights(date, 30), nil
+				},
+			}
+			sleepRepo := &mocks.MockSleepStageRepository{
+				BulkUpsertFunc: func(_ context.Context, _ []entity.SleepStage) error { return nil },
+			}
+			var sent *entity.SleepStageAlert
+			sender := &mocks.MockWebhookSender{
+				SendFunc: func(_ context.Context, _ string, payload any) error {
+					sent = payload.(*entity.SleepStageAlert)
+					return nil
+				},
+			}
+
+			uc := NewSyncBiometricsUseCase(provider, summaryRepo, &mocks.MockHeartRateRepository{},
+				sleepRepo, &mocks.MockExerciseRepository{}, nil).
+				WithSleepStageAlert(sender)
+			if err := uc.SyncDate(context.Background(), date); err != nil {
+				t.Fatalf("SyncDate() error = %v", err)
+			}
+			if !gotFrom.Equal(date.AddDate(0, 0, -30)) || !gotTo.Equal(date.AddDate(0, 0, -1)) {
+				t.Errorf("ListRange(%v, %v), want the 30 nights before %v", gotFrom, gotTo, date)
+			}
+			if (sent != nil) != tt.wantAlert {
+				t.Fatalf("alert sent = %v, want %v", sent != nil, tt.wantAlert)
+			}
+			if sent != nil && (sent.Date != "2026-04-18" || sent.Stage != "deep") {
+				t.Errorf("alert = %+v, want deep on 2026-04-18", sent)
+			}
+		})
+	}
+}
+
+func TestSyncBiometrics_SleepStageAlert_SentOncePerDate(t *testing.T) {
+	date := time.Date(2026, 4, 18, 0, 0, 0, 0, jst)
+	provider := summaryOnlyProvider(entity.DailySummary{Date: date})
+	provider.FetchSleepStagesFunc = func(_ context.Context, _ time.Time) ([]entity.SleepStage, *entity.SleepRecord, error) {
+		return nightStages(0, 240, 90, 120), nil, nil
+	}
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		UpsertFunc: func(_ context.Context, _ *entity.DailySummary) error { return nil },
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+			return stagedNights(date, 30), nil
+		},
+	}
+	sleepRepo := &mocks.MockSleepStageRepository{
+		BulkUpsertFunc: func(_ context.Context, _ []entity.SleepStage) error { return nil },
+	}
+	sent := 0
+	sender := &mocks.MockWebhookSender{
+		SendFunc: func(_ context.Context, _ string, _ any) error {
+			sent++
+			return nil
+		},
+	}
+
+	uc := NewSyncBiometricsUseCase(provider, summaryRepo, &mocks.MockHeartRateRepository{},
+		sleepRepo, &mocks.MockExerciseRepository{}, nil).
+		WithSleepStageAlert(sender).
+		WithAlertSentStore(memAlertSentStore())
+	for i := 0; i < 3; i++ {
+		if err := uc.SyncDate(context.Background(), date); err != nil {
+			t.Fatalf("SyncDate() error = %v", err)
+		}
+	}
+	if sent != 1 {
+		t.Errorf("alerts sent = %d, want 1", sent)
+	}
+}
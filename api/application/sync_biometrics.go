@@ -2,9 +2,14 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/url"
 	"time"
 
+	"github.com/google/uuid"
+
 	"vitametron/api/domain/entity"
 	"vitametron/api/domain/port"
 )
@@ -16,6 +21,46 @@ type SyncBiometricsUseCase struct {
 	sleepRepo    port.SleepStageRepository
 	exerciseRepo port.ExerciseRepository
 	qualityRepo  port.DataQualityRepository
+	hrvRepo      port.HRVSampleRepository
+	azmRepo      port.ActiveZoneSampleRepository
+	hrAlert      port.WebhookSender
+	sleepAlert   port.WebhookSender
+	feverAlert   port.WebhookSender
+	alertsSent   port.AlertSentStore
+	vo2max       *ExerciseVO2MaxUseCase
+	fillForward  bool
+
+	retryCount   int
+	retryBackoff time.Duration
+}
+
+// SyncError records one failed attempt to fetch a date's daily summary.
+type SyncError struct {
+	Date    time.Time
+	Attempt int
+	Err     error
+}
+
+func (e *SyncError) Error() string {
+	return fmt.Sprintf("sync %s attempt %d: %v", e.Date.Format("2006-01-02"), e.Attempt, e.Err)
+}
+
+func (e *SyncError) Unwrap() error { return e.Err }
+
+// SyncErrors returns every attempt recorded in an error returned by SyncDate.
+func SyncErrors(err error) []*SyncError {
+	var attempts []*SyncError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			attempts = append(attempts, SyncErrors(e)...)
+		}
+		return attempts
+	}
+	var se *SyncError
+	if errors.As(err, &se) {
+		attempts = append(attempts, se)
+	}
+	return attempts
 }
 
 func NewSyncBiometricsUseCase(
@@ -36,27 +81,104 @@ func NewSyncBiometricsUseCase(
 	}
 }
 
+// WithRetry retries the daily summary fetch up to count times on network
+// errors, waiting backoff × attempt between tries.
+func (uc *SyncBiometricsUseCase) WithRetry(count int, backoff time.Duration) *SyncBiometricsUseCase {
+	uc.retryCount = count
+	uc.retryBackoff = backoff
+	return uc
+}
+
+// WithHRVSamples stores the provider's 5-minute HRV segments on each sync.
+func (uc *SyncBiometricsUseCase) WithHRVSamples(repo port.HRVSampleRepository) *SyncBiometricsUseCase {
+	uc.hrvRepo = repo
+	return uc
+}
+
+// WithActiveZoneSamples stores the provider's 1-minute active zone minutes on each sync.
+func (uc *SyncBiometricsUseCase) WithActiveZoneSamples(repo port.ActiveZoneSampleRepository) *SyncBiometricsUseCase {
+	uc.azmRepo = repo
+	return uc
+}
+
+// WithRestingHRAlert posts an entity.HRAlert to sender whenever a synced
+// day's resting HR shows a sustained rise.
+func (uc *SyncBiometricsUseCase) WithRestingHRAlert(sender port.WebhookSender) *SyncBiometricsUseCase {
+	uc.hrAlert = sender
+	return uc
+}
+
+// WithAlertSentStore sends each alert at most once per date, so the periodic
+// re-sync of today does not repeat it. Without a store every sync that
+// detects the condition sends again.
+func (uc *SyncBiometricsUseCase) WithAlertSentStore(store port.AlertSentStore) *SyncBiometricsUseCase {
+	uc.alertsSent = store
+	return uc
+}
+
+// WithSleepStageAlert posts an entity.SleepStageAlert to sender whenever a
+// synced night's stage breakdown departs sharply from the last 30 nights.
+func (uc *SyncBiometricsUseCase) WithSleepStageAlert(sender port.WebhookSender) *SyncBiometricsUseCase {
+	uc.sleepAlert = sender
+	return uc
+}
+
+// WithFeverAlert posts an entity.FeverAlert to sender whenever a synced day
+// is flagged as a fever candidate.
+func (uc *SyncBiometricsUseCase) WithFeverAlert(sender port.WebhookSender) *SyncBiometricsUseCase {
+	uc.feverAlert = sender
+	return uc
+}
+
+// WithVO2MaxEstimate estimates VO2max for each synced exercise with an
+// average HR, using est, before it is stored.
+func (uc *SyncBiometricsUseCase) WithVO2MaxEstimate(est *ExerciseVO2MaxUseCase) *SyncBiometricsUseCase {
+	uc.vo2max = est
+	return uc
+}
+
+// WithFillForward copies readings missing from a synced day from the
+// previous day's summary, see FillForwardSummary.
+func (uc *SyncBiometricsUseCase) WithFillForward() *SyncBiometricsUseCase {
+	uc.fillForward = true
+	return uc
+}
+
 func (uc *SyncBiometricsUseCase) SyncDate(ctx context.Context, date time.Time) error {
+	_, err := uc.SyncDateReport(ctx, date)
+	return err
+}
+
+// SyncDateReport syncs date like SyncDate and reports which metrics were
+// stored and which optional steps failed without aborting the sync.
+func (uc *SyncBiometricsUseCase) SyncDateReport(ctx context.Context, date time.Time) (*entity.SyncReport, error) {
+	report := &entity.SyncReport{Date: date.Format("2006-01-02"), SoftErrors: map[string]string{}}
+
 	// Fetch daily summary (includes activity, sleep summary, basic HR)
-	summary, err := uc.provider.FetchDailySummary(ctx, date)
+	summary, err := uc.fetchDailySummaryWithRetry(ctx, date)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	summary.ComputeActivityIntensityScore()
 
 	// Enrich with additional data, continue on individual fetch failures
 	if dailyRMSSD, deepRMSSD, err := uc.provider.FetchHRV(ctx, date); err == nil {
 		summary.HRVDailyRMSSD = entity.Float32Ptr(dailyRMSSD)
 		summary.HRVDeepRMSSD = entity.Float32Ptr(deepRMSSD)
+		report.Populated = append(report.Populated, entity.SyncStepHRV)
 	} else {
 		log.Printf("warn: FetchHRV failed for %s: %v", date.Format("2006-01-02"), err)
+		report.SoftErrors[entity.SyncStepHRV] = err.Error()
 	}
 
 	if avg, min, max, err := uc.provider.FetchSpO2(ctx, date); err == nil {
 		summary.SpO2Avg = entity.Float32Ptr(avg)
 		summary.SpO2Min = entity.Float32Ptr(min)
 		summary.SpO2Max = entity.Float32Ptr(max)
+		report.Populated = append(report.Populated, entity.SyncStepSpO2)
 	} else {
 		log.Printf("warn: FetchSpO2 failed for %s: %v", date.Format("2006-01-02"), err)
+		report.SoftErrors[entity.SyncStepSpO2] = err.Error()
 	}
 
 	if full, deep, light, rem, err := uc.provider.FetchBreathingRate(ctx, date); err == nil {
@@ -64,14 +186,26 @@ func (uc *SyncBiometricsUseCase) SyncDate(ctx context.Context, date time.Time) e
 		summary.BRDeepSleep = entity.Float32Ptr(deep)
 		summary.BRLightSleep = entity.Float32Ptr(light)
 		summary.BRREMSleep = entity.Float32Ptr(rem)
+		report.Populated = append(report.Populated, entity.SyncStepBreathingRate)
 	} else {
 		log.Printf("warn: FetchBreathingRate failed for %s: %v", date.Format("2006-01-02"), err)
+		report.SoftErrors[entity.SyncStepBreathingRate] = err.Error()
 	}
 
 	if temp, err := uc.provider.FetchSkinTemperature(ctx, date); err == nil {
 		summary.SkinTempVariation = entity.Float32Ptr(temp)
+		report.Populated = append(report.Populated, entity.SyncStepSkinTemp)
 	} else {
 		log.Printf("warn: FetchSkinTemperature failed for %s: %v", date.Format("2006-01-02"), err)
+		report.SoftErrors[entity.SyncStepSkinTemp] = err.Error()
+	}
+
+	if water, err := uc.provider.FetchWaterLog(ctx, date); err == nil {
+		summary.WaterIntakeMl = water
+		report.Populated = append(report.Populated, entity.SyncStepWater)
+	} else {
+		log.Printf("warn: FetchWaterLog failed for %s: %v", date.Format("2006-01-02"), err)
+		report.SoftErrors[entity.SyncStepWater] = err.Error()
 	}
 
 	// Fetch sleep stages + summary (before upsert so summary includes sleep data)
@@ -91,13 +225,38 @@ func (uc *SyncBiometricsUseCase) SyncDate(ctx context.Context, date time.Time) e
 			summary.SleepWakeMin = rec.WakeMin
 			summary.SleepIsMain = rec.IsMainSleep
 		}
+		report.Populated = append(report.Populated, entity.SyncStepSleep)
 	} else {
 		log.Printf("warn: FetchSleepStages failed for %s: %v", date.Format("2006-01-02"), err)
+		report.SoftErrors[entity.SyncStepSleep] = err.Error()
+	}
+
+	// Data quality describes what was measured, so it uses the summary as
+	// fetched, before any fill-forward.
+	measured := *summary
+	if uc.fillForward {
+		uc.fillForwardSummary(ctx, date, summary)
 	}
 
+	// Flag a fever candidate in the same write as the readings it is based on.
+	feverThreshold := uc.flagFeverCandidate(ctx, date, summary)
+
 	// Upsert enriched summary (now includes sleep)
 	if err := uc.summaryRepo.Upsert(ctx, summary); err != nil {
-		return err
+		return nil, err
+	}
+	report.Populated = append([]string{entity.SyncStepDailySummary}, report.Populated...)
+
+	if uc.feverAlert != nil && summary.FeverCandidate {
+		uc.alertFever(ctx, date, *summary.SkinTempVariation, feverThreshold)
+	}
+
+	if uc.hrAlert != nil && summary.RestingHR > 0 {
+		uc.alertRestingHRTrend(ctx, date)
+	}
+
+	if uc.sleepAlert != nil && len(sleepStages) > 0 {
+		uc.alertSleepStageAnomaly(ctx, date, sleepStages)
 	}
 
 	// Fetch and store HR intraday
@@ -106,6 +265,41 @@ func (uc *SyncBiometricsUseCase) SyncDate(ctx context.Context, date time.Time) e
 		hrSamples = samples
 		if err := uc.hrRepo.BulkUpsert(ctx, hrSamples); err != nil {
 			log.Printf("warn: BulkUpsert HR failed for %s: %v", date.Format("2006-01-02"), err)
+			report.SoftErrors[entity.SyncStepHeartRate] = err.Error()
+		} else {
+			report.Populated = append(report.Populated, entity.SyncStepHeartRate)
+		}
+	} else if err != nil {
+		report.SoftErrors[entity.SyncStepHeartRate] = err.Error()
+	}
+
+	// Fetch and store HRV intraday
+	if uc.hrvRepo != nil {
+		if samples, err := uc.provider.FetchHRVIntraday(ctx, date); err != nil {
+			log.Printf("warn: FetchHRVIntraday failed for %s: %v", date.Format("2006-01-02"), err)
+			report.SoftErrors[entity.SyncStepHRVIntraday] = err.Error()
+		} else if len(samples) > 0 {
+			if err := uc.hrvRepo.BulkUpsert(ctx, samples); err != nil {
+				log.Printf("warn: BulkUpsert HRV failed for %s: %v", date.Format("2006-01-02"), err)
+				report.SoftErrors[entity.SyncStepHRVIntraday] = err.Error()
+			} else {
+				report.Populated = append(report.Populated, entity.SyncStepHRVIntraday)
+			}
+		}
+	}
+
+	// Fetch and store active zone minutes intraday
+	if uc.azmRepo != nil {
+		if samples, err := uc.provider.FetchActiveZoneMinutesIntraday(ctx, date); err != nil {
+			log.Printf("warn: FetchActiveZoneMinutesIntraday failed for %s: %v", date.Format("2006-01-02"), err)
+			report.SoftErrors[entity.SyncStepActiveZones] = err.Error()
+		} else if len(samples) > 0 {
+			if err := uc.azmRepo.BulkUpsert(ctx, samples); err != nil {
+				log.Printf("warn: BulkUpsert active zone minutes failed for %s: %v", date.Format("2006-01-02"), err)
+				report.SoftErrors[entity.SyncStepActiveZones] = err.Error()
+			} else {
+				report.Populated = append(report.Populated, entity.SyncStepActiveZones)
+			}
 		}
 	}
 
@@ -113,27 +307,205 @@ func (uc *SyncBiometricsUseCase) SyncDate(ctx context.Context, date time.Time) e
 	if len(sleepStages) > 0 {
 		if err := uc.sleepRepo.BulkUpsert(ctx, sleepStages); err != nil {
 			log.Printf("warn: BulkUpsert sleep stages failed for %s: %v", date.Format("2006-01-02"), err)
+			report.SoftErrors[entity.SyncStepSleepStages] = err.Error()
+		} else {
+			report.Populated = append(report.Populated, entity.SyncStepSleepStages)
 		}
 	}
 
 	// Fetch and store exercise logs
 	if exercises, err := uc.provider.FetchExerciseLogs(ctx, date); err == nil {
+		stored := 0
 		for i := range exercises {
+			uc.fillVO2Max(ctx, &exercises[i])
 			if err := uc.exerciseRepo.Upsert(ctx, &exercises[i]); err != nil {
 				log.Printf("warn: Upsert exercise failed: %v", err)
+				report.SoftErrors[entity.SyncStepExercise] = err.Error()
+				continue
 			}
+			stored++
 		}
+		if stored > 0 {
+			report.Populated = append(report.Populated, entity.SyncStepExercise)
+		}
+	} else {
+		report.SoftErrors[entity.SyncStepExercise] = err.Error()
 	}
 
 	// Compute and store data quality
 	if uc.qualityRepo != nil {
-		quality := uc.computeDataQuality(ctx, date, summary, hrSamples)
+		quality := uc.computeDataQuality(ctx, date, &measured, hrSamples, sleepStages)
 		if err := uc.qualityRepo.Upsert(ctx, quality); err != nil {
 			log.Printf("warn: Upsert data quality failed for %s: %v", date.Format("2006-01-02"), err)
+			report.SoftErrors[entity.SyncStepDataQuality] = err.Error()
+		} else {
+			report.Populated = append(report.Populated, entity.SyncStepDataQuality)
 		}
 	}
 
-	return nil
+	return report, nil
+}
+
+// fillForwardSummary fills readings missing from summary with the previous
+// day's. Lookup failures are logged and leave the summary as fetched.
+func (uc *SyncBiometricsUseCase) fillForwardSummary(ctx context.Context, date time.Time, summary *entity.DailySummary) {
+	stored, err := uc.summaryRepo.GetByDate(ctx, date)
+	if err != nil {
+		log.Printf("warn: fill forward: get summary for %s: %v", date.Format("2006-01-02"), err)
+		return
+	}
+	previous, err := uc.summaryRepo.GetByDate(ctx, date.AddDate(0, 0, -1))
+	if err != nil {
+		log.Printf("warn: fill forward: get previous summary for %s: %v", date.Format("2006-01-02"), err)
+		return
+	}
+	keepStoredReadings(summary, stored)
+	FillForwardSummary(summary, previous)
+}
+
+// flagFeverCandidate sets summary.FeverCandidate and returns the threshold it
+// was compared with. A failed baseline lookup is logged and leaves no flag.
+func (uc *SyncBiometricsUseCase) flagFeverCandidate(ctx context.Context, date time.Time, summary *entity.DailySummary) float32 {
+	candidate, threshold, err := evaluateFever(ctx, date, summary, uc.summaryRepo)
+	if err != nil {
+		log.Printf("warn: fever candidate check failed for %s: %v", date.Format("2006-01-02"), err)
+	}
+	summary.FeverCandidate = candidate
+	return threshold
+}
+
+// alertFever sends a fever candidate alert for date. Failures are logged and
+// never fail the sync.
+func (uc *SyncBiometricsUseCase) alertFever(ctx context.Context, date time.Time, value, threshold float32) {
+	day := date.Format("2006-01-02")
+	log.Printf("warn: fever candidate on %s: skin temp variation %.2f exceeds threshold %.2f", day, value, threshold)
+	if !uc.claimAlert(ctx, alertKindFever, day) {
+		return
+	}
+	alert := &entity.FeverAlert{Date: day, SkinTempVariation: value, Threshold: threshold}
+	jobID := uuid.New().String()
+	if err := uc.feverAlert.Send(ctx, jobID, alert); err != nil {
+		log.Printf("fever alert %s: send %s failed: %v", jobID, day, err)
+		uc.releaseAlert(ctx, alertKindFever, day)
+		return
+	}
+	log.Printf("fever alert %s: sent %s", jobID, day)
+}
+
+// alertRestingHRTrend checks the resting HR trend ending on date and sends an
+// alert when it is rising. Failures are logged and never fail the sync.
+func (uc *SyncBiometricsUseCase) alertRestingHRTrend(ctx context.Context, date time.Time) {
+	day := date.Format("2006-01-02")
+	from, to := restingHRTrendWindow(date)
+	summaries, err := uc.summaryRepo.ListRange(ctx, from, to)
+	if err != nil {
+		log.Printf("warn: resting HR trend: list summaries for %s: %v", day, err)
+		return
+	}
+	alert := DetectRestingHRTrend(ctx, summaries)
+	if alert == nil || alert.Date != day {
+		return
+	}
+
+	if !uc.claimAlert(ctx, alertKindRestingHR, day) {
+		return
+	}
+	jobID := uuid.New().String()
+	if err := uc.hrAlert.Send(ctx, jobID, alert); err != nil {
+		log.Printf("resting hr alert %s: send %s failed: %v", jobID, day, err)
+		uc.releaseAlert(ctx, alertKindRestingHR, day)
+		return
+	}
+	log.Printf("resting hr alert %s: sent %s (+%.1f bpm)", jobID, day, alert.Delta)
+}
+
+// Alert kinds recorded in the AlertSentStore.
+const (
+	alertKindRestingHR  = "hr"
+	alertKindSleepStage = "sleep"
+	alertKindFever      = "fever"
+)
+
+// claimAlert reports whether the kind alert for day should be sent and marks
+// it as sent. A store failure is logged and lets the alert through.
+func (uc *SyncBiometricsUseCase) claimAlert(ctx context.Context, kind, day string) bool {
+	if uc.alertsSent == nil {
+		return true
+	}
+	first, err := uc.alertsSent.MarkSent(ctx, kind, day)
+	if err != nil {
+		log.Printf("warn: mark %s alert sent for %s: %v", kind, day, err)
+		return true
+	}
+	return first
+}
+
+// releaseAlert clears the mark set by claimAlert after a failed send.
+func (uc *SyncBiometricsUseCase) releaseAlert(ctx context.Context, kind, day string) {
+	if uc.alertsSent == nil {
+		return
+	}
+	if err := uc.alertsSent.ClearSent(ctx, kind, day); err != nil {
+		log.Printf("warn: clear %s alert mark for %s: %v", kind, day, err)
+	}
+}
+
+// alertSleepStageAnomaly compares the night of date with the 30 before it and
+// sends an alert when a stage share is anomalous. Failures are logged and
+// never fail the sync.
+func (uc *SyncBiometricsUseCase) alertSleepStageAnomaly(ctx context.Context, date time.Time, stages []entity.SleepStage) {
+	day := date.Format("2006-01-02")
+	from, to := sleepStageBaselineWindow(date)
+	summaries, err := uc.summaryRepo.ListRange(ctx, from, to)
+	if err != nil {
+		log.Printf("warn: sleep stage anomaly: list summaries for %s: %v", day, err)
+		return
+	}
+	alert := DetectSleepStageAnomaly(stages, summaries)
+	if alert == nil {
+		return
+	}
+	alert.Date = day
+
+	if !uc.claimAlert(ctx, alertKindSleepStage, day) {
+		return
+	}
+	jobID := uuid.New().String()
+	if err := uc.sleepAlert.Send(ctx, jobID, alert); err != nil {
+		log.Printf("sleep stage alert %s: send %s failed: %v", jobID, day, err)
+		uc.releaseAlert(ctx, alertKindSleepStage, day)
+		return
+	}
+	log.Printf("sleep stage alert %s: sent %s (%s %+.3f)", jobID, day, alert.Stage, alert.Delta)
+}
+
+// fetchDailySummaryWithRetry retries transient network failures only; HTTP
+// errors such as 401/403/404 will not improve on retry and fail immediately.
+// On failure the returned error joins a *SyncError for every attempt.
+func (uc *SyncBiometricsUseCase) fetchDailySummaryWithRetry(ctx context.Context, date time.Time) (*entity.DailySummary, error) {
+	var attempts []error
+	for attempt := 1; ; attempt++ {
+		summary, err := uc.provider.FetchDailySummary(ctx, date)
+		if err == nil {
+			if len(attempts) > 0 {
+				log.Printf("sync %s succeeded after %d failed attempts: %v",
+					date.Format("2006-01-02"), len(attempts), errors.Join(attempts...))
+			}
+			return summary, nil
+		}
+		attempts = append(attempts, &SyncError{Date: date, Attempt: attempt, Err: err})
+
+		var urlErr *url.Error
+		if attempt > uc.retryCount || !errors.As(err, &urlErr) || ctx.Err() != nil {
+			return nil, errors.Join(attempts...)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(attempts...)
+		case <-time.After(uc.retryBackoff * time.Duration(attempt)):
+		}
+	}
 }
 
 func (uc *SyncBiometricsUseCase) computeDataQuality(
@@ -141,6 +513,7 @@ func (uc *SyncBiometricsUseCase) computeDataQuality(
 	date time.Time,
 	summary *entity.DailySummary,
 	hrSamples []entity.HeartRateSample,
+	sleepStages []entity.SleepStage,
 ) *entity.DataQuality {
 	// Plausibility
 	flags := entity.CheckPlausibility(summary)
@@ -181,6 +554,20 @@ func (uc *SyncBiometricsUseCase) computeDataQuality(
 		baselineMaturity = "mature"
 	}
 
+	// Overnight SpO2 floor; 0 means no reading
+	var lowestSpO2 float32
+	if summary.SpO2Min != nil && *summary.SpO2Min > 0 {
+		lowestSpO2 = *summary.SpO2Min
+	}
+	spo2MinAlert := lowestSpO2 > 0 && lowestSpO2 < entity.SpO2AlertThreshold
+
+	sleepConfidence := sleepStageConfidence(summary)
+
+	var sleepStageQuality float32
+	if valid, total := entity.ValidateSleepStageTransitions(sleepStages); total > 0 {
+		sleepStageQuality = float32(valid) / float32(total) * 100
+	}
+
 	// Composite confidence score
 	wearNorm := wearTimeHours / 16.0
 	if wearNorm > 1.0 {
@@ -190,7 +577,7 @@ func (uc *SyncBiometricsUseCase) computeDataQuality(
 	if baselineNorm > 1.0 {
 		baselineNorm = 1.0
 	}
-	confidenceScore := 0.4*completenessPct + 0.3*wearNorm + 0.3*baselineNorm
+	confidenceScore := 0.36*completenessPct + 0.27*wearNorm + 0.27*baselineNorm + 0.1*sleepConfidence
 
 	var confidenceLevel string
 	switch {
@@ -203,19 +590,53 @@ func (uc *SyncBiometricsUseCase) computeDataQuality(
 	}
 
 	return &entity.DataQuality{
-		Date:              date,
-		WearTimeHours:     wearTimeHours,
-		HRSampleCount:     hrSampleCount,
-		CompletenessPct:   completenessPct,
-		MetricsPresent:    present,
-		MetricsMissing:    missing,
-		PlausibilityFlags: flags,
-		PlausibilityPass:  plausibilityPass,
-		IsValidDay:        isValidDay,
-		BaselineDays:      baselineDays,
-		BaselineMaturity:  baselineMaturity,
-		ConfidenceScore:   confidenceScore,
-		ConfidenceLevel:   confidenceLevel,
-		ComputedAt:        time.Now(),
+		Date:                 date,
+		WearTimeHours:        wearTimeHours,
+		HRSampleCount:        hrSampleCount,
+		CompletenessPct:      completenessPct,
+		MetricsPresent:       present,
+		MetricsMissing:       missing,
+		PlausibilityFlags:    flags,
+		PlausibilityPass:     plausibilityPass,
+		IsValidDay:           isValidDay,
+		BaselineDays:         baselineDays,
+		BaselineMaturity:     baselineMaturity,
+		ConfidenceScore:      confidenceScore,
+		ConfidenceLevel:      confidenceLevel,
+		LowestSpO2:           lowestSpO2,
+		SpO2MinAlert:         spo2MinAlert,
+		SleepStageConfidence: sleepConfidence,
+		SleepStageQualityPct: sleepStageQuality,
+		ComputedAt:           time.Now(),
+	}
+}
+
+// minStagedSleepMin is the shortest "stages" night trusted at full confidence;
+// Fitbit falls back to classic scoring for shorter sleeps.
+const minStagedSleepMin = 180
+
+// sleepStageConfidence rates the night's sleep staging: Fitbit's "stages"
+// algorithm is more accurate than "classic", and only for full-length nights.
+func sleepStageConfidence(s *entity.DailySummary) float32 {
+	switch {
+	case s.SleepType == "stages" && s.SleepDurationMin >= minStagedSleepMin:
+		return 1.0
+	case s.SleepType == "stages" && s.SleepDurationMin > 0:
+		return 0.5
+	case s.SleepType == "classic" && s.SleepDurationMin > 0:
+		return 0.2
+	default:
+		return 0
+	}
+}
+
+// fillVO2Max estimates VO2max for ex when an estimator is set. The day's
+// summary is stored first, so its resting HR is available.
+func (uc *SyncBiometricsUseCase) fillVO2Max(ctx context.Context, ex *entity.ExerciseLog) {
+	if uc.vo2max == nil {
+		return
+	}
+	if err := uc.vo2max.Fill(ctx, ex); err != nil {
+		log.Printf("warn: estimate VO2max for exercise %s: %v", ex.ExternalID, err)
 	}
 }
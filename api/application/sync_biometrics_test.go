@@ -3,6 +3,9 @@ package application
 import (
 	"context"
 	"errors"
+	"math"
+	"net/url"
+	"slices"
 	"testing"
 	"time"
 
@@ -31,7 +34,8 @@ func TestSyncBiometrics_AllSuccess(t *testing.T) {
 
 	provider := &mocks.MockBiometricsProvider{
 		FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
-			return &entity.DailySummary{Date: date, Steps: 10000}, nil
+			return &entity.DailySummary{Date: date, Steps: 10000,
+				MinutesSedentary: 700, MinutesLightly: 200, MinutesFairly: 50, MinutesVery: 50}, nil
 		},
 		FetchHRVFunc: func(_ context.Context, _ time.Time) (float32, float32, error) {
 			return 45.0, 55.0, nil
@@ -45,6 +49,9 @@ func TestSyncBiometrics_AllSuccess(t *testing.T) {
 		FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) {
 			return 0.5, nil
 		},
+		FetchWaterLogFunc: func(_ context.Context, _ time.Time) (int, error) {
+			return 1800, nil
+		},
 		FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
 			return []entity.HeartRateSample{{BPM: 72}}, nil
 		},
@@ -71,6 +78,9 @@ func TestSyncBiometrics_AllSuccess(t *testing.T) {
 	}
 
 	summaryRepo := &mocks.MockDailySummaryRepository{
+		GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) { return nil, nil },
+		// Fever detection reads the 30-day baseline; no history means no flag.
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) { return nil, nil },
 		UpsertFunc: func(_ context.Context, s *entity.DailySummary) error {
 			upserted = true
 			if s.HRVDailyRMSSD == nil || *s.HRVDailyRMSSD != 45.0 {
@@ -97,6 +107,13 @@ func TestSyncBiometrics_AllSuccess(t *testing.T) {
 			if !s.SleepIsMain {
 				t.Error("SleepIsMain = false, want true")
 			}
+			if s.WaterIntakeMl != 1800 {
+				t.Errorf("WaterIntakeMl = %d, want 1800", s.WaterIntakeMl)
+			}
+			// (200 + 50*2 + 50*3) / 1000 * 100
+			if s.ActivityIntensityScore != 45 {
+				t.Errorf("ActivityIntensityScore = %v, want 45", s.ActivityIntensityScore)
+			}
 			return nil
 		},
 	}
@@ -138,6 +155,12 @@ func TestSyncBiometrics_PartialFetchFailure_Continues(t *testing.T) {
 		FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) {
 			return 0, errors.New("temp unavailable")
 		},
+		FetchWaterLogFunc: func(_ context.Context, _ time.Time) (int, error) {
+			return 0, errors.New("water unavailable")
+		},
+		FetchHRVIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HRVSample, error) {
+			return nil, errors.New("hrv intraday unavailable")
+		},
 		FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
 			return nil, errors.New("hr unavailable")
 		},
@@ -150,18 +173,156 @@ func TestSyncBiometrics_PartialFetchFailure_Continues(t *testing.T) {
 	}
 
 	summaryRepo := &mocks.MockDailySummaryRepository{
-		UpsertFunc: func(_ context.Context, _ *entity.DailySummary) error { return nil },
+		GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) { return nil, nil },
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) { return nil, nil },
+		UpsertFunc:    func(_ context.Context, _ *entity.DailySummary) error { return nil },
 	}
 	hrRepo := &mocks.MockHeartRateRepository{}
 	sleepRepo := &mocks.MockSleepStageRepository{}
 	exerciseRepo := &mocks.MockExerciseRepository{}
 
-	uc := NewSyncBiometricsUseCase(provider, summaryRepo, hrRepo, sleepRepo, exerciseRepo, newQualityRepo())
+	uc := NewSyncBiometricsUseCase(provider, summaryRepo, hrRepo, sleepRepo, exerciseRepo, newQualityRepo()).
+		WithHRVSamples(&mocks.MockHRVSampleRepository{})
 	if err := uc.SyncDate(context.Background(), date); err != nil {
 		t.Fatalf("SyncDate() should succeed with partial failures, got error = %v", err)
 	}
 }
 
+func TestSyncBiometrics_StoresHRVIntraday(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	unavailable := errors.New("unavailable")
+
+	provider := &mocks.MockBiometricsProvider{
+		FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+			return &entity.DailySummary{Date: date}, nil
+		},
+		FetchHRVFunc: func(_ context.Context, _ time.Time) (float32, float32, error) {
+			return 0, 0, unavailable
+		},
+		FetchSpO2Func: func(_ context.Context, _ time.Time) (float32, float32, float32, error) {
+			return 0, 0, 0, unavailable
+		},
+		FetchBreathingRateFunc: func(_ context.Context, _ time.Time) (float32, float32, float32, float32, error) {
+			return 0, 0, 0, 0, unavailable
+		},
+		FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) {
+			return 0, unavailable
+		},
+		FetchWaterLogFunc: func(_ context.Context, _ time.Time) (int, error) {
+			return 0, unavailable
+		},
+		FetchHRVIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HRVSample, error) {
+			return []entity.HRVSample{
+				{Time: date.Add(3 * time.Hour), RMSSD: 38.2},
+				{Time: date.Add(3*time.Hour + 5*time.Minute), RMSSD: 41.0},
+			}, nil
+		},
+		FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
+			return nil, unavailable
+		},
+		FetchSleepStagesFunc: func(_ context.Context, _ time.Time) ([]entity.SleepStage, *entity.SleepRecord, error) {
+			return nil, nil, unavailable
+		},
+		FetchExerciseLogsFunc: func(_ context.Context, _ time.Time) ([]entity.ExerciseLog, error) {
+			return nil, unavailable
+		},
+	}
+
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) { return nil, nil },
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) { return nil, nil },
+		UpsertFunc:    func(_ context.Context, _ *entity.DailySummary) error { return nil },
+	}
+	var stored []entity.HRVSample
+	hrvRepo := &mocks.MockHRVSampleRepository{
+		BulkUpsertFunc: func(_ context.Context, samples []entity.HRVSample) error {
+			stored = samples
+			return nil
+		},
+	}
+
+	uc := NewSyncBiometricsUseCase(provider, summaryRepo, &mocks.MockHeartRateRepository{},
+		&mocks.MockSleepStageRepository{}, &mocks.MockExerciseRepository{}, newQualityRepo()).
+		WithHRVSamples(hrvRepo)
+	if err := uc.SyncDate(context.Background(), date); err != nil {
+		t.Fatalf("SyncDate() error = %v", err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("stored %d HRV samples, want 2", len(stored))
+	}
+	if stored[1].RMSSD != 41.0 {
+		t.Errorf("stored[1].RMSSD = %v, want 41.0", stored[1].RMSSD)
+	}
+}
+
+func TestSyncBiometrics_StoresActiveZones(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	unavailable := errors.New("unavailable")
+
+	provider := &mocks.MockBiometricsProvider{
+		FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+			return &entity.DailySummary{Date: date}, nil
+		},
+		FetchHRVFunc: func(_ context.Context, _ time.Time) (float32, float32, error) {
+			return 0, 0, unavailable
+		},
+		FetchSpO2Func: func(_ context.Context, _ time.Time) (float32, float32, float32, error) {
+			return 0, 0, 0, unavailable
+		},
+		FetchBreathingRateFunc: func(_ context.Context, _ time.Time) (float32, float32, float32, float32, error) {
+			return 0, 0, 0, 0, unavailable
+		},
+		FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) {
+			return 0, unavailable
+		},
+		FetchWaterLogFunc: func(_ context.Context, _ time.Time) (int, error) {
+			return 0, unavailable
+		},
+		FetchActiveZoneMinutesIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.ActiveZoneSample, error) {
+			return []entity.ActiveZoneSample{
+				{Time: date.Add(7 * time.Hour), FatBurnMin: 1},
+				{Time: date.Add(7*time.Hour + time.Minute), CardioMin: 1},
+			}, nil
+		},
+		FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
+			return nil, unavailable
+		},
+		FetchSleepStagesFunc: func(_ context.Context, _ time.Time) ([]entity.SleepStage, *entity.SleepRecord, error) {
+			return nil, nil, unavailable
+		},
+		FetchExerciseLogsFunc: func(_ context.Context, _ time.Time) ([]entity.ExerciseLog, error) {
+			return nil, unavailable
+		},
+	}
+
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) { return nil, nil },
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) { return nil, nil },
+		UpsertFunc:    func(_ context.Context, _ *entity.DailySummary) error { return nil },
+	}
+	var stored []entity.ActiveZoneSample
+	azmRepo := &mocks.MockActiveZoneSampleRepository{
+		BulkUpsertFunc: func(_ context.Context, samples []entity.ActiveZoneSample) error {
+			stored = samples
+			return nil
+		},
+	}
+
+	uc := NewSyncBiometricsUseCase(provider, summaryRepo, &mocks.MockHeartRateRepository{},
+		&mocks.MockSleepStageRepository{}, &mocks.MockExerciseRepository{}, newQualityRepo()).
+		WithActiveZoneSamples(azmRepo)
+	report, err := uc.SyncDateReport(context.Background(), date)
+	if err != nil {
+		t.Fatalf("SyncDateReport() error = %v", err)
+	}
+	if len(stored) != 2 || stored[1].CardioMin != 1 {
+		t.Fatalf("stored = %+v, want 2 samples with the second in cardio", stored)
+	}
+	if !slices.Contains(report.Populated, entity.SyncStepActiveZones) {
+		t.Errorf("Populated = %v, want %s", report.Populated, entity.SyncStepActiveZones)
+	}
+}
+
 func TestSyncBiometrics_DailySummaryFetchError_ReturnsImmediately(t *testing.T) {
 	provider := &mocks.MockBiometricsProvider{
 		FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
@@ -176,6 +337,61 @@ func TestSyncBiometrics_DailySummaryFetchError_ReturnsImmediately(t *testing.T)
 	}
 }
 
+func TestSyncBiometrics_DailySummaryFetchRetry(t *testing.T) {
+	networkErr := &url.Error{Op: "Get", URL: "https://api.fitbit.com", Err: errors.New("connection reset")}
+	authErr := errors.New("fitbit: /1/user/-/activities returned 401: unauthorized")
+
+	tests := []struct {
+		name         string
+		errs         []error
+		wantCalls    int
+		wantErr      bool
+		wantAttempts int
+	}{
+		{"network error then success", []error{networkErr, nil}, 2, false, 0},
+		{"network error exhausts retries", []error{networkErr, networkErr, networkErr, networkErr}, 4, true, 4},
+		{"auth error not retried", []error{authErr}, 1, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			provider := &mocks.MockBiometricsProvider{
+				FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+					err := tt.errs[calls]
+					calls++
+					if err != nil {
+						return nil, err
+					}
+					return &entity.DailySummary{}, nil
+				},
+			}
+
+			uc := NewSyncBiometricsUseCase(provider, nil, nil, nil, nil, nil).WithRetry(3, 0)
+			summary, err := uc.fetchDailySummaryWithRetry(context.Background(), time.Now())
+
+			if calls != tt.wantCalls {
+				t.Errorf("FetchDailySummary calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && summary == nil {
+				t.Error("summary = nil, want non-nil")
+			}
+			attempts := SyncErrors(err)
+			if len(attempts) != tt.wantAttempts {
+				t.Fatalf("SyncErrors() len = %d, want %d", len(attempts), tt.wantAttempts)
+			}
+			for i, a := range attempts {
+				if a.Attempt != i+1 {
+					t.Errorf("attempts[%d].Attempt = %d, want %d", i, a.Attempt, i+1)
+				}
+			}
+		})
+	}
+}
+
 func TestSyncBiometrics_ComputesDataQuality(t *testing.T) {
 	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
 	var qualityUpserted bool
@@ -208,6 +424,9 @@ func TestSyncBiometrics_ComputesDataQuality(t *testing.T) {
 		FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) {
 			return 0.5, nil
 		},
+		FetchWaterLogFunc: func(_ context.Context, _ time.Time) (int, error) {
+			return 1800, nil
+		},
 		FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
 			return hrSamples, nil
 		},
@@ -220,7 +439,9 @@ func TestSyncBiometrics_ComputesDataQuality(t *testing.T) {
 	}
 
 	summaryRepo := &mocks.MockDailySummaryRepository{
-		UpsertFunc: func(_ context.Context, _ *entity.DailySummary) error { return nil },
+		GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) { return nil, nil },
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) { return nil, nil },
+		UpsertFunc:    func(_ context.Context, _ *entity.DailySummary) error { return nil },
 	}
 	hrRepo := &mocks.MockHeartRateRepository{
 		BulkUpsertFunc: func(_ context.Context, _ []entity.HeartRateSample) error { return nil },
@@ -275,7 +496,191 @@ func TestSyncBiometrics_ComputesDataQuality(t *testing.T) {
 	if capturedQuality.CompletenessPct != 1.0 {
 		t.Errorf("CompletenessPct = %f, want 1.0", capturedQuality.CompletenessPct)
 	}
+	if capturedQuality.LowestSpO2 != 95.0 {
+		t.Errorf("LowestSpO2 = %f, want 95.0", capturedQuality.LowestSpO2)
+	}
+	if capturedQuality.SpO2MinAlert {
+		t.Error("SpO2MinAlert = true, want false")
+	}
+	if capturedQuality.SleepStageConfidence != 0 {
+		t.Errorf("SleepStageConfidence = %f, want 0 without sleep data", capturedQuality.SleepStageConfidence)
+	}
+	if capturedQuality.SleepStageQualityPct != 0 {
+		t.Errorf("SleepStageQualityPct = %f, want 0 without sleep stages", capturedQuality.SleepStageQualityPct)
+	}
+	// 0.36*1.0 completeness + 0.27*(10/16) wear + 0.27*(30/60) baseline + 0.1*0 sleep
+	if got := capturedQuality.ConfidenceScore; got < 0.6637 || got > 0.6638 {
+		t.Errorf("ConfidenceScore = %f, want 0.66375", got)
+	}
 	if capturedQuality.ConfidenceScore <= 0 {
 		t.Errorf("ConfidenceScore = %f, want > 0", capturedQuality.ConfidenceScore)
 	}
 }
+
+func TestSyncBiometrics_SpO2MinAlert(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		spo2Min    float32
+		spo2Err    error
+		wantLowest float32
+		wantAlert  bool
+	}{
+		{"below threshold", 86.0, nil, 86.0, true},
+		{"at threshold", 88.0, nil, 88.0, false},
+		{"normal", 94.0, nil, 94.0, false},
+		{"no reading", 0, errors.New("spo2 unavailable"), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unavailable := errors.New("unavailable")
+			provider := &mocks.MockBiometricsProvider{
+				FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+					return &entity.DailySummary{Date: date}, nil
+				},
+				FetchHRVFunc: func(_ context.Context, _ time.Time) (float32, float32, error) {
+					return 0, 0, unavailable
+				},
+				FetchSpO2Func: func(_ context.Context, _ time.Time) (float32, float32, float32, error) {
+					return 95.0, tt.spo2Min, 99.0, tt.spo2Err
+				},
+				FetchBreathingRateFunc: func(_ context.Context, _ time.Time) (float32, float32, float32, float32, error) {
+					return 0, 0, 0, 0, unavailable
+				},
+				FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) {
+					return 0, unavailable
+				},
+				FetchWaterLogFunc: func(_ context.Context, _ time.Time) (int, error) {
+					return 0, unavailable
+				},
+				FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
+					return nil, unavailable
+				},
+				FetchSleepStagesFunc: func(_ context.Context, _ time.Time) ([]entity.SleepStage, *entity.SleepRecord, error) {
+					return nil, nil, unavailable
+				},
+				FetchExerciseLogsFunc: func(_ context.Context, _ time.Time) ([]entity.ExerciseLog, error) {
+					return nil, unavailable
+				},
+			}
+			summaryRepo := &mocks.MockDailySummaryRepository{
+				UpsertFunc: func(_ context.Context, _ *entity.DailySummary) error { return nil },
+			}
+			var captured *entity.DataQuality
+			qualityRepo := newQualityRepo()
+			qualityRepo.UpsertFunc = func(_ context.Context, q *entity.DataQuality) error {
+				captured = q
+				return nil
+			}
+
+			uc := NewSyncBiometricsUseCase(provider, summaryRepo, &mocks.MockHeartRateRepository{},
+				&mocks.MockSleepStageRepository{}, &mocks.MockExerciseRepository{}, qualityRepo)
+			if err := uc.SyncDate(context.Background(), date); err != nil {
+				t.Fatalf("SyncDate() error = %v", err)
+			}
+			if captured == nil {
+				t.Fatal("data quality was not upserted")
+			}
+			if captured.LowestSpO2 != tt.wantLowest {
+				t.Errorf("LowestSpO2 = %v, want %v", captured.LowestSpO2, tt.wantLowest)
+			}
+			if captured.SpO2MinAlert != tt.wantAlert {
+				t.Errorf("SpO2MinAlert = %v, want %v", captured.SpO2MinAlert, tt.wantAlert)
+			}
+		})
+	}
+}
+
+func TestSleepStageConfidence(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary entity.DailySummary
+		want    float32
+	}{
+		{"full stages night", entity.DailySummary{SleepType: "stages", SleepDurationMin: 420}, 1.0},
+		{"stages at threshold", entity.DailySummary{SleepType: "stages", SleepDurationMin: 180}, 1.0},
+		{"short stages", entity.DailySummary{SleepType: "stages", SleepDurationMin: 120}, 0.5},
+		{"classic", entity.DailySummary{SleepType: "classic", SleepDurationMin: 400}, 0.2},
+		{"no sleep", entity.DailySummary{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sleepStageConfidence(&tt.summary); got != tt.want {
+				t.Errorf("sleepStageConfidence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeDataQuality_SleepStageQualityPct(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	start := date.Add(-2 * time.Hour)
+	// 90 min of deep and 40 min of REM in plausible episodes, plus a 3 min
+	// REM fragment: 130 of 133 minutes are plausible.
+	var stages []entity.SleepStage
+	for _, s := range []struct {
+		stage   string
+		minutes int
+	}{{"deep", 90}, {"light", 10}, {"rem", 3}, {"light", 10}, {"rem", 40}} {
+		stages = append(stages, entity.SleepStage{Time: start, Stage: s.stage, Seconds: s.minutes * 60})
+		start = start.Add(time.Duration(s.minutes) * time.Minute)
+	}
+
+	uc := NewSyncBiometricsUseCase(nil, nil, nil, nil, nil, nil)
+	q := uc.computeDataQuality(context.Background(), date, &entity.DailySummary{Date: date}, nil, stages)
+	if want := float32(130) / 133 * 100; math.Abs(float64(q.SleepStageQualityPct-want)) > 0.001 {
+		t.Errorf("SleepStageQualityPct = %v, want %v", q.SleepStageQualityPct, want)
+	}
+}
+
+func TestSyncBiometrics_EstimatesExerciseVO2Max(t *testing.T) {
+	date := time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC)
+	var stored *entity.DailySummary
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		UpsertFunc: func(_ context.Context, s *entity.DailySummary) error {
+			stored = s
+			return nil
+		},
+		GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+			return stored, nil
+		},
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+			return nil, nil
+		},
+	}
+	var upserted []entity.ExerciseLog
+	exerciseRepo := &mocks.MockExerciseRepository{
+		UpsertFunc: func(_ context.Context, l *entity.ExerciseLog) error {
+			upserted = append(upserted, *l)
+			return nil
+		},
+	}
+	provider := summaryOnlyProvider(entity.DailySummary{Date: date, RestingHR: 60, MaxHR: 180})
+	provider.FetchExerciseLogsFunc = func(_ context.Context, _ time.Time) ([]entity.ExerciseLog, error) {
+		started := time.Date(2026, 4, 18, 7, 0, 0, 0, jst)
+		return []entity.ExerciseLog{
+			{ExternalID: "run", StartedAt: started, AvgHR: 150},
+			{ExternalID: "yoga", StartedAt: started},
+		}, nil
+	}
+
+	uc := NewSyncBiometricsUseCase(provider, summaryRepo, &mocks.MockHeartRateRepository{},
+		&mocks.MockSleepStageRepository{}, exerciseRepo, nil).
+		WithVO2MaxEstimate(NewExerciseVO2MaxUseCase(exerciseRepo, summaryRepo, 0))
+	if err := uc.SyncDate(context.Background(), date); err != nil {
+		t.Fatalf("SyncDate() error = %v", err)
+	}
+
+	if len(upserted) != 2 {
+		t.Fatalf("upserted %d exercises, want 2", len(upserted))
+	}
+	// 15 * (180 / 60), from the summary stored earlier in the same sync.
+	if v := upserted[0].EstimatedVO2Max; v == nil || *v != 45 {
+		t.Errorf("run VO2max = %v, want 45", v)
+	}
+	if v := upserted[1].EstimatedVO2Max; v != nil {
+		t.Errorf("yoga VO2max = %v, want nil without avg HR", *v)
+	}
+}
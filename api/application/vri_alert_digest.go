@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// VRIAlertDigestUseCase reports the days of a week whose VRI score fell
+// below a threshold to the VRI alert webhook.
+type VRIAlertDigestUseCase struct {
+	vriRepo   port.VRIRepository
+	sender    port.WebhookSender
+	threshold float64
+}
+
+func NewVRIAlertDigestUseCase(vriRepo port.VRIRepository, sender port.WebhookSender, threshold float64) *VRIAlertDigestUseCase {
+	return &VRIAlertDigestUseCase{vriRepo: vriRepo, sender: sender, threshold: threshold}
+}
+
+// Build collects the days in the week starting weekStart whose VRI score is
+// below the threshold, oldest first.
+func (uc *VRIAlertDigestUseCase) Build(ctx context.Context, weekStart time.Time) (*entity.VRIAlertDigest, error) {
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	scores, err := uc.vriRepo.ListRange(ctx, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &entity.VRIAlertDigest{
+		WeekStart: weekStart,
+		WeekEnd:   weekEnd,
+		AlertDays: []entity.VRIAlertDay{},
+	}
+	for _, s := range scores {
+		if float64(s.VRIScore) >= uc.threshold {
+			continue
+		}
+		digest.AlertDays = append(digest.AlertDays, entity.VRIAlertDay{
+			Date:          s.Date.Format("2006-01-02"),
+			VRIScore:      s.VRIScore,
+			VRIConfidence: s.VRIConfidence,
+		})
+	}
+	sort.Slice(digest.AlertDays, func(i, j int) bool {
+		return digest.AlertDays[i].Date < digest.AlertDays[j].Date
+	})
+	digest.LowVRICount = len(digest.AlertDays)
+	return digest, nil
+}
+
+// Send builds the week's alert digest and POSTs it only when at least one
+// day is below the threshold.
+func (uc *VRIAlertDigestUseCase) Send(ctx context.Context, weekStart time.Time) (*entity.VRIAlertDigest, error) {
+	week := weekStart.Format("2006-01-02")
+	digest, err := uc.Build(ctx, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	if digest.LowVRICount == 0 {
+		log.Printf("vri alert: week %s has no days below %.1f", week, uc.threshold)
+		return digest, nil
+	}
+
+	jobID := uuid.New().String()
+	if err := uc.sender.Send(ctx, jobID, digest); err != nil {
+		log.Printf("vri alert %s: send week %s failed: %v", jobID, week, err)
+		return digest, err
+	}
+	log.Printf("vri alert %s: sent week %s with %d low days", jobID, week, digest.LowVRICount)
+	return digest, nil
+}
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"vitametron/api/adapter/webhook"
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestVRIAlertDigestUseCase_Send(t *testing.T) {
+	weekStart := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	repo := &mocks.MockVRIRepository{
+		ListRangeFunc: func(_ context.Context, from, to time.Time) ([]entity.VRIScore, error) {
+			if !from.Equal(weekStart) || !to.Equal(weekStart.AddDate(0, 0, 6)) {
+				t.Errorf("ListRange(%v, %v), want the Mon–Sun week", from, to)
+			}
+			return []entity.VRIScore{
+				{Date: weekStart.AddDate(0, 0, 4), VRIScore: 22.5, VRIConfidence: 0.6},
+				{Date: weekStart, VRIScore: 65, VRIConfidence: 0.9},
+				{Date: weekStart.AddDate(0, 0, 1), VRIScore: 35, VRIConfidence: 0.8},
+				{Date: weekStart.AddDate(0, 0, 2), VRIScore: 40, VRIConfidence: 0.8},
+			}, nil
+		},
+	}
+
+	var body []byte
+	var signature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(webhook.SignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	uc := NewVRIAlertDigestUseCase(repo, webhook.New(srv.URL, "secret"), 40)
+	digest, err := uc.Send(context.Background(), weekStart)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if body == nil {
+		t.Fatal("webhook not called")
+	}
+	if want := webhook.Sign("secret", body); signature != want {
+		t.Errorf("signature = %q, want %q", signature, want)
+	}
+
+	var payload struct {
+		WeekStart   time.Time `json:"week_start"`
+		WeekEnd     time.Time `json:"week_end"`
+		LowVRICount int       `json:"low_vri_count"`
+		AlertDays   []struct {
+			Date          string  `json:"date"`
+			VRIScore      float32 `json:"vri_score"`
+			VRIConfidence float32 `json:"vri_confidence"`
+		} `json:"alert_days"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.LowVRICount != 2 || digest.LowVRICount != 2 || len(payload.AlertDays) != 2 {
+		t.Fatalf("payload = %+v, want 2 low days", payload)
+	}
+	if payload.AlertDays[0].Date != "2025-01-07" || payload.AlertDays[1].Date != "2025-01-10" {
+		t.Errorf("alert dates = %s, %s; want 2025-01-07, 2025-01-10", payload.AlertDays[0].Date, payload.AlertDays[1].Date)
+	}
+	if payload.AlertDays[1].VRIScore != 22.5 || payload.AlertDays[1].VRIConfidence != 0.6 {
+		t.Errorf("alert day = %+v", payload.AlertDays[1])
+	}
+	if !payload.WeekEnd.Equal(weekStart.AddDate(0, 0, 6)) {
+		t.Errorf("week_end = %v", payload.WeekEnd)
+	}
+}
+
+func TestVRIAlertDigestUseCase_Send_NoLowDays(t *testing.T) {
+	repo := &mocks.MockVRIRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.VRIScore, error) {
+			return []entity.VRIScore{{VRIScore: 70}}, nil
+		},
+	}
+	// SendFunc left nil: sending would panic.
+	uc := NewVRIAlertDigestUseCase(repo, &mocks.MockWebhookSender{}, 40)
+
+	digest, err := uc.Send(context.Background(), time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if digest.LowVRICount != 0 {
+		t.Errorf("LowVRICount = %d, want 0", digest.LowVRICount)
+	}
+}
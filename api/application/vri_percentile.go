@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+	"math"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+const (
+	// vriPercentileWindowDays is the history each score is placed against.
+	vriPercentileWindowDays = 90
+	// minVRIPercentileDays is the fewest prior scores for a percentile.
+	minVRIPercentileDays = 14
+)
+
+// ComputeVRIPercentile returns the normal CDF of score for the given mean and
+// standard deviation, 0-1. A zero std is the limit of the CDF: 0.5 at the
+// mean and 0 or 1 either side.
+func ComputeVRIPercentile(score, mean, std float32) float32 {
+	if std <= 0 {
+		switch {
+		case score < mean:
+			return 0
+		case score > mean:
+			return 1
+		default:
+			return 0.5
+		}
+	}
+	z := float64(score-mean) / (float64(std) * math.Sqrt2)
+	return float32(0.5 * (1 + math.Erf(z)))
+}
+
+// AttachVRIPercentiles sets PopulationPercentile on each score in place from
+// the vriPercentileWindowDays of scores before its date.
+func AttachVRIPercentiles(ctx context.Context, repo port.VRIRepository, scores []entity.VRIScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+	first, last := scores[0].Date, scores[0].Date
+	for _, s := range scores[1:] {
+		if s.Date.Before(first) {
+			first = s.Date
+		}
+		if s.Date.After(last) {
+			last = s.Date
+		}
+	}
+	history, err := repo.ListRange(ctx, first.AddDate(0, 0, -vriPercentileWindowDays), last)
+	if err != nil {
+		return err
+	}
+	applyVRIPercentiles(history, scores)
+	return nil
+}
+
+// applyVRIPercentiles compares dates as JST calendar days, since scores
+// from the DB and from the ML service carry different zones.
+func applyVRIPercentiles(history, scores []entity.VRIScore) {
+	for i := range scores {
+		day := scores[i].Date.In(jst).Format("2006-01-02")
+		start := scores[i].Date.In(jst).AddDate(0, 0, -vriPercentileWindowDays).Format("2006-01-02")
+		var window []float64
+		for _, h := range history {
+			d := h.Date.In(jst).Format("2006-01-02")
+			if d >= start && d < day {
+				window = append(window, float64(h.VRIScore))
+			}
+		}
+		if len(window) < minVRIPercentileDays {
+			scores[i].PopulationPercentile = nil
+			continue
+		}
+		mean, std := meanStdDev(window)
+		p := ComputeVRIPercentile(scores[i].VRIScore, float32(mean), float32(std))
+		scores[i].PopulationPercentile = &p
+	}
+}
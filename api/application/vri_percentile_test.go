@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestComputeVRIPercentile(t *testing.T) {
+	tests := []struct {
+		name             string
+		score, mean, std float32
+		want             float32
+	}{
+		{"at mean", 60, 60, 10, 0.5},
+		{"one SD above", 70, 60, 10, 0.8413},
+		{"one SD below", 50, 60, 10, 0.1587},
+		{"two SD below", 40, 60, 10, 0.0228},
+		{"no spread below", 59, 60, 0, 0},
+		{"no spread above", 61, 60, 0, 1},
+		{"no spread at mean", 60, 60, 0, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeVRIPercentile(tt.score, tt.mean, tt.std)
+			if math.Abs(float64(got-tt.want)) > 1e-4 {
+				t.Errorf("ComputeVRIPercentile(%v, %v, %v) = %v, want %v", tt.score, tt.mean, tt.std, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachVRIPercentiles(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, d) }
+
+	// 20 days alternating 50 and 70: mean 60, sample SD ~10.26.
+	var history []entity.VRIScore
+	for i := 0; i < 20; i++ {
+		v := float32(50)
+		if i%2 == 1 {
+			v = 70
+		}
+		history = append(history, entity.VRIScore{Date: day(i), VRIScore: v})
+	}
+
+	var gotFrom, gotTo time.Time
+	repo := &mocks.MockVRIRepository{
+		ListRangeFunc: func(_ context.Context, from, to time.Time) ([]entity.VRIScore, error) {
+			gotFrom, gotTo = from, to
+			return history, nil
+		},
+	}
+
+	// Day 20 has the full history before it; day 10 only ten prior days.
+	// The ML fallback returns JST-midnight dates, which must match too.
+	jstDay20 := time.Date(2025, 4, 21, 0, 0, 0, 0, jst)
+	scores := []entity.VRIScore{
+		{Date: jstDay20, VRIScore: 60},
+		{Date: day(10), VRIScore: 60},
+	}
+	if err := AttachVRIPercentiles(context.Background(), repo, scores); err != nil {
+		t.Fatal(err)
+	}
+
+	if !gotFrom.Equal(day(10).AddDate(0, 0, -vriPercentileWindowDays)) || !gotTo.Equal(jstDay20) {
+		t.Errorf("ListRange(%v, %v), want 90 days before the earliest score through the latest", gotFrom, gotTo)
+	}
+	if p := scores[0].PopulationPercentile; p == nil || math.Abs(float64(*p)-0.5) > 1e-4 {
+		t.Errorf("day 20 percentile = %v, want 0.5", p)
+	}
+	if scores[1].PopulationPercentile != nil {
+		t.Errorf("day 10 percentile = %v, want nil with only 10 prior days", *scores[1].PopulationPercentile)
+	}
+}
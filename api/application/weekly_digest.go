@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// conditionTrendThreshold is the week-over-week change in average overall VAS
+// (0-100) needed to call the condition trend improving or declining.
+const conditionTrendThreshold = 5.0
+
+// WeeklyDigestUseCase gathers a week of health signals and delivers them to
+// the digest webhook.
+type WeeklyDigestUseCase struct {
+	predictor     port.MLPredictor
+	vriRepo       port.VRIRepository
+	anomalyRepo   port.AnomalyRepository
+	who5Repo      port.WHO5Repository
+	conditionRepo port.ConditionRepository
+	sender        port.WebhookSender
+}
+
+func NewWeeklyDigestUseCase(
+	predictor port.MLPredictor,
+	vriRepo port.VRIRepository,
+	anomalyRepo port.AnomalyRepository,
+	who5Repo port.WHO5Repository,
+	conditionRepo port.ConditionRepository,
+	sender port.WebhookSender,
+) *WeeklyDigestUseCase {
+	return &WeeklyDigestUseCase{
+		predictor:     predictor,
+		vriRepo:       vriRepo,
+		anomalyRepo:   anomalyRepo,
+		who5Repo:      who5Repo,
+		conditionRepo: conditionRepo,
+		sender:        sender,
+	}
+}
+
+// LastCompletedWeek returns the Monday starting the most recent Mon–Sun week
+// that ended before now, at midnight in now's location.
+func LastCompletedWeek(now time.Time) time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	sinceMonday := (int(today.Weekday()) + 6) % 7
+	return today.AddDate(0, 0, -sinceMonday-7)
+}
+
+// Build assembles the digest for the week starting weekStart. A failing ML
+// service only drops the insight; repository errors fail the build.
+func (uc *WeeklyDigestUseCase) Build(ctx context.Context, weekStart time.Time) (*entity.WeeklyDigest, error) {
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	digest := &entity.WeeklyDigest{
+		WeekStart:   weekStart,
+		WeekEnd:     weekEnd,
+		GeneratedAt: time.Now(),
+	}
+
+	insight, err := uc.predictor.GetWeeklyInsights(ctx, weekEnd)
+	if err != nil {
+		log.Printf("warn: digest weekly insight: %v", err)
+	} else {
+		digest.Insight = insight
+	}
+
+	scores, err := uc.vriRepo.ListRange(ctx, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) > 0 {
+		var sum float64
+		for _, s := range scores {
+			sum += float64(s.VRIScore)
+		}
+		avg := math.Round(sum/float64(len(scores))*10) / 10
+		digest.AvgVRIScore = &avg
+		digest.VRIDays = len(scores)
+	}
+
+	anomalies, err := uc.anomalyRepo.ListRange(ctx, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range anomalies {
+		if a.IsAnomaly {
+			digest.AnomalyCount++
+		}
+	}
+
+	who5, err := uc.who5Repo.GetLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if who5 != nil && !who5.AssessedAt.Before(weekStart) && who5.AssessedAt.Before(weekEnd.AddDate(0, 0, 1)) {
+		pct := who5.Percentage
+		digest.WHO5Percentage = &pct
+	}
+
+	// Condition summaries match logged_at inclusively, so end each week just before the next starts.
+	current, err := uc.conditionRepo.GetSummary(ctx, entity.ConditionFilter{From: weekStart, To: weekStart.AddDate(0, 0, 7).Add(-time.Nanosecond)})
+	if err != nil {
+		return nil, err
+	}
+	previous, err := uc.conditionRepo.GetSummary(ctx, entity.ConditionFilter{From: weekStart.AddDate(0, 0, -7), To: weekStart.Add(-time.Nanosecond)})
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.TotalCount > 0 {
+		avg := math.Round(current.OverallVASAvg*10) / 10
+		digest.ConditionAvgVAS = &avg
+	}
+	digest.ConditionTrend = conditionTrend(current, previous)
+
+	return digest, nil
+}
+
+// Send builds the digest for weekStart and POSTs it to the webhook. The
+// returned digest carries the job ID even when delivery fails.
+func (uc *WeeklyDigestUseCase) Send(ctx context.Context, weekStart time.Time) (*entity.WeeklyDigest, error) {
+	jobID := uuid.New().String()
+	week := weekStart.Format("2006-01-02")
+
+	digest, err := uc.Build(ctx, weekStart)
+	if err != nil {
+		log.Printf("digest %s: build week %s failed: %v", jobID, week, err)
+		return &entity.WeeklyDigest{JobID: jobID, WeekStart: weekStart}, err
+	}
+	digest.JobID = jobID
+
+	if err := uc.sender.Send(ctx, jobID, digest); err != nil {
+		log.Printf("digest %s: send week %s failed: %v", jobID, week, err)
+		return digest, err
+	}
+
+	log.Printf("digest %s: sent week %s", jobID, week)
+	return digest, nil
+}
+
+func conditionTrend(current, previous *entity.ConditionSummary) string {
+	if current == nil || previous == nil || current.TotalCount == 0 || previous.TotalCount == 0 {
+		return entity.ConditionTrendInsufficient
+	}
+	diff := current.OverallVASAvg - previous.OverallVASAvg
+	switch {
+	case diff >= conditionTrendThreshold:
+		return entity.ConditionTrendImproving
+	case diff <= -conditionTrendThreshold:
+		return entity.ConditionTrendDeclining
+	default:
+		return entity.ConditionTrendStable
+	}
+}
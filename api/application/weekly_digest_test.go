@@ -0,0 +1,141 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestLastCompletedWeek(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"monday", time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC), time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"wednesday", time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC), time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"sunday", time.Date(2025, 1, 19, 23, 0, 0, 0, time.UTC), time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LastCompletedWeek(tt.now); !got.Equal(tt.want) {
+				t.Errorf("LastCompletedWeek() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionTrend(t *testing.T) {
+	tests := []struct {
+		name              string
+		current, previous *entity.ConditionSummary
+		want              string
+	}{
+		{"improving", &entity.ConditionSummary{TotalCount: 3, OverallVASAvg: 70}, &entity.ConditionSummary{TotalCount: 3, OverallVASAvg: 60}, entity.ConditionTrendImproving},
+		{"declining", &entity.ConditionSummary{TotalCount: 3, OverallVASAvg: 50}, &entity.ConditionSummary{TotalCount: 3, OverallVASAvg: 60}, entity.ConditionTrendDeclining},
+		{"stable", &entity.ConditionSummary{TotalCount: 3, OverallVASAvg: 62}, &entity.ConditionSummary{TotalCount: 3, OverallVASAvg: 60}, entity.ConditionTrendStable},
+		{"no previous logs", &entity.ConditionSummary{TotalCount: 3, OverallVASAvg: 62}, &entity.ConditionSummary{}, entity.ConditionTrendInsufficient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conditionTrend(tt.current, tt.previous); got != tt.want {
+				t.Errorf("conditionTrend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func newDigestUseCase(sender *mocks.MockWebhookSender) *WeeklyDigestUseCase {
+	weekStart := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	return NewWeeklyDigestUseCase(
+		&mocks.MockMLPredictor{
+			GetWeeklyInsightsFunc: func(_ context.Context, _ time.Time) (*entity.WeeklyInsight, error) {
+				return nil, errors.New("ml unavailable")
+			},
+		},
+		&mocks.MockVRIRepository{
+			ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.VRIScore, error) {
+				return []entity.VRIScore{{VRIScore: 60}, {VRIScore: 70}}, nil
+			},
+		},
+		&mocks.MockAnomalyRepository{
+			ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.AnomalyDetection, error) {
+				return []entity.AnomalyDetection{{IsAnomaly: true}, {IsAnomaly: false}, {IsAnomaly: true}}, nil
+			},
+		},
+		&mocks.MockWHO5Repository{
+			GetLatestFunc: func(_ context.Context) (*entity.WHO5Assessment, error) {
+				return &entity.WHO5Assessment{AssessedAt: weekStart.AddDate(0, 0, 6).Add(20 * time.Hour), Percentage: 64}, nil
+			},
+		},
+		&mocks.MockConditionRepository{
+			GetSummaryFunc: func(_ context.Context, filter entity.ConditionFilter) (*entity.ConditionSummary, error) {
+				if filter.From.Equal(weekStart) {
+					return &entity.ConditionSummary{TotalCount: 5, OverallVASAvg: 72}, nil
+				}
+				return &entity.ConditionSummary{TotalCount: 4, OverallVASAvg: 60}, nil
+			},
+		},
+		sender,
+	)
+}
+
+func TestWeeklyDigestUseCase_Send(t *testing.T) {
+	weekStart := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	var sentJobID string
+	var sent *entity.WeeklyDigest
+	sender := &mocks.MockWebhookSender{
+		SendFunc: func(_ context.Context, jobID string, payload any) error {
+			sentJobID = jobID
+			sent = payload.(*entity.WeeklyDigest)
+			return nil
+		},
+	}
+
+	digest, err := newDigestUseCase(sender).Send(context.Background(), weekStart)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if sent != digest || sentJobID == "" || digest.JobID != sentJobID {
+		t.Fatalf("sent digest/job ID mismatch: job %q, digest job %q", sentJobID, digest.JobID)
+	}
+	if !digest.WeekEnd.Equal(weekStart.AddDate(0, 0, 6)) {
+		t.Errorf("WeekEnd = %v", digest.WeekEnd)
+	}
+	if digest.Insight != nil {
+		t.Errorf("Insight = %+v, want nil when ML fails", digest.Insight)
+	}
+	if digest.AvgVRIScore == nil || *digest.AvgVRIScore != 65 || digest.VRIDays != 2 {
+		t.Errorf("AvgVRIScore = %v, VRIDays = %d, want 65 over 2 days", digest.AvgVRIScore, digest.VRIDays)
+	}
+	if digest.AnomalyCount != 2 {
+		t.Errorf("AnomalyCount = %d, want 2", digest.AnomalyCount)
+	}
+	if digest.WHO5Percentage == nil || *digest.WHO5Percentage != 64 {
+		t.Errorf("WHO5Percentage = %v, want 64", digest.WHO5Percentage)
+	}
+	if digest.ConditionAvgVAS == nil || *digest.ConditionAvgVAS != 72 {
+		t.Errorf("ConditionAvgVAS = %v, want 72", digest.ConditionAvgVAS)
+	}
+	if digest.ConditionTrend != entity.ConditionTrendImproving {
+		t.Errorf("ConditionTrend = %q, want %q", digest.ConditionTrend, entity.ConditionTrendImproving)
+	}
+}
+
+func TestWeeklyDigestUseCase_Send_WebhookError(t *testing.T) {
+	sender := &mocks.MockWebhookSender{
+		SendFunc: func(_ context.Context, _ string, _ any) error { return errors.New("webhook down") },
+	}
+
+	digest, err := newDigestUseCase(sender).Send(context.Background(), time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+	if err == nil {
+		t.Fatal("Send() expected error")
+	}
+	if digest == nil || digest.JobID == "" {
+		t.Error("digest job ID missing on failure")
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -13,9 +14,13 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"vitametron/api/adapter/fitbit"
+	"vitametron/api/adapter/healthconnect"
 	"vitametron/api/adapter/mlclient"
 	"vitametron/api/adapter/postgres"
+	"vitametron/api/adapter/webhook"
 	"vitametron/api/application"
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
 	"vitametron/api/handler"
 	"vitametron/api/infrastructure/cache"
 	"vitametron/api/infrastructure/config"
@@ -23,10 +28,31 @@ import (
 	"vitametron/api/infrastructure/database"
 	"vitametron/api/infrastructure/scheduler"
 	"vitametron/api/infrastructure/server"
+	"vitametron/api/infrastructure/uploads"
 )
 
 func main() {
+	previewMigrations := flag.Bool("preview-migrations", false, "print pending database migrations and exit")
+	flag.Parse()
+
 	cfg := config.Load()
+	if err := config.Validate(cfg); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	if *previewMigrations {
+		pending, err := database.PreviewMigrations(cfg.DB.DSN())
+		if err != nil {
+			log.Fatalf("failed to preview migrations: %v", err)
+		}
+		if len(pending) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		for _, name := range pending {
+			fmt.Println(name)
+		}
+		os.Exit(0)
+	}
 
 	// Run migrations before opening the connection pool
 	if err := database.RunMigrations(cfg.DB.DSN()); err != nil {
@@ -55,62 +81,156 @@ func main() {
 	}
 
 	// Adapters
+	postgres.SetQueryTimeout(time.Duration(cfg.DB.QueryTimeoutSec) * time.Second)
+	healthconnect.SetQueryTimeout(time.Duration(cfg.Import.HealthConnectQueryTimeoutSec) * time.Second)
 	conditionRepo := postgres.NewConditionRepo(pool)
 	summaryRepo := postgres.NewDailySummaryRepo(pool)
 	hrRepo := postgres.NewHeartRateRepo(pool)
+	hrvRepo := postgres.NewHRVSampleRepo(pool)
+	azmRepo := postgres.NewActiveZoneSampleRepo(pool)
 	sleepRepo := postgres.NewSleepStageRepo(pool)
 	exerciseRepo := postgres.NewExerciseRepo(pool)
 	tokenRepo := postgres.NewTokenRepo(pool)
 	qualityRepo := postgres.NewDataQualityRepo(pool)
 	vriRepo := postgres.NewVRIRepo(pool)
-	mlClient := mlclient.New(cfg.ML.URL)
+	anomalyRepo := postgres.NewAnomalyRepo(pool)
+	mlClient, err := mlclient.New(cfg.ML.URL)
+	if err != nil {
+		log.Fatalf("failed to init ML client: %v", err)
+	}
+	mlClient.WithAnomalyModelVersion(cfg.ML.AnomalyModelVersion)
+	anomalySensitivity := cache.NewAnomalySensitivityStore(rdb)
+	if err := mlClient.Ping(context.Background()); err != nil {
+		log.Printf("warn: ML service at %s unreachable: %v", mlClient.BaseURL(), err)
+	}
 
 	// Fitbit OAuth + Client
-	fitbitOAuth := fitbit.NewFitbitOAuth(cfg.Fitbit, rdb, tokenRepo, enc)
-	fitbitClient := fitbit.NewFitbitClient(fitbitOAuth)
+	pkceStateRepo := postgres.NewPKCEStateRepo(pool)
+	fitbitOAuth := fitbit.NewFitbitOAuth(cfg.Fitbit, rdb, tokenRepo, enc).WithPKCEStateStore(pkceStateRepo)
+	fitbitClient := fitbit.NewFitbitClient(fitbitOAuth, cfg.Profile)
 
 	who5Repo := postgres.NewWHO5Repo(pool)
 
 	// Use cases
+	vo2maxUC := application.NewExerciseVO2MaxUseCase(exerciseRepo, summaryRepo, cfg.Profile.Age)
 	conditionUC := application.NewRecordConditionUseCase(conditionRepo)
 	who5UC := application.NewWHO5UseCase(who5Repo)
 	insightsUC := application.NewGetInsightsUseCase(mlClient)
-	syncUC := application.NewSyncBiometricsUseCase(fitbitClient, summaryRepo, hrRepo, sleepRepo, exerciseRepo, qualityRepo)
+	syncUC := application.NewSyncBiometricsUseCase(fitbitClient, summaryRepo, hrRepo, sleepRepo, exerciseRepo, qualityRepo).
+		WithRetry(cfg.Sync.RetryCount, time.Duration(cfg.Sync.RetryBackoffSec)*time.Second).
+		WithHRVSamples(hrvRepo).
+		WithActiveZoneSamples(azmRepo).
+		WithAlertSentStore(cache.NewAlertSentStore(rdb)).
+		WithVO2MaxEstimate(vo2maxUC)
+	if cfg.Sync.EnableFillForward {
+		syncUC.WithFillForward()
+	}
+	if cfg.Webhook.HRAlertURL != "" {
+		syncUC.WithRestingHRAlert(webhook.New(cfg.Webhook.HRAlertURL, cfg.Webhook.Secret))
+	}
+	if cfg.Webhook.SleepAlertURL != "" {
+		syncUC.WithSleepStageAlert(webhook.New(cfg.Webhook.SleepAlertURL, cfg.Webhook.Secret))
+	}
+	if cfg.Webhook.FeverAlertURL != "" {
+		syncUC.WithFeverAlert(webhook.New(cfg.Webhook.FeverAlertURL, cfg.Webhook.Secret))
+	}
 
 	// Handlers
-	conditionHandler := handler.NewConditionHandler(conditionUC)
+	adminAuth := server.APIKeyAuth(cfg.Admin.APIKey)
+	conditionHandler := handler.NewConditionHandler(conditionUC).
+		WithAPIKeyAuth(adminAuth).
+		WithAnnotations(postgres.NewConditionAnnotationRepo(pool)).
+		WithAppleHealthImport(application.NewImportAppleHealthConditionsUseCase(conditionUC))
 	who5Handler := handler.NewWHO5Handler(who5UC)
 	insightsHandler := handler.NewInsightsHandler(insightsUC)
-	biometricsHandler := handler.NewBiometricsHandler(summaryRepo, hrRepo, sleepRepo, qualityRepo)
-	oauthHandler := handler.NewOAuthHandler(fitbitOAuth, syncUC)
-	syncHandler := handler.NewSyncHandler(syncUC)
-	importUC := application.NewImportHealthConnectUseCase(summaryRepo, hrRepo, sleepRepo, exerciseRepo)
-	importHandler := handler.NewImportHandler(importUC, rdb, cfg.Preprocessor.UploadDir)
-	anomalyRepo := postgres.NewAnomalyRepo(pool)
+	biometricsHandler := handler.NewBiometricsHandler(summaryRepo, hrRepo, sleepRepo, qualityRepo).
+		WithHRVSamples(hrvRepo).
+		WithActiveZones(azmRepo).
+		WithTokenWarning(fitbitOAuth).
+		WithFreshnessCheck(time.Duration(cfg.Health.DataFreshnessHours) * time.Hour).
+		WithSimilarDays(application.NewSimilarDaysUseCase(summaryRepo))
+	normalRangesUC := application.NewNormalRangesUseCase(summaryRepo, qualityRepo)
+	normalRangesHandler := handler.NewNormalRangesHandler(normalRangesUC, rdb)
+	exerciseRouteRepo := postgres.NewExerciseRouteRepo(pool)
+	exerciseHandler := handler.NewExerciseHandler(exerciseRepo).
+		WithVO2MaxEstimator(vo2maxUC).
+		WithRoutes(exerciseRouteRepo)
+	activityCalc := application.NewActivityEquivalentCalculator(summaryRepo, cfg.Profile.WeightKG)
+	hydrationAnalyzer := application.NewHydrationAnalyzer(summaryRepo)
+	conditionSources := application.NewConditionSourceAnalyzer(conditionRepo)
+	vasHistogram := application.NewVASHistogramAnalyzer(conditionRepo)
+	breathingRate := application.NewBreathingRateAnalyzer(summaryRepo)
+	predictiveCorrelation := application.NewPredictiveCorrelationAnalyzer(conditionRepo, summaryRepo)
+	jointAlerts := application.NewJointAlertAnalyzer(vriRepo, anomalyRepo)
+	analyticsHandler := handler.NewAnalyticsHandler(activityCalc, hydrationAnalyzer, conditionSources, vasHistogram, breathingRate, predictiveCorrelation, jointAlerts)
+	oauthHandler := handler.NewOAuthHandler(fitbitOAuth, syncUC).WithTokenHealth(fitbitOAuth)
+	fitbitStatsHandler := handler.NewFitbitStatsHandler(cache.NewFitbitStatsCache(fitbitClient, rdb))
+	fitbitSyncQueue := cache.NewFitbitSyncQueue(rdb)
+	fitbitSubscriptionHandler := handler.NewFitbitSubscriptionHandler(fitbitClient, fitbitSyncQueue, adminAuth).
+		WithVerificationCode(cfg.Fitbit.SubscriberVerifyCode)
+	syncStatus := cache.NewSyncStatusStore(rdb)
+	syncHandler := handler.NewSyncHandler(syncUC).
+		WithProviderStatus(syncStatus, map[string]port.OAuthProvider{fitbitClient.ProviderName(): fitbitOAuth}).
+		WithTrigger(syncUC, adminAuth)
+	importUC := application.NewImportHealthConnectUseCase(summaryRepo, hrRepo, sleepRepo, exerciseRepo).
+		WithSkipIfFitbit(cfg.Import.HealthConnectSkipIfFitbit).
+		WithHistory(postgres.NewImportHistoryRepo(pool)).
+		WithRoutes(exerciseRouteRepo).
+		WithVO2MaxEstimate(vo2maxUC)
+	importHandler := handler.NewImportHandler(importUC, rdb, cfg.Preprocessor.UploadDir).WithAPIKeyAuth(adminAuth)
 	divergenceRepo := postgres.NewDivergenceRepo(pool)
 	adviceRepo := postgres.NewAdviceRepo(pool)
 	circadianRepo := postgres.NewCircadianRepo(pool)
-	vriHandler := handler.NewVRIHandler(mlClient, vriRepo)
-	anomalyHandler := handler.NewAnomalyHandler(mlClient, anomalyRepo)
-	divergenceHandler := handler.NewDivergenceHandler(mlClient, divergenceRepo)
+	vriHandler := handler.NewVRIHandler(mlClient, vriRepo).
+		WithTimeout(time.Duration(cfg.Server.VRIRequestTimeoutSec) * time.Second)
+	anomalyHandler := handler.NewAnomalyHandler(mlClient, anomalyRepo).
+		WithModelMetadata(postgres.NewModelMetadataRepo(pool), adminAuth).
+		WithSensitivity(anomalySensitivity, adminAuth).
+		WithTimeout(time.Duration(cfg.Server.AnomalyRequestTimeoutSec) * time.Second)
+	divergenceHandler := handler.NewDivergenceHandler(mlClient, divergenceRepo).
+		WithTimeout(time.Duration(cfg.Server.DivergenceRequestTimeoutSec) * time.Second)
 	hrvHandler := handler.NewHRVHandler(mlClient)
+	sleepPredictionHandler := handler.NewSleepPredictionHandler(mlClient, rdb)
 	weeklyInsightsHandler := handler.NewWeeklyInsightsHandler(mlClient)
-	adviceHandler := handler.NewAdviceHandler(mlClient, adviceRepo)
+	adviceHandler := handler.NewAdviceHandler(mlClient, adviceRepo).
+		WithRegenerateLimit(rdb, cfg.ML.MaxDailyAdviceRegenerations)
 	healthkitHandler := handler.NewHealthKitHandler(rdb, cfg.Preprocessor.URL, cfg.Preprocessor.UploadDir)
 	circadianHandler := handler.NewCircadianHandler(mlClient, circadianRepo)
 	retrainHandler := handler.NewRetrainHandler(mlClient)
+	digestUC := application.NewWeeklyDigestUseCase(mlClient, vriRepo, anomalyRepo, who5Repo, conditionRepo,
+		webhook.New(cfg.Webhook.DigestURL, cfg.Webhook.Secret))
+	digestHandler := handler.NewDigestHandler(digestUC, adminAuth)
 
 	// Scheduler
+	schedulerConfig := cache.NewSchedulerConfigStore(rdb)
 	interval := cfg.Sync.IntervalMin
-	if interval < 5 {
-		interval = 5
+	if stored, ok, err := schedulerConfig.IntervalMin(ctx); err != nil {
+		log.Printf("warn: read stored sync interval: %v", err)
+	} else if ok && stored <= entity.MaxSyncIntervalMin {
+		interval = stored
+	}
+	if interval < entity.MinSyncIntervalMin {
+		interval = entity.MinSyncIntervalMin
 	}
 	sched := scheduler.New(syncUC, fitbitOAuth, time.Duration(interval)*time.Minute)
+	sched.WithUploadCleanup(uploads.NewCleaner(cfg.Preprocessor.UploadDir, rdb)).
+		WithPKCEStateCleanup(pkceStateRepo).
+		WithSyncStatus(syncStatus, fitbitClient.ProviderName()).
+		WithSyncQueue(fitbitSyncQueue, 30*time.Second)
+	if cfg.Webhook.DigestURL != "" {
+		sched.WithWeeklyDigest(digestUC)
+	}
+	if cfg.Webhook.VRIAlertURL != "" {
+		sched.WithVRIAlertDigest(application.NewVRIAlertDigestUseCase(vriRepo,
+			webhook.New(cfg.Webhook.VRIAlertURL, cfg.Webhook.Secret), cfg.VRI.AlertThreshold))
+	}
+	syncHandler.WithSchedulerConfig(sched, schedulerConfig, adminAuth)
 	sched.Start()
 	log.Printf("sync scheduler started: every %d minutes", interval)
 
 	// Server
 	srv := server.New()
+	srv.HealthMaxLatency = time.Duration(cfg.Health.MaxLatencyMs) * time.Millisecond
 
 	// Health checks
 	srv.RegisterHealthRoutes(&pgxPinger{pool}, &redisPinger{rdb})
@@ -121,19 +241,30 @@ func main() {
 	who5Handler.Register(api)
 	insightsHandler.Register(api)
 	biometricsHandler.Register(api)
+	normalRangesHandler.Register(api)
+	exerciseHandler.Register(api)
+	analyticsHandler.Register(api)
+	digestHandler.Register(api)
 	oauthHandler.Register(api)
+	fitbitStatsHandler.Register(api)
+	fitbitSubscriptionHandler.Register(api)
 	syncHandler.Register(api)
 	importHandler.Register(api)
 	vriHandler.Register(api)
 	anomalyHandler.Register(api)
 	divergenceHandler.Register(api)
 	hrvHandler.Register(api)
+	sleepPredictionHandler.Register(api)
 	weeklyInsightsHandler.Register(api)
 	adviceHandler.Register(api)
 	healthkitHandler.Register(api)
 	circadianHandler.Register(api)
 	retrainHandler.Register(api)
 
+	// Admin routes (API key required)
+	admin := api.Group("/admin", adminAuth)
+	conditionHandler.RegisterAdmin(admin)
+
 	// Graceful shutdown
 	go func() {
 		if err := srv.Echo.Start(fmt.Sprintf(":%d", cfg.Server.Port)); err != nil {
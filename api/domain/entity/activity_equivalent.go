@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// ActivityEquivalent estimates the activity a step count represents.
+// Date is set only for per-day results from a range.
+type ActivityEquivalent struct {
+	Date                   time.Time `json:"date,omitzero"`
+	Steps                  int       `json:"steps"`
+	ActiveMinutesEstimated int       `json:"active_minutes_estimated"`
+	CaloriesEstimated      int       `json:"calories_estimated"`
+	DistanceKMEstimated    float32   `json:"distance_km_estimated"`
+}
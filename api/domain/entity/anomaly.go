@@ -28,8 +28,88 @@ type AnomalyTrainResult struct {
 	Message          string   `json:"Message"`
 }
 
+// Contamination bounds accepted by the ML service's anomaly trainer, and the
+// value it uses when none is given.
+const (
+	DefaultAnomalyContamination = 0.02
+	MinAnomalyContamination     = 0.001
+	MaxAnomalyContamination     = 0.1
+)
+
+// Anomaly detection sensitivities a user can choose. Each maps to the
+// contamination rate the anomaly model is trained with; see
+// AnomalySensitivityRate.
+const (
+	AnomalySensitivityLow    = "low"
+	AnomalySensitivityNormal = "normal"
+	AnomalySensitivityHigh   = "high"
+)
+
+var anomalySensitivityRates = map[string]float64{
+	AnomalySensitivityLow:    0.02,
+	AnomalySensitivityNormal: 0.05,
+	AnomalySensitivityHigh:   0.10,
+}
+
+// AnomalySensitivityRate returns the contamination rate of sensitivity s; ok
+// is false for an unknown sensitivity.
+func AnomalySensitivityRate(s string) (rate float64, ok bool) {
+	rate, ok = anomalySensitivityRates[s]
+	return rate, ok
+}
+
+// AnomalySensitivityConfig is the anomaly sensitivity in effect and its
+// contamination rate.
+type AnomalySensitivityConfig struct {
+	Sensitivity string  `json:"sensitivity"`
+	Rate        float64 `json:"rate"`
+}
+
+// AnomalySensitivityUpdate reports a sensitivity change: the model version
+// trained with its rate, and how many stored detections from other model
+// versions were dropped so they are recomputed.
+type AnomalySensitivityUpdate struct {
+	AnomalySensitivityConfig
+	ModelVersion      string `json:"model_version"`
+	ClearedDetections int64  `json:"cleared_detections"`
+}
+
+// AnomalyTrainRun is one recorded anomaly model training run.
+type AnomalyTrainRun struct {
+	ID        int64     `json:"ID"`
+	TrainedAt time.Time `json:"TrainedAt"`
+	AnomalyTrainResult
+}
+
+// AnomalyModelConfig is the contamination the current anomaly model was
+// trained with. ModelVersion is empty and TrainedAt nil before any recorded run.
+type AnomalyModelConfig struct {
+	Contamination float64    `json:"contamination"`
+	ModelVersion  string     `json:"model_version"`
+	TrainedAt     *time.Time `json:"trained_at"`
+}
+
 type AnomalyModelStatus struct {
 	IsReady      bool     `json:"IsReady"`
 	ModelVersion string   `json:"ModelVersion"`
 	FeatureNames []string `json:"FeatureNames"`
 }
+
+// AnomalyDriver is one feature's SHAP contribution to an anomaly score.
+// Contribution is its share of the total absolute SHAP value of the drivers.
+type AnomalyDriver struct {
+	Feature      string  `json:"feature"`
+	ShapValue    float32 `json:"shap_value"`
+	Direction    string  `json:"direction"`
+	Contribution float32 `json:"contribution"`
+	Description  string  `json:"description"`
+}
+
+// AnomalyWaterfall lays the drivers out as a waterfall chart from Baseline
+// to Output (the anomaly score). Only the top drivers are stored, so the
+// remaining features' SHAP values are folded into Baseline.
+type AnomalyWaterfall struct {
+	Baseline float32         `json:"baseline"`
+	Output   float32         `json:"output"`
+	Steps    []AnomalyDriver `json:"steps"`
+}
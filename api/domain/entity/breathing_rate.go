@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// BreathingRateDay holds one night's breathing rates (breaths/min) by sleep
+// stage. Disturbance marks a BRFullSleep above the range's 90th percentile.
+type BreathingRateDay struct {
+	Date        string   `json:"date"`
+	FullSleep   *float32 `json:"full_sleep"`
+	DeepSleep   *float32 `json:"deep_sleep"`
+	LightSleep  *float32 `json:"light_sleep"`
+	REMSleep    *float32 `json:"rem_sleep"`
+	Disturbance bool     `json:"disturbance"`
+}
+
+// BreathingRateStageCorrelation relates a stage's breathing rate to the
+// minutes spent in that stage. Correlation is nil below MinPairs nights.
+type BreathingRateStageCorrelation struct {
+	Stage       string   `json:"stage"`
+	Pairs       int      `json:"pairs"`
+	Correlation *float64 `json:"correlation"`
+}
+
+// BreathingRateAnalysis combines the four breathing rate series with their
+// sleep stage correlations. DisturbanceThreshold is nil when too few nights
+// have a full-sleep rate to estimate the 90th percentile.
+type BreathingRateAnalysis struct {
+	From                 time.Time                       `json:"from"`
+	To                   time.Time                       `json:"to"`
+	Days                 []BreathingRateDay              `json:"days"`
+	Correlations         []BreathingRateStageCorrelation `json:"correlations"`
+	DisturbanceThreshold *float64                        `json:"disturbance_threshold"`
+	DisturbanceDates     []string                        `json:"disturbance_dates"`
+	MinPairs             int                             `json:"min_pairs"`
+}
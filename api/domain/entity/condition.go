@@ -3,6 +3,7 @@ package entity
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -22,7 +23,33 @@ type ConditionLog struct {
 	StressVAS       *int // optional
 	Note            string
 	Tags            []string
+	Source          string // why the log was recorded; see ConditionSource*
+	IsOutlier       bool   // OverallVAS was over 3 SD from the recent mean when recorded
 	CreatedAt       time.Time
+	DeletedAt       *time.Time // set while the log is soft-deleted
+}
+
+// Condition log sources, used to assess reporting bias in self-reports.
+const (
+	ConditionSourceSpontaneous = "spontaneous"
+	ConditionSourceReminder    = "reminder"
+	ConditionSourceScheduled   = "scheduled"
+)
+
+// ConditionSources lists every valid ConditionLog.Source.
+var ConditionSources = []string{ConditionSourceSpontaneous, ConditionSourceReminder, ConditionSourceScheduled}
+
+type ConditionSourceCount struct {
+	Source string `json:"source"`
+	Count  int    `json:"count"`
+}
+
+// ConditionSourceBreakdown counts condition logs per source over a window.
+type ConditionSourceBreakdown struct {
+	From    time.Time              `json:"from"`
+	To      time.Time              `json:"to"`
+	Total   int                    `json:"total"`
+	Sources []ConditionSourceCount `json:"sources"`
 }
 
 type TagCount struct {
@@ -38,8 +65,36 @@ type ConditionFilter struct {
 	Offset    int
 	SortField string
 	SortDir   string
+	// Tags matches logs carrying all (TagOperatorAnd, the default) or any
+	// (TagOperatorOr) of the given tags. Tag, if also set, is ANDed on top.
+	Tags        []string
+	TagOperator string
+	// Archived queries condition_logs_archive instead of the live table.
+	Archived bool
+	// IncludeDeleted also lists soft-deleted logs.
+	IncludeDeleted bool
+	// CountOnly returns just Total, skipping the row fetch.
+	CountOnly bool
+	// ExcludeOutliers skips logs flagged IsOutlier.
+	ExcludeOutliers bool
+	// WeightingMode controls how GetSummary averages days with several logs
+	// (see Weighting*); empty means WeightingUniform.
+	WeightingMode string
 }
 
+const (
+	TagOperatorAnd = "and"
+	TagOperatorOr  = "or"
+)
+
+// Summary weighting modes. WeightingTimeWeighted gives the k-th of n logs in
+// a JST day the weight 2k/(n+1), so later entries count more while each day
+// keeps the same total weight as with WeightingUniform.
+const (
+	WeightingUniform      = "uniform"
+	WeightingTimeWeighted = "time_weighted"
+)
+
 type ConditionListResult struct {
 	Items []ConditionLog `json:"items"`
 	Total int            `json:"total"`
@@ -74,6 +129,67 @@ type ConditionSummary struct {
 	StressVASAvg       float64 `json:"stress_vas_avg"`
 	StressVASMin       int     `json:"stress_vas_min"`
 	StressVASMax       int     `json:"stress_vas_max"`
+	// TrendDirection compares mean overall VAS between the two halves of the
+	// range (see ConditionTrend*); TrendSlope is that change per day.
+	TrendDirection string  `json:"trend_direction"`
+	TrendSlope     float64 `json:"trend_slope"`
+}
+
+// HeatmapDay is one calendar day of overall VAS for heatmap views. Value is
+// the mean of the day's logs and nil when HasData is false.
+type HeatmapDay struct {
+	Date    string   `json:"date"`
+	Value   *float32 `json:"value"`
+	HasData bool     `json:"has_data"`
+}
+
+// ConditionWeekly aggregates one ISO week (Monday to Sunday, JST) of condition
+// logs. A week without logs has LogCount 0 and zero overall VAS statistics;
+// the optional VAS statistics are nil when no log in the week recorded them.
+type ConditionWeekly struct {
+	WeekStart           string   `json:"week_start"`
+	WeekEnd             string   `json:"week_end"`
+	LogCount            int      `json:"log_count"`
+	OverallVASMean      float64  `json:"overall_vas_mean"`
+	OverallVASMin       float64  `json:"overall_vas_min"`
+	OverallVASMax       float64  `json:"overall_vas_max"`
+	MoodVASMean         *float64 `json:"mood_vas_mean"`
+	MoodVASMin          *float64 `json:"mood_vas_min"`
+	MoodVASMax          *float64 `json:"mood_vas_max"`
+	EnergyVASMean       *float64 `json:"energy_vas_mean"`
+	EnergyVASMin        *float64 `json:"energy_vas_min"`
+	EnergyVASMax        *float64 `json:"energy_vas_max"`
+	SleepQualityVASMean *float64 `json:"sleep_quality_vas_mean"`
+	SleepQualityVASMin  *float64 `json:"sleep_quality_vas_min"`
+	SleepQualityVASMax  *float64 `json:"sleep_quality_vas_max"`
+	StressVASMean       *float64 `json:"stress_vas_mean"`
+	StressVASMin        *float64 `json:"stress_vas_min"`
+	StressVASMax        *float64 `json:"stress_vas_max"`
+}
+
+// ConditionLogHistory is one edit of a condition log, holding the log as it
+// was before and after the update.
+type ConditionLogHistory struct {
+	ID             int64        `json:"id"`
+	ConditionLogID int64        `json:"condition_log_id"`
+	ChangedAt      time.Time    `json:"changed_at"`
+	OldValues      ConditionLog `json:"old_values"`
+	NewValues      ConditionLog `json:"new_values"`
+}
+
+// HistogramBucket counts values in [BucketStart, BucketEnd], both inclusive.
+// Frequency is Count as a fraction of all values.
+type HistogramBucket struct {
+	BucketStart int     `json:"bucket_start"`
+	BucketEnd   int     `json:"bucket_end"`
+	Count       int     `json:"count"`
+	Frequency   float32 `json:"frequency"`
+}
+
+// VASPoint is one condition log's overall VAS, for trend computation.
+type VASPoint struct {
+	LoggedAt   time.Time
+	OverallVAS int
 }
 
 func (c *ConditionLog) Validate() error {
@@ -119,6 +235,9 @@ func (c *ConditionLog) Validate() error {
 			return fmt.Errorf("tag must be 50 characters or less, got %q", tag)
 		}
 	}
+	if c.Source != "" && !slices.Contains(ConditionSources, c.Source) {
+		return fmt.Errorf("source must be one of %v, got %q", ConditionSources, c.Source)
+	}
 	return nil
 }
 
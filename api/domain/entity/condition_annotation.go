@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxAnnotationLabelLen caps annotation labels; they are short study tags,
+// not free text.
+const MaxAnnotationLabelLen = 64
+
+// ConditionAnnotation labels a condition log for study analysis, e.g.
+// "pre-intervention" or "on-medication".
+type ConditionAnnotation struct {
+	ID             int64     `json:"id"`
+	ConditionLogID int64     `json:"condition_log_id"`
+	Label          string    `json:"label"`
+	Note           string    `json:"note,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Validate trims the label and checks it is present and within
+// MaxAnnotationLabelLen.
+func (a *ConditionAnnotation) Validate() error {
+	a.Label = strings.TrimSpace(a.Label)
+	if a.Label == "" {
+		return fmt.Errorf("label is required")
+	}
+	if n := len([]rune(a.Label)); n > MaxAnnotationLabelLen {
+		return fmt.Errorf("label must be at most %d characters, got %d", MaxAnnotationLabelLen, n)
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ func TestConditionLog_Validate_OK(t *testing.T) {
 		{"vas 0", ConditionLog{OverallVAS: 0, LoggedAt: time.Now()}},
 		{"vas 50", ConditionLog{OverallVAS: 50, LoggedAt: time.Now()}},
 		{"vas 100", ConditionLog{OverallVAS: 100, LoggedAt: time.Now()}},
+		{"reminder source", ConditionLog{OverallVAS: 50, Source: ConditionSourceReminder, LoggedAt: time.Now()}},
 		{"vas with all dimensions", ConditionLog{
 			OverallVAS:      75,
 			MoodVAS:         intPtr(60),
@@ -74,6 +75,7 @@ func TestConditionLog_Validate_Error(t *testing.T) {
 		{"note too long", ConditionLog{OverallVAS: 50, Note: string(longNote)}},
 		{"too many tags", ConditionLog{OverallVAS: 50, Tags: manyTags}},
 		{"tag too long", ConditionLog{OverallVAS: 50, Tags: []string{string(longTag)}}},
+		{"unknown source", ConditionLog{OverallVAS: 50, Source: "push"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -3,8 +3,10 @@ package entity
 import "time"
 
 type DailySummary struct {
-	Date     time.Time
-	Provider string
+	Date time.Time
+	// Providers lists every source that contributed to this day, in the order
+	// they first wrote it. Single-source days hold one element.
+	Providers []string
 
 	// Heart rate
 	RestingHR int
@@ -28,6 +30,8 @@ type DailySummary struct {
 
 	// Skin temperature
 	SkinTempVariation *float32
+	// FeverCandidate is set when SkinTempVariation exceeds the 30-day mean + 2 SD.
+	FeverCandidate bool
 
 	// Sleep
 	SleepStart        *time.Time
@@ -56,6 +60,10 @@ type DailySummary struct {
 	MinutesFairly    int
 	MinutesVery      int
 
+	// ActivityIntensityScore weights lightly, fairly and very active minutes
+	// by 1, 2 and 3 over all tracked minutes, times 100 (0-300).
+	ActivityIntensityScore float32
+
 	// VO2 Max
 	VO2Max *float32
 
@@ -65,9 +73,68 @@ type DailySummary struct {
 	HRZoneCardioMin int
 	HRZonePeakMin   int
 
+	// Hydration (mL)
+	WaterIntakeMl int
+
+	// FilledForwardFields names the metrics copied from the previous day
+	// because this day's reading was missing.
+	FilledForwardFields []string
+
 	SyncedAt time.Time
 }
 
+// ComputeActivityIntensityScore sets ActivityIntensityScore from the
+// activity minutes. A day without tracked minutes scores 0.
+func (s *DailySummary) ComputeActivityIntensityScore() {
+	total := s.MinutesSedentary + s.MinutesLightly + s.MinutesFairly + s.MinutesVery
+	if total <= 0 {
+		s.ActivityIntensityScore = 0
+		return
+	}
+	weighted := s.MinutesLightly + s.MinutesFairly*2 + s.MinutesVery*3
+	s.ActivityIntensityScore = float32(weighted) / float32(total) * 100
+}
+
+// IsFilledForward reports whether field holds a value copied from the
+// previous day rather than a measurement.
+func (s *DailySummary) IsFilledForward(field string) bool {
+	for _, f := range s.FilledForwardFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// HasProvider reports whether name contributed to the summary.
+func (s *DailySummary) HasProvider(name string) bool {
+	for _, p := range s.Providers {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PrimaryProvider returns the first contributing source, or "" if none.
+func (s *DailySummary) PrimaryProvider() string {
+	if len(s.Providers) == 0 {
+		return ""
+	}
+	return s.Providers[0]
+}
+
+// DailySummaryRangeResult is a page of daily summaries. Total counts every
+// stored day in the requested range; HasMore is set when Items was cut short
+// by the range cap.
+type DailySummaryRangeResult struct {
+	Items   []DailySummary `json:"items"`
+	Total   int            `json:"total"`
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	HasMore bool           `json:"has_more"`
+}
+
 // Float32Ptr returns a pointer to v, or nil if v is zero (sentinel for missing data).
 func Float32Ptr(v float32) *float32 {
 	if v == 0 {
@@ -75,3 +142,84 @@ func Float32Ptr(v float32) *float32 {
 	}
 	return &v
 }
+
+// SummaryMetric exposes one numeric DailySummary field by name. Value reports
+// false when the field is missing (nil pointer or zero sentinel).
+type SummaryMetric struct {
+	Name  string
+	Value func(s *DailySummary) (float64, bool)
+}
+
+func intMetric(name string, get func(s *DailySummary) int) SummaryMetric {
+	return SummaryMetric{Name: name, Value: func(s *DailySummary) (float64, bool) {
+		v := get(s)
+		return float64(v), v != 0
+	}}
+}
+
+func float32Metric(name string, get func(s *DailySummary) float32) SummaryMetric {
+	return SummaryMetric{Name: name, Value: func(s *DailySummary) (float64, bool) {
+		v := get(s)
+		return float64(v), v != 0
+	}}
+}
+
+func optionalMetric(name string, get func(s *DailySummary) *float32) SummaryMetric {
+	return SummaryMetric{Name: name, Value: func(s *DailySummary) (float64, bool) {
+		v := get(s)
+		if v == nil {
+			return 0, false
+		}
+		return float64(*v), true
+	}}
+}
+
+// SummaryMetrics lists every numeric DailySummary field, keyed by its column name.
+var SummaryMetrics = []SummaryMetric{
+	intMetric("resting_hr", func(s *DailySummary) int { return s.RestingHR }),
+	float32Metric("avg_hr", func(s *DailySummary) float32 { return s.AvgHR }),
+	intMetric("max_hr", func(s *DailySummary) int { return s.MaxHR }),
+	optionalMetric("hrv_daily_rmssd", func(s *DailySummary) *float32 { return s.HRVDailyRMSSD }),
+	optionalMetric("hrv_deep_rmssd", func(s *DailySummary) *float32 { return s.HRVDeepRMSSD }),
+	optionalMetric("spo2_avg", func(s *DailySummary) *float32 { return s.SpO2Avg }),
+	optionalMetric("spo2_min", func(s *DailySummary) *float32 { return s.SpO2Min }),
+	optionalMetric("spo2_max", func(s *DailySummary) *float32 { return s.SpO2Max }),
+	optionalMetric("br_full_sleep", func(s *DailySummary) *float32 { return s.BRFullSleep }),
+	optionalMetric("br_deep_sleep", func(s *DailySummary) *float32 { return s.BRDeepSleep }),
+	optionalMetric("br_light_sleep", func(s *DailySummary) *float32 { return s.BRLightSleep }),
+	optionalMetric("br_rem_sleep", func(s *DailySummary) *float32 { return s.BRREMSleep }),
+	optionalMetric("skin_temp_variation", func(s *DailySummary) *float32 { return s.SkinTempVariation }),
+	intMetric("sleep_duration_min", func(s *DailySummary) int { return s.SleepDurationMin }),
+	intMetric("sleep_minutes_asleep", func(s *DailySummary) int { return s.SleepMinutesAsleep }),
+	intMetric("sleep_minutes_awake", func(s *DailySummary) int { return s.SleepMinutesAwake }),
+	intMetric("sleep_onset_latency", func(s *DailySummary) int { return s.SleepOnsetLatency }),
+	intMetric("sleep_deep_min", func(s *DailySummary) int { return s.SleepDeepMin }),
+	intMetric("sleep_light_min", func(s *DailySummary) int { return s.SleepLightMin }),
+	intMetric("sleep_rem_min", func(s *DailySummary) int { return s.SleepREMMin }),
+	intMetric("sleep_wake_min", func(s *DailySummary) int { return s.SleepWakeMin }),
+	intMetric("steps", func(s *DailySummary) int { return s.Steps }),
+	float32Metric("distance_km", func(s *DailySummary) float32 { return s.DistanceKM }),
+	intMetric("floors", func(s *DailySummary) int { return s.Floors }),
+	intMetric("calories_total", func(s *DailySummary) int { return s.CaloriesTotal }),
+	intMetric("calories_active", func(s *DailySummary) int { return s.CaloriesActive }),
+	intMetric("calories_bmr", func(s *DailySummary) int { return s.CaloriesBMR }),
+	intMetric("active_zone_min", func(s *DailySummary) int { return s.ActiveZoneMin }),
+	intMetric("minutes_sedentary", func(s *DailySummary) int { return s.MinutesSedentary }),
+	intMetric("minutes_lightly", func(s *DailySummary) int { return s.MinutesLightly }),
+	intMetric("minutes_fairly", func(s *DailySummary) int { return s.MinutesFairly }),
+	intMetric("minutes_very", func(s *DailySummary) int { return s.MinutesVery }),
+	optionalMetric("vo2_max", func(s *DailySummary) *float32 { return s.VO2Max }),
+	intMetric("hr_zone_out_min", func(s *DailySummary) int { return s.HRZoneOutMin }),
+	intMetric("hr_zone_fat_min", func(s *DailySummary) int { return s.HRZoneFatMin }),
+	intMetric("hr_zone_cardio_min", func(s *DailySummary) int { return s.HRZoneCardioMin }),
+	intMetric("hr_zone_peak_min", func(s *DailySummary) int { return s.HRZonePeakMin }),
+	intMetric("water_intake_ml", func(s *DailySummary) int { return s.WaterIntakeMl }),
+}
+
+// FeverAlert reports a day whose skin temperature variation exceeds the
+// preceding 30-day mean + 2 SD.
+type FeverAlert struct {
+	Date              string  `json:"date"`
+	SkinTempVariation float32 `json:"skin_temp_variation"`
+	Threshold         float32 `json:"threshold"`
+}
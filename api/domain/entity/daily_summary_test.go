@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"math"
 	"testing"
 	"time"
 )
@@ -9,7 +10,7 @@ func TestDailySummary_FieldsExist(t *testing.T) {
 	now := time.Now()
 	ds := DailySummary{
 		Date:               now,
-		Provider:           "fitbit",
+		Providers:          []string{"fitbit"},
 		RestingHR:          60,
 		AvgHR:              72.5,
 		MaxHR:              180,
@@ -54,8 +55,8 @@ func TestDailySummary_FieldsExist(t *testing.T) {
 		SyncedAt:           now,
 	}
 
-	if ds.Provider != "fitbit" {
-		t.Errorf("Provider = %q, want %q", ds.Provider, "fitbit")
+	if ds.PrimaryProvider() != "fitbit" {
+		t.Errorf("PrimaryProvider() = %q, want %q", ds.PrimaryProvider(), "fitbit")
 	}
 	if ds.RestingHR != 60 {
 		t.Errorf("RestingHR = %d, want 60", ds.RestingHR)
@@ -64,3 +65,48 @@ func TestDailySummary_FieldsExist(t *testing.T) {
 		t.Errorf("Steps = %d, want 10000", ds.Steps)
 	}
 }
+
+func TestDailySummary_Providers(t *testing.T) {
+	merged := DailySummary{Providers: []string{"fitbit", "health_connect"}}
+	if !merged.HasProvider("fitbit") || !merged.HasProvider("health_connect") {
+		t.Errorf("HasProvider() false for listed provider in %v", merged.Providers)
+	}
+	if merged.HasProvider("apple_watch") {
+		t.Error("HasProvider(apple_watch) = true, want false")
+	}
+	if got := merged.PrimaryProvider(); got != "fitbit" {
+		t.Errorf("PrimaryProvider() = %q, want fitbit", got)
+	}
+
+	var empty DailySummary
+	if empty.HasProvider("fitbit") || empty.PrimaryProvider() != "" {
+		t.Error("empty Providers should report no provider")
+	}
+}
+
+func TestDailySummary_ComputeActivityIntensityScore(t *testing.T) {
+	tests := []struct {
+		name                                string
+		sedentary, lightly, fairly, veryMin int
+		want                                float32
+	}{
+		{"mixed day", 600, 200, 60, 40, 48.888889}, // (200 + 120 + 120) / 900 * 100
+		{"all very active", 0, 0, 0, 30, 300},
+		{"all sedentary", 720, 0, 0, 0, 0},
+		{"no tracked minutes", 0, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := DailySummary{
+				MinutesSedentary: tt.sedentary,
+				MinutesLightly:   tt.lightly,
+				MinutesFairly:    tt.fairly,
+				MinutesVery:      tt.veryMin,
+			}
+			s.ComputeActivityIntensityScore()
+			if math.Abs(float64(s.ActivityIntensityScore-tt.want)) > 1e-4 {
+				t.Errorf("ActivityIntensityScore = %v, want ~%v", s.ActivityIntensityScore, tt.want)
+			}
+		})
+	}
+}
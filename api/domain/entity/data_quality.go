@@ -2,6 +2,10 @@ package entity
 
 import "time"
 
+// SpO2AlertThreshold is the overnight SpO2 minimum (%) below which a day is
+// flagged as clinically significant.
+const SpO2AlertThreshold = 88.0
+
 type DataQuality struct {
 	Date              time.Time
 	WearTimeHours     float32
@@ -15,6 +19,52 @@ type DataQuality struct {
 	BaselineDays      int
 	BaselineMaturity  string // "cold" | "warming" | "mature"
 	ConfidenceScore   float32
-	ConfidenceLevel   string // "low" | "medium" | "high"
-	ComputedAt        time.Time
+	ConfidenceLevel   string  // "low" | "medium" | "high"
+	LowestSpO2        float32 // 0 when no SpO2 was recorded
+	SpO2MinAlert      bool
+	// SleepStageConfidence rates how trustworthy the night's staging is:
+	// 1.0 full "stages" night, 0.5 short "stages", 0.2 "classic", 0 none.
+	SleepStageConfidence float32
+	// SleepStageQualityPct is the percentage (0-100) of the night's deep and
+	// REM time spent in episodes with a physiologically plausible length; 0
+	// without staging.
+	SleepStageQualityPct float32
+	ComputedAt           time.Time
+}
+
+// DataQualitySummary aggregates data quality over a date range for
+// monitoring. MetricCoverage maps each metric to the fraction (0-1) of days
+// in the range on which it was present.
+type DataQualitySummary struct {
+	From                  time.Time          `json:"from"`
+	To                    time.Time          `json:"to"`
+	TotalDays             int                `json:"total_days"`
+	DaysWithData          int                `json:"days_with_data"`
+	ValidDays             int                `json:"valid_days"`
+	AvgConfidence         float32            `json:"avg_confidence"`
+	MetricCoverage        map[string]float32 `json:"metric_coverage"`
+	MostCommonMissing     string             `json:"most_common_missing"`
+	BaselineMaturityStart string             `json:"baseline_maturity_start"`
+	BaselineMaturityEnd   string             `json:"baseline_maturity_end"`
+	BaselineDaysChange    int                `json:"baseline_days_change"`
+}
+
+// DataQualityTrend lays daily data quality over a date range out as parallel
+// series, one entry per calendar day in Dates. A day without a record is nil
+// in every series. The *7d series are trailing 7-day means of the days that
+// have a value, nil when none of them does. CompletenessPct and
+// ConfidenceScore are 0-1 like DataQuality.
+type DataQualityTrend struct {
+	From              time.Time  `json:"from"`
+	To                time.Time  `json:"to"`
+	Dates             []string   `json:"dates"`
+	WearTimeHours     []*float32 `json:"wear_time_hours"`
+	CompletenessPct   []*float32 `json:"completeness_pct"`
+	ConfidenceScore   []*float32 `json:"confidence_score"`
+	WearTimeHours7d   []*float32 `json:"wear_time_hours_7d"`
+	CompletenessPct7d []*float32 `json:"completeness_pct_7d"`
+	ConfidenceScore7d []*float32 `json:"confidence_score_7d"`
+	ValidDayCount     int        `json:"valid_day_count"`
+	TotalDays         int        `json:"total_days"`
+	ValidRatePct      float32    `json:"valid_rate_pct"`
 }
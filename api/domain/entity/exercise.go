@@ -6,14 +6,66 @@ import (
 )
 
 type ExerciseLog struct {
-	ID           int64
-	ExternalID   string
-	ActivityName string
-	StartedAt    time.Time
-	DurationMS   int64
-	Calories     int
-	AvgHR        int
-	DistanceKM   float32
-	ZoneMinutes  json.RawMessage
-	SyncedAt     time.Time
+	ID                int64
+	ExternalID        string
+	ActivityName      string
+	StartedAt         time.Time
+	DurationMS        int64
+	Calories          int
+	AvgHR             int
+	DistanceKM        float32
+	ZoneMinutes       json.RawMessage
+	MET               float32 // metabolic equivalent; 0 if unknown
+	CaloriesPerMinute float32
+	Pace              float32  // seconds per km; 0 when distance is unknown
+	EstimatedVO2Max   *float32 // Uth-Sørensen estimate in ml/kg/min; nil until estimated
+	Tags              []string
+	Notes             string
+	SyncedAt          time.Time
+}
+
+// GPXPoint is one recorded position of an exercise route. Elevation is in
+// metres and nil when the device recorded no altitude.
+type GPXPoint struct {
+	Lat       float64   `json:"lat"`
+	Lng       float64   `json:"lng"`
+	Elevation *float64  `json:"elevation,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// ExerciseRoute is the GPS track of the exercise log with ExerciseExternalID,
+// oldest point first.
+type ExerciseRoute struct {
+	ExerciseExternalID string
+	Points             []GPXPoint
+}
+
+// ComputePace returns the pace in seconds per km, or 0 when there is no
+// distance or duration to divide.
+func ComputePace(durationMS int64, distanceKM float32) float32 {
+	if distanceKM <= 0 || durationMS <= 0 {
+		return 0
+	}
+	return float32(float64(durationMS) / 1000 / float64(distanceKM))
+}
+
+// PacePoint is one exercise in a pace trend.
+type PacePoint struct {
+	StartedAt  time.Time `json:"started_at"`
+	DistanceKM float32   `json:"distance_km"`
+	DurationMS int64     `json:"duration_ms"`
+	Pace       float32   `json:"pace"`
+}
+
+// PaceTrend lists the pace of every exercise of one activity, oldest first.
+// Best is the fastest (lowest) pace and Average is total time over total
+// distance; all three are nil when no exercise had a distance.
+type PaceTrend struct {
+	Activity string      `json:"activity"`
+	From     time.Time   `json:"from"`
+	To       time.Time   `json:"to"`
+	Points   []PacePoint `json:"points"`
+	Best     *float32    `json:"best"`
+	Worst    *float32    `json:"worst"`
+	Average  *float32    `json:"average"`
 }
@@ -27,3 +27,28 @@ func TestExerciseLog_Fields(t *testing.T) {
 		t.Errorf("DurationMS = %d, want 3600000", e.DurationMS)
 	}
 }
+
+func TestComputePace(t *testing.T) {
+	tests := []struct {
+		name       string
+		durationMS int64
+		distanceKM float32
+		want       float32
+	}{
+		{"5k in 25 min", 25 * 60000, 5, 300},
+		{"10k in 50 min", 50 * 60000, 10, 300},
+		{"ride 40 km in 80 min", 80 * 60000, 40, 120},
+		{"fractional distance", 30 * 60000, 5.2, 346.1538},
+		{"no distance", 30 * 60000, 0, 0},
+		{"negative distance", 30 * 60000, -1, 0},
+		{"no duration", 0, 5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputePace(tt.durationMS, tt.distanceKM)
+			if diff := got - tt.want; diff > 0.001 || diff < -0.001 {
+				t.Errorf("ComputePace(%d, %v) = %v, want %v", tt.durationMS, tt.distanceKM, got, tt.want)
+			}
+		})
+	}
+}
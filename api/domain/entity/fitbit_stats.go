@@ -0,0 +1,18 @@
+package entity
+
+// FitbitLifetimeStats are the account-lifetime totals reported by Fitbit.
+// Fitbit reports -1 for totals it does not track (e.g. active score).
+type FitbitLifetimeStats struct {
+	Steps       int     `json:"steps"`
+	DistanceKM  float64 `json:"distance_km"`
+	Floors      int     `json:"floors"`
+	ActiveScore int     `json:"active_score"`
+	CaloriesOut int     `json:"calories_out"`
+}
+
+// FitbitBadge is a badge the user has earned. Date is the day it was earned.
+type FitbitBadge struct {
+	Name  string `json:"name"`
+	Date  string `json:"date"`
+	Value int    `json:"value"`
+}
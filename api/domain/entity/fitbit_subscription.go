@@ -0,0 +1,21 @@
+package entity
+
+// FitbitSubscription is a Subscription API registration for the user's data.
+// An empty CollectionType means every collection is subscribed.
+type FitbitSubscription struct {
+	SubscriptionID string `json:"subscription_id"`
+	SubscriberID   string `json:"subscriber_id"`
+	CollectionType string `json:"collection_type,omitempty"`
+	OwnerID        string `json:"owner_id"`
+	OwnerType      string `json:"owner_type"`
+}
+
+// FitbitNotification is one entry of a Subscription API push. Date is the
+// YYYY-MM-DD day whose data changed.
+type FitbitNotification struct {
+	CollectionType string `json:"collectionType"`
+	Date           string `json:"date"`
+	OwnerID        string `json:"ownerId"`
+	OwnerType      string `json:"ownerType"`
+	SubscriptionID string `json:"subscriptionId"`
+}
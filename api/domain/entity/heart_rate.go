@@ -7,3 +7,36 @@ type HeartRateSample struct {
 	BPM        int
 	Confidence int
 }
+
+// HRVSample is one 5-minute heart rate variability reading taken during sleep.
+type HRVSample struct {
+	Time  time.Time
+	RMSSD float32
+}
+
+// ActiveZoneSample is one minute of active zone minutes. Each field is 1 when
+// the minute was spent in that heart rate zone and 0 otherwise.
+type ActiveZoneSample struct {
+	Time       time.Time
+	FatBurnMin int
+	CardioMin  int
+	PeakMin    int
+}
+
+// HeartRateBucket summarizes intraday samples within one N-minute window.
+type HeartRateBucket struct {
+	BucketTime  time.Time
+	AvgBPM      float32
+	MinBPM      float32
+	MaxBPM      float32
+	SampleCount int
+}
+
+// HRAlert reports a sustained resting heart rate rise: the 3-day mean ending
+// on Date exceeds the 30-day mean by Delta BPM.
+type HRAlert struct {
+	Date          string  `json:"date"`
+	ShortTermMean float32 `json:"short_term_mean"`
+	LongTermMean  float32 `json:"long_term_mean"`
+	Delta         float32 `json:"delta"`
+}
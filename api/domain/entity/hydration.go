@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// HydrationHRVCorrelation relates daily water intake to the following day's
+// HRV. Correlation is nil when fewer than MinPairs days pair up.
+type HydrationHRVCorrelation struct {
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Pairs       int       `json:"pairs"`
+	Correlation *float64  `json:"correlation"`
+	AvgWaterMl  *float64  `json:"avg_water_ml"`
+	MinPairs    int       `json:"min_pairs"`
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// DeviceInfo identifies an app/device pair found in a Health Connect export.
+// Different Wear OS devices export data of different quality, so the set is
+// kept per import for debugging.
+type DeviceInfo struct {
+	AppInfoID   int    `json:"app_info_id"`
+	PackageName string `json:"package_name"`
+	DeviceName  string `json:"device_name"`
+	DeviceModel string `json:"device_model"`
+}
+
+// DateRange is an inclusive span of dates.
+type DateRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+// Joint alert severities.
+const (
+	JointSeverityCritical = "critical" // both scores in the period's worst quartile
+	JointSeverityHigh     = "high"
+)
+
+// JointAlert marks a day with both a low VRI score and an anomaly flag, a
+// stronger signal than either alone. Date is a JST calendar day.
+type JointAlert struct {
+	Date                   string  `json:"date"`
+	VRIScore               float32 `json:"vri_score"`
+	AnomalyScore           float32 `json:"anomaly_score"`
+	NormalizedAnomalyScore float32 `json:"normalized_anomaly_score"`
+	JointSeverity          string  `json:"joint_severity"`
+}
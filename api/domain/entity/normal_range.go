@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// MetricNormalRange is a personal reference range for one DailySummary metric.
+// Lower/Upper span mean ± 1 SD.
+type MetricNormalRange struct {
+	Metric       string   `json:"metric"`
+	Mean         float32  `json:"mean"`
+	StdDev       float32  `json:"std_dev"`
+	Lower        float32  `json:"lower"`
+	Upper        float32  `json:"upper"`
+	P5           float32  `json:"p5"`
+	P95          float32  `json:"p95"`
+	SampleCount  int      `json:"sample_count"`
+	TodayValue   *float32 `json:"today_value"`
+	TodayInRange *bool    `json:"today_in_range"`
+}
+
+// NormalRanges is the set of personal reference ranges over the last
+// WindowDays valid days; DaysUsed is lower when fewer were found.
+type NormalRanges struct {
+	Date       time.Time           `json:"date"`
+	WindowDays int                 `json:"window_days"`
+	DaysUsed   int                 `json:"days_used"`
+	Ranges     []MetricNormalRange `json:"ranges"`
+}
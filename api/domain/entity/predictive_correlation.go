@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// PredictiveCorrelation relates the mean overall VAS of each day in
+// [From, To] to a daily summary metric LagDays later. Correlation and its
+// 95% confidence interval are nil when fewer than MinPairs days pair up.
+type PredictiveCorrelation struct {
+	Metric      string    `json:"metric"`
+	LagDays     int       `json:"lag_days"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Pairs       int       `json:"pairs"`
+	MinPairs    int       `json:"min_pairs"`
+	Correlation *float64  `json:"correlation"`
+	CILower     *float64  `json:"ci_lower"`
+	CIUpper     *float64  `json:"ci_upper"`
+}
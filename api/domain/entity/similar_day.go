@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// SimilarDay is one day ranked by its distance to a query day in a chosen
+// metric space.
+type SimilarDay struct {
+	Date     time.Time     `json:"date"`
+	Distance float64       `json:"distance"`
+	Summary  *DailySummary `json:"summary"`
+}
+
+// SimilarDays lists the days closest to Date, nearest first.
+type SimilarDays struct {
+	Date    time.Time    `json:"date"`
+	Metrics []string     `json:"metrics"`
+	Days    []SimilarDay `json:"days"`
+}
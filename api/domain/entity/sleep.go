@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 type SleepRecord struct {
 	LogID            int64
@@ -18,9 +21,91 @@ type SleepRecord struct {
 	IsMainSleep      bool
 }
 
+// Sleep stage data sources.
+const (
+	SleepStageSourceFitbit        = "fitbit"
+	SleepStageSourceHealthConnect = "health_connect"
+	SleepStageSourceManual        = "manual"
+)
+
 type SleepStage struct {
 	Time    time.Time
 	Stage   string // "deep" | "light" | "rem" | "wake"
 	Seconds int
 	LogID   int64
+	Source  string // "fitbit" | "health_connect" | "manual"
+}
+
+// Sleep inertia levels, by the stage slept in just before waking.
+const (
+	SleepInertiaMinimal  = "minimal"  // woke from REM or brief light sleep
+	SleepInertiaModerate = "moderate" // woke from consolidated light (N2) sleep
+	SleepInertiaSevere   = "severe"   // woke from deep sleep
+	SleepInertiaUnknown  = "unknown"
+)
+
+// SleepInertia estimates post-waking grogginess.
+type SleepInertia struct {
+	Level                string
+	WakeStage            string
+	EstimatedDurationMin int
+}
+
+// Physiologically expected length of one uninterrupted deep or REM episode.
+// Episodes outside these bounds usually point at device or staging errors.
+const (
+	DeepEpisodeMinSec = 20 * 60
+	DeepEpisodeMaxSec = 120 * 60
+	REMEpisodeMinSec  = 10 * 60
+	REMEpisodeMaxSec  = 50 * 60
+)
+
+// ValidateSleepStageTransitions merges consecutive entries of the same stage
+// into episodes and returns the seconds of deep and REM sleep, and how many
+// of those seconds fall in episodes whose length is within the expected
+// bounds. Light and wake episodes have no bounds and are not counted. Stages
+// need not be sorted.
+func ValidateSleepStageTransitions(stages []SleepStage) (validSec, totalSec int) {
+	sorted := make([]SleepStage, len(stages))
+	copy(sorted, stages)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	check := func(stage string, seconds int) {
+		var lo, hi int
+		switch stage {
+		case "deep":
+			lo, hi = DeepEpisodeMinSec, DeepEpisodeMaxSec
+		case "rem":
+			lo, hi = REMEpisodeMinSec, REMEpisodeMaxSec
+		default:
+			return
+		}
+		totalSec += seconds
+		if seconds >= lo && seconds <= hi {
+			validSec += seconds
+		}
+	}
+
+	for i := 0; i < len(sorted); {
+		stage, seconds := sorted[i].Stage, 0
+		for ; i < len(sorted) && sorted[i].Stage == stage; i++ {
+			seconds += sorted[i].Seconds
+		}
+		check(stage, seconds)
+	}
+	return validSec, totalSec
+}
+
+// SleepStageAlert reports a night whose share of one sleep stage lies more
+// than 3 SD from its 30-night mean, which points at a device error or a real
+// change in sleep. Fractions are of the total staged time, 0-1.
+type SleepStageAlert struct {
+	Date             string  `json:"date"`
+	Stage            string  `json:"stage"`
+	Fraction         float32 `json:"fraction"`
+	BaselineFraction float32 `json:"baseline_fraction"`
+	BaselineSD       float32 `json:"baseline_sd"`
+	Delta            float32 `json:"delta"`
+	ZScore           float32 `json:"z_score"`
+	BaselineNights   int     `json:"baseline_nights"`
 }
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// SleepQualityPrediction forecasts the sleep of TargetDate from the
+// biometrics of Date, the day before.
+type SleepQualityPrediction struct {
+	Date                 time.Time
+	TargetDate           time.Time
+	PredictedEfficiency  float32 // percent of time in bed asleep, 0-100
+	PredictedDurationMin float32
+	Confidence           float32
+	ModelVersion         string
+}
@@ -45,3 +45,52 @@ func TestSleepStage_Fields(t *testing.T) {
 		t.Errorf("Seconds = %d, want 300", s.Seconds)
 	}
 }
+
+func TestValidateSleepStageTransitions(t *testing.T) {
+	start := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	// seq builds back-to-back stages from (stage, minutes) pairs.
+	seq := func(parts ...any) []SleepStage {
+		var out []SleepStage
+		at := start
+		for i := 0; i < len(parts); i += 2 {
+			sec := parts[i+1].(int) * 60
+			out = append(out, SleepStage{Time: at, Stage: parts[i].(string), Seconds: sec})
+			at = at.Add(time.Duration(sec) * time.Second)
+		}
+		return out
+	}
+
+	// Wants are in minutes of deep and REM sleep.
+	tests := []struct {
+		name      string
+		stages    []SleepStage
+		wantValid int
+		wantTotal int
+	}{
+		{"empty", nil, 0, 0},
+		{"light and wake only", seq("light", 30, "wake", 5, "light", 40), 0, 0},
+		{"valid night", seq("light", 20, "deep", 45, "light", 30, "rem", 20, "light", 40, "deep", 25, "rem", 35), 125, 125},
+		{"deep split across entries merges", seq("deep", 10, "deep", 15, "light", 10), 25, 25},
+		{"corrupted short deep", seq("light", 20, "deep", 2, "light", 10, "rem", 20), 20, 22},
+		{"corrupted long rem", seq("rem", 90, "light", 10, "deep", 130), 0, 220},
+		{"bounds inclusive", seq("deep", 20, "light", 5, "deep", 120, "light", 5, "rem", 10, "light", 5, "rem", 50), 200, 200},
+		// One short episode among long valid ones barely moves the share.
+		{"weighted by time", seq("deep", 90, "light", 10, "rem", 3, "light", 10, "rem", 40), 130, 133},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, total := ValidateSleepStageTransitions(tt.stages)
+			if valid != tt.wantValid*60 || total != tt.wantTotal*60 {
+				t.Errorf("ValidateSleepStageTransitions() = (%d, %d) sec, want (%d, %d) min", valid, total, tt.wantValid, tt.wantTotal)
+			}
+		})
+	}
+
+	t.Run("unsorted input", func(t *testing.T) {
+		stages := seq("deep", 10, "deep", 15, "light", 5, "rem", 20)
+		stages[1], stages[2] = stages[2], stages[1]
+		if valid, total := ValidateSleepStageTransitions(stages); valid != 45*60 || total != 45*60 {
+			t.Errorf("ValidateSleepStageTransitions() = (%d, %d), want (2700, 2700)", valid, total)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// ProviderSyncStatus reports the sync health of one biometrics provider.
+// LastSyncAt is the last successful sync; LastError is cleared by it.
+type ProviderSyncStatus struct {
+	Provider     string     `json:"provider"`
+	LastSyncAt   *time.Time `json:"last_sync_at"`
+	LastError    string     `json:"last_error"`
+	IsAuthorized bool       `json:"is_authorized"`
+}
+
+// Sync steps named in a SyncReport.
+const (
+	SyncStepDailySummary  = "daily_summary"
+	SyncStepHRV           = "hrv"
+	SyncStepSpO2          = "spo2"
+	SyncStepBreathingRate = "breathing_rate"
+	SyncStepSkinTemp      = "skin_temperature"
+	SyncStepWater         = "water"
+	SyncStepSleep         = "sleep"
+	SyncStepSleepStages   = "sleep_stages"
+	SyncStepHeartRate     = "heart_rate_intraday"
+	SyncStepHRVIntraday   = "hrv_intraday"
+	SyncStepActiveZones   = "active_zones_intraday"
+	SyncStepExercise      = "exercise"
+	SyncStepDataQuality   = "data_quality"
+)
+
+// SyncReport describes one date's sync: the steps whose data was stored and
+// the optional steps that failed (step → error) without failing the sync.
+type SyncReport struct {
+	Date       string            `json:"date"`
+	Populated  []string          `json:"populated"`
+	SoftErrors map[string]string `json:"soft_errors"`
+}
+
+// Bounds of the scheduled sync interval, in minutes.
+const (
+	MinSyncIntervalMin = 5
+	MaxSyncIntervalMin = 1440
+)
+
+// SyncSchedulerConfig is the runtime-adjustable scheduler configuration.
+type SyncSchedulerConfig struct {
+	IntervalMin    int `json:"interval_min"`
+	MinIntervalMin int `json:"min_interval_min"`
+	MaxIntervalMin int `json:"max_interval_min"`
+}
@@ -23,4 +23,23 @@ type VRIScore struct {
 	ContributingFactors json.RawMessage `json:"ContributingFactors"`
 	MetricsIncluded     []string        `json:"MetricsIncluded"`
 	ComputedAt          time.Time       `json:"ComputedAt"`
+	// PopulationPercentile places VRIScore on a normal fit of the previous
+	// 90 days' scores, 0-1. VitaMetron is single-user, so the "population"
+	// is the user's own history. Computed on read; nil without enough history.
+	PopulationPercentile *float32 `json:"PopulationPercentile"`
+}
+
+// VRIAlertDay is one day whose VRI score fell below the alert threshold.
+type VRIAlertDay struct {
+	Date          string  `json:"date"`
+	VRIScore      float32 `json:"vri_score"`
+	VRIConfidence float32 `json:"vri_confidence"`
+}
+
+// VRIAlertDigest lists a week's low-VRI days for the VRI alert webhook.
+type VRIAlertDigest struct {
+	WeekStart   time.Time     `json:"week_start"`
+	WeekEnd     time.Time     `json:"week_end"`
+	AlertDays   []VRIAlertDay `json:"alert_days"`
+	LowVRICount int           `json:"low_vri_count"`
 }
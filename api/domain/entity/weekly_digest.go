@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Condition trend labels for WeeklyDigest.ConditionTrend.
+const (
+	ConditionTrendImproving    = "improving"
+	ConditionTrendStable       = "stable"
+	ConditionTrendDeclining    = "declining"
+	ConditionTrendInsufficient = "insufficient_data"
+)
+
+// WeeklyDigest consolidates one calendar week (Mon–Sun) of health signals.
+// Optional signals are nil when no data exists for the week.
+type WeeklyDigest struct {
+	JobID           string         `json:"job_id"`
+	WeekStart       time.Time      `json:"week_start"`
+	WeekEnd         time.Time      `json:"week_end"`
+	Insight         *WeeklyInsight `json:"insight"`
+	AvgVRIScore     *float64       `json:"avg_vri_score"`
+	VRIDays         int            `json:"vri_days"`
+	AnomalyCount    int            `json:"anomaly_count"`
+	WHO5Percentage  *int           `json:"who5_percentage"`
+	ConditionAvgVAS *float64       `json:"condition_avg_vas"`
+	ConditionTrend  string         `json:"condition_trend"`
+	GeneratedAt     time.Time      `json:"generated_at"`
+}
@@ -6,16 +6,22 @@ import (
 	"time"
 )
 
+// WHO5ScreeningCutoff is the WHO guideline cutoff: a normalized score below
+// this value warrants screening for depression.
+const WHO5ScreeningCutoff = 52
+
 type WHO5Assessment struct {
-	ID          int64
-	AssessedAt  time.Time
-	PeriodStart time.Time
-	PeriodEnd   time.Time
-	Items       [5]int // each 0-5
-	RawScore    int    // 0-25
-	Percentage  int    // 0-100
-	Note        string
-	CreatedAt   time.Time
+	ID                      int64
+	AssessedAt              time.Time
+	PeriodStart             time.Time
+	PeriodEnd               time.Time
+	Items                   [5]int // each 0-5
+	RawScore                int    // 0-25
+	Percentage              int    // 0-100 (normalized score = RawScore * 4)
+	DepressionScreeningFlag bool   // Percentage < WHO5ScreeningCutoff
+	ScoreInterpretation     string // "poor" | "fair" | "good"
+	Note                    string
+	CreatedAt               time.Time
 }
 
 func (w *WHO5Assessment) Validate() error {
@@ -47,4 +53,18 @@ func (w *WHO5Assessment) ComputeScores() {
 	}
 	w.RawScore = sum
 	w.Percentage = sum * 4
+	w.DepressionScreeningFlag = w.Percentage < WHO5ScreeningCutoff
+	w.ScoreInterpretation = InterpretWHO5(w.Percentage)
+}
+
+// InterpretWHO5 classifies a normalized 0-100 score into tertiles.
+func InterpretWHO5(percentage int) string {
+	switch {
+	case percentage < 34:
+		return "poor"
+	case percentage < 67:
+		return "fair"
+	default:
+		return "good"
+	}
 }
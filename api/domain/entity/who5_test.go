@@ -0,0 +1,41 @@
+package entity
+
+import "testing"
+
+func TestWHO5Assessment_ComputeScores(t *testing.T) {
+	tests := []struct {
+		name               string
+		items              [5]int
+		wantRaw            int
+		wantPercentage     int
+		wantFlag           bool
+		wantInterpretation string
+	}{
+		{"all zero", [5]int{0, 0, 0, 0, 0}, 0, 0, true, "poor"},
+		{"poor upper bound", [5]int{2, 2, 2, 1, 1}, 8, 32, true, "poor"},
+		{"fair at cutoff", [5]int{3, 3, 3, 2, 2}, 13, 52, false, "fair"},
+		{"just below cutoff", [5]int{3, 3, 2, 2, 2}, 12, 48, true, "fair"},
+		{"fair upper bound", [5]int{4, 4, 3, 3, 2}, 16, 64, false, "fair"},
+		{"good", [5]int{4, 4, 4, 3, 2}, 17, 68, false, "good"},
+		{"all max", [5]int{5, 5, 5, 5, 5}, 25, 100, false, "good"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &WHO5Assessment{Items: tt.items}
+			a.ComputeScores()
+			if a.RawScore != tt.wantRaw {
+				t.Errorf("RawScore = %d, want %d", a.RawScore, tt.wantRaw)
+			}
+			if a.Percentage != tt.wantPercentage {
+				t.Errorf("Percentage = %d, want %d", a.Percentage, tt.wantPercentage)
+			}
+			if a.DepressionScreeningFlag != tt.wantFlag {
+				t.Errorf("DepressionScreeningFlag = %v, want %v", a.DepressionScreeningFlag, tt.wantFlag)
+			}
+			if a.ScoreInterpretation != tt.wantInterpretation {
+				t.Errorf("ScoreInterpretation = %q, want %q", a.ScoreInterpretation, tt.wantInterpretation)
+			}
+		})
+	}
+}
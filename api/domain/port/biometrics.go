@@ -14,7 +14,27 @@ type BiometricsProvider interface {
 	FetchSleepStages(ctx context.Context, date time.Time) ([]entity.SleepStage, *entity.SleepRecord, error)
 	FetchExerciseLogs(ctx context.Context, date time.Time) ([]entity.ExerciseLog, error)
 	FetchHRV(ctx context.Context, date time.Time) (float32, float32, error)
+	FetchHRVIntraday(ctx context.Context, date time.Time) ([]entity.HRVSample, error)
+	FetchActiveZoneMinutesIntraday(ctx context.Context, date time.Time) ([]entity.ActiveZoneSample, error)
 	FetchSpO2(ctx context.Context, date time.Time) (avg, min, max float32, err error)
 	FetchBreathingRate(ctx context.Context, date time.Time) (full, deep, light, rem float32, err error)
 	FetchSkinTemperature(ctx context.Context, date time.Time) (float32, error)
+	FetchWaterLog(ctx context.Context, date time.Time) (int, error)
+}
+
+// FitbitStatsProvider reads account-level Fitbit data that is not tied to a
+// single day.
+type FitbitStatsProvider interface {
+	FetchLifetimeStats(ctx context.Context) (*entity.FitbitLifetimeStats, error)
+	FetchBadges(ctx context.Context) ([]entity.FitbitBadge, error)
+}
+
+// FitbitSubscriptionProvider manages Subscription API registrations and
+// authenticates the pushes Fitbit sends for them.
+type FitbitSubscriptionProvider interface {
+	FetchSubscriptions(ctx context.Context) ([]entity.FitbitSubscription, error)
+	CreateSubscription(ctx context.Context, endpoint, subscriptionID string) (*entity.FitbitSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+	// VerifyNotification reports whether signature is valid for the raw body.
+	VerifyNotification(body []byte, signature string) bool
 }
@@ -2,10 +2,18 @@ package port
 
 import "context"
 
+// OAuthProvider runs the OAuth flow and holds the stored token for one
+// biometrics provider, so handlers and the scheduler work with any provider.
 type OAuthProvider interface {
 	AuthorizationURL(ctx context.Context) (url, state string, err error)
 	ExchangeCode(ctx context.Context, code, state string) error
 	RefreshTokenIfNeeded(ctx context.Context) error
 	IsAuthorized(ctx context.Context) (bool, error)
 	Disconnect(ctx context.Context) error
+	GetAccessToken(ctx context.Context) (string, error)
+}
+
+// TokenHealthChecker reports whether a refreshed OAuth token failed to persist.
+type TokenHealthChecker interface {
+	TokenSaveFailed(ctx context.Context) (bool, error)
 }
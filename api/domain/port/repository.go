@@ -11,21 +11,105 @@ type ConditionRepository interface {
 	Create(ctx context.Context, log *entity.ConditionLog) error
 	GetByID(ctx context.Context, id int64) (*entity.ConditionLog, error)
 	List(ctx context.Context, filter entity.ConditionFilter) (*entity.ConditionListResult, error)
+	// Update overwrites a log and records its previous values in the history.
 	Update(ctx context.Context, log *entity.ConditionLog) error
+	// GetHistory returns the recorded edits of a log, oldest first.
+	GetHistory(ctx context.Context, id int64) ([]entity.ConditionLogHistory, error)
+	// Delete soft-deletes a log; every query but List with IncludeDeleted skips it.
 	Delete(ctx context.Context, id int64) error
+	// Restore clears a soft delete and reports whether a deleted log was found.
+	Restore(ctx context.Context, id int64) (bool, error)
+	// DeletePermanent removes a log, deleted or not, and reports whether it existed.
+	DeletePermanent(ctx context.Context, id int64) (bool, error)
 	GetTags(ctx context.Context) ([]entity.TagCount, error)
-	GetSummary(ctx context.Context, from, to time.Time) (*entity.ConditionSummary, error)
+	// GetSummary aggregates logs in [filter.From, filter.To] using
+	// filter.WeightingMode for the averages; other filter fields are ignored.
+	GetSummary(ctx context.Context, filter entity.ConditionFilter) (*entity.ConditionSummary, error)
+	// ListRange returns every log in [from, to], oldest first.
+	ListRange(ctx context.Context, from, to time.Time) ([]entity.ConditionLog, error)
+	// GetVASSeries returns overall VAS for each log in [from, to], oldest first.
+	GetVASSeries(ctx context.Context, from, to time.Time) ([]entity.VASPoint, error)
+	CountBySource(ctx context.Context, from, to time.Time) ([]entity.ConditionSourceCount, error)
+	// Archive moves logs with logged_at before the cutoff to the archive table
+	// and returns the number of rows moved.
+	Archive(ctx context.Context, before time.Time) (int64, error)
+	// RenameTag replaces oldTag with newTag on every log and returns the number of logs updated.
+	RenameTag(ctx context.Context, oldTag, newTag string) (int64, error)
+}
+
+// ConditionAnnotationRepository stores study labels attached to condition logs.
+type ConditionAnnotationRepository interface {
+	// Create inserts an annotation and sets its ID and CreatedAt.
+	Create(ctx context.Context, a *entity.ConditionAnnotation) error
+	// ListByLogID returns the annotations of a log, oldest first.
+	ListByLogID(ctx context.Context, logID int64) ([]entity.ConditionAnnotation, error)
+	// Delete removes an annotation of the given log and reports whether it existed.
+	Delete(ctx context.Context, logID, id int64) (bool, error)
 }
 
 type DailySummaryRepository interface {
 	Upsert(ctx context.Context, summary *entity.DailySummary) error
 	GetByDate(ctx context.Context, date time.Time) (*entity.DailySummary, error)
 	ListRange(ctx context.Context, from, to time.Time) ([]entity.DailySummary, error)
+	CountRange(ctx context.Context, from, to time.Time) (int, error)
 }
 
 type HeartRateRepository interface {
 	BulkUpsert(ctx context.Context, samples []entity.HeartRateSample) error
 	ListRange(ctx context.Context, from, to time.Time) ([]entity.HeartRateSample, error)
+	// ListRangeAggregated buckets the samples in [from, to); to itself is
+	// excluded so a day ending at the next midnight gets no extra bucket.
+	ListRangeAggregated(ctx context.Context, from, to time.Time, bucketMin int) ([]entity.HeartRateBucket, error)
+}
+
+type HRVSampleRepository interface {
+	BulkUpsert(ctx context.Context, samples []entity.HRVSample) error
+	ListRange(ctx context.Context, from, to time.Time) ([]entity.HRVSample, error)
+}
+
+type ActiveZoneSampleRepository interface {
+	BulkUpsert(ctx context.Context, samples []entity.ActiveZoneSample) error
+	ListRange(ctx context.Context, from, to time.Time) ([]entity.ActiveZoneSample, error)
+}
+
+// ImportHistoryRepository keeps per-job import metadata. GetDevices returns
+// nil, nil when the job is unknown.
+type ImportHistoryRepository interface {
+	SaveDevices(ctx context.Context, jobID, source string, devices []entity.DeviceInfo) error
+	GetDevices(ctx context.Context, jobID string) ([]entity.DeviceInfo, error)
+}
+
+// SchedulerConfigStore persists scheduler settings changed at runtime so they
+// survive a restart.
+type SchedulerConfigStore interface {
+	// IntervalMin returns the stored sync interval; ok is false when none is stored.
+	IntervalMin(ctx context.Context) (minutes int, ok bool, err error)
+	SetIntervalMin(ctx context.Context, minutes int) error
+}
+
+// SyncStatusStore records the outcome of each scheduled provider sync.
+type SyncStatusStore interface {
+	// RecordSync stores a sync outcome; a nil syncErr marks a success at at.
+	RecordSync(ctx context.Context, provider string, at time.Time, syncErr error) error
+	// List returns every provider with a recorded sync. IsAuthorized is not set.
+	List(ctx context.Context) ([]entity.ProviderSyncStatus, error)
+}
+
+// SyncQueue holds dates waiting to be re-synced after a provider push.
+type SyncQueue interface {
+	Enqueue(ctx context.Context, dates []time.Time) error
+	// Dequeue pops the oldest date; ok is false when the queue is empty.
+	Dequeue(ctx context.Context) (date time.Time, ok bool, err error)
+}
+
+// AlertSentStore remembers which alerts went out, so re-syncing a date does
+// not send its alerts again.
+type AlertSentStore interface {
+	// MarkSent records the kind alert for date and reports whether it was not
+	// recorded before.
+	MarkSent(ctx context.Context, kind, date string) (bool, error)
+	// ClearSent forgets a mark so a failed send is retried on the next sync.
+	ClearSent(ctx context.Context, kind, date string) error
 }
 
 type SleepStageRepository interface {
@@ -37,6 +121,20 @@ type SleepStageRepository interface {
 type ExerciseRepository interface {
 	Upsert(ctx context.Context, log *entity.ExerciseLog) error
 	ListRange(ctx context.Context, from, to time.Time) ([]entity.ExerciseLog, error)
+	GetByID(ctx context.Context, id int64) (*entity.ExerciseLog, error)
+	UpdateEstimatedVO2Max(ctx context.Context, id int64, vo2max float32) error
+	// ListByTag returns the logs in [from, to] carrying tag, newest first.
+	ListByTag(ctx context.Context, tag string, from, to time.Time) ([]entity.ExerciseLog, error)
+	// UpdateNotes replaces the notes and tags of a log.
+	UpdateNotes(ctx context.Context, id int64, notes string, tags []string) error
+}
+
+// ExerciseRouteRepository stores GPS tracks keyed by exercise external ID.
+type ExerciseRouteRepository interface {
+	// Save replaces the route of route.ExerciseExternalID.
+	Save(ctx context.Context, route *entity.ExerciseRoute) error
+	// GetByExercise returns nil when the exercise has no route.
+	GetByExercise(ctx context.Context, externalID string) (*entity.ExerciseRoute, error)
 }
 
 type TokenRepository interface {
@@ -45,6 +143,24 @@ type TokenRepository interface {
 	Delete(ctx context.Context, provider string) error
 }
 
+// PKCEStateRepository durably stores OAuth PKCE states alongside Redis.
+type PKCEStateRepository interface {
+	Save(ctx context.Context, state, verifier string, expiresAt time.Time) error
+	// Take deletes the state and returns its verifier; ok is false when the
+	// state is unknown or expired.
+	Take(ctx context.Context, state string) (verifier string, ok bool, err error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// ModelMetadataRepository records ML model training runs.
+type ModelMetadataRepository interface {
+	SaveAnomalyTrain(ctx context.Context, result *entity.AnomalyTrainResult) error
+	// LatestAnomalyTrain returns nil when no run is recorded.
+	LatestAnomalyTrain(ctx context.Context) (*entity.AnomalyTrainRun, error)
+	// ListAnomalyTrains returns every run, newest first.
+	ListAnomalyTrains(ctx context.Context) ([]entity.AnomalyTrainRun, error)
+}
+
 type PredictionRepository interface {
 	Save(ctx context.Context, pred *entity.ConditionPrediction) error
 	GetByDate(ctx context.Context, date time.Time) (*entity.ConditionPrediction, error)
@@ -54,6 +170,7 @@ type DataQualityRepository interface {
 	Upsert(ctx context.Context, q *entity.DataQuality) error
 	GetByDate(ctx context.Context, date time.Time) (*entity.DataQuality, error)
 	ListRange(ctx context.Context, from, to time.Time) ([]entity.DataQuality, error)
+	ListAlerts(ctx context.Context, from, to time.Time) ([]entity.DataQuality, error)
 	CountValidDays(ctx context.Context, before time.Time, windowDays int) (int, error)
 }
 
@@ -65,6 +182,17 @@ type VRIRepository interface {
 type AnomalyRepository interface {
 	GetByDate(ctx context.Context, date time.Time) (*entity.AnomalyDetection, error)
 	ListRange(ctx context.Context, from, to time.Time) ([]entity.AnomalyDetection, error)
+	// DeleteOtherModelVersions drops the stored detections not computed by
+	// modelVersion and returns how many there were.
+	DeleteOtherModelVersions(ctx context.Context, modelVersion string) (int64, error)
+}
+
+// AnomalySensitivityStore persists the user's anomaly sensitivity so it
+// survives a restart.
+type AnomalySensitivityStore interface {
+	// Sensitivity returns the stored sensitivity; ok is false when none is stored.
+	Sensitivity(ctx context.Context) (sensitivity string, ok bool, err error)
+	SetSensitivity(ctx context.Context, sensitivity string) error
 }
 
 type DivergenceRepository interface {
@@ -0,0 +1,7 @@
+package port
+
+import "context"
+
+type WebhookSender interface {
+	Send(ctx context.Context, jobID string, payload any) error
+}
@@ -2,10 +2,13 @@ package handler
 
 import (
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 
 	"vitametron/api/adapter/mlclient"
 	"vitametron/api/domain/entity"
@@ -15,12 +18,24 @@ import (
 type AdviceHandler struct {
 	mlClient   *mlclient.Client
 	adviceRepo port.AdviceRepository
+
+	rdb            *redis.Client
+	maxRegenPerDay int
 }
 
 func NewAdviceHandler(mlClient *mlclient.Client, adviceRepo port.AdviceRepository) *AdviceHandler {
 	return &AdviceHandler{mlClient: mlClient, adviceRepo: adviceRepo}
 }
 
+// WithRegenerateLimit caps regenerations at maxPerDay per advice date,
+// counted in Redis until the next UTC midnight. A non-positive maxPerDay
+// leaves regeneration unlimited.
+func (h *AdviceHandler) WithRegenerateLimit(rdb *redis.Client, maxPerDay int) *AdviceHandler {
+	h.rdb = rdb
+	h.maxRegenPerDay = maxPerDay
+	return h
+}
+
 func (h *AdviceHandler) GetAdvice(c echo.Context) error {
 	dateStr := c.QueryParam("date")
 	if dateStr == "" {
@@ -67,6 +82,11 @@ func (h *AdviceHandler) RegenerateAdvice(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
 	}
 
+	if retryAfter, ok := h.allowRegenerate(c, date); !ok {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "daily regeneration limit reached"})
+	}
+
 	advice, err := h.mlClient.RegenerateAdvice(c.Request().Context(), date)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -75,6 +95,34 @@ func (h *AdviceHandler) RegenerateAdvice(c echo.Context) error {
 	return c.JSON(http.StatusOK, advice)
 }
 
+// allowRegenerate counts a regeneration of date's advice and reports whether
+// it is within the daily limit, along with the time left until the counter
+// resets. A Redis failure lets the request through.
+func (h *AdviceHandler) allowRegenerate(c echo.Context, date time.Time) (time.Duration, bool) {
+	if h.rdb == nil || h.maxRegenPerDay <= 0 {
+		return 0, true
+	}
+
+	now := time.Now().UTC()
+	untilReset := now.Truncate(24 * time.Hour).Add(24 * time.Hour).Sub(now)
+	ctx := c.Request().Context()
+	key := "advice_regen_count:" + date.Format("2006-01-02")
+	count, err := h.rdb.Incr(ctx, key).Result()
+	if err != nil {
+		log.Printf("warn: count advice regeneration: %v", err)
+		return 0, true
+	}
+	if count == 1 {
+		if err := h.rdb.Expire(ctx, key, untilReset).Err(); err != nil {
+			log.Printf("warn: expire advice regeneration count: %v", err)
+		}
+	}
+	if count > int64(h.maxRegenPerDay) {
+		return untilReset, false
+	}
+	return 0, true
+}
+
 func (h *AdviceHandler) Register(g *echo.Group) {
 	g.GET("/advice", h.GetAdvice)
 	g.POST("/advice/regenerate", h.RegenerateAdvice)
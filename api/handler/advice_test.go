@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestAdviceHandler_RegenerateAdvice_DailyLimit(t *testing.T) {
+	var mlCalls int
+	mlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/advice/regenerate" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		mlCalls++
+		json.NewEncoder(w).Encode(map[string]any{
+			"date":        "2026-02-17",
+			"advice_text": "早めに休みましょう。",
+			"model_name":  "test",
+		})
+	}))
+	defer mlServer.Close()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	h := NewAdviceHandler(newTestMLClient(mlServer.URL), nil).WithRegenerateLimit(rdb, 3)
+	regenerate := func(date string) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/advice/regenerate?date="+date, nil)
+		rec := httptest.NewRecorder()
+		if err := h.RegenerateAdvice(e.NewContext(req, rec)); err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+
+	for i := 1; i <= 3; i++ {
+		if rec := regenerate("2026-02-17"); rec.Code != http.StatusOK {
+			t.Fatalf("call %d: status = %d, want %d", i, rec.Code, http.StatusOK)
+		}
+	}
+
+	rec := regenerate("2026-02-17")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("call 4: status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retryAfter <= 0 || retryAfter > 24*60*60 {
+		t.Errorf("Retry-After = %q, want seconds until UTC midnight", rec.Header().Get("Retry-After"))
+	}
+	if mlCalls != 3 {
+		t.Errorf("ML called %d times, want 3", mlCalls)
+	}
+	if ttl := mr.TTL("advice_regen_count:2026-02-17"); ttl <= 0 {
+		t.Errorf("counter TTL = %v, want it to expire", ttl)
+	}
+
+	// Each advice date has its own budget.
+	if rec := regenerate("2026-02-16"); rec.Code != http.StatusOK {
+		t.Errorf("other date: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/application"
+)
+
+type AnalyticsHandler struct {
+	activity         *application.ActivityEquivalentCalculator
+	hydration        *application.HydrationAnalyzer
+	conditionSources *application.ConditionSourceAnalyzer
+	vasHistogram     *application.VASHistogramAnalyzer
+	breathingRate    *application.BreathingRateAnalyzer
+	predictive       *application.PredictiveCorrelationAnalyzer
+	jointAlerts      *application.JointAlertAnalyzer
+}
+
+func NewAnalyticsHandler(
+	activity *application.ActivityEquivalentCalculator,
+	hydration *application.HydrationAnalyzer,
+	conditionSources *application.ConditionSourceAnalyzer,
+	vasHistogram *application.VASHistogramAnalyzer,
+	breathingRate *application.BreathingRateAnalyzer,
+	predictive *application.PredictiveCorrelationAnalyzer,
+	jointAlerts *application.JointAlertAnalyzer,
+) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		activity:         activity,
+		hydration:        hydration,
+		conditionSources: conditionSources,
+		vasHistogram:     vasHistogram,
+		breathingRate:    breathingRate,
+		predictive:       predictive,
+		jointAlerts:      jointAlerts,
+	}
+}
+
+// GetActivityEquivalent converts a step count to estimated activity.
+// GET /api/analytics/activity-equivalent?steps=10000
+func (h *AnalyticsHandler) GetActivityEquivalent(c echo.Context) error {
+	steps, err := strconv.Atoi(c.QueryParam("steps"))
+	if err != nil || steps < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "steps must be a non-negative integer"})
+	}
+	return c.JSON(http.StatusOK, h.activity.Convert(steps))
+}
+
+// GetActivityEquivalentRange converts each day's recorded steps.
+// GET /api/analytics/activity-equivalent/range?from=2025-01-01&to=2025-01-31
+func (h *AnalyticsHandler) GetActivityEquivalentRange(c echo.Context) error {
+	from, err := parseDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+	}
+	to, err := parseDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 1 year"})
+	}
+
+	result, err := h.activity.ConvertRange(c.Request().Context(), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetHydrationHRV correlates daily water intake with next-day HRV.
+// GET /api/analytics/hydration-hrv?from=2025-01-01&to=2025-03-31
+func (h *AnalyticsHandler) GetHydrationHRV(c echo.Context) error {
+	to := time.Now().In(jst)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, jst)
+	if s := c.QueryParam("to"); s != "" {
+		d, err := parseDate(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+		}
+		to = d
+	}
+	from := to.AddDate(0, 0, -89)
+	if s := c.QueryParam("from"); s != "" {
+		d, err := parseDate(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+		}
+		from = d
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 1 year"})
+	}
+
+	result, err := h.hydration.CorrelateNextDayHRV(c.Request().Context(), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetConditionSources counts condition logs by source over the last window days.
+// GET /api/analytics/condition-sources?window=30
+func (h *AnalyticsHandler) GetConditionSources(c echo.Context) error {
+	window := 30
+	if w := c.QueryParam("window"); w != "" {
+		n, err := strconv.Atoi(w)
+		if err != nil || n < 1 || n > 365 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "window must be between 1 and 365"})
+		}
+		window = n
+	}
+
+	now := time.Now().In(jst)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jst)
+	from := today.AddDate(0, 0, -(window - 1))
+
+	result, err := h.conditionSources.Breakdown(c.Request().Context(), from, now)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetVASHistogram buckets one VAS metric of the condition logs in a range.
+// GET /api/analytics/vas-histogram?metric=overall_vas&bins=10&from=2025-01-01&to=2025-03-31
+func (h *AnalyticsHandler) GetVASHistogram(c echo.Context) error {
+	metric := c.QueryParam("metric")
+	if metric == "" {
+		metric = "overall_vas"
+	}
+	bins := 10
+	if b := c.QueryParam("bins"); b != "" {
+		n, err := strconv.Atoi(b)
+		if err != nil || n < 1 || n > 100 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "bins must be between 1 and 100"})
+		}
+		bins = n
+	}
+	from, err := parseDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+	}
+	to, err := parseDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 1 year"})
+	}
+
+	// date-only 'to' → include entire day
+	buckets, err := h.vasHistogram.Histogram(c.Request().Context(), metric, bins, from, to.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if errors.Is(err, application.ErrUnknownVASMetric) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, buckets)
+}
+
+// GetBreathingRate returns nightly breathing rates, their correlation with
+// sleep stage durations, and likely respiratory disturbance nights.
+// GET /api/analytics/breathing-rate?from=2025-01-01&to=2025-03-31
+func (h *AnalyticsHandler) GetBreathingRate(c echo.Context) error {
+	from, err := parseDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+	}
+	to, err := parseDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 1 year"})
+	}
+
+	result, err := h.breathingRate.Analyze(c.Request().Context(), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetPredictiveCorrelation correlates daily wellbeing over the last window
+// days with a biometric lag days later.
+// GET /api/analytics/predictive-correlation?metric=resting_hr&lag=1&window=60
+func (h *AnalyticsHandler) GetPredictiveCorrelation(c echo.Context) error {
+	metric := c.QueryParam("metric")
+	if metric == "" {
+		metric = "resting_hr"
+	}
+	lag := 1
+	if l := c.QueryParam("lag"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > 7 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "lag must be between 1 and 7"})
+		}
+		lag = n
+	}
+	window := 60
+	if w := c.QueryParam("window"); w != "" {
+		n, err := strconv.Atoi(w)
+		if err != nil || n < 7 || n > 365 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "window must be between 7 and 365"})
+		}
+		window = n
+	}
+
+	// The last lag days have no outcome yet, so the window ends lag days ago.
+	now := time.Now().In(jst)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jst).AddDate(0, 0, -lag)
+	from := to.AddDate(0, 0, -(window - 1))
+
+	result, err := h.predictive.Correlate(c.Request().Context(), metric, lag, from, to)
+	if errors.Is(err, application.ErrUnknownSummaryMetric) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetJointAlerts lists days where a VRI score below 40 coincides with an
+// anomaly flag.
+// GET /api/analytics/joint-alerts?from=2025-01-01&to=2025-03-31
+func (h *AnalyticsHandler) GetJointAlerts(c echo.Context) error {
+	from, err := parseDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+	}
+	to, err := parseDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 1 year"})
+	}
+
+	alerts, err := h.jointAlerts.Alerts(c.Request().Context(), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, alerts)
+}
+
+func (h *AnalyticsHandler) Register(g *echo.Group) {
+	g.GET("/analytics/activity-equivalent", h.GetActivityEquivalent)
+	g.GET("/analytics/activity-equivalent/range", h.GetActivityEquivalentRange)
+	g.GET("/analytics/hydration-hrv", h.GetHydrationHRV)
+	g.GET("/analytics/condition-sources", h.GetConditionSources)
+	g.GET("/analytics/vas-histogram", h.GetVASHistogram)
+	g.GET("/analytics/breathing-rate", h.GetBreathingRate)
+	g.GET("/analytics/predictive-correlation", h.GetPredictiveCorrelation)
+	g.GET("/analytics/joint-alerts", h.GetJointAlerts)
+}
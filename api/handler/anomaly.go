@@ -1,10 +1,18 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/sync/singleflight"
 
 	"vitametron/api/adapter/mlclient"
 	"vitametron/api/domain/entity"
@@ -14,12 +22,45 @@ import (
 type AnomalyHandler struct {
 	mlClient    *mlclient.Client
 	anomalyRepo port.AnomalyRepository
+	// inflight collapses concurrent on-demand detections of the same date.
+	inflight singleflight.Group
+	// timeout bounds an on-demand detection; zero waits for the ML client.
+	timeout time.Duration
+
+	modelMetadata port.ModelMetadataRepository
+	adminAuth     echo.MiddlewareFunc
+
+	sensitivityStore port.AnomalySensitivityStore
+	sensitivityAuth  echo.MiddlewareFunc
 }
 
 func NewAnomalyHandler(mlClient *mlclient.Client, anomalyRepo port.AnomalyRepository) *AnomalyHandler {
 	return &AnomalyHandler{mlClient: mlClient, anomalyRepo: anomalyRepo}
 }
 
+// WithModelMetadata records every training run in repo and enables the
+// /ml/anomaly routes; retraining with a new contamination is guarded by mw.
+func (h *AnomalyHandler) WithModelMetadata(repo port.ModelMetadataRepository, mw echo.MiddlewareFunc) *AnomalyHandler {
+	h.modelMetadata = repo
+	h.adminAuth = mw
+	return h
+}
+
+// WithSensitivity enables GET /ml/anomaly/sensitivity and
+// PUT /ml/anomaly/sensitivity, the latter guarded by mw.
+// Changes are persisted to store so they survive a restart.
+func (h *AnomalyHandler) WithSensitivity(store port.AnomalySensitivityStore, mw echo.MiddlewareFunc) *AnomalyHandler {
+	h.sensitivityStore = store
+	h.sensitivityAuth = mw
+	return h
+}
+
+// WithTimeout makes GetAnomaly answer 503 when an on-demand detection takes longer than d.
+func (h *AnomalyHandler) WithTimeout(d time.Duration) *AnomalyHandler {
+	h.timeout = d
+	return h
+}
+
 func (h *AnomalyHandler) GetAnomaly(c echo.Context) error {
 	dateStr := c.QueryParam("date")
 	if dateStr == "" {
@@ -37,16 +78,24 @@ func (h *AnomalyHandler) GetAnomaly(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 	if detection != nil {
-		return c.JSON(http.StatusOK, detection)
+		return jsonWithETag(c, detection)
 	}
 
 	// Fall back to ML client for on-demand compute
-	detection, err = h.mlClient.DetectAnomaly(c.Request().Context(), date)
+	detection, err = dedupe(c.Request().Context(), &h.inflight, date.Format("2006-01-02"),
+		func(ctx context.Context) (*entity.AnomalyDetection, error) {
+			ctx, cancel := withDeadline(ctx, h.timeout)
+			defer cancel()
+			return h.mlClient.DetectAnomaly(ctx, date)
+		})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "anomaly_detection_timeout"})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, detection)
+	return jsonWithETag(c, detection)
 }
 
 func (h *AnomalyHandler) GetAnomalyRange(c echo.Context) error {
@@ -87,6 +136,220 @@ func (h *AnomalyHandler) GetAnomalyRange(c echo.Context) error {
 	return c.JSON(http.StatusOK, detections)
 }
 
+type anomalyModelVersionUsage struct {
+	ModelVersion string `json:"model_version"`
+	FirstDate    string `json:"first_date"`
+	LastDate     string `json:"last_date"`
+	Days         int    `json:"days"`
+}
+
+// GetModelVersions returns the distinct model versions that produced the
+// stored detections in the given range, so users can tell when their history
+// was computed under a different model.
+func (h *AnomalyHandler) GetModelVersions(c echo.Context) error {
+	fromStr := c.QueryParam("from")
+	toStr := c.QueryParam("to")
+	if fromStr == "" || toStr == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "from and to are required"})
+	}
+
+	from, err := parseDate(fromStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid from date"})
+	}
+	to, err := parseDate(toStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid to date"})
+	}
+
+	detections, err := h.anomalyRepo.ListRange(c.Request().Context(), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, summarizeModelVersions(detections))
+}
+
+// summarizeModelVersions groups detections (ordered by date) by model
+// version, preserving first-seen order.
+func summarizeModelVersions(detections []entity.AnomalyDetection) []anomalyModelVersionUsage {
+	usages := []anomalyModelVersionUsage{}
+	index := make(map[string]int)
+	for _, d := range detections {
+		day := d.Date.Format("2006-01-02")
+		i, ok := index[d.ModelVersion]
+		if !ok {
+			index[d.ModelVersion] = len(usages)
+			usages = append(usages, anomalyModelVersionUsage{
+				ModelVersion: d.ModelVersion,
+				FirstDate:    day,
+				LastDate:     day,
+				Days:         1,
+			})
+			continue
+		}
+		usages[i].LastDate = day
+		usages[i].Days++
+	}
+	return usages
+}
+
+type anomalyZScorePoint struct {
+	Date                 time.Time `json:"date"`
+	NormalizedScore      float32   `json:"normalized_score"`
+	QualityAdjustedScore float32   `json:"quality_adjusted_score"`
+	IsAnomaly            bool      `json:"is_anomaly"`
+}
+
+type anomalyZScoreHistory struct {
+	Points      []anomalyZScorePoint `json:"points"`
+	AnomalyRate float32              `json:"anomaly_rate"`
+	MaxScore    float32              `json:"max_score"`
+}
+
+// GetZScoreHistory returns a lightweight score time series for charting,
+// with the share of anomaly days and the peak score in the period.
+// GET /api/anomaly/z-scores?from=2026-01-01&to=2026-01-31
+func (h *AnomalyHandler) GetZScoreHistory(c echo.Context) error {
+	fromStr := c.QueryParam("from")
+	toStr := c.QueryParam("to")
+	if fromStr == "" || toStr == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "from and to are required"})
+	}
+
+	from, err := parseDate(fromStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid from date"})
+	}
+	to, err := parseDate(toStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid to date"})
+	}
+
+	detections, err := h.anomalyRepo.ListRange(c.Request().Context(), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, summarizeZScores(detections))
+}
+
+func summarizeZScores(detections []entity.AnomalyDetection) anomalyZScoreHistory {
+	history := anomalyZScoreHistory{Points: make([]anomalyZScorePoint, 0, len(detections))}
+	anomalies := 0
+	for i, d := range detections {
+		history.Points = append(history.Points, anomalyZScorePoint{
+			Date:                 d.Date,
+			NormalizedScore:      d.NormalizedScore,
+			QualityAdjustedScore: d.QualityAdjustedScore,
+			IsAnomaly:            d.IsAnomaly,
+		})
+		if d.IsAnomaly {
+			anomalies++
+		}
+		if i == 0 || d.NormalizedScore > history.MaxScore {
+			history.MaxScore = d.NormalizedScore
+		}
+	}
+	if len(detections) > 0 {
+		history.AnomalyRate = float32(anomalies) / float32(len(detections))
+	}
+	return history
+}
+
+// maxAnomalyDrivers is how many drivers GetDrivers returns.
+const maxAnomalyDrivers = 5
+
+type anomalyDriversResponse struct {
+	Date      time.Time               `json:"date"`
+	Drivers   []entity.AnomalyDriver  `json:"drivers"`
+	Waterfall entity.AnomalyWaterfall `json:"waterfall"`
+}
+
+// GetDrivers returns the stored top drivers of a day's detection ranked by
+// absolute SHAP value, plus the same drivers as waterfall chart steps.
+// GET /api/anomaly/drivers?date=2026-01-15
+func (h *AnomalyHandler) GetDrivers(c echo.Context) error {
+	dateStr := c.QueryParam("date")
+	if dateStr == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "date is required"})
+	}
+
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
+	}
+
+	detection, err := h.anomalyRepo.GetByDate(c.Request().Context(), date)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if detection == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "no anomaly detection for this date"})
+	}
+
+	drivers, err := rankAnomalyDrivers(detection.TopDrivers)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "invalid stored drivers: " + err.Error()})
+	}
+
+	return jsonWithETag(c, anomalyDriversResponse{
+		Date:      detection.Date,
+		Drivers:   drivers,
+		Waterfall: anomalyWaterfall(detection.AnomalyScore, drivers),
+	})
+}
+
+// rankAnomalyDrivers parses stored drivers, sorts them by absolute SHAP
+// value (largest first) and keeps the top maxAnomalyDrivers.
+func rankAnomalyDrivers(raw json.RawMessage) ([]entity.AnomalyDriver, error) {
+	var stored []struct {
+		Feature     string  `json:"feature"`
+		ShapValue   float32 `json:"shap_value"`
+		Direction   string  `json:"direction"`
+		Description string  `json:"description"`
+	}
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &stored); err != nil {
+			return nil, err
+		}
+	}
+
+	drivers := make([]entity.AnomalyDriver, 0, len(stored))
+	var total float64
+	for _, d := range stored {
+		drivers = append(drivers, entity.AnomalyDriver{
+			Feature:     d.Feature,
+			ShapValue:   d.ShapValue,
+			Direction:   d.Direction,
+			Description: d.Description,
+		})
+		total += math.Abs(float64(d.ShapValue))
+	}
+	sort.SliceStable(drivers, func(i, j int) bool {
+		return math.Abs(float64(drivers[i].ShapValue)) > math.Abs(float64(drivers[j].ShapValue))
+	})
+	if len(drivers) > maxAnomalyDrivers {
+		drivers = drivers[:maxAnomalyDrivers]
+	}
+	if total > 0 {
+		for i := range drivers {
+			drivers[i].Contribution = float32(math.Abs(float64(drivers[i].ShapValue)) / total)
+		}
+	}
+	return drivers, nil
+}
+
+// anomalyWaterfall places the drivers between a baseline and the score,
+// so the steps sum exactly from Baseline to Output.
+func anomalyWaterfall(score float32, drivers []entity.AnomalyDriver) entity.AnomalyWaterfall {
+	var sum float32
+	for _, d := range drivers {
+		sum += d.ShapValue
+	}
+	return entity.AnomalyWaterfall{Baseline: score - sum, Output: score, Steps: drivers}
+}
+
 func (h *AnomalyHandler) GetAnomalyStatus(c echo.Context) error {
 	status, err := h.mlClient.GetAnomalyStatus(c.Request().Context())
 	if err != nil {
@@ -97,17 +360,149 @@ func (h *AnomalyHandler) GetAnomalyStatus(c echo.Context) error {
 }
 
 func (h *AnomalyHandler) TrainAnomalyModel(c echo.Context) error {
-	result, err := h.mlClient.TrainAnomalyModel(c.Request().Context())
+	result, err := h.mlClient.TrainAnomalyModel(c.Request().Context(), 0)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	h.recordTrain(c.Request().Context(), result)
 
 	return c.JSON(http.StatusOK, result)
 }
 
+// recordTrain stores a finished training run. The model is already trained,
+// so a failure is only logged.
+func (h *AnomalyHandler) recordTrain(ctx context.Context, result *entity.AnomalyTrainResult) {
+	if h.modelMetadata == nil {
+		return
+	}
+	if err := h.modelMetadata.SaveAnomalyTrain(ctx, result); err != nil {
+		log.Printf("warn: record anomaly training run %s: %v", result.ModelVersion, err)
+	}
+}
+
+// GetAnomalyConfig returns the contamination of the latest recorded training
+// run, or the ML service default before any run.
+// GET /api/ml/anomaly/config
+func (h *AnomalyHandler) GetAnomalyConfig(c echo.Context) error {
+	run, err := h.modelMetadata.LatestAnomalyTrain(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	cfg := entity.AnomalyModelConfig{Contamination: entity.DefaultAnomalyContamination}
+	if run != nil {
+		cfg.Contamination = run.Contamination
+		cfg.ModelVersion = run.ModelVersion
+		cfg.TrainedAt = &run.TrainedAt
+	}
+	return c.JSON(http.StatusOK, cfg)
+}
+
+// RetrainAnomalyModel trains a new anomaly model with the given contamination.
+// POST /api/ml/anomaly/retrain
+func (h *AnomalyHandler) RetrainAnomalyModel(c echo.Context) error {
+	var req struct {
+		Contamination *float64 `json:"contamination"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.Contamination == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "contamination is required"})
+	}
+	if v := *req.Contamination; v < entity.MinAnomalyContamination || v > entity.MaxAnomalyContamination {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf(
+			"contamination must be between %g and %g", entity.MinAnomalyContamination, entity.MaxAnomalyContamination)})
+	}
+
+	result, err := h.mlClient.TrainAnomalyModel(c.Request().Context(), *req.Contamination)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	h.recordTrain(c.Request().Context(), result)
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetAnomalyHistory lists every recorded anomaly training run, newest first.
+// GET /api/ml/anomaly/history
+func (h *AnomalyHandler) GetAnomalyHistory(c echo.Context) error {
+	runs, err := h.modelMetadata.ListAnomalyTrains(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, runs)
+}
+
+// GetSensitivity returns the stored anomaly sensitivity, or the one matching
+// the ML service's default contamination before any change.
+// GET /api/ml/anomaly/sensitivity
+func (h *AnomalyHandler) GetSensitivity(c echo.Context) error {
+	sensitivity, ok, err := h.sensitivityStore.Sensitivity(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if !ok {
+		sensitivity = entity.AnomalySensitivityLow
+	}
+	rate, _ := entity.AnomalySensitivityRate(sensitivity)
+	return c.JSON(http.StatusOK, entity.AnomalySensitivityConfig{Sensitivity: sensitivity, Rate: rate})
+}
+
+// UpdateSensitivity retrains the anomaly model with the contamination rate of
+// the chosen sensitivity, then drops the stored detections of other model
+// versions. Both this API and the ML service serve stored detections first,
+// so without that the new rate would never reach days already computed. The
+// ML service's scheduled retrains keep the latest model's rate.
+// PUT /api/ml/anomaly/sensitivity
+func (h *AnomalyHandler) UpdateSensitivity(c echo.Context) error {
+	var req struct {
+		Sensitivity string `json:"sensitivity"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	rate, ok := entity.AnomalySensitivityRate(req.Sensitivity)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf(
+			"sensitivity must be one of %q, %q or %q",
+			entity.AnomalySensitivityLow, entity.AnomalySensitivityNormal, entity.AnomalySensitivityHigh)})
+	}
+
+	ctx := c.Request().Context()
+	result, err := h.mlClient.TrainAnomalyModel(ctx, rate)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	h.recordTrain(ctx, result)
+	if err := h.sensitivityStore.SetSensitivity(ctx, req.Sensitivity); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	cleared, err := h.anomalyRepo.DeleteOtherModelVersions(ctx, result.ModelVersion)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, entity.AnomalySensitivityUpdate{
+		AnomalySensitivityConfig: entity.AnomalySensitivityConfig{Sensitivity: req.Sensitivity, Rate: rate},
+		ModelVersion:             result.ModelVersion,
+		ClearedDetections:        cleared,
+	})
+}
+
 func (h *AnomalyHandler) Register(g *echo.Group) {
 	g.GET("/anomaly", h.GetAnomaly)
 	g.GET("/anomaly/range", h.GetAnomalyRange)
 	g.GET("/anomaly/status", h.GetAnomalyStatus)
+	g.GET("/anomaly/model-versions", h.GetModelVersions)
+	g.GET("/anomaly/z-scores", h.GetZScoreHistory)
+	g.GET("/anomaly/drivers", h.GetDrivers)
 	g.POST("/anomaly/train", h.TrainAnomalyModel)
+	if h.modelMetadata != nil {
+		g.GET("/ml/anomaly/config", h.GetAnomalyConfig)
+		g.GET("/ml/anomaly/history", h.GetAnomalyHistory)
+		g.POST("/ml/anomaly/retrain", h.RetrainAnomalyModel, h.adminAuth)
+	}
+	if h.sensitivityStore != nil {
+		g.GET("/ml/anomaly/sensitivity", h.GetSensitivity)
+		g.PUT("/ml/anomaly/sensitivity", h.UpdateSensitivity, h.sensitivityAuth)
+	}
 }
@@ -3,8 +3,10 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -189,3 +191,439 @@ func TestAnomalyHandler_GetAnomalyRange_EmptyResult(t *testing.T) {
 		t.Errorf("expected 0 detections, got %d", len(resp))
 	}
 }
+
+func TestAnomalyHandler_GetModelVersions(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	repo := &mocks.MockAnomalyRepository{
+		ListRangeFunc: func(ctx context.Context, from, to time.Time) ([]entity.AnomalyDetection, error) {
+			return []entity.AnomalyDetection{
+				{Date: day(10), ModelVersion: "v1"},
+				{Date: day(11), ModelVersion: "v1"},
+				{Date: day(12), ModelVersion: "v2"},
+				{Date: day(13), ModelVersion: "v2"},
+				{Date: day(14), ModelVersion: "v2"},
+			}, nil
+		},
+	}
+
+	h := newAnomalyHandler(repo)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/anomaly/model-versions?from=2026-01-10&to=2026-01-14", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := h.GetModelVersions(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var resp []anomalyModelVersionUsage
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := []anomalyModelVersionUsage{
+		{ModelVersion: "v1", FirstDate: "2026-01-10", LastDate: "2026-01-11", Days: 2},
+		{ModelVersion: "v2", FirstDate: "2026-01-12", LastDate: "2026-01-14", Days: 3},
+	}
+	if len(resp) != len(want) {
+		t.Fatalf("expected %d versions, got %d", len(want), len(resp))
+	}
+	for i := range want {
+		if resp[i] != want[i] {
+			t.Errorf("versions[%d] = %+v, want %+v", i, resp[i], want[i])
+		}
+	}
+}
+
+func TestAnomalyHandler_GetModelVersions_MissingParams(t *testing.T) {
+	h := newAnomalyHandler(&mocks.MockAnomalyRepository{})
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/anomaly/model-versions?to=2026-01-14", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := h.GetModelVersions(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestAnomalyHandler_GetZScoreHistory(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	repo := &mocks.MockAnomalyRepository{
+		ListRangeFunc: func(ctx context.Context, from, to time.Time) ([]entity.AnomalyDetection, error) {
+			return []entity.AnomalyDetection{
+				{Date: day(10), NormalizedScore: 0.2, QualityAdjustedScore: 0.1},
+				{Date: day(11), NormalizedScore: 0.9, QualityAdjustedScore: 0.8, IsAnomaly: true},
+				{Date: day(12), NormalizedScore: 0.4, QualityAdjustedScore: 0.3},
+				{Date: day(13), NormalizedScore: 0.3, QualityAdjustedScore: 0.3},
+			}, nil
+		},
+	}
+
+	h := newAnomalyHandler(repo)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/anomaly/z-scores?from=2026-01-10&to=2026-01-13", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := h.GetZScoreHistory(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var resp anomalyZScoreHistory
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Points) != 4 {
+		t.Fatalf("expected 4 points, got %d", len(resp.Points))
+	}
+	if !resp.Points[1].Date.Equal(day(11)) || !resp.Points[1].IsAnomaly || resp.Points[1].QualityAdjustedScore != 0.8 {
+		t.Errorf("points[1] = %+v", resp.Points[1])
+	}
+	if resp.AnomalyRate != 0.25 {
+		t.Errorf("anomaly_rate = %v, want 0.25", resp.AnomalyRate)
+	}
+	if resp.MaxScore != 0.9 {
+		t.Errorf("max_score = %v, want 0.9", resp.MaxScore)
+	}
+}
+
+func TestAnomalyHandler_GetZScoreHistory_Empty(t *testing.T) {
+	repo := &mocks.MockAnomalyRepository{
+		ListRangeFunc: func(ctx context.Context, from, to time.Time) ([]entity.AnomalyDetection, error) {
+			return nil, nil
+		},
+	}
+
+	h := newAnomalyHandler(repo)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/anomaly/z-scores?from=2026-01-10&to=2026-01-13", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := h.GetZScoreHistory(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body := rec.Body.String(); body != `{"points":[],"anomaly_rate":0,"max_score":0}`+"\n" {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestAnomalyHandler_GetDrivers(t *testing.T) {
+	stored := `[
+		{"feature":"resting_hr","shap_value":0.10,"direction":"anomalous","description":"RHR up"},
+		{"feature":"hrv","shap_value":-0.40,"direction":"anomalous","description":"HRV down"},
+		{"feature":"steps","shap_value":0.05,"direction":"neutral","description":""},
+		{"feature":"sleep","shap_value":0.20,"direction":"anomalous","description":"Short sleep"},
+		{"feature":"spo2","shap_value":-0.15,"direction":"normal","description":""},
+		{"feature":"skin_temp","shap_value":0.10,"direction":"neutral","description":""}
+	]`
+	repo := &mocks.MockAnomalyRepository{
+		GetByDateFunc: func(ctx context.Context, date time.Time) (*entity.AnomalyDetection, error) {
+			return &entity.AnomalyDetection{Date: date, AnomalyScore: 0.3, TopDrivers: json.RawMessage(stored)}, nil
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/anomaly/drivers?date=2026-01-15", nil), rec)
+	if err := newAnomalyHandler(repo).GetDrivers(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var resp anomalyDriversResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"hrv", "sleep", "spo2", "resting_hr", "skin_temp"}
+	if len(resp.Drivers) != len(want) {
+		t.Fatalf("len(drivers) = %d, want %d", len(resp.Drivers), len(want))
+	}
+	for i, f := range want {
+		if resp.Drivers[i].Feature != f {
+			t.Errorf("drivers[%d] = %s, want %s", i, resp.Drivers[i].Feature, f)
+		}
+	}
+	// |SHAP| total is 1.0, so contributions equal the absolute SHAP values.
+	if c := resp.Drivers[0].Contribution; math.Abs(float64(c)-0.4) > 1e-6 {
+		t.Errorf("hrv contribution = %v, want 0.4", c)
+	}
+
+	wf := resp.Waterfall
+	if wf.Output != 0.3 || len(wf.Steps) != len(want) {
+		t.Fatalf("waterfall = %+v", wf)
+	}
+	end := wf.Baseline
+	for _, s := range wf.Steps {
+		end += s.ShapValue
+	}
+	if math.Abs(float64(end-wf.Output)) > 1e-6 {
+		t.Errorf("baseline + steps = %v, want output %v", end, wf.Output)
+	}
+}
+
+func TestAnomalyHandler_GetDrivers_NotFound(t *testing.T) {
+	repo := &mocks.MockAnomalyRepository{
+		GetByDateFunc: func(ctx context.Context, date time.Time) (*entity.AnomalyDetection, error) {
+			return nil, nil
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/anomaly/drivers?date=2026-01-15", nil), rec)
+	if err := newAnomalyHandler(repo).GetDrivers(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestRankAnomalyDrivers_Empty(t *testing.T) {
+	for _, raw := range []string{"", "null", "[]"} {
+		drivers, err := rankAnomalyDrivers(json.RawMessage(raw))
+		if err != nil || drivers == nil || len(drivers) != 0 {
+			t.Errorf("rankAnomalyDrivers(%q) = %v, %v, want empty slice", raw, drivers, err)
+		}
+	}
+	if _, err := rankAnomalyDrivers(json.RawMessage(`{"bad":`)); err == nil {
+		t.Error("rankAnomalyDrivers() should fail on malformed JSON")
+	}
+}
+
+func TestAnomalyHandler_GetAnomalyConfig(t *testing.T) {
+	trainedAt := time.Date(2026, 4, 18, 3, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		run         *entity.AnomalyTrainRun
+		wantContam  float64
+		wantVersion string
+	}{
+		{"no runs", nil, entity.DefaultAnomalyContamination, ""},
+		{"latest run", &entity.AnomalyTrainRun{TrainedAt: trainedAt, AnomalyTrainResult: entity.AnomalyTrainResult{
+			ModelVersion: "v7", Contamination: 0.05,
+		}}, 0.05, "v7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newAnomalyHandler(&mocks.MockAnomalyRepository{}).WithModelMetadata(&mocks.MockModelMetadataRepository{
+				LatestAnomalyTrainFunc: func(_ context.Context) (*entity.AnomalyTrainRun, error) { return tt.run, nil },
+			}, nil)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/ml/anomaly/config", nil)
+			rec := httptest.NewRecorder()
+			if err := h.GetAnomalyConfig(e.NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			var got entity.AnomalyModelConfig
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Contamination != tt.wantContam || got.ModelVersion != tt.wantVersion {
+				t.Errorf("config = %+v, want contamination %v version %q", got, tt.wantContam, tt.wantVersion)
+			}
+			if (got.TrainedAt != nil) != (tt.run != nil) {
+				t.Errorf("TrainedAt = %v, want set only with a run", got.TrainedAt)
+			}
+		})
+	}
+}
+
+func TestAnomalyHandler_RetrainAnomalyModel(t *testing.T) {
+	var gotBody map[string]float64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/anomaly/train" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model_version":"v8","training_days_used":90,"contamination":0.05,"pot_threshold":0.7,"feature_names":["hrv"],"message":"ok"}`))
+	}))
+	defer srv.Close()
+
+	var saved *entity.AnomalyTrainResult
+	h := NewAnomalyHandler(newTestMLClient(srv.URL), &mocks.MockAnomalyRepository{}).
+		WithModelMetadata(&mocks.MockModelMetadataRepository{
+			SaveAnomalyTrainFunc: func(_ context.Context, r *entity.AnomalyTrainResult) error {
+				saved = r
+				return nil
+			},
+		}, nil)
+	e := echo.New()
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/ml/anomaly/retrain", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		if err := h.RetrainAnomalyModel(e.NewContext(req, rec)); err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+
+	for _, body := range []string{`{}`, `{"contamination":0}`, `{"contamination":0.5}`, `not json`} {
+		if rec := post(body); rec.Code != http.StatusBadRequest {
+			t.Errorf("body %s: status = %d, want 400", body, rec.Code)
+		}
+	}
+	if saved != nil {
+		t.Fatal("rejected requests should not train")
+	}
+
+	rec := post(`{"contamination":0.05}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if gotBody["contamination"] != 0.05 {
+		t.Errorf("ML request contamination = %v, want 0.05", gotBody["contamination"])
+	}
+	if saved == nil || saved.ModelVersion != "v8" || saved.Contamination != 0.05 {
+		t.Errorf("saved = %+v, want v8 with contamination 0.05", saved)
+	}
+}
+
+func TestAnomalyHandler_GetAnomalyHistory(t *testing.T) {
+	runs := []entity.AnomalyTrainRun{
+		{ID: 2, AnomalyTrainResult: entity.AnomalyTrainResult{ModelVersion: "v8", Contamination: 0.05}},
+		{ID: 1, AnomalyTrainResult: entity.AnomalyTrainResult{ModelVersion: "v7", Contamination: 0.02}},
+	}
+	h := newAnomalyHandler(&mocks.MockAnomalyRepository{}).WithModelMetadata(&mocks.MockModelMetadataRepository{
+		ListAnomalyTrainsFunc: func(_ context.Context) ([]entity.AnomalyTrainRun, error) { return runs, nil },
+	}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/ml/anomaly/history", nil)
+	rec := httptest.NewRecorder()
+	if err := h.GetAnomalyHistory(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	var got []entity.AnomalyTrainRun
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ModelVersion != "v8" || got[1].Contamination != 0.02 {
+		t.Errorf("history = %+v", got)
+	}
+}
+
+type stubSensitivityStore struct{ stored string }
+
+func (s *stubSensitivityStore) Sensitivity(context.Context) (string, bool, error) {
+	return s.stored, s.stored != "", nil
+}
+
+func (s *stubSensitivityStore) SetSensitivity(_ context.Context, sensitivity string) error {
+	s.stored = sensitivity
+	return nil
+}
+
+func TestAnomalyHandler_UpdateSensitivity(t *testing.T) {
+	var gotBody map[string]float64
+	detects := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/anomaly/train" {
+			detects++
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model_version":"v9","training_days_used":90,"contamination":0.1,"pot_threshold":0.7,"feature_names":["hrv"],"message":"ok"}`))
+	}))
+	defer srv.Close()
+
+	store := &stubSensitivityStore{}
+	var keptVersion string
+	repo := &mocks.MockAnomalyRepository{
+		DeleteOtherModelVersionsFunc: func(_ context.Context, modelVersion string) (int64, error) {
+			keptVersion = modelVersion
+			return 12, nil
+		},
+	}
+	h := NewAnomalyHandler(newTestMLClient(srv.URL), repo).
+		WithSensitivity(store, func(next echo.HandlerFunc) echo.HandlerFunc { return next })
+	e := echo.New()
+	h.Register(e.Group("/api"))
+
+	getSensitivity := func() entity.AnomalySensitivityConfig {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/ml/anomaly/sensitivity", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET status = %d, want 200: %s", rec.Code, rec.Body.String())
+		}
+		var cfg entity.AnomalySensitivityConfig
+		if err := json.Unmarshal(rec.Body.Bytes(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+	if got := getSensitivity(); got != (entity.AnomalySensitivityConfig{Sensitivity: "low", Rate: 0.02}) {
+		t.Errorf("GET before any change = %+v, want the ML default (low, 0.02)", got)
+	}
+
+	put := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/ml/anomaly/sensitivity", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, body := range []string{`{}`, `{"sensitivity":"extreme"}`, `not json`} {
+		if rec := put(body); rec.Code != http.StatusBadRequest {
+			t.Errorf("body %s: status = %d, want 400", body, rec.Code)
+		}
+	}
+	if store.stored != "" || gotBody != nil || keptVersion != "" {
+		t.Fatal("rejected requests should neither store, retrain nor clear")
+	}
+
+	rec := put(`{"sensitivity":"high"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var got entity.AnomalySensitivityUpdate
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := entity.AnomalySensitivityUpdate{
+		AnomalySensitivityConfig: entity.AnomalySensitivityConfig{Sensitivity: "high", Rate: 0.1},
+		ModelVersion:             "v9",
+		ClearedDetections:        12,
+	}
+	if got != want {
+		t.Errorf("response = %+v, want %+v", got, want)
+	}
+	if keptVersion != "v9" {
+		t.Errorf("cleared detections except model version %q, want v9", keptVersion)
+	}
+	if got := getSensitivity(); got != (entity.AnomalySensitivityConfig{Sensitivity: "high", Rate: 0.1}) {
+		t.Errorf("GET after change = %+v, want high, 0.1", got)
+	}
+	if gotBody["contamination"] != 0.1 {
+		t.Errorf("trained with contamination %v, want 0.1", gotBody["contamination"])
+	}
+	if store.stored != "high" {
+		t.Errorf("stored = %q, want high", store.stored)
+	}
+	if detects != 0 {
+		t.Errorf("made %d other ML calls, want only the retrain", detects)
+	}
+}
@@ -1,20 +1,39 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"vitametron/api/application"
 	"vitametron/api/domain/entity"
 	"vitametron/api/domain/port"
 )
 
+// maxSummaryRangeDays caps how far To may be from From in one /biometrics/range call.
+const maxSummaryRangeDays = 31
+
+// Default and maximum ?days= of /biometrics/completeness-sparkline.
+const (
+	defaultSparklineDays = 30
+	maxSparklineDays     = 365
+)
+
 type BiometricsHandler struct {
 	summaries   port.DailySummaryRepository
 	heartRates  port.HeartRateRepository
 	sleepStages port.SleepStageRepository
 	quality     port.DataQualityRepository
+	hrvSamples  port.HRVSampleRepository
+	activeZones port.ActiveZoneSampleRepository
+	tokenHealth port.TokenHealthChecker
+	similarDays *application.SimilarDaysUseCase
+
+	staleAfter time.Duration
 }
 
 func NewBiometricsHandler(
@@ -31,6 +50,38 @@ func NewBiometricsHandler(
 	}
 }
 
+// WithHRVSamples enables GET /biometrics/hrv/intraday.
+func (h *BiometricsHandler) WithHRVSamples(repo port.HRVSampleRepository) *BiometricsHandler {
+	h.hrvSamples = repo
+	return h
+}
+
+// WithActiveZones enables GET /biometrics/active-zones/intraday.
+func (h *BiometricsHandler) WithActiveZones(repo port.ActiveZoneSampleRepository) *BiometricsHandler {
+	h.activeZones = repo
+	return h
+}
+
+// WithSimilarDays enables GET /biometrics/similar.
+func (h *BiometricsHandler) WithSimilarDays(uc *application.SimilarDaysUseCase) *BiometricsHandler {
+	h.similarDays = uc
+	return h
+}
+
+// WithTokenWarning flags biometrics responses with a warning while the
+// latest refreshed Fitbit token could not be saved.
+func (h *BiometricsHandler) WithTokenWarning(checker port.TokenHealthChecker) *BiometricsHandler {
+	h.tokenHealth = checker
+	return h
+}
+
+// WithFreshnessCheck flags /biometrics responses whose data was last synced
+// more than staleAfter ago. See dataFreshness.
+func (h *BiometricsHandler) WithFreshnessCheck(staleAfter time.Duration) *BiometricsHandler {
+	h.staleAfter = staleAfter
+	return h
+}
+
 func (h *BiometricsHandler) GetDailySummary(c echo.Context) error {
 	dateStr := c.QueryParam("date")
 	var date time.Time
@@ -52,47 +103,69 @@ func (h *BiometricsHandler) GetDailySummary(c echo.Context) error {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "no data for date"})
 	}
 
-	return c.JSON(http.StatusOK, summary)
+	return jsonWithETag(c, summary)
 }
 
 func (h *BiometricsHandler) GetDailySummaryRange(c echo.Context) error {
 	fromStr := c.QueryParam("from")
 	toStr := c.QueryParam("to")
 
-	from, err := parseDate(fromStr)
+	loc, err := requestLocation(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	// daily_summaries is keyed by calendar date, and pgx encodes a DATE from
+	// the time's own location, so the bounds stay in loc rather than UTC.
+	from, err := parseDateIn(fromStr, loc)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
 	}
-	to, err := parseDate(toStr)
+	to, err := parseDateIn(toStr, loc)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
 	}
 	if to.Before(from) {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
 	}
-	if to.Sub(from).Hours() > 31*24 {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 31 days"})
+
+	ctx := c.Request().Context()
+	total, err := h.summaries.CountRange(ctx, from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	summaries, err := h.summaries.ListRange(c.Request().Context(), from, to)
+	// Items are capped at 31 days past From; the caller pages on by requesting from the day after To.
+	result := entity.DailySummaryRangeResult{Total: total, From: from, To: to}
+	if limit := from.AddDate(0, 0, maxSummaryRangeDays); to.After(limit) {
+		result.To = limit
+		result.HasMore = true
+	}
+
+	summaries, err := h.summaries.ListRange(ctx, result.From, result.To)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 	if summaries == nil {
 		summaries = []entity.DailySummary{}
 	}
-	return c.JSON(http.StatusOK, summaries)
+	result.Items = summaries
+	return c.JSON(http.StatusOK, result)
 }
 
 func (h *BiometricsHandler) GetHeartRateIntraday(c echo.Context) error {
 	dateStr := c.QueryParam("date")
-	date, err := parseDate(dateStr)
+	loc, err := requestLocation(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	date, err := parseDateIn(dateStr, loc)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
 	}
 
-	from := date
-	to := date.AddDate(0, 0, 1)
+	// AddDate in loc keeps DST days at their real 23 or 25 hours.
+	from := date.UTC()
+	to := date.AddDate(0, 0, 1).UTC()
 
 	samples, err := h.heartRates.ListRange(c.Request().Context(), from, to)
 	if err != nil {
@@ -104,6 +177,112 @@ func (h *BiometricsHandler) GetHeartRateIntraday(c echo.Context) error {
 	return c.JSON(http.StatusOK, samples)
 }
 
+// GetHRVIntraday returns the 5-minute HRV segments of date's main sleep.
+// Segments are only recorded asleep, and a night that starts before
+// midnight belongs to the day it ends on, so the window is the main sleep
+// session rather than the calendar day. Without sleep data the calendar day
+// is used.
+// GET /api/biometrics/hrv/intraday?date=2025-01-15
+func (h *BiometricsHandler) GetHRVIntraday(c echo.Context) error {
+	loc, err := requestLocation(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	date, err := parseDateIn(c.QueryParam("date"), loc)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
+	}
+
+	ctx := c.Request().Context()
+	stages, _, err := h.loadMainSleepStages(ctx, date)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	from, to := date.UTC(), date.AddDate(0, 0, 1).UTC()
+	if start, end, ok := sleepSessionBounds(stages); ok {
+		from, to = start.UTC(), end.UTC()
+	}
+
+	samples, err := h.hrvSamples.ListRange(ctx, from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if samples == nil {
+		samples = []entity.HRVSample{}
+	}
+	return c.JSON(http.StatusOK, samples)
+}
+
+// sleepSessionBounds returns the start of the first stage and the end of the
+// last one; ok is false when there are no stages.
+func sleepSessionBounds(stages []entity.SleepStage) (start, end time.Time, ok bool) {
+	for i, s := range stages {
+		stageEnd := s.Time.Add(time.Duration(s.Seconds) * time.Second)
+		if i == 0 || s.Time.Before(start) {
+			start = s.Time
+		}
+		if i == 0 || stageEnd.After(end) {
+			end = stageEnd
+		}
+	}
+	return start, end, len(stages) > 0
+}
+
+// GetActiveZonesIntraday returns the day's 1-minute active zone samples, so
+// users can see when they reached each heart rate zone.
+// GET /api/biometrics/active-zones/intraday?date=2025-01-15
+func (h *BiometricsHandler) GetActiveZonesIntraday(c echo.Context) error {
+	loc, err := requestLocation(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	date, err := parseDateIn(c.QueryParam("date"), loc)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
+	}
+
+	samples, err := h.activeZones.ListRange(c.Request().Context(), date.UTC(), date.AddDate(0, 0, 1).UTC())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if samples == nil {
+		samples = []entity.ActiveZoneSample{}
+	}
+	return c.JSON(http.StatusOK, samples)
+}
+
+// GetHeartRateIntradayAggregated returns the day's heart rate averaged into
+// N-minute buckets (default 5) for lighter chart payloads.
+// GET /api/heartrate/intraday/aggregated?date=2025-01-15&bucket=5
+func (h *BiometricsHandler) GetHeartRateIntradayAggregated(c echo.Context) error {
+	loc, err := requestLocation(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	date, err := parseDateIn(c.QueryParam("date"), loc)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
+	}
+
+	bucket := 5
+	if b := c.QueryParam("bucket"); b != "" {
+		n, err := strconv.Atoi(b)
+		if err != nil || n < 1 || n > 60 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "bucket must be between 1 and 60 minutes"})
+		}
+		bucket = n
+	}
+
+	buckets, err := h.heartRates.ListRangeAggregated(c.Request().Context(), date.UTC(), date.AddDate(0, 0, 1).UTC(), bucket)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if buckets == nil {
+		buckets = []entity.HeartRateBucket{}
+	}
+	return c.JSON(http.StatusOK, buckets)
+}
+
 func (h *BiometricsHandler) GetSleepStages(c echo.Context) error {
 	dateStr := c.QueryParam("date")
 	date, err := parseDate(dateStr)
@@ -111,8 +290,19 @@ func (h *BiometricsHandler) GetSleepStages(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
 	}
 
-	ctx := c.Request().Context()
+	stages, _, err := h.loadMainSleepStages(c.Request().Context(), date)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if stages == nil {
+		stages = []entity.SleepStage{}
+	}
+	return c.JSON(http.StatusOK, stages)
+}
 
+// loadMainSleepStages returns the main sleep session's stages for date along
+// with the day's summary (nil if none).
+func (h *BiometricsHandler) loadMainSleepStages(ctx context.Context, date time.Time) ([]entity.SleepStage, *entity.DailySummary, error) {
 	// Use DailySummary's SleepStart/SleepEnd for accurate session boundaries
 	// to avoid mixing stages from two different sleep sessions on calendar-day boundaries.
 	var stages []entity.SleepStage
@@ -126,19 +316,45 @@ func (h *BiometricsHandler) GetSleepStages(c echo.Context) error {
 		to := date.Add(14 * time.Hour)    // current day 14:00
 		stages, err = h.sleepStages.ListByTimeRange(ctx, from, to)
 	}
+	if err != nil {
+		return nil, nil, err
+	}
 	// Filter to main session on both paths — guards against dual-source duplicates
 	// (Fitbit sync + Health Connect import) where LogID differs.
-	if err == nil {
-		stages = filterMainSleepSession(stages)
-		stages = deduplicateStages(stages)
+	stages = filterMainSleepSession(stages)
+	stages = deduplicateStages(stages)
+	return stages, summary, nil
+}
+
+// GetSleepInertia estimates grogginess on waking from the stage the main
+// sleep session ended in.
+// GET /api/sleep/inertia?date=2025-01-15
+func (h *BiometricsHandler) GetSleepInertia(c echo.Context) error {
+	date, err := parseDate(c.QueryParam("date"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
 	}
+
+	stages, summary, err := h.loadMainSleepStages(c.Request().Context(), date)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if stages == nil {
-		stages = []entity.SleepStage{}
+	if len(stages) == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "no sleep data for date"})
 	}
-	return c.JSON(http.StatusOK, stages)
+
+	var wakeTime time.Time
+	if summary != nil && summary.SleepEnd != nil {
+		wakeTime = *summary.SleepEnd
+	} else {
+		for _, s := range stages {
+			if end := s.Time.Add(time.Duration(s.Seconds) * time.Second); end.After(wakeTime) {
+				wakeTime = end
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, application.EstimateSleepInertia(stages, wakeTime))
 }
 
 func (h *BiometricsHandler) GetDataQuality(c echo.Context) error {
@@ -165,22 +381,65 @@ func (h *BiometricsHandler) GetDataQuality(c echo.Context) error {
 	return c.JSON(http.StatusOK, quality)
 }
 
+// GetCompletenessSparkline returns the completeness (0-1, as stored) of each
+// of the last ?days= days up to today, oldest first, for small chart widgets.
+// Days without a quality record are 0.
+// GET /api/biometrics/completeness-sparkline?days=30
+func (h *BiometricsHandler) GetCompletenessSparkline(c echo.Context) error {
+	days := defaultSparklineDays
+	if s := c.QueryParam("days"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxSparklineDays {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("days must be between 1 and %d", maxSparklineDays),
+			})
+		}
+		days = n
+	}
+	loc, err := requestLocation(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	to, _ := parseDateIn(todayParam, loc)
+	from := to.AddDate(0, 0, -(days - 1))
+	qualities, err := h.quality.ListRange(c.Request().Context(), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	byDay := make(map[string]float32, len(qualities))
+	for _, q := range qualities {
+		byDay[q.Date.Format("2006-01-02")] = q.CompletenessPct
+	}
+	sparkline := make([]float32, days)
+	for i := range sparkline {
+		sparkline[i] = byDay[from.AddDate(0, 0, i).Format("2006-01-02")]
+	}
+	return c.JSON(http.StatusOK, sparkline)
+}
+
 func (h *BiometricsHandler) GetDataQualityRange(c echo.Context) error {
 	fromStr := c.QueryParam("from")
 	toStr := c.QueryParam("to")
 
-	from, err := parseDate(fromStr)
+	loc, err := requestLocation(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	// Like daily_summaries, daily_data_quality is keyed by calendar date.
+	from, err := parseDateIn(fromStr, loc)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
 	}
-	to, err := parseDate(toStr)
+	to, err := parseDateIn(toStr, loc)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
 	}
 	if to.Before(from) {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
 	}
-	if to.Sub(from).Hours() > 31*24 {
+	if to.After(from.AddDate(0, 0, 31)) {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 31 days"})
 	}
 
@@ -194,24 +453,106 @@ func (h *BiometricsHandler) GetDataQualityRange(c echo.Context) error {
 	return c.JSON(http.StatusOK, qualities)
 }
 
+// GetQualityAlerts lists days whose SpO2 dipped below the alert threshold or
+// that failed a plausibility check.
+// GET /api/quality/alerts?from=2025-01-01&to=2025-01-31
+func (h *BiometricsHandler) GetQualityAlerts(c echo.Context) error {
+	from, err := parseDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+	}
+	to, err := parseDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+
+	alerts, err := h.quality.ListAlerts(c.Request().Context(), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if alerts == nil {
+		alerts = []entity.DataQuality{}
+	}
+	return c.JSON(http.StatusOK, alerts)
+}
+
+// GetQualitySummary aggregates data quality over a range for monitoring.
+// GET /api/quality/summary?from=2025-01-01&to=2025-03-31
+func (h *BiometricsHandler) GetQualitySummary(c echo.Context) error {
+	from, err := parseDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+	}
+	to, err := parseDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 1 year"})
+	}
+
+	qualities, err := h.quality.ListRange(c.Request().Context(), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, application.SummarizeDataQuality(qualities, from, to))
+}
+
+// GetQualityTrend returns daily wear time, completeness and confidence with
+// 7-day rolling averages, to spot periods of degraded data collection.
+// GET /api/quality/trend?from=2025-01-01&to=2025-03-31
+func (h *BiometricsHandler) GetQualityTrend(c echo.Context) error {
+	from, err := parseDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+	}
+	to, err := parseDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 1 year"})
+	}
+
+	qualities, err := h.quality.ListRange(c.Request().Context(), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, application.BuildDataQualityTrend(qualities, from, to))
+}
+
 // filterMainSleepSession picks stages belonging to the LogID with the most
-// total seconds, discarding nap or secondary sessions.
+// total seconds, discarding nap or secondary sessions. On a tie, a
+// Fitbit-sourced session wins over a Health Connect one.
 func filterMainSleepSession(stages []entity.SleepStage) []entity.SleepStage {
 	if len(stages) == 0 {
 		return stages
 	}
 
-	// Sum total seconds per LogID.
+	// Sum total seconds per LogID and note whether it came from Fitbit.
 	totals := make(map[int64]int)
+	fitbit := make(map[int64]bool)
 	for _, s := range stages {
 		totals[s.LogID] += s.Seconds
+		if s.Source == entity.SleepStageSourceFitbit {
+			fitbit[s.LogID] = true
+		}
 	}
 
 	// Find the LogID with the most sleep.
 	var bestID int64
 	var bestSec int
 	for id, sec := range totals {
-		if sec > bestSec {
+		if sec > bestSec || (sec == bestSec && fitbit[id] && !fitbit[bestID]) {
 			bestID = id
 			bestSec = sec
 		}
@@ -245,11 +586,60 @@ func deduplicateStages(stages []entity.SleepStage) []entity.SleepStage {
 	return result
 }
 
+// GetFeverCandidate reports whether the day's skin temperature is elevated
+// against the personal 30-day baseline.
+// GET /api/health/fever-candidate?date=2025-01-15
+func (h *BiometricsHandler) GetFeverCandidate(c echo.Context) error {
+	dateStr := c.QueryParam("date")
+	if dateStr == "" {
+		dateStr = time.Now().In(jst).Format("2006-01-02")
+	}
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
+	}
+
+	candidate, threshold, err := application.DetectFeverCandidate(c.Request().Context(), date, h.summaries)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"date":            date.Format("2006-01-02"),
+		"fever_candidate": candidate,
+		"threshold":       threshold,
+	})
+}
+
 func (h *BiometricsHandler) Register(g *echo.Group) {
-	g.GET("/biometrics", h.GetDailySummary)
-	g.GET("/biometrics/range", h.GetDailySummaryRange)
-	g.GET("/biometrics/quality", h.GetDataQuality)
-	g.GET("/biometrics/quality/range", h.GetDataQualityRange)
-	g.GET("/heartrate/intraday", h.GetHeartRateIntraday)
-	g.GET("/sleep/stages", h.GetSleepStages)
+	var mw []echo.MiddlewareFunc
+	if h.tokenHealth != nil {
+		mw = append(mw, tokenWarning(h.tokenHealth))
+	}
+	bioMW := append([]echo.MiddlewareFunc{}, mw...)
+	if h.staleAfter > 0 {
+		bioMW = append(bioMW, dataFreshness(h.staleAfter))
+	}
+	g.GET("/biometrics", h.GetDailySummary, bioMW...)
+	g.GET("/biometrics/range", h.GetDailySummaryRange, bioMW...)
+	g.GET("/biometrics/quality", h.GetDataQuality, bioMW...)
+	g.GET("/biometrics/quality/range", h.GetDataQualityRange, bioMW...)
+	g.GET("/biometrics/completeness-sparkline", h.GetCompletenessSparkline, bioMW...)
+	g.GET("/quality/alerts", h.GetQualityAlerts, mw...)
+	g.GET("/quality/summary", h.GetQualitySummary, mw...)
+	g.GET("/quality/trend", h.GetQualityTrend, mw...)
+	if h.hrvSamples != nil {
+		g.GET("/biometrics/hrv/intraday", h.GetHRVIntraday, bioMW...)
+	}
+	if h.activeZones != nil {
+		g.GET("/biometrics/active-zones/intraday", h.GetActiveZonesIntraday, bioMW...)
+	}
+	if h.similarDays != nil {
+		g.GET("/biometrics/similar", h.GetSimilarDays, bioMW...)
+	}
+	g.GET("/heartrate/intraday", h.GetHeartRateIntraday, mw...)
+	g.GET("/heartrate/intraday/aggregated", h.GetHeartRateIntradayAggregated, mw...)
+	g.GET("/sleep/stages", h.GetSleepStages, mw...)
+	g.GET("/sleep/inertia", h.GetSleepInertia, mw...)
+	g.GET("/health/fever-candidate", h.GetFeverCandidate, mw...)
 }
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -20,31 +22,83 @@ func countSubstring(s, sub string) int {
 type stubDailySummaryRepo struct {
 	summary   *entity.DailySummary
 	summaries []entity.DailySummary
+	count     int
 	err       error
+
+	listFrom, listTo time.Time
+	gotDate          time.Time
 }
 
 func (s *stubDailySummaryRepo) Upsert(_ context.Context, _ *entity.DailySummary) error {
 	return nil
 }
 
-func (s *stubDailySummaryRepo) GetByDate(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+func (s *stubDailySummaryRepo) GetByDate(_ context.Context, date time.Time) (*entity.DailySummary, error) {
+	s.gotDate = date
 	return s.summary, s.err
 }
 
-func (s *stubDailySummaryRepo) ListRange(_ context.Context, _, _ time.Time) ([]entity.DailySummary, error) {
+func (s *stubDailySummaryRepo) ListRange(_ context.Context, from, to time.Time) ([]entity.DailySummary, error) {
+	s.listFrom, s.listTo = from, to
 	return s.summaries, s.err
 }
 
+func (s *stubDailySummaryRepo) CountRange(_ context.Context, _, _ time.Time) (int, error) {
+	return s.count, s.err
+}
+
 type stubHeartRateRepo struct {
 	samples []entity.HeartRateSample
+	buckets []entity.HeartRateBucket
 	err     error
+
+	gotBucketMin   int
+	gotFrom, gotTo time.Time
 }
 
 func (s *stubHeartRateRepo) BulkUpsert(_ context.Context, _ []entity.HeartRateSample) error {
 	return nil
 }
 
-func (s *stubHeartRateRepo) ListRange(_ context.Context, _, _ time.Time) ([]entity.HeartRateSample, error) {
+func (s *stubHeartRateRepo) ListRange(_ context.Context, from, to time.Time) ([]entity.HeartRateSample, error) {
+	s.gotFrom, s.gotTo = from, to
+	return s.samples, s.err
+}
+
+func (s *stubHeartRateRepo) ListRangeAggregated(_ context.Context, _, _ time.Time, bucketMin int) ([]entity.HeartRateBucket, error) {
+	s.gotBucketMin = bucketMin
+	return s.buckets, s.err
+}
+
+type stubHRVSampleRepo struct {
+	samples []entity.HRVSample
+	err     error
+
+	gotFrom, gotTo time.Time
+}
+
+func (s *stubHRVSampleRepo) BulkUpsert(_ context.Context, _ []entity.HRVSample) error {
+	return nil
+}
+
+func (s *stubHRVSampleRepo) ListRange(_ context.Context, from, to time.Time) ([]entity.HRVSample, error) {
+	s.gotFrom, s.gotTo = from, to
+	return s.samples, s.err
+}
+
+type stubActiveZoneSampleRepo struct {
+	samples []entity.ActiveZoneSample
+	err     error
+
+	gotFrom, gotTo time.Time
+}
+
+func (s *stubActiveZoneSampleRepo) BulkUpsert(_ context.Context, _ []entity.ActiveZoneSample) error {
+	return nil
+}
+
+func (s *stubActiveZoneSampleRepo) ListRange(_ context.Context, from, to time.Time) ([]entity.ActiveZoneSample, error) {
+	s.gotFrom, s.gotTo = from, to
 	return s.samples, s.err
 }
 
@@ -72,6 +126,7 @@ func (s *stubSleepStageRepo) ListByTimeRange(_ context.Context, _, _ time.Time)
 type stubDataQualityRepo struct {
 	quality   *entity.DataQuality
 	qualities []entity.DataQuality
+	alerts    []entity.DataQuality
 	err       error
 }
 
@@ -85,6 +140,10 @@ func (s *stubDataQualityRepo) ListRange(_ context.Context, _, _ time.Time) ([]en
 	return s.qualities, s.err
 }
 
+func (s *stubDataQualityRepo) ListAlerts(_ context.Context, _, _ time.Time) ([]entity.DataQuality, error) {
+	return s.alerts, s.err
+}
+
 func (s *stubDataQualityRepo) CountValidDays(_ context.Context, _ time.Time, _ int) (int, error) {
 	return 0, nil
 }
@@ -100,7 +159,7 @@ func TestBiometricsHandler_GetDailySummary(t *testing.T) {
 	c := e.NewContext(req, rec)
 
 	h := newHandler(&stubDailySummaryRepo{
-		summary: &entity.DailySummary{Provider: "fitbit"},
+		summary: &entity.DailySummary{Providers: []string{"fitbit"}},
 	})
 	if err := h.GetDailySummary(c); err != nil {
 		t.Fatal(err)
@@ -111,6 +170,48 @@ func TestBiometricsHandler_GetDailySummary(t *testing.T) {
 	}
 }
 
+func TestBiometricsHandler_GetDailySummary_Today(t *testing.T) {
+	get := func(date string) time.Time {
+		t.Helper()
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/biometrics?date="+date, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		repo := &stubDailySummaryRepo{summary: &entity.DailySummary{}}
+		if err := newHandler(repo).GetDailySummary(c); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("date=%s: status = %d, want %d", date, rec.Code, http.StatusOK)
+		}
+		return repo.gotDate
+	}
+
+	// Bracket the request so a JST date rollover mid-test cannot fail it.
+	before := get(time.Now().In(jst).Format("2006-01-02"))
+	today := get("today")
+	after := get(time.Now().In(jst).Format("2006-01-02"))
+	if !today.Equal(before) && !today.Equal(after) {
+		t.Errorf("date=today resolved to %v, want %v", today, after)
+	}
+}
+
+func TestParseDateIn_Today(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*3600)
+	got, err := parseDateIn("today", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().In(loc)
+	if got.Location() != loc || got.Hour() != 0 || got.Minute() != 0 {
+		t.Errorf("parseDateIn(today) = %v, want midnight in %v", got, loc)
+	}
+	if d := now.Sub(got); d < 0 || d >= 25*time.Hour {
+		t.Errorf("parseDateIn(today) = %v, want the date of %v", got, now)
+	}
+}
+
 func TestBiometricsHandler_GetDailySummary_NotFound(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/biometrics?date=2025-06-15", nil)
@@ -134,7 +235,8 @@ func TestBiometricsHandler_GetDailySummaryRange_OK(t *testing.T) {
 	c := e.NewContext(req, rec)
 
 	h := newHandler(&stubDailySummaryRepo{
-		summaries: []entity.DailySummary{{Provider: "fitbit"}, {Provider: "fitbit"}},
+		summaries: []entity.DailySummary{{Providers: []string{"fitbit"}}, {Providers: []string{"fitbit"}}},
+		count:     2,
 	})
 	if err := h.GetDailySummaryRange(c); err != nil {
 		t.Fatal(err)
@@ -142,6 +244,50 @@ func TestBiometricsHandler_GetDailySummaryRange_OK(t *testing.T) {
 	if rec.Code != http.StatusOK {
 		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
 	}
+
+	var body entity.DailySummaryRangeResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Items) != 2 || body.Total != 2 {
+		t.Errorf("items = %d, total = %d, want 2 and 2", len(body.Items), body.Total)
+	}
+	if body.HasMore {
+		t.Error("has_more = true, want false")
+	}
+}
+
+func TestBiometricsHandler_GetDailySummaryRange_Capped(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/biometrics/range?from=2025-01-01&to=2025-06-30", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	repo := &stubDailySummaryRepo{summaries: []entity.DailySummary{{Providers: []string{"fitbit"}}}, count: 150}
+	h := newHandler(repo)
+	if err := h.GetDailySummaryRange(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var body entity.DailySummaryRangeResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if !body.HasMore {
+		t.Error("has_more = false, want true")
+	}
+	if body.Total != 150 {
+		t.Errorf("total = %d, want 150", body.Total)
+	}
+	if got := repo.listTo.Format("2006-01-02"); got != "2025-02-01" {
+		t.Errorf("ListRange to = %s, want 2025-02-01", got)
+	}
+	if got := body.To.In(jst).Format("2006-01-02"); got != "2025-02-01" {
+		t.Errorf("to = %s, want 2025-02-01", got)
+	}
 }
 
 func TestBiometricsHandler_GetDailySummaryRange_BadFrom(t *testing.T) {
@@ -202,6 +348,9 @@ func TestBiometricsHandler_GetDailySummaryRange_Empty(t *testing.T) {
 	if rec.Code != http.StatusOK {
 		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
 	}
+	if !strings.Contains(rec.Body.String(), `"items":[]`) {
+		t.Errorf("body = %s, want empty items array", rec.Body.String())
+	}
 }
 
 func TestBiometricsHandler_GetHeartRateIntraday_OK(t *testing.T) {
@@ -362,6 +511,38 @@ func TestFilterMainSleepSession(t *testing.T) {
 	}
 }
 
+func TestFilterMainSleepSession_TiePrefersFitbit(t *testing.T) {
+	stages := []entity.SleepStage{
+		{Stage: "deep", Seconds: 3600, LogID: 0, Source: entity.SleepStageSourceHealthConnect},
+		{Stage: "light", Seconds: 3600, LogID: 0, Source: entity.SleepStageSourceHealthConnect},
+		{Stage: "deep", Seconds: 3600, LogID: 42, Source: entity.SleepStageSourceFitbit},
+		{Stage: "light", Seconds: 3600, LogID: 42, Source: entity.SleepStageSourceFitbit},
+	}
+	// Map iteration order is random, so repeat to cover both visit orders.
+	for i := 0; i < 20; i++ {
+		filtered := filterMainSleepSession(stages)
+		if len(filtered) != 2 {
+			t.Fatalf("expected 2 stages, got %d", len(filtered))
+		}
+		for _, s := range filtered {
+			if s.Source != entity.SleepStageSourceFitbit {
+				t.Fatalf("expected fitbit stages, got %q (LogID %d)", s.Source, s.LogID)
+			}
+		}
+	}
+}
+
+func TestFilterMainSleepSession_LongerHCWins(t *testing.T) {
+	stages := []entity.SleepStage{
+		{Stage: "deep", Seconds: 7200, LogID: 0, Source: entity.SleepStageSourceHealthConnect},
+		{Stage: "deep", Seconds: 3600, LogID: 42, Source: entity.SleepStageSourceFitbit},
+	}
+	filtered := filterMainSleepSession(stages)
+	if len(filtered) != 1 || filtered[0].Source != entity.SleepStageSourceHealthConnect {
+		t.Fatalf("expected the longer health_connect session, got %+v", filtered)
+	}
+}
+
 func TestFilterMainSleepSession_Empty(t *testing.T) {
 	filtered := filterMainSleepSession(nil)
 	if len(filtered) != 0 {
@@ -532,6 +713,52 @@ func TestBiometricsHandler_GetDataQualityRange_OK(t *testing.T) {
 	}
 }
 
+func TestBiometricsHandler_GetQualityAlerts(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		repo       *stubDataQualityRepo
+		wantStatus int
+		wantLen    int
+	}{
+		{"alerts", "?from=2025-06-01&to=2025-06-30", &stubDataQualityRepo{alerts: []entity.DataQuality{
+			{SpO2MinAlert: true, LowestSpO2: 86, PlausibilityPass: true},
+			{PlausibilityPass: false},
+		}}, http.StatusOK, 2},
+		{"none", "?from=2025-06-01&to=2025-06-30", &stubDataQualityRepo{}, http.StatusOK, 0},
+		{"bad from", "?from=bad&to=2025-06-30", &stubDataQualityRepo{}, http.StatusBadRequest, -1},
+		{"to before from", "?from=2025-06-30&to=2025-06-01", &stubDataQualityRepo{}, http.StatusBadRequest, -1},
+		{"repo error", "?from=2025-06-01&to=2025-06-30", &stubDataQualityRepo{err: errors.New("db down")}, http.StatusInternalServerError, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/quality/alerts"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := NewBiometricsHandler(&stubDailySummaryRepo{}, &stubHeartRateRepo{}, &stubSleepStageRepo{}, tt.repo)
+			if err := h.GetQualityAlerts(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantLen < 0 {
+				return
+			}
+			var got []entity.DataQuality
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("len = %d, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+}
+
 func TestParseDate_JST(t *testing.T) {
 	d, err := parseDate("2026-02-19")
 	if err != nil {
@@ -547,6 +774,89 @@ func TestParseDate_JST(t *testing.T) {
 	}
 }
 
+func TestBiometricsHandler_GetHeartRateIntraday_Timezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantFrom string
+		wantTo   string
+		wantZone string
+	}{
+		{"default JST", "date=2026-01-15", "2026-01-14T15:00:00Z", "2026-01-15T15:00:00Z", "Asia/Tokyo"},
+		{"UTC+9", "date=2026-01-15&tz=Asia/Tokyo", "2026-01-14T15:00:00Z", "2026-01-15T15:00:00Z", "Asia/Tokyo"},
+		{"UTC-5", "date=2026-01-15&tz=America/New_York", "2026-01-15T05:00:00Z", "2026-01-16T05:00:00Z", "America/New_York"},
+		{"DST start is 23 hours", "date=2026-03-08&tz=America/New_York", "2026-03-08T05:00:00Z", "2026-03-09T04:00:00Z", "America/New_York"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/heartrate/intraday?"+tt.query, nil), rec)
+			hr := &stubHeartRateRepo{}
+			h := NewBiometricsHandler(&stubDailySummaryRepo{}, hr, &stubSleepStageRepo{}, &stubDataQualityRepo{})
+			if err := h.GetHeartRateIntraday(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			if got := hr.gotFrom.Format(time.RFC3339); got != tt.wantFrom {
+				t.Errorf("from = %s, want %s", got, tt.wantFrom)
+			}
+			if got := hr.gotTo.Format(time.RFC3339); got != tt.wantTo {
+				t.Errorf("to = %s, want %s", got, tt.wantTo)
+			}
+			if got := rec.Header().Get("X-Response-Timezone"); got != tt.wantZone {
+				t.Errorf("X-Response-Timezone = %q, want %q", got, tt.wantZone)
+			}
+		})
+	}
+}
+
+func TestBiometricsHandler_GetDailySummaryRange_Timezone(t *testing.T) {
+	for _, tz := range []string{"Asia/Tokyo", "America/New_York"} {
+		t.Run(tz, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet,
+				"/api/biometrics/range?from=2026-01-15&to=2026-01-20&tz="+tz, nil), rec)
+			repo := &stubDailySummaryRepo{}
+			if err := newHandler(repo).GetDailySummaryRange(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			// A UTC conversion would move the UTC+9 bounds back a calendar day.
+			if got := repo.listFrom.Format("2006-01-02"); got != "2026-01-15" {
+				t.Errorf("from date = %s, want 2026-01-15", got)
+			}
+			if got := repo.listTo.Format("2006-01-02"); got != "2026-01-20" {
+				t.Errorf("to date = %s, want 2026-01-20", got)
+			}
+			if repo.listFrom.Location().String() != tz {
+				t.Errorf("from location = %s, want %s", repo.listFrom.Location(), tz)
+			}
+			if got := rec.Header().Get("X-Response-Timezone"); got != tz {
+				t.Errorf("X-Response-Timezone = %q, want %q", got, tz)
+			}
+		})
+	}
+}
+
+func TestBiometricsHandler_GetDailySummaryRange_InvalidTimezone(t *testing.T) {
+	for _, tz := range []string{"Mars/Olympus", "Local"} {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet,
+			"/api/biometrics/range?from=2026-01-15&to=2026-01-20&tz="+tz, nil), rec)
+		if err := newHandler(&stubDailySummaryRepo{}).GetDailySummaryRange(c); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("tz=%s: status = %d, want 400", tz, rec.Code)
+		}
+	}
+}
+
 func TestParseDate_InvalidFormat(t *testing.T) {
 	_, err := parseDate("not-a-date")
 	if err == nil {
@@ -617,3 +927,363 @@ func TestDeduplicateStages(t *testing.T) {
 		}
 	})
 }
+
+func TestBiometricsHandler_GetHeartRateIntradayAggregated(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBucket int
+	}{
+		{"default bucket", "?date=2025-06-15", http.StatusOK, 5},
+		{"custom bucket", "?date=2025-06-15&bucket=15", http.StatusOK, 15},
+		{"invalid bucket", "?date=2025-06-15&bucket=0", http.StatusBadRequest, 0},
+		{"invalid date", "?date=bad", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/heartrate/intraday/aggregated"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			hr := &stubHeartRateRepo{buckets: []entity.HeartRateBucket{{AvgBPM: 70, MinBPM: 65, MaxBPM: 80, SampleCount: 5}}}
+			h := NewBiometricsHandler(&stubDailySummaryRepo{}, hr, &stubSleepStageRepo{}, &stubDataQualityRepo{})
+			if err := h.GetHeartRateIntradayAggregated(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if hr.gotBucketMin != tt.wantBucket {
+				t.Errorf("bucketMin = %d, want %d", hr.gotBucketMin, tt.wantBucket)
+			}
+		})
+	}
+}
+
+func TestBiometricsHandler_GetHRVIntraday(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		repo       *stubHRVSampleRepo
+		wantStatus int
+		wantLen    int
+	}{
+		{"samples", "?date=2025-06-15", &stubHRVSampleRepo{samples: []entity.HRVSample{{RMSSD: 40}, {RMSSD: 42}}}, http.StatusOK, 2},
+		{"no data", "?date=2025-06-15", &stubHRVSampleRepo{}, http.StatusOK, 0},
+		{"invalid date", "?date=bad", &stubHRVSampleRepo{}, http.StatusBadRequest, -1},
+		{"repo error", "?date=2025-06-15", &stubHRVSampleRepo{err: errors.New("db down")}, http.StatusInternalServerError, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/biometrics/hrv/intraday"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := NewBiometricsHandler(&stubDailySummaryRepo{}, &stubHeartRateRepo{}, &stubSleepStageRepo{}, &stubDataQualityRepo{}).
+				WithHRVSamples(tt.repo)
+			if err := h.GetHRVIntraday(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantLen < 0 {
+				return
+			}
+			var got []entity.HRVSample
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("len = %d, want %d", len(got), tt.wantLen)
+			}
+			if want := tt.repo.gotFrom.AddDate(0, 0, 1); !tt.repo.gotTo.Equal(want) {
+				t.Errorf("range = %v..%v, want one day", tt.repo.gotFrom, tt.repo.gotTo)
+			}
+		})
+	}
+}
+
+func TestBiometricsHandler_GetHRVIntraday_SleepSession(t *testing.T) {
+	// The night of 2025-06-14/15 in JST: 23:00 to 06:30.
+	start := time.Date(2025, 6, 14, 23, 0, 0, 0, jst)
+	stages := &stubSleepStageRepo{stages: []entity.SleepStage{
+		{Time: start, Stage: "light", Seconds: 3600, LogID: 1, Source: entity.SleepStageSourceFitbit},
+		{Time: start.Add(time.Hour), Stage: "deep", Seconds: 5400, LogID: 1, Source: entity.SleepStageSourceFitbit},
+		{Time: start.Add(150 * time.Minute), Stage: "rem", Seconds: 18000, LogID: 1, Source: entity.SleepStageSourceFitbit},
+	}}
+	repo := &stubHRVSampleRepo{samples: []entity.HRVSample{{RMSSD: 40}}}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/biometrics/hrv/intraday?date=2025-06-15", nil), rec)
+	h := NewBiometricsHandler(&stubDailySummaryRepo{}, &stubHeartRateRepo{}, stages, &stubDataQualityRepo{}).
+		WithHRVSamples(repo)
+	if err := h.GetHRVIntraday(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if want := start.UTC(); !repo.gotFrom.Equal(want) {
+		t.Errorf("from = %v, want sleep start %v", repo.gotFrom, want)
+	}
+	if want := start.Add(7*time.Hour + 30*time.Minute).UTC(); !repo.gotTo.Equal(want) {
+		t.Errorf("to = %v, want sleep end %v", repo.gotTo, want)
+	}
+}
+
+func TestBiometricsHandler_GetActiveZonesIntraday(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		repo       *stubActiveZoneSampleRepo
+		wantStatus int
+		wantLen    int
+	}{
+		{"samples", "?date=2025-06-15", &stubActiveZoneSampleRepo{samples: []entity.ActiveZoneSample{{FatBurnMin: 1}, {CardioMin: 1}}}, http.StatusOK, 2},
+		{"no data", "?date=2025-06-15", &stubActiveZoneSampleRepo{}, http.StatusOK, 0},
+		{"invalid date", "?date=bad", &stubActiveZoneSampleRepo{}, http.StatusBadRequest, -1},
+		{"repo error", "?date=2025-06-15", &stubActiveZoneSampleRepo{err: errors.New("db down")}, http.StatusInternalServerError, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/biometrics/active-zones/intraday"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := NewBiometricsHandler(&stubDailySummaryRepo{}, &stubHeartRateRepo{}, &stubSleepStageRepo{}, &stubDataQualityRepo{}).
+				WithActiveZones(tt.repo)
+			if err := h.GetActiveZonesIntraday(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantLen < 0 {
+				return
+			}
+			var got []entity.ActiveZoneSample
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("len = %d, want %d", len(got), tt.wantLen)
+			}
+			if want := tt.repo.gotFrom.AddDate(0, 0, 1); !tt.repo.gotTo.Equal(want) {
+				t.Errorf("range = %v..%v, want one day", tt.repo.gotFrom, tt.repo.gotTo)
+			}
+		})
+	}
+}
+
+func TestBiometricsHandler_GetSleepInertia(t *testing.T) {
+	start := time.Date(2025, 6, 14, 23, 0, 0, 0, jst)
+	end := start.Add(7 * time.Hour)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/sleep/inertia?date=2025-06-15", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := NewBiometricsHandler(
+		&stubDailySummaryRepo{summary: &entity.DailySummary{SleepStart: &start, SleepEnd: &end}},
+		&stubHeartRateRepo{},
+		&stubSleepStageRepo{timeRangeStages: []entity.SleepStage{
+			{Time: start, Stage: "light", Seconds: 6 * 3600, LogID: 1},
+			{Time: start.Add(6 * time.Hour), Stage: "deep", Seconds: 3600, LogID: 1},
+		}},
+		&stubDataQualityRepo{},
+	)
+	if err := h.GetSleepInertia(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var got entity.SleepInertia
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Level != entity.SleepInertiaSevere {
+		t.Errorf("Level = %q, want %q", got.Level, entity.SleepInertiaSevere)
+	}
+}
+
+func TestBiometricsHandler_GetSleepInertia_NoData(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/sleep/inertia?date=2025-06-15", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := newHandler(&stubDailySummaryRepo{})
+	if err := h.GetSleepInertia(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestBiometricsHandler_GetQualitySummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		repo       *stubDataQualityRepo
+		wantStatus int
+	}{
+		{"summary", "?from=2025-06-01&to=2025-06-10", &stubDataQualityRepo{qualities: []entity.DataQuality{
+			{Date: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), IsValidDay: true, ConfidenceScore: 0.8, MetricsPresent: []string{"resting_hr"}},
+		}}, http.StatusOK},
+		{"bad to", "?from=2025-06-01&to=bad", &stubDataQualityRepo{}, http.StatusBadRequest},
+		{"over a year", "?from=2024-01-01&to=2025-06-01", &stubDataQualityRepo{}, http.StatusBadRequest},
+		{"repo error", "?from=2025-06-01&to=2025-06-10", &stubDataQualityRepo{err: errors.New("db down")}, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/quality/summary"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := NewBiometricsHandler(&stubDailySummaryRepo{}, &stubHeartRateRepo{}, &stubSleepStageRepo{}, tt.repo)
+			if err := h.GetQualitySummary(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got entity.DataQualitySummary
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if got.TotalDays != 10 || got.ValidDays != 1 {
+				t.Errorf("TotalDays = %d, ValidDays = %d, want 10, 1", got.TotalDays, got.ValidDays)
+			}
+		})
+	}
+}
+
+func TestBiometricsHandler_GetQualityTrend(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		repo       *stubDataQualityRepo
+		wantStatus int
+	}{
+		{"trend", "?from=2025-06-01&to=2025-06-10", &stubDataQualityRepo{qualities: []entity.DataQuality{
+			{Date: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), IsValidDay: true, WearTimeHours: 21, CompletenessPct: 0.9, ConfidenceScore: 0.8},
+		}}, http.StatusOK},
+		{"bad from", "?from=bad&to=2025-06-10", &stubDataQualityRepo{}, http.StatusBadRequest},
+		{"to before from", "?from=2025-06-10&to=2025-06-01", &stubDataQualityRepo{}, http.StatusBadRequest},
+		{"over a year", "?from=2024-01-01&to=2025-06-01", &stubDataQualityRepo{}, http.StatusBadRequest},
+		{"repo error", "?from=2025-06-01&to=2025-06-10", &stubDataQualityRepo{err: errors.New("db down")}, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/quality/trend"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := NewBiometricsHandler(&stubDailySummaryRepo{}, &stubHeartRateRepo{}, &stubSleepStageRepo{}, tt.repo)
+			if err := h.GetQualityTrend(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got entity.DataQualityTrend
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if got.TotalDays != 10 || got.ValidDayCount != 1 || len(got.Dates) != 10 {
+				t.Fatalf("TotalDays = %d, ValidDayCount = %d, dates = %d; want 10, 1, 10", got.TotalDays, got.ValidDayCount, len(got.Dates))
+			}
+			if got.Dates[1] != "2025-06-02" || got.WearTimeHours[1] == nil || *got.WearTimeHours[1] != 21 {
+				t.Errorf("day 2025-06-02 not aligned: dates[1] = %s, wear = %v", got.Dates[1], got.WearTimeHours[1])
+			}
+			if got.WearTimeHours[0] != nil {
+				t.Errorf("WearTimeHours[0] = %v, want null", *got.WearTimeHours[0])
+			}
+		})
+	}
+}
+
+func TestBiometricsHandler_GetCompletenessSparkline(t *testing.T) {
+	now := time.Now().In(jst)
+	day := func(daysAgo int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()-daysAgo, 0, 0, 0, 0, time.UTC)
+	}
+	// Out of order; the sparkline must place each value on its own day.
+	repo := &stubDataQualityRepo{qualities: []entity.DataQuality{
+		{Date: day(1), CompletenessPct: 0.8},
+		{Date: day(29), CompletenessPct: 0.5},
+		{Date: day(0), CompletenessPct: 1},
+	}}
+
+	tests := []struct {
+		name       string
+		query      string
+		repo       *stubDataQualityRepo
+		wantStatus int
+		wantLen    int
+	}{
+		{"default 30 days", "", repo, http.StatusOK, 30},
+		{"7 days", "?days=7", repo, http.StatusOK, 7},
+		{"zero days", "?days=0", repo, http.StatusBadRequest, 0},
+		{"too many days", "?days=366", repo, http.StatusBadRequest, 0},
+		{"not a number", "?days=abc", repo, http.StatusBadRequest, 0},
+		{"repo error", "", &stubDataQualityRepo{err: errors.New("db down")}, http.StatusInternalServerError, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/biometrics/completeness-sparkline"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := NewBiometricsHandler(&stubDailySummaryRepo{}, &stubHeartRateRepo{}, &stubSleepStageRepo{}, tt.repo)
+			if err := h.GetCompletenessSparkline(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got []float32
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if len(got) != tt.wantLen {
+				t.Fatalf("len = %d, want %d", len(got), tt.wantLen)
+			}
+			n := len(got)
+			if got[n-1] != 1 || got[n-2] != 0.8 || got[n-3] != 0 {
+				t.Errorf("last three = %v, want [0 0.8 1]", got[n-3:])
+			}
+			if n == 30 && got[0] != 0.5 {
+				t.Errorf("first = %v, want 0.5", got[0])
+			}
+		})
+	}
+}
@@ -2,24 +2,50 @@ package handler
 
 import (
 	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"vitametron/api/application"
 	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
 )
 
 type ConditionHandler struct {
-	uc application.ConditionUseCase
+	uc          application.ConditionUseCase
+	keyAuth     echo.MiddlewareFunc
+	annotations port.ConditionAnnotationRepository
+	appleHealth *application.ImportAppleHealthConditionsUseCase
 }
 
 func NewConditionHandler(uc application.ConditionUseCase) *ConditionHandler {
 	return &ConditionHandler{uc: uc}
 }
 
+// WithAPIKeyAuth sets the middleware guarding write-many routes such as tag
+// rename. Those routes are not registered until it is set.
+func (h *ConditionHandler) WithAPIKeyAuth(mw echo.MiddlewareFunc) *ConditionHandler {
+	h.keyAuth = mw
+	return h
+}
+
+// WithAnnotations enables the /conditions/:id/annotations routes backed by repo.
+func (h *ConditionHandler) WithAnnotations(repo port.ConditionAnnotationRepository) *ConditionHandler {
+	h.annotations = repo
+	return h
+}
+
+// WithAppleHealthImport enables POST /conditions/import/apple-health backed by uc.
+func (h *ConditionHandler) WithAppleHealthImport(uc *application.ImportAppleHealthConditionsUseCase) *ConditionHandler {
+	h.appleHealth = uc
+	return h
+}
+
 type createConditionRequest struct {
 	// VAS 0-100 (primary)
 	Wellbeing    int    `json:"wellbeing"`
@@ -30,6 +56,8 @@ type createConditionRequest struct {
 	Note         string `json:"note,omitempty"`
 	Tags         []string   `json:"tags,omitempty"`
 	LoggedAt     *time.Time `json:"logged_at,omitempty"`
+	// Source is "spontaneous" (default), "reminder" or "scheduled".
+	Source string `json:"source,omitempty"`
 }
 
 func (h *ConditionHandler) Create(c echo.Context) error {
@@ -51,6 +79,7 @@ func (h *ConditionHandler) Create(c echo.Context) error {
 		StressVAS:       req.Stress,
 		Note:            req.Note,
 		Tags:            req.Tags,
+		Source:          req.Source,
 		LoggedAt:        loggedAt,
 	}
 
@@ -89,17 +118,36 @@ func (h *ConditionHandler) List(c echo.Context) error {
 		to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
 	}
 
+	var tags []string
+	for _, t := range strings.Split(c.QueryParam("tags"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	tagOp := c.QueryParam("tag_op")
+	if tagOp != "" && tagOp != entity.TagOperatorAnd && tagOp != entity.TagOperatorOr {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "tag_op must be 'and' or 'or'"})
+	}
+
 	limit, _ := strconv.Atoi(c.QueryParam("limit"))
 	offset, _ := strconv.Atoi(c.QueryParam("offset"))
 
 	filter := entity.ConditionFilter{
 		From:      from,
 		To:        to,
-		Tag:       c.QueryParam("tag"),
-		Limit:     limit,
-		Offset:    offset,
-		SortField: c.QueryParam("sort"),
-		SortDir:   c.QueryParam("order"),
+		Tag:         c.QueryParam("tag"),
+		Tags:        tags,
+		TagOperator: tagOp,
+		Limit:       limit,
+		Offset:      offset,
+		SortField:   c.QueryParam("sort"),
+		SortDir:     c.QueryParam("order"),
+		Archived:    c.QueryParam("archived") == "true",
+
+		IncludeDeleted: c.QueryParam("include_deleted") == "true",
+		CountOnly:      c.QueryParam("count_only") == "true",
+
+		ExcludeOutliers: c.QueryParam("exclude_outliers") == "true",
 	}
 
 	result, err := h.uc.List(c.Request().Context(), filter)
@@ -155,6 +203,20 @@ func (h *ConditionHandler) Update(c echo.Context) error {
 	return c.JSON(http.StatusOK, log)
 }
 
+func (h *ConditionHandler) GetHistory(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+
+	history, err := h.uc.GetHistory(c.Request().Context(), id)
+	if err != nil {
+		return conditionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
 func (h *ConditionHandler) Delete(c echo.Context) error {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -168,6 +230,35 @@ func (h *ConditionHandler) Delete(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// Restore undoes a soft delete and returns the restored log.
+func (h *ConditionHandler) Restore(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+
+	log, err := h.uc.Restore(c.Request().Context(), id)
+	if err != nil {
+		return conditionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, log)
+}
+
+// DeletePermanent removes a log for good, whether or not it was soft-deleted.
+func (h *ConditionHandler) DeletePermanent(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+
+	if err := h.uc.DeletePermanent(c.Request().Context(), id); err != nil {
+		return conditionError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (h *ConditionHandler) GetTags(c echo.Context) error {
 	tags, err := h.uc.GetTags(c.Request().Context())
 	if err != nil {
@@ -191,7 +282,15 @@ func (h *ConditionHandler) GetSummary(c echo.Context) error {
 		to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
 	}
 
-	summary, err := h.uc.GetSummary(c.Request().Context(), from, to)
+	weighting := c.QueryParam("weighting")
+	if weighting == "" {
+		weighting = entity.WeightingUniform
+	}
+	if weighting != entity.WeightingUniform && weighting != entity.WeightingTimeWeighted {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "weighting must be 'uniform' or 'time_weighted'"})
+	}
+
+	summary, err := h.uc.GetSummary(c.Request().Context(), entity.ConditionFilter{From: from, To: to, WeightingMode: weighting})
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
@@ -199,14 +298,241 @@ func (h *ConditionHandler) GetSummary(c echo.Context) error {
 	return c.JSON(http.StatusOK, summary)
 }
 
+// GetHeatmap returns one entry per day of the year with the mean overall VAS.
+// GET /api/conditions/heatmap?year=2025
+func (h *ConditionHandler) GetHeatmap(c echo.Context) error {
+	year := time.Now().In(jst).Year()
+	if v := c.QueryParam("year"); v != "" {
+		y, err := strconv.Atoi(v)
+		if err != nil || y < 1970 || y > 2100 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "year must be between 1970 and 2100"})
+		}
+		year = y
+	}
+
+	heatmap, err := h.uc.GetHeatmap(c.Request().Context(), year)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, heatmap)
+}
+
+// GetWeekly returns the condition logs in the range rolled up by ISO week.
+// GET /api/conditions/weekly?from=2025-01-01&to=2025-03-31
+func (h *ConditionHandler) GetWeekly(c echo.Context) error {
+	fromStr, toStr := c.QueryParam("from"), c.QueryParam("to")
+	if fromStr == "" || toStr == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "from and to are required"})
+	}
+	from, err := parseDate(fromStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid from date"})
+	}
+	to, err := parseDate(toStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid to date"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "from must not be after to"})
+	}
+
+	weeks, err := h.uc.GetWeekly(c.Request().Context(), from, to.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, weeks)
+}
+
+type renameTagRequest struct {
+	NewTag string `json:"new_tag"`
+}
+
+// RenameTag replaces a tag on every condition log.
+// PUT /api/conditions/tags/:oldTag {"new_tag": "migraine"}
+func (h *ConditionHandler) RenameTag(c echo.Context) error {
+	oldTag := strings.TrimSpace(c.Param("oldTag"))
+	var req renameTagRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+	}
+	newTag := strings.TrimSpace(req.NewTag)
+	if oldTag == "" || newTag == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "old and new tag are required"})
+	}
+	if oldTag == newTag {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "new_tag must differ from the current tag"})
+	}
+
+	n, err := h.uc.RenameTag(c.Request().Context(), oldTag, newTag)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]int64{"updated": n})
+}
+
+// Archive moves condition logs recorded before the given date to the archive table.
+// POST /api/admin/conditions/archive?before=2024-01-01
+func (h *ConditionHandler) Archive(c echo.Context) error {
+	before, err := parseDate(c.QueryParam("before"))
+	if err != nil || before.IsZero() {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "before must be YYYY-MM-DD"})
+	}
+
+	n, err := h.uc.Archive(c.Request().Context(), before)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"archived": n,
+		"before":   before.Format("2006-01-02"),
+	})
+}
+
+type createAnnotationRequest struct {
+	Label string `json:"label"`
+	Note  string `json:"note,omitempty"`
+}
+
+// POST /api/conditions/:id/annotations {"label": "pre-intervention", "note": "..."}
+func (h *ConditionHandler) CreateAnnotation(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+
+	var req createAnnotationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+	}
+	annotation := &entity.ConditionAnnotation{ConditionLogID: id, Label: req.Label, Note: req.Note}
+	if err := annotation.Validate(); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	if _, err := h.uc.GetByID(ctx, id); err != nil {
+		return conditionError(c, err)
+	}
+	if err := h.annotations.Create(ctx, annotation); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, annotation)
+}
+
+// GET /api/conditions/:id/annotations
+func (h *ConditionHandler) ListAnnotations(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+
+	ctx := c.Request().Context()
+	if _, err := h.uc.GetByID(ctx, id); err != nil {
+		return conditionError(c, err)
+	}
+	annotations, err := h.annotations.ListByLogID(ctx, id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if annotations == nil {
+		annotations = []entity.ConditionAnnotation{}
+	}
+
+	return c.JSON(http.StatusOK, annotations)
+}
+
+// DELETE /api/conditions/:id/annotations/:annoId
+func (h *ConditionHandler) DeleteAnnotation(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+	annoID, err := strconv.ParseInt(c.Param("annoId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid annotation id"})
+	}
+
+	ok, err := h.annotations.Delete(c.Request().Context(), id, annoID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ImportAppleHealth tags condition logs with the "Mood Changes" symptoms of
+// an Apple Health export.xml uploaded as the multipart "file" field. The part is streamed, not buffered.
+// POST /api/conditions/import/apple-health
+func (h *ConditionHandler) ImportAppleHealth(c echo.Context) error {
+	mr, err := c.Request().MultipartReader()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid multipart request"})
+	}
+
+	var filePart *multipart.Part
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to read multipart"})
+		}
+		if part.FormName() == "file" {
+			filePart = part
+			break
+		}
+		part.Close()
+	}
+	if filePart == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "file is required"})
+	}
+	defer filePart.Close()
+
+	result, err := h.appleHealth.Execute(c.Request().Context(), filePart)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// RegisterAdmin registers maintenance routes; g is expected to be API-key protected.
+func (h *ConditionHandler) RegisterAdmin(g *echo.Group) {
+	g.POST("/conditions/archive", h.Archive)
+}
+
 func (h *ConditionHandler) Register(g *echo.Group) {
 	g.POST("/conditions", h.Create)
 	g.GET("/conditions", h.List)
 	g.GET("/conditions/tags", h.GetTags)
+	if h.keyAuth != nil {
+		g.PUT("/conditions/tags/:oldTag", h.RenameTag, h.keyAuth)
+	}
 	g.GET("/conditions/summary", h.GetSummary)
+	g.GET("/conditions/heatmap", h.GetHeatmap)
+	g.GET("/conditions/weekly", h.GetWeekly)
+	if h.appleHealth != nil {
+		g.POST("/conditions/import/apple-health", h.ImportAppleHealth)
+	}
 	g.GET("/conditions/:id", h.GetByID)
+	g.GET("/conditions/:id/history", h.GetHistory)
 	g.PUT("/conditions/:id", h.Update)
 	g.DELETE("/conditions/:id", h.Delete)
+	g.POST("/conditions/:id/restore", h.Restore)
+	if h.keyAuth != nil {
+		g.DELETE("/conditions/:id/permanent", h.DeletePermanent, h.keyAuth)
+	}
+	if h.annotations != nil {
+		g.POST("/conditions/:id/annotations", h.CreateAnnotation)
+		g.GET("/conditions/:id/annotations", h.ListAnnotations)
+		g.DELETE("/conditions/:id/annotations/:annoId", h.DeleteAnnotation)
+	}
 }
 
 func conditionError(c echo.Context, err error) error {
@@ -1,16 +1,20 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"vitametron/api/application"
 	"vitametron/api/domain/entity"
 )
 
@@ -27,6 +31,29 @@ type stubConditionUseCase struct {
 	tagsErr    error
 	summary    *entity.ConditionSummary
 	summaryErr error
+	archived   int64
+	archiveErr error
+
+	renamed   int64
+	renameErr error
+
+	heatmap []entity.HeatmapDay
+	gotYear int
+
+	weekly         []entity.ConditionWeekly
+	gotFrom, gotTo time.Time
+
+	history    []entity.ConditionLogHistory
+	historyErr error
+
+	restored           *entity.ConditionLog
+	restoreErr         error
+	deletePermanentID  int64
+	deletePermanentErr error
+
+	gotFilter            entity.ConditionFilter
+	gotBefore            time.Time
+	gotOldTag, gotNewTag string
 }
 
 func (s *stubConditionUseCase) Create(_ context.Context, _ *entity.ConditionLog) error {
@@ -37,7 +64,8 @@ func (s *stubConditionUseCase) GetByID(_ context.Context, _ int64) (*entity.Cond
 	return s.getByIDLog, s.getByIDErr
 }
 
-func (s *stubConditionUseCase) List(_ context.Context, _ entity.ConditionFilter) (*entity.ConditionListResult, error) {
+func (s *stubConditionUseCase) List(_ context.Context, filter entity.ConditionFilter) (*entity.ConditionListResult, error) {
+	s.gotFilter = filter
 	return s.listResult, s.listErr
 }
 
@@ -45,18 +73,52 @@ func (s *stubConditionUseCase) Update(_ context.Context, _ int64, _ *entity.Cond
 	return s.updateErr
 }
 
+func (s *stubConditionUseCase) GetHistory(_ context.Context, _ int64) ([]entity.ConditionLogHistory, error) {
+	return s.history, s.historyErr
+}
+
 func (s *stubConditionUseCase) Delete(_ context.Context, _ int64) error {
 	return s.deleteErr
 }
 
+func (s *stubConditionUseCase) Restore(_ context.Context, _ int64) (*entity.ConditionLog, error) {
+	return s.restored, s.restoreErr
+}
+
+func (s *stubConditionUseCase) DeletePermanent(_ context.Context, id int64) error {
+	s.deletePermanentID = id
+	return s.deletePermanentErr
+}
+
 func (s *stubConditionUseCase) GetTags(_ context.Context) ([]entity.TagCount, error) {
 	return s.tags, s.tagsErr
 }
 
-func (s *stubConditionUseCase) GetSummary(_ context.Context, _, _ time.Time) (*entity.ConditionSummary, error) {
+func (s *stubConditionUseCase) GetSummary(_ context.Context, filter entity.ConditionFilter) (*entity.ConditionSummary, error) {
+	s.gotFilter = filter
 	return s.summary, s.summaryErr
 }
 
+func (s *stubConditionUseCase) GetHeatmap(_ context.Context, year int) ([]entity.HeatmapDay, error) {
+	s.gotYear = year
+	return s.heatmap, nil
+}
+
+func (s *stubConditionUseCase) GetWeekly(_ context.Context, from, to time.Time) ([]entity.ConditionWeekly, error) {
+	s.gotFrom, s.gotTo = from, to
+	return s.weekly, nil
+}
+
+func (s *stubConditionUseCase) RenameTag(_ context.Context, oldTag, newTag string) (int64, error) {
+	s.gotOldTag, s.gotNewTag = oldTag, newTag
+	return s.renamed, s.renameErr
+}
+
+func (s *stubConditionUseCase) Archive(_ context.Context, before time.Time) (int64, error) {
+	s.gotBefore = before
+	return s.archived, s.archiveErr
+}
+
 func TestConditionHandler_Create_Success(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodPost, "/api/conditions",
@@ -151,6 +213,39 @@ func TestConditionHandler_GetByID_Success(t *testing.T) {
 	}
 }
 
+func TestConditionHandler_GetHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		stub       *stubConditionUseCase
+		wantStatus int
+	}{
+		{"ok", "1", &stubConditionUseCase{history: []entity.ConditionLogHistory{
+			{ID: 1, ConditionLogID: 1, OldValues: entity.ConditionLog{OverallVAS: 40}, NewValues: entity.ConditionLog{OverallVAS: 60}},
+		}}, http.StatusOK},
+		{"not found", "999", &stubConditionUseCase{historyErr: entity.ErrNotFound}, http.StatusNotFound},
+		{"invalid id", "abc", &stubConditionUseCase{}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/conditions/"+tt.id+"/history", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+
+			if err := NewConditionHandler(tt.stub).GetHistory(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func TestConditionHandler_GetByID_NotFound(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/conditions/999", nil)
@@ -245,6 +340,48 @@ func TestConditionHandler_List_WithPagination(t *testing.T) {
 	}
 }
 
+func TestConditionHandler_List_Tags(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantTags   []string
+		wantOp     string
+	}{
+		{"and", "?tags=headache,tired&tag_op=and", http.StatusOK, []string{"headache", "tired"}, "and"},
+		{"or", "?tags=headache,%20tired,&tag_op=or", http.StatusOK, []string{"headache", "tired"}, "or"},
+		{"default operator", "?tags=headache", http.StatusOK, []string{"headache"}, ""},
+		{"invalid operator", "?tags=headache&tag_op=xor", http.StatusBadRequest, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/conditions"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			uc := &stubConditionUseCase{listResult: &entity.ConditionListResult{Items: []entity.ConditionLog{}}}
+			h := NewConditionHandler(uc)
+			if err := h.List(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if strings.Join(uc.gotFilter.Tags, ",") != strings.Join(tt.wantTags, ",") {
+				t.Errorf("Tags = %v, want %v", uc.gotFilter.Tags, tt.wantTags)
+			}
+			if uc.gotFilter.TagOperator != tt.wantOp {
+				t.Errorf("TagOperator = %q, want %q", uc.gotFilter.TagOperator, tt.wantOp)
+			}
+		})
+	}
+}
+
 func TestConditionHandler_Update_Success(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodPut, "/api/conditions/1",
@@ -325,6 +462,65 @@ func TestConditionHandler_Delete(t *testing.T) {
 	}
 }
 
+func TestConditionHandler_Restore(t *testing.T) {
+	tests := []struct {
+		name     string
+		stub     *stubConditionUseCase
+		wantCode int
+	}{
+		{"restored", &stubConditionUseCase{restored: &entity.ConditionLog{ID: 1, OverallVAS: 60}}, http.StatusOK},
+		{"not deleted", &stubConditionUseCase{restoreErr: entity.ErrNotFound}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/api/conditions/1/restore", nil), rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			if err := NewConditionHandler(tt.stub).Restore(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestConditionHandler_DeletePermanent(t *testing.T) {
+	stub := &stubConditionUseCase{}
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodDelete, "/api/conditions/7/permanent", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	if err := NewConditionHandler(stub).DeletePermanent(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if stub.deletePermanentID != 7 {
+		t.Errorf("deleted id = %d, want 7", stub.deletePermanentID)
+	}
+}
+
+func TestConditionHandler_DeletePermanent_RequiresAPIKey(t *testing.T) {
+	e := echo.New()
+	deny := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error { return c.NoContent(http.StatusUnauthorized) }
+	}
+	NewConditionHandler(&stubConditionUseCase{}).WithAPIKeyAuth(deny).Register(e.Group("/api"))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/conditions/7/permanent", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
 func TestConditionHandler_GetTags(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/conditions/tags", nil)
@@ -354,6 +550,70 @@ func TestConditionHandler_GetTags(t *testing.T) {
 	}
 }
 
+func TestConditionHandler_GetHeatmap(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantYear   int
+	}{
+		{"explicit year", "?year=2024", http.StatusOK, 2024},
+		{"default year", "", http.StatusOK, time.Now().In(jst).Year()},
+		{"not a number", "?year=abc", http.StatusBadRequest, 0},
+		{"out of range", "?year=1800", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/conditions/heatmap"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			stub := &stubConditionUseCase{heatmap: []entity.HeatmapDay{{Date: "2024-01-01"}}}
+			if err := NewConditionHandler(stub).GetHeatmap(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if stub.gotYear != tt.wantYear {
+				t.Errorf("year = %d, want %d", stub.gotYear, tt.wantYear)
+			}
+		})
+	}
+}
+
+func TestConditionHandler_GetSummary_Weighting(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantMode   string
+	}{
+		{"", http.StatusOK, entity.WeightingUniform},
+		{"&weighting=time_weighted", http.StatusOK, entity.WeightingTimeWeighted},
+		{"&weighting=latest", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/conditions/summary?from=2025-01-01&to=2025-01-31"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			stub := &stubConditionUseCase{summary: &entity.ConditionSummary{}}
+			if err := NewConditionHandler(stub).GetSummary(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if stub.gotFilter.WeightingMode != tt.wantMode {
+				t.Errorf("WeightingMode = %q, want %q", stub.gotFilter.WeightingMode, tt.wantMode)
+			}
+		})
+	}
+}
+
 func TestConditionHandler_GetSummary(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/conditions/summary?from=2025-01-01&to=2025-01-31", nil)
@@ -384,3 +644,357 @@ func TestConditionHandler_GetSummary(t *testing.T) {
 		t.Errorf("TotalCount = %d, want 10", summary.TotalCount)
 	}
 }
+
+func TestConditionHandler_List_Archived(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/conditions?archived=true", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	stub := &stubConditionUseCase{listResult: &entity.ConditionListResult{}}
+	h := NewConditionHandler(stub)
+	if err := h.List(c); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !stub.gotFilter.Archived {
+		t.Error("filter.Archived = false, want true")
+	}
+}
+
+func TestConditionHandler_List_IncludeDeleted(t *testing.T) {
+	for query, want := range map[string]bool{"": false, "?include_deleted=true": true} {
+		stub := &stubConditionUseCase{listResult: &entity.ConditionListResult{}}
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/conditions"+query, nil), rec)
+		if err := NewConditionHandler(stub).List(c); err != nil {
+			t.Fatal(err)
+		}
+		if stub.gotFilter.IncludeDeleted != want {
+			t.Errorf("%q: IncludeDeleted = %v, want %v", query, stub.gotFilter.IncludeDeleted, want)
+		}
+	}
+}
+
+func TestConditionHandler_Archive(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"valid date", "?before=2024-01-01", http.StatusOK},
+		{"missing before", "", http.StatusBadRequest},
+		{"invalid before", "?before=yesterday", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/conditions/archive"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			stub := &stubConditionUseCase{archived: 12}
+			h := NewConditionHandler(stub)
+			if err := h.Archive(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["archived"] != float64(12) {
+				t.Errorf("archived = %v, want 12", body["archived"])
+			}
+			if got := stub.gotBefore.Format("2006-01-02"); got != "2024-01-01" {
+				t.Errorf("before = %s, want 2024-01-01", got)
+			}
+		})
+	}
+}
+
+func TestConditionHandler_RenameTag(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldTag     string
+		body       string
+		wantStatus int
+	}{
+		{"renames tag", "headache", `{"new_tag":"migraine"}`, http.StatusOK},
+		{"missing new tag", "headache", `{}`, http.StatusBadRequest},
+		{"same tag", "headache", `{"new_tag":"headache"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/api/conditions/tags/"+tt.oldTag, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("oldTag")
+			c.SetParamValues(tt.oldTag)
+
+			stub := &stubConditionUseCase{renamed: 4}
+			h := NewConditionHandler(stub)
+			if err := h.RenameTag(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if stub.gotOldTag != "headache" || stub.gotNewTag != "migraine" {
+				t.Errorf("RenameTag(%q, %q), want (headache, migraine)", stub.gotOldTag, stub.gotNewTag)
+			}
+			var body map[string]int64
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["updated"] != 4 {
+				t.Errorf("updated = %d, want 4", body["updated"])
+			}
+		})
+	}
+}
+
+func TestConditionHandler_RenameTag_RequiresAPIKey(t *testing.T) {
+	e := echo.New()
+	requireKey := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("X-API-Key") != "secret" {
+				return c.NoContent(http.StatusUnauthorized)
+			}
+			return next(c)
+		}
+	}
+	NewConditionHandler(&stubConditionUseCase{}).WithAPIKeyAuth(requireKey).Register(e.Group("/api"))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/conditions/tags/headache", strings.NewReader(`{"new_tag":"migraine"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestConditionHandler_List_CountOnly(t *testing.T) {
+	for query, want := range map[string]bool{"": false, "?count_only=true": true} {
+		stub := &stubConditionUseCase{listResult: &entity.ConditionListResult{Items: []entity.ConditionLog{}, Total: 7}}
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/conditions"+query, nil), rec)
+		if err := NewConditionHandler(stub).List(c); err != nil {
+			t.Fatal(err)
+		}
+		if stub.gotFilter.CountOnly != want {
+			t.Errorf("%q: CountOnly = %v, want %v", query, stub.gotFilter.CountOnly, want)
+		}
+		if body := strings.TrimSpace(rec.Body.String()); body != `{"items":[],"total":7}` {
+			t.Errorf("%q: body = %s", query, body)
+		}
+	}
+}
+
+func TestConditionHandler_GetWeekly(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"valid range", "?from=2024-01-01&to=2024-01-31", http.StatusOK},
+		{"missing to", "?from=2024-01-01", http.StatusBadRequest},
+		{"invalid from", "?from=jan&to=2024-01-31", http.StatusBadRequest},
+		{"reversed", "?from=2024-02-01&to=2024-01-01", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/conditions/weekly"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			stub := &stubConditionUseCase{weekly: []entity.ConditionWeekly{{WeekStart: "2024-01-01", WeekEnd: "2024-01-07", LogCount: 2}}}
+			if err := NewConditionHandler(stub).GetWeekly(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if want := time.Date(2024, 2, 1, 0, 0, 0, 0, jst).Add(-time.Nanosecond); !stub.gotTo.Equal(want) {
+				t.Errorf("to = %v, want end of 2024-01-31 JST", stub.gotTo)
+			}
+			var got []entity.ConditionWeekly
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 1 || got[0].LogCount != 2 {
+				t.Errorf("body = %+v", got)
+			}
+		})
+	}
+}
+
+// stubAnnotationRepo implements port.ConditionAnnotationRepository in memory.
+type stubAnnotationRepo struct {
+	annotations []entity.ConditionAnnotation
+	nextID      int64
+}
+
+func (s *stubAnnotationRepo) Create(_ context.Context, a *entity.ConditionAnnotation) error {
+	s.nextID++
+	a.ID = s.nextID
+	a.CreatedAt = time.Now()
+	s.annotations = append(s.annotations, *a)
+	return nil
+}
+
+func (s *stubAnnotationRepo) ListByLogID(_ context.Context, logID int64) ([]entity.ConditionAnnotation, error) {
+	var out []entity.ConditionAnnotation
+	for _, a := range s.annotations {
+		if a.ConditionLogID == logID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubAnnotationRepo) Delete(_ context.Context, logID, id int64) (bool, error) {
+	for i, a := range s.annotations {
+		if a.ID == id && a.ConditionLogID == logID {
+			s.annotations = append(s.annotations[:i], s.annotations[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestConditionHandler_Annotations(t *testing.T) {
+	repo := &stubAnnotationRepo{}
+	uc := &stubConditionUseCase{getByIDLog: &entity.ConditionLog{ID: 1}}
+	e := echo.New()
+	NewConditionHandler(uc).WithAnnotations(repo).Register(e.Group("/api"))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/api/conditions/1/annotations", `{"label":" pre-intervention ","note":"baseline week"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	var created entity.ConditionAnnotation
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != 1 || created.ConditionLogID != 1 || created.Label != "pre-intervention" {
+		t.Errorf("created = %+v", created)
+	}
+
+	if rec := do(http.MethodPost, "/api/conditions/1/annotations", `{"label":"  "}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("blank label status = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+
+	rec = do(http.MethodGet, "/api/conditions/1/annotations", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var listed []entity.ConditionAnnotation
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || listed[0].Note != "baseline week" {
+		t.Errorf("listed = %+v", listed)
+	}
+
+	if rec := do(http.MethodDelete, "/api/conditions/2/annotations/1", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("delete under other log status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := do(http.MethodDelete, "/api/conditions/1/annotations/1", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("delete status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec := do(http.MethodGet, "/api/conditions/1/annotations", ""); rec.Body.String() != "[]\n" {
+		t.Errorf("list after delete = %q, want []", rec.Body.String())
+	}
+
+	uc.getByIDLog, uc.getByIDErr = nil, entity.ErrNotFound
+	if rec := do(http.MethodPost, "/api/conditions/9/annotations", `{"label":"on-medication"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("create on missing log status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestConditionHandler_Annotations_NotRegisteredWithoutRepo(t *testing.T) {
+	e := echo.New()
+	NewConditionHandler(&stubConditionUseCase{}).Register(e.Group("/api"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/conditions/1/annotations", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound && rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want route to be missing", rec.Code)
+	}
+}
+
+func TestConditionHandler_ImportAppleHealth(t *testing.T) {
+	fixture, err := os.ReadFile("../adapter/applehealthxml/testdata/export.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRequest := func(field string) *http.Request {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, _ := mw.CreateFormFile(field, "export.xml")
+		fw.Write(fixture)
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/api/conditions/import/apple-health", &body)
+		req.Header.Set(echo.HeaderContentType, mw.FormDataContentType())
+		return req
+	}
+
+	e := echo.New()
+	uc := &stubConditionUseCase{listResult: &entity.ConditionListResult{}}
+	NewConditionHandler(uc).
+		WithAppleHealthImport(application.NewImportAppleHealthConditionsUseCase(uc)).
+		Register(e.Group("/api"))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, newRequest("file"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var got application.ConditionImportResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != (application.ConditionImportResult{Imported: 2, Errors: 2}) {
+		t.Errorf("result = %+v, want 2 imported, 2 errors", got)
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, newRequest("upload"))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("missing file status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// dataFreshness marks JSON object responses whose data was last synced more
+// than maxAge ago, before its JST day had ended, with an X-Data-Stale header
+// and a "stale_since" field holding that sync time. A past day synced after
+// it ended is complete, however long ago that was. The sync time is the
+// top-level SyncedAt of a DailySummary, or the newest SyncedAt of a range
+// response's items, each with its Date. Responses without a SyncedAt are
+// left alone.
+//
+// Staleness depends on the clock, not only on the data the handler's ETag
+// covers, so If-None-Match is taken off the request and the handler always
+// renders the full body. A stale body is sent without an ETag; a fresh one
+// whose ETag the client already holds becomes the 304 the handler would have
+// sent.
+func dataFreshness(maxAge time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ifNoneMatch := c.Request().Header.Get("If-None-Match")
+			c.Request().Header.Del("If-None-Match")
+			res := c.Response()
+			orig := res.Writer
+			buf := &bufferedWriter{ResponseWriter: orig, status: http.StatusOK}
+			res.Writer = buf
+			err := next(c)
+			res.Writer = orig
+
+			body := buf.body.Bytes()
+			stale := false
+			if strings.HasPrefix(orig.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+				if syncedAt, date, ok := latestSyncedAt(body); ok && isStale(syncedAt, date, maxAge) {
+					staleSince := syncedAt.UTC().Format(time.RFC3339)
+					body = injectField(body, `"stale_since":"`+staleSince+`"`)
+					orig.Header().Set("X-Data-Stale", "true")
+					orig.Header().Del("ETag")
+					stale = true
+				}
+			}
+			if etag := orig.Header().Get("ETag"); !stale && buf.status == http.StatusOK &&
+				ifNoneMatch != "" && etag != "" && etagMatches(ifNoneMatch, etag) {
+				orig.Header().Del(echo.HeaderContentType)
+				orig.WriteHeader(http.StatusNotModified)
+				return err
+			}
+			orig.WriteHeader(buf.status)
+			if _, werr := orig.Write(body); werr != nil {
+				log.Printf("warn: write response: %v", werr)
+			}
+			return err
+		}
+	}
+}
+
+// isStale reports whether data for date, last synced at syncedAt, is older
+// than maxAge and may still be missing the rest of its day. A zero date is
+// judged by age alone.
+func isStale(syncedAt, date time.Time, maxAge time.Duration) bool {
+	if time.Since(syncedAt) <= maxAge {
+		return false
+	}
+	if date.IsZero() {
+		return true
+	}
+	y, m, d := date.Date()
+	dayEnd := time.Date(y, m, d+1, 0, 0, 0, 0, jst)
+	return syncedAt.Before(dayEnd)
+}
+
+// latestSyncedAt reads SyncedAt and Date from a JSON object body, falling
+// back to the item with the newest SyncedAt among its "items".
+func latestSyncedAt(body []byte) (time.Time, time.Time, bool) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return time.Time{}, time.Time{}, false
+	}
+	type synced struct {
+		Date     time.Time
+		SyncedAt time.Time
+	}
+	var resp struct {
+		synced
+		Items []synced `json:"items"`
+	}
+	if err := json.Unmarshal(trimmed, &resp); err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	latest := resp.synced
+	for _, item := range resp.Items {
+		if item.SyncedAt.After(latest.SyncedAt) {
+			latest = item
+		}
+	}
+	return latest.SyncedAt, latest.Date, !latest.SyncedAt.IsZero()
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/domain/entity"
+)
+
+func TestDataFreshness(t *testing.T) {
+	old := time.Now().Add(-30 * time.Hour).UTC().Truncate(time.Second)
+	recent := time.Now().Add(-time.Hour)
+	// The JST day old falls on, keyed like a DATE column, and one a week before.
+	y, m, d := old.In(jst).Date()
+	oldDay := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	pastDay := oldDay.AddDate(0, 0, -7)
+
+	tests := []struct {
+		name      string
+		body      any
+		wantStale bool
+	}{
+		{
+			name:      "old summary is stale",
+			body:      entity.DailySummary{Date: oldDay, Steps: 100, SyncedAt: old},
+			wantStale: true,
+		},
+		{
+			name: "past day synced after it ended is fresh",
+			body: entity.DailySummary{Date: pastDay, Steps: 100, SyncedAt: old},
+		},
+		{
+			name:      "summary without a date is judged by age",
+			body:      entity.DailySummary{Steps: 100, SyncedAt: old},
+			wantStale: true,
+		},
+		{
+			name: "recent summary is fresh",
+			body: entity.DailySummary{Steps: 100, SyncedAt: recent},
+		},
+		{
+			name: "range uses newest item",
+			body: entity.DailySummaryRangeResult{Items: []entity.DailySummary{
+				{SyncedAt: old}, {SyncedAt: recent},
+			}},
+		},
+		{
+			name: "range of past days is fresh",
+			body: entity.DailySummaryRangeResult{Items: []entity.DailySummary{
+				{Date: pastDay.AddDate(0, 0, -1), SyncedAt: old.Add(-time.Hour)}, {Date: pastDay, SyncedAt: old},
+			}},
+		},
+		{
+			name: "range ending on an unfinished day is stale",
+			body: entity.DailySummaryRangeResult{Items: []entity.DailySummary{
+				{Date: pastDay, SyncedAt: old.Add(-time.Hour)}, {Date: oldDay, SyncedAt: old},
+			}},
+			wantStale: true,
+		},
+		{
+			name: "body without SyncedAt is left alone",
+			body: map[string]string{"error": "no data for date"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/biometrics", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := func(c echo.Context) error { return c.JSON(http.StatusOK, tt.body) }
+			if err := dataFreshness(26 * time.Hour)(handler)(c); err != nil {
+				t.Fatal(err)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON body %q: %v", rec.Body.String(), err)
+			}
+			header := rec.Header().Get("X-Data-Stale")
+			if tt.wantStale {
+				if header != "true" {
+					t.Errorf("X-Data-Stale = %q, want true", header)
+				}
+				if got := body["stale_since"]; got != old.Format(time.RFC3339) {
+					t.Errorf("stale_since = %v, want %s", got, old.Format(time.RFC3339))
+				}
+				return
+			}
+			if header != "" {
+				t.Errorf("X-Data-Stale = %q, want absent", header)
+			}
+			if _, ok := body["stale_since"]; ok {
+				t.Error("unexpected stale_since field")
+			}
+		})
+	}
+}
+
+func TestDataFreshness_ETag(t *testing.T) {
+	old := time.Now().Add(-30 * time.Hour).UTC().Truncate(time.Second)
+	y, m, d := old.In(jst).Date()
+	oldDay := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		summary    entity.DailySummary
+		wantStatus int
+		wantStale  bool
+	}{
+		{
+			name:       "stale data ignores a matching If-None-Match",
+			summary:    entity.DailySummary{Date: oldDay, Steps: 100, SyncedAt: old},
+			wantStatus: http.StatusOK,
+			wantStale:  true,
+		},
+		{
+			name:       "fresh data still answers 304",
+			summary:    entity.DailySummary{Steps: 100, SyncedAt: time.Now().Add(-time.Hour)},
+			wantStatus: http.StatusNotModified,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The client holds the ETag of the same data, e.g. from while it
+			// was still fresh.
+			etag := generateETag(&tt.summary)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/biometrics", nil)
+			req.Header.Set("If-None-Match", etag)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := func(c echo.Context) error { return jsonWithETag(c, &tt.summary) }
+			if err := dataFreshness(26 * time.Hour)(handler)(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			stale := rec.Header().Get("X-Data-Stale") == "true"
+			if stale != tt.wantStale {
+				t.Errorf("X-Data-Stale = %v, want %v", stale, tt.wantStale)
+			}
+			if tt.wantStale {
+				if got := rec.Header().Get("ETag"); got != "" {
+					t.Errorf("ETag = %q on a stale body, want none", got)
+				}
+				var body map[string]any
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatalf("invalid JSON body %q: %v", rec.Body.String(), err)
+				}
+				if _, ok := body["stale_since"]; !ok {
+					t.Error("missing stale_since field")
+				}
+				return
+			}
+			if got := rec.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+			if rec.Body.Len() != 0 {
+				t.Errorf("body = %q, want empty 304", rec.Body.String())
+			}
+		})
+	}
+}
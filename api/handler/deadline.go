@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"context"
+	"time"
+)
+
+// withDeadline bounds ctx by d for an on-demand ML compute. A non-positive d
+// leaves ctx as is.
+func withDeadline(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
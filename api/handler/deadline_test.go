@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+// assertMLTimeout points a handler at an ML service that never answers and
+// checks the request ends in 503 with wantError once its deadline passes.
+func assertMLTimeout(t *testing.T, newHandle func(mlURL string, timeout time.Duration) echo.HandlerFunc, target, wantError string) {
+	t.Helper()
+
+	mlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer mlServer.Close()
+
+	handle := newHandle(mlServer.URL, 50*time.Millisecond)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+
+	start := time.Now()
+	if err := handle(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("handler returned after %v, want shortly after the deadline", elapsed)
+	}
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["error"] != wantError {
+		t.Errorf("error = %q, want %q", body["error"], wantError)
+	}
+}
+
+func TestVRIHandler_GetVRI_Timeout(t *testing.T) {
+	assertMLTimeout(t, func(url string, timeout time.Duration) echo.HandlerFunc {
+		return NewVRIHandler(newTestMLClient(url), &mocks.MockVRIRepository{
+			GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.VRIScore, error) { return nil, nil },
+		}).WithTimeout(timeout).GetVRI
+	}, "/api/vri?date=2026-01-15", "vri_computation_timeout")
+}
+
+func TestAnomalyHandler_GetAnomaly_Timeout(t *testing.T) {
+	assertMLTimeout(t, func(url string, timeout time.Duration) echo.HandlerFunc {
+		return NewAnomalyHandler(newTestMLClient(url), &mocks.MockAnomalyRepository{
+			GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.AnomalyDetection, error) { return nil, nil },
+		}).WithTimeout(timeout).GetAnomaly
+	}, "/api/anomaly?date=2026-01-15", "anomaly_detection_timeout")
+}
+
+func TestDivergenceHandler_GetDivergence_Timeout(t *testing.T) {
+	assertMLTimeout(t, func(url string, timeout time.Duration) echo.HandlerFunc {
+		return NewDivergenceHandler(newTestMLClient(url), &mocks.MockDivergenceRepository{
+			GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DivergenceDetection, error) { return nil, nil },
+		}).WithTimeout(timeout).GetDivergence
+	}, "/api/divergence?date=2026-01-15", "divergence_detection_timeout")
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"context"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// dedupe runs fn once for concurrent callers sharing key and gives each the
+// same result. fn gets a context detached from the first caller's
+// cancellation, so one closed tab does not fail the others.
+func dedupe[T any](ctx context.Context, g *singleflight.Group, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	shared := context.WithoutCancel(ctx)
+	v, err, _ := g.Do(key, func() (any, error) {
+		return fn(shared)
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+// assertSingleMLCall fires 10 concurrent requests for one date and checks
+// the ML service is called exactly once.
+func assertSingleMLCall(t *testing.T, newHandle func(mlURL string) echo.HandlerFunc, target string) {
+	t.Helper()
+
+	var calls atomic.Int32
+	arrived := make(chan struct{})
+	release := make(chan struct{})
+	mlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			close(arrived)
+		}
+		<-release
+		w.Write([]byte(`{}`))
+	}))
+	defer mlServer.Close()
+
+	handle := newHandle(mlServer.URL)
+	var wg sync.WaitGroup
+	codes := make([]int, 10)
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+			if err := handle(c); err != nil {
+				t.Error(err)
+			}
+			codes[i] = rec.Code
+		}()
+	}
+
+	<-arrived
+	// Give the other requests time to join the in-flight call.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("ML service called %d times, want 1", got)
+	}
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("request %d: status = %d, want %d", i, code, http.StatusOK)
+		}
+	}
+}
+
+func TestVRIHandler_GetVRI_DedupesConcurrentRequests(t *testing.T) {
+	assertSingleMLCall(t, func(url string) echo.HandlerFunc {
+		return NewVRIHandler(newTestMLClient(url), &mocks.MockVRIRepository{
+			GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.VRIScore, error) { return nil, nil },
+			ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.VRIScore, error) { return nil, nil },
+		}).GetVRI
+	}, "/api/vri?date=2026-01-15")
+}
+
+func TestAnomalyHandler_GetAnomaly_DedupesConcurrentRequests(t *testing.T) {
+	assertSingleMLCall(t, func(url string) echo.HandlerFunc {
+		return NewAnomalyHandler(newTestMLClient(url), &mocks.MockAnomalyRepository{
+			GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.AnomalyDetection, error) { return nil, nil },
+		}).GetAnomaly
+	}, "/api/anomaly?date=2026-01-15")
+}
+
+func TestHRVHandler_GetPrediction_DedupesConcurrentRequests(t *testing.T) {
+	assertSingleMLCall(t, func(url string) echo.HandlerFunc {
+		return newHRVHandlerWithURL(url).GetPrediction
+	}, "/api/hrv/predict?date=2026-01-15")
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/application"
+)
+
+type DigestHandler struct {
+	uc      application.DigestUseCase
+	keyAuth echo.MiddlewareFunc
+}
+
+func NewDigestHandler(uc application.DigestUseCase, keyAuth echo.MiddlewareFunc) *DigestHandler {
+	return &DigestHandler{uc: uc, keyAuth: keyAuth}
+}
+
+// Send builds and delivers a weekly digest on demand. week_start defaults to
+// the last completed Mon–Sun week.
+// POST /api/webhooks/digest/send?week_start=2025-01-06
+func (h *DigestHandler) Send(c echo.Context) error {
+	weekStart := application.LastCompletedWeek(time.Now().In(jst))
+	if ws := c.QueryParam("week_start"); ws != "" {
+		d, err := parseDate(ws)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'week_start' date format"})
+		}
+		if d.Weekday() != time.Monday {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "'week_start' must be a Monday"})
+		}
+		weekStart = d
+	}
+
+	digest, err := h.uc.Send(c.Request().Context(), weekStart)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error(), "job_id": digest.JobID})
+	}
+	return c.JSON(http.StatusOK, digest)
+}
+
+func (h *DigestHandler) Register(g *echo.Group) {
+	g.POST("/webhooks/digest/send", h.Send, h.keyAuth)
+}
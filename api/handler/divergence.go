@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -14,12 +16,20 @@ import (
 type DivergenceHandler struct {
 	mlClient       *mlclient.Client
 	divergenceRepo port.DivergenceRepository
+	// timeout bounds an on-demand detection; zero waits for the ML client.
+	timeout time.Duration
 }
 
 func NewDivergenceHandler(mlClient *mlclient.Client, divergenceRepo port.DivergenceRepository) *DivergenceHandler {
 	return &DivergenceHandler{mlClient: mlClient, divergenceRepo: divergenceRepo}
 }
 
+// WithTimeout makes GetDivergence answer 503 when an on-demand detection takes longer than d.
+func (h *DivergenceHandler) WithTimeout(d time.Duration) *DivergenceHandler {
+	h.timeout = d
+	return h
+}
+
 func (h *DivergenceHandler) GetDivergence(c echo.Context) error {
 	dateStr := c.QueryParam("date")
 	if dateStr == "" {
@@ -41,7 +51,12 @@ func (h *DivergenceHandler) GetDivergence(c echo.Context) error {
 	}
 
 	// Fall back to ML client for on-demand compute
-	detection, err = h.mlClient.DetectDivergence(c.Request().Context(), date)
+	ctx, cancel := withDeadline(c.Request().Context(), h.timeout)
+	defer cancel()
+	detection, err = h.mlClient.DetectDivergence(ctx, date)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "divergence_detection_timeout"})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// generateETag returns a strong ETag for the JSON encoding of v, or "" if v
+// cannot be encoded.
+func generateETag(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// jsonWithETag writes v as JSON with an ETag header, or 304 Not Modified when
+// the request's If-None-Match already names that ETag.
+func jsonWithETag(c echo.Context, v any) error {
+	etag := generateETag(v)
+	if etag == "" {
+		return c.JSON(http.StatusOK, v)
+	}
+	c.Response().Header().Set("ETag", etag)
+	if etagMatches(c.Request().Header.Get("If-None-Match"), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSON(http.StatusOK, v)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. GET uses
+// weak comparison, so a W/ prefix on a listed tag is ignored.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestGenerateETag(t *testing.T) {
+	a := generateETag(map[string]int{"x": 1})
+	if a == "" || a[0] != '"' || a[len(a)-1] != '"' {
+		t.Fatalf("generateETag() = %q, want quoted hash", a)
+	}
+	if b := generateETag(map[string]int{"x": 1}); a != b {
+		t.Errorf("generateETag() not stable: %q vs %q", a, b)
+	}
+	if b := generateETag(map[string]int{"x": 2}); a == b {
+		t.Error("generateETag() same for different values")
+	}
+	if got := generateETag(make(chan int)); got != "" {
+		t.Errorf("generateETag(unencodable) = %q, want empty", got)
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`*`, true},
+		{`"xyz"`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, `"abc"`); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+// assertNotModifiedOnRepeat issues target twice, the second time with the
+// first response's ETag, and expects 304 with an empty body.
+func assertNotModifiedOnRepeat(t *testing.T, target string, serve echo.HandlerFunc) {
+	t.Helper()
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	if err := serve(e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("first status = %d, want 200", rec.Code)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("first response has no ETag")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	if err := serve(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("repeat status = %d, want 304", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("304 body = %q, want empty", rec.Body.String())
+	}
+	if got := rec.Header().Get("ETag"); got != etag {
+		t.Errorf("304 ETag = %q, want %q", got, etag)
+	}
+}
+
+func TestVRIHandler_GetVRI_NotModified(t *testing.T) {
+	h := NewVRIHandler(nil, &mocks.MockVRIRepository{
+		GetByDateFunc: func(_ context.Context, date time.Time) (*entity.VRIScore, error) {
+			return &entity.VRIScore{Date: date, VRIScore: 72, VRIConfidence: 0.8, ComputedAt: date}, nil
+		},
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.VRIScore, error) { return nil, nil },
+	})
+	assertNotModifiedOnRepeat(t, "/api/vri?date=2026-01-15", h.GetVRI)
+}
+
+func TestAnomalyHandler_GetAnomaly_NotModified(t *testing.T) {
+	h := newAnomalyHandler(&mocks.MockAnomalyRepository{
+		GetByDateFunc: func(_ context.Context, date time.Time) (*entity.AnomalyDetection, error) {
+			return &entity.AnomalyDetection{Date: date, NormalizedScore: 0.4, TopDrivers: json.RawMessage(`[]`), ComputedAt: date}, nil
+		},
+	})
+	assertNotModifiedOnRepeat(t, "/api/anomaly?date=2026-01-15", h.GetAnomaly)
+}
+
+func TestBiometricsHandler_GetDailySummary_NotModified(t *testing.T) {
+	date := time.Date(2025, 6, 15, 0, 0, 0, 0, jst)
+	h := newHandler(&stubDailySummaryRepo{summary: &entity.DailySummary{Date: date, Steps: 8000}})
+	assertNotModifiedOnRepeat(t, "/api/biometrics?date=2025-06-15", h.GetDailySummary)
+}
+
+func TestBiometricsHandler_GetDailySummary_ChangedDataNewETag(t *testing.T) {
+	date := time.Date(2025, 6, 15, 0, 0, 0, 0, jst)
+	repo := &stubDailySummaryRepo{summary: &entity.DailySummary{Date: date, Steps: 8000}}
+	h := newHandler(repo)
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	if err := h.GetDailySummary(e.NewContext(httptest.NewRequest(http.MethodGet, "/api/biometrics?date=2025-06-15", nil), rec)); err != nil {
+		t.Fatal(err)
+	}
+	etag := rec.Header().Get("ETag")
+
+	repo.summary = &entity.DailySummary{Date: date, Steps: 9000}
+	req := httptest.NewRequest(http.MethodGet, "/api/biometrics?date=2025-06-15", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	if err := h.GetDailySummary(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 after data changed", rec.Code)
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Error("ETag unchanged after data changed")
+	}
+}
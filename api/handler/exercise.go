@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/adapter/gpx"
+	"vitametron/api/application"
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+var exerciseCSVHeader = []string{
+	"external_id", "activity_name", "started_at", "duration_min", "calories", "avg_hr", "distance_km",
+}
+
+type ExerciseHandler struct {
+	exercises port.ExerciseRepository
+	vo2max    *application.ExerciseVO2MaxUseCase
+	routes    port.ExerciseRouteRepository
+}
+
+func NewExerciseHandler(exercises port.ExerciseRepository) *ExerciseHandler {
+	return &ExerciseHandler{exercises: exercises}
+}
+
+// WithVO2MaxEstimator enables the VO2max estimation route.
+func (h *ExerciseHandler) WithVO2MaxEstimator(uc *application.ExerciseVO2MaxUseCase) *ExerciseHandler {
+	h.vo2max = uc
+	return h
+}
+
+// WithRoutes enables the GPX route download.
+func (h *ExerciseHandler) WithRoutes(repo port.ExerciseRouteRepository) *ExerciseHandler {
+	h.routes = repo
+	return h
+}
+
+// GetRouteGPX downloads the GPS track of one exercise as GPX.
+// GET /api/exercise/:id/route.gpx
+func (h *ExerciseHandler) GetRouteGPX(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+
+	ctx := c.Request().Context()
+	log, err := h.exercises.GetByID(ctx, id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if log == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+	route, err := h.routes.GetByExercise(ctx, log.ExternalID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if route == nil || len(route.Points) == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "exercise has no route"})
+	}
+
+	filename := fmt.Sprintf("exercise_%d_%s.gpx", id, log.StartedAt.In(jst).Format("20060102"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	c.Response().Header().Set(echo.HeaderContentType, gpx.ContentType)
+	c.Response().WriteHeader(http.StatusOK)
+	return gpx.Encode(c.Response(), log, route)
+}
+
+// EstimateVO2Max computes the Uth-Sørensen VO2max for one exercise and stores it.
+// POST /api/exercise/:id/estimate-vo2max
+func (h *ExerciseHandler) EstimateVO2Max(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+
+	log, err := h.vo2max.Estimate(c.Request().Context(), id)
+	if errors.Is(err, entity.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+	if errors.Is(err, application.ErrVO2MaxInputsMissing) {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, log)
+}
+
+// List returns exercise logs in a date range, newest first, optionally only
+// those carrying a tag.
+// GET /api/exercise?from=2025-01-01&to=2025-01-31&tag=running
+func (h *ExerciseHandler) List(c echo.Context) error {
+	from, err := parseDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+	}
+	to, err := parseDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 1 year"})
+	}
+
+	// date-only 'to' → include entire day
+	end := to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	var logs []entity.ExerciseLog
+	if tag := strings.TrimSpace(c.QueryParam("tag")); tag != "" {
+		logs, err = h.exercises.ListByTag(c.Request().Context(), tag, from, end)
+	} else {
+		logs, err = h.exercises.ListRange(c.Request().Context(), from, end)
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if logs == nil {
+		logs = []entity.ExerciseLog{}
+	}
+	return c.JSON(http.StatusOK, logs)
+}
+
+type updateExerciseNotesRequest struct {
+	Notes string   `json:"notes"`
+	Tags  []string `json:"tags"`
+}
+
+// UpdateNotes replaces the notes and tags of an imported exercise log.
+// PUT /api/exercise/:id/notes
+func (h *ExerciseHandler) UpdateNotes(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+	var req updateExerciseNotesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	tags := make([]string, 0, len(req.Tags))
+	for _, t := range req.Tags {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	ctx := c.Request().Context()
+	log, err := h.exercises.GetByID(ctx, id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if log == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+	if err := h.exercises.UpdateNotes(ctx, id, req.Notes, tags); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	log.Notes = req.Notes
+	log.Tags = tags
+	return c.JSON(http.StatusOK, log)
+}
+
+// GetPaceTrend returns the pace (seconds per km) of one activity over time.
+// GET /api/exercise/pace-trend?activity=Running&from=2025-01-01&to=2025-03-31
+func (h *ExerciseHandler) GetPaceTrend(c echo.Context) error {
+	activity := c.QueryParam("activity")
+	if activity == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "activity is required"})
+	}
+	from, err := parseDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+	}
+	to, err := parseDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 1 year"})
+	}
+
+	// date-only 'to' → include entire day
+	logs, err := h.exercises.ListRange(c.Request().Context(), from, to.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, application.BuildPaceTrend(activity, from, to, logs))
+}
+
+// Export downloads exercise logs as CSV (with a totals row) or JSON.
+// GET /api/exercise/export?from=2025-01-01&to=2025-01-31&format=csv
+func (h *ExerciseHandler) Export(c echo.Context) error {
+	from, err := parseDate(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'from' date format"})
+	}
+	to, err := parseDate(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' date format"})
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "'to' must not be before 'from'"})
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "range must not exceed 1 year"})
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be csv or json"})
+	}
+
+	// date-only 'to' → include entire day
+	logs, err := h.exercises.ListRange(c.Request().Context(), from, to.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if logs == nil {
+		logs = []entity.ExerciseLog{}
+	}
+
+	filename := fmt.Sprintf("exercise_%s_%s.%s", from.Format("20060102"), to.Format("20060102"), format)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "json" {
+		return c.JSON(http.StatusOK, logs)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	return writeExerciseCSV(c.Response(), logs)
+}
+
+func writeExerciseCSV(w http.ResponseWriter, logs []entity.ExerciseLog) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exerciseCSVHeader); err != nil {
+		return err
+	}
+
+	var totalMin float64
+	var totalCalories int
+	var totalKM float32
+	for _, l := range logs {
+		durationMin := float64(l.DurationMS) / 60000
+		totalMin += durationMin
+		totalCalories += l.Calories
+		totalKM += l.DistanceKM
+
+		if err := cw.Write([]string{
+			l.ExternalID,
+			l.ActivityName,
+			l.StartedAt.In(jst).Format(time.RFC3339),
+			strconv.FormatFloat(durationMin, 'f', 1, 64),
+			strconv.Itoa(l.Calories),
+			strconv.Itoa(l.AvgHR),
+			strconv.FormatFloat(float64(l.DistanceKM), 'f', 2, 32),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := cw.Write([]string{
+		"total", "", "",
+		strconv.FormatFloat(totalMin, 'f', 1, 64),
+		strconv.Itoa(totalCalories),
+		"",
+		strconv.FormatFloat(float64(totalKM), 'f', 2, 32),
+	}); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (h *ExerciseHandler) Register(g *echo.Group) {
+	g.GET("/exercise", h.List)
+	g.GET("/exercise/export", h.Export)
+	g.GET("/exercise/pace-trend", h.GetPaceTrend)
+	if h.vo2max != nil {
+		g.POST("/exercise/:id/estimate-vo2max", h.EstimateVO2Max)
+	}
+	g.PUT("/exercise/:id/notes", h.UpdateNotes)
+	if h.routes != nil {
+		g.GET("/exercise/:id/route.gpx", h.GetRouteGPX)
+	}
+}
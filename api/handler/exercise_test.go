@@ -0,0 +1,429 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/application"
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func newTestExerciseHandler(logs []entity.ExerciseLog) *ExerciseHandler {
+	return NewExerciseHandler(&mocks.MockExerciseRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.ExerciseLog, error) {
+			return logs, nil
+		},
+	})
+}
+
+func TestExerciseHandler_Export_CSV(t *testing.T) {
+	logs := []entity.ExerciseLog{
+		{ExternalID: "a1", ActivityName: "Run", StartedAt: time.Date(2025, 1, 2, 7, 0, 0, 0, jst), DurationMS: 30 * 60000, Calories: 300, AvgHR: 150, DistanceKM: 5},
+		{ExternalID: "a2", ActivityName: "Walk", StartedAt: time.Date(2025, 1, 3, 18, 0, 0, 0, jst), DurationMS: 45 * 60000, Calories: 150, AvgHR: 100, DistanceKM: 3.5},
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/exercise/export?from=2025-01-01&to=2025-01-31&format=csv", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := newTestExerciseHandler(logs)
+	if err := h.Export(c); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if cd := rec.Header().Get(echo.HeaderContentDisposition); !strings.HasPrefix(cd, "attachment") {
+		t.Errorf("Content-Disposition = %q, want attachment", cd)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	// header + 2 logs + totals
+	if len(records) != 4 {
+		t.Fatalf("rows = %d, want 4", len(records))
+	}
+	if got := strings.Join(records[0], ","); got != "external_id,activity_name,started_at,duration_min,calories,avg_hr,distance_km" {
+		t.Errorf("header = %q", got)
+	}
+	totals := records[3]
+	if totals[0] != "total" || totals[3] != "75.0" || totals[4] != "450" || totals[6] != "8.50" {
+		t.Errorf("totals row = %v", totals)
+	}
+}
+
+func TestExerciseHandler_Export_JSON(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/exercise/export?from=2025-01-01&to=2025-01-31&format=json", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := newTestExerciseHandler([]entity.ExerciseLog{{ExternalID: "a1"}})
+	if err := h.Export(c); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var got []entity.ExerciseLog
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Errorf("len = %d, want 1", len(got))
+	}
+}
+
+func TestExerciseHandler_Export_BadRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"missing from", "?to=2025-01-31"},
+		{"range over a year", "?from=2024-01-01&to=2025-06-01"},
+		{"unknown format", "?from=2025-01-01&to=2025-01-31&format=xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/exercise/export"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if err := newTestExerciseHandler(nil).Export(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestExerciseHandler_GetPaceTrend(t *testing.T) {
+	logs := []entity.ExerciseLog{
+		{ActivityName: "Running", StartedAt: time.Date(2025, 1, 2, 7, 0, 0, 0, jst), DurationMS: 25 * 60000, DistanceKM: 5, Pace: 300},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"ok", "activity=Running&from=2025-01-01&to=2025-01-31", http.StatusOK},
+		{"missing activity", "from=2025-01-01&to=2025-01-31", http.StatusBadRequest},
+		{"bad date", "activity=Running&from=nope&to=2025-01-31", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/exercise/pace-trend?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if err := newTestExerciseHandler(logs).GetPaceTrend(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var trend entity.PaceTrend
+			if err := json.Unmarshal(rec.Body.Bytes(), &trend); err != nil {
+				t.Fatal(err)
+			}
+			if len(trend.Points) != 1 || trend.Best == nil || *trend.Best != 300 {
+				t.Errorf("trend = %+v, want one point at 300 s/km", trend)
+			}
+		})
+	}
+}
+
+func TestExerciseHandler_EstimateVO2Max(t *testing.T) {
+	exercises := &mocks.MockExerciseRepository{
+		GetByIDFunc: func(_ context.Context, id int64) (*entity.ExerciseLog, error) {
+			switch id {
+			case 1:
+				return &entity.ExerciseLog{ID: 1, StartedAt: time.Date(2025, 1, 2, 7, 0, 0, 0, jst), AvgHR: 150}, nil
+			case 2:
+				return &entity.ExerciseLog{ID: 2, StartedAt: time.Date(2025, 1, 2, 7, 0, 0, 0, jst)}, nil
+			}
+			return nil, nil
+		},
+		UpdateEstimatedVO2MaxFunc: func(_ context.Context, _ int64, _ float32) error { return nil },
+	}
+	summaries := &mocks.MockDailySummaryRepository{
+		GetByDateFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+			return &entity.DailySummary{RestingHR: 60}, nil
+		},
+	}
+	h := NewExerciseHandler(exercises).
+		WithVO2MaxEstimator(application.NewExerciseVO2MaxUseCase(exercises, summaries, 30))
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"estimated", "1", http.StatusOK},
+		{"no avg HR", "2", http.StatusUnprocessableEntity},
+		{"unknown exercise", "3", http.StatusNotFound},
+		{"invalid id", "abc", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/exercise/"+tt.id+"/estimate-vo2max", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+
+			if err := h.EstimateVO2Max(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got entity.ExerciseLog
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			// 15 * ((220 - 30) / 60)
+			if got.EstimatedVO2Max == nil || *got.EstimatedVO2Max != 47.5 {
+				t.Errorf("EstimatedVO2Max = %v, want 47.5", got.EstimatedVO2Max)
+			}
+		})
+	}
+}
+
+func TestExerciseHandler_List(t *testing.T) {
+	var gotTag string
+	exercises := &mocks.MockExerciseRepository{
+		ListRangeFunc: func(_ context.Context, _, _ time.Time) ([]entity.ExerciseLog, error) {
+			return []entity.ExerciseLog{{ExternalID: "a1"}, {ExternalID: "a2"}}, nil
+		},
+		ListByTagFunc: func(_ context.Context, tag string, _, _ time.Time) ([]entity.ExerciseLog, error) {
+			gotTag = tag
+			return nil, nil
+		},
+	}
+	h := NewExerciseHandler(exercises)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantLen    int
+		wantTag    string
+	}{
+		{"range", "from=2025-01-01&to=2025-01-31", http.StatusOK, 2, ""},
+		{"by tag", "from=2025-01-01&to=2025-01-31&tag=running", http.StatusOK, 0, "running"},
+		{"missing from", "to=2025-01-31", http.StatusBadRequest, 0, ""},
+		{"reversed", "from=2025-02-01&to=2025-01-01", http.StatusBadRequest, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTag = ""
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/exercise?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if err := h.List(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got []entity.ExerciseLog
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got == nil || len(got) != tt.wantLen {
+				t.Errorf("logs = %v, want %d entries", got, tt.wantLen)
+			}
+			if gotTag != tt.wantTag {
+				t.Errorf("tag = %q, want %q", gotTag, tt.wantTag)
+			}
+		})
+	}
+}
+
+func TestExerciseHandler_UpdateNotes(t *testing.T) {
+	var gotNotes string
+	var gotTags []string
+	exercises := &mocks.MockExerciseRepository{
+		GetByIDFunc: func(_ context.Context, id int64) (*entity.ExerciseLog, error) {
+			if id == 1 {
+				return &entity.ExerciseLog{ID: 1, ActivityName: "Run", Tags: []string{}}, nil
+			}
+			return nil, nil
+		},
+		UpdateNotesFunc: func(_ context.Context, _ int64, notes string, tags []string) error {
+			gotNotes, gotTags = notes, tags
+			return nil
+		},
+	}
+	h := NewExerciseHandler(exercises)
+
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		wantStatus int
+	}{
+		{"updated", "1", `{"notes":"felt strong","tags":["running"," ","interval "]}`, http.StatusOK},
+		{"unknown exercise", "2", `{"notes":"x"}`, http.StatusNotFound},
+		{"invalid id", "abc", `{}`, http.StatusBadRequest},
+		{"invalid body", "1", `{"tags":"running"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/api/exercise/"+tt.id+"/notes", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+
+			if err := h.UpdateNotes(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if gotNotes != "felt strong" || strings.Join(gotTags, ",") != "running,interval" {
+				t.Errorf("stored notes=%q tags=%v", gotNotes, gotTags)
+			}
+			var got entity.ExerciseLog
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Notes != "felt strong" || len(got.Tags) != 2 {
+				t.Errorf("response = %+v", got)
+			}
+		})
+	}
+}
+
+func TestExerciseHandler_List_CoversWholeToDay(t *testing.T) {
+	var gotFrom, gotTo time.Time
+	exercises := &mocks.MockExerciseRepository{
+		ListRangeFunc: func(_ context.Context, from, to time.Time) ([]entity.ExerciseLog, error) {
+			gotFrom, gotTo = from, to
+			return []entity.ExerciseLog{{ExternalID: "late", StartedAt: to.Add(-time.Minute)}}, nil
+		},
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/exercise?from=2025-01-01&to=2025-01-31", nil)
+	rec := httptest.NewRecorder()
+	if err := NewExerciseHandler(exercises).List(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	wantFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, jst)
+	wantTo := time.Date(2025, 2, 1, 0, 0, 0, 0, jst).Add(-time.Nanosecond)
+	if !gotFrom.Equal(wantFrom) || !gotTo.Equal(wantTo) {
+		t.Errorf("ListRange(%v, %v), want (%v, %v)", gotFrom, gotTo, wantFrom, wantTo)
+	}
+	if !strings.Contains(rec.Body.String(), `"ExternalID":"late"`) {
+		t.Errorf("body = %s, want the log started late on the 'to' day", rec.Body.String())
+	}
+}
+
+func TestExerciseHandler_GetRouteGPX(t *testing.T) {
+	exercises := &mocks.MockExerciseRepository{
+		GetByIDFunc: func(_ context.Context, id int64) (*entity.ExerciseLog, error) {
+			switch id {
+			case 1:
+				return &entity.ExerciseLog{ID: 1, ExternalID: "hc-route", ActivityName: "Running", StartedAt: time.Date(2025, 5, 3, 7, 0, 0, 0, jst)}, nil
+			case 2:
+				return &entity.ExerciseLog{ID: 2, ExternalID: "hc-noroute", ActivityName: "Yoga"}, nil
+			}
+			return nil, nil
+		},
+	}
+	routes := &mocks.MockExerciseRouteRepository{
+		GetByExerciseFunc: func(_ context.Context, externalID string) (*entity.ExerciseRoute, error) {
+			if externalID != "hc-route" {
+				return nil, nil
+			}
+			return &entity.ExerciseRoute{ExerciseExternalID: externalID, Points: []entity.GPXPoint{
+				{Lat: 35.6812, Lng: 139.7671, Time: time.Date(2025, 5, 3, 7, 0, 0, 0, jst)},
+			}}, nil
+		},
+	}
+	h := NewExerciseHandler(exercises).WithRoutes(routes)
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"route", "1", http.StatusOK},
+		{"no route", "2", http.StatusNotFound},
+		{"unknown exercise", "3", http.StatusNotFound},
+		{"invalid id", "abc", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/exercise/"+tt.id+"/route.gpx", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+
+			if err := h.GetRouteGPX(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if ct := rec.Header().Get(echo.HeaderContentType); ct != "application/gpx+xml" {
+				t.Errorf("Content-Type = %q, want application/gpx+xml", ct)
+			}
+			if cd := rec.Header().Get(echo.HeaderContentDisposition); !strings.Contains(cd, "exercise_1_20250503.gpx") {
+				t.Errorf("Content-Disposition = %q", cd)
+			}
+			if body := rec.Body.String(); !strings.Contains(body, `<trkpt lat="35.6812" lon="139.7671">`) {
+				t.Errorf("body missing track point:\n%s", body)
+			}
+		})
+	}
+}
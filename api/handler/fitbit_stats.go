@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+type FitbitStatsHandler struct {
+	stats port.FitbitStatsProvider
+}
+
+func NewFitbitStatsHandler(stats port.FitbitStatsProvider) *FitbitStatsHandler {
+	return &FitbitStatsHandler{stats: stats}
+}
+
+// GetLifetimeStats returns account-lifetime totals.
+// GET /api/fitbit/lifetime-stats
+func (h *FitbitStatsHandler) GetLifetimeStats(c echo.Context) error {
+	stats, err := h.stats.FetchLifetimeStats(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, stats)
+}
+
+// GetBadges returns the badges earned so far.
+// GET /api/fitbit/badges
+func (h *FitbitStatsHandler) GetBadges(c echo.Context) error {
+	badges, err := h.stats.FetchBadges(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if badges == nil {
+		badges = []entity.FitbitBadge{}
+	}
+	return c.JSON(http.StatusOK, badges)
+}
+
+func (h *FitbitStatsHandler) Register(g *echo.Group) {
+	g.GET("/fitbit/lifetime-stats", h.GetLifetimeStats)
+	g.GET("/fitbit/badges", h.GetBadges)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestFitbitStatsHandler(t *testing.T) {
+	h := NewFitbitStatsHandler(&mocks.MockFitbitStatsProvider{
+		FetchLifetimeStatsFunc: func(_ context.Context) (*entity.FitbitLifetimeStats, error) {
+			return &entity.FitbitLifetimeStats{Steps: 1000000, DistanceKM: 750.2}, nil
+		},
+		FetchBadgesFunc: func(_ context.Context) ([]entity.FitbitBadge, error) {
+			return nil, nil
+		},
+	})
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	if err := h.GetLifetimeStats(e.NewContext(httptest.NewRequest(http.MethodGet, "/api/fitbit/lifetime-stats", nil), rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("lifetime status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var stats entity.FitbitLifetimeStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Steps != 1000000 {
+		t.Errorf("Steps = %d, want 1000000", stats.Steps)
+	}
+
+	rec = httptest.NewRecorder()
+	if err := h.GetBadges(e.NewContext(httptest.NewRequest(http.MethodGet, "/api/fitbit/badges", nil), rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || rec.Body.String() != "[]\n" {
+		t.Errorf("badges = %d %q, want 200 []", rec.Code, rec.Body.String())
+	}
+}
+
+func TestFitbitStatsHandler_Error(t *testing.T) {
+	h := NewFitbitStatsHandler(&mocks.MockFitbitStatsProvider{
+		FetchLifetimeStatsFunc: func(_ context.Context) (*entity.FitbitLifetimeStats, error) {
+			return nil, errors.New("fitbit down")
+		},
+	})
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/fitbit/lifetime-stats", nil), rec)
+	if err := h.GetLifetimeStats(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
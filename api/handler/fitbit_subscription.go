@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+// maxNotificationBytes bounds a Fitbit push body; real pushes are a few KB.
+const maxNotificationBytes = 1 << 20
+
+type FitbitSubscriptionHandler struct {
+	subs       port.FitbitSubscriptionProvider
+	queue      port.SyncQueue
+	keyAuth    echo.MiddlewareFunc
+	verifyCode string
+}
+
+// NewFitbitSubscriptionHandler guards subscription management with mw. The
+// notification endpoint is public and authenticated by its signature.
+func NewFitbitSubscriptionHandler(subs port.FitbitSubscriptionProvider, queue port.SyncQueue, mw echo.MiddlewareFunc) *FitbitSubscriptionHandler {
+	return &FitbitSubscriptionHandler{subs: subs, queue: queue, keyAuth: mw}
+}
+
+// WithVerificationCode enables GET /fitbit/notification, which Fitbit calls
+// with ?verify= when a subscriber endpoint is added or changed.
+func (h *FitbitSubscriptionHandler) WithVerificationCode(code string) *FitbitSubscriptionHandler {
+	h.verifyCode = code
+	return h
+}
+
+// ListSubscriptions returns the current Fitbit subscriptions.
+// GET /api/fitbit/subscribe
+func (h *FitbitSubscriptionHandler) ListSubscriptions(c echo.Context) error {
+	subs, err := h.subs.FetchSubscriptions(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+	}
+	if subs == nil {
+		subs = []entity.FitbitSubscription{}
+	}
+	return c.JSON(http.StatusOK, subs)
+}
+
+type subscribeRequest struct {
+	SubscriptionID string `json:"subscription_id"`
+	SubscriberID   string `json:"subscriber_id"`
+}
+
+// Subscribe registers a subscription for all of the user's collections.
+// POST /api/fitbit/subscribe
+func (h *FitbitSubscriptionHandler) Subscribe(c echo.Context) error {
+	var req subscribeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.SubscriptionID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "subscription_id is required"})
+	}
+	sub, err := h.subs.CreateSubscription(c.Request().Context(), req.SubscriberID, req.SubscriptionID)
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, sub)
+}
+
+// Unsubscribe removes a subscription.
+// DELETE /api/fitbit/subscribe/:id
+func (h *FitbitSubscriptionHandler) Unsubscribe(c echo.Context) error {
+	if err := h.subs.DeleteSubscription(c.Request().Context(), c.Param("id")); err != nil {
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Verify answers Fitbit's subscriber verification: 204 for the configured
+// code, 404 for anything else.
+// GET /api/fitbit/notification?verify=
+func (h *FitbitSubscriptionHandler) Verify(c echo.Context) error {
+	if c.QueryParam("verify") != h.verifyCode {
+		return c.NoContent(http.StatusNotFound)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Notify queues the dates named in a Fitbit push for sync. Fitbit expects a
+// 204 within a few seconds, so syncing happens in the scheduler. A bad
+// signature gets 404, as the Subscription API recommends.
+// POST /api/fitbit/notification
+func (h *FitbitSubscriptionHandler) Notify(c echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxNotificationBytes))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to read body"})
+	}
+	if !h.subs.VerifyNotification(body, c.Request().Header.Get("X-Fitbit-Signature")) {
+		log.Printf("warn: rejected fitbit notification with invalid signature")
+		return c.NoContent(http.StatusNotFound)
+	}
+
+	var notifications []entity.FitbitNotification
+	if err := json.Unmarshal(body, &notifications); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid notification body"})
+	}
+
+	seen := make(map[string]bool, len(notifications))
+	dates := make([]time.Time, 0, len(notifications))
+	for _, n := range notifications {
+		if seen[n.Date] {
+			continue
+		}
+		seen[n.Date] = true
+		date, err := parseDate(n.Date)
+		if err != nil {
+			log.Printf("warn: fitbit notification with invalid date %q", n.Date)
+			continue
+		}
+		dates = append(dates, date)
+	}
+	if err := h.queue.Enqueue(c.Request().Context(), dates); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *FitbitSubscriptionHandler) Register(g *echo.Group) {
+	g.GET("/fitbit/subscribe", h.ListSubscriptions, h.keyAuth)
+	g.POST("/fitbit/subscribe", h.Subscribe, h.keyAuth)
+	g.DELETE("/fitbit/subscribe/:id", h.Unsubscribe, h.keyAuth)
+	g.POST("/fitbit/notification", h.Notify)
+	if h.verifyCode != "" {
+		g.GET("/fitbit/notification", h.Verify)
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestFitbitSubscriptionHandler_Notify(t *testing.T) {
+	body := `[
+		{"collectionType":"activities","date":"2026-04-18","ownerId":"ABC","ownerType":"user","subscriptionId":"vm-1"},
+		{"collectionType":"sleep","date":"2026-04-18","ownerId":"ABC","ownerType":"user","subscriptionId":"vm-1"},
+		{"collectionType":"body","date":"2026-04-19","ownerId":"ABC","ownerType":"user","subscriptionId":"vm-1"}
+	]`
+
+	tests := []struct {
+		name      string
+		signature string
+		body      string
+		wantCode  int
+		wantDates []string
+	}{
+		{"valid push queues unique dates", "good", body, http.StatusNoContent, []string{"2026-04-18", "2026-04-19"}},
+		{"bad signature", "bad", body, http.StatusNotFound, nil},
+		{"malformed body", "good", `{"not":"an array"}`, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queued []string
+			h := NewFitbitSubscriptionHandler(&mocks.MockFitbitSubscriptionProvider{
+				VerifyNotificationFunc: func(_ []byte, signature string) bool { return signature == "good" },
+			}, &mocks.MockSyncQueue{
+				EnqueueFunc: func(_ context.Context, dates []time.Time) error {
+					for _, d := range dates {
+						queued = append(queued, d.Format("2006-01-02"))
+					}
+					return nil
+				},
+			}, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/fitbit/notification", strings.NewReader(tt.body))
+			req.Header.Set("X-Fitbit-Signature", tt.signature)
+			rec := httptest.NewRecorder()
+			if err := h.Notify(echo.New().NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if strings.Join(queued, ",") != strings.Join(tt.wantDates, ",") {
+				t.Errorf("queued = %v, want %v", queued, tt.wantDates)
+			}
+		})
+	}
+}
+
+func TestFitbitSubscriptionHandler_Subscribe(t *testing.T) {
+	var gotEndpoint, gotID, deletedID string
+	h := NewFitbitSubscriptionHandler(&mocks.MockFitbitSubscriptionProvider{
+		CreateSubscriptionFunc: func(_ context.Context, endpoint, subscriptionID string) (*entity.FitbitSubscription, error) {
+			gotEndpoint, gotID = endpoint, subscriptionID
+			return &entity.FitbitSubscription{SubscriptionID: subscriptionID, SubscriberID: endpoint}, nil
+		},
+		DeleteSubscriptionFunc: func(_ context.Context, subscriptionID string) error {
+			deletedID = subscriptionID
+			return nil
+		},
+	}, nil, nil)
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/fitbit/subscribe", strings.NewReader(`{"subscriber_id":"1"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	if err := h.Subscribe(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("missing subscription_id status = %d, want 400", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/fitbit/subscribe", strings.NewReader(`{"subscription_id":"vm-1","subscriber_id":"1"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	if err := h.Subscribe(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusCreated || gotID != "vm-1" || gotEndpoint != "1" {
+		t.Errorf("subscribe = %d (id %q, endpoint %q)", rec.Code, gotID, gotEndpoint)
+	}
+
+	rec = httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/api/fitbit/subscribe/vm-1", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues("vm-1")
+	if err := h.Unsubscribe(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNoContent || deletedID != "vm-1" {
+		t.Errorf("unsubscribe = %d (id %q)", rec.Code, deletedID)
+	}
+}
+
+func TestFitbitSubscriptionHandler_Verify(t *testing.T) {
+	h := NewFitbitSubscriptionHandler(&mocks.MockFitbitSubscriptionProvider{}, nil, nil).
+		WithVerificationCode("s3cret")
+	e := echo.New()
+
+	for code, want := range map[string]int{"s3cret": http.StatusNoContent, "wrong": http.StatusNotFound} {
+		rec := httptest.NewRecorder()
+		if err := h.Verify(e.NewContext(httptest.NewRequest(http.MethodGet, "/api/fitbit/notification?verify="+code, nil), rec)); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != want {
+			t.Errorf("verify=%s status = %d, want %d", code, rec.Code, want)
+		}
+	}
+}
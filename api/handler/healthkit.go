@@ -141,41 +141,13 @@ func (h *HealthKitHandler) StatusSSE(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "job_id is required"})
 	}
 
-	c.Response().Header().Set("Content-Type", "text/event-stream")
-	c.Response().Header().Set("Cache-Control", "no-cache")
-	c.Response().Header().Set("Connection", "keep-alive")
-	c.Response().Header().Set("X-Accel-Buffering", "no")
-
-	ctx := c.Request().Context()
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			data, err := h.rdb.Get(ctx, "hk_import:"+jobID).Result()
-			if err != nil {
-				continue
-			}
-
-			fmt.Fprintf(c.Response(), "data: %s\n\n", data)
-			c.Response().Flush()
-
-			// Check if completed or failed
-			var status struct {
-				Status string `json:"status"`
-			}
-			if json.Unmarshal([]byte(data), &status) == nil {
-				if status.Status == "completed" || status.Status == "failed" {
-					return nil
-				}
-			}
-		}
-	}
+	return streamImportProgress(c, h.rdb, "hk_import:"+jobID)
 }
 
+// chunkSessionTTL is how long a chunked upload session lives in Redis after
+// its last init, chunk or extend call.
+const chunkSessionTTL = 2 * time.Hour
+
 // chunkMeta is stored in Redis to track multi-chunk upload state.
 type chunkMeta struct {
 	TotalChunks int      `json:"total_chunks"`
@@ -213,7 +185,7 @@ func (h *HealthKitHandler) InitUpload(c echo.Context) error {
 	metaJSON, _ := json.Marshal(meta)
 
 	ctx := c.Request().Context()
-	if err := h.rdb.Set(ctx, "hk_chunk:"+uploadID, string(metaJSON), 2*time.Hour).Err(); err != nil {
+	if err := h.rdb.Set(ctx, "hk_chunk:"+uploadID, string(metaJSON), chunkSessionTTL).Err(); err != nil {
 		os.RemoveAll(chunkDir)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to init upload session"})
 	}
@@ -282,7 +254,7 @@ func (h *HealthKitHandler) UploadChunk(c echo.Context) error {
 	}
 
 	updatedJSON, _ := json.Marshal(meta)
-	h.rdb.Set(ctx, "hk_chunk:"+uploadID, string(updatedJSON), 2*time.Hour)
+	h.rdb.Set(ctx, "hk_chunk:"+uploadID, string(updatedJSON), chunkSessionTTL)
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"chunk_index": chunkIdx,
@@ -291,6 +263,25 @@ func (h *HealthKitHandler) UploadChunk(c echo.Context) error {
 	})
 }
 
+// ExtendUpload resets the TTL of an upload session so slow uploads do not expire.
+// POST /api/import/healthkit/extend/:uploadId
+func (h *HealthKitHandler) ExtendUpload(c echo.Context) error {
+	return extendChunkSession(c, h.rdb, "hk_chunk:"+c.Param("uploadId"))
+}
+
+// extendChunkSession resets the TTL of the chunk session at key to
+// chunkSessionTTL, or responds 404 when the session does not exist.
+func extendChunkSession(c echo.Context, rdb *redis.Client, key string) error {
+	ok, err := rdb.Expire(c.Request().Context(), key, chunkSessionTTL).Result()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to extend upload session"})
+	}
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "upload session not found"})
+	}
+	return c.JSON(http.StatusOK, map[string]int{"expires_in_seconds": int(chunkSessionTTL.Seconds())})
+}
+
 // CompleteUpload concatenates all chunks into a single ZIP and triggers processing.
 // POST /api/import/healthkit/complete/:uploadId
 func (h *HealthKitHandler) CompleteUpload(c echo.Context) error {
@@ -401,6 +392,7 @@ func (h *HealthKitHandler) Register(g *echo.Group) {
 	g.POST("/import/healthkit/init", h.InitUpload)
 	g.PUT("/import/healthkit/chunk/:uploadId/:chunkIndex", h.UploadChunk)
 	g.POST("/import/healthkit/complete/:uploadId", h.CompleteUpload)
+	g.POST("/import/healthkit/extend/:uploadId", h.ExtendUpload)
 	// Legacy single-request upload (LAN direct, etc.)
 	g.POST("/import/healthkit", h.Upload)
 	// Status
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestHealthKitHandler_StatusSSE_PubSub(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	h := NewHealthKitHandler(rdb, "", t.TempDir())
+
+	// Progress is written by the preprocessor; emulate its SET + PUBLISH.
+	ctx := context.Background()
+	setProgress := func(data string) {
+		t.Helper()
+		if err := rdb.Set(ctx, "hk_import:job-1", data, time.Hour).Err(); err != nil {
+			t.Fatal(err)
+		}
+		if err := rdb.Publish(ctx, "hk_import:job-1", data).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setProgress(`{"status":"queued","stage":"queued"}`)
+
+	e := echo.New()
+	h.Register(e.Group("/api"))
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/import/healthkit/stream/job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	events := make(chan string)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+				events <- strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+
+	next := func() string {
+		t.Helper()
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatal("stream closed early")
+			}
+			return ev
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+		return ""
+	}
+
+	if ev := next(); !strings.Contains(ev, `"stage":"queued"`) {
+		t.Errorf("first event = %s, want queued stage", ev)
+	}
+
+	setProgress(`{"status":"processing","stage":"parsing"}`)
+	if ev := next(); !strings.Contains(ev, `"stage":"parsing"`) {
+		t.Errorf("second event = %s, want parsing stage", ev)
+	}
+
+	setProgress(`{"status":"completed","stage":"done"}`)
+	if ev := next(); !strings.Contains(ev, `"status":"completed"`) {
+		t.Errorf("third event = %s, want completed status", ev)
+	}
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected stream to close after completed event")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed after completed event")
+	}
+}
+
+func TestHealthKitHandler_ExtendUpload(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	h := NewHealthKitHandler(rdb, "", t.TempDir())
+
+	e := echo.New()
+	h.Register(e.Group("/api"))
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/api/import/healthkit/init", `{"file_name":"export.zip","file_size":10,"chunk_size":5}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("init status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var initResp struct {
+		UploadID string `json:"upload_id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &initResp); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nearly expired: extend, then wait past the original deadline.
+	mr.FastForward(chunkSessionTTL - time.Minute)
+	rec = do(http.MethodPost, "/api/import/healthkit/extend/"+initResp.UploadID, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("extend status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if ttl := mr.TTL("hk_chunk:" + initResp.UploadID); ttl != chunkSessionTTL {
+		t.Errorf("TTL after extend = %v, want %v", ttl, chunkSessionTTL)
+	}
+	mr.FastForward(chunkSessionTTL - time.Minute)
+
+	rec = do(http.MethodPut, "/api/import/healthkit/chunk/"+initResp.UploadID+"/0", "12345")
+	if rec.Code != http.StatusOK {
+		t.Errorf("chunk status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = do(http.MethodPost, "/api/import/healthkit/extend/unknown", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -1,15 +1,28 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/sync/singleflight"
 
 	"vitametron/api/adapter/mlclient"
+	"vitametron/api/domain/entity"
+)
+
+// HRV range forecasts default to 3 days and are capped at a week.
+const (
+	defaultHRVForecastDays = 3
+	maxHRVForecastDays     = 7
 )
 
 type HRVHandler struct {
 	mlClient *mlclient.Client
+	// inflight collapses concurrent predictions for the same date.
+	inflight singleflight.Group
 }
 
 func NewHRVHandler(mlClient *mlclient.Client) *HRVHandler {
@@ -27,7 +40,8 @@ func (h *HRVHandler) GetPrediction(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
 	}
 
-	prediction, err := h.mlClient.PredictHRV(c.Request().Context(), date)
+	prediction, err := dedupe(c.Request().Context(), &h.inflight, date.Format("2006-01-02"),
+		func(ctx context.Context) (*entity.HRVPrediction, error) { return h.mlClient.PredictHRV(ctx, date) })
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
@@ -35,6 +49,36 @@ func (h *HRVHandler) GetPrediction(c echo.Context) error {
 	return c.JSON(http.StatusOK, prediction)
 }
 
+// GET /api/hrv/predict/range?date=2026-02-17&days=3
+func (h *HRVHandler) GetPredictionRange(c echo.Context) error {
+	dateStr := c.QueryParam("date")
+	if dateStr == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "date is required"})
+	}
+
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
+	}
+
+	days := defaultHRVForecastDays
+	if s := c.QueryParam("days"); s != "" {
+		days, err = strconv.Atoi(s)
+		if err != nil || days < 1 || days > maxHRVForecastDays {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("days must be between 1 and %d", maxHRVForecastDays),
+			})
+		}
+	}
+
+	predictions, err := h.mlClient.PredictHRVRange(c.Request().Context(), date, days)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, predictions)
+}
+
 func (h *HRVHandler) GetStatus(c echo.Context) error {
 	status, err := h.mlClient.GetHRVStatus(c.Request().Context())
 	if err != nil {
@@ -55,6 +99,7 @@ func (h *HRVHandler) Train(c echo.Context) error {
 
 func (h *HRVHandler) Register(g *echo.Group) {
 	g.GET("/hrv/predict", h.GetPrediction)
+	g.GET("/hrv/predict/range", h.GetPredictionRange)
 	g.GET("/hrv/status", h.GetStatus)
 	g.POST("/hrv/train", h.Train)
 }
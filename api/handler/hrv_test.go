@@ -146,6 +146,80 @@ func TestHRVHandler_GetStatus_MLError(t *testing.T) {
 	}
 }
 
+func TestHRVHandler_GetPredictionRange(t *testing.T) {
+	mlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hrv/predict/range" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("days"); got != "3" {
+			t.Errorf("days = %q, want 3", got)
+		}
+		preds := make([]map[string]any, 0, 3)
+		for _, target := range []string{"2026-02-18", "2026-02-19", "2026-02-20"} {
+			preds = append(preds, map[string]any{
+				"date":                 "2026-02-17",
+				"target_date":          target,
+				"predicted_hrv_zscore": 0.4,
+				"predicted_direction":  "above_baseline",
+				"confidence":           0.6,
+				"top_drivers":          []map[string]any{},
+				"model_version":        "v20260215_xgb",
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"predictions": preds})
+	}))
+	defer mlServer.Close()
+
+	h := newHRVHandlerWithURL(mlServer.URL)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/hrv/predict/range?date=2026-02-17&days=3", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := h.GetPredictionRange(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp []entity.HRVPrediction
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp) != 3 {
+		t.Fatalf("expected 3 predictions, got %d", len(resp))
+	}
+	seen := make(map[string]bool)
+	for i, p := range resp {
+		target := p.TargetDate.Format("2006-01-02")
+		if seen[target] {
+			t.Errorf("duplicate target date %s", target)
+		}
+		seen[target] = true
+		if i > 0 && !p.TargetDate.Equal(resp[i-1].TargetDate.AddDate(0, 0, 1)) {
+			t.Errorf("target dates not sequential: %s after %s", target, resp[i-1].TargetDate.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestHRVHandler_GetPredictionRange_InvalidDays(t *testing.T) {
+	h := newHRVHandlerWithURL("http://localhost:0")
+	for _, days := range []string{"0", "8", "abc"} {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/hrv/predict/range?date=2026-02-17&days="+days, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := h.GetPredictionRange(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("days=%s: expected 400, got %d", days, rec.Code)
+		}
+	}
+}
+
 func newHRVHandlerWithURL(url string) *HRVHandler {
 	return &HRVHandler{
 		mlClient: newTestMLClient(url),
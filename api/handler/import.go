@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -14,6 +15,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,26 +23,92 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"vitametron/api/application"
+	"vitametron/api/infrastructure/uploads"
 )
 
+// hcImportLockKey serialises imports so overlapping exports never
+// interleave their upserts. The holder refreshes the lock while it runs; it
+// expires hcImportLockTTL after the last refresh in case the holder dies. A
+// second import waits up to hcImportLockWait for it.
+const (
+	hcImportLockKey  = "hc_import_lock"
+	hcImportLockTTL  = 5 * time.Minute
+	hcImportLockWait = 10 * time.Second
+)
+
+// releaseImportLock deletes the lock only while it still holds the caller's
+// job ID, so an expired lock taken over by another job is left alone.
+var releaseImportLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// refreshImportLock extends the lock's TTL only while it still holds the
+// caller's job ID.
+var refreshImportLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+var errImportLocked = errors.New("another import is still running")
+
 type ImportHandler struct {
-	uc        *application.ImportHealthConnectUseCase
-	rdb       *redis.Client
-	uploadDir string
+	uc          *application.ImportHealthConnectUseCase
+	rdb         *redis.Client
+	uploadDir   string
+	keyAuth     echo.MiddlewareFunc
+	lockWait    time.Duration
+	lockRefresh time.Duration
 }
 
 func NewImportHandler(uc *application.ImportHealthConnectUseCase, rdb *redis.Client, uploadDir string) *ImportHandler {
-	return &ImportHandler{uc: uc, rdb: rdb, uploadDir: uploadDir}
+	return &ImportHandler{
+		uc:          uc,
+		rdb:         rdb,
+		uploadDir:   uploadDir,
+		lockWait:    hcImportLockWait,
+		lockRefresh: hcImportLockTTL / 3,
+	}
+}
+
+// WithAPIKeyAuth sets the middleware guarding upload cleanup. That route is
+// not registered until it is set.
+func (h *ImportHandler) WithAPIKeyAuth(mw echo.MiddlewareFunc) *ImportHandler {
+	h.keyAuth = mw
+	return h
 }
 
+// Cleanup removes chunked-upload directories untouched for 24 hours along with
+// their Redis sessions.
+// POST /api/import/cleanup
+func (h *ImportHandler) Cleanup(c echo.Context) error {
+	result, err := uploads.NewCleaner(h.uploadDir, h.rdb).Cleanup(c.Request().Context(), uploads.DefaultMaxAge)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// hcImportStatusTTL is how long a job's progress, and a failed job's ZIP
+// path, stay in Redis after the last update.
+const hcImportStatusTTL = time.Hour
+
 // hcImportProgress is the progress structure stored in Redis for async import tracking.
 type hcImportProgress struct {
-	Status string                   `json:"status"`
-	Stage  string                   `json:"stage"`
-	Error  string                   `json:"error,omitempty"`
+	Status string                    `json:"status"`
+	Stage  string                    `json:"stage"`
+	Error  string                    `json:"error,omitempty"`
 	Result *application.ImportResult `json:"result,omitempty"`
+	// Processed and Total count daily summaries during the importing stage.
+	Processed int `json:"processed,omitempty"`
+	Total     int `json:"total,omitempty"`
 }
 
+// ImportHealthConnect imports an uploaded Health Connect ZIP in one request.
+// With ?dry_run=true it only returns an ImportPreview.
+// POST /api/import/health-connect
 func (h *ImportHandler) ImportHealthConnect(c echo.Context) error {
 	mr, err := c.Request().MultipartReader()
 	if err != nil {
@@ -93,6 +161,23 @@ func (h *ImportHandler) ImportHealthConnect(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
+	if c.QueryParam("dry_run") == "true" {
+		preview, err := h.uc.Preview(c.Request().Context(), dbPath)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("import preview failed: %v", err)})
+		}
+		return c.JSON(http.StatusOK, preview)
+	}
+
+	release, err := h.acquireImportLock(c.Request().Context(), uuid.NewString())
+	if errors.Is(err, errImportLocked) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	defer release()
+
 	result, err := h.uc.Execute(c.Request().Context(), dbPath)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("import failed: %v", err)})
@@ -161,7 +246,7 @@ func (h *ImportHandler) InitUpload(c echo.Context) error {
 	metaJSON, _ := json.Marshal(meta)
 
 	ctx := c.Request().Context()
-	if err := h.rdb.Set(ctx, "hc_chunk:"+uploadID, string(metaJSON), 2*time.Hour).Err(); err != nil {
+	if err := h.rdb.Set(ctx, "hc_chunk:"+uploadID, string(metaJSON), chunkSessionTTL).Err(); err != nil {
 		os.RemoveAll(chunkDir)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to init upload session"})
 	}
@@ -227,7 +312,7 @@ func (h *ImportHandler) UploadChunk(c echo.Context) error {
 	}
 
 	updatedJSON, _ := json.Marshal(meta)
-	h.rdb.Set(ctx, "hc_chunk:"+uploadID, string(updatedJSON), 2*time.Hour)
+	h.rdb.Set(ctx, "hc_chunk:"+uploadID, string(updatedJSON), chunkSessionTTL)
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"chunk_index": chunkIdx,
@@ -236,6 +321,12 @@ func (h *ImportHandler) UploadChunk(c echo.Context) error {
 	})
 }
 
+// ExtendUpload resets the TTL of an upload session so slow uploads do not expire.
+// POST /api/import/health-connect/extend/:uploadId
+func (h *ImportHandler) ExtendUpload(c echo.Context) error {
+	return extendChunkSession(c, h.rdb, "hc_chunk:"+c.Param("uploadId"))
+}
+
 // CompleteUpload concatenates all chunks into a ZIP, then launches async import.
 // Returns HTTP 202 immediately with a job_id for progress tracking.
 // POST /api/import/health-connect/complete/:uploadId
@@ -309,9 +400,7 @@ func (h *ImportHandler) CompleteUpload(c echo.Context) error {
 
 	// Create job and store initial status in Redis
 	jobID := uuid.New().String()
-	progress := hcImportProgress{Status: "processing", Stage: "extracting"}
-	progressJSON, _ := json.Marshal(progress)
-	h.rdb.Set(ctx, "hc_import:"+jobID, string(progressJSON), 1*time.Hour)
+	h.setProgress(ctx, jobID, hcImportProgress{Status: "processing", Stage: "extracting"})
 
 	// Launch async import in goroutine
 	go h.runImport(jobID, zipPath)
@@ -322,51 +411,217 @@ func (h *ImportHandler) CompleteUpload(c echo.Context) error {
 	})
 }
 
-// runImport extracts the DB from ZIP and runs the import use case in the background.
+// runImport extracts the DB from ZIP and runs the import use case in the
+// background. The ZIP is removed only on success; a failed job keeps it for
+// RetryImport.
 func (h *ImportHandler) runImport(jobID, zipPath string) {
 	ctx := context.Background()
 
+	release, err := h.acquireImportLock(ctx, jobID)
+	if err != nil {
+		log.Printf("[hc-import] job %s: %v", jobID, err)
+		h.setImportFailed(ctx, jobID, zipPath, err.Error())
+		return
+	}
+	defer release()
+
 	tmpDir, err := os.MkdirTemp("", "hc-import-*")
 	if err != nil {
 		log.Printf("[hc-import] job %s: failed to create temp dir: %v", jobID, err)
-		h.setImportFailed(ctx, jobID, fmt.Sprintf("failed to create temp dir: %v", err))
-		os.Remove(zipPath)
+		h.setImportFailed(ctx, jobID, zipPath, fmt.Sprintf("failed to create temp dir: %v", err))
 		return
 	}
 	defer os.RemoveAll(tmpDir)
-	defer os.Remove(zipPath)
 
 	// Stage: extracting
 	dbPath, err := extractDBFromZip(zipPath, tmpDir)
 	if err != nil {
 		log.Printf("[hc-import] job %s: extraction failed: %v", jobID, err)
-		h.setImportFailed(ctx, jobID, err.Error())
+		h.setImportFailed(ctx, jobID, zipPath, err.Error())
 		return
 	}
 
 	// Stage: importing
-	progress := hcImportProgress{Status: "processing", Stage: "importing"}
-	progressJSON, _ := json.Marshal(progress)
-	h.rdb.Set(ctx, "hc_import:"+jobID, string(progressJSON), 1*time.Hour)
+	h.setProgress(ctx, jobID, hcImportProgress{Status: "processing", Stage: "importing"})
 
-	result, err := h.uc.Execute(ctx, dbPath)
+	result, err := h.uc.ExecuteWithProgress(ctx, dbPath, func(processed, total int) {
+		h.setProgress(ctx, jobID, hcImportProgress{Status: "processing", Stage: "importing", Processed: processed, Total: total})
+	})
 	if err != nil {
 		log.Printf("[hc-import] job %s: import failed: %v", jobID, err)
-		h.setImportFailed(ctx, jobID, fmt.Sprintf("import failed: %v", err))
+		h.setImportFailed(ctx, jobID, zipPath, fmt.Sprintf("import failed: %v", err))
 		return
 	}
+	os.Remove(zipPath)
+
+	if err := h.uc.RecordDevices(ctx, jobID, result.Devices); err != nil {
+		log.Printf("[hc-import] job %s: record devices failed: %v", jobID, err)
+	}
 
 	// Stage: completed
-	completed := hcImportProgress{Status: "completed", Stage: "done", Result: result}
-	completedJSON, _ := json.Marshal(completed)
-	h.rdb.Set(ctx, "hc_import:"+jobID, string(completedJSON), 1*time.Hour)
+	h.setProgress(ctx, jobID, hcImportProgress{Status: "completed", Stage: "done", Result: result})
 	log.Printf("[hc-import] job %s: completed", jobID)
 }
 
-func (h *ImportHandler) setImportFailed(ctx context.Context, jobID, errMsg string) {
-	failed := hcImportProgress{Status: "failed", Error: errMsg}
-	failedJSON, _ := json.Marshal(failed)
-	h.rdb.Set(ctx, "hc_import:"+jobID, string(failedJSON), 1*time.Hour)
+// acquireImportLock takes hcImportLockKey for jobID, retrying with
+// exponential backoff for up to h.lockWait while another import holds it.
+// The lock is refreshed every h.lockRefresh until the returned func
+// releases it.
+func (h *ImportHandler) acquireImportLock(ctx context.Context, jobID string) (func(), error) {
+	deadline := time.Now().Add(h.lockWait)
+	backoff := 100 * time.Millisecond
+	for {
+		ok, err := h.rdb.SetNX(ctx, hcImportLockKey, jobID, hcImportLockTTL).Result()
+		if err != nil {
+			return nil, fmt.Errorf("acquire import lock: %w", err)
+		}
+		if ok {
+			stop := make(chan struct{})
+			done := make(chan struct{})
+			go h.keepImportLock(ctx, jobID, stop, done)
+
+			var once sync.Once
+			return func() {
+				once.Do(func() {
+					close(stop)
+					<-done
+				})
+				if err := releaseImportLock.Run(ctx, h.rdb, []string{hcImportLockKey}, jobID).Err(); err != nil {
+					log.Printf("[hc-import] job %s: release import lock: %v", jobID, err)
+				}
+			}, nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, errImportLocked
+		}
+		time.Sleep(min(backoff, remaining))
+		backoff *= 2
+	}
+}
+
+// keepImportLock resets the lock's TTL every h.lockRefresh until stop is
+// closed, so a long import does not lose the lock halfway through.
+func (h *ImportHandler) keepImportLock(ctx context.Context, jobID string, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(h.lockRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			held, err := refreshImportLock.Run(ctx, h.rdb, []string{hcImportLockKey}, jobID, hcImportLockTTL.Milliseconds()).Int()
+			if err != nil {
+				log.Printf("[hc-import] job %s: refresh import lock: %v", jobID, err)
+				continue
+			}
+			if held == 0 {
+				log.Printf("[hc-import] job %s: import lock lost", jobID)
+				return
+			}
+		}
+	}
+}
+
+// setImportFailed marks the job failed and keeps its ZIP path, outside the
+// public progress, so RetryImport can re-run it. The uploads cleaner removes
+// the ZIP once it is older than uploads.DefaultMaxAge.
+func (h *ImportHandler) setImportFailed(ctx context.Context, jobID, zipPath, errMsg string) {
+	if err := h.rdb.Set(ctx, "hc_import_zip:"+jobID, zipPath, hcImportStatusTTL).Err(); err != nil {
+		log.Printf("[hc-import] job %s: store zip path failed: %v", jobID, err)
+	}
+	h.setProgress(ctx, jobID, hcImportProgress{Status: "failed", Error: errMsg})
+}
+
+var (
+	errJobNotFound     = errors.New("job not found")
+	errJobNotFailed    = errors.New("job is not in failed state")
+	errImportFileGone  = errors.New("import file is no longer available")
+	errRetryInProgress = errors.New("job is already being retried")
+)
+
+// RetryImport re-runs a failed async import from its kept ZIP.
+// POST /api/import/health-connect/retry/:jobId
+func (h *ImportHandler) RetryImport(c echo.Context) error {
+	jobID := c.Param("jobId")
+	ctx := c.Request().Context()
+
+	zipPath, err := h.claimFailedJob(ctx, jobID)
+	switch {
+	case errors.Is(err, errJobNotFound), errors.Is(err, errJobNotFailed), errors.Is(err, errImportFileGone):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, errRetryInProgress):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to claim job"})
+	}
+
+	h.setProgress(ctx, jobID, hcImportProgress{Status: "processing", Stage: "extracting"})
+	go h.runImport(jobID, zipPath)
+
+	return c.JSON(http.StatusAccepted, map[string]string{
+		"job_id": jobID,
+		"status": "processing",
+	})
+}
+
+// claimFailedJob moves a failed job back to processing and returns its ZIP
+// path. The check and the move run in one WATCH transaction, so of two
+// concurrent retries only one claims the job.
+func (h *ImportHandler) claimFailedJob(ctx context.Context, jobID string) (string, error) {
+	progressKey, zipKey := "hc_import:"+jobID, "hc_import_zip:"+jobID
+	processing, _ := json.Marshal(hcImportProgress{Status: "processing", Stage: "extracting"})
+
+	var zipPath string
+	err := h.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, progressKey).Result()
+		if err == redis.Nil {
+			return errJobNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var progress hcImportProgress
+		if err := json.Unmarshal([]byte(data), &progress); err != nil {
+			return err
+		}
+		if progress.Status != "failed" {
+			return errJobNotFailed
+		}
+		zipPath, err = tx.Get(ctx, zipKey).Result()
+		if err == redis.Nil {
+			return errImportFileGone
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(zipPath); err != nil {
+			return errImportFileGone
+		}
+
+		_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			p.Set(ctx, progressKey, string(processing), hcImportStatusTTL)
+			p.Del(ctx, zipKey)
+			return nil
+		})
+		return err
+	}, progressKey)
+	if errors.Is(err, redis.TxFailedErr) {
+		return "", errRetryInProgress
+	}
+	return zipPath, err
+}
+
+// setProgress stores the job progress for polling clients and publishes it
+// on the job's channel for SSE subscribers.
+func (h *ImportHandler) setProgress(ctx context.Context, jobID string, progress hcImportProgress) {
+	progressJSON, _ := json.Marshal(progress)
+	h.rdb.Set(ctx, "hc_import:"+jobID, string(progressJSON), hcImportStatusTTL)
+	if err := h.rdb.Publish(ctx, "hc_import:"+jobID, string(progressJSON)).Err(); err != nil {
+		log.Printf("[hc-import] job %s: publish progress failed: %v", jobID, err)
+	}
 }
 
 // Status returns the current import progress from Redis.
@@ -393,6 +648,24 @@ func (h *ImportHandler) Status(c echo.Context) error {
 	return c.JSON(http.StatusOK, result)
 }
 
+// Devices returns the Health Connect apps and devices found by a completed import.
+// GET /api/import/health-connect/devices/:jobId
+func (h *ImportHandler) Devices(c echo.Context) error {
+	jobID := c.Param("jobId")
+	if jobID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "job_id is required"})
+	}
+
+	devices, err := h.uc.GetDevices(c.Request().Context(), jobID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if devices == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "job not found"})
+	}
+	return c.JSON(http.StatusOK, devices)
+}
+
 // StatusSSE streams import progress via Server-Sent Events.
 // GET /api/import/health-connect/stream/:jobId
 func (h *ImportHandler) StatusSSE(c echo.Context) error {
@@ -401,48 +674,80 @@ func (h *ImportHandler) StatusSSE(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "job_id is required"})
 	}
 
+	return streamImportProgress(c, h.rdb, "hc_import:"+jobID)
+}
+
+// streamImportProgress streams the progress published on channel as SSE
+// events until the job completes or fails. Import jobs store their latest
+// progress under the same key as the channel, which is sent first so a
+// client connecting mid-import does not wait for the next stage change.
+func streamImportProgress(c echo.Context, rdb *redis.Client, channel string) error {
+	ctx := c.Request().Context()
+
+	// Subscribe before reading the current state so no stage change published
+	// in between is missed.
+	sub := rdb.Subscribe(ctx, channel)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to subscribe to job status"})
+	}
+
 	c.Response().Header().Set("Content-Type", "text/event-stream")
 	c.Response().Header().Set("Cache-Control", "no-cache")
 	c.Response().Header().Set("Connection", "keep-alive")
 	c.Response().Header().Set("X-Accel-Buffering", "no")
 
-	ctx := c.Request().Context()
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+	if data, err := rdb.Get(ctx, channel).Result(); err == nil {
+		if writeImportEvent(c, data) {
+			return nil
+		}
+	}
 
+	ch := sub.Channel()
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
-		case <-ticker.C:
-			data, err := h.rdb.Get(ctx, "hc_import:"+jobID).Result()
-			if err != nil {
-				continue
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
 			}
-
-			fmt.Fprintf(c.Response(), "data: %s\n\n", data)
-			c.Response().Flush()
-
-			var status struct {
-				Status string `json:"status"`
-			}
-			if json.Unmarshal([]byte(data), &status) == nil {
-				if status.Status == "completed" || status.Status == "failed" {
-					return nil
-				}
+			if writeImportEvent(c, msg.Payload) {
+				return nil
 			}
 		}
 	}
 }
 
+// writeImportEvent writes a progress payload as an SSE event and reports
+// whether the job has reached a terminal state.
+func writeImportEvent(c echo.Context, data string) bool {
+	fmt.Fprintf(c.Response(), "data: %s\n\n", data)
+	c.Response().Flush()
+
+	var status struct {
+		Status string `json:"status"`
+	}
+	if json.Unmarshal([]byte(data), &status) == nil {
+		return status.Status == "completed" || status.Status == "failed"
+	}
+	return false
+}
+
 func (h *ImportHandler) Register(g *echo.Group) {
 	// Chunked upload (Cloudflare Tunnel 100MB limit workaround)
 	g.POST("/import/health-connect/init", h.InitUpload)
 	g.PUT("/import/health-connect/chunk/:uploadId/:chunkIndex", h.UploadChunk)
 	g.POST("/import/health-connect/complete/:uploadId", h.CompleteUpload)
+	g.POST("/import/health-connect/extend/:uploadId", h.ExtendUpload)
+	g.POST("/import/health-connect/retry/:jobId", h.RetryImport)
 	// Status / SSE
 	g.GET("/import/health-connect/status/:jobId", h.Status)
 	g.GET("/import/health-connect/stream/:jobId", h.StatusSSE)
+	g.GET("/import/health-connect/devices/:jobId", h.Devices)
 	// Legacy single-request upload
 	g.POST("/import/health-connect", h.ImportHealthConnect)
+	if h.keyAuth != nil {
+		g.POST("/import/cleanup", h.Cleanup, h.keyAuth)
+	}
 }
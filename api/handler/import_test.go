@@ -0,0 +1,461 @@
+package handler
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"vitametron/api/application"
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func newTestImportHandler(t *testing.T) (*ImportHandler, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return NewImportHandler(nil, rdb, t.TempDir()), mr
+}
+
+func TestImportHandler_StatusSSE_PubSub(t *testing.T) {
+	h, mr := newTestImportHandler(t)
+	ctx := context.Background()
+	h.setProgress(ctx, "job-1", hcImportProgress{Status: "processing", Stage: "extracting"})
+
+	e := echo.New()
+	h.Register(e.Group("/api"))
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/import/health-connect/stream/job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	events := make(chan string)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+				events <- strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+
+	next := func() string {
+		t.Helper()
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatal("stream closed early")
+			}
+			return ev
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+		return ""
+	}
+
+	// Initial snapshot is sent from the stored key.
+	if ev := next(); !strings.Contains(ev, `"stage":"extracting"`) {
+		t.Errorf("first event = %s, want extracting stage", ev)
+	}
+
+	// Subsequent stages arrive via pub/sub without polling.
+	h.setProgress(ctx, "job-1", hcImportProgress{Status: "processing", Stage: "importing"})
+	if ev := next(); !strings.Contains(ev, `"stage":"importing"`) {
+		t.Errorf("second event = %s, want importing stage", ev)
+	}
+
+	h.setProgress(ctx, "job-1", hcImportProgress{Status: "completed", Stage: "done"})
+	if ev := next(); !strings.Contains(ev, `"status":"completed"`) {
+		t.Errorf("third event = %s, want completed status", ev)
+	}
+
+	// Terminal state closes the stream and releases the subscription.
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected stream to close after completed event")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed after completed event")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(mr.PubSubChannels("hc_import:*")) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription not cleaned up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestImportHandler_StatusSSE_AlreadyFinished(t *testing.T) {
+	h, _ := newTestImportHandler(t)
+	h.setProgress(context.Background(), "job-2", hcImportProgress{Status: "failed", Error: "boom"})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/import/health-connect/stream/job-2", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("jobId")
+	c.SetParamValues("job-2")
+
+	if err := h.StatusSSE(c); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rec.Body.String(), `"status":"failed"`) {
+		t.Errorf("body = %q, want failed event", rec.Body.String())
+	}
+}
+
+func TestImportHandler_Devices(t *testing.T) {
+	history := &mocks.MockImportHistoryRepository{
+		GetDevicesFunc: func(_ context.Context, jobID string) ([]entity.DeviceInfo, error) {
+			if jobID != "job-3" {
+				return nil, nil
+			}
+			return []entity.DeviceInfo{{AppInfoID: 5, PackageName: "com.nothing.smartcenter", DeviceName: "Nothing"}}, nil
+		},
+	}
+	uc := application.NewImportHealthConnectUseCase(nil, nil, nil, nil).WithHistory(history)
+	h := NewImportHandler(uc, nil, t.TempDir())
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		jobID      string
+		wantStatus int
+		wantLen    int
+	}{
+		{"known job", "job-3", http.StatusOK, 1},
+		{"unknown job", "job-missing", http.StatusNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/import/health-connect/devices/"+tt.jobID, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("jobId")
+			c.SetParamValues(tt.jobID)
+
+			if err := h.Devices(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got []entity.DeviceInfo
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.wantLen || got[0].DeviceName != "Nothing" {
+				t.Errorf("devices = %+v", got)
+			}
+		})
+	}
+}
+
+func TestImportHandler_RetryImport(t *testing.T) {
+	h, _ := newTestImportHandler(t)
+	ctx := context.Background()
+
+	// A ZIP without the export DB fails extraction again, which shows the
+	// retry ran and that the file is kept for another attempt.
+	zipPath := filepath.Join(h.uploadDir, "upload.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := zip.NewWriter(f).Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	h.setImportFailed(ctx, "job-failed", zipPath, "db down")
+	h.setProgress(ctx, "job-done", hcImportProgress{Status: "completed", Stage: "done"})
+	h.setImportFailed(ctx, "job-gone", filepath.Join(h.uploadDir, "missing.zip"), "db down")
+
+	e := echo.New()
+	retry := func(jobID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/import/health-connect/retry/"+jobID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("jobId")
+		c.SetParamValues(jobID)
+		if err := h.RetryImport(c); err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+
+	for _, jobID := range []string{"job-unknown", "job-done", "job-gone"} {
+		if rec := retry(jobID); rec.Code != http.StatusNotFound {
+			t.Errorf("retry %s: status = %d, want 404", jobID, rec.Code)
+		}
+	}
+
+	if rec := retry("job-failed"); rec.Code != http.StatusAccepted {
+		t.Fatalf("retry: status = %d, want 202: %s", rec.Code, rec.Body.String())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	var progress hcImportProgress
+	var data string
+	for {
+		var err error
+		data, err = h.rdb.Get(ctx, "hc_import:job-failed").Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		json.Unmarshal([]byte(data), &progress)
+		if progress.Status == "failed" && progress.Error != "db down" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("retried import did not finish: %+v", progress)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if want := "health_connect_export.db not found in zip"; progress.Error != want {
+		t.Errorf("Error = %q, want %q", progress.Error, want)
+	}
+	if strings.Contains(data, zipPath) {
+		t.Errorf("public status exposes the zip path: %s", data)
+	}
+	if kept, _ := h.rdb.Get(ctx, "hc_import_zip:job-failed").Result(); kept != zipPath {
+		t.Errorf("kept zip path = %q, want %q", kept, zipPath)
+	}
+	if _, err := os.Stat(zipPath); err != nil {
+		t.Errorf("zip should be kept after a failed retry: %v", err)
+	}
+}
+
+func TestImportHandler_RetryImport_ClaimsOnce(t *testing.T) {
+	h, _ := newTestImportHandler(t)
+	ctx := context.Background()
+
+	zipPath := filepath.Join(h.uploadDir, "upload.zip")
+	if err := os.WriteFile(zipPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	h.setImportFailed(ctx, "job-failed", zipPath, "db down")
+
+	got, err := h.claimFailedJob(ctx, "job-failed")
+	if err != nil || got != zipPath {
+		t.Fatalf("first claim = %q, %v; want %q", got, err, zipPath)
+	}
+	if _, err := h.claimFailedJob(ctx, "job-failed"); !errors.Is(err, errJobNotFailed) {
+		t.Errorf("second claim err = %v, want %v", err, errJobNotFailed)
+	}
+}
+
+func TestImportHandler_ImportLock(t *testing.T) {
+	h, mr := newTestImportHandler(t)
+	h.lockWait = 2 * time.Second
+	ctx := context.Background()
+
+	releaseFirst, err := h.acquireImportLock(ctx, "job-a")
+	if err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+
+	// A concurrent import blocks until the first one releases the lock.
+	acquired := make(chan error, 1)
+	var releaseSecond func()
+	go func() {
+		var err error
+		releaseSecond, err = h.acquireImportLock(ctx, "job-b")
+		acquired <- err
+	}()
+
+	select {
+	case err := <-acquired:
+		t.Fatalf("second import acquired a held lock: %v", err)
+	case <-time.After(150 * time.Millisecond):
+	}
+	if ttl := mr.TTL(hcImportLockKey); ttl != hcImportLockTTL {
+		t.Errorf("lock TTL = %v, want %v", ttl, hcImportLockTTL)
+	}
+
+	releaseFirst()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("second acquire: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("second import never acquired the lock")
+	}
+	if holder, _ := mr.Get(hcImportLockKey); holder != "job-b" {
+		t.Errorf("lock holder = %q, want job-b", holder)
+	}
+
+	// A stale release from the first job must not free the second job's lock.
+	releaseFirst()
+	if holder, _ := mr.Get(hcImportLockKey); holder != "job-b" {
+		t.Errorf("lock holder after stale release = %q, want job-b", holder)
+	}
+	releaseSecond()
+	if mr.Exists(hcImportLockKey) {
+		t.Error("lock not released")
+	}
+}
+
+func TestImportHandler_RunImport_LockTimeout(t *testing.T) {
+	h, mr := newTestImportHandler(t)
+	h.lockWait = 50 * time.Millisecond
+	mr.Set(hcImportLockKey, "job-other")
+
+	zipPath := filepath.Join(h.uploadDir, "upload.zip")
+	if err := os.WriteFile(zipPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	h.runImport("job-1", zipPath)
+
+	data, err := h.rdb.Get(context.Background(), "hc_import:job-1").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var progress hcImportProgress
+	json.Unmarshal([]byte(data), &progress)
+	if progress.Status != "failed" || progress.Error != "another import is still running" {
+		t.Errorf("progress = %+v, want failed with lock error", progress)
+	}
+	if kept, _ := mr.Get("hc_import_zip:job-1"); kept != zipPath {
+		t.Errorf("kept zip path = %q, want %q", kept, zipPath)
+	}
+}
+
+func TestImportHandler_ImportLockRefresh(t *testing.T) {
+	h, mr := newTestImportHandler(t)
+	h.lockRefresh = 10 * time.Millisecond
+
+	release, err := h.acquireImportLock(context.Background(), "job-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// Let most of the TTL run out; the refresher should reset it.
+	mr.FastForward(hcImportLockTTL - time.Minute)
+	deadline := time.Now().Add(time.Second)
+	for mr.TTL(hcImportLockKey) != hcImportLockTTL {
+		if time.Now().After(deadline) {
+			t.Fatalf("lock TTL = %v, want it refreshed to %v", mr.TTL(hcImportLockKey), hcImportLockTTL)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Once another job owns the key the refresher leaves it alone.
+	mr.Set(hcImportLockKey, "job-b")
+	time.Sleep(50 * time.Millisecond)
+	if ttl := mr.TTL(hcImportLockKey); ttl != 0 {
+		t.Errorf("lock TTL after takeover = %v, want none", ttl)
+	}
+}
+
+func TestImportHandler_ImportHealthConnect_LockHeld(t *testing.T) {
+	h, mr := newTestImportHandler(t)
+	h.lockWait = 50 * time.Millisecond
+	mr.Set(hcImportLockKey, "job-other")
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	if _, err := zw.Create("health_connect_export.db"); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "export.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(zipBuf.Bytes())
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/import/health-connect", &body)
+	req.Header.Set(echo.HeaderContentType, mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	if err := h.ImportHealthConnect(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want 409: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestImportHandler_ExtendUpload(t *testing.T) {
+	h, mr := newTestImportHandler(t)
+	e := echo.New()
+	h.Register(e.Group("/api"))
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/api/import/health-connect/init", `{"file_name":"hc.zip","file_size":10,"chunk_size":5}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("init status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var initResp struct {
+		UploadID string `json:"upload_id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &initResp); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nearly expired: extend, then wait past the original deadline.
+	mr.FastForward(chunkSessionTTL - time.Minute)
+	rec = do(http.MethodPost, "/api/import/health-connect/extend/"+initResp.UploadID, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("extend status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"expires_in_seconds":7200}` {
+		t.Errorf("extend body = %s", got)
+	}
+	mr.FastForward(chunkSessionTTL - time.Minute)
+
+	rec = do(http.MethodPut, "/api/import/health-connect/chunk/"+initResp.UploadID+"/0", "12345")
+	if rec.Code != http.StatusOK {
+		t.Errorf("chunk status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = do(http.MethodPost, "/api/import/health-connect/extend/unknown", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
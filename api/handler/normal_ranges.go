@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"vitametron/api/application"
+	"vitametron/api/domain/entity"
+)
+
+const normalRangesCacheTTL = 24 * time.Hour
+
+type NormalRangesHandler struct {
+	uc  *application.NormalRangesUseCase
+	rdb *redis.Client
+}
+
+func NewNormalRangesHandler(uc *application.NormalRangesUseCase, rdb *redis.Client) *NormalRangesHandler {
+	return &NormalRangesHandler{uc: uc, rdb: rdb}
+}
+
+// GetNormalRanges returns personal reference ranges for each biometric.
+// GET /api/biometrics/normal-ranges?window=90
+func (h *NormalRangesHandler) GetNormalRanges(c echo.Context) error {
+	window := 90
+	if w := c.QueryParam("window"); w != "" {
+		n, err := strconv.Atoi(w)
+		if err != nil || n < 7 || n > 365 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "window must be between 7 and 365"})
+		}
+		window = n
+	}
+
+	today, err := parseDate(time.Now().In(jst).Format("2006-01-02"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Only the ranges are cached; today's values change with every sync,
+	// so they are flagged per request.
+	ctx := c.Request().Context()
+	cacheKey := fmt.Sprintf("biometrics:normal_ranges:%d:%s", window, today.Format("2006-01-02"))
+	var result *entity.NormalRanges
+	if cached, err := h.rdb.Get(ctx, cacheKey).Result(); err == nil {
+		if err := json.Unmarshal([]byte(cached), &result); err != nil {
+			log.Printf("warn: decode cached normal ranges: %v", err)
+			result = nil
+		}
+	}
+	if result == nil {
+		result, err = h.uc.Ranges(ctx, today, window)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		if err := h.rdb.Set(ctx, cacheKey, data, normalRangesCacheTTL).Err(); err != nil {
+			log.Printf("warn: cache normal ranges: %v", err)
+		}
+	}
+
+	if err := h.uc.FlagToday(ctx, result); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *NormalRangesHandler) Register(g *echo.Group) {
+	g.GET("/biometrics/normal-ranges", h.GetNormalRanges)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"vitametron/api/application"
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestNormalRangesHandler_CachesRangesNotToday(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	var listCalls int
+	todayHR := 58
+	summaryRepo := &mocks.MockDailySummaryRepository{
+		ListRangeFunc: func(_ context.Context, _, to time.Time) ([]entity.DailySummary, error) {
+			listCalls++
+			return []entity.DailySummary{
+				{Date: to.AddDate(0, 0, -1), RestingHR: 58},
+				{Date: to, RestingHR: 62},
+			}, nil
+		},
+		GetByDateFunc: func(_ context.Context, date time.Time) (*entity.DailySummary, error) {
+			return &entity.DailySummary{Date: date, RestingHR: todayHR}, nil
+		},
+	}
+	qualityRepo := &mocks.MockDataQualityRepository{
+		ListRangeFunc: func(context.Context, time.Time, time.Time) ([]entity.DataQuality, error) {
+			return nil, nil
+		},
+	}
+
+	e := echo.New()
+	NewNormalRangesHandler(application.NewNormalRangesUseCase(summaryRepo, qualityRepo), rdb).
+		Register(e.Group("/api"))
+	get := func() entity.MetricNormalRange {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/biometrics/normal-ranges?window=30", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+		}
+		var got entity.NormalRanges
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Ranges) != 1 || got.Ranges[0].TodayValue == nil || got.Ranges[0].TodayInRange == nil {
+			t.Fatalf("ranges = %+v, want resting_hr with today flags", got.Ranges)
+		}
+		return got.Ranges[0]
+	}
+
+	first := get()
+	if *first.TodayValue != 58 || !*first.TodayInRange {
+		t.Errorf("first today = %v / %v, want 58 in range", *first.TodayValue, *first.TodayInRange)
+	}
+
+	// A later sync changes today's value: the cached ranges are reused but
+	// the today flags follow the new value.
+	todayHR = 75
+	second := get()
+	if listCalls != 1 {
+		t.Errorf("ListRange calls = %d, want 1 (ranges served from cache)", listCalls)
+	}
+	if second.Mean != first.Mean || *second.TodayValue != 75 || *second.TodayInRange {
+		t.Errorf("second = mean %v, today %v / %v; want mean %v, 75 out of range",
+			second.Mean, *second.TodayValue, *second.TodayInRange, first.Mean)
+	}
+
+	// The cache holds no today flags.
+	keys := mr.Keys()
+	if len(keys) != 1 {
+		t.Fatalf("cache keys = %v, want one", keys)
+	}
+	cached, _ := mr.Get(keys[0])
+	var stored entity.NormalRanges
+	if err := json.Unmarshal([]byte(cached), &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Ranges[0].TodayValue != nil || stored.Ranges[0].TodayInRange != nil {
+		t.Errorf("cached range = %+v, want no today flags", stored.Ranges[0])
+	}
+}
+
+func TestNormalRangesHandler_InvalidWindow(t *testing.T) {
+	e := echo.New()
+	NewNormalRangesHandler(nil, nil).Register(e.Group("/api"))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/biometrics/normal-ranges?window=3", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
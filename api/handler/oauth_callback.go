@@ -13,14 +13,21 @@ import (
 )
 
 type OAuthHandler struct {
-	oauth  port.OAuthProvider
-	syncUC application.SyncUseCase
+	oauth       port.OAuthProvider
+	syncUC      application.SyncUseCase
+	tokenHealth port.TokenHealthChecker
 }
 
 func NewOAuthHandler(oauth port.OAuthProvider, syncUC application.SyncUseCase) *OAuthHandler {
 	return &OAuthHandler{oauth: oauth, syncUC: syncUC}
 }
 
+// WithTokenHealth enables GET /auth/fitbit/token-health.
+func (h *OAuthHandler) WithTokenHealth(checker port.TokenHealthChecker) *OAuthHandler {
+	h.tokenHealth = checker
+	return h
+}
+
 func (h *OAuthHandler) Authorize(c echo.Context) error {
 	url, _, err := h.oauth.AuthorizationURL(c.Request().Context())
 	if err != nil {
@@ -74,6 +81,26 @@ func (h *OAuthHandler) Status(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": status})
 }
 
+// TokenHealth reports whether Fitbit is authorized and whether the most
+// recently refreshed token failed to save.
+func (h *OAuthHandler) TokenHealth(c echo.Context) error {
+	ctx := c.Request().Context()
+	saveFailed, err := h.tokenHealth.TokenSaveFailed(ctx)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	authorized, err := h.oauth.IsAuthorized(ctx)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]bool{
+		"authorized":        authorized,
+		"token_save_failed": saveFailed,
+		"healthy":           authorized && !saveFailed,
+	})
+}
+
 func (h *OAuthHandler) Disconnect(c echo.Context) error {
 	if err := h.oauth.Disconnect(c.Request().Context()); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -85,5 +112,8 @@ func (h *OAuthHandler) Register(g *echo.Group) {
 	g.GET("/auth/fitbit", h.Authorize)
 	g.GET("/auth/fitbit/callback", h.Callback)
 	g.GET("/auth/fitbit/status", h.Status)
+	if h.tokenHealth != nil {
+		g.GET("/auth/fitbit/token-health", h.TokenHealth)
+	}
 	g.DELETE("/auth/fitbit", h.Disconnect)
 }
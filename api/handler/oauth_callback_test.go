@@ -41,6 +41,10 @@ func (s *stubOAuthProvider) Disconnect(_ context.Context) error {
 	return s.disconnErr
 }
 
+func (s *stubOAuthProvider) GetAccessToken(_ context.Context) (string, error) {
+	return "", nil
+}
+
 func TestOAuthHandler_Authorize(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/auth/fitbit", nil)
@@ -228,3 +232,42 @@ func TestOAuthHandler_Disconnect(t *testing.T) {
 		t.Errorf("status = %q, want %q", body["status"], "disconnected")
 	}
 }
+
+func TestOAuthHandler_TokenHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		authorized bool
+		failed     bool
+		wantHealth bool
+	}{
+		{"healthy", true, false, true},
+		{"unsaved token", true, true, false},
+		{"not authorized", false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/fitbit/token-health", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := NewOAuthHandler(&stubOAuthProvider{isAuthorized: tt.authorized}, &stubSyncUseCase{}).
+				WithTokenHealth(&stubTokenHealth{failed: tt.failed})
+			if err := h.TokenHealth(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+
+			var body map[string]bool
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["authorized"] != tt.authorized || body["token_save_failed"] != tt.failed || body["healthy"] != tt.wantHealth {
+				t.Errorf("body = %v", body)
+			}
+		})
+	}
+}
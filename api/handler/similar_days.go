@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/application"
+	"vitametron/api/domain/entity"
+)
+
+const maxSimilarDays = 30
+
+var defaultSimilarMetrics = []string{"hrv_daily_rmssd", "spo2_avg", "sleep_duration_min"}
+
+// GetSimilarDays returns the days of the past year closest to date in the
+// given metric space, nearest first.
+// GET /api/biometrics/similar?date=2025-06-15&top=5&metrics=hrv_daily_rmssd,spo2_avg,sleep_duration_min
+func (h *BiometricsHandler) GetSimilarDays(c echo.Context) error {
+	date, err := parseDate(c.QueryParam("date"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
+	}
+
+	top := 5
+	if t := c.QueryParam("top"); t != "" {
+		n, err := strconv.Atoi(t)
+		if err != nil || n < 1 || n > maxSimilarDays {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "top must be between 1 and 30"})
+		}
+		top = n
+	}
+
+	names := defaultSimilarMetrics
+	if m := c.QueryParam("metrics"); m != "" {
+		names = strings.Split(m, ",")
+	}
+	metrics := make([]entity.SummaryMetric, 0, len(names))
+	for _, name := range names {
+		m, ok := application.LookupSummaryMetric(strings.TrimSpace(name))
+		if !ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "unknown metric: " + name})
+		}
+		metrics = append(metrics, m)
+	}
+
+	result, err := h.similarDays.Execute(c.Request().Context(), date, metrics, top)
+	if errors.Is(err, entity.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "no data for date"})
+	}
+	if errors.Is(err, application.ErrQueryDayMissingMetric) {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/application"
+	"vitametron/api/domain/entity"
+)
+
+func TestBiometricsHandler_GetSimilarDays(t *testing.T) {
+	date := time.Date(2025, 6, 15, 0, 0, 0, 0, jst)
+	repo := &stubDailySummaryRepo{summaries: []entity.DailySummary{
+		{Date: date.AddDate(0, 0, -2), RestingHR: 70, Steps: 4000},
+		{Date: date.AddDate(0, 0, -1), RestingHR: 61, Steps: 9000},
+		{Date: date, RestingHR: 60, Steps: 9000},
+	}}
+	h := newHandler(repo).WithSimilarDays(application.NewSimilarDaysUseCase(repo))
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantDays   int
+	}{
+		{"ranked", "date=2025-06-15&metrics=resting_hr,steps", http.StatusOK, 2},
+		{"top", "date=2025-06-15&metrics=resting_hr,steps&top=1", http.StatusOK, 1},
+		{"query day missing default metrics", "date=2025-06-15", http.StatusUnprocessableEntity, 0},
+		{"no data", "date=2025-06-20&metrics=resting_hr", http.StatusNotFound, 0},
+		{"unknown metric", "date=2025-06-15&metrics=resting_hr,mood", http.StatusBadRequest, 0},
+		{"invalid top", "date=2025-06-15&top=0", http.StatusBadRequest, 0},
+		{"invalid date", "date=June", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/biometrics/similar?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if err := h.GetSimilarDays(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got entity.SimilarDays
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Days) != tt.wantDays {
+				t.Fatalf("len(days) = %d, want %d", len(got.Days), tt.wantDays)
+			}
+			if got.Days[0].Summary.RestingHR != 61 {
+				t.Errorf("nearest day = %+v, want the 61 bpm day", got.Days[0].Summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"vitametron/api/adapter/mlclient"
+)
+
+const sleepPredictionCacheTTL = time.Hour
+
+type SleepPredictionHandler struct {
+	mlClient *mlclient.Client
+	rdb      *redis.Client
+}
+
+func NewSleepPredictionHandler(mlClient *mlclient.Client, rdb *redis.Client) *SleepPredictionHandler {
+	return &SleepPredictionHandler{mlClient: mlClient, rdb: rdb}
+}
+
+// GetPrediction forecasts the night after date from that day's biometrics.
+// GET /api/sleep/predict?date=2026-02-17
+func (h *SleepPredictionHandler) GetPrediction(c echo.Context) error {
+	dateStr := c.QueryParam("date")
+	if dateStr == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "date is required"})
+	}
+
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format"})
+	}
+
+	ctx := c.Request().Context()
+	cacheKey := "sleep:predict:" + date.Format("2006-01-02")
+	if cached, err := h.rdb.Get(ctx, cacheKey).Result(); err == nil {
+		return c.JSONBlob(http.StatusOK, []byte(cached))
+	}
+
+	prediction, err := h.mlClient.PredictSleepQuality(ctx, date)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	data, err := json.Marshal(prediction)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if err := h.rdb.Set(ctx, cacheKey, data, sleepPredictionCacheTTL).Err(); err != nil {
+		log.Printf("warn: cache sleep prediction: %v", err)
+	}
+
+	return c.JSONBlob(http.StatusOK, data)
+}
+
+func (h *SleepPredictionHandler) Register(g *echo.Group) {
+	g.GET("/sleep/predict", h.GetPrediction)
+}
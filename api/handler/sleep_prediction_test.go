@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestSleepPredictionHandler_GetPrediction_Cached(t *testing.T) {
+	calls := 0
+	ml := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/sleep/predict" || r.URL.Query().Get("date") != "2026-02-17" {
+			t.Errorf("ML request = %s, want /sleep/predict?date=2026-02-17", r.URL)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"target_date":            "2026-02-18",
+			"predicted_efficiency":   91,
+			"predicted_duration_min": 430,
+			"confidence":             0.8,
+			"model_version":          "sleep-v1",
+		})
+	}))
+	defer ml.Close()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	h := NewSleepPredictionHandler(newTestMLClient(ml.URL), rdb)
+
+	e := echo.New()
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/sleep/predict?date=2026-02-17", nil)
+		rec := httptest.NewRecorder()
+		if err := h.GetPrediction(e.NewContext(req, rec)); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200; body = %s", rec.Code, rec.Body.String())
+		}
+		var got struct {
+			PredictedEfficiency float32
+			ModelVersion        string
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.PredictedEfficiency != 91 || got.ModelVersion != "sleep-v1" {
+			t.Errorf("response %d = %+v", i, got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("ML calls = %d, want 1 (second request served from cache)", calls)
+	}
+	if ttl := mr.TTL("sleep:predict:2026-02-17"); ttl != time.Hour {
+		t.Errorf("cache TTL = %v, want 1h", ttl)
+	}
+}
+
+func TestSleepPredictionHandler_GetPrediction_MissingDate(t *testing.T) {
+	h := NewSleepPredictionHandler(newTestMLClient("http://ml.invalid"), nil)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/sleep/predict", nil)
+	rec := httptest.NewRecorder()
+	if err := h.GetPrediction(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
@@ -1,22 +1,62 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"vitametron/api/application"
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
 )
 
+// triggerTimeout bounds a manual sync triggered through the API.
+const triggerTimeout = 60 * time.Second
+
 type SyncHandler struct {
-	uc application.SyncUseCase
+	uc      application.SyncUseCase
+	status  port.SyncStatusStore
+	oauth   map[string]port.OAuthProvider
+	trigger application.SyncReportUseCase
+	keyAuth echo.MiddlewareFunc
+
+	scheduler   application.SyncScheduler
+	configStore port.SchedulerConfigStore
+	configAuth  echo.MiddlewareFunc
 }
 
 func NewSyncHandler(uc application.SyncUseCase) *SyncHandler {
 	return &SyncHandler{uc: uc}
 }
 
+// WithProviderStatus enables GET /sync/providers. oauth maps provider names to
+// their authorization; those providers are listed even before their first sync.
+func (h *SyncHandler) WithProviderStatus(store port.SyncStatusStore, oauth map[string]port.OAuthProvider) *SyncHandler {
+	h.status = store
+	h.oauth = oauth
+	return h
+}
+
+// WithTrigger enables POST /sync/trigger, guarded by mw.
+func (h *SyncHandler) WithTrigger(uc application.SyncReportUseCase, mw echo.MiddlewareFunc) *SyncHandler {
+	h.trigger = uc
+	h.keyAuth = mw
+	return h
+}
+
+// WithSchedulerConfig enables GET and PUT /sync/config. Updates are guarded
+// by mw and persisted to store so they survive a restart.
+func (h *SyncHandler) WithSchedulerConfig(sched application.SyncScheduler, store port.SchedulerConfigStore, mw echo.MiddlewareFunc) *SyncHandler {
+	h.scheduler = sched
+	h.configStore = store
+	h.configAuth = mw
+	return h
+}
+
 func (h *SyncHandler) Sync(c echo.Context) error {
 	dateStr := c.QueryParam("date")
 	var date time.Time
@@ -40,6 +80,111 @@ func (h *SyncHandler) Sync(c echo.Context) error {
 	})
 }
 
+// Trigger syncs one date immediately, outside the scheduler, and reports
+// which metrics were stored and which optional fetches failed.
+// POST /api/sync/trigger?date=2025-01-15
+func (h *SyncHandler) Trigger(c echo.Context) error {
+	date := time.Now().In(jst)
+	if s := c.QueryParam("date"); s != "" {
+		d, err := parseDate(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid date format, use YYYY-MM-DD"})
+		}
+		date = d
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), triggerTimeout)
+	defer cancel()
+
+	report, err := h.trigger.SyncDateReport(ctx, date)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+type updateSyncConfigRequest struct {
+	IntervalMin int `json:"interval_min"`
+}
+
+// GetConfig returns the current sync interval and its allowed bounds.
+// GET /api/sync/config
+func (h *SyncHandler) GetConfig(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.schedulerConfig())
+}
+
+// UpdateConfig changes the sync interval without a restart.
+// PUT /api/sync/config
+func (h *SyncHandler) UpdateConfig(c echo.Context) error {
+	var req updateSyncConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.IntervalMin < entity.MinSyncIntervalMin || req.IntervalMin > entity.MaxSyncIntervalMin {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("interval_min must be between %d and %d", entity.MinSyncIntervalMin, entity.MaxSyncIntervalMin),
+		})
+	}
+
+	if err := h.configStore.SetIntervalMin(c.Request().Context(), req.IntervalMin); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	h.scheduler.SetInterval(time.Duration(req.IntervalMin) * time.Minute)
+	return c.JSON(http.StatusOK, h.schedulerConfig())
+}
+
+func (h *SyncHandler) schedulerConfig() entity.SyncSchedulerConfig {
+	return entity.SyncSchedulerConfig{
+		IntervalMin:    int(h.scheduler.Interval() / time.Minute),
+		MinIntervalMin: entity.MinSyncIntervalMin,
+		MaxIntervalMin: entity.MaxSyncIntervalMin,
+	}
+}
+
+// GetProviderStatuses reports each provider's last sync and authorization.
+// GET /api/sync/providers
+func (h *SyncHandler) GetProviderStatuses(c echo.Context) error {
+	ctx := c.Request().Context()
+	statuses, err := h.status.List(ctx)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	seen := make(map[string]bool, len(statuses))
+	for _, s := range statuses {
+		seen[s.Provider] = true
+	}
+	for name := range h.oauth {
+		if !seen[name] {
+			statuses = append(statuses, entity.ProviderSyncStatus{Provider: name})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Provider < statuses[j].Provider })
+
+	for i := range statuses {
+		oauth, ok := h.oauth[statuses[i].Provider]
+		if !ok {
+			continue
+		}
+		authorized, err := oauth.IsAuthorized(ctx)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		statuses[i].IsAuthorized = authorized
+	}
+	return c.JSON(http.StatusOK, statuses)
+}
+
 func (h *SyncHandler) Register(g *echo.Group) {
 	g.POST("/sync", h.Sync)
+	if h.status != nil {
+		g.GET("/sync/providers", h.GetProviderStatuses)
+	}
+	if h.trigger != nil && h.keyAuth != nil {
+		g.POST("/sync/trigger", h.Trigger, h.keyAuth)
+	}
+	if h.scheduler != nil && h.configStore != nil && h.configAuth != nil {
+		g.GET("/sync/config", h.GetConfig)
+		g.PUT("/sync/config", h.UpdateConfig, h.configAuth)
+	}
 }
@@ -2,12 +2,20 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/labstack/echo/v4"
+
+	"vitametron/api/application"
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+	"vitametron/api/mocks"
 )
 
 type stubSyncUseCase struct {
@@ -65,3 +73,238 @@ func TestSyncHandler_InvalidDate(t *testing.T) {
 		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
 	}
 }
+
+func TestSyncHandler_GetProviderStatuses(t *testing.T) {
+	lastSync := time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC)
+	store := &mocks.MockSyncStatusStore{
+		ListFunc: func(_ context.Context) ([]entity.ProviderSyncStatus, error) {
+			return []entity.ProviderSyncStatus{
+				{Provider: "garmin", LastError: "unauthorized"},
+				{Provider: "fitbit", LastSyncAt: &lastSync},
+			}, nil
+		},
+	}
+	authorized := func(ok bool) *mocks.MockOAuthProvider {
+		return &mocks.MockOAuthProvider{
+			IsAuthorizedFunc: func(_ context.Context) (bool, error) { return ok, nil },
+		}
+	}
+	h := NewSyncHandler(&stubSyncUseCase{}).WithProviderStatus(store, map[string]port.OAuthProvider{
+		"fitbit": authorized(true),
+		"oura":   authorized(false), // configured but never synced
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/sync/providers", nil)
+	rec := httptest.NewRecorder()
+	if err := h.GetProviderStatuses(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var got []entity.ProviderSyncStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("len = %d, want 3: %+v", len(got), got)
+	}
+	want := []struct {
+		provider   string
+		authorized bool
+		synced     bool
+	}{
+		{"fitbit", true, true},
+		{"garmin", false, false},
+		{"oura", false, false},
+	}
+	for i, w := range want {
+		if got[i].Provider != w.provider || got[i].IsAuthorized != w.authorized || (got[i].LastSyncAt != nil) != w.synced {
+			t.Errorf("got[%d] = %+v, want provider=%s authorized=%v synced=%v", i, got[i], w.provider, w.authorized, w.synced)
+		}
+	}
+	if got[1].LastError != "unauthorized" {
+		t.Errorf("garmin LastError = %q, want %q", got[1].LastError, "unauthorized")
+	}
+}
+
+func TestSyncHandler_Trigger(t *testing.T) {
+	unavailable := errors.New("unavailable")
+	provider := &mocks.MockBiometricsProvider{
+		FetchDailySummaryFunc: func(_ context.Context, date time.Time) (*entity.DailySummary, error) {
+			return &entity.DailySummary{Date: date, Steps: 8000}, nil
+		},
+		FetchHRVFunc: func(_ context.Context, _ time.Time) (float32, float32, error) { return 42, 50, nil },
+		FetchSpO2Func: func(_ context.Context, _ time.Time) (float32, float32, float32, error) {
+			return 0, 0, 0, unavailable
+		},
+		FetchBreathingRateFunc: func(_ context.Context, _ time.Time) (float32, float32, float32, float32, error) {
+			return 0, 0, 0, 0, unavailable
+		},
+		FetchSkinTemperatureFunc: func(_ context.Context, _ time.Time) (float32, error) { return 0, unavailable },
+		FetchWaterLogFunc:        func(_ context.Context, _ time.Time) (int, error) { return 0, unavailable },
+		FetchSleepStagesFunc: func(_ context.Context, _ time.Time) ([]entity.SleepStage, *entity.SleepRecord, error) {
+			return nil, nil, unavailable
+		},
+		FetchHeartRateIntradayFunc: func(_ context.Context, _ time.Time) ([]entity.HeartRateSample, error) {
+			return nil, unavailable
+		},
+		FetchExerciseLogsFunc: func(_ context.Context, _ time.Time) ([]entity.ExerciseLog, error) {
+			return nil, unavailable
+		},
+	}
+	var upserted *entity.DailySummary
+	var deadline time.Time
+	summaries := &mocks.MockDailySummaryRepository{
+		UpsertFunc: func(ctx context.Context, s *entity.DailySummary) error {
+			upserted = s
+			deadline, _ = ctx.Deadline()
+			return nil
+		},
+	}
+	uc := application.NewSyncBiometricsUseCase(provider, summaries, &mocks.MockHeartRateRepository{},
+		&mocks.MockSleepStageRepository{}, &mocks.MockExerciseRepository{}, nil)
+	pass := func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	h := NewSyncHandler(uc).WithTrigger(uc, pass)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/sync/trigger?date=2025-01-15", nil)
+	rec := httptest.NewRecorder()
+	if err := h.Trigger(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	if upserted == nil || upserted.Steps != 8000 || upserted.HRVDailyRMSSD == nil {
+		t.Fatalf("upserted = %+v, want synced summary with HRV", upserted)
+	}
+	if remaining := time.Until(deadline); remaining <= 0 || remaining > triggerTimeout {
+		t.Errorf("context deadline in %v, want within %v", remaining, triggerTimeout)
+	}
+
+	var report entity.SyncReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Date != "2025-01-15" {
+		t.Errorf("Date = %q, want 2025-01-15", report.Date)
+	}
+	if len(report.Populated) != 2 || report.Populated[0] != entity.SyncStepDailySummary || report.Populated[1] != entity.SyncStepHRV {
+		t.Errorf("Populated = %v, want [daily_summary hrv]", report.Populated)
+	}
+	if report.SoftErrors[entity.SyncStepSpO2] != "unavailable" || len(report.SoftErrors) != 7 {
+		t.Errorf("SoftErrors = %v, want 7 entries incl. spo2", report.SoftErrors)
+	}
+}
+
+func TestSyncHandler_Trigger_Errors(t *testing.T) {
+	provider := &mocks.MockBiometricsProvider{
+		FetchDailySummaryFunc: func(_ context.Context, _ time.Time) (*entity.DailySummary, error) {
+			return nil, errors.New("unauthorized")
+		},
+	}
+	uc := application.NewSyncBiometricsUseCase(provider, nil, nil, nil, nil, nil)
+	h := NewSyncHandler(uc).WithTrigger(uc, func(next echo.HandlerFunc) echo.HandlerFunc { return next })
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"invalid date", "?date=bad", http.StatusBadRequest},
+		{"summary fetch fails", "?date=2025-01-15", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/sync/trigger"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			if err := h.Trigger(echo.New().NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+type stubSyncScheduler struct {
+	interval time.Duration
+}
+
+func (s *stubSyncScheduler) Interval() time.Duration     { return s.interval }
+func (s *stubSyncScheduler) SetInterval(d time.Duration) { s.interval = d }
+
+func TestSyncHandler_Config(t *testing.T) {
+	var stored int
+	store := &mocks.MockSchedulerConfigStore{
+		SetIntervalMinFunc: func(_ context.Context, minutes int) error {
+			stored = minutes
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		wantStatus   int
+		wantInterval int
+	}{
+		{"update", `{"interval_min":30}`, http.StatusOK, 30},
+		{"lower bound", `{"interval_min":5}`, http.StatusOK, 5},
+		{"upper bound", `{"interval_min":1440}`, http.StatusOK, 1440},
+		{"too short", `{"interval_min":4}`, http.StatusBadRequest, 10},
+		{"too long", `{"interval_min":1441}`, http.StatusBadRequest, 10},
+		{"missing", `{}`, http.StatusBadRequest, 10},
+		{"invalid body", `{"interval_min":"30"}`, http.StatusBadRequest, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored = 0
+			sched := &stubSyncScheduler{interval: 10 * time.Minute}
+			pass := func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+			h := NewSyncHandler(&stubSyncUseCase{}).WithSchedulerConfig(sched, store, pass)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/api/sync/config", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			if err := h.UpdateConfig(e.NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got := int(sched.interval / time.Minute); got != tt.wantInterval {
+				t.Errorf("scheduler interval = %d min, want %d", got, tt.wantInterval)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if stored != 0 {
+					t.Errorf("stored interval %d on a rejected update", stored)
+				}
+				return
+			}
+			if stored != tt.wantInterval {
+				t.Errorf("stored interval = %d, want %d", stored, tt.wantInterval)
+			}
+
+			req = httptest.NewRequest(http.MethodGet, "/api/sync/config", nil)
+			rec = httptest.NewRecorder()
+			if err := h.GetConfig(e.NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			var got entity.SyncSchedulerConfig
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			want := entity.SyncSchedulerConfig{IntervalMin: tt.wantInterval, MinIntervalMin: 5, MaxIntervalMin: 1440}
+			if got != want {
+				t.Errorf("GET config = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
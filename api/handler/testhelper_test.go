@@ -3,5 +3,9 @@ package handler
 import "vitametron/api/adapter/mlclient"
 
 func newTestMLClient(url string) *mlclient.Client {
-	return mlclient.New(url)
+	c, err := mlclient.New(url)
+	if err != nil {
+		panic(err)
+	}
+	return c
 }
@@ -1,10 +1,52 @@
 package handler
 
-import "time"
+import (
+	"errors"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
 
 var jst = time.FixedZone("JST", 9*3600)
 
-// parseDate parses "YYYY-MM-DD" as midnight in JST.
+// defaultTimezone names jst in X-Response-Timezone when no ?tz= is given.
+const defaultTimezone = "Asia/Tokyo"
+
+var errInvalidTimezone = errors.New("invalid tz, use an IANA name such as Asia/Tokyo")
+
+// todayParam is the date value resolved server-side to the current date.
+const todayParam = "today"
+
+// parseDate parses "YYYY-MM-DD" or "today" as midnight in JST.
 func parseDate(s string) (time.Time, error) {
-	return time.ParseInLocation("2006-01-02", s, jst)
+	return parseDateIn(s, jst)
+}
+
+// parseDateIn parses "YYYY-MM-DD" as midnight in loc. "today" resolves to
+// the current date in loc.
+func parseDateIn(s string, loc *time.Location) (time.Time, error) {
+	if s == todayParam {
+		now := time.Now().In(loc)
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc), nil
+	}
+	return time.ParseInLocation("2006-01-02", s, loc)
+}
+
+// requestLocation resolves the ?tz= IANA zone that date parameters are read
+// in, defaulting to JST, and reports it in the X-Response-Timezone header.
+func requestLocation(c echo.Context) (*time.Location, error) {
+	name := c.QueryParam("tz")
+	loc := jst
+	if name == "" {
+		name = defaultTimezone
+	} else {
+		// LoadLocation maps "" and "Local" to server zones, which callers cannot mean.
+		l, err := time.LoadLocation(name)
+		if err != nil || name == "Local" {
+			return nil, errInvalidTimezone
+		}
+		loc = l
+	}
+	c.Response().Header().Set("X-Response-Timezone", name)
+	return loc, nil
 }
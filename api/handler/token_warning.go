@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"vitametron/api/domain/port"
+)
+
+const tokenUnsavedWarning = "fitbit_token_unsaved"
+
+// tokenWarning adds a "warning" field to JSON object responses while the
+// Fitbit token save failure flag is set. Non-object bodies only get the
+// X-Warning header. A failing flag lookup never fails the request.
+//
+// The ETag a handler computes does not cover the injected field, so while
+// the flag is set ETags are skipped: If-None-Match is ignored, so a cached
+// copy without the warning is never confirmed with a 304, and no ETag is
+// sent with the warned body.
+func tokenWarning(checker port.TokenHealthChecker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			failed, err := checker.TokenSaveFailed(c.Request().Context())
+			if err != nil {
+				log.Printf("warn: check token save flag: %v", err)
+				return next(c)
+			}
+			if !failed {
+				return next(c)
+			}
+
+			c.Request().Header.Del("If-None-Match")
+			res := c.Response()
+			orig := res.Writer
+			buf := &bufferedWriter{ResponseWriter: orig, status: http.StatusOK}
+			res.Writer = buf
+			err = next(c)
+			res.Writer = orig
+
+			body := buf.body.Bytes()
+			if strings.HasPrefix(orig.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+				body = injectWarning(body)
+			}
+			orig.Header().Del("ETag")
+			orig.Header().Set("X-Warning", tokenUnsavedWarning)
+			orig.WriteHeader(buf.status)
+			if _, werr := orig.Write(body); werr != nil {
+				log.Printf("warn: write response: %v", werr)
+			}
+			return err
+		}
+	}
+}
+
+// bufferedWriter holds the status and body so the middleware can rewrite
+// the body once the handler has finished.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) { w.status = status }
+
+func (w *bufferedWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+// injectWarning prepends the warning field to a JSON object body and returns
+// any other body unchanged.
+func injectWarning(body []byte) []byte {
+	return injectField(body, `"warning":"`+tokenUnsavedWarning+`"`)
+}
+
+// injectField prepends an encoded "key":value pair to a JSON object body and
+// returns any other body unchanged.
+func injectField(body []byte, field string) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) < 2 || trimmed[0] != '{' {
+		return body
+	}
+	rest := bytes.TrimSpace(trimmed[1:])
+	if rest[0] != '}' {
+		field += ","
+	}
+	out := make([]byte, 0, len(trimmed)+len(field)+1)
+	out = append(out, '{')
+	out = append(out, field...)
+	out = append(out, rest...)
+	return append(out, '\n')
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type stubTokenHealth struct {
+	failed bool
+	err    error
+}
+
+func (s *stubTokenHealth) TokenSaveFailed(_ context.Context) (bool, error) {
+	return s.failed, s.err
+}
+
+func TestTokenWarning(t *testing.T) {
+	tests := []struct {
+		name        string
+		checker     *stubTokenHealth
+		handler     echo.HandlerFunc
+		wantWarning string
+		wantHeader  string
+		wantStatus  int
+	}{
+		{
+			name:    "flag set adds warning to object",
+			checker: &stubTokenHealth{failed: true},
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]int{"steps": 100})
+			},
+			wantWarning: tokenUnsavedWarning,
+			wantHeader:  tokenUnsavedWarning,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:    "flag set keeps error status",
+			checker: &stubTokenHealth{failed: true},
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "no data for date"})
+			},
+			wantWarning: tokenUnsavedWarning,
+			wantHeader:  tokenUnsavedWarning,
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			name:    "flag unset leaves response alone",
+			checker: &stubTokenHealth{},
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]int{"steps": 100})
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "lookup error does not fail request",
+			checker: &stubTokenHealth{err: errors.New("redis down")},
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]int{"steps": 100})
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/biometrics", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if err := tokenWarning(tt.checker)(tt.handler)(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("X-Warning"); got != tt.wantHeader {
+				t.Errorf("X-Warning = %q, want %q", got, tt.wantHeader)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+			}
+			got, _ := body["warning"].(string)
+			if got != tt.wantWarning {
+				t.Errorf("warning = %q, want %q", got, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestTokenWarning_SkipsETag(t *testing.T) {
+	payload := map[string]int{"steps": 100}
+	handler := func(c echo.Context) error { return jsonWithETag(c, payload) }
+	etag := generateETag(payload)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/biometrics", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	if err := tokenWarning(&stubTokenHealth{failed: true})(handler)(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The client's copy has no warning, so it must not be confirmed.
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got != "" {
+		t.Errorf("ETag = %q, want none on a warned body", got)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+	}
+	if body["warning"] != tokenUnsavedWarning {
+		t.Errorf("warning = %v, want %s", body["warning"], tokenUnsavedWarning)
+	}
+}
+
+func TestInjectWarning(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{}`, `{"warning":"fitbit_token_unsaved"}` + "\n"},
+		{`{"a":1}` + "\n", `{"warning":"fitbit_token_unsaved","a":1}` + "\n"},
+		{`[1,2]`, `[1,2]`},
+		{``, ``},
+	}
+	for _, tt := range tests {
+		if got := string(injectWarning([]byte(tt.in))); got != tt.want {
+			t.Errorf("injectWarning(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
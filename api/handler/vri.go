@@ -1,15 +1,20 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"log"
 	"math"
 	"net/http"
 	"sort"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/sync/singleflight"
 
 	"vitametron/api/adapter/mlclient"
+	"vitametron/api/application"
 	"vitametron/api/domain/entity"
 	"vitametron/api/domain/port"
 )
@@ -17,12 +22,22 @@ import (
 type VRIHandler struct {
 	mlClient *mlclient.Client
 	vriRepo  port.VRIRepository
+	// inflight collapses concurrent on-demand computes of the same date.
+	inflight singleflight.Group
+	// timeout bounds an on-demand compute; zero waits for the ML client.
+	timeout time.Duration
 }
 
 func NewVRIHandler(mlClient *mlclient.Client, vriRepo port.VRIRepository) *VRIHandler {
 	return &VRIHandler{mlClient: mlClient, vriRepo: vriRepo}
 }
 
+// WithTimeout makes GetVRI answer 503 when an on-demand compute takes longer than d.
+func (h *VRIHandler) WithTimeout(d time.Duration) *VRIHandler {
+	h.timeout = d
+	return h
+}
+
 func (h *VRIHandler) GetVRI(c echo.Context) error {
 	dateStr := c.QueryParam("date")
 	if dateStr == "" {
@@ -41,16 +56,34 @@ func (h *VRIHandler) GetVRI(c echo.Context) error {
 	}
 	if score != nil {
 		score.ContributingFactors = buildContributingFactors(score)
-		return c.JSON(http.StatusOK, score)
+		return jsonWithETag(c, h.withPercentile(c.Request().Context(), score))
 	}
 
 	// Fall back to ML client for on-demand compute
-	score, err = h.mlClient.GetVRI(c.Request().Context(), date)
+	score, err = dedupe(c.Request().Context(), &h.inflight, date.Format("2006-01-02"),
+		func(ctx context.Context) (*entity.VRIScore, error) {
+			ctx, cancel := withDeadline(ctx, h.timeout)
+			defer cancel()
+			return h.mlClient.GetVRI(ctx, date)
+		})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "vri_computation_timeout"})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, score)
+	return jsonWithETag(c, h.withPercentile(c.Request().Context(), score))
+}
+
+// withPercentile returns a copy of score with PopulationPercentile set. The
+// percentile is supplementary, so a failed history lookup only logs.
+func (h *VRIHandler) withPercentile(ctx context.Context, score *entity.VRIScore) *entity.VRIScore {
+	scores := []entity.VRIScore{*score}
+	if err := application.AttachVRIPercentiles(ctx, h.vriRepo, scores); err != nil {
+		log.Printf("warn: VRI percentile for %s: %v", score.Date.Format("2006-01-02"), err)
+	}
+	return &scores[0]
 }
 
 func (h *VRIHandler) GetVRIRange(c echo.Context) error {
@@ -87,6 +120,9 @@ func (h *VRIHandler) GetVRIRange(c echo.Context) error {
 	if scores == nil {
 		scores = []entity.VRIScore{}
 	}
+	if err := application.AttachVRIPercentiles(c.Request().Context(), h.vriRepo, scores); err != nil {
+		log.Printf("warn: VRI percentiles for %s..%s: %v", fromStr, toStr, err)
+	}
 
 	return c.JSON(http.StatusOK, scores)
 }
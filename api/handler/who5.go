@@ -68,6 +68,26 @@ func (h *WHO5Handler) GetLatest(c echo.Context) error {
 	return c.JSON(http.StatusOK, a)
 }
 
+// GetScreeningStatus returns the depression screening flag and score
+// interpretation of the latest assessment.
+func (h *WHO5Handler) GetScreeningStatus(c echo.Context) error {
+	a, err := h.uc.GetLatest(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if a == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "no assessment found"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"assessment_id":             a.ID,
+		"assessed_at":               a.AssessedAt,
+		"normalized_score":          a.Percentage,
+		"depression_screening_flag": a.DepressionScreeningFlag,
+		"score_interpretation":      a.ScoreInterpretation,
+	})
+}
+
 func (h *WHO5Handler) GetByID(c echo.Context) error {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -108,5 +128,6 @@ func (h *WHO5Handler) Register(g *echo.Group) {
 	g.POST("/who5", h.Create)
 	g.GET("/who5", h.List)
 	g.GET("/who5/latest", h.GetLatest)
+	g.GET("/who5/screening-status", h.GetScreeningStatus)
 	g.GET("/who5/:id", h.GetByID)
 }
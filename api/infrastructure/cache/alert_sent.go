@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// alertSentTTL outlives the days a date keeps being re-synced, after which
+// the marks are no longer needed.
+const alertSentTTL = 72 * time.Hour
+
+// AlertSentStore marks sent alerts in Redis as <kind>_alert:sent:<date>.
+type AlertSentStore struct {
+	rdb *redis.Client
+}
+
+func NewAlertSentStore(rdb *redis.Client) *AlertSentStore {
+	return &AlertSentStore{rdb: rdb}
+}
+
+func alertSentKey(kind, date string) string {
+	return fmt.Sprintf("%s_alert:sent:%s", kind, date)
+}
+
+func (s *AlertSentStore) MarkSent(ctx context.Context, kind, date string) (bool, error) {
+	return s.rdb.SetNX(ctx, alertSentKey(kind, date), time.Now().UTC().Format(time.RFC3339), alertSentTTL).Result()
+}
+
+func (s *AlertSentStore) ClearSent(ctx context.Context, kind, date string) error {
+	return s.rdb.Del(ctx, alertSentKey(kind, date)).Err()
+}
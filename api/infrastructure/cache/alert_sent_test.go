@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestAlertSentStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	store := NewAlertSentStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	ctx := context.Background()
+
+	if first, err := store.MarkSent(ctx, "hr", "2026-04-18"); err != nil || !first {
+		t.Fatalf("first MarkSent() = %v, %v; want true, nil", first, err)
+	}
+	if first, err := store.MarkSent(ctx, "hr", "2026-04-18"); err != nil || first {
+		t.Errorf("repeat MarkSent() = %v, %v; want false, nil", first, err)
+	}
+	if first, _ := store.MarkSent(ctx, "sleep", "2026-04-18"); !first {
+		t.Error("another kind on the same date should not be marked")
+	}
+	if ttl := mr.TTL("hr_alert:sent:2026-04-18"); ttl != alertSentTTL {
+		t.Errorf("TTL = %v, want %v", ttl, alertSentTTL)
+	}
+
+	if err := store.ClearSent(ctx, "hr", "2026-04-18"); err != nil {
+		t.Fatal(err)
+	}
+	if first, _ := store.MarkSent(ctx, "hr", "2026-04-18"); !first {
+		t.Error("MarkSent() after ClearSent() should report first")
+	}
+}
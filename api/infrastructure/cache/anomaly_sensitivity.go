@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const anomalySensitivityKey = "user:anomaly_sensitivity"
+
+// AnomalySensitivityStore keeps the user's anomaly sensitivity in Redis.
+type AnomalySensitivityStore struct {
+	rdb *redis.Client
+}
+
+func NewAnomalySensitivityStore(rdb *redis.Client) *AnomalySensitivityStore {
+	return &AnomalySensitivityStore{rdb: rdb}
+}
+
+func (s *AnomalySensitivityStore) Sensitivity(ctx context.Context) (string, bool, error) {
+	v, err := s.rdb.Get(ctx, anomalySensitivityKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return v, true, nil
+}
+
+func (s *AnomalySensitivityStore) SetSensitivity(ctx context.Context, sensitivity string) error {
+	return s.rdb.Set(ctx, anomalySensitivityKey, sensitivity, 0).Err()
+}
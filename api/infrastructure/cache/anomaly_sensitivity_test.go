@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestAnomalySensitivityStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	store := NewAnomalySensitivityStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	ctx := context.Background()
+
+	if _, ok, err := store.Sensitivity(ctx); err != nil || ok {
+		t.Fatalf("Sensitivity() before set = ok %v, err %v; want not found", ok, err)
+	}
+
+	if err := store.SetSensitivity(ctx, "high"); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := store.Sensitivity(ctx)
+	if err != nil || !ok || got != "high" {
+		t.Errorf("Sensitivity() = %q, %v, %v; want high, true, nil", got, ok, err)
+	}
+	if v, _ := mr.Get("user:anomaly_sensitivity"); v != "high" {
+		t.Errorf("stored value = %q, want high", v)
+	}
+}
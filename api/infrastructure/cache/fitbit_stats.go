@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/domain/port"
+)
+
+const (
+	lifetimeStatsKey = "fitbit:lifetime_stats"
+	lifetimeStatsTTL = time.Hour
+	badgesKey        = "fitbit:badges"
+	badgesTTL        = 6 * time.Hour
+)
+
+// FitbitStatsCache wraps a FitbitStatsProvider with Redis caching. Lifetime
+// totals change slowly and badges rarely, so both are served from cache to
+// spare the Fitbit rate limit.
+type FitbitStatsCache struct {
+	provider port.FitbitStatsProvider
+	rdb      *redis.Client
+}
+
+func NewFitbitStatsCache(provider port.FitbitStatsProvider, rdb *redis.Client) *FitbitStatsCache {
+	return &FitbitStatsCache{provider: provider, rdb: rdb}
+}
+
+func (c *FitbitStatsCache) FetchLifetimeStats(ctx context.Context) (*entity.FitbitLifetimeStats, error) {
+	var stats entity.FitbitLifetimeStats
+	if c.get(ctx, lifetimeStatsKey, &stats) {
+		return &stats, nil
+	}
+	fresh, err := c.provider.FetchLifetimeStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, lifetimeStatsKey, fresh, lifetimeStatsTTL)
+	return fresh, nil
+}
+
+func (c *FitbitStatsCache) FetchBadges(ctx context.Context) ([]entity.FitbitBadge, error) {
+	var badges []entity.FitbitBadge
+	if c.get(ctx, badgesKey, &badges) {
+		return badges, nil
+	}
+	fresh, err := c.provider.FetchBadges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, badgesKey, fresh, badgesTTL)
+	return fresh, nil
+}
+
+// get reports whether key held a cached value that decoded into out.
+func (c *FitbitStatsCache) get(ctx context.Context, key string, out any) bool {
+	data, err := c.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("warn: read %s from cache: %v", key, err)
+		}
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
+
+// set caches v under key. Failures only cost a later upstream call.
+func (c *FitbitStatsCache) set(ctx context.Context, key string, v any, ttl time.Duration) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	if err := c.rdb.Set(ctx, key, data, ttl).Err(); err != nil {
+		log.Printf("warn: write %s to cache: %v", key, err)
+	}
+}
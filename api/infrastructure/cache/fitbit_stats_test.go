@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"vitametron/api/domain/entity"
+	"vitametron/api/mocks"
+)
+
+func TestFitbitStatsCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	ctx := context.Background()
+
+	var statsCalls, badgeCalls int
+	provider := &mocks.MockFitbitStatsProvider{
+		FetchLifetimeStatsFunc: func(_ context.Context) (*entity.FitbitLifetimeStats, error) {
+			statsCalls++
+			return &entity.FitbitLifetimeStats{Steps: 1000000, Floors: 300}, nil
+		},
+		FetchBadgesFunc: func(_ context.Context) ([]entity.FitbitBadge, error) {
+			badgeCalls++
+			return []entity.FitbitBadge{{Name: "Trail Shoe", Date: "2025-03-02", Value: 10000}}, nil
+		},
+	}
+	c := NewFitbitStatsCache(provider, rdb)
+
+	for range 2 {
+		stats, err := c.FetchLifetimeStats(ctx)
+		if err != nil {
+			t.Fatalf("FetchLifetimeStats() error = %v", err)
+		}
+		if stats.Steps != 1000000 || stats.Floors != 300 {
+			t.Errorf("stats = %+v", stats)
+		}
+		badges, err := c.FetchBadges(ctx)
+		if err != nil {
+			t.Fatalf("FetchBadges() error = %v", err)
+		}
+		if len(badges) != 1 || badges[0].Name != "Trail Shoe" {
+			t.Errorf("badges = %+v", badges)
+		}
+	}
+	if statsCalls != 1 || badgeCalls != 1 {
+		t.Errorf("upstream calls = %d stats, %d badges, want 1 each", statsCalls, badgeCalls)
+	}
+	if ttl := mr.TTL(lifetimeStatsKey); ttl != lifetimeStatsTTL {
+		t.Errorf("lifetime TTL = %v, want %v", ttl, lifetimeStatsTTL)
+	}
+	if ttl := mr.TTL(badgesKey); ttl != badgesTTL {
+		t.Errorf("badges TTL = %v, want %v", ttl, badgesTTL)
+	}
+
+	mr.FastForward(lifetimeStatsTTL)
+	if _, err := c.FetchLifetimeStats(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if statsCalls != 2 {
+		t.Errorf("stats calls after expiry = %d, want 2", statsCalls)
+	}
+}
+
+func TestFitbitStatsCache_ErrorNotCached(t *testing.T) {
+	mr := miniredis.RunT(t)
+	provider := &mocks.MockFitbitStatsProvider{
+		FetchBadgesFunc: func(_ context.Context) ([]entity.FitbitBadge, error) {
+			return nil, errors.New("rate limited")
+		},
+	}
+	c := NewFitbitStatsCache(provider, redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	if _, err := c.FetchBadges(context.Background()); err == nil {
+		t.Fatal("FetchBadges() error = nil, want error")
+	}
+	if mr.Exists(badgesKey) {
+		t.Error("failed fetch was cached")
+	}
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fitbitSyncQueueKey is a Redis list of YYYY-MM-DD dates, oldest at the head.
+// fitbitSyncPendingKey is a set of the same dates, so a date already waiting
+// is not queued again.
+const (
+	fitbitSyncQueueKey   = "fitbit:sync_queue"
+	fitbitSyncPendingKey = "fitbit:sync_queue:pending"
+)
+
+// enqueueUnique pushes each date in ARGV that is not already pending.
+var enqueueUnique = redis.NewScript(`
+for _, d in ipairs(ARGV) do
+	if redis.call("SADD", KEYS[2], d) == 1 then
+		redis.call("RPUSH", KEYS[1], d)
+	end
+end
+return 0`)
+
+// popPending pops the oldest date and clears its pending mark.
+var popPending = redis.NewScript(`
+local d = redis.call("LPOP", KEYS[1])
+if d then
+	redis.call("SREM", KEYS[2], d)
+end
+return d`)
+
+var jst = time.FixedZone("JST", 9*60*60)
+
+// FitbitSyncQueue queues dates reported by Fitbit notifications for sync.
+type FitbitSyncQueue struct {
+	rdb *redis.Client
+}
+
+func NewFitbitSyncQueue(rdb *redis.Client) *FitbitSyncQueue {
+	return &FitbitSyncQueue{rdb: rdb}
+}
+
+// Enqueue skips dates already waiting in the queue; Fitbit sends one
+// notification per changed collection, often several for the same day.
+func (q *FitbitSyncQueue) Enqueue(ctx context.Context, dates []time.Time) error {
+	if len(dates) == 0 {
+		return nil
+	}
+	values := make([]any, len(dates))
+	for i, d := range dates {
+		values[i] = d.In(jst).Format("2006-01-02")
+	}
+	keys := []string{fitbitSyncQueueKey, fitbitSyncPendingKey}
+	if err := enqueueUnique.Run(ctx, q.rdb, keys, values...).Err(); err != nil {
+		return fmt.Errorf("enqueue fitbit sync: %w", err)
+	}
+	return nil
+}
+
+// Dequeue drops entries that are not valid dates instead of returning them.
+func (q *FitbitSyncQueue) Dequeue(ctx context.Context) (time.Time, bool, error) {
+	for {
+		v, err := popPending.Run(ctx, q.rdb, []string{fitbitSyncQueueKey, fitbitSyncPendingKey}).Text()
+		if err == redis.Nil {
+			return time.Time{}, false, nil
+		}
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("dequeue fitbit sync: %w", err)
+		}
+		if d, err := time.ParseInLocation("2006-01-02", v, jst); err == nil {
+			return d, true, nil
+		}
+	}
+}
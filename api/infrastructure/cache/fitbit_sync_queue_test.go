@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestFitbitSyncQueue_FIFO(t *testing.T) {
+	mr := miniredis.RunT(t)
+	q := NewFitbitSyncQueue(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	ctx := context.Background()
+
+	first := time.Date(2026, 4, 18, 0, 0, 0, 0, jst)
+	second := first.AddDate(0, 0, 1)
+	if err := q.Enqueue(ctx, []time.Time{first, second}); err != nil {
+		t.Fatal(err)
+	}
+	// A malformed entry is skipped rather than blocking the queue.
+	mr.Lpush(fitbitSyncQueueKey, "garbage")
+
+	for _, want := range []time.Time{first, second} {
+		got, ok, err := q.Dequeue(ctx)
+		if err != nil || !ok {
+			t.Fatalf("Dequeue() = %v, %v, %v", got, ok, err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("Dequeue() = %s, want %s", got, want)
+		}
+	}
+	if _, ok, err := q.Dequeue(ctx); ok || err != nil {
+		t.Errorf("empty queue: ok = %v, err = %v", ok, err)
+	}
+}
+
+func TestFitbitSyncQueue_SkipsPendingDates(t *testing.T) {
+	mr := miniredis.RunT(t)
+	q := NewFitbitSyncQueue(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	ctx := context.Background()
+
+	day := time.Date(2026, 4, 18, 0, 0, 0, 0, jst)
+	// One notification per changed collection, all for the same day.
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue(ctx, []time.Time{day, day}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, _ := mr.List(fitbitSyncQueueKey); len(got) != 1 {
+		t.Fatalf("queue = %v, want one entry", got)
+	}
+
+	if _, ok, err := q.Dequeue(ctx); !ok || err != nil {
+		t.Fatalf("Dequeue() ok = %v, err = %v", ok, err)
+	}
+	// Once taken off the queue, a new notification queues the date again.
+	if err := q.Enqueue(ctx, []time.Time{day}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := mr.List(fitbitSyncQueueKey); len(got) != 1 {
+		t.Errorf("queue after re-enqueue = %v, want one entry", got)
+	}
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const schedulerIntervalKey = "scheduler:config:interval_min"
+
+// SchedulerConfigStore keeps the runtime scheduler configuration in Redis.
+type SchedulerConfigStore struct {
+	rdb *redis.Client
+}
+
+func NewSchedulerConfigStore(rdb *redis.Client) *SchedulerConfigStore {
+	return &SchedulerConfigStore{rdb: rdb}
+}
+
+func (s *SchedulerConfigStore) IntervalMin(ctx context.Context) (int, bool, error) {
+	v, err := s.rdb.Get(ctx, schedulerIntervalKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false, fmt.Errorf("parse %s: %w", schedulerIntervalKey, err)
+	}
+	return n, true, nil
+}
+
+func (s *SchedulerConfigStore) SetIntervalMin(ctx context.Context, minutes int) error {
+	return s.rdb.Set(ctx, schedulerIntervalKey, minutes, 0).Err()
+}
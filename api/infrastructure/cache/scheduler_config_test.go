@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestSchedulerConfigStore_IntervalMin(t *testing.T) {
+	mr := miniredis.RunT(t)
+	store := NewSchedulerConfigStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	ctx := context.Background()
+
+	if _, ok, err := store.IntervalMin(ctx); err != nil || ok {
+		t.Fatalf("IntervalMin() before set = ok %v, err %v; want not found", ok, err)
+	}
+
+	if err := store.SetIntervalMin(ctx, 30); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := store.IntervalMin(ctx)
+	if err != nil || !ok || got != 30 {
+		t.Errorf("IntervalMin() = %d, %v, %v; want 30, true, nil", got, ok, err)
+	}
+	if v, _ := mr.Get("scheduler:config:interval_min"); v != "30" {
+		t.Errorf("stored value = %q, want 30", v)
+	}
+
+	mr.Set("scheduler:config:interval_min", "soon")
+	if _, _, err := store.IntervalMin(ctx); err == nil {
+		t.Error("IntervalMin() with a malformed value: want error")
+	}
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"vitametron/api/domain/entity"
+)
+
+// syncStatusKeyPrefix is followed by the provider name; each key is a hash
+// with "last_sync_at" (RFC 3339) and "last_error" fields.
+const syncStatusKeyPrefix = "scheduler:last_sync:"
+
+// SyncStatusStore keeps per-provider sync outcomes in Redis.
+type SyncStatusStore struct {
+	rdb *redis.Client
+}
+
+func NewSyncStatusStore(rdb *redis.Client) *SyncStatusStore {
+	return &SyncStatusStore{rdb: rdb}
+}
+
+func (s *SyncStatusStore) RecordSync(ctx context.Context, provider string, at time.Time, syncErr error) error {
+	key := syncStatusKeyPrefix + provider
+	if syncErr != nil {
+		return s.rdb.HSet(ctx, key, "last_error", syncErr.Error()).Err()
+	}
+	return s.rdb.HSet(ctx, key, "last_sync_at", at.UTC().Format(time.RFC3339), "last_error", "").Err()
+}
+
+// List scans for every provider key, so new providers appear once the
+// scheduler records their first sync.
+func (s *SyncStatusStore) List(ctx context.Context) ([]entity.ProviderSyncStatus, error) {
+	var keys []string
+	iter := s.rdb.Scan(ctx, 0, syncStatusKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("scan sync status: %w", err)
+	}
+	sort.Strings(keys)
+
+	statuses := make([]entity.ProviderSyncStatus, 0, len(keys))
+	for _, key := range keys {
+		fields, err := s.rdb.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, fmt.Errorf("read sync status %s: %w", key, err)
+		}
+		status := entity.ProviderSyncStatus{
+			Provider:  strings.TrimPrefix(key, syncStatusKeyPrefix),
+			LastError: fields["last_error"],
+		}
+		if v := fields["last_sync_at"]; v != "" {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				status.LastSyncAt = &t
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
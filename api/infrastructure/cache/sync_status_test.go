@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestSyncStatusStore_RecordAndList(t *testing.T) {
+	mr := miniredis.RunT(t)
+	store := NewSyncStatusStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	ctx := context.Background()
+	at := time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC)
+
+	if err := store.RecordSync(ctx, "fitbit", at, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordSync(ctx, "fitbit", at.Add(10*time.Minute), errors.New("rate limited")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordSync(ctx, "garmin", at, errors.New("unauthorized")); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("len = %d, want 2", len(statuses))
+	}
+
+	fitbit := statuses[0]
+	if fitbit.Provider != "fitbit" {
+		t.Errorf("statuses[0].Provider = %q, want fitbit", fitbit.Provider)
+	}
+	if fitbit.LastSyncAt == nil || !fitbit.LastSyncAt.Equal(at) {
+		t.Errorf("fitbit LastSyncAt = %v, want %v (failures keep the last success)", fitbit.LastSyncAt, at)
+	}
+	if fitbit.LastError != "rate limited" {
+		t.Errorf("fitbit LastError = %q, want %q", fitbit.LastError, "rate limited")
+	}
+
+	garmin := statuses[1]
+	if garmin.LastSyncAt != nil {
+		t.Errorf("garmin LastSyncAt = %v, want nil", garmin.LastSyncAt)
+	}
+
+	// A later success clears the error.
+	if err := store.RecordSync(ctx, "fitbit", at.Add(20*time.Minute), nil); err != nil {
+		t.Fatal(err)
+	}
+	statuses, _ = store.List(ctx)
+	if statuses[0].LastError != "" {
+		t.Errorf("LastError = %q after success, want empty", statuses[0].LastError)
+	}
+}
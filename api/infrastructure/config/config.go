@@ -14,6 +14,12 @@ type Config struct {
 	ML           MLConfig
 	Sync         SyncConfig
 	Preprocessor PreprocessorConfig
+	Profile      ProfileConfig
+	Admin        AdminConfig
+	Health       HealthConfig
+	Import       ImportConfig
+	Webhook      WebhookConfig
+	VRI          VRIConfig
 }
 
 type DBConfig struct {
@@ -23,6 +29,8 @@ type DBConfig struct {
 	User     string
 	Password string
 	SSLMode  string
+	// QueryTimeoutSec bounds each repository operation.
+	QueryTimeoutSec int
 }
 
 // DSN returns a PostgreSQL connection string.
@@ -46,18 +54,36 @@ type FitbitConfig struct {
 	ClientSecret  string
 	RedirectURI   string
 	EncryptionKey string
+	// SubscriberVerifyCode answers Fitbit's subscriber verification requests;
+	// empty disables the verification endpoint.
+	SubscriberVerifyCode string
 }
 
 type ServerConfig struct {
 	Port int
+	// VRIRequestTimeoutSec, AnomalyRequestTimeoutSec and DivergenceRequestTimeoutSec
+	// bound on-demand ML computes; a request exceeding them gets 503.
+	VRIRequestTimeoutSec        int
+	AnomalyRequestTimeoutSec    int
+	DivergenceRequestTimeoutSec int
 }
 
 type MLConfig struct {
 	URL string
+	// AnomalyModelVersion pins anomaly detection to a specific model version (optional).
+	AnomalyModelVersion string
+	// MaxDailyAdviceRegenerations caps advice regenerations per date; 0 disables the limit.
+	MaxDailyAdviceRegenerations int
 }
 
 type SyncConfig struct {
 	IntervalMin int
+	// RetryCount is how many times a failed daily summary fetch is retried on network errors.
+	RetryCount      int
+	RetryBackoffSec int
+	// EnableFillForward copies missing HRV, SpO2, breathing rate, skin
+	// temperature and VO2 Max readings from the previous day on sync.
+	EnableFillForward bool
 }
 
 type PreprocessorConfig struct {
@@ -65,16 +91,63 @@ type PreprocessorConfig struct {
 	UploadDir string
 }
 
+// ProfileConfig holds static user attributes used by physiological formulas.
+// Zero values mean "unknown".
+type ProfileConfig struct {
+	WeightKG float64
+	Age      int
+}
+
+// AdminConfig guards maintenance endpoints under /api/admin.
+// An empty APIKey disables them.
+type AdminConfig struct {
+	APIKey string
+}
+
+type HealthConfig struct {
+	// MaxLatencyMs is the slowest acceptable dependency ping before /api/health returns 503.
+	MaxLatencyMs int
+	// DataFreshnessHours is how old a day's last sync may be before
+	// /api/biometrics responses are flagged as stale.
+	DataFreshnessHours int
+}
+
+type ImportConfig struct {
+	// HealthConnectSkipIfFitbit keeps Fitbit daily summaries when a Health Connect import covers the same date.
+	HealthConnectSkipIfFitbit bool
+	// HealthConnectQueryTimeoutSec bounds each query against an uploaded
+	// Health Connect export; large exports may need more than the default.
+	HealthConnectQueryTimeoutSec int
+}
+
+// WebhookConfig configures the digest and alert webhooks. An empty DigestURL,
+// VRIAlertURL, HRAlertURL, SleepAlertURL or FeverAlertURL disables that
+// delivery; all are signed with Secret.
+type WebhookConfig struct {
+	DigestURL     string
+	VRIAlertURL   string
+	HRAlertURL    string
+	SleepAlertURL string
+	FeverAlertURL string
+	Secret        string
+}
+
+type VRIConfig struct {
+	// AlertThreshold is the VRI score below which a day is reported in the weekly VRI alert.
+	AlertThreshold float64
+}
+
 // Load reads configuration from environment variables and secrets.
 func Load() *Config {
 	return &Config{
 		DB: DBConfig{
-			Host:     envOrDefault("DB_HOST", "postgres"),
-			Port:     envIntOrDefault("DB_PORT", 5432),
-			Name:     envOrDefault("DB_NAME", "vitametron"),
-			User:     envOrDefault("DB_USER", "vitametron"),
-			Password: ReadSecret("db_password"),
-			SSLMode:  envOrDefault("DB_SSLMODE", "disable"),
+			Host:            envOrDefault("DB_HOST", "postgres"),
+			Port:            envIntOrDefault("DB_PORT", 5432),
+			Name:            envOrDefault("DB_NAME", "vitametron"),
+			User:            envOrDefault("DB_USER", "vitametron"),
+			Password:        ReadSecret("db_password"),
+			SSLMode:         envOrDefault("DB_SSLMODE", "disable"),
+			QueryTimeoutSec: envIntOrDefault("DB_QUERY_TIMEOUT_SEC", 30),
 		},
 		Redis: RedisConfig{
 			Host:     envOrDefault("REDIS_HOST", "redis"),
@@ -82,24 +155,59 @@ func Load() *Config {
 			Password: ReadSecret("redis_password"),
 		},
 		Fitbit: FitbitConfig{
-			ClientID:      ReadSecret("fitbit_client_id"),
-			ClientSecret:  ReadSecret("fitbit_client_secret"),
-			RedirectURI:   ReadSecret("fitbit_redirect_url"),
-			EncryptionKey: ReadSecret("encryption_key"),
+			ClientID:             ReadSecret("fitbit_client_id"),
+			ClientSecret:         ReadSecret("fitbit_client_secret"),
+			RedirectURI:          ReadSecret("fitbit_redirect_url"),
+			EncryptionKey:        ReadSecret("encryption_key"),
+			SubscriberVerifyCode: os.Getenv("FITBIT_SUBSCRIBER_VERIFY_CODE"),
 		},
 		Server: ServerConfig{
-			Port: envIntOrDefault("SERVER_PORT", 8080),
+			Port:                        envIntOrDefault("SERVER_PORT", 8080),
+			VRIRequestTimeoutSec:        envIntOrDefault("SERVER_VRI_REQUEST_TIMEOUT_SEC", 25),
+			AnomalyRequestTimeoutSec:    envIntOrDefault("SERVER_ANOMALY_REQUEST_TIMEOUT_SEC", 25),
+			DivergenceRequestTimeoutSec: envIntOrDefault("SERVER_DIVERGENCE_REQUEST_TIMEOUT_SEC", 25),
 		},
 		ML: MLConfig{
-			URL: envOrDefault("ML_SERVICE_URL", "http://ml:8000"),
+			URL:                         envOrDefault("ML_SERVICE_URL", "http://ml:8000"),
+			AnomalyModelVersion:         os.Getenv("ML_ANOMALY_MODEL_VERSION"),
+			MaxDailyAdviceRegenerations: envIntOrDefault("ML_MAX_DAILY_ADVICE_REGENERATIONS", 3),
 		},
 		Sync: SyncConfig{
-			IntervalMin: envIntOrDefault("SYNC_INTERVAL_MIN", 10),
+			IntervalMin:       envIntOrDefault("SYNC_INTERVAL_MIN", 10),
+			RetryCount:        envIntOrDefault("SYNC_RETRY_COUNT", 3),
+			RetryBackoffSec:   envIntOrDefault("SYNC_RETRY_BACKOFF_SEC", 5),
+			EnableFillForward: envBoolOrDefault("SYNC_FILL_FORWARD", false),
 		},
 		Preprocessor: PreprocessorConfig{
 			URL:       envOrDefault("PREPROCESSOR_URL", "http://preprocessor:8100"),
 			UploadDir: envOrDefault("UPLOAD_DIR", "/data/uploads"),
 		},
+		Profile: ProfileConfig{
+			WeightKG: envFloatOrDefault("USER_WEIGHT_KG", 0),
+			Age:      envIntOrDefault("USER_AGE", 0),
+		},
+		Admin: AdminConfig{
+			APIKey: ReadSecret("admin_api_key"),
+		},
+		Health: HealthConfig{
+			MaxLatencyMs:       envIntOrDefault("HEALTH_MAX_LATENCY_MS", 1000),
+			DataFreshnessHours: envIntOrDefault("HEALTH_DATA_FRESHNESS_HOURS", 26),
+		},
+		Import: ImportConfig{
+			HealthConnectSkipIfFitbit:    envBoolOrDefault("IMPORT_HC_SKIP_IF_FITBIT", false),
+			HealthConnectQueryTimeoutSec: envIntOrDefault("IMPORT_HC_QUERY_TIMEOUT_SEC", 30),
+		},
+		Webhook: WebhookConfig{
+			DigestURL:     os.Getenv("WEBHOOK_DIGEST_URL"),
+			VRIAlertURL:   os.Getenv("WEBHOOK_VRI_ALERT_URL"),
+			HRAlertURL:    os.Getenv("WEBHOOK_HR_ALERT_URL"),
+			SleepAlertURL: os.Getenv("WEBHOOK_SLEEP_ALERT_URL"),
+			FeverAlertURL: os.Getenv("WEBHOOK_FEVER_ALERT_URL"),
+			Secret:        ReadSecret("webhook_secret"),
+		},
+		VRI: VRIConfig{
+			AlertThreshold: envFloatOrDefault("VRI_ALERT_THRESHOLD", 40),
+		},
 	}
 }
 
@@ -118,3 +226,21 @@ func envIntOrDefault(key string, fallback int) int {
 	}
 	return fallback
 }
+
+func envFloatOrDefault(key string, fallback float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
+
+func envBoolOrDefault(key string, fallback bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
@@ -27,6 +27,9 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.DB.SSLMode != "disable" {
 		t.Errorf("DB.SSLMode = %q, want %q", cfg.DB.SSLMode, "disable")
 	}
+	if cfg.DB.QueryTimeoutSec != 30 {
+		t.Errorf("DB.QueryTimeoutSec = %d, want %d", cfg.DB.QueryTimeoutSec, 30)
+	}
 	if cfg.Redis.Host != "redis" {
 		t.Errorf("Redis.Host = %q, want %q", cfg.Redis.Host, "redis")
 	}
@@ -36,9 +39,30 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.Server.Port != 8080 {
 		t.Errorf("Server.Port = %d, want %d", cfg.Server.Port, 8080)
 	}
+	if cfg.Server.VRIRequestTimeoutSec != 25 {
+		t.Errorf("Server.VRIRequestTimeoutSec = %d, want %d", cfg.Server.VRIRequestTimeoutSec, 25)
+	}
 	if cfg.ML.URL != "http://ml:8000" {
 		t.Errorf("ML.URL = %q, want %q", cfg.ML.URL, "http://ml:8000")
 	}
+	if cfg.Health.MaxLatencyMs != 1000 {
+		t.Errorf("Health.MaxLatencyMs = %d, want %d", cfg.Health.MaxLatencyMs, 1000)
+	}
+	if cfg.Health.DataFreshnessHours != 26 {
+		t.Errorf("Health.DataFreshnessHours = %d, want %d", cfg.Health.DataFreshnessHours, 26)
+	}
+	if cfg.Sync.RetryCount != 3 {
+		t.Errorf("Sync.RetryCount = %d, want %d", cfg.Sync.RetryCount, 3)
+	}
+	if cfg.Sync.RetryBackoffSec != 5 {
+		t.Errorf("Sync.RetryBackoffSec = %d, want %d", cfg.Sync.RetryBackoffSec, 5)
+	}
+	if cfg.Import.HealthConnectSkipIfFitbit {
+		t.Error("Import.HealthConnectSkipIfFitbit = true, want false")
+	}
+	if cfg.Import.HealthConnectQueryTimeoutSec != 30 {
+		t.Errorf("Import.HealthConnectQueryTimeoutSec = %d, want %d", cfg.Import.HealthConnectQueryTimeoutSec, 30)
+	}
 }
 
 func TestLoad_EnvOverrides(t *testing.T) {
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ValidationError describes one invalid configuration field.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// ValidationErrors collects every invalid field so startup reports them all at once.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, v := range e {
+		msgs[i] = v.Error()
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks the fields the server cannot start without. It returns
+// ValidationErrors listing every failure, or nil.
+func Validate(cfg *Config) error {
+	var errs ValidationErrors
+	add := func(field, reason string) {
+		errs = append(errs, ValidationError{Field: field, Reason: reason})
+	}
+
+	if cfg.DB.Host == "" {
+		add("DB.Host", "must not be empty")
+	}
+	if cfg.DB.Name == "" {
+		add("DB.Name", "must not be empty")
+	}
+	if !validPort(cfg.DB.Port) {
+		add("DB.Port", fmt.Sprintf("must be in 1-65535, got %d", cfg.DB.Port))
+	}
+
+	if cfg.Redis.Host == "" {
+		add("Redis.Host", "must not be empty")
+	}
+	if !validPort(cfg.Redis.Port) {
+		add("Redis.Port", fmt.Sprintf("must be in 1-65535, got %d", cfg.Redis.Port))
+	}
+
+	if key, err := base64.StdEncoding.DecodeString(cfg.Fitbit.EncryptionKey); err != nil {
+		add("Fitbit.EncryptionKey", "must be base64")
+	} else if len(key) != 32 {
+		add("Fitbit.EncryptionKey", fmt.Sprintf("must decode to 32 bytes, got %d", len(key)))
+	}
+
+	if u, err := url.Parse(cfg.ML.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		add("ML.URL", fmt.Sprintf("must be an absolute URL, got %q", cfg.ML.URL))
+	}
+
+	if !validPort(cfg.Server.Port) {
+		add("Server.Port", fmt.Sprintf("must be in 1-65535, got %d", cfg.Server.Port))
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validPort(p int) bool {
+	return p >= 1 && p <= 65535
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func validConfig() *Config {
+	return &Config{
+		DB:     DBConfig{Host: "postgres", Port: 5432, Name: "vitametron"},
+		Redis:  RedisConfig{Host: "redis", Port: 6379},
+		Fitbit: FitbitConfig{EncryptionKey: base64.StdEncoding.EncodeToString(make([]byte, 32))},
+		Server: ServerConfig{Port: 8080},
+		ML:     MLConfig{URL: "http://ml:8000"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(*Config)
+		wantFields []string
+	}{
+		{"valid", func(*Config) {}, nil},
+		{"empty db host", func(c *Config) { c.DB.Host = "" }, []string{"DB.Host"}},
+		{"empty db name", func(c *Config) { c.DB.Name = "" }, []string{"DB.Name"}},
+		{"empty redis host", func(c *Config) { c.Redis.Host = "" }, []string{"Redis.Host"}},
+		{"redis port out of range", func(c *Config) { c.Redis.Port = 70000 }, []string{"Redis.Port"}},
+		{"encryption key not base64", func(c *Config) { c.Fitbit.EncryptionKey = "not base64!" }, []string{"Fitbit.EncryptionKey"}},
+		{"encryption key too short", func(c *Config) {
+			c.Fitbit.EncryptionKey = base64.StdEncoding.EncodeToString(make([]byte, 16))
+		}, []string{"Fitbit.EncryptionKey"}},
+		{"empty encryption key", func(c *Config) { c.Fitbit.EncryptionKey = "" }, []string{"Fitbit.EncryptionKey"}},
+		{"ml url without scheme", func(c *Config) { c.ML.URL = "ml:8000/path" }, []string{"ML.URL"}},
+		{"ml url unparsable", func(c *Config) { c.ML.URL = "http://%zz" }, []string{"ML.URL"}},
+		{"server port zero", func(c *Config) { c.Server.Port = 0 }, []string{"Server.Port"}},
+		{"multiple failures", func(c *Config) {
+			c.DB.Host = ""
+			c.Server.Port = 65536
+			c.ML.URL = ""
+		}, []string{"DB.Host", "ML.URL", "Server.Port"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.mutate(cfg)
+
+			err := Validate(cfg)
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Validate() = %v, want ValidationErrors", err)
+			}
+			got := make([]string, len(verrs))
+			for i, v := range verrs {
+				got[i] = v.Field
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
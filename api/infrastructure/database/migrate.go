@@ -4,6 +4,9 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
+	"io/fs"
+	"path"
+	"sort"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
@@ -12,6 +15,9 @@ import (
 //go:embed migrations/*.sql
 var migrations embed.FS
 
+// versionTable is where goose records applied migrations.
+const versionTable = "goose_db_version"
+
 func RunMigrations(dsn string) error {
 	db, err := sql.Open("pgx", dsn)
 	if err != nil {
@@ -25,3 +31,79 @@ func RunMigrations(dsn string) error {
 	}
 	return goose.Up(db, "migrations")
 }
+
+// PreviewMigrations returns the migration files RunMigrations would apply,
+// in order, without executing them or creating the version table.
+func PreviewMigrations(dsn string) ([]string, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("migration: open db: %w", err)
+	}
+	defer db.Close()
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("migration: list files: %w", err)
+	}
+	return pendingMigrations(files, applied)
+}
+
+// appliedVersions reads the versions currently applied. goose appends a row
+// per up/down, so the latest row for each version decides its state. A
+// missing table means a fresh database with nothing applied.
+func appliedVersions(db *sql.DB) (map[int64]bool, error) {
+	var exists bool
+	if err := db.QueryRow(`SELECT to_regclass($1) IS NOT NULL`, versionTable).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("migration: check version table: %w", err)
+	}
+	applied := map[int64]bool{}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := db.Query(`SELECT version_id, is_applied FROM ` + versionTable + ` ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("migration: read versions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		if err := rows.Scan(&version, &isApplied); err != nil {
+			return nil, fmt.Errorf("migration: read versions: %w", err)
+		}
+		applied[version] = isApplied
+	}
+	return applied, rows.Err()
+}
+
+// pendingMigrations returns the base names of files whose version is not
+// applied, sorted by version.
+func pendingMigrations(files []string, applied map[int64]bool) ([]string, error) {
+	type migration struct {
+		version int64
+		name    string
+	}
+	var pending []migration
+	for _, f := range files {
+		name := path.Base(f)
+		version, err := goose.NumericComponent(name)
+		if err != nil {
+			return nil, fmt.Errorf("migration: %s: %w", name, err)
+		}
+		if !applied[version] {
+			pending = append(pending, migration{version, name})
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].version < pending[j].version })
+
+	names := make([]string, len(pending))
+	for i, m := range pending {
+		names[i] = m.name
+	}
+	return names, nil
+}
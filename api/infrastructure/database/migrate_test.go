@@ -0,0 +1,48 @@
+package database
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestPendingMigrations(t *testing.T) {
+	files := []string{
+		"migrations/20260102000000_second.sql",
+		"migrations/20260101000000_first.sql",
+		"migrations/20260103000000_third.sql",
+	}
+	applied := map[int64]bool{
+		20260101000000: true,
+		20260102000000: false, // rolled back
+	}
+
+	got, err := pendingMigrations(files, applied)
+	if err != nil {
+		t.Fatalf("pendingMigrations() error = %v", err)
+	}
+	want := []string{"20260102000000_second.sql", "20260103000000_third.sql"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("pendingMigrations() = %v, want %v", got, want)
+	}
+}
+
+func TestPendingMigrations_InvalidName(t *testing.T) {
+	if _, err := pendingMigrations([]string{"migrations/init.sql"}, nil); err == nil {
+		t.Error("pendingMigrations() error = nil, want error for unversioned file")
+	}
+}
+
+func TestPendingMigrations_EmbeddedFilesParse(t *testing.T) {
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := pendingMigrations(files, nil)
+	if err != nil {
+		t.Fatalf("pendingMigrations() error = %v", err)
+	}
+	if len(got) != len(files) {
+		t.Errorf("len = %d, want %d on a fresh database", len(got), len(files))
+	}
+}
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE exercise_logs ADD COLUMN IF NOT EXISTS met REAL;
+ALTER TABLE exercise_logs ADD COLUMN IF NOT EXISTS calories_per_minute REAL;
+
+-- +goose Down
+ALTER TABLE exercise_logs DROP COLUMN IF EXISTS calories_per_minute;
+ALTER TABLE exercise_logs DROP COLUMN IF EXISTS met;
@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- WHO-5 depression screening (normalized score < 52) and tertile interpretation
+ALTER TABLE who5_assessments ADD COLUMN depression_screening_flag BOOLEAN
+    GENERATED ALWAYS AS (((item1 + item2 + item3 + item4 + item5) * 4) < 52) STORED;
+ALTER TABLE who5_assessments ADD COLUMN score_interpretation TEXT
+    GENERATED ALWAYS AS (
+        CASE
+            WHEN (item1 + item2 + item3 + item4 + item5) * 4 < 34 THEN 'poor'
+            WHEN (item1 + item2 + item3 + item4 + item5) * 4 < 67 THEN 'fair'
+            ELSE 'good'
+        END
+    ) STORED;
+
+-- +goose Down
+ALTER TABLE who5_assessments DROP COLUMN IF EXISTS score_interpretation;
+ALTER TABLE who5_assessments DROP COLUMN IF EXISTS depression_screening_flag;
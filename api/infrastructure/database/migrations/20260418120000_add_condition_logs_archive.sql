@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- Archive for old condition logs; same columns as condition_logs but ids are
+-- copied over rather than generated.
+CREATE TABLE IF NOT EXISTS condition_logs_archive (
+    LIKE condition_logs INCLUDING DEFAULTS INCLUDING CONSTRAINTS,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_condition_archive_logged_at ON condition_logs_archive (logged_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS condition_logs_archive;
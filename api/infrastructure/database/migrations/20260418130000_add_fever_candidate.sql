@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- Skin temperature above the personal 30-day mean + 2 SD
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS fever_candidate BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE daily_summaries DROP COLUMN IF EXISTS fever_candidate;
@@ -0,0 +1,28 @@
+-- +goose Up
+
+-- condition_logs.created_at has always been declared TIMESTAMPTZ, but a
+-- deployment whose column was altered to DATE (e.g. by a manual restore)
+-- returns every created_at as midnight. Convert it back in place; the time
+-- component of rows written while the column was DATE is lost and stays at
+-- 00:00. No-op on deployments that already use TIMESTAMPTZ.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['condition_logs', 'condition_logs_archive'] LOOP
+        IF EXISTS (
+            SELECT 1 FROM information_schema.columns
+            WHERE table_name = t AND column_name = 'created_at' AND data_type = 'date'
+        ) THEN
+            EXECUTE format(
+                'ALTER TABLE %I ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at::timestamptz, ALTER COLUMN created_at SET DEFAULT NOW()',
+                t);
+        END IF;
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Nothing to undo: TIMESTAMPTZ is the declared type.
+SELECT 1;
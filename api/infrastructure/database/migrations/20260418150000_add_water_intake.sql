@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- Total daily water intake from the Fitbit water log (mL)
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS water_intake_ml INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE daily_summaries DROP COLUMN IF EXISTS water_intake_ml;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Every source that contributed to a day. The legacy provider column is kept
+-- for writers that only know a single source (e.g. the HealthKit preprocessor).
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS providers TEXT[] NOT NULL DEFAULT '{}';
+UPDATE daily_summaries SET providers = ARRAY[provider] WHERE providers = '{}';
+
+-- +goose Down
+ALTER TABLE daily_summaries DROP COLUMN IF EXISTS providers;
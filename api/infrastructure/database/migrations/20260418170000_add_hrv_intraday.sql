@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- 5-minute HRV segments recorded during sleep
+CREATE TABLE IF NOT EXISTS hrv_intraday (
+    time  TIMESTAMPTZ NOT NULL,
+    rmssd REAL NOT NULL,
+    PRIMARY KEY (time)
+);
+SELECT create_hypertable('hrv_intraday', by_range('time'), if_not_exists => TRUE);
+SELECT add_retention_policy('hrv_intraday', INTERVAL '90 days', if_not_exists => TRUE);
+
+-- +goose Down
+DROP TABLE IF EXISTS hrv_intraday;
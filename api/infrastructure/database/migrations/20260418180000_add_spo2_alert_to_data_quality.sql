@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Lowest overnight SpO2 (0 = no reading) and whether it fell below 88%
+ALTER TABLE daily_data_quality ADD COLUMN IF NOT EXISTS lowest_spo2 REAL NOT NULL DEFAULT 0;
+ALTER TABLE daily_data_quality ADD COLUMN IF NOT EXISTS spo2_min_alert BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE daily_data_quality DROP COLUMN IF EXISTS spo2_min_alert;
+ALTER TABLE daily_data_quality DROP COLUMN IF EXISTS lowest_spo2;
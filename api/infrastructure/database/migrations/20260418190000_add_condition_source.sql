@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Why a condition log was recorded: spontaneous, reminder or scheduled
+ALTER TABLE condition_logs ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'spontaneous';
+ALTER TABLE condition_logs_archive ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'spontaneous';
+
+-- +goose Down
+ALTER TABLE condition_logs_archive DROP COLUMN IF EXISTS source;
+ALTER TABLE condition_logs DROP COLUMN IF EXISTS source;
@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- Trust in the night's sleep staging (1.0 stages, 0.5 short stages, 0.2 classic, 0 none)
+ALTER TABLE daily_data_quality ADD COLUMN IF NOT EXISTS sleep_stage_confidence REAL NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE daily_data_quality DROP COLUMN IF EXISTS sleep_stage_confidence;
@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- One row per async Health Connect import job, kept for debugging
+CREATE TABLE IF NOT EXISTS import_history (
+    job_id       TEXT PRIMARY KEY,
+    source       TEXT NOT NULL,
+    devices_json JSONB NOT NULL DEFAULT '[]',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS import_history;
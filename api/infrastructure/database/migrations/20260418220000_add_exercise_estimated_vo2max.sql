@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- Uth-Sørensen VO2max estimate (ml/kg/min) computed on demand per exercise
+ALTER TABLE exercise_logs ADD COLUMN IF NOT EXISTS estimated_vo2max REAL;
+
+-- +goose Down
+ALTER TABLE exercise_logs DROP COLUMN IF EXISTS estimated_vo2max;
@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- Before/after snapshots of every condition log edit. No foreign key, so
+-- history survives archiving of the log it belongs to.
+CREATE TABLE IF NOT EXISTS condition_log_history (
+    id               BIGSERIAL PRIMARY KEY,
+    condition_log_id BIGINT NOT NULL,
+    changed_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    old_values       JSONB NOT NULL,
+    new_values       JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_condition_log_history_log
+    ON condition_log_history (condition_log_id, changed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS condition_log_history;
@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- Pace in seconds per km, derived from duration and distance
+ALTER TABLE exercise_logs ADD COLUMN IF NOT EXISTS pace REAL;
+
+UPDATE exercise_logs SET pace = duration_ms / 1000.0 / distance_km
+WHERE distance_km > 0 AND duration_ms > 0;
+
+-- +goose Down
+ALTER TABLE exercise_logs DROP COLUMN IF EXISTS pace;
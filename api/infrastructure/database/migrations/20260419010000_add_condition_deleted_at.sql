@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- Soft delete: a set deleted_at hides the log until it is restored
+ALTER TABLE condition_logs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE condition_logs_archive ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- +goose Down
+DELETE FROM condition_logs_archive WHERE deleted_at IS NOT NULL;
+DELETE FROM condition_logs WHERE deleted_at IS NOT NULL;
+ALTER TABLE condition_logs_archive DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE condition_logs DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- Free-form labels and notes, editable after import
+ALTER TABLE exercise_logs ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE exercise_logs ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_exercise_logs_tags ON exercise_logs USING GIN (tags);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_exercise_logs_tags;
+ALTER TABLE exercise_logs DROP COLUMN IF EXISTS notes;
+ALTER TABLE exercise_logs DROP COLUMN IF EXISTS tags;
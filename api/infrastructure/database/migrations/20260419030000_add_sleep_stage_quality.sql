@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- Fraction of deep/REM episodes with a plausible length (deep 20-120 min, REM 10-50 min)
+ALTER TABLE daily_data_quality ADD COLUMN IF NOT EXISTS sleep_stage_quality_pct REAL NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE daily_data_quality DROP COLUMN IF EXISTS sleep_stage_quality_pct;
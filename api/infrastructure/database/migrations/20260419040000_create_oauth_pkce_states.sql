@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- Fallback copy of in-flight OAuth PKCE states, so a Redis restart mid-flow
+-- does not invalidate the callback
+CREATE TABLE IF NOT EXISTS oauth_pkce_states (
+    state       TEXT PRIMARY KEY,
+    verifier    TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_pkce_states_expires_at ON oauth_pkce_states (expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS oauth_pkce_states;
@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- Metrics copied from the previous day because the day's own reading was missing
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS filled_forward_fields TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE daily_summaries DROP COLUMN IF EXISTS filled_forward_fields;
@@ -0,0 +1,21 @@
+-- +goose Up
+
+-- One row per model training run started through the API. Unlike
+-- anomaly_model_metadata, which the ML service keys by model version, this
+-- keeps every run so the history can be listed.
+CREATE TABLE IF NOT EXISTS ml_model_metadata (
+    id                 BIGSERIAL PRIMARY KEY,
+    model              TEXT NOT NULL,
+    model_version      TEXT NOT NULL,
+    training_days_used INTEGER NOT NULL DEFAULT 0,
+    contamination      DOUBLE PRECISION NOT NULL,
+    pot_threshold      DOUBLE PRECISION NOT NULL DEFAULT 0,
+    feature_names      TEXT[] NOT NULL DEFAULT '{}',
+    message            TEXT NOT NULL DEFAULT '',
+    trained_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ml_model_metadata_model_trained_at ON ml_model_metadata (model, trained_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS ml_model_metadata;
@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- Data source of each sleep stage. Health Connect imports carry log_id = 0.
+ALTER TABLE sleep_stages ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'fitbit';
+UPDATE sleep_stages SET source = 'health_connect' WHERE log_id = 0;
+
+-- +goose Down
+ALTER TABLE sleep_stages DROP COLUMN IF EXISTS source;
@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- Study labels on condition logs. No foreign key, so annotations survive
+-- archiving of the log they belong to, like condition_log_history.
+CREATE TABLE IF NOT EXISTS condition_annotations (
+    id               BIGSERIAL PRIMARY KEY,
+    condition_log_id BIGINT NOT NULL,
+    label            TEXT NOT NULL,
+    note             TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_condition_annotations_log
+    ON condition_annotations (condition_log_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS condition_annotations;
@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- Intensity-weighted share of tracked activity minutes (0-300)
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS activity_intensity_score REAL NOT NULL DEFAULT 0;
+
+UPDATE daily_summaries
+SET activity_intensity_score =
+    (COALESCE(minutes_lightly, 0) + COALESCE(minutes_fairly, 0) * 2 + COALESCE(minutes_very, 0) * 3)::real
+    / (COALESCE(minutes_sedentary, 0) + COALESCE(minutes_lightly, 0) + COALESCE(minutes_fairly, 0) + COALESCE(minutes_very, 0)) * 100
+WHERE COALESCE(minutes_sedentary, 0) + COALESCE(minutes_lightly, 0) + COALESCE(minutes_fairly, 0) + COALESCE(minutes_very, 0) > 0;
+
+-- +goose Down
+ALTER TABLE daily_summaries DROP COLUMN IF EXISTS activity_intensity_score;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Set when overall_vas was over 3 SD from the mean of the previous 30 logs
+ALTER TABLE condition_logs ADD COLUMN IF NOT EXISTS is_outlier BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE condition_logs_archive ADD COLUMN IF NOT EXISTS is_outlier BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE condition_logs_archive DROP COLUMN IF EXISTS is_outlier;
+ALTER TABLE condition_logs DROP COLUMN IF EXISTS is_outlier;
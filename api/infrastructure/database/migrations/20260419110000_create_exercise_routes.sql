@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- GPS tracks of exercise logs, stored as a JSON array of
+-- {lat, lng, elevation, time} points in recording order.
+CREATE TABLE IF NOT EXISTS exercise_routes (
+    exercise_external_id TEXT PRIMARY KEY REFERENCES exercise_logs (external_id) ON DELETE CASCADE,
+    points               JSONB NOT NULL,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS exercise_routes;
@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- 1-minute active zone minutes; each column is 1 when the minute was in that zone
+CREATE TABLE IF NOT EXISTS active_zone_intraday (
+    time         TIMESTAMPTZ NOT NULL,
+    fat_burn_min SMALLINT NOT NULL DEFAULT 0,
+    cardio_min   SMALLINT NOT NULL DEFAULT 0,
+    peak_min     SMALLINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (time)
+);
+SELECT create_hypertable('active_zone_intraday', by_range('time'), if_not_exists => TRUE);
+SELECT add_retention_policy('active_zone_intraday', INTERVAL '90 days', if_not_exists => TRUE);
+
+-- +goose Down
+DROP TABLE IF EXISTS active_zone_intraday;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Scale sleep_stage_quality_pct from a 0-1 fraction to 0-100. Rows stay
+-- weighted by episode count until their day is re-synced; new rows are
+-- weighted by time.
+UPDATE daily_data_quality
+SET sleep_stage_quality_pct = sleep_stage_quality_pct * 100;
+
+-- +goose Down
+UPDATE daily_data_quality
+SET sleep_stage_quality_pct = sleep_stage_quality_pct / 100.0;
@@ -0,0 +1,18 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultQueryTimeout bounds a single repository operation when no timeout is configured.
+const DefaultQueryTimeout = 30 * time.Second
+
+// WithQueryTimeout derives a context that expires after d so a hung query
+// cannot block its caller indefinitely. A non-positive d only adds cancellation.
+func WithQueryTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
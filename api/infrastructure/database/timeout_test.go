@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithQueryTimeout(t *testing.T) {
+	t.Run("sets deadline", func(t *testing.T) {
+		ctx, cancel := WithQueryTimeout(context.Background(), time.Second)
+		defer cancel()
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("Deadline() ok = false, want true")
+		}
+		if until := time.Until(deadline); until <= 0 || until > time.Second {
+			t.Errorf("deadline in %v, want within 1s", until)
+		}
+	})
+
+	t.Run("non-positive disables deadline", func(t *testing.T) {
+		ctx, cancel := WithQueryTimeout(context.Background(), 0)
+		defer cancel()
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("Deadline() ok = true, want false")
+		}
+		cancel()
+		if ctx.Err() == nil {
+			t.Error("ctx.Err() = nil after cancel")
+		}
+	})
+
+	t.Run("expires", func(t *testing.T) {
+		ctx, cancel := WithQueryTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+		if ctx.Err() != context.DeadlineExceeded {
+			t.Errorf("ctx.Err() = %v, want DeadlineExceeded", ctx.Err())
+		}
+	})
+}
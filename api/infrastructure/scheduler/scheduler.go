@@ -3,18 +3,75 @@ package scheduler
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 
 	"vitametron/api/application"
 	"vitametron/api/domain/port"
+	"vitametron/api/infrastructure/uploads"
 )
 
+// digestWeekday and digestHour set when the weekly digest is sent (JST).
+const (
+	digestWeekday = time.Monday
+	digestHour    = 9
+)
+
+var jst = time.FixedZone("JST", 9*60*60)
+
+// uploadCleanupInterval is how often abandoned upload directories are removed.
+const uploadCleanupInterval = 24 * time.Hour
+
+// pkceCleanupInterval is how often expired OAuth PKCE states are purged.
+const pkceCleanupInterval = time.Hour
+
+// maxQueuedSyncs caps how many queued dates one drain of the sync queue
+// handles, leaving the rest for the next tick.
+const maxQueuedSyncs = 31
+
+// maxQueuedSyncRetries is how many times a queued date whose sync failed is
+// put back on the queue before it is dropped.
+const maxQueuedSyncRetries = 3
+
+// queuedSyncTimeout bounds the sync of one queued date; queueOpTimeout bounds
+// a single queue or authorization call.
+const (
+	queuedSyncTimeout = 120 * time.Second
+	queueOpTimeout    = 5 * time.Second
+)
+
+// UploadCleaner removes upload directories untouched for longer than maxAge.
+type UploadCleaner interface {
+	Cleanup(ctx context.Context, maxAge time.Duration) (*uploads.CleanupResult, error)
+}
+
+// PKCEStateCleaner purges expired OAuth PKCE states.
+type PKCEStateCleaner interface {
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
 type Scheduler struct {
-	syncUC   application.SyncUseCase
-	oauth    port.OAuthProvider
+	syncUC        application.SyncUseCase
+	oauth         port.OAuthProvider
+	digest        application.DigestUseCase
+	vriAlert      application.VRIAlertUseCase
+	cleaner       UploadCleaner
+	pkceCleaner   PKCEStateCleaner
+	status        port.SyncStatusStore
+	provider      string
+	queue         port.SyncQueue
+	queueInterval time.Duration
+	stop          chan struct{}
+	done          chan struct{}
+
+	// queueRetries counts failed syncs per queued date; only the run loop
+	// touches it.
+	queueRetries map[string]int
+
+	mu       sync.Mutex
 	interval time.Duration
-	stop     chan struct{}
-	done     chan struct{}
+	// intervalChanged wakes the run loop to reset its sync ticker.
+	intervalChanged chan struct{}
 }
 
 func New(syncUC application.SyncUseCase, oauth port.OAuthProvider, interval time.Duration) *Scheduler {
@@ -24,6 +81,70 @@ func New(syncUC application.SyncUseCase, oauth port.OAuthProvider, interval time
 		interval: interval,
 		stop:     make(chan struct{}),
 		done:     make(chan struct{}),
+
+		queueRetries: make(map[string]int),
+
+		intervalChanged: make(chan struct{}, 1),
+	}
+}
+
+// WithWeeklyDigest sends the previous calendar week's digest every Monday morning.
+func (s *Scheduler) WithWeeklyDigest(digest application.DigestUseCase) *Scheduler {
+	s.digest = digest
+	return s
+}
+
+// WithVRIAlertDigest reports the previous week's low-VRI days every Monday
+// morning, alongside the weekly digest.
+func (s *Scheduler) WithVRIAlertDigest(uc application.VRIAlertUseCase) *Scheduler {
+	s.vriAlert = uc
+	return s
+}
+
+// WithUploadCleanup removes abandoned upload directories once a day.
+func (s *Scheduler) WithUploadCleanup(cleaner UploadCleaner) *Scheduler {
+	s.cleaner = cleaner
+	return s
+}
+
+// WithPKCEStateCleanup purges expired OAuth PKCE states once an hour.
+func (s *Scheduler) WithPKCEStateCleanup(cleaner PKCEStateCleaner) *Scheduler {
+	s.pkceCleaner = cleaner
+	return s
+}
+
+// WithSyncStatus records each sync outcome for provider, for GET /api/sync/providers.
+func (s *Scheduler) WithSyncStatus(store port.SyncStatusStore, provider string) *Scheduler {
+	s.status = store
+	s.provider = provider
+	return s
+}
+
+// WithSyncQueue syncs dates queued by provider push notifications, checking
+// the queue every interval.
+func (s *Scheduler) WithSyncQueue(queue port.SyncQueue, interval time.Duration) *Scheduler {
+	s.queue = queue
+	s.queueInterval = interval
+	return s
+}
+
+// Interval returns the current sync interval.
+func (s *Scheduler) Interval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interval
+}
+
+// SetInterval changes the sync interval. A running scheduler restarts its
+// sync ticker, so the next sync is d from now.
+func (s *Scheduler) SetInterval(d time.Duration) {
+	s.mu.Lock()
+	s.interval = d
+	s.mu.Unlock()
+
+	select {
+	case s.intervalChanged <- struct{}{}:
+	default:
 	}
 }
 
@@ -39,19 +160,130 @@ func (s *Scheduler) Stop() {
 func (s *Scheduler) run() {
 	defer close(s.done)
 
-	ticker := time.NewTicker(s.interval)
+	ticker := time.NewTicker(s.Interval())
 	defer ticker.Stop()
 
+	var digestTimer *time.Timer
+	var digestC <-chan time.Time
+	if s.digest != nil {
+		digestTimer = time.NewTimer(time.Until(nextDigestRun(time.Now())))
+		defer digestTimer.Stop()
+		digestC = digestTimer.C
+	}
+
+	var vriAlertTimer *time.Timer
+	var vriAlertC <-chan time.Time
+	if s.vriAlert != nil {
+		vriAlertTimer = time.NewTimer(time.Until(nextDigestRun(time.Now())))
+		defer vriAlertTimer.Stop()
+		vriAlertC = vriAlertTimer.C
+	}
+
+	var cleanupC <-chan time.Time
+	if s.cleaner != nil {
+		cleanupTicker := time.NewTicker(uploadCleanupInterval)
+		defer cleanupTicker.Stop()
+		cleanupC = cleanupTicker.C
+	}
+
+	var pkceCleanupC <-chan time.Time
+	if s.pkceCleaner != nil {
+		pkceCleanupTicker := time.NewTicker(pkceCleanupInterval)
+		defer pkceCleanupTicker.Stop()
+		pkceCleanupC = pkceCleanupTicker.C
+	}
+
+	var queueC <-chan time.Time
+	if s.queue != nil {
+		queueTicker := time.NewTicker(s.queueInterval)
+		defer queueTicker.Stop()
+		queueC = queueTicker.C
+	}
+
 	for {
 		select {
 		case <-s.stop:
 			return
 		case <-ticker.C:
 			s.sync()
+		case <-s.intervalChanged:
+			ticker.Reset(s.Interval())
+		case <-queueC:
+			s.drainSyncQueue()
+		case <-cleanupC:
+			s.cleanupUploads()
+		case <-pkceCleanupC:
+			s.cleanupPKCEStates()
+		case <-digestC:
+			s.sendDigest()
+			digestTimer.Reset(time.Until(nextDigestRun(time.Now())))
+		case <-vriAlertC:
+			s.sendVRIAlert()
+			vriAlertTimer.Reset(time.Until(nextDigestRun(time.Now())))
 		}
 	}
 }
 
+// nextDigestRun returns the first digest slot strictly after now.
+func nextDigestRun(now time.Time) time.Time {
+	now = now.In(jst)
+	days := (int(digestWeekday) - int(now.Weekday()) + 7) % 7
+	next := time.Date(now.Year(), now.Month(), now.Day()+days, digestHour, 0, 0, 0, jst)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+func (s *Scheduler) cleanupUploads() {
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	result, err := s.cleaner.Cleanup(ctx, uploads.DefaultMaxAge)
+	if err != nil {
+		log.Printf("scheduler: upload cleanup failed: %v", err)
+		return
+	}
+	log.Printf("scheduler: upload cleanup removed %d dirs and %d zips (%d bytes)", result.CleanedDirs, result.CleanedZips, result.FreedBytes)
+}
+
+func (s *Scheduler) cleanupPKCEStates() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	n, err := s.pkceCleaner.DeleteExpired(ctx)
+	if err != nil {
+		log.Printf("scheduler: pkce state cleanup failed: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("scheduler: pkce state cleanup removed %d states", n)
+	}
+}
+
+func (s *Scheduler) sendDigest() {
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	weekStart := application.LastCompletedWeek(time.Now().In(jst))
+	digest, err := s.digest.Send(ctx, weekStart)
+	if err != nil {
+		log.Printf("scheduler: weekly digest %s for %s failed: %v", digest.JobID, weekStart.Format("2006-01-02"), err)
+		return
+	}
+	log.Printf("scheduler: weekly digest %s for %s sent", digest.JobID, weekStart.Format("2006-01-02"))
+}
+
+func (s *Scheduler) sendVRIAlert() {
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	weekStart := application.LastCompletedWeek(time.Now().In(jst))
+	if _, err := s.vriAlert.Send(ctx, weekStart); err != nil {
+		log.Printf("scheduler: VRI alert for %s failed: %v", weekStart.Format("2006-01-02"), err)
+	}
+}
+
 func (s *Scheduler) sync() {
 	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 	defer cancel()
@@ -66,10 +298,104 @@ func (s *Scheduler) sync() {
 		return
 	}
 
-	if err := s.syncUC.SyncDate(ctx, time.Now()); err != nil {
+	err = s.syncUC.SyncDate(ctx, time.Now())
+	s.recordStatus(ctx, err)
+	if err != nil {
+		if attempts := application.SyncErrors(err); len(attempts) > 0 {
+			for _, a := range attempts {
+				log.Printf("scheduler: sync %s attempt %d failed: %v", a.Date.Format("2006-01-02"), a.Attempt, a.Err)
+			}
+			return
+		}
 		log.Printf("scheduler: sync failed: %v", err)
 		return
 	}
 
 	log.Printf("scheduler: sync completed")
 }
+
+// drainSyncQueue syncs up to maxQueuedSyncs queued dates, skipping
+// duplicates. Each date gets its own queuedSyncTimeout. Failed dates are put
+// back on the queue after the drain, up to maxQueuedSyncRetries times.
+// Nothing is dequeued while the provider is not authorized.
+func (s *Scheduler) drainSyncQueue() {
+	ctx, cancel := context.WithTimeout(context.Background(), queueOpTimeout)
+	authorized, err := s.oauth.IsAuthorized(ctx)
+	cancel()
+	if err != nil || !authorized {
+		return
+	}
+
+	var failed []time.Time
+	defer func() { s.requeueFailed(failed) }()
+
+	seen := make(map[string]bool)
+	for i := 0; i < maxQueuedSyncs; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), queueOpTimeout)
+		date, ok, err := s.queue.Dequeue(ctx)
+		cancel()
+		if err != nil {
+			log.Printf("scheduler: failed to read sync queue: %v", err)
+			return
+		}
+		if !ok {
+			return
+		}
+		key := date.Format("2006-01-02")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if err := s.syncQueuedDate(date); err != nil {
+			log.Printf("scheduler: queued sync %s failed: %v", key, err)
+			failed = append(failed, date)
+			continue
+		}
+		delete(s.queueRetries, key)
+		log.Printf("scheduler: queued sync %s completed", key)
+	}
+}
+
+func (s *Scheduler) syncQueuedDate(date time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queuedSyncTimeout)
+	defer cancel()
+
+	err := s.syncUC.SyncDate(ctx, date)
+	s.recordStatus(ctx, err)
+	return err
+}
+
+// requeueFailed puts failed dates back on the queue, dropping those that
+// have used up their retries.
+func (s *Scheduler) requeueFailed(dates []time.Time) {
+	retry := make([]time.Time, 0, len(dates))
+	for _, d := range dates {
+		key := d.Format("2006-01-02")
+		s.queueRetries[key]++
+		if s.queueRetries[key] > maxQueuedSyncRetries {
+			log.Printf("scheduler: dropping queued sync %s after %d retries", key, maxQueuedSyncRetries)
+			delete(s.queueRetries, key)
+			continue
+		}
+		retry = append(retry, d)
+	}
+	if len(retry) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queueOpTimeout)
+	defer cancel()
+	if err := s.queue.Enqueue(ctx, retry); err != nil {
+		log.Printf("scheduler: failed to requeue %d dates: %v", len(retry), err)
+	}
+}
+
+func (s *Scheduler) recordStatus(ctx context.Context, syncErr error) {
+	if s.status == nil {
+		return
+	}
+	if err := s.status.RecordSync(ctx, s.provider, time.Now(), syncErr); err != nil {
+		log.Printf("scheduler: failed to record %s sync status: %v", s.provider, err)
+	}
+}
@@ -2,9 +2,13 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"vitametron/api/domain/entity"
 )
 
 // --- stubs ---
@@ -29,6 +33,7 @@ func (s *stubOAuth) ExchangeCode(_ context.Context, _, _ string) error { return
 func (s *stubOAuth) RefreshTokenIfNeeded(_ context.Context) error      { return nil }
 func (s *stubOAuth) IsAuthorized(_ context.Context) (bool, error)      { return s.authorized, nil }
 func (s *stubOAuth) Disconnect(_ context.Context) error                { return nil }
+func (s *stubOAuth) GetAccessToken(_ context.Context) (string, error)  { return "", nil }
 
 // --- tests ---
 
@@ -84,3 +89,208 @@ func TestScheduler_StopsGracefully(t *testing.T) {
 		t.Fatal("Stop did not return within 1 second")
 	}
 }
+
+type stubSyncStatus struct {
+	mu       sync.Mutex
+	provider string
+	records  int
+}
+
+func (s *stubSyncStatus) RecordSync(_ context.Context, provider string, _ time.Time, _ error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.provider = provider
+	s.records++
+	return nil
+}
+
+func (s *stubSyncStatus) List(_ context.Context) ([]entity.ProviderSyncStatus, error) {
+	return nil, nil
+}
+
+func TestScheduler_RecordsSyncStatus(t *testing.T) {
+	status := &stubSyncStatus{}
+	sched := New(&stubSyncUC{}, &stubOAuth{authorized: true}, 10*time.Millisecond).
+		WithSyncStatus(status, "fitbit")
+	sched.Start()
+
+	time.Sleep(35 * time.Millisecond)
+	sched.Stop()
+
+	status.mu.Lock()
+	defer status.mu.Unlock()
+	if status.records == 0 {
+		t.Fatal("no sync status recorded")
+	}
+	if status.provider != "fitbit" {
+		t.Errorf("provider = %q, want fitbit", status.provider)
+	}
+}
+
+func TestNextDigestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"sunday", time.Date(2025, 1, 12, 20, 0, 0, 0, jst), time.Date(2025, 1, 13, 9, 0, 0, 0, jst)},
+		{"monday before slot", time.Date(2025, 1, 13, 8, 59, 0, 0, jst), time.Date(2025, 1, 13, 9, 0, 0, 0, jst)},
+		{"monday at slot", time.Date(2025, 1, 13, 9, 0, 0, 0, jst), time.Date(2025, 1, 20, 9, 0, 0, 0, jst)},
+		{"utc input", time.Date(2025, 1, 12, 23, 30, 0, 0, time.UTC), time.Date(2025, 1, 13, 9, 0, 0, 0, jst)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextDigestRun(tt.now); !got.Equal(tt.want) {
+				t.Errorf("nextDigestRun() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type stubSyncQueue struct {
+	mu    sync.Mutex
+	dates []time.Time
+}
+
+func (q *stubSyncQueue) Enqueue(_ context.Context, dates []time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.dates = append(q.dates, dates...)
+	return nil
+}
+
+func (q *stubSyncQueue) Dequeue(_ context.Context) (time.Time, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.dates) == 0 {
+		return time.Time{}, false, nil
+	}
+	d := q.dates[0]
+	q.dates = q.dates[1:]
+	return d, true, nil
+}
+
+func TestScheduler_DrainsSyncQueue(t *testing.T) {
+	day := time.Date(2026, 4, 18, 0, 0, 0, 0, jst)
+	queue := &stubSyncQueue{dates: []time.Time{day, day, day.AddDate(0, 0, 1)}}
+	syncUC := &stubSyncUC{}
+	sched := New(syncUC, &stubOAuth{authorized: true}, time.Hour).
+		WithSyncQueue(queue, 10*time.Millisecond)
+	sched.Start()
+
+	time.Sleep(35 * time.Millisecond)
+	sched.Stop()
+
+	if got := syncUC.callCount.Load(); got != 2 {
+		t.Errorf("sync calls = %d, want 2 (duplicate date skipped)", got)
+	}
+}
+
+// failingSyncUC fails every sync and records each call's deadline.
+type failingSyncUC struct {
+	deadlines []time.Time
+}
+
+func (s *failingSyncUC) SyncDate(ctx context.Context, _ time.Time) error {
+	d, _ := ctx.Deadline()
+	s.deadlines = append(s.deadlines, d)
+	return errors.New("fitbit unavailable")
+}
+
+func TestScheduler_RequeuesFailedQueuedSync(t *testing.T) {
+	day := time.Date(2026, 4, 18, 0, 0, 0, 0, jst)
+	queue := &stubSyncQueue{dates: []time.Time{day}}
+	syncUC := &failingSyncUC{}
+	sched := New(syncUC, &stubOAuth{authorized: true}, time.Hour).
+		WithSyncQueue(queue, time.Hour)
+
+	for i := 0; i < maxQueuedSyncRetries+2; i++ {
+		sched.drainSyncQueue()
+	}
+
+	if got, want := len(syncUC.deadlines), maxQueuedSyncRetries+1; got != want {
+		t.Errorf("sync attempts = %d, want %d (first try plus retries)", got, want)
+	}
+	if len(queue.dates) != 0 {
+		t.Errorf("queue length = %d, want 0 after retries are used up", len(queue.dates))
+	}
+	if len(sched.queueRetries) != 0 {
+		t.Errorf("retry counts = %v, want none left", sched.queueRetries)
+	}
+}
+
+func TestScheduler_QueuedSyncsGetOwnTimeout(t *testing.T) {
+	day := time.Date(2026, 4, 18, 0, 0, 0, 0, jst)
+	queue := &stubSyncQueue{dates: []time.Time{day, day.AddDate(0, 0, 1)}}
+	syncUC := &failingSyncUC{}
+	sched := New(syncUC, &stubOAuth{authorized: true}, time.Hour).
+		WithSyncQueue(queue, time.Hour)
+
+	start := time.Now()
+	sched.drainSyncQueue()
+
+	if len(syncUC.deadlines) != 2 {
+		t.Fatalf("sync attempts = %d, want 2", len(syncUC.deadlines))
+	}
+	for i, d := range syncUC.deadlines {
+		if d.Before(start.Add(queuedSyncTimeout)) {
+			t.Errorf("sync %d deadline = %v, want at least %v after the drain started", i, d.Sub(start), queuedSyncTimeout)
+		}
+	}
+}
+
+func TestScheduler_SyncQueueWaitsForAuthorization(t *testing.T) {
+	queue := &stubSyncQueue{dates: []time.Time{time.Now()}}
+	syncUC := &stubSyncUC{}
+	sched := New(syncUC, &stubOAuth{authorized: false}, time.Hour).
+		WithSyncQueue(queue, 10*time.Millisecond)
+	sched.Start()
+
+	time.Sleep(35 * time.Millisecond)
+	sched.Stop()
+
+	if got := syncUC.callCount.Load(); got != 0 {
+		t.Errorf("sync calls = %d, want 0", got)
+	}
+	if len(queue.dates) != 1 {
+		t.Errorf("queue length = %d, want 1 (left for later)", len(queue.dates))
+	}
+}
+
+func TestScheduler_SetInterval(t *testing.T) {
+	syncUC := &stubSyncUC{}
+	oauth := &stubOAuth{authorized: true}
+
+	sched := New(syncUC, oauth, time.Hour)
+	sched.Start()
+	defer sched.Stop()
+
+	sched.SetInterval(10 * time.Millisecond)
+	if got := sched.Interval(); got != 10*time.Millisecond {
+		t.Errorf("Interval() = %v, want 10ms", got)
+	}
+
+	time.Sleep(55 * time.Millisecond)
+	if count := syncUC.callCount.Load(); count < 2 {
+		t.Errorf("expected at least 2 sync calls after shortening the interval, got %d", count)
+	}
+}
+
+type stubPKCECleaner struct {
+	calls atomic.Int64
+}
+
+func (s *stubPKCECleaner) DeleteExpired(_ context.Context) (int64, error) {
+	s.calls.Add(1)
+	return 3, nil
+}
+
+func TestScheduler_CleanupPKCEStates(t *testing.T) {
+	cleaner := &stubPKCECleaner{}
+	sched := New(&stubSyncUC{}, &stubOAuth{}, time.Hour).WithPKCEStateCleanup(cleaner)
+
+	sched.cleanupPKCEStates()
+	if got := cleaner.calls.Load(); got != 1 {
+		t.Errorf("DeleteExpired calls = %d, want 1", got)
+	}
+}
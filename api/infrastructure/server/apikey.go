@@ -0,0 +1,26 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIKeyHeader is the request header carrying the admin API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuth rejects requests whose X-API-Key header does not match key.
+// If key is empty every request is rejected, so admin routes stay closed
+// until a key is configured.
+func APIKeyAuth(key string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			got := c.Request().Header.Get(APIKeyHeader)
+			if key == "" || subtle.ConstantTimeCompare([]byte(got), []byte(key)) != 1 {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid API key"})
+			}
+			return next(c)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -14,8 +15,20 @@ type Pinger interface {
 	Ping(ctx context.Context) error
 }
 
+// DefaultHealthMaxLatency is the component latency above which /api/health
+// reports the service as degraded.
+const DefaultHealthMaxLatency = time.Second
+
+// ComponentHealth is the per-dependency entry in the /api/health response.
+type ComponentHealth struct {
+	Status    string `json:"status"`
+	LatencyMs int    `json:"latency_ms"`
+}
+
 type Server struct {
 	Echo *echo.Echo
+	// HealthMaxLatency is the slowest acceptable ping for a health component.
+	HealthMaxLatency time.Duration
 }
 
 func New() *Server {
@@ -30,8 +43,9 @@ func New() *Server {
 			return strings.HasPrefix(c.Path(), "/api/import/healthkit")
 		},
 	}))
+	e.Use(Envelope())
 
-	return &Server{Echo: e}
+	return &Server{Echo: e, HealthMaxLatency: DefaultHealthMaxLatency}
 }
 
 // Start starts the Echo server on the given address (e.g. ":8080").
@@ -46,25 +60,46 @@ func (s *Server) RegisterHealthRoutes(dbPinger, redisPinger Pinger) {
 	})
 
 	s.Echo.GET("/api/health", func(c echo.Context) error {
-		result := map[string]string{"status": "ok"}
+		ctx := c.Request().Context()
+		result := map[string]interface{}{"status": "ok"}
+		components := map[string]ComponentHealth{}
 		status := http.StatusOK
 
-		if err := dbPinger.Ping(c.Request().Context()); err != nil {
-			result["db"] = "error"
-			result["status"] = "degraded"
-			status = http.StatusServiceUnavailable
-		} else {
-			result["db"] = "ok"
-		}
-
-		if err := redisPinger.Ping(c.Request().Context()); err != nil {
-			result["redis"] = "error"
-			result["status"] = "degraded"
-			status = http.StatusServiceUnavailable
-		} else {
-			result["redis"] = "ok"
+		for _, comp := range []struct {
+			name   string
+			pinger Pinger
+		}{
+			{"db", dbPinger},
+			{"redis", redisPinger},
+		} {
+			ch := s.checkComponent(ctx, comp.pinger)
+			if ch.Status != "ok" {
+				result["status"] = "degraded"
+				status = http.StatusServiceUnavailable
+			}
+			result[comp.name] = ch.Status
+			components[comp.name] = ch
 		}
 
+		result["components"] = components
+		result["components_checked"] = len(components)
 		return c.JSON(status, result)
 	})
 }
+
+// checkComponent pings p and reports "error" on failure or "slow" when the
+// round trip exceeds HealthMaxLatency.
+func (s *Server) checkComponent(ctx context.Context, p Pinger) ComponentHealth {
+	start := time.Now()
+	err := p.Ping(ctx)
+	elapsed := time.Since(start)
+
+	ch := ComponentHealth{Status: "ok", LatencyMs: int(elapsed.Milliseconds())}
+	switch {
+	case err != nil:
+		ch.Status = "error"
+	case s.HealthMaxLatency > 0 && elapsed > s.HealthMaxLatency:
+		ch.Status = "slow"
+	}
+	return ch
+}
@@ -7,16 +7,27 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 type mockPinger struct {
-	err error
+	err   error
+	delay time.Duration
 }
 
 func (m *mockPinger) Ping(_ context.Context) error {
+	time.Sleep(m.delay)
 	return m.err
 }
 
+type apiHealthBody struct {
+	Status            string                     `json:"status"`
+	DB                string                     `json:"db"`
+	Redis             string                     `json:"redis"`
+	Components        map[string]ComponentHealth `json:"components"`
+	ComponentsChecked int                        `json:"components_checked"`
+}
+
 func TestHealthEndpoint(t *testing.T) {
 	srv := New()
 	srv.RegisterHealthRoutes(&mockPinger{}, &mockPinger{})
@@ -50,15 +61,21 @@ func TestAPIHealth_AllOK(t *testing.T) {
 		t.Errorf("GET /api/health status = %d, want %d", rec.Code, http.StatusOK)
 	}
 
-	var body map[string]string
+	var body apiHealthBody
 	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
 		t.Fatalf("failed to parse response: %v", err)
 	}
-	if body["db"] != "ok" {
-		t.Errorf("db = %q, want %q", body["db"], "ok")
+	if body.DB != "ok" {
+		t.Errorf("db = %q, want %q", body.DB, "ok")
 	}
-	if body["redis"] != "ok" {
-		t.Errorf("redis = %q, want %q", body["redis"], "ok")
+	if body.Redis != "ok" {
+		t.Errorf("redis = %q, want %q", body.Redis, "ok")
+	}
+	if body.ComponentsChecked != 2 {
+		t.Errorf("components_checked = %d, want %d", body.ComponentsChecked, 2)
+	}
+	if _, ok := body.Components["db"]; !ok {
+		t.Error("components missing db entry")
 	}
 }
 
@@ -74,15 +91,15 @@ func TestAPIHealth_DBDown(t *testing.T) {
 		t.Errorf("GET /api/health status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
 	}
 
-	var body map[string]string
+	var body apiHealthBody
 	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
 		t.Fatalf("failed to parse response: %v", err)
 	}
-	if body["db"] != "error" {
-		t.Errorf("db = %q, want %q", body["db"], "error")
+	if body.DB != "error" {
+		t.Errorf("db = %q, want %q", body.DB, "error")
 	}
-	if body["status"] != "degraded" {
-		t.Errorf("status = %q, want %q", body["status"], "degraded")
+	if body.Status != "degraded" {
+		t.Errorf("status = %q, want %q", body.Status, "degraded")
 	}
 }
 
@@ -98,3 +115,32 @@ func TestAPIHealth_RedisDown(t *testing.T) {
 		t.Errorf("GET /api/health status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
 	}
 }
+
+func TestAPIHealth_LatencyExceeded(t *testing.T) {
+	srv := New()
+	srv.HealthMaxLatency = 10 * time.Millisecond
+	srv.RegisterHealthRoutes(&mockPinger{}, &mockPinger{delay: 30 * time.Millisecond})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+	rec := httptest.NewRecorder()
+	srv.Echo.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET /api/health status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	var body apiHealthBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	redis := body.Components["redis"]
+	if redis.Status != "slow" {
+		t.Errorf("redis status = %q, want %q", redis.Status, "slow")
+	}
+	if redis.LatencyMs < 30 {
+		t.Errorf("redis latency_ms = %d, want >= 30", redis.LatencyMs)
+	}
+	if body.Components["db"].Status != "ok" {
+		t.Errorf("db status = %q, want %q", body.Components["db"].Status, "ok")
+	}
+}
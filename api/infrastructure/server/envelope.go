@@ -0,0 +1,119 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// EnvelopeVersion is the version reported in enveloped responses.
+const EnvelopeVersion = "1.0"
+
+// ResponseEnvelope wraps a JSON response body when ?envelope=true is set.
+type ResponseEnvelope struct {
+	Data      json.RawMessage `json:"data"`
+	RequestID string          `json:"request_id"`
+	Timestamp string          `json:"timestamp"`
+	Version   string          `json:"version"`
+}
+
+// Envelope wraps JSON responses in a ResponseEnvelope when the request has
+// ?envelope=true. The request ID is taken from X-Request-Id or generated.
+// Non-JSON and empty responses, such as exports, event streams and 304s,
+// are written through unchanged.
+func Envelope() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.QueryParam("envelope") != "true" {
+				return next(c)
+			}
+
+			res := c.Response()
+			orig := res.Writer
+			w := &envelopeWriter{ResponseWriter: orig}
+			res.Writer = w
+			err := next(c)
+			if err != nil {
+				// Run the error handler now so its body is enveloped too.
+				c.Error(err)
+			}
+			res.Writer = orig
+
+			if w.passthrough || !w.wroteHeader {
+				return nil
+			}
+
+			requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+			if requestID == "" {
+				requestID = uuid.NewString()
+			}
+			body, merr := json.Marshal(ResponseEnvelope{
+				Data:      json.RawMessage(w.buf.Bytes()),
+				RequestID: requestID,
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+				Version:   EnvelopeVersion,
+			})
+			if merr != nil {
+				// Not valid JSON after all; send the body as the handler wrote it.
+				body = w.buf.Bytes()
+			} else {
+				orig.Header().Set(echo.HeaderXRequestID, requestID)
+			}
+			orig.Header().Set(echo.HeaderContentLength, strconv.Itoa(len(body)))
+			orig.WriteHeader(w.status)
+			_, werr := orig.Write(body)
+			return werr
+		}
+	}
+}
+
+// envelopeWriter buffers a JSON response body. Once the header shows the
+// response is not JSON it switches to writing straight through.
+type envelopeWriter struct {
+	http.ResponseWriter
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	passthrough bool
+}
+
+func (w *envelopeWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	if code == http.StatusNoContent || code == http.StatusNotModified ||
+		!strings.HasPrefix(w.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *envelopeWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buf.Write(b)
+}
+
+// Flush forwards to the underlying writer for streamed responses; buffered
+// JSON is only written once the handler returns.
+func (w *envelopeWriter) Flush() {
+	if w.passthrough {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+func (w *envelopeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
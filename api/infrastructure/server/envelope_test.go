@@ -0,0 +1,130 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newEnvelopeEcho() *echo.Echo {
+	e := echo.New()
+	e.Use(Envelope())
+	e.GET("/json", func(c echo.Context) error {
+		return c.JSON(http.StatusCreated, map[string]int{"value": 42})
+	})
+	e.GET("/text", func(c echo.Context) error {
+		return c.String(http.StatusOK, "plain")
+	})
+	e.GET("/empty", func(c echo.Context) error {
+		return c.NoContent(http.StatusNotModified)
+	})
+	e.GET("/fail", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadRequest, "bad input")
+	})
+	return e
+}
+
+func TestEnvelope_WrapsJSON(t *testing.T) {
+	e := newEnvelopeEcho()
+	req := httptest.NewRequest(http.MethodGet, "/json?envelope=true", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	var env struct {
+		Data      map[string]int `json:"data"`
+		RequestID string         `json:"request_id"`
+		Timestamp string         `json:"timestamp"`
+		Version   string         `json:"version"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("unmarshal: %v (%s)", err, rec.Body.String())
+	}
+	if env.Data["value"] != 42 {
+		t.Errorf("data = %v, want value 42", env.Data)
+	}
+	if env.RequestID != "req-1" {
+		t.Errorf("request_id = %q, want req-1", env.RequestID)
+	}
+	if env.Timestamp == "" {
+		t.Error("timestamp is empty")
+	}
+	if env.Version != EnvelopeVersion {
+		t.Errorf("version = %q, want %q", env.Version, EnvelopeVersion)
+	}
+	if got := rec.Header().Get(echo.HeaderContentLength); got != strconv.Itoa(rec.Body.Len()) {
+		t.Errorf("Content-Length = %q, body is %d bytes", got, rec.Body.Len())
+	}
+}
+
+func TestEnvelope_GeneratesRequestID(t *testing.T) {
+	e := newEnvelopeEcho()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/json?envelope=true", nil))
+
+	var env ResponseEnvelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if env.RequestID == "" {
+		t.Error("request_id is empty")
+	}
+	if rec.Header().Get(echo.HeaderXRequestID) != env.RequestID {
+		t.Errorf("X-Request-Id = %q, want %q", rec.Header().Get(echo.HeaderXRequestID), env.RequestID)
+	}
+}
+
+func TestEnvelope_WrapsErrors(t *testing.T) {
+	e := newEnvelopeEcho()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail?envelope=true", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	var env struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("unmarshal: %v (%s)", err, rec.Body.String())
+	}
+	if env.Data["message"] != "bad input" {
+		t.Errorf("data = %v, want message \"bad input\"", env.Data)
+	}
+}
+
+func TestEnvelope_Passthrough(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{"envelope off", "/json", http.StatusCreated, "{\"value\":42}\n"},
+		{"envelope false", "/json?envelope=false", http.StatusCreated, "{\"value\":42}\n"},
+		{"non-JSON body", "/text?envelope=true", http.StatusOK, "plain"},
+		{"no content", "/empty?envelope=true", http.StatusNotModified, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnvelopeEcho()
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package uploads
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultMaxAge is how long an upload directory may sit untouched before cleanup.
+const DefaultMaxAge = 24 * time.Hour
+
+// chunkKeyPrefixes are the Redis session keys of chunked uploads, keyed by upload ID
+// (the directory name).
+var chunkKeyPrefixes = []string{"hc_chunk:", "hk_chunk:"}
+
+type CleanupResult struct {
+	CleanedDirs int   `json:"cleaned_dirs"`
+	CleanedZips int   `json:"cleaned_zips"`
+	FreedBytes  int64 `json:"freed_bytes"`
+}
+
+// Cleaner removes abandoned chunked-upload directories, and assembled ZIPs
+// that failed imports kept for a retry.
+type Cleaner struct {
+	dir string
+	rdb *redis.Client
+}
+
+func NewCleaner(dir string, rdb *redis.Client) *Cleaner {
+	return &Cleaner{dir: dir, rdb: rdb}
+}
+
+// Cleanup removes directories and assembled ZIPs under the upload dir not
+// modified within maxAge. A directory whose Redis upload session still
+// exists is in use, however old its mtime, and is kept. A missing upload dir
+// is not an error.
+func (c *Cleaner) Cleanup(ctx context.Context, maxAge time.Duration) (*CleanupResult, error) {
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return &CleanupResult{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	result := &CleanupResult{}
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) != ".zip" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+
+		path := filepath.Join(c.dir, e.Name())
+		if !e.IsDir() {
+			if err := os.Remove(path); err != nil {
+				log.Printf("warn: remove upload zip %s: %v", path, err)
+				continue
+			}
+			result.CleanedZips++
+			result.FreedBytes += info.Size()
+			continue
+		}
+
+		keys := make([]string, len(chunkKeyPrefixes))
+		for i, p := range chunkKeyPrefixes {
+			keys[i] = p + e.Name()
+		}
+		live, err := c.rdb.Exists(ctx, keys...).Result()
+		if err != nil {
+			log.Printf("warn: check upload session %s: %v", e.Name(), err)
+			continue
+		}
+		if live > 0 {
+			continue
+		}
+
+		size := dirSize(path)
+		if err := os.RemoveAll(path); err != nil {
+			log.Printf("warn: remove upload dir %s: %v", path, err)
+			continue
+		}
+		result.CleanedDirs++
+		result.FreedBytes += size
+	}
+	return result, nil
+}
+
+func dirSize(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,94 @@
+package uploads
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestCleaner_Cleanup(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	dir := t.TempDir()
+	mkUpload := func(name string, age time.Duration, session bool) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(p, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(p, "000000.part"), make([]byte, 100), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Now().Add(-age)
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		if session {
+			mr.Set("hc_chunk:"+name, "{}")
+		}
+	}
+	mkUpload("old", 48*time.Hour, false)
+	mkUpload("recent", time.Hour, true)
+	// A slow upload still has its session, so its old mtime does not matter.
+	mkUpload("active", 48*time.Hour, true)
+	// Recent assembled zips may belong to a retryable import; old ones go.
+	mkZip := func(name string, age time.Duration) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte("zip"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Now().Add(-age)
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mkZip("job.zip", time.Hour)
+	mkZip("failed.zip", 48*time.Hour)
+	// Other stray files are left alone.
+	mkZip("notes.txt", 48*time.Hour)
+
+	result, err := NewCleaner(dir, rdb).Cleanup(context.Background(), DefaultMaxAge)
+	if err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+
+	if result.CleanedDirs != 1 || result.CleanedZips != 1 || result.FreedBytes != 103 {
+		t.Errorf("result = %+v, want 1 dir / 1 zip / 103 bytes", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old")); !os.IsNotExist(err) {
+		t.Error("old upload dir still exists")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "recent")); err != nil {
+		t.Errorf("recent upload dir removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "active")); err != nil {
+		t.Errorf("upload dir with a live session removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "job.zip")); err != nil {
+		t.Errorf("job.zip removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "failed.zip")); !os.IsNotExist(err) {
+		t.Error("old failed.zip still exists")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Errorf("notes.txt removed: %v", err)
+	}
+	if !mr.Exists("hc_chunk:recent") || !mr.Exists("hc_chunk:active") {
+		t.Error("Redis key for a live upload deleted")
+	}
+}
+
+func TestCleaner_MissingDir(t *testing.T) {
+	result, err := NewCleaner(filepath.Join(t.TempDir(), "missing"), nil).Cleanup(context.Background(), DefaultMaxAge)
+	if err != nil || result.CleanedDirs != 0 {
+		t.Errorf("Cleanup() = %+v, %v; want empty result, nil", result, err)
+	}
+}
@@ -8,15 +8,18 @@ import (
 )
 
 type MockBiometricsProvider struct {
-	ProviderNameFunc           func() string
-	FetchDailySummaryFunc      func(ctx context.Context, date time.Time) (*entity.DailySummary, error)
-	FetchHeartRateIntradayFunc func(ctx context.Context, date time.Time) ([]entity.HeartRateSample, error)
-	FetchSleepStagesFunc       func(ctx context.Context, date time.Time) ([]entity.SleepStage, *entity.SleepRecord, error)
-	FetchExerciseLogsFunc      func(ctx context.Context, date time.Time) ([]entity.ExerciseLog, error)
-	FetchHRVFunc               func(ctx context.Context, date time.Time) (float32, float32, error)
-	FetchSpO2Func              func(ctx context.Context, date time.Time) (float32, float32, float32, error)
-	FetchBreathingRateFunc     func(ctx context.Context, date time.Time) (float32, float32, float32, float32, error)
-	FetchSkinTemperatureFunc   func(ctx context.Context, date time.Time) (float32, error)
+	ProviderNameFunc                   func() string
+	FetchDailySummaryFunc              func(ctx context.Context, date time.Time) (*entity.DailySummary, error)
+	FetchHeartRateIntradayFunc         func(ctx context.Context, date time.Time) ([]entity.HeartRateSample, error)
+	FetchSleepStagesFunc               func(ctx context.Context, date time.Time) ([]entity.SleepStage, *entity.SleepRecord, error)
+	FetchExerciseLogsFunc              func(ctx context.Context, date time.Time) ([]entity.ExerciseLog, error)
+	FetchHRVFunc                       func(ctx context.Context, date time.Time) (float32, float32, error)
+	FetchHRVIntradayFunc               func(ctx context.Context, date time.Time) ([]entity.HRVSample, error)
+	FetchActiveZoneMinutesIntradayFunc func(ctx context.Context, date time.Time) ([]entity.ActiveZoneSample, error)
+	FetchSpO2Func                      func(ctx context.Context, date time.Time) (float32, float32, float32, error)
+	FetchBreathingRateFunc             func(ctx context.Context, date time.Time) (float32, float32, float32, float32, error)
+	FetchSkinTemperatureFunc           func(ctx context.Context, date time.Time) (float32, error)
+	FetchWaterLogFunc                  func(ctx context.Context, date time.Time) (int, error)
 }
 
 func (m *MockBiometricsProvider) ProviderName() string {
@@ -43,6 +46,14 @@ func (m *MockBiometricsProvider) FetchHRV(ctx context.Context, date time.Time) (
 	return m.FetchHRVFunc(ctx, date)
 }
 
+func (m *MockBiometricsProvider) FetchHRVIntraday(ctx context.Context, date time.Time) ([]entity.HRVSample, error) {
+	return m.FetchHRVIntradayFunc(ctx, date)
+}
+
+func (m *MockBiometricsProvider) FetchActiveZoneMinutesIntraday(ctx context.Context, date time.Time) ([]entity.ActiveZoneSample, error) {
+	return m.FetchActiveZoneMinutesIntradayFunc(ctx, date)
+}
+
 func (m *MockBiometricsProvider) FetchSpO2(ctx context.Context, date time.Time) (float32, float32, float32, error) {
 	return m.FetchSpO2Func(ctx, date)
 }
@@ -54,3 +65,43 @@ func (m *MockBiometricsProvider) FetchBreathingRate(ctx context.Context, date ti
 func (m *MockBiometricsProvider) FetchSkinTemperature(ctx context.Context, date time.Time) (float32, error) {
 	return m.FetchSkinTemperatureFunc(ctx, date)
 }
+
+func (m *MockBiometricsProvider) FetchWaterLog(ctx context.Context, date time.Time) (int, error) {
+	return m.FetchWaterLogFunc(ctx, date)
+}
+
+type MockFitbitStatsProvider struct {
+	FetchLifetimeStatsFunc func(ctx context.Context) (*entity.FitbitLifetimeStats, error)
+	FetchBadgesFunc        func(ctx context.Context) ([]entity.FitbitBadge, error)
+}
+
+func (m *MockFitbitStatsProvider) FetchLifetimeStats(ctx context.Context) (*entity.FitbitLifetimeStats, error) {
+	return m.FetchLifetimeStatsFunc(ctx)
+}
+
+func (m *MockFitbitStatsProvider) FetchBadges(ctx context.Context) ([]entity.FitbitBadge, error) {
+	return m.FetchBadgesFunc(ctx)
+}
+
+type MockFitbitSubscriptionProvider struct {
+	FetchSubscriptionsFunc func(ctx context.Context) ([]entity.FitbitSubscription, error)
+	CreateSubscriptionFunc func(ctx context.Context, endpoint, subscriptionID string) (*entity.FitbitSubscription, error)
+	DeleteSubscriptionFunc func(ctx context.Context, subscriptionID string) error
+	VerifyNotificationFunc func(body []byte, signature string) bool
+}
+
+func (m *MockFitbitSubscriptionProvider) FetchSubscriptions(ctx context.Context) ([]entity.FitbitSubscription, error) {
+	return m.FetchSubscriptionsFunc(ctx)
+}
+
+func (m *MockFitbitSubscriptionProvider) CreateSubscription(ctx context.Context, endpoint, subscriptionID string) (*entity.FitbitSubscription, error) {
+	return m.CreateSubscriptionFunc(ctx, endpoint, subscriptionID)
+}
+
+func (m *MockFitbitSubscriptionProvider) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	return m.DeleteSubscriptionFunc(ctx, subscriptionID)
+}
+
+func (m *MockFitbitSubscriptionProvider) VerifyNotification(body []byte, signature string) bool {
+	return m.VerifyNotificationFunc(body, signature)
+}
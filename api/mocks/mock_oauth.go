@@ -8,6 +8,7 @@ type MockOAuthProvider struct {
 	RefreshTokenIfNeededFunc func(ctx context.Context) error
 	IsAuthorizedFunc         func(ctx context.Context) (bool, error)
 	DisconnectFunc           func(ctx context.Context) error
+	GetAccessTokenFunc       func(ctx context.Context) (string, error)
 }
 
 func (m *MockOAuthProvider) AuthorizationURL(ctx context.Context) (string, string, error) {
@@ -29,3 +30,7 @@ func (m *MockOAuthProvider) IsAuthorized(ctx context.Context) (bool, error) {
 func (m *MockOAuthProvider) Disconnect(ctx context.Context) error {
 	return m.DisconnectFunc(ctx)
 }
+
+func (m *MockOAuthProvider) GetAccessToken(ctx context.Context) (string, error) {
+	return m.GetAccessTokenFunc(ctx)
+}
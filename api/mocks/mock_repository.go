@@ -14,7 +14,17 @@ type MockConditionRepository struct {
 	UpdateFunc     func(ctx context.Context, log *entity.ConditionLog) error
 	DeleteFunc     func(ctx context.Context, id int64) error
 	GetTagsFunc    func(ctx context.Context) ([]entity.TagCount, error)
-	GetSummaryFunc func(ctx context.Context, from, to time.Time) (*entity.ConditionSummary, error)
+	GetSummaryFunc func(ctx context.Context, filter entity.ConditionFilter) (*entity.ConditionSummary, error)
+	ArchiveFunc    func(ctx context.Context, before time.Time) (int64, error)
+	RenameTagFunc  func(ctx context.Context, oldTag, newTag string) (int64, error)
+
+	CountBySourceFunc func(ctx context.Context, from, to time.Time) ([]entity.ConditionSourceCount, error)
+	GetVASSeriesFunc  func(ctx context.Context, from, to time.Time) ([]entity.VASPoint, error)
+	ListRangeFunc     func(ctx context.Context, from, to time.Time) ([]entity.ConditionLog, error)
+	GetHistoryFunc    func(ctx context.Context, id int64) ([]entity.ConditionLogHistory, error)
+
+	RestoreFunc         func(ctx context.Context, id int64) (bool, error)
+	DeletePermanentFunc func(ctx context.Context, id int64) (bool, error)
 }
 
 func (m *MockConditionRepository) Create(ctx context.Context, log *entity.ConditionLog) error {
@@ -33,22 +43,55 @@ func (m *MockConditionRepository) Update(ctx context.Context, log *entity.Condit
 	return m.UpdateFunc(ctx, log)
 }
 
+func (m *MockConditionRepository) GetHistory(ctx context.Context, id int64) ([]entity.ConditionLogHistory, error) {
+	return m.GetHistoryFunc(ctx, id)
+}
+
 func (m *MockConditionRepository) Delete(ctx context.Context, id int64) error {
 	return m.DeleteFunc(ctx, id)
 }
 
+func (m *MockConditionRepository) Restore(ctx context.Context, id int64) (bool, error) {
+	return m.RestoreFunc(ctx, id)
+}
+
+func (m *MockConditionRepository) DeletePermanent(ctx context.Context, id int64) (bool, error) {
+	return m.DeletePermanentFunc(ctx, id)
+}
+
 func (m *MockConditionRepository) GetTags(ctx context.Context) ([]entity.TagCount, error) {
 	return m.GetTagsFunc(ctx)
 }
 
-func (m *MockConditionRepository) GetSummary(ctx context.Context, from, to time.Time) (*entity.ConditionSummary, error) {
-	return m.GetSummaryFunc(ctx, from, to)
+func (m *MockConditionRepository) GetSummary(ctx context.Context, filter entity.ConditionFilter) (*entity.ConditionSummary, error) {
+	return m.GetSummaryFunc(ctx, filter)
+}
+
+func (m *MockConditionRepository) Archive(ctx context.Context, before time.Time) (int64, error) {
+	return m.ArchiveFunc(ctx, before)
+}
+
+func (m *MockConditionRepository) ListRange(ctx context.Context, from, to time.Time) ([]entity.ConditionLog, error) {
+	return m.ListRangeFunc(ctx, from, to)
+}
+
+func (m *MockConditionRepository) GetVASSeries(ctx context.Context, from, to time.Time) ([]entity.VASPoint, error) {
+	return m.GetVASSeriesFunc(ctx, from, to)
+}
+
+func (m *MockConditionRepository) CountBySource(ctx context.Context, from, to time.Time) ([]entity.ConditionSourceCount, error) {
+	return m.CountBySourceFunc(ctx, from, to)
+}
+
+func (m *MockConditionRepository) RenameTag(ctx context.Context, oldTag, newTag string) (int64, error) {
+	return m.RenameTagFunc(ctx, oldTag, newTag)
 }
 
 type MockDailySummaryRepository struct {
-	UpsertFunc    func(ctx context.Context, summary *entity.DailySummary) error
-	GetByDateFunc func(ctx context.Context, date time.Time) (*entity.DailySummary, error)
-	ListRangeFunc func(ctx context.Context, from, to time.Time) ([]entity.DailySummary, error)
+	UpsertFunc     func(ctx context.Context, summary *entity.DailySummary) error
+	GetByDateFunc  func(ctx context.Context, date time.Time) (*entity.DailySummary, error)
+	ListRangeFunc  func(ctx context.Context, from, to time.Time) ([]entity.DailySummary, error)
+	CountRangeFunc func(ctx context.Context, from, to time.Time) (int, error)
 }
 
 func (m *MockDailySummaryRepository) Upsert(ctx context.Context, summary *entity.DailySummary) error {
@@ -63,9 +106,15 @@ func (m *MockDailySummaryRepository) ListRange(ctx context.Context, from, to tim
 	return m.ListRangeFunc(ctx, from, to)
 }
 
+func (m *MockDailySummaryRepository) CountRange(ctx context.Context, from, to time.Time) (int, error) {
+	return m.CountRangeFunc(ctx, from, to)
+}
+
 type MockHeartRateRepository struct {
 	BulkUpsertFunc func(ctx context.Context, samples []entity.HeartRateSample) error
 	ListRangeFunc  func(ctx context.Context, from, to time.Time) ([]entity.HeartRateSample, error)
+
+	ListRangeAggregatedFunc func(ctx context.Context, from, to time.Time, bucketMin int) ([]entity.HeartRateBucket, error)
 }
 
 func (m *MockHeartRateRepository) BulkUpsert(ctx context.Context, samples []entity.HeartRateSample) error {
@@ -76,6 +125,137 @@ func (m *MockHeartRateRepository) ListRange(ctx context.Context, from, to time.T
 	return m.ListRangeFunc(ctx, from, to)
 }
 
+func (m *MockHeartRateRepository) ListRangeAggregated(ctx context.Context, from, to time.Time, bucketMin int) ([]entity.HeartRateBucket, error) {
+	return m.ListRangeAggregatedFunc(ctx, from, to, bucketMin)
+}
+
+type MockHRVSampleRepository struct {
+	BulkUpsertFunc func(ctx context.Context, samples []entity.HRVSample) error
+	ListRangeFunc  func(ctx context.Context, from, to time.Time) ([]entity.HRVSample, error)
+}
+
+func (m *MockHRVSampleRepository) BulkUpsert(ctx context.Context, samples []entity.HRVSample) error {
+	return m.BulkUpsertFunc(ctx, samples)
+}
+
+func (m *MockHRVSampleRepository) ListRange(ctx context.Context, from, to time.Time) ([]entity.HRVSample, error) {
+	return m.ListRangeFunc(ctx, from, to)
+}
+
+type MockImportHistoryRepository struct {
+	SaveDevicesFunc func(ctx context.Context, jobID, source string, devices []entity.DeviceInfo) error
+	GetDevicesFunc  func(ctx context.Context, jobID string) ([]entity.DeviceInfo, error)
+}
+
+func (m *MockImportHistoryRepository) SaveDevices(ctx context.Context, jobID, source string, devices []entity.DeviceInfo) error {
+	return m.SaveDevicesFunc(ctx, jobID, source, devices)
+}
+
+func (m *MockImportHistoryRepository) GetDevices(ctx context.Context, jobID string) ([]entity.DeviceInfo, error) {
+	return m.GetDevicesFunc(ctx, jobID)
+}
+
+type MockSyncStatusStore struct {
+	RecordSyncFunc func(ctx context.Context, provider string, at time.Time, syncErr error) error
+	ListFunc       func(ctx context.Context) ([]entity.ProviderSyncStatus, error)
+}
+
+func (m *MockSyncStatusStore) RecordSync(ctx context.Context, provider string, at time.Time, syncErr error) error {
+	return m.RecordSyncFunc(ctx, provider, at, syncErr)
+}
+
+func (m *MockSyncStatusStore) List(ctx context.Context) ([]entity.ProviderSyncStatus, error) {
+	return m.ListFunc(ctx)
+}
+
+type MockSchedulerConfigStore struct {
+	IntervalMinFunc    func(ctx context.Context) (int, bool, error)
+	SetIntervalMinFunc func(ctx context.Context, minutes int) error
+}
+
+func (m *MockSchedulerConfigStore) IntervalMin(ctx context.Context) (int, bool, error) {
+	return m.IntervalMinFunc(ctx)
+}
+
+func (m *MockSchedulerConfigStore) SetIntervalMin(ctx context.Context, minutes int) error {
+	return m.SetIntervalMinFunc(ctx, minutes)
+}
+
+type MockPKCEStateRepository struct {
+	SaveFunc          func(ctx context.Context, state, verifier string, expiresAt time.Time) error
+	TakeFunc          func(ctx context.Context, state string) (string, bool, error)
+	DeleteExpiredFunc func(ctx context.Context) (int64, error)
+}
+
+func (m *MockPKCEStateRepository) Save(ctx context.Context, state, verifier string, expiresAt time.Time) error {
+	return m.SaveFunc(ctx, state, verifier, expiresAt)
+}
+
+func (m *MockPKCEStateRepository) Take(ctx context.Context, state string) (string, bool, error) {
+	return m.TakeFunc(ctx, state)
+}
+
+func (m *MockPKCEStateRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	return m.DeleteExpiredFunc(ctx)
+}
+
+type MockModelMetadataRepository struct {
+	SaveAnomalyTrainFunc   func(ctx context.Context, result *entity.AnomalyTrainResult) error
+	LatestAnomalyTrainFunc func(ctx context.Context) (*entity.AnomalyTrainRun, error)
+	ListAnomalyTrainsFunc  func(ctx context.Context) ([]entity.AnomalyTrainRun, error)
+}
+
+func (m *MockModelMetadataRepository) SaveAnomalyTrain(ctx context.Context, result *entity.AnomalyTrainResult) error {
+	return m.SaveAnomalyTrainFunc(ctx, result)
+}
+
+func (m *MockModelMetadataRepository) LatestAnomalyTrain(ctx context.Context) (*entity.AnomalyTrainRun, error) {
+	return m.LatestAnomalyTrainFunc(ctx)
+}
+
+func (m *MockModelMetadataRepository) ListAnomalyTrains(ctx context.Context) ([]entity.AnomalyTrainRun, error) {
+	return m.ListAnomalyTrainsFunc(ctx)
+}
+
+type MockAlertSentStore struct {
+	MarkSentFunc  func(ctx context.Context, kind, date string) (bool, error)
+	ClearSentFunc func(ctx context.Context, kind, date string) error
+}
+
+func (m *MockAlertSentStore) MarkSent(ctx context.Context, kind, date string) (bool, error) {
+	return m.MarkSentFunc(ctx, kind, date)
+}
+
+func (m *MockAlertSentStore) ClearSent(ctx context.Context, kind, date string) error {
+	return m.ClearSentFunc(ctx, kind, date)
+}
+
+type MockSyncQueue struct {
+	EnqueueFunc func(ctx context.Context, dates []time.Time) error
+	DequeueFunc func(ctx context.Context) (time.Time, bool, error)
+}
+
+func (m *MockSyncQueue) Enqueue(ctx context.Context, dates []time.Time) error {
+	return m.EnqueueFunc(ctx, dates)
+}
+
+func (m *MockSyncQueue) Dequeue(ctx context.Context) (time.Time, bool, error) {
+	return m.DequeueFunc(ctx)
+}
+
+type MockActiveZoneSampleRepository struct {
+	BulkUpsertFunc func(ctx context.Context, samples []entity.ActiveZoneSample) error
+	ListRangeFunc  func(ctx context.Context, from, to time.Time) ([]entity.ActiveZoneSample, error)
+}
+
+func (m *MockActiveZoneSampleRepository) BulkUpsert(ctx context.Context, samples []entity.ActiveZoneSample) error {
+	return m.BulkUpsertFunc(ctx, samples)
+}
+
+func (m *MockActiveZoneSampleRepository) ListRange(ctx context.Context, from, to time.Time) ([]entity.ActiveZoneSample, error) {
+	return m.ListRangeFunc(ctx, from, to)
+}
+
 type MockSleepStageRepository struct {
 	BulkUpsertFunc      func(ctx context.Context, stages []entity.SleepStage) error
 	ListByDateFunc      func(ctx context.Context, date time.Time) ([]entity.SleepStage, error)
@@ -95,8 +275,12 @@ func (m *MockSleepStageRepository) ListByTimeRange(ctx context.Context, from, to
 }
 
 type MockExerciseRepository struct {
-	UpsertFunc    func(ctx context.Context, log *entity.ExerciseLog) error
-	ListRangeFunc func(ctx context.Context, from, to time.Time) ([]entity.ExerciseLog, error)
+	UpsertFunc                func(ctx context.Context, log *entity.ExerciseLog) error
+	ListRangeFunc             func(ctx context.Context, from, to time.Time) ([]entity.ExerciseLog, error)
+	GetByIDFunc               func(ctx context.Context, id int64) (*entity.ExerciseLog, error)
+	UpdateEstimatedVO2MaxFunc func(ctx context.Context, id int64, vo2max float32) error
+	ListByTagFunc             func(ctx context.Context, tag string, from, to time.Time) ([]entity.ExerciseLog, error)
+	UpdateNotesFunc           func(ctx context.Context, id int64, notes string, tags []string) error
 }
 
 func (m *MockExerciseRepository) Upsert(ctx context.Context, log *entity.ExerciseLog) error {
@@ -107,6 +291,35 @@ func (m *MockExerciseRepository) ListRange(ctx context.Context, from, to time.Ti
 	return m.ListRangeFunc(ctx, from, to)
 }
 
+func (m *MockExerciseRepository) GetByID(ctx context.Context, id int64) (*entity.ExerciseLog, error) {
+	return m.GetByIDFunc(ctx, id)
+}
+
+func (m *MockExerciseRepository) UpdateEstimatedVO2Max(ctx context.Context, id int64, vo2max float32) error {
+	return m.UpdateEstimatedVO2MaxFunc(ctx, id, vo2max)
+}
+
+func (m *MockExerciseRepository) ListByTag(ctx context.Context, tag string, from, to time.Time) ([]entity.ExerciseLog, error) {
+	return m.ListByTagFunc(ctx, tag, from, to)
+}
+
+func (m *MockExerciseRepository) UpdateNotes(ctx context.Context, id int64, notes string, tags []string) error {
+	return m.UpdateNotesFunc(ctx, id, notes, tags)
+}
+
+type MockExerciseRouteRepository struct {
+	SaveFunc          func(ctx context.Context, route *entity.ExerciseRoute) error
+	GetByExerciseFunc func(ctx context.Context, externalID string) (*entity.ExerciseRoute, error)
+}
+
+func (m *MockExerciseRouteRepository) Save(ctx context.Context, route *entity.ExerciseRoute) error {
+	return m.SaveFunc(ctx, route)
+}
+
+func (m *MockExerciseRouteRepository) GetByExercise(ctx context.Context, externalID string) (*entity.ExerciseRoute, error) {
+	return m.GetByExerciseFunc(ctx, externalID)
+}
+
 type MockTokenRepository struct {
 	GetFunc    func(ctx context.Context, provider string) ([]byte, []byte, time.Time, error)
 	SaveFunc   func(ctx context.Context, provider string, accessToken, refreshToken []byte, expiresAt time.Time) error
@@ -142,6 +355,7 @@ type MockDataQualityRepository struct {
 	UpsertFunc         func(ctx context.Context, q *entity.DataQuality) error
 	GetByDateFunc      func(ctx context.Context, date time.Time) (*entity.DataQuality, error)
 	ListRangeFunc      func(ctx context.Context, from, to time.Time) ([]entity.DataQuality, error)
+	ListAlertsFunc     func(ctx context.Context, from, to time.Time) ([]entity.DataQuality, error)
 	CountValidDaysFunc func(ctx context.Context, before time.Time, windowDays int) (int, error)
 }
 
@@ -157,13 +371,18 @@ func (m *MockDataQualityRepository) ListRange(ctx context.Context, from, to time
 	return m.ListRangeFunc(ctx, from, to)
 }
 
+func (m *MockDataQualityRepository) ListAlerts(ctx context.Context, from, to time.Time) ([]entity.DataQuality, error) {
+	return m.ListAlertsFunc(ctx, from, to)
+}
+
 func (m *MockDataQualityRepository) CountValidDays(ctx context.Context, before time.Time, windowDays int) (int, error) {
 	return m.CountValidDaysFunc(ctx, before, windowDays)
 }
 
 type MockAnomalyRepository struct {
-	GetByDateFunc func(ctx context.Context, date time.Time) (*entity.AnomalyDetection, error)
-	ListRangeFunc func(ctx context.Context, from, to time.Time) ([]entity.AnomalyDetection, error)
+	GetByDateFunc                func(ctx context.Context, date time.Time) (*entity.AnomalyDetection, error)
+	ListRangeFunc                func(ctx context.Context, from, to time.Time) ([]entity.AnomalyDetection, error)
+	DeleteOtherModelVersionsFunc func(ctx context.Context, modelVersion string) (int64, error)
 }
 
 func (m *MockAnomalyRepository) GetByDate(ctx context.Context, date time.Time) (*entity.AnomalyDetection, error) {
@@ -174,6 +393,10 @@ func (m *MockAnomalyRepository) ListRange(ctx context.Context, from, to time.Tim
 	return m.ListRangeFunc(ctx, from, to)
 }
 
+func (m *MockAnomalyRepository) DeleteOtherModelVersions(ctx context.Context, modelVersion string) (int64, error) {
+	return m.DeleteOtherModelVersionsFunc(ctx, modelVersion)
+}
+
 type MockDivergenceRepository struct {
 	GetByDateFunc func(ctx context.Context, date time.Time) (*entity.DivergenceDetection, error)
 	ListRangeFunc func(ctx context.Context, from, to time.Time) ([]entity.DivergenceDetection, error)
@@ -199,3 +422,26 @@ func (m *MockVRIRepository) GetByDate(ctx context.Context, date time.Time) (*ent
 func (m *MockVRIRepository) ListRange(ctx context.Context, from, to time.Time) ([]entity.VRIScore, error) {
 	return m.ListRangeFunc(ctx, from, to)
 }
+
+type MockWHO5Repository struct {
+	CreateFunc    func(ctx context.Context, a *entity.WHO5Assessment) error
+	GetByIDFunc   func(ctx context.Context, id int64) (*entity.WHO5Assessment, error)
+	GetLatestFunc func(ctx context.Context) (*entity.WHO5Assessment, error)
+	ListFunc      func(ctx context.Context, limit, offset int) ([]entity.WHO5Assessment, int, error)
+}
+
+func (m *MockWHO5Repository) Create(ctx context.Context, a *entity.WHO5Assessment) error {
+	return m.CreateFunc(ctx, a)
+}
+
+func (m *MockWHO5Repository) GetByID(ctx context.Context, id int64) (*entity.WHO5Assessment, error) {
+	return m.GetByIDFunc(ctx, id)
+}
+
+func (m *MockWHO5Repository) GetLatest(ctx context.Context) (*entity.WHO5Assessment, error) {
+	return m.GetLatestFunc(ctx)
+}
+
+func (m *MockWHO5Repository) List(ctx context.Context, limit, offset int) ([]entity.WHO5Assessment, int, error) {
+	return m.ListFunc(ctx, limit, offset)
+}
@@ -0,0 +1,11 @@
+package mocks
+
+import "context"
+
+type MockWebhookSender struct {
+	SendFunc func(ctx context.Context, jobID string, payload any) error
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, jobID string, payload any) error {
+	return m.SendFunc(ctx, jobID, payload)
+}
@@ -3,6 +3,7 @@ import { effectiveDateISO, effectiveDaysAgoISO, isOvernightHours } from '$lib/ut
 import type { ConditionLog, ConditionListResult } from '$lib/types/condition';
 import type {
 	DailySummary,
+	DailySummaryRangeResult,
 	HeartRateSample,
 	SleepStageEntry,
 	DataQuality,
@@ -50,7 +51,7 @@ export async function loadDashboard(): Promise<DashboardData> {
 		todaySummary,
 		recentCondRes,
 		yesterdaySummary,
-		weekSummaries,
+		weekSummaryRes,
 		todayHR,
 		yesterdayHR,
 		todaySleep,
@@ -58,7 +59,7 @@ export async function loadDashboard(): Promise<DashboardData> {
 		dataQuality,
 		todayVRI,
 		weekVRI,
-		monthSummaries,
+		monthSummaryRes,
 		monthVRI,
 		monthCondRes,
 		todayCircadian,
@@ -75,7 +76,10 @@ export async function loadDashboard(): Promise<DashboardData> {
 			{ items: [], total: 0 }
 		),
 		fetchJSON<DailySummary | null>(`/api/biometrics?date=${yesterday}`, null),
-		fetchJSON<DailySummary[]>(`/api/biometrics/range?from=${sevenDaysAgo}&to=${today}`, []),
+		fetchJSON<DailySummaryRangeResult | null>(
+			`/api/biometrics/range?from=${sevenDaysAgo}&to=${today}`,
+			null
+		),
 		fetchJSON<HeartRateSample[]>(`/api/heartrate/intraday?date=${today}`, []),
 		fetchJSON<HeartRateSample[]>(`/api/heartrate/intraday?date=${yesterday}`, []),
 		fetchJSON<SleepStageEntry[]>(`/api/sleep/stages?date=${today}`, []),
@@ -83,7 +87,10 @@ export async function loadDashboard(): Promise<DashboardData> {
 		fetchJSON<DataQuality | null>(`/api/biometrics/quality?date=${today}`, null),
 		fetchJSON<VRIScore | null>(`/api/vri?date=${today}`, null),
 		fetchJSON<VRIScore[]>(`/api/vri/range?from=${sevenDaysAgo}&to=${today}`, []),
-		fetchJSON<DailySummary[]>(`/api/biometrics/range?from=${thirtyDaysAgo}&to=${today}`, []),
+		fetchJSON<DailySummaryRangeResult | null>(
+			`/api/biometrics/range?from=${thirtyDaysAgo}&to=${today}`,
+			null
+		),
 		fetchJSON<VRIScore[]>(`/api/vri/range?from=${thirtyDaysAgo}&to=${today}`, []),
 		fetchJSON<ConditionListResult>(
 			`/api/conditions?from=${thirtyDaysAgo}&to=${today}&limit=30&sort=logged_at&order=asc`,
@@ -99,7 +106,7 @@ export async function loadDashboard(): Promise<DashboardData> {
 		todaySummary,
 		recentConditions: recentCondRes.items ?? [],
 		yesterdaySummary,
-		weekSummaries,
+		weekSummaries: weekSummaryRes?.items ?? [],
 		todayHR,
 		yesterdayHR,
 		todaySleep,
@@ -107,7 +114,7 @@ export async function loadDashboard(): Promise<DashboardData> {
 		dataQuality,
 		todayVRI,
 		weekVRI,
-		monthSummaries,
+		monthSummaries: monthSummaryRes?.items ?? [],
 		monthVRI,
 		monthConditions: monthCondRes.items ?? [],
 		todayCircadian,
@@ -1,7 +1,7 @@
 /** Matches Go entity.DailySummary (PascalCase JSON, no json tags) */
 export interface DailySummary {
 	Date: string;
-	Provider: string;
+	Providers: string[];
 
 	// Heart rate
 	RestingHR: number;
@@ -52,6 +52,8 @@ export interface DailySummary {
 	MinutesLightly: number;
 	MinutesFairly: number;
 	MinutesVery: number;
+	/** (lightly*1 + fairly*2 + very*3) / tracked minutes * 100, 0-300 */
+	ActivityIntensityScore: number;
 
 	// VO2 Max
 	VO2Max: number | null;
@@ -62,7 +64,16 @@ export interface DailySummary {
 	HRZoneCardioMin: number;
 	HRZonePeakMin: number;
 
+	// Hydration (mL)
+	WaterIntakeMl: number;
+
+	/** Metrics copied from the previous day because this day's reading was missing */
+	FilledForwardFields: string[];
+
 	SyncedAt: string;
+
+	/** Set by the API when SyncedAt is older than the freshness threshold */
+	stale_since?: string;
 }
 
 export interface HeartRateSample {
@@ -78,6 +89,16 @@ export interface SleepStageEntry {
 	Stage: SleepStage;
 	Seconds: number;
 	LogID: number;
+	Source: 'fitbit' | 'health_connect' | 'manual';
+}
+
+/** Matches Go entity.DailySummaryRangeResult (snake_case JSON via json tags) */
+export interface DailySummaryRangeResult {
+	items: DailySummary[];
+	total: number;
+	from: string;
+	to: string;
+	has_more: boolean;
 }
 
 export interface DataQuality {
@@ -93,9 +114,27 @@ export interface DataQuality {
 	BaselineMaturity: string;
 	ConfidenceScore: number;
 	ConfidenceLevel: string;
+	LowestSpO2: number;
+	SpO2MinAlert: boolean;
+	SleepStageConfidence: number;
+	SleepStageQualityPct: number;
 	ComputedAt: string;
 }
 
+export interface DataQualitySummary {
+	from: string;
+	to: string;
+	total_days: number;
+	days_with_data: number;
+	valid_days: number;
+	avg_confidence: number;
+	metric_coverage: Record<string, number>;
+	most_common_missing: string;
+	baseline_maturity_start: string;
+	baseline_maturity_end: string;
+	baseline_days_change: number;
+}
+
 export interface VRIMetricContribution {
 	metric: string;
 	z_score: number;
@@ -122,6 +161,8 @@ export interface VRIScore {
 	ContributingFactors: VRIMetricContribution[] | null;
 	MetricsIncluded: string[];
 	ComputedAt: string;
+	/** 0-1 position of VRIScore on a normal fit of the previous 90 days */
+	PopulationPercentile: number | null;
 }
 
 export interface CircadianMetricContribution {
@@ -214,6 +255,26 @@ export interface AnomalyDetection {
 	ComputedAt: string;
 }
 
+export interface AnomalyDriver {
+	feature: string;
+	shap_value: number;
+	direction: string;
+	contribution: number;
+	description: string;
+}
+
+export interface AnomalyWaterfall {
+	baseline: number;
+	output: number;
+	steps: AnomalyDriver[];
+}
+
+export interface AnomalyDrivers {
+	date: string;
+	drivers: AnomalyDriver[];
+	waterfall: AnomalyWaterfall;
+}
+
 export interface MetricComparison {
 	label: string;
 	today: number | null;
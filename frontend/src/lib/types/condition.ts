@@ -15,9 +15,15 @@ export interface ConditionLog {
 	StressVAS: number | null;
 	Note: string;
 	Tags: string[];
+	Source: ConditionSource;
+	/** OverallVAS was over 3 SD from the mean of the previous 30 logs */
+	IsOutlier: boolean;
 	CreatedAt: string;
+	DeletedAt: string | null;
 }
 
+export type ConditionSource = 'spontaneous' | 'reminder' | 'scheduled';
+
 /** Matches Go handler.createConditionRequest */
 export interface CreateConditionRequest {
 	wellbeing: number; // 0-100, required
@@ -28,6 +34,7 @@ export interface CreateConditionRequest {
 	note?: string;
 	tags?: string[];
 	logged_at?: string;
+	source?: ConditionSource;
 }
 
 /** Matches Go entity.ConditionListResult (lowercase JSON via json tags) */
@@ -36,6 +43,24 @@ export interface ConditionListResult {
 	total: number;
 }
 
+/** Matches Go entity.ConditionLogHistory (snake_case JSON via json tags) */
+export interface ConditionLogHistory {
+	id: number;
+	condition_log_id: number;
+	changed_at: string;
+	old_values: ConditionLog;
+	new_values: ConditionLog;
+}
+
+/** Matches Go entity.ConditionAnnotation (snake_case JSON via json tags) */
+export interface ConditionAnnotation {
+	id: number;
+	condition_log_id: number;
+	label: string;
+	note?: string;
+	created_at: string;
+}
+
 /** Matches Go entity.ConditionSummary (snake_case JSON via json tags) */
 export interface ConditionSummary {
 	total_count: number;
@@ -66,6 +91,45 @@ export interface ConditionSummary {
 	stress_vas_avg: number;
 	stress_vas_min: number;
 	stress_vas_max: number;
+	trend_direction: 'improving' | 'stable' | 'declining' | 'insufficient_data';
+	trend_slope: number;
+}
+
+/** Matches Go entity.HeatmapDay (snake_case JSON via json tags) */
+export interface HeatmapDay {
+	date: string;
+	value: number | null;
+	has_data: boolean;
+}
+
+/** Matches Go entity.ConditionWeekly (snake_case JSON via json tags) */
+export interface ConditionWeekly {
+	week_start: string;
+	week_end: string;
+	log_count: number;
+	overall_vas_mean: number;
+	overall_vas_min: number;
+	overall_vas_max: number;
+	mood_vas_mean: number | null;
+	mood_vas_min: number | null;
+	mood_vas_max: number | null;
+	energy_vas_mean: number | null;
+	energy_vas_min: number | null;
+	energy_vas_max: number | null;
+	sleep_quality_vas_mean: number | null;
+	sleep_quality_vas_min: number | null;
+	sleep_quality_vas_max: number | null;
+	stress_vas_mean: number | null;
+	stress_vas_min: number | null;
+	stress_vas_max: number | null;
+}
+
+/** Matches Go entity.HistogramBucket (snake_case JSON via json tags) */
+export interface HistogramBucket {
+	bucket_start: number;
+	bucket_end: number;
+	count: number;
+	frequency: number;
 }
 
 /** Matches Go entity.TagCount (lowercase JSON via json tags) */
@@ -34,6 +34,7 @@ FROM condition_logs cl
 JOIN daily_summaries ds ON ds.date = cl.logged_at::date
 LEFT JOIN daily_data_quality dq ON dq.date = ds.date
 WHERE dq.is_valid_day IS NOT FALSE
+  AND cl.deleted_at IS NULL
 """
 
 
@@ -58,6 +59,7 @@ JOIN daily_summaries ds ON ds.date = cl.logged_at::date
 LEFT JOIN daily_data_quality dq ON dq.date = ds.date
 LEFT JOIN vri_scores vs ON vs.date = ds.date
 WHERE dq.is_valid_day IS NOT FALSE
+  AND cl.deleted_at IS NULL
   AND ds.date BETWEEN $1 AND $2
 ORDER BY ds.date
 """
@@ -113,7 +113,7 @@ SELECT
     EXTRACT(DOW FROM d.date)           AS day_of_week,
     cl.overall                         AS condition_score
 FROM daily_avgs d
-INNER JOIN condition_logs cl ON cl.logged_at::date = d.date
+INNER JOIN condition_logs cl ON cl.logged_at::date = d.date AND cl.deleted_at IS NULL
 WHERE d.date BETWEEN $1 AND $2
 ORDER BY d.date
 """
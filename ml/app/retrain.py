@@ -33,6 +33,26 @@ INSERT INTO retrain_logs (
 RETURNING id
 """
 
+LATEST_ANOMALY_CONTAMINATION = """
+SELECT contamination FROM anomaly_model_metadata
+ORDER BY trained_at DESC LIMIT 1
+"""
+
+DEFAULT_ANOMALY_CONTAMINATION = 0.02
+
+
+async def _latest_anomaly_contamination(pool) -> float:
+    """Contamination of the most recent anomaly model.
+
+    Scheduled retrains reuse it so a sensitivity chosen through the API
+    survives them. Falls back to the default when none is recorded.
+    """
+    async with pool.acquire() as conn:
+        value = await conn.fetchval(LATEST_ANOMALY_CONTAMINATION)
+    if value is None or not 0.001 <= float(value) <= 0.1:
+        return DEFAULT_ANOMALY_CONTAMINATION
+    return float(value)
+
 
 async def run_retrain(app, *, trigger: str = "scheduled", mode: str = "daily") -> dict:
     """Run retraining for all eligible models.
@@ -65,7 +85,8 @@ async def run_retrain(app, *, trigger: str = "scheduled", mode: str = "daily") -
         check = await check_anomaly_trainability(pool)
         if check.trainable:
             detector = app.state.anomaly_detector
-            metadata = await train_anomaly(pool, detector)
+            contamination = await _latest_anomaly_contamination(pool)
+            metadata = await train_anomaly(pool, detector, contamination=contamination)
             results["anomaly"] = {
                 "status": "success",
                 "message": f"Trained on {metadata['training_days']} days",
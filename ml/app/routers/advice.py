@@ -92,7 +92,7 @@ ORDER BY predicted_at DESC LIMIT 1
 FETCH_CONDITION_LOG_QUERY = """
 SELECT overall_vas, tags, note AS notes
 FROM condition_logs
-WHERE logged_at::date = $1::date
+WHERE logged_at::date = $1::date AND deleted_at IS NULL
 ORDER BY logged_at DESC LIMIT 1
 """
 
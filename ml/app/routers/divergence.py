@@ -48,7 +48,7 @@ FETCH_CONDITION_FOR_DATE_QUERY = """
 SELECT id,
        overall_vas::float AS score
 FROM condition_logs
-WHERE logged_at::date = $1::date
+WHERE logged_at::date = $1::date AND deleted_at IS NULL
 ORDER BY logged_at DESC
 LIMIT 1
 """
@@ -26,7 +26,7 @@ ORDER BY date
 CONDITION_QUERY = """
 SELECT avg(overall_vas) AS avg_score
 FROM condition_logs
-WHERE logged_at::date BETWEEN $1 AND $2
+WHERE logged_at::date BETWEEN $1 AND $2 AND deleted_at IS NULL
 """
 
 
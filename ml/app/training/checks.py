@@ -189,6 +189,7 @@ async def check_divergence_trainability(pool) -> TrainabilityResult:
             JOIN daily_summaries ds ON cl.logged_at::date = ds.date
             JOIN daily_data_quality dq ON ds.date = dq.date
             WHERE dq.is_valid_day IS NOT FALSE
+              AND cl.deleted_at IS NULL
         """)
         if total_pairs < 14:
             return TrainabilityResult(
@@ -205,7 +206,7 @@ async def check_divergence_trainability(pool) -> TrainabilityResult:
         if last_trained is not None:
             new_count = await conn.fetchval("""
                 SELECT COUNT(*) FROM condition_logs
-                WHERE logged_at::date > $1::date
+                WHERE logged_at::date > $1::date AND deleted_at IS NULL
             """, last_trained)
         else:
             new_count = total_pairs
@@ -200,3 +200,28 @@ async def test_daily_mode_skips_optuna(
     call_kwargs = mock_train_hrv.call_args[1]
     assert call_kwargs["optuna_trials"] == 0
     assert call_kwargs["include_lstm"] is False
+
+
+@patch("app.retrain.check_anomaly_trainability")
+@patch("app.retrain.train_anomaly")
+@patch("app.retrain.check_hrv_trainability")
+@patch("app.retrain.check_divergence_trainability")
+async def test_anomaly_keeps_latest_contamination(
+    mock_div_check, mock_hrv_check, mock_train_anomaly, mock_anom_check
+):
+    """Scheduled retrains reuse the contamination of the latest model."""
+    from app.training.checks import TrainabilityResult
+
+    mock_anom_check.return_value = TrainabilityResult(trainable=True, reason="Ready")
+    mock_train_anomaly.return_value = {
+        "model_version": "anomaly_v_20260301",
+        "training_days": 60,
+    }
+    mock_hrv_check.return_value = TrainabilityResult(trainable=False, reason="No new data")
+    mock_div_check.return_value = TrainabilityResult(trainable=False, reason="No new data")
+
+    app = _make_app()
+    app.state.db_pool.conn.fetchval = AsyncMock(return_value=0.1)
+    await run_retrain(app, trigger="scheduled", mode="daily")
+
+    assert mock_train_anomaly.call_args.kwargs["contamination"] == 0.1
@@ -74,7 +74,7 @@ class BatchWriter:
                 calories_total, calories_active, calories_bmr,
                 vo2_max,
                 hr_zone_out_min, hr_zone_fat_min, hr_zone_cardio_min, hr_zone_peak_min,
-                synced_at
+                synced_at, providers
             ) VALUES (
                 $1, $2, $3, $4, $5,
                 $6, $7,
@@ -89,10 +89,13 @@ class BatchWriter:
                 $28, $29, $30,
                 $31,
                 $32, $33, $34, $35,
-                NOW()
+                NOW(), ARRAY[$2::text]
             )
             ON CONFLICT (date) DO UPDATE SET
                 provider = EXCLUDED.provider,
+                providers = array_cat(daily_summaries.providers,
+                    ARRAY(SELECT p FROM unnest(EXCLUDED.providers) AS p
+                          WHERE p <> ALL(daily_summaries.providers))),
                 resting_hr = COALESCE(EXCLUDED.resting_hr, daily_summaries.resting_hr),
                 avg_hr = COALESCE(EXCLUDED.avg_hr, daily_summaries.avg_hr),
                 max_hr = COALESCE(EXCLUDED.max_hr, daily_summaries.max_hr),
@@ -69,18 +69,18 @@ class ProcessResponse(BaseModel):
 async def process(req: ProcessRequest, background_tasks: BackgroundTasks):
     rds = app.state.redis
     # Initialize progress
-    await rds.set(
-        f"hk_import:{req.job_id}",
-        json.dumps({
-            "status": "queued",
-            "stage": "queued",
-            "records_processed": 0,
-            "records_total": 0,
-            "days_written": 0,
-            "current_date": "",
-            "errors": [],
-        }),
-    )
+    progress_key = f"hk_import:{req.job_id}"
+    payload = json.dumps({
+        "status": "queued",
+        "stage": "queued",
+        "records_processed": 0,
+        "records_total": 0,
+        "days_written": 0,
+        "current_date": "",
+        "errors": [],
+    })
+    await rds.set(progress_key, payload)
+    await rds.publish(progress_key, payload)
     background_tasks.add_task(run_import, req.zip_path, req.job_id)
     return ProcessResponse(job_id=req.job_id, status="queued")
 
@@ -104,7 +104,10 @@ async def run_import(zip_path: str, job_id: str):
         data = await rds.get(progress_key)
         current = json.loads(data) if data else {}
         current.update(kwargs)
-        await rds.set(progress_key, json.dumps(current))
+        payload = json.dumps(current)
+        await rds.set(progress_key, payload)
+        # The API streams progress to SSE clients from this channel.
+        await rds.publish(progress_key, payload)
 
     try:
         await update_progress(status="processing", stage="parsing")